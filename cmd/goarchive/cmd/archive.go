@@ -16,7 +16,11 @@ var (
 	archiveJob                   string
 	archiveForce                 bool
 	archiveSkipValidatePreflight bool
+	archiveForceMaxDeleteRows    bool
 	archiveForceTriggers         bool
+	archiveStopMode              string
+	archiveStateDumpSignal       string
+	archiveSkipMaintenance       bool
 )
 
 var archiveCmd = &cobra.Command{
@@ -47,11 +51,26 @@ func init() {
 		"Skip preflight checks before this run (DANGEROUS - see docs)")
 	archiveCmd.Flags().BoolVar(&archiveForceTriggers, "force-triggers", false,
 		"Proceed despite DELETE triggers detected by preflight")
+	archiveCmd.Flags().BoolVar(&archiveForceMaxDeleteRows, "force-max-delete-rows", false,
+		"Delete past safety.max_delete_rows for this run (the cap guards against a where clause that matches far more rows than intended)")
+
+	archiveCmd.Flags().StringVar(&archiveStopMode, "stop-mode", "finish-batch",
+		"What the first SIGINT/SIGTERM does to the in-flight batch: finish-batch (complete it and commit its checkpoint, then stop) or immediate (cancel it now; the next run replays it)")
+
+	archiveCmd.Flags().StringVar(&archiveStateDumpSignal, "state-dump-signal", "",
+		"Log the job's current state (phase, table, batch, counters, last checkpoint PK) without stopping it whenever this signal arrives: SIGQUIT, SIGUSR1 or SIGUSR2 (not on Windows). Empty disables")
+	archiveCmd.Flags().BoolVar(&archiveSkipMaintenance, "skip-maintenance", false,
+		"Skip processing.post_copy_maintenance (ANALYZE/OPTIMIZE TABLE on the destination) for this run")
 
 	rootCmd.AddCommand(archiveCmd)
 }
 
 func runArchive(cmd *cobra.Command, args []string) error {
+	stopMode, err := archiver.ParseStopMode(archiveStopMode)
+	if err != nil {
+		return err
+	}
+
 	configFile := GetConfigFile()
 
 	// Load configuration
@@ -97,7 +116,7 @@ func runArchive(cmd *cobra.Command, args []string) error {
 	// whatever state is left). A third Ctrl-C hard-terminates.
 	ctx, stopCh := database.SetupGracefulShutdown(
 		func(_ os.Signal) {
-			log.Warn("Received shutdown signal - finishing current batch, then stopping (Ctrl-C again to abort now)...")
+			log.Warn(shutdownSignalMessage(stopMode))
 		},
 		func(_ os.Signal) {
 			log.Error("Received second shutdown signal - aborting in-flight work")
@@ -119,7 +138,7 @@ func runArchive(cmd *cobra.Command, args []string) error {
 	if err := dbManager.Ping(ctx); err != nil {
 		return fmt.Errorf("database connection failed: %w", err)
 	}
-	if err := runRuntimePreflight(ctx, cfg, jobCfg, dbManager, log, "archive", jobCfg.GetJobVerification(cfg.Verification),
+	if err := runRuntimePreflight(ctx, cfg, jobCfg, dbManager, log, "archive", jobCfg.WeakestVerification(jobCfg.GetJobVerification(cfg.Verification)),
 		archiver.PreflightProfileFull, archiveForceTriggers, true, archiveSkipValidatePreflight); err != nil {
 		return err
 	}
@@ -136,11 +155,32 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("orchestrator initialization failed: %w", err)
 	}
 	orch.SetForce(archiveForce)
+	orch.SetForceMaxDeleteRows(archiveForceMaxDeleteRows)
 	orch.SetStopChannel(stopCh)
+	orch.SetStopMode(stopMode)
+	if jobCfg.PhaseHook != "" {
+		orch.SetHooks(archiver.CommandHooks{Path: jobCfg.PhaseHook})
+	}
+	orch.SetSkipMaintenance(archiveSkipMaintenance)
+	if archiveStateDumpSignal != "" {
+		stopDump, err := database.NotifyStateDump(archiveStateDumpSignal, orch.LogSnapshot)
+		if err != nil {
+			return err
+		}
+		defer stopDump()
+	}
+	stopHealth, err := startHealthServer(cfg, dbManager, func() interface{} { return orch.Snapshot() }, log)
+	if err != nil {
+		return err
+	}
+	defer stopHealth()
 
 	// Execute archive operation
 	result, err := orch.Execute(ctx, nil)
 	if err != nil {
+		if errors.Is(err, archiver.ErrRuntimeBudgetExceeded) {
+			return fmt.Errorf("archive operation stopped (processing.max_runtime reached; run again to resume): %w", err)
+		}
 		if errors.Is(err, context.Canceled) {
 			log.Warn("Archive operation cancelled by user")
 			return fmt.Errorf("archive operation cancelled: %w", err)
@@ -154,6 +194,7 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		"tables_copied", result.TablesCopied,
 		"tables_deleted", result.TablesDeleted,
 		"records_copied", result.RecordsCopied,
+		"records_skipped", result.RecordsSkipped,
 		"records_deleted", result.RecordsDeleted,
 		"success", result.Success,
 		"errors", len(result.Errors),
@@ -166,6 +207,9 @@ func runArchive(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Tables Copied: %d\n", result.TablesCopied)
 	fmt.Printf("Tables Deleted: %d\n", result.TablesDeleted)
 	fmt.Printf("Records Copied: %d\n", result.RecordsCopied)
+	if result.RecordsSkipped > 0 {
+		fmt.Printf("Records Skipped: %d (already present in destination)\n", result.RecordsSkipped)
+	}
 	fmt.Printf("Records Deleted: %d\n", result.RecordsDeleted)
 	fmt.Printf("Success: %v\n", result.Success)
 
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dbsmedya/goarchive/internal/archiver"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveJobsNames                 []string
+	archiveJobsParallel              int
+	archiveJobsContinueOnError       bool
+	archiveJobsSkipValidatePreflight bool
+	archiveJobsForceTriggers         bool
+	archiveJobsStopMode              string
+)
+
+var archiveJobsCmd = &cobra.Command{
+	Use:   "archive-jobs",
+	Short: "Archive several jobs in one run",
+	Long: `Archive-jobs runs the archive workflow of several jobs from the
+configuration file in one process, one after another by default. Each job
+takes its own advisory lock and logs with its own logging settings, and a
+summary line is printed per job at the end.
+
+By default the first failed job stops the jobs that have not started yet;
+--continue-on-error runs every job regardless. Preflight checks run for
+every selected job before the first one starts.
+
+Example:
+  goarchive archive-jobs --config archiver.yaml
+  goarchive archive-jobs --jobs archive_old_orders,archive_sessions --parallel 2`,
+	RunE: runArchiveJobs,
+}
+
+func init() {
+	archiveJobsCmd.Flags().StringSliceVar(&archiveJobsNames, "jobs", nil,
+		"Comma-separated job names to archive, in order (default: every job, sorted by name)")
+	archiveJobsCmd.Flags().IntVar(&archiveJobsParallel, "parallel", 1,
+		"How many jobs to run at once")
+	archiveJobsCmd.Flags().BoolVar(&archiveJobsContinueOnError, "continue-on-error", false,
+		"Run the remaining jobs after a job fails instead of skipping them")
+	archiveJobsCmd.Flags().BoolVar(&archiveJobsSkipValidatePreflight, "skip-validate-preflight", false,
+		"Skip preflight checks before this run (DANGEROUS - see docs)")
+	archiveJobsCmd.Flags().BoolVar(&archiveJobsForceTriggers, "force-triggers", false,
+		"Proceed despite DELETE triggers detected by preflight")
+	archiveJobsCmd.Flags().StringVar(&archiveJobsStopMode, "stop-mode", "finish-batch",
+		"What the first SIGINT/SIGTERM does to each running job's in-flight batch: finish-batch or immediate (see archive --stop-mode)")
+
+	rootCmd.AddCommand(archiveJobsCmd)
+}
+
+func runArchiveJobs(cmd *cobra.Command, args []string) error {
+	stopMode, err := archiver.ParseStopMode(archiveJobsStopMode)
+	if err != nil {
+		return err
+	}
+	if archiveJobsParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1, got %d", archiveJobsParallel)
+	}
+
+	configFile := GetConfigFile()
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	overrides := GetCLIOverrides()
+	cfg.ApplyOverrides(overrides.LogLevel, overrides.LogFormat, overrides.SkipVerify)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	jobs, err := cfg.SelectJobs(archiveJobsNames)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("no jobs defined in %s", configFile)
+	}
+
+	logCfg := effectiveJobLogging(cfg, nil, overrides)
+	log, err := logger.New(&logCfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer syncLogger(log)
+
+	jobLogs := make(map[string]*logger.Logger, len(jobs))
+	for _, named := range jobs {
+		jobLog, err := newJobLogger(cfg, named.Job, named.Name)
+		if err != nil {
+			return fmt.Errorf("failed to initialize logger for job %s: %w", named.Name, err)
+		}
+		defer syncLogger(jobLog)
+		jobLogs[named.Name] = jobLog
+	}
+
+	dbManager := database.NewManager(cfg)
+
+	ctx, stopCh := database.SetupGracefulShutdown(
+		func(_ os.Signal) {
+			log.Warn(shutdownSignalMessage(stopMode))
+		},
+		func(_ os.Signal) {
+			log.Error("Received second shutdown signal - aborting in-flight work")
+			syncLogger(log)
+		},
+	)
+
+	if err := dbManager.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Errorf("Failed to close database connections: %v", err)
+		}
+	}()
+
+	if err := dbManager.Ping(ctx); err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+
+	runnerJobs := make([]archiver.RunnerJob, 0, len(jobs))
+	for _, named := range jobs {
+		jobCfg, jobLog := named.Job, jobLogs[named.Name]
+		if err := runRuntimePreflight(ctx, cfg, jobCfg, dbManager, jobLog, "archive", jobCfg.WeakestVerification(jobCfg.GetJobVerification(cfg.Verification)),
+			archiver.PreflightProfileFull, archiveJobsForceTriggers, true, archiveJobsSkipValidatePreflight); err != nil {
+			return fmt.Errorf("job %s: %w", named.Name, err)
+		}
+		runnerJobs = append(runnerJobs, archiver.ArchiveRunnerJob(cfg, named, dbManager, func(orch *archiver.ArchiveOrchestrator) {
+			orch.SetLogger(jobLog)
+			orch.SetStopChannel(stopCh)
+			orch.SetStopMode(stopMode)
+			if jobCfg.PhaseHook != "" {
+				orch.SetHooks(archiver.CommandHooks{Path: jobCfg.PhaseHook})
+			}
+		}))
+	}
+
+	runner := archiver.NewRunner(log)
+	runner.SetParallelism(archiveJobsParallel)
+	runner.SetContinueOnError(archiveJobsContinueOnError)
+	outcomes, err := runner.Run(ctx, runnerJobs)
+	if err != nil {
+		return err
+	}
+	return writeJobOutcomes(cmd.OutOrStdout(), outcomes)
+}
+
+// writeJobOutcomes prints one summary line per job and returns an error
+// naming the number of jobs that failed or were skipped.
+func writeJobOutcomes(w io.Writer, outcomes []archiver.JobOutcome) error {
+	_, _ = fmt.Fprintf(w, "\n=== Archive Jobs Complete ===\n")
+	failed := 0
+	for _, outcome := range outcomes {
+		switch {
+		case outcome.Skipped:
+			failed++
+			_, _ = fmt.Fprintf(w, "  %s: skipped\n", outcome.Job)
+		case outcome.Err != nil:
+			failed++
+			_, _ = fmt.Fprintf(w, "  %s: FAILED: %v\n", outcome.Job, outcome.Err)
+		case outcome.Failed():
+			failed++
+			_, _ = fmt.Fprintf(w, "  %s: completed with %d errors\n", outcome.Job, len(outcome.Result.Errors))
+		default:
+			r := outcome.Result
+			_, _ = fmt.Fprintf(w, "  %s: %d records copied, %d deleted in %s\n", outcome.Job, r.RecordsCopied, r.RecordsDeleted, r.Duration)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d jobs did not complete", failed, len(outcomes))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/archiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveJobsCommandStructure(t *testing.T) {
+	assert.NotNil(t, archiveJobsCmd)
+	assert.Equal(t, "archive-jobs", archiveJobsCmd.Use)
+	assert.NotNil(t, archiveJobsCmd.RunE)
+
+	flags := archiveJobsCmd.Flags()
+	for _, name := range []string{"jobs", "parallel", "continue-on-error", "skip-validate-preflight", "force-triggers", "stop-mode"} {
+		assert.NotNil(t, flags.Lookup(name), "flag %s", name)
+	}
+	assert.Equal(t, "1", flags.Lookup("parallel").DefValue)
+}
+
+func TestWriteJobOutcomes(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeJobOutcomes(&buf, []archiver.JobOutcome{
+		{Job: "orders", Result: &archiver.ArchiveResult{RecordsCopied: 5, RecordsDeleted: 5, Duration: time.Second, Success: true}},
+		{Job: "sessions", Err: errors.New("lock held")},
+		{Job: "logs", Skipped: true},
+	})
+	require.EqualError(t, err, "2 of 3 jobs did not complete")
+	assert.Contains(t, buf.String(), "  orders: 5 records copied, 5 deleted in 1s\n")
+	assert.Contains(t, buf.String(), "  sessions: FAILED: lock held\n")
+	assert.Contains(t, buf.String(), "  logs: skipped\n")
+
+	buf.Reset()
+	require.NoError(t, writeJobOutcomes(&buf, []archiver.JobOutcome{
+		{Job: "orders", Result: &archiver.ArchiveResult{Success: true}},
+	}))
+}
@@ -16,6 +16,8 @@ var (
 	copyOnlyJob                   string
 	copyOnlyForce                 bool
 	copyOnlySkipValidatePreflight bool
+	copyOnlyStopMode              string
+	copyOnlySkipMaintenance       bool
 )
 
 var copyOnlyCmd = &cobra.Command{
@@ -39,10 +41,20 @@ func init() {
 		"Proceed past advisory lock contention only when the lock holder's heartbeat is stale (indicating a crashed prior instance). Also bypasses destination duplicate preflight checks after confirmation.")
 	copyOnlyCmd.Flags().BoolVar(&copyOnlySkipValidatePreflight, "skip-validate-preflight", false,
 		"Skip preflight checks before this run (DANGEROUS - see docs)")
+	copyOnlyCmd.Flags().StringVar(&copyOnlyStopMode, "stop-mode", "finish-batch",
+		"What the first SIGINT/SIGTERM does to the in-flight batch: finish-batch (complete it and commit its checkpoint, then stop) or immediate (cancel it now; the next run replays it)")
+	copyOnlyCmd.Flags().BoolVar(&copyOnlySkipMaintenance, "skip-maintenance", false,
+		"Skip processing.post_copy_maintenance (ANALYZE/OPTIMIZE TABLE on the destination) for this run")
+
 	rootCmd.AddCommand(copyOnlyCmd)
 }
 
 func runCopyOnly(cmd *cobra.Command, args []string) error {
+	stopMode, err := archiver.ParseStopMode(copyOnlyStopMode)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.Load(GetConfigFile())
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -75,7 +87,7 @@ func runCopyOnly(cmd *cobra.Command, args []string) error {
 	// hard-terminates.
 	ctx, stopCh := database.SetupGracefulShutdown(
 		func(_ os.Signal) {
-			log.Warn("Received shutdown signal - finishing current batch, then stopping (Ctrl-C again to abort now)...")
+			log.Warn(shutdownSignalMessage(stopMode))
 		},
 		func(_ os.Signal) {
 			log.Error("Received second shutdown signal - aborting in-flight work")
@@ -95,7 +107,7 @@ func runCopyOnly(cmd *cobra.Command, args []string) error {
 	if err := dbManager.Ping(ctx); err != nil {
 		return fmt.Errorf("database connection failed: %w", err)
 	}
-	if err := runRuntimePreflight(ctx, cfg, jobCfg, dbManager, log, "copy-only", jobCfg.GetJobVerification(cfg.Verification),
+	if err := runRuntimePreflight(ctx, cfg, jobCfg, dbManager, log, "copy-only", jobCfg.WeakestVerification(jobCfg.GetJobVerification(cfg.Verification)),
 		archiver.PreflightProfileNonDestructive, false, false, copyOnlySkipValidatePreflight); err != nil {
 		return err
 	}
@@ -109,9 +121,22 @@ func runCopyOnly(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("copy-only orchestrator initialization failed: %w", err)
 	}
 	orch.SetStopChannel(stopCh)
+	orch.SetStopMode(stopMode)
+	if jobCfg.PhaseHook != "" {
+		orch.SetHooks(archiver.CommandHooks{Path: jobCfg.PhaseHook})
+	}
+	orch.SetSkipMaintenance(copyOnlySkipMaintenance)
+	stopHealth, err := startHealthServer(cfg, dbManager, nil, log)
+	if err != nil {
+		return err
+	}
+	defer stopHealth()
 
 	result, err := orch.Execute(ctx, copyOnlyForce)
 	if err != nil {
+		if errors.Is(err, archiver.ErrRuntimeBudgetExceeded) {
+			return fmt.Errorf("copy-only operation stopped (processing.max_runtime reached; run again to resume): %w", err)
+		}
 		if errors.Is(err, context.Canceled) {
 			return fmt.Errorf("copy-only operation cancelled: %w", err)
 		}
@@ -123,6 +148,7 @@ func runCopyOnly(cmd *cobra.Command, args []string) error {
 		"duration", result.Duration,
 		"tables_copied", result.TablesCopied,
 		"records_copied", result.RecordsCopied,
+		"records_skipped", result.RecordsSkipped,
 		"success", result.Success,
 		"errors", len(result.Errors),
 	)
@@ -132,6 +158,9 @@ func runCopyOnly(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Duration: %s\n", result.Duration)
 	fmt.Printf("Tables Copied: %d\n", result.TablesCopied)
 	fmt.Printf("Records Copied: %d\n", result.RecordsCopied)
+	if result.RecordsSkipped > 0 {
+		fmt.Printf("Records Skipped: %d (already present in destination)\n", result.RecordsSkipped)
+	}
 	fmt.Printf("Success: %v\n", result.Success)
 	if len(result.Errors) > 0 {
 		fmt.Printf("\nErrors:\n")
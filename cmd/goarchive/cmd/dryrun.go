@@ -2,12 +2,16 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/dbsmedya/goarchive/internal/archiver"
 	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/database"
 	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +28,8 @@ The dry-run:
   - Shows the job WHERE clause and estimated row counts (root and children,
     filtered through the relation chain)
   - Shows the number of batches that would be processed
+  - Discovers the first batch and shows its rows next to their COUNT(*)
+    estimate, i.e. what one batch holds in memory
   - Validates batch_size against destination payload limits (rolled back)
 
 Recommended operator workflow: validate -> dry-run -> archive.
@@ -107,7 +113,7 @@ func runDryrun(cmd *cobra.Command, args []string) error {
 	// Dry-run is the operator's go/no-go step: run the non-destructive
 	// preflight profile so schema/charset/grants problems surface here,
 	// not at archive time. Workflow: validate -> dry-run -> archive.
-	verification := jobCfg.GetJobVerification(cfg.Verification)
+	verification := jobCfg.WeakestVerification(jobCfg.GetJobVerification(cfg.Verification))
 	if err := runRuntimePreflight(ctx, cfg, jobCfg, dbManager, log, "dry-run",
 		verification, archiver.PreflightProfileNonDestructive, false, true, false); err != nil {
 		return err
@@ -125,8 +131,12 @@ func runDryrun(cmd *cobra.Command, args []string) error {
 	// Display execution plan
 	estimator.DisplayExecutionPlan(result)
 
-	// Validate that the chosen batch_size fits destination limits (rolled back).
 	jobProcessing := cfg.GetJobProcessing(dryrunJob)
+	if err := displayFirstBatch(ctx, os.Stdout, dbManager.ReadSource(), g, jobCfg, jobProcessing, log); err != nil {
+		return err
+	}
+
+	// Validate that the chosen batch_size fits destination limits (rolled back).
 	validator := archiver.NewPayloadValidator(
 		dbManager.Source, dbManager.Destination, g, jobCfg,
 		cfg.Safety, jobProcessing.BatchSize, log,
@@ -139,3 +149,35 @@ func runDryrun(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// displayFirstBatch discovers the first batch of root rows, from the start of
+// the root table, with the discovery COUNT(*) estimate taken first, and
+// writes the estimated and discovered row counts to w.
+func displayFirstBatch(ctx context.Context, w io.Writer, source *sql.DB, g *graph.Graph, jobCfg *config.JobConfig, processing config.ProcessingConfig, log *logger.Logger) error {
+	fetcher := archiver.NewRootIDFetcher(source, jobCfg.RootTable, g.GetPK(jobCfg.RootTable),
+		jobCfg.Where, processing.BatchSize, nil)
+	fetcher.SetOrder(processing.RootOrder)
+	rootPKs, err := fetcher.FetchNextBatch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch first batch: %w", err)
+	}
+	if len(rootPKs) == 0 {
+		_, _ = fmt.Fprintf(w, "\nFirst batch: no rows in %s match the job's WHERE clause\n", jobCfg.RootTable)
+		return nil
+	}
+
+	discovery, err := archiver.NewRecordDiscovery(g, source, processing.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to create record discovery: %w", err)
+	}
+	discovery.SetLogger(log.WithPhase("discovery"))
+	discovery.SetMaxInClauseSize(processing.MaxInClauseSize)
+	discovery.SetEstimateCounts(true)
+	recordSet, err := discovery.Discover(ctx, rootPKs)
+	if err != nil {
+		return fmt.Errorf("first batch discovery failed: %w", err)
+	}
+	_, _ = fmt.Fprintf(w, "\nFirst batch: %d root rows, %d rows in %d tables (COUNT(*) estimate: %d)\n",
+		len(rootPKs), recordSet.Stats.RecordsFound, recordSet.Stats.TablesScanned, recordSet.Stats.EstimatedRecords)
+	return nil
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDryrunCommandStructure(t *testing.T) {
@@ -138,3 +145,33 @@ func TestDryrunCmd_Execute_MissingConfig(t *testing.T) {
 	err := rootCmd.Execute()
 	assert.Error(t, err)
 }
+
+func TestDisplayFirstBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	jobCfg := &config.JobConfig{
+		RootTable:  "customers",
+		PrimaryKey: "id",
+		Where:      "created_at < '2020-01-01'",
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "customer_id", DependencyType: "1-N"},
+		},
+	}
+	g, err := graph.NewBuilder(jobCfg).Build()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `id` FROM `customers` WHERE").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)).AddRow(int64(11)).AddRow(int64(12)))
+
+	var buf bytes.Buffer
+	processing := config.ProcessingConfig{BatchSize: 100}
+	require.NoError(t, displayFirstBatch(context.Background(), &buf, db, g, jobCfg, processing, logger.NewDefault()))
+	assert.Equal(t, "\nFirst batch: 2 root rows, 5 rows in 2 tables (COUNT(*) estimate: 5)\n", buf.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
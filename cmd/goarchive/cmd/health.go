@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
+	"github.com/dbsmedya/goarchive/internal/health"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// startHealthServer starts the health server when health.port is set and
+// returns the func that stops it; both are no-ops when it is disabled.
+// status feeds /status and may be nil.
+func startHealthServer(cfg *config.Config, dbManager *database.Manager, status func() interface{}, log *logger.Logger) (func(), error) {
+	if cfg.Health.Port == 0 {
+		return func() {}, nil
+	}
+	return health.Start(cfg.Health.ListenAddr(), health.NewHandler(dbManager, status), log)
+}
@@ -62,6 +62,9 @@ func runListJobs(cmd *cobra.Command, args []string) error {
 		} else {
 			cmd.Printf("   WHERE:         (none)\n")
 		}
+		if job.IncrementalColumn != "" {
+			cmd.Printf("   Incremental:   %s\n", job.IncrementalColumn)
+		}
 
 		// Relations count
 		cmd.Printf("   Relations:     %d table(s)\n", len(job.Relations))
@@ -77,8 +77,8 @@ func TestNewJobLoggerTagsJobAndWritesFile(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read job log file: %v", err)
 	}
-	if !strings.Contains(string(content), `"job": "archive_shipment_error_logs"`) &&
-		!strings.Contains(string(content), `"job":"archive_shipment_error_logs"`) {
+	if !strings.Contains(string(content), `"job_name": "archive_shipment_error_logs"`) &&
+		!strings.Contains(string(content), `"job_name":"archive_shipment_error_logs"`) {
 		t.Errorf("expected job tag on log entries, got: %s", content)
 	}
 	if !strings.Contains(string(content), "job tag check") {
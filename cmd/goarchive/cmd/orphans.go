@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/dbsmedya/goarchive/internal/archiver"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/lock"
+	"github.com/spf13/cobra"
+)
+
+var (
+	orphansJob                string
+	orphansDelete             bool
+	orphansYes                bool
+	orphansForceMaxDeleteRows bool
+)
+
+var orphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "Find (and optionally delete) orphaned child rows in the source",
+	Long: `Orphans scans the source for child rows whose foreign key points to a
+parent row that no longer exists, along every relation of the job graph, and
+reports the count per table. Rows with a NULL foreign key are not orphans.
+
+With --delete, the orphaned rows are deleted child-first using the job's
+delete settings (batch_delete_size, delete_sleep_seconds,
+max_rows_per_second, transactional_delete, delete_audit_log). --delete must
+be confirmed with --yes, and the same safety.max_delete_rows cap and
+safety.require_confirmation token as archive and purge apply. Deletion holds
+the job's advisory lock, so it cannot overlap an archive or purge of the same
+job. Rows that referenced a deleted orphan become orphans themselves and are
+found by the next run.
+
+WARNING: --delete permanently deletes data without archiving it.
+
+Example:
+  goarchive orphans --config archiver.yaml --job archive_old_orders
+  goarchive orphans --config archiver.yaml --job archive_old_orders --delete --yes`,
+	RunE: runOrphans,
+}
+
+func init() {
+	orphansCmd.Flags().StringVarP(&orphansJob, "job", "j", "",
+		"Job name from configuration file (required)")
+	_ = orphansCmd.MarkFlagRequired("job") // Config-time error, cannot fail
+
+	orphansCmd.Flags().BoolVar(&orphansDelete, "delete", false,
+		"Delete the orphaned rows (default: report only); requires --yes")
+	orphansCmd.Flags().BoolVar(&orphansYes, "yes", false,
+		"Confirm --delete: orphaned rows are deleted without being archived")
+	orphansCmd.Flags().BoolVar(&orphansForceMaxDeleteRows, "force-max-delete-rows", false,
+		"Delete past safety.max_delete_rows for this run")
+
+	rootCmd.AddCommand(orphansCmd)
+}
+
+func runOrphans(cmd *cobra.Command, args []string) error {
+	if orphansDelete && !orphansYes {
+		return fmt.Errorf("--delete permanently deletes orphaned rows without archiving them; run without --delete to review them, then pass --delete --yes")
+	}
+
+	configFile := GetConfigFile()
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	overrides := GetCLIOverrides()
+	cfg.ApplyOverrides(overrides.LogLevel, overrides.LogFormat, overrides.SkipVerify)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	jobCfgValue, exists := cfg.Jobs[orphansJob]
+	if !exists {
+		return fmt.Errorf("job '%s' not found in configuration", orphansJob)
+	}
+	jobCfg := &jobCfgValue
+
+	log, err := newJobLogger(cfg, jobCfg, orphansJob)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer syncLogger(log)
+
+	g, err := graph.NewBuilder(jobCfg).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	if g.HasCycle() {
+		return fmt.Errorf("dependency cycle detected in graph")
+	}
+
+	dbManager := database.NewManager(cfg)
+	// Nothing is checkpointed, so the first signal simply cancels.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := dbManager.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Errorf("Failed to close database connections: %v", err)
+		}
+	}()
+	if err := dbManager.Ping(ctx); err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+
+	processing := cfg.GetJobProcessing(orphansJob)
+	scanner, err := archiver.NewOrphanScanner(dbManager.Source, g, log.WithPhase("orphans"))
+	if err != nil {
+		return fmt.Errorf("failed to create orphan scanner: %w", err)
+	}
+	scanner.SetBatchSize(processing.BatchSize)
+	scanner.SetDeleteGuards(cfg.Safety, orphansJob, jobCfg, orphansForceMaxDeleteRows)
+
+	var result *archiver.OrphanScanResult
+	if !orphansDelete {
+		result, err = scanner.Scan(ctx)
+		if err != nil {
+			return fmt.Errorf("orphan scan failed: %w", err)
+		}
+	} else {
+		jobLock := lock.NewJobLock(dbManager.Destination, orphansJob)
+		acquired, err := jobLock.TryAcquire(ctx)
+		if err != nil {
+			return fmt.Errorf("job-name lock errored: %w", err)
+		}
+		if !acquired {
+			return fmt.Errorf("job %q is running (lock held); refusing to delete orphans concurrently", orphansJob)
+		}
+		defer func() { _, _ = jobLock.ReleaseLock(context.Background()) }()
+
+		deletePhase, err := archiver.NewDeletePhase(dbManager.Source, g, processing.BatchDeleteSize, log.WithPhase("delete"))
+		if err != nil {
+			return fmt.Errorf("failed to create delete phase: %w", err)
+		}
+		deletePhase.SetSleepSeconds(processing.DeleteSleepSeconds)
+		deletePhase.SetMaxInClauseSize(processing.MaxInClauseSize)
+		deletePhase.SetSortPKs(processing.SortDeletePKs)
+		deletePhase.SetRateLimiter(archiver.NewRowRateLimiter(processing.MaxRowsPerSecond))
+		deletePhase.SetTransactional(cfg.Safety.TransactionalDelete)
+		if cfg.Safety.DeleteAuditLog != "" {
+			audit, err := archiver.OpenDeleteAuditLog(cfg.Safety.DeleteAuditLog, orphansJob)
+			if err != nil {
+				return fmt.Errorf("failed to open delete audit log: %w", err)
+			}
+			defer func() {
+				if err := audit.Close(); err != nil {
+					log.Errorf("Failed to close delete audit log: %v", err)
+				}
+			}()
+			deletePhase.SetAuditLog(audit)
+		}
+		if cfg.Safety.DeleteBackup != "" {
+			backup, err := archiver.OpenDeleteBackup(cfg.Safety.DeleteBackup, orphansJob, cfg.Safety.DeleteBackupFormat)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := backup.Close(); err != nil {
+					log.Errorf("Failed to close delete backup: %v", err)
+				}
+			}()
+			deletePhase.SetBackup(backup)
+		}
+
+		result, err = scanner.Clean(ctx, deletePhase)
+		if err != nil {
+			return fmt.Errorf("orphan cleanup failed: %w", err)
+		}
+	}
+
+	fmt.Printf("\n=== Orphaned Rows ===\n")
+	fmt.Printf("Job: %s\n", orphansJob)
+	tables := make([]string, 0, len(result.CountPerTable))
+	for table := range result.CountPerTable {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("  %s: %d\n", table, result.CountPerTable[table])
+	}
+	fmt.Printf("Total orphaned rows: %d\n", result.RowsOrphaned)
+	if result.Deleted != nil {
+		fmt.Printf("Rows deleted: %d\n", result.Deleted.RowsDeleted)
+	} else if orphansDelete {
+		fmt.Println("Nothing to delete.")
+	} else if result.RowsOrphaned > 0 {
+		fmt.Println("\nℹ️  Report only; run again with --delete to remove them")
+	}
+	return nil
+}
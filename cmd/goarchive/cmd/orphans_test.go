@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrphansCommandStructure(t *testing.T) {
+	assert.NotNil(t, orphansCmd)
+	assert.Equal(t, "orphans", orphansCmd.Use)
+	assert.NotNil(t, orphansCmd.RunE)
+
+	flags := orphansCmd.Flags()
+	jobFlag := flags.Lookup("job")
+	assert.NotNil(t, jobFlag)
+	assert.Equal(t, "j", jobFlag.Shorthand)
+	deleteFlag := flags.Lookup("delete")
+	assert.NotNil(t, deleteFlag)
+	assert.Equal(t, "false", deleteFlag.DefValue)
+}
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
+	"github.com/dbsmedya/goarchive/internal/archiver"
 	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/dbsmedya/goarchive/internal/mermaidascii"
 	"github.com/spf13/cobra"
@@ -15,7 +18,11 @@ import (
 // outputWriter is used for printing output, can be overridden in tests
 var outputWriter io.Writer = os.Stdout
 
-var planJob string
+var (
+	planJob      string
+	planFormat   string
+	planEstimate bool
+)
 
 var planCmd = &cobra.Command{
 	Use:   "plan",
@@ -25,12 +32,23 @@ for tables based on dependency resolution.
 
 The plan shows:
   - Visual relation tree (using mermaid-ascii)
+  - Table, relationship and leaf counts, dependency depth and the longest
+    root-to-leaf chain
+  - Tables grouped by level (distance from the root table)
   - Copy order (parent tables first)
   - Delete order (child tables first)
   - Detected table relationships
+  - Per-table statement plan: copy/delete position, estimated rows
+    (with --estimate) and the SQL templates copy and delete will run
+
+Use --format json to print only the statement plan as JSON, or
+--format mermaid to print the dependency graph as a Mermaid block for
+Markdown.
 
 Example:
-  goarchive plan --config archiver.yaml --job archive_old_orders`,
+  goarchive plan --config archiver.yaml --job archive_old_orders
+  goarchive plan --job archive_old_orders --estimate --format json
+  goarchive plan --job archive_old_orders --format mermaid > graph.mmd`,
 	RunE: runPlan,
 }
 
@@ -38,11 +56,19 @@ func init() {
 	planCmd.Flags().StringVarP(&planJob, "job", "j", "",
 		"Job name from configuration file (required)")
 	_ = planCmd.MarkFlagRequired("job") // Config-time error, cannot fail
+	planCmd.Flags().StringVar(&planFormat, "format", "text",
+		"Output format: text, json or mermaid")
+	planCmd.Flags().BoolVar(&planEstimate, "estimate", false,
+		"Connect to the source database and include estimated row counts")
 
 	rootCmd.AddCommand(planCmd)
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
+	if planFormat != "text" && planFormat != "json" && planFormat != "mermaid" {
+		return fmt.Errorf("invalid --format %q: must be text, json or mermaid", planFormat)
+	}
+
 	configFile := GetConfigFile()
 
 	// Load configuration
@@ -70,6 +96,21 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to build dependency graph: %w", err)
 	}
+	if planFormat == "mermaid" {
+		return g.WriteMermaid(outputWriter)
+	}
+
+	// Get job-specific configs
+	jobProcessing := job.GetJobProcessing(cfg.Processing)
+	jobVerification := job.GetJobVerification(cfg.Verification)
+
+	report, err := buildPlanReport(cfg, job, g, jobProcessing, jobVerification)
+	if err != nil {
+		return err
+	}
+	if planFormat == "json" {
+		return report.WriteJSON(outputWriter)
+	}
 
 	// Display visual tree using mermaid-ascii
 	if err := printMermaidTree(job, cfg, g); err != nil {
@@ -95,11 +136,23 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	printSection("Job Overview")
 	fmt.Printf("  Root Table:  %s (PK: %s)\n", job.RootTable, job.PrimaryKey)
-	fmt.Printf("  Total Tables: %d\n", g.NodeCount())
+	stats := g.Stats()
+	fmt.Printf("  Total Tables: %d\n", stats.NodeCount)
+	fmt.Printf("  Relationships: %d\n", stats.EdgeCount)
+	fmt.Printf("  Leaf Tables:  %d\n", stats.LeafCount)
+	fmt.Printf("  Max Depth:    %d\n", stats.MaxDepth)
+	fmt.Printf("  Longest Chain: %s\n", strings.Join(g.LongestPath(), " → "))
 	if job.Where != "" {
 		fmt.Printf("  WHERE Clause: %s\n", job.Where)
 	}
 
+	// Levels section
+	fmt.Println()
+	printSection("Levels (tables by distance from the root)")
+	for i, level := range g.Levels() {
+		fmt.Printf("  [%d] %s\n", i, strings.Join(level, ", "))
+	}
+
 	// Copy order section
 	fmt.Println()
 	printSection("Copy Order (parent tables first)")
@@ -119,26 +172,23 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	// Relationships section
 	fmt.Println()
 	printSection("Detected Relationships")
-	for _, edge := range g.AllEdges() {
-		meta := g.GetEdgeMeta(edge.From, edge.To)
-		dependencyType := "unknown"
-		foreignKey := "unknown"
-		if meta != nil {
-			dependencyType = meta.DependencyType
-			foreignKey = meta.ForeignKey
+	for _, table := range copyOrder {
+		for _, edge := range g.EdgesFrom(table) {
+			dependencyType := "unknown"
+			foreignKey := "unknown"
+			if edge.Meta != nil {
+				dependencyType = edge.Meta.DependencyType
+				foreignKey = edge.Meta.ForeignKey
+			}
+			fmt.Printf("  • %s → %s (%s) FK: %s\n",
+				edge.From,
+				edge.To,
+				dependencyType,
+				foreignKey,
+			)
 		}
-		fmt.Printf("  • %s → %s (%s) FK: %s\n",
-			edge.From,
-			edge.To,
-			dependencyType,
-			foreignKey,
-		)
 	}
 
-	// Get job-specific configs
-	jobProcessing := job.GetJobProcessing(cfg.Processing)
-	jobVerification := job.GetJobVerification(cfg.Verification)
-
 	// Configuration section
 	fmt.Println()
 	printSection("Configuration")
@@ -163,7 +213,45 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	return nil
+	// Statement plan section
+	fmt.Println()
+	printSection("Statement Plan")
+	return report.WriteText(outputWriter)
+}
+
+// buildPlanReport builds the per-table statement plan, running the estimator
+// against the source database when --estimate is set.
+func buildPlanReport(cfg *config.Config, job *config.JobConfig, g *graph.Graph, processing config.ProcessingConfig, verification config.VerificationConfig) (*archiver.PlanReport, error) {
+	planner, err := archiver.NewPlanner(planJob, job, g, processing, verification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create planner: %w", err)
+	}
+	if !planEstimate {
+		return planner.Plan(nil)
+	}
+
+	log, err := newJobLogger(cfg, job, planJob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer syncLogger(log)
+
+	ctx := context.Background()
+	dbManager := database.NewManager(cfg)
+	if err := dbManager.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to databases: %w", err)
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Errorf("Failed to close database connections: %v", err)
+		}
+	}()
+
+	estimate, err := archiver.NewEstimator(dbManager.Source, cfg, job, g, log).Estimate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("estimation failed: %w", err)
+	}
+	return planner.Plan(estimate)
 }
 
 // printHeader prints a formatted header
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // setOutputWriter sets the output writer (used for testing)
@@ -43,6 +46,14 @@ func TestPlanCommandFlags(t *testing.T) {
 	if annotations != nil {
 		assert.Contains(t, annotations, "cobra_annotation_bash_completion_one_required_flag")
 	}
+
+	formatFlag := flags.Lookup("format")
+	assert.NotNil(t, formatFlag)
+	assert.Equal(t, "text", formatFlag.DefValue)
+
+	estimateFlag := flags.Lookup("estimate")
+	assert.NotNil(t, estimateFlag)
+	assert.Equal(t, "false", estimateFlag.DefValue)
 }
 
 func TestPlanIsAddedToRoot(t *testing.T) {
@@ -491,3 +502,67 @@ func TestPlanCmd_Execute_InvalidJob(t *testing.T) {
 	err := rootCmd.Execute()
 	assert.Error(t, err)
 }
+
+// writePlanTestConfig writes a config with one orders -> order_items job and
+// returns its path.
+func writePlanTestConfig(t *testing.T) string {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "plan.yaml")
+	configContent := `source:
+  host: 127.0.0.1
+  port: 3305
+  user: root
+  password: test
+  database: test_db
+
+destination:
+  host: 127.0.0.1
+  port: 3307
+  user: root
+  password: test
+  database: test_archive
+
+jobs:
+  test_job:
+    root_table: orders
+    primary_key: id
+    where: "id < 100"
+    relations:
+      - table: order_items
+        primary_key: id
+        foreign_key: order_id
+        dependency_type: "1-N"
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(configContent), 0644))
+	return configFile
+}
+
+func TestRunPlan_MermaidFormat(t *testing.T) {
+	origCfgFile, origPlanJob, origPlanFormat := cfgFile, planJob, planFormat
+	defer func() {
+		cfgFile, planJob, planFormat = origCfgFile, origPlanJob, origPlanFormat
+		resetOutputWriter()
+	}()
+
+	cfgFile = writePlanTestConfig(t)
+	planJob = "test_job"
+	planFormat = "mermaid"
+	var buf bytes.Buffer
+	setOutputWriter(&buf)
+
+	require.NoError(t, runPlan(planCmd, nil))
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, "graph TD\n"))
+	assert.Contains(t, output, "orders -->|order_id (1-N)| order_items")
+	assert.NotContains(t, output, "Execution Plan")
+}
+
+func TestRunPlan_InvalidFormat(t *testing.T) {
+	origPlanFormat := planFormat
+	defer func() { planFormat = origPlanFormat }()
+
+	planFormat = "dot"
+	err := runPlan(planCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "text, json or mermaid")
+}
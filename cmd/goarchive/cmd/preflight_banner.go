@@ -65,6 +65,10 @@ func runRuntimePreflight(
 		}
 	}
 	checker.SetVerification(verification)
+	checker.SetAllowExtraDestinationColumns(cfg.Safety.AllowExtraDestinationColumns)
+	checker.SetDiskSpaceLimit(cfg.Safety.DestinationFreeSpaceMB*1024*1024, cfg.Safety.DiskSpaceMargin)
+	checker.SetRejectGeneratedColumns(cfg.Safety.RejectGeneratedColumns)
+	checker.SetMultiPath(cfg.Safety.MultiPath)
 	if err := checker.RunWithProfile(ctx, profile, forceTriggers, enforceFKVisibility); err != nil {
 		return fmt.Errorf("preflight checks failed (run 'goarchive validate' for full diagnostics): %w", err)
 	}
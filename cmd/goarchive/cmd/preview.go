@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/dbsmedya/goarchive/internal/archiver"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	previewJob   string
+	previewLimit int
+)
+
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Show sample rows the next batch would archive",
+	Long: `Preview fetches the first batch of root rows matching the job's WHERE
+clause (batch_size roots), discovers their related rows and prints up to
+--limit full rows per table in copy order, so the data can be inspected
+before archiving. Nothing is written to either database.
+
+The preview always starts from the beginning of the root table: it ignores
+the resume checkpoint and the incremental_column window of earlier runs.
+
+Example:
+  goarchive preview --config archiver.yaml --job archive_old_orders
+  goarchive preview --job archive_old_orders --limit 3`,
+	RunE: runPreview,
+}
+
+func init() {
+	previewCmd.Flags().StringVarP(&previewJob, "job", "j", "",
+		"Job name from configuration file (required)")
+	_ = previewCmd.MarkFlagRequired("job") // Config-time error, cannot fail
+	previewCmd.Flags().IntVarP(&previewLimit, "limit", "n", 10,
+		"Maximum rows to show per table")
+
+	rootCmd.AddCommand(previewCmd)
+}
+
+func runPreview(cmd *cobra.Command, args []string) error {
+	if previewLimit <= 0 {
+		return fmt.Errorf("invalid --limit %d: must be positive", previewLimit)
+	}
+
+	configFile := GetConfigFile()
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	overrides := GetCLIOverrides()
+	cfg.ApplyOverrides(overrides.LogLevel, overrides.LogFormat, overrides.SkipVerify)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	jobCfgValue, exists := cfg.Jobs[previewJob]
+	if !exists {
+		return fmt.Errorf("job '%s' not found in configuration", previewJob)
+	}
+	jobCfg := &jobCfgValue
+
+	log, err := newJobLogger(cfg, jobCfg, previewJob)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer syncLogger(log)
+
+	g, err := graph.NewBuilder(jobCfg).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	if g.HasCycle() {
+		return fmt.Errorf("dependency cycle detected in graph")
+	}
+
+	dbManager := database.NewManager(cfg)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := dbManager.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Errorf("Failed to close database connections: %v", err)
+		}
+	}()
+	if err := dbManager.Ping(ctx); err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+
+	processing := cfg.GetJobProcessing(previewJob)
+	source := dbManager.ReadSource()
+	fetcher := archiver.NewRootIDFetcher(source, jobCfg.RootTable, g.GetPK(jobCfg.RootTable),
+		jobCfg.Where, processing.BatchSize, nil)
+	fetcher.SetOrder(processing.RootOrder)
+	rootPKs, err := fetcher.FetchNextBatch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch root rows: %w", err)
+	}
+	if len(rootPKs) == 0 {
+		_, _ = fmt.Fprintf(outputWriter, "No rows in %s match the job's WHERE clause.\n", jobCfg.RootTable)
+		return nil
+	}
+
+	discovery, err := archiver.NewRecordDiscovery(g, source, processing.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to create record discovery: %w", err)
+	}
+	discovery.SetLogger(log.WithPhase("discovery"))
+	discovery.SetMaxInClauseSize(processing.MaxInClauseSize)
+	recordSet, err := discovery.Discover(ctx, rootPKs)
+	if err != nil {
+		return fmt.Errorf("record discovery failed: %w", err)
+	}
+
+	copyOrder, err := g.CopyOrder()
+	if err != nil {
+		return fmt.Errorf("failed to get copy order: %w", err)
+	}
+	for _, table := range copyOrder {
+		pks := recordSet.Records[table]
+		rows, err := discovery.PreviewRows(ctx, table, pks, previewLimit)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(outputWriter, "\n=== %s: %d rows in batch, showing %d ===\n", table, len(pks), len(rows))
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(outputWriter, formatPreviewRow(row, g.GetPK(table)))
+		}
+	}
+	return nil
+}
+
+// formatPreviewRow renders row as col=value pairs, the primary key first and
+// the other columns by name. Binary values are shown as hex.
+func formatPreviewRow(row map[string]interface{}, pkColumn string) string {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		if col != pkColumn {
+			cols = append(cols, col)
+		}
+	}
+	sort.Strings(cols)
+	if _, ok := row[pkColumn]; ok {
+		cols = append([]string{pkColumn}, cols...)
+	}
+
+	fields := make([]string, len(cols))
+	for i, col := range cols {
+		switch v := row[col].(type) {
+		case nil:
+			fields[i] = col + "=NULL"
+		case []byte:
+			fields[i] = fmt.Sprintf("%s=0x%X", col, v)
+		case string:
+			fields[i] = fmt.Sprintf("%s=%q", col, v)
+		default:
+			fields[i] = fmt.Sprintf("%s=%v", col, v)
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewCommandStructure(t *testing.T) {
+	assert.NotNil(t, previewCmd)
+	assert.Equal(t, "preview", previewCmd.Use)
+	assert.NotNil(t, previewCmd.RunE)
+
+	flags := previewCmd.Flags()
+	jobFlag := flags.Lookup("job")
+	assert.NotNil(t, jobFlag)
+	assert.Equal(t, "j", jobFlag.Shorthand)
+	limitFlag := flags.Lookup("limit")
+	assert.NotNil(t, limitFlag)
+	assert.Equal(t, "10", limitFlag.DefValue)
+}
+
+func TestFormatPreviewRow(t *testing.T) {
+	row := map[string]interface{}{
+		"status": "closed",
+		"id":     int64(7),
+		"note":   nil,
+		"digest": []byte{0xde, 0xad},
+	}
+	assert.Equal(t, `id=7 digest=0xDEAD note=NULL status="closed"`, formatPreviewRow(row, "id"))
+}
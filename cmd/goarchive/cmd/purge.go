@@ -16,7 +16,10 @@ var (
 	purgeJob                   string
 	purgeForce                 bool
 	purgeSkipValidatePreflight bool
+	purgeForceMaxDeleteRows    bool
 	purgeForceTriggers         bool
+	purgeVerifyDestination     bool
+	purgeStopMode              string
 )
 
 var purgeCmd = &cobra.Command{
@@ -29,6 +32,10 @@ The purge process:
   1. Discover all related records using BFS traversal
   2. Delete from source in dependency order (child-first)
 
+With --verify-destination, each root's records are first verified against
+the destination (job verification method) and nothing is deleted for a root
+that does not match. Use this to delete after an earlier copy-only run.
+
 WARNING: This permanently deletes data. Use --dry-run first to verify.
 
 Example:
@@ -47,11 +54,23 @@ func init() {
 		"Skip preflight checks before this run (DANGEROUS - see docs)")
 	purgeCmd.Flags().BoolVar(&purgeForceTriggers, "force-triggers", false,
 		"Proceed despite DELETE triggers detected by preflight")
+	purgeCmd.Flags().BoolVar(&purgeForceMaxDeleteRows, "force-max-delete-rows", false,
+		"Delete past safety.max_delete_rows for this run (the cap guards against a where clause that matches far more rows than intended)")
+	purgeCmd.Flags().BoolVar(&purgeVerifyDestination, "verify-destination", false,
+		"Delete only records that verify against the destination (delete after a prior copy-only run)")
+
+	purgeCmd.Flags().StringVar(&purgeStopMode, "stop-mode", "finish-batch",
+		"What the first SIGINT/SIGTERM does to the in-flight batch: finish-batch (complete it and commit its checkpoint, then stop) or immediate (cancel it now; the next run replays it)")
 
 	rootCmd.AddCommand(purgeCmd)
 }
 
 func runPurge(cmd *cobra.Command, args []string) error {
+	stopMode, err := archiver.ParseStopMode(purgeStopMode)
+	if err != nil {
+		return err
+	}
+
 	configFile := GetConfigFile()
 
 	// Load configuration
@@ -95,7 +114,7 @@ func runPurge(cmd *cobra.Command, args []string) error {
 	// (replay recovers whatever is left). A third Ctrl-C hard-terminates.
 	ctx, stopCh := database.SetupGracefulShutdown(
 		func(_ os.Signal) {
-			log.Warn("Received shutdown signal - finishing current batch, then stopping (Ctrl-C again to abort now)...")
+			log.Warn(shutdownSignalMessage(stopMode))
 		},
 		func(_ os.Signal) {
 			log.Error("Received second shutdown signal - aborting in-flight work")
@@ -131,9 +150,23 @@ func runPurge(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("purge orchestrator initialization failed: %w", err)
 	}
 	orch.SetForce(purgeForce)
+	orch.SetForceMaxDeleteRows(purgeForceMaxDeleteRows)
 	orch.SetStopChannel(stopCh)
+	orch.SetStopMode(stopMode)
+	if jobCfg.PhaseHook != "" {
+		orch.SetHooks(archiver.CommandHooks{Path: jobCfg.PhaseHook})
+	}
+	orch.SetVerifyDestination(purgeVerifyDestination)
+	stopHealth, err := startHealthServer(cfg, dbManager, nil, log)
+	if err != nil {
+		return err
+	}
+	defer stopHealth()
 	result, err := orch.Execute(ctx)
 	if err != nil {
+		if errors.Is(err, archiver.ErrRuntimeBudgetExceeded) {
+			return fmt.Errorf("purge operation stopped (processing.max_runtime reached; run again to resume): %w", err)
+		}
 		if errors.Is(err, context.Canceled) {
 			log.Warn("Purge operation cancelled by user")
 			return fmt.Errorf("purge operation cancelled: %w", err)
@@ -146,6 +179,7 @@ func runPurge(cmd *cobra.Command, args []string) error {
 		"duration", result.Duration,
 		"batches_processed", result.BatchesProcessed,
 		"records_deleted", result.RecordsDeleted,
+		"records_verified", result.RecordsVerified,
 	)
 
 	// Display results
@@ -154,6 +188,9 @@ func runPurge(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Duration: %s\n", result.Duration)
 	fmt.Printf("Batches processed: %d\n", result.BatchesProcessed)
 	fmt.Printf("Records deleted: %d\n", result.RecordsDeleted)
+	if purgeVerifyDestination {
+		fmt.Printf("Records verified on destination: %d\n", result.RecordsVerified)
+	}
 	fmt.Println("\nℹ️  No data was copied (purge mode)")
 
 	return nil
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	relationsJob      string
+	relationsMaxDepth int
+)
+
+var relationsCmd = &cobra.Command{
+	Use:   "relations",
+	Short: "Generate a job's relations from the source database's foreign keys",
+	Long: `Relations builds the dependency graph of the job's root table from the
+foreign keys of the source database and prints it as a relations: block to
+paste into the job configuration.
+
+Every table whose single-column foreign key references its parent's primary
+key is included, down to --max-depth levels of children. The dependency
+type is 1-1 when the foreign key column is uniquely indexed, otherwise 1-N.
+The job's exclude_tables are pruned, with their descendants, before
+printing. Review the result before archiving with it: relations the
+database does not declare as foreign keys are not found.
+
+Example:
+  goarchive relations --config archiver.yaml --job archive_old_orders
+  goarchive relations --job archive_old_orders --max-depth 2 > relations.yaml`,
+	RunE: runRelations,
+}
+
+func init() {
+	relationsCmd.Flags().StringVarP(&relationsJob, "job", "j", "",
+		"Job name from configuration file (required)")
+	_ = relationsCmd.MarkFlagRequired("job") // Config-time error, cannot fail
+	relationsCmd.Flags().IntVar(&relationsMaxDepth, "max-depth", graph.DefaultSchemaMaxDepth,
+		"Levels of child tables to follow below the root table")
+
+	rootCmd.AddCommand(relationsCmd)
+}
+
+func runRelations(cmd *cobra.Command, args []string) error {
+	configFile := GetConfigFile()
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	overrides := GetCLIOverrides()
+	cfg.ApplyOverrides(overrides.LogLevel, overrides.LogFormat, overrides.SkipVerify)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	jobCfgValue, exists := cfg.Jobs[relationsJob]
+	if !exists {
+		return fmt.Errorf("job '%s' not found in configuration", relationsJob)
+	}
+	jobCfg := &jobCfgValue
+
+	log, err := newJobLogger(cfg, jobCfg, relationsJob)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer syncLogger(log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	dbManager := database.NewManager(cfg)
+	if err := dbManager.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+	defer func() {
+		if err := dbManager.Close(); err != nil {
+			log.Errorf("Failed to close database connections: %v", err)
+		}
+	}()
+
+	builder := graph.NewBuilder(jobCfg)
+	builder.SetLogger(log)
+	g, err := builder.BuildFromSchema(ctx, dbManager.Source, relationsMaxDepth)
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph from schema: %w", err)
+	}
+	return writeSchemaRelations(cmd.OutOrStdout(), g)
+}
+
+// writeSchemaRelations writes the relations below g's root as a YAML
+// relations: block in the job configuration format.
+func writeSchemaRelations(w io.Writer, g *graph.Graph) error {
+	doc := struct {
+		Relations []config.Relation `yaml:"relations"`
+	}{Relations: schemaRelations(g, g.Root)}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write relations: %w", err)
+	}
+	return enc.Close()
+}
+
+// schemaRelations converts the children of table into config relations,
+// recursively. Graphs built by BuildFromSchema are trees, so every table is
+// emitted once.
+func schemaRelations(g *graph.Graph, table string) []config.Relation {
+	var relations []config.Relation
+	for _, child := range g.GetChildren(table) {
+		rel := config.Relation{Table: child, PrimaryKey: g.GetPK(child)}
+		if meta := g.GetEdgeMeta(table, child); meta != nil {
+			rel.ForeignKey, rel.DependencyType = meta.ForeignKey, meta.DependencyType
+		}
+		rel.Relations = schemaRelations(g, child)
+		relations = append(relations, rel)
+	}
+	return relations
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRelationsCommandStructure(t *testing.T) {
+	assert.NotNil(t, relationsCmd)
+	assert.Equal(t, "relations", relationsCmd.Use)
+	assert.NotNil(t, relationsCmd.RunE)
+
+	flags := relationsCmd.Flags()
+	jobFlag := flags.Lookup("job")
+	require.NotNil(t, jobFlag)
+	assert.Equal(t, "j", jobFlag.Shorthand)
+	depthFlag := flags.Lookup("max-depth")
+	require.NotNil(t, depthFlag)
+	assert.Equal(t, "10", depthFlag.DefValue)
+}
+
+func TestWriteSchemaRelations(t *testing.T) {
+	// users -> orders -> order_items, users -> profiles (1-1)
+	g := graph.NewGraph("users", "id")
+	for _, name := range []string{"orders", "order_items", "profiles"} {
+		g.AddNode(name, nil)
+	}
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("orders", "order_items", "order_id", "order_id", "1-N")
+	g.AddEdgeWithMeta("users", "profiles", "user_id", "id", "1-1")
+	g.SetPK("orders", "order_id")
+	g.SetPK("order_items", "id")
+	g.SetPK("profiles", "id")
+
+	var buf bytes.Buffer
+	require.NoError(t, writeSchemaRelations(&buf, g))
+
+	// The output must load back as a job's relations.
+	var job config.JobConfig
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &job), buf.String())
+	want := []config.Relation{
+		{Table: "orders", PrimaryKey: "order_id", ForeignKey: "user_id", DependencyType: "1-N",
+			Relations: []config.Relation{
+				{Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N", Relations: []config.Relation{}},
+			}},
+		{Table: "profiles", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-1", Relations: []config.Relation{}},
+	}
+	assert.Equal(t, want, job.Relations)
+	assert.Contains(t, buf.String(), "relations:\n  - table: orders\n")
+}
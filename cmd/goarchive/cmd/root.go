@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/dbsmedya/goarchive/internal/archiver"
 	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/logger"
 	"github.com/spf13/cobra"
@@ -106,6 +107,15 @@ func syncLogger(log *logger.Logger) {
 	}
 }
 
+// shutdownSignalMessage is logged on the first SIGINT/SIGTERM of a
+// copy→verify→delete run.
+func shutdownSignalMessage(mode archiver.StopMode) string {
+	if mode == archiver.StopImmediately {
+		return fmt.Sprintf("Received shutdown signal (--stop-mode=%s) - canceling current batch and stopping (it is replayed on the next run)...", mode)
+	}
+	return fmt.Sprintf("Received shutdown signal (--stop-mode=%s) - finishing current batch, then stopping (Ctrl-C again to abort now)...", mode)
+}
+
 // GetConfigFile returns the config file path
 func GetConfigFile() string {
 	return cfgFile
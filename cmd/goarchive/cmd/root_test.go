@@ -3,6 +3,7 @@ package cmd
 import (
 	"testing"
 
+	"github.com/dbsmedya/goarchive/internal/archiver"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -167,3 +168,8 @@ func TestRootCommandSubcommands(t *testing.T) {
 		assert.Contains(t, commandNames, expected, "Expected command %s not found", expected)
 	}
 }
+
+func TestShutdownSignalMessage(t *testing.T) {
+	assert.Contains(t, shutdownSignalMessage(archiver.StopAfterBatch), "--stop-mode=finish-batch")
+	assert.Contains(t, shutdownSignalMessage(archiver.StopImmediately), "--stop-mode=immediate")
+}
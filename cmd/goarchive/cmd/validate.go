@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 
@@ -16,6 +17,7 @@ import (
 var (
 	validateForceTriggers bool
 	validateJob           string
+	validateAllErrors     bool
 )
 
 var validateCmd = &cobra.Command{
@@ -35,7 +37,8 @@ Checks performed:
 
 Example:
   goarchive validate --config archiver.yaml
-  goarchive validate --job archive_old_orders`,
+  goarchive validate --job archive_old_orders
+  goarchive validate --all-errors`,
 	RunE: runValidate,
 }
 
@@ -44,6 +47,8 @@ func init() {
 	validateCmd.Flags().BoolVar(&validateForceTriggers, "force-triggers", false, "Allow DELETE triggers (triggers will fire during delete)")
 	validateCmd.Flags().StringVarP(&validateJob, "job", "j", "",
 		"Validate only this job (default: validate all jobs)")
+	validateCmd.Flags().BoolVar(&validateAllErrors, "all-errors", false,
+		"Run every preflight check and report all failures instead of stopping at the first")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -119,6 +124,13 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		fmt.Println("   copy order and understand the risk of inserting orphaned rows.")
 		fmt.Println()
 	}
+	if cfg.Safety.DisableSourceForeignKeyChecks {
+		fmt.Println("⚠️  WARNING: safety.disable_source_foreign_key_checks is ENABLED.")
+		fmt.Println("   Source deletes will skip FK constraint validation during delete.")
+		fmt.Println("   Rows outside the job graph that reference deleted rows are left")
+		fmt.Println("   orphaned instead of blocking the delete.")
+		fmt.Println()
+	}
 
 	// Validate each job, collecting failures so the summary names every job
 	// that did not pass.
@@ -132,6 +144,9 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Root Table:  %s\n", jobCfg.RootTable)
 		fmt.Printf("   Primary Key: %s\n", jobCfg.PrimaryKey)
 		fmt.Printf("   Relations:   %d table(s)\n", len(jobCfg.Relations))
+		if cfg.Safety.RequireConfirmation {
+			fmt.Printf("   Confirm Token: %s\n", jobCfg.ExpectedConfirmToken(jobName))
+		}
 
 		if err := validateJobConfig(ctx, cfg, jobCfg, dbManager, log); err != nil {
 			fmt.Printf("   ❌ FAILED: %v\n\n", err)
@@ -176,6 +191,9 @@ func validateJobConfig(
 		cycleInfo := g.DetectIncompleteProcessing()
 		return fmt.Errorf("cycle detected: %d nodes in cycle", len(cycleInfo.UnprocessedNodes))
 	}
+	if problems := g.ValidateStructure(); len(problems) > 0 {
+		return fmt.Errorf("graph structure invalid: %w", errors.Join(problems...))
+	}
 
 	checker, err := archiver.NewPreflightChecker(dbManager.Source, cfg.Source.Database, g, log)
 	if err != nil {
@@ -184,7 +202,12 @@ func validateJobConfig(
 	if err := checker.ConfigureDestination(dbManager.Destination, cfg.Destination.Database, cfg.Destination.EffectiveJobSchema()); err != nil {
 		return fmt.Errorf("failed to configure destination preflight checks: %w", err)
 	}
-	checker.SetVerification(jobCfg.GetJobVerification(cfg.Verification))
+	checker.SetVerification(jobCfg.WeakestVerification(jobCfg.GetJobVerification(cfg.Verification)))
+	checker.SetAllowExtraDestinationColumns(cfg.Safety.AllowExtraDestinationColumns)
+	checker.SetDiskSpaceLimit(cfg.Safety.DestinationFreeSpaceMB*1024*1024, cfg.Safety.DiskSpaceMargin)
+	checker.SetRejectGeneratedColumns(cfg.Safety.RejectGeneratedColumns)
+	checker.SetMultiPath(cfg.Safety.MultiPath)
+	checker.SetAggregateErrors(validateAllErrors)
 
 	if err := checker.RunAllChecks(ctx, validateForceTriggers); err != nil {
 		return fmt.Errorf("preflight checks failed: %w", err)
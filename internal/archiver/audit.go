@@ -0,0 +1,153 @@
+package archiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// Delete audit events written by DeleteAuditLog.
+const (
+	auditEventDelete  = "delete"
+	auditEventSummary = "summary"
+)
+
+// deleteAuditEntry is one JSON line of the delete audit log. A "delete" line
+// describes one DELETE statement; the final "summary" line rolls up the run.
+type deleteAuditEntry struct {
+	Time         string           `json:"ts"`
+	Job          string           `json:"job"`
+	Event        string           `json:"event"`
+	Table        string           `json:"table,omitempty"`
+	PKs          []interface{}    `json:"pks,omitempty"`
+	RowsAffected int64            `json:"rows_affected"`
+	Statements   int              `json:"statements,omitempty"`
+	RowsPerTable map[string]int64 `json:"rows_per_table,omitempty"`
+}
+
+// DeleteAuditLog records every DELETE the delete phase issues as one JSON line
+// (timestamp, job, table, the PKs bound to the statement, rows affected), and
+// a final summary line on Close. It is an append-only compliance record:
+// each line is written and, for files, fsynced before the next DELETE runs.
+//
+// In transactional delete mode lines are written only after the transaction
+// commits, so the log never lists rows that were rolled back.
+type DeleteAuditLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	file   *os.File // set when the log owns a file; synced and closed by the log
+	job    string
+	now    func() time.Time
+	closed bool
+
+	statements   int
+	rowsPerTable map[string]int64
+}
+
+// NewDeleteAuditLog writes audit lines for job to w. The caller owns w; Close
+// writes the summary line but does not close w.
+func NewDeleteAuditLog(w io.Writer, job string) *DeleteAuditLog {
+	return &DeleteAuditLog{
+		w:            w,
+		job:          job,
+		now:          time.Now,
+		rowsPerTable: make(map[string]int64),
+	}
+}
+
+// OpenDeleteAuditLog opens (creating if needed) the audit file at path in
+// append mode, so successive runs extend the same record.
+func OpenDeleteAuditLog(path, job string) (*DeleteAuditLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delete audit log: %w", err)
+	}
+	a := NewDeleteAuditLog(f, job)
+	a.file = f
+	return a, nil
+}
+
+// RecordDelete writes the line for one executed DELETE statement.
+func (a *DeleteAuditLog) RecordDelete(table string, pks []interface{}, rowsAffected int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.statements++
+	a.rowsPerTable[table] += rowsAffected
+	return a.writeLocked(deleteAuditEntry{
+		Event:        auditEventDelete,
+		Table:        table,
+		PKs:          auditPKs(pks),
+		RowsAffected: rowsAffected,
+	})
+}
+
+// Close writes the summary line (statement count and rows deleted per table)
+// and closes the file if the log opened it. Safe to call more than once.
+func (a *DeleteAuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+
+	var total int64
+	for _, n := range a.rowsPerTable {
+		total += n
+	}
+	err := a.writeLocked(deleteAuditEntry{
+		Event:        auditEventSummary,
+		RowsAffected: total,
+		Statements:   a.statements,
+		RowsPerTable: a.rowsPerTable,
+	})
+	if a.file != nil {
+		if cerr := a.file.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close delete audit log: %w", cerr)
+		}
+	}
+	return err
+}
+
+func (a *DeleteAuditLog) writeLocked(entry deleteAuditEntry) error {
+	if a.closed && entry.Event != auditEventSummary {
+		return fmt.Errorf("delete audit log is closed")
+	}
+	entry.Time = a.now().UTC().Format(time.RFC3339Nano)
+	entry.Job = a.job
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode delete audit entry: %w", err)
+	}
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write delete audit log: %w", err)
+	}
+	if a.file != nil {
+		if err := a.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync delete audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+// auditPKs converts driver values for JSON: binary ([]byte) PKs are written
+// in their canonical hex form (types.EncodePK) rather than the base64
+// encoding/json would otherwise emit.
+func auditPKs(pks []interface{}) []interface{} {
+	out := make([]interface{}, len(pks))
+	for i, pk := range pks {
+		if b, ok := pk.([]byte); ok {
+			out[i], _ = types.EncodePK(b)
+		} else {
+			out[i] = pk
+		}
+	}
+	return out
+}
@@ -0,0 +1,143 @@
+package archiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+func readAuditLines(t *testing.T, data []byte) []deleteAuditEntry {
+	t.Helper()
+	var entries []deleteAuditEntry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var e deleteAuditEntry
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &e), "line: %s", sc.Text())
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestDeleteAuditLog_RecordsEveryBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	dp, err := NewDeletePhase(db, createMultiLevelGraph(), 2, logger.NewDefault())
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	audit := NewDeleteAuditLog(&buf, "archive_customers")
+	audit.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	dp.SetAuditLog(audit)
+
+	// Children first: orders in two chunks of batch size 2, then customers.
+	mock.ExpectExec("DELETE FROM `orders`").WithArgs(int64(10), int64(11)).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `orders`").WithArgs(int64(12)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `customers`").WithArgs([]byte("7")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = dp.Delete(context.Background(), &RecordSet{
+		RootPKs: []interface{}{[]byte("7")},
+		Records: map[string][]interface{}{
+			"customers": {[]byte("7")},
+			"orders":    {int64(10), int64(11), int64(12)},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, audit.Close())
+	require.NoError(t, audit.Close(), "Close is idempotent")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	entries := readAuditLines(t, buf.Bytes())
+	require.Len(t, entries, 4)
+	for _, e := range entries {
+		assert.Equal(t, "archive_customers", e.Job)
+		assert.Equal(t, "2026-01-02T03:04:05Z", e.Time)
+	}
+	assert.Equal(t, deleteAuditEntry{Time: entries[0].Time, Job: "archive_customers", Event: "delete",
+		Table: "orders", PKs: []interface{}{float64(10), float64(11)}, RowsAffected: 2}, entries[0])
+	assert.Equal(t, []interface{}{float64(12)}, entries[1].PKs)
+	assert.Equal(t, "customers", entries[2].Table)
+	assert.Equal(t, []interface{}{"0x37"}, entries[2].PKs, "binary ([]byte) PKs are logged in canonical hex form")
+
+	summary := entries[3]
+	assert.Equal(t, "summary", summary.Event)
+	assert.Equal(t, 3, summary.Statements)
+	assert.Equal(t, int64(4), summary.RowsAffected)
+	assert.Equal(t, map[string]int64{"orders": 3, "customers": 1}, summary.RowsPerTable)
+}
+
+func TestDeleteAuditLog_TransactionalWritesOnlyAfterCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	dp, err := NewDeletePhase(db, createMultiLevelGraph(), 10, logger.NewDefault())
+	require.NoError(t, err)
+	dp.SetTransactional(true)
+	var buf bytes.Buffer
+	audit := NewDeleteAuditLog(&buf, "job")
+	dp.SetAuditLog(audit)
+
+	records := &RecordSet{
+		RootPKs: []interface{}{int64(1)},
+		Records: map[string][]interface{}{"customers": {int64(1)}, "orders": {int64(5)}},
+	}
+
+	// First attempt fails on the parent and rolls back: nothing is audited.
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `orders`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `customers`").WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectRollback()
+	_, err = dp.Delete(context.Background(), records)
+	require.Error(t, err)
+	assert.Zero(t, buf.Len(), "rolled-back deletes must not be audited")
+
+	// Retry commits: both statements are audited after COMMIT.
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `orders`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `customers`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	_, err = dp.Delete(context.Background(), records)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	entries := readAuditLines(t, buf.Bytes())
+	require.Len(t, entries, 2)
+	assert.Equal(t, "orders", entries[0].Table)
+	assert.Equal(t, "customers", entries[1].Table)
+}
+
+func TestOpenDeleteAuditLog_AppendsAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deletes.jsonl")
+
+	for run := 0; run < 2; run++ {
+		audit, err := OpenDeleteAuditLog(path, "job")
+		require.NoError(t, err)
+		require.NoError(t, audit.RecordDelete("orders", []interface{}{int64(run)}, 1))
+		require.NoError(t, audit.Close())
+		assert.Error(t, audit.RecordDelete("orders", nil, 0), "writes after Close are rejected")
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	entries := readAuditLines(t, data)
+	require.Len(t, entries, 4)
+	assert.Equal(t, []string{"delete", "summary", "delete", "summary"},
+		[]string{entries[0].Event, entries[1].Event, entries[2].Event, entries[3].Event})
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
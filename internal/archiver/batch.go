@@ -11,6 +11,10 @@ import (
 
 // RootIDFetcher handles fetching batches of root table primary keys.
 // It supports checkpoint-based resumption and respects configurable batch sizes.
+// Pages use keyset (seek) pagination on the root PK (`pk > checkpoint ORDER BY
+// pk LIMIT n`, or `pk < checkpoint ORDER BY pk DESC` in descending order),
+// never OFFSET, so each page costs the same however deep the scan and root
+// PKs are never loaded up front.
 //
 // GA-P3-F1-T1: Root ID Fetcher
 type RootIDFetcher struct {
@@ -19,7 +23,11 @@ type RootIDFetcher struct {
 	pkColumn   string
 	criteria   string
 	batchSize  int
-	checkpoint interface{} // Last processed integer PK value; nil means no lower bound.
+	checkpoint interface{} // Last processed integer PK value; nil means no bound.
+	descending bool        // processing.root_order: desc
+
+	// window, when set, limits root rows to an incremental_column range.
+	window *incrementalWindow
 }
 
 // NewRootIDFetcher creates a new RootIDFetcher for the specified root table.
@@ -44,8 +52,9 @@ func NewRootIDFetcher(db *sql.DB, rootTable, pkColumn, criteria string, batchSiz
 
 // FetchNextBatch retrieves the next batch of root IDs matching the criteria.
 //
-// The query respects the checkpoint by selecting only PKs greater than the last
-// processed value, ensuring progress can resume after interruption.
+// The query respects the checkpoint by selecting only PKs past the last
+// processed value (greater, or smaller in descending order), ensuring
+// progress can resume after interruption.
 //
 // Returns:
 //   - []interface{}: Slice of primary key values (empty if no more rows)
@@ -69,29 +78,38 @@ func (f *RootIDFetcher) FetchNextBatch(ctx context.Context) ([]interface{}, erro
 	// This ensures:
 	// 1. Only rows matching criteria are selected
 	// 2. Resume from checkpoint (pk > last_processed), or start unbounded on first run
-	// 3. Deterministic ordering (pk ASC)
+	// 3. Deterministic ordering (pk ASC, or pk DESC with pk < checkpoint)
 	// 4. Controlled batch size
+	bounds, args := f.window.conditions()
+	direction, past := "ASC", ">"
+	if f.descending {
+		direction, past = "DESC", "<"
+	}
 	var query string
-	var args []interface{}
 	if f.checkpoint == nil {
 		query = fmt.Sprintf(
-			"SELECT %s FROM %s WHERE (%s) ORDER BY %s ASC LIMIT ?",
+			"SELECT %s FROM %s WHERE (%s)%s ORDER BY %s %s LIMIT ?",
 			sqlutil.QuoteIdentifier(f.pkColumn),
 			sqlutil.QuoteIdentifier(f.rootTable),
 			whereClause,
+			bounds,
 			sqlutil.QuoteIdentifier(f.pkColumn),
+			direction,
 		)
-		args = []interface{}{f.batchSize}
+		args = append(args, f.batchSize)
 	} else {
 		query = fmt.Sprintf(
-			"SELECT %s FROM %s WHERE (%s) AND %s > ? ORDER BY %s ASC LIMIT ?",
+			"SELECT %s FROM %s WHERE (%s)%s AND %s %s ? ORDER BY %s %s LIMIT ?",
 			sqlutil.QuoteIdentifier(f.pkColumn),
 			sqlutil.QuoteIdentifier(f.rootTable),
 			whereClause,
+			bounds,
 			sqlutil.QuoteIdentifier(f.pkColumn),
+			past,
 			sqlutil.QuoteIdentifier(f.pkColumn),
+			direction,
 		)
-		args = []interface{}{f.checkpoint, f.batchSize}
+		args = append(args, f.checkpoint, f.batchSize)
 	}
 
 	rows, err := f.db.QueryContext(ctx, query, args...)
@@ -123,6 +141,18 @@ func (f *RootIDFetcher) FetchNextBatch(ctx context.Context) ([]interface{}, erro
 	return ids, nil
 }
 
+// SetIncrementalWindow limits fetched root rows to w's incremental_column
+// range. A nil w (the default) fetches every row matching the criteria.
+func (f *RootIDFetcher) SetIncrementalWindow(w *incrementalWindow) {
+	f.window = w
+}
+
+// SetOrder sets the root PK order (processing.root_order): "desc" fetches
+// from the highest PK down; anything else, ascending (the default).
+func (f *RootIDFetcher) SetOrder(order string) {
+	f.descending = order == "desc"
+}
+
 // UpdateCheckpoint updates the last processed PK value.
 // This should be called after successfully processing a batch to enable resumption.
 func (f *RootIDFetcher) UpdateCheckpoint(lastID interface{}) {
@@ -176,3 +176,91 @@ func TestRootIDFetcher_EmptyStringCheckpointStartsUnbounded(t *testing.T) {
 	assert.Equal(t, []interface{}{int64(0), int64(1)}, ids)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestRootIDFetcher_KeysetPagesUntilExhausted walks a full scan the way the
+// orchestrator does: each page seeks past the last PK of the previous one, a
+// short page is still processed, and the following empty page ends the scan.
+func TestRootIDFetcher_KeysetPagesUntilExhausted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	const seek = "SELECT `id` FROM `orders` WHERE \\(status = 'closed'\\) AND `id` > \\? ORDER BY `id` ASC LIMIT \\?"
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE \\(status = 'closed'\\) ORDER BY `id` ASC LIMIT \\?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4))
+	mock.ExpectQuery(seek).
+		WithArgs(int64(4), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).AddRow(9))
+	mock.ExpectQuery(seek).
+		WithArgs(int64(9), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectQuery(seek).
+		WithArgs(int64(12), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	fetcher := NewRootIDFetcher(db, "orders", "id", "status = 'closed'", 2, nil)
+
+	var pages [][]interface{}
+	for {
+		ids, err := fetcher.FetchNextBatch(context.Background())
+		assert.NoError(t, err)
+		if len(ids) == 0 {
+			break
+		}
+		pages = append(pages, ids)
+		fetcher.UpdateCheckpoint(ids[len(ids)-1])
+	}
+
+	want := [][]interface{}{
+		{int64(1), int64(4)},
+		{int64(7), int64(9)},
+		{int64(12)},
+	}
+	assert.Equal(t, want, pages)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRootIDFetcher_DescendingOrder(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE (status = 'closed') ORDER BY `id` DESC LIMIT ?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12).AddRow(9))
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE (status = 'closed') AND `id` < ? ORDER BY `id` DESC LIMIT ?").
+		WithArgs(int64(9), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	fetcher := NewRootIDFetcher(db, "orders", "id", "status = 'closed'", 2, nil)
+	fetcher.SetOrder("desc")
+
+	ids, err := fetcher.FetchNextBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(12), int64(9)}, ids)
+	// The orchestrator checkpoints the last PK of a page: here the lowest.
+	fetcher.UpdateCheckpoint(ids[len(ids)-1])
+	ids, err = fetcher.FetchNextBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(4)}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRootIDFetcher_AscendingOrderByDefault(t *testing.T) {
+	for _, order := range []string{"", "asc"} {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+
+		mock.ExpectQuery("SELECT `id` FROM `orders` WHERE (1=1) AND `id` > ? ORDER BY `id` ASC LIMIT ?").
+			WithArgs(5, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		fetcher := NewRootIDFetcher(db, "orders", "id", "", 10, 5)
+		fetcher.SetOrder(order)
+		_, err = fetcher.FetchNextBatch(context.Background())
+		assert.NoError(t, err, "order %q", order)
+		assert.NoError(t, mock.ExpectationsWereMet(), "order %q", order)
+		_ = db.Close()
+	}
+}
@@ -0,0 +1,86 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// CascadeDeletedTables returns the graph tables whose rows are removed by an
+// ON DELETE CASCADE foreign key when their parent rows are deleted, mapped to
+// the parent that cascades into them. A table qualifies only when EVERY graph
+// edge into it is backed by a CASCADE constraint on the configured FK column;
+// a single RESTRICT/NO ACTION parent would reject the parent DELETE if the
+// child rows were left in place. The root table never qualifies.
+func (p *PreflightChecker) CascadeDeletedTables(ctx context.Context) (map[string]string, error) {
+	fks, err := p.getForeignKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get foreign keys: %w", err)
+	}
+	return cascadeDeletedTables(p.graph, fks, p.sourceDBName), nil
+}
+
+// cascadeDeletedTables matches graph edges against source FK metadata. See
+// CascadeDeletedTables.
+func cascadeDeletedTables(g *graph.Graph, fks []ForeignKeyResult, sourceDBName string) map[string]string {
+	type fkKey struct{ table, column, refTable, refColumn string }
+	cascading := make(map[fkKey]bool)
+	for _, fk := range fks {
+		if fk.OnDelete != "CASCADE" || fk.TableSchema != sourceDBName || fk.ReferencedTableSchema != sourceDBName {
+			continue
+		}
+		cascading[fkKey{fk.Table, fk.Column, fk.ReferencedTable, fk.ReferencedColumn}] = true
+	}
+
+	result := make(map[string]string)
+	for _, table := range g.AllNodes() {
+		parents := g.GetParents(table)
+		if table == g.Root || len(parents) == 0 {
+			continue
+		}
+		allCascade := true
+		for _, parent := range parents {
+			meta := g.GetEdgeMeta(parent, table)
+			if meta == nil || !cascading[fkKey{table, meta.ForeignKey, parent, meta.ReferenceKey}] {
+				allCascade = false
+				break
+			}
+		}
+		if allCascade {
+			result[table] = parents[0]
+		}
+	}
+	return result
+}
+
+// applyCascadeSkips wires safety.skip_cascaded_deletes into the delete phase:
+// when enabled, tables fully covered by ON DELETE CASCADE are not deleted
+// explicitly. A no-op when the option is off.
+func applyCascadeSkips(ctx context.Context, db *sql.DB, sourceDBName string, g *graph.Graph, safety config.SafetyConfig, log *logger.Logger, deletePhase *DeletePhase) error {
+	if !safety.SkipCascadedDeletes {
+		return nil
+	}
+	checker, err := NewPreflightChecker(db, sourceDBName, g, log)
+	if err != nil {
+		return err
+	}
+	cascaded, err := checker.CascadeDeletedTables(ctx)
+	if err != nil {
+		return err
+	}
+	if len(cascaded) > 0 {
+		tables := make([]string, 0, len(cascaded))
+		for table := range cascaded {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		log.Infow("Delete phase will rely on ON DELETE CASCADE for these tables", "tables", tables)
+	}
+	deletePhase.SetCascadedTables(cascaded)
+	return nil
+}
@@ -0,0 +1,93 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+func createCascadeTestGraph() *graph.Graph {
+	// users -> orders -> order_items, users -> audit, orders -> audit
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", nil)
+	g.AddNode("order_items", nil)
+	g.AddNode("audit", nil)
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("orders", "order_items", "order_id", "id", "1-N")
+	g.AddEdgeWithMeta("users", "audit", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("orders", "audit", "order_id", "id", "1-N")
+	return g
+}
+
+func cascadeFK(table, column, refTable, onDelete string) ForeignKeyResult {
+	return ForeignKeyResult{
+		TableSchema: "testdb", Table: table, Column: column,
+		ReferencedTableSchema: "testdb", ReferencedTable: refTable, ReferencedColumn: "id",
+		OnDelete: onDelete,
+	}
+}
+
+func TestCascadeDeletedTables_SingleParent(t *testing.T) {
+	fks := []ForeignKeyResult{
+		cascadeFK("orders", "user_id", "users", "RESTRICT"),
+		cascadeFK("order_items", "order_id", "orders", "CASCADE"),
+	}
+
+	got := cascadeDeletedTables(createCascadeTestGraph(), fks, "testdb")
+	if len(got) != 1 || got["order_items"] != "orders" {
+		t.Errorf("expected {order_items: orders}, got %v", got)
+	}
+}
+
+func TestCascadeDeletedTables_RequiresEveryParent(t *testing.T) {
+	// audit cascades from users but not from orders: the orders DELETE would be
+	// rejected if audit rows were left behind, so audit must be deleted explicitly.
+	fks := []ForeignKeyResult{
+		cascadeFK("audit", "user_id", "users", "CASCADE"),
+		cascadeFK("audit", "order_id", "orders", "NO ACTION"),
+	}
+	if got := cascadeDeletedTables(createCascadeTestGraph(), fks, "testdb"); len(got) != 0 {
+		t.Errorf("expected no cascaded tables, got %v", got)
+	}
+
+	fks[1].OnDelete = "CASCADE"
+	if got := cascadeDeletedTables(createCascadeTestGraph(), fks, "testdb"); got["audit"] == "" {
+		t.Errorf("expected audit to be cascaded, got %v", got)
+	}
+}
+
+func TestCascadeDeletedTables_IgnoresMismatchedColumnsAndSchemas(t *testing.T) {
+	fks := []ForeignKeyResult{
+		// CASCADE on a column the relation does not use
+		cascadeFK("order_items", "legacy_order_id", "orders", "CASCADE"),
+		// CASCADE from another schema
+		{TableSchema: "other", Table: "orders", Column: "user_id",
+			ReferencedTableSchema: "other", ReferencedTable: "users", ReferencedColumn: "id", OnDelete: "CASCADE"},
+	}
+	if got := cascadeDeletedTables(createCascadeTestGraph(), fks, "testdb"); len(got) != 0 {
+		t.Errorf("expected no cascaded tables, got %v", got)
+	}
+}
+
+func TestApplyCascadeSkips_Disabled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := createCascadeTestGraph()
+	dp, _ := NewDeletePhase(db, g, 500, logger.NewDefault())
+
+	// No information_schema queries when the option is off.
+	if err := applyCascadeSkips(context.Background(), db, "testdb", g, config.SafetyConfig{}, logger.NewDefault(), dp); err != nil {
+		t.Fatalf("applyCascadeSkips failed: %v", err)
+	}
+	if dp.cascaded != nil {
+		t.Errorf("expected no cascaded tables, got %v", dp.cascaded)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ValidateCharsetCompatibility (CHARSET_CHECK) compares, column by column
+// (matched by name), the character sets, collations and ENUM/SET value lists
+// of each source table with its destination table, so values the destination
+// cannot store are caught before the copy rather than failing mid-copy or
+// being silently transliterated:
+//
+//   - a character set difference fails the check under count verification
+//     (or skip_verification), which cannot detect altered text; under sha256 or
+//     server_checksum it is only a warning, as verification fails before
+//     delete if text changed.
+//   - an ENUM/SET value of the source column that the destination column does
+//     not define always fails: strict mode rejects the row mid-copy and
+//     INSERT IGNORE stores an empty string instead.
+//   - a collation difference, and a destination ENUM/SET that defines extra or
+//     reordered values, are warnings: the stored values are unchanged.
+func (p *PreflightChecker) ValidateCharsetCompatibility(ctx context.Context, tables []string) error {
+	if p.destinationDB == nil {
+		return fmt.Errorf("destination database not configured; call ConfigureDestination first")
+	}
+	p.logger.Debug("Checking destination charset compatibility...")
+
+	targets, err := p.destinationTargets(ctx, tables)
+	if err != nil {
+		return err
+	}
+	charsetFatal := p.charsetMismatchFatal()
+	var incompatible []string
+	for _, target := range targets {
+		if target.unmatched {
+			continue
+		}
+		table := target.label()
+		sourceColumns, err := p.getTableColumns(ctx, p.db, p.sourceDBName, target.source)
+		if err != nil {
+			return fmt.Errorf("failed to read source schema for %s: %w", target.source, err)
+		}
+		destColumns, err := p.getTableColumns(ctx, p.destinationDB, p.destinationDBName, target.dest)
+		if err != nil {
+			return fmt.Errorf("failed to read destination schema for %s: %w", target.dest, err)
+		}
+		destByName := make(map[string]ColumnDefinition, len(destColumns))
+		for _, d := range destColumns {
+			destByName[d.ColumnName] = d
+		}
+
+		for _, s := range sourceColumns {
+			d, ok := destByName[s.ColumnName]
+			if !ok {
+				continue // reported by DEST_SCHEMA_COMPATIBILITY_CHECK
+			}
+			if s.CharacterSet != d.CharacterSet {
+				if charsetFatal {
+					incompatible = append(incompatible, fmt.Sprintf("%s.%s(character set mismatch (source=%s, destination=%s): copying can silently transliterate or truncate text and count verification cannot detect it; align charsets or use sha256 verification)",
+						table, s.ColumnName, s.CharacterSet, d.CharacterSet))
+					continue
+				}
+				p.logger.Warnf("Table %s column %s: charset differs (source=%s destination=%s); sha256 verification will fail before delete if data is altered",
+					table, s.ColumnName, s.CharacterSet, d.CharacterSet)
+			} else if s.Collation != d.Collation {
+				p.logger.Warnf("Table %s column %s: collation differs (source=%s destination=%s); stored bytes are identical but comparisons/sorting may differ in the archive",
+					table, s.ColumnName, s.Collation, d.Collation)
+			}
+
+			if reason := p.enumIncompatibility(table, s, d); reason != "" {
+				incompatible = append(incompatible, fmt.Sprintf("%s.%s(%s)", table, s.ColumnName, reason))
+			}
+		}
+	}
+
+	if len(incompatible) > 0 {
+		return &PreflightError{
+			Check:   "CHARSET_CHECK",
+			Message: "Destination columns cannot store every source value",
+			Tables:  incompatible,
+		}
+	}
+
+	p.logger.Debug("Destination charset compatibility check PASSED")
+	return nil
+}
+
+// enumIncompatibility reports source ENUM/SET values the destination column
+// lacks, or "" when it can store them all. Other value-list differences are
+// logged as warnings.
+func (p *PreflightChecker) enumIncompatibility(table string, s, d ColumnDefinition) string {
+	sKind, sValues, sOK := parseEnumType(s.ColumnType)
+	dKind, dValues, dOK := parseEnumType(d.ColumnType)
+	if !sOK || !dOK || sKind != dKind {
+		return "" // not both ENUM or both SET: a type mismatch
+	}
+
+	defined := make(map[string]bool, len(dValues))
+	for _, v := range dValues {
+		defined[v] = true
+	}
+	var missing []string
+	for _, v := range sValues {
+		if !defined[v] {
+			missing = append(missing, fmt.Sprintf("'%s'", v))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("destination %s lacks source values %s", strings.ToUpper(dKind), strings.Join(missing, ", "))
+	}
+	if strings.Join(sValues, ",") != strings.Join(dValues, ",") {
+		p.logger.Warnf("Table %s column %s: %s values differ (source=%s destination=%s); every source value is defined on the destination",
+			table, s.ColumnName, strings.ToUpper(dKind), s.ColumnType, d.ColumnType)
+	}
+	return ""
+}
+
+// sameEnumKind reports whether both column types are ENUM or both are SET.
+func sameEnumKind(a, b string) bool {
+	aKind, _, aOK := parseEnumType(a)
+	bKind, _, bOK := parseEnumType(b)
+	return aOK && bOK && aKind == bKind
+}
+
+// parseEnumType splits an information_schema COLUMN_TYPE such as
+// enum('new','paid') into its kind ("enum" or "set") and its values, undoing
+// the doubled single quotes MySQL escapes quotes with. ok is false for any
+// other type.
+func parseEnumType(columnType string) (kind string, values []string, ok bool) {
+	t := strings.TrimSpace(columnType)
+	lower := strings.ToLower(t)
+	switch {
+	case strings.HasPrefix(lower, "enum(") && strings.HasSuffix(t, ")"):
+		kind, t = "enum", t[len("enum("):len(t)-1]
+	case strings.HasPrefix(lower, "set(") && strings.HasSuffix(t, ")"):
+		kind, t = "set", t[len("set("):len(t)-1]
+	default:
+		return "", nil, false
+	}
+
+	var cur strings.Builder
+	inQuote := false
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case c == '\'' && inQuote && i+1 < len(t) && t[i+1] == '\'':
+			cur.WriteByte('\'')
+			i++
+		case c == '\'':
+			inQuote = !inQuote
+			if !inQuote {
+				values = append(values, cur.String())
+				cur.Reset()
+			}
+		case inQuote:
+			cur.WriteByte(c)
+		}
+	}
+	return kind, values, true
+}
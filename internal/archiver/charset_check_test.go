@@ -0,0 +1,172 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+func runCharsetCheck(t *testing.T, verification config.VerificationConfig, sourceCols, destCols [][]driverValue) error {
+	t.Helper()
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	checker, _ := NewPreflightChecker(sourceDB, "sourcedb", createPreflightTestGraph(), logger.NewDefault())
+	_ = checker.ConfigureDestination(destDB, "destdb", "destdb")
+	checker.SetVerification(verification)
+
+	columns := []string{"ORDINAL_POSITION", "COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE",
+		"COLUMN_KEY", "EXTRA", "CHARACTER_SET_NAME", "COLLATION_NAME"}
+	sourceRows := sqlmock.NewRows(columns)
+	for _, row := range sourceCols {
+		sourceRows.AddRow(row...)
+	}
+	sourceMock.ExpectQuery("SELECT\\s+ORDINAL_POSITION,").
+		WithArgs("sourcedb", "users").
+		WillReturnRows(sourceRows)
+	destRows := sqlmock.NewRows(columns)
+	for _, row := range destCols {
+		destRows.AddRow(row...)
+	}
+	destMock.ExpectQuery("SELECT\\s+ORDINAL_POSITION,").
+		WithArgs("destdb", "users").
+		WillReturnRows(destRows)
+
+	err := checker.ValidateCharsetCompatibility(context.Background(), []string{"users"})
+	if mockErr := sourceMock.ExpectationsWereMet(); mockErr != nil {
+		t.Errorf("source expectations: %v", mockErr)
+	}
+	if mockErr := destMock.ExpectationsWereMet(); mockErr != nil {
+		t.Errorf("destination expectations: %v", mockErr)
+	}
+	return err
+}
+
+func TestValidateCharsetCompatibility(t *testing.T) {
+	count := config.VerificationConfig{Method: "count"}
+	sha256 := config.VerificationConfig{Method: "sha256"}
+
+	tests := []struct {
+		name         string
+		verification config.VerificationConfig
+		sourceCols   [][]driverValue
+		destCols     [][]driverValue
+		wantErr      string
+	}{
+		{
+			name:         "utf8mb4 to latin1 is rejected under count verification",
+			verification: count,
+			sourceCols: [][]driverValue{
+				{1, "id", "bigint", "NO", "PRI", "", "", ""},
+				{2, "name", "varchar(255)", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			destCols: [][]driverValue{
+				{1, "id", "bigint", "NO", "PRI", "", "", ""},
+				{2, "name", "varchar(255)", "YES", "", "", "latin1", "latin1_swedish_ci"},
+			},
+			wantErr: "users.name(character set mismatch (source=utf8mb4, destination=latin1)",
+		},
+		{
+			name:         "utf8mb4 to latin1 only warns under sha256 verification",
+			verification: sha256,
+			sourceCols: [][]driverValue{
+				{1, "name", "varchar(255)", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			destCols: [][]driverValue{
+				{1, "name", "varchar(255)", "YES", "", "", "latin1", "latin1_swedish_ci"},
+			},
+		},
+		{
+			name:         "collation-only difference warns",
+			verification: count,
+			sourceCols: [][]driverValue{
+				{1, "name", "varchar(255)", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			destCols: [][]driverValue{
+				{1, "name", "varchar(255)", "YES", "", "", "utf8mb4", "utf8mb4_general_ci"},
+			},
+		},
+		{
+			name:         "destination enum missing a source value is rejected",
+			verification: sha256,
+			sourceCols: [][]driverValue{
+				{1, "status", "enum('new','paid','refunded')", "NO", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			destCols: [][]driverValue{
+				{1, "status", "enum('new','paid')", "NO", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			wantErr: "users.status(destination ENUM lacks source values 'refunded')",
+		},
+		{
+			name:         "destination set defining extra values is allowed",
+			verification: count,
+			sourceCols: [][]driverValue{
+				{1, "flags", "set('a','b')", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			destCols: [][]driverValue{
+				{1, "flags", "set('a','b','c')", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+		},
+		{
+			name:         "columns are matched by name",
+			verification: count,
+			sourceCols: [][]driverValue{
+				{1, "id", "bigint", "NO", "PRI", "", "", ""},
+				{2, "note", "text", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+			},
+			destCols: [][]driverValue{
+				{1, "note", "text", "YES", "", "", "utf8mb4", "utf8mb4_0900_ai_ci"},
+				{2, "id", "bigint", "NO", "PRI", "", "", ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCharsetCheck(t, tt.verification, tt.sourceCols, tt.destCols)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected charset check to pass, got: %v", err)
+				}
+				return
+			}
+			var pfErr *PreflightError
+			if !errors.As(err, &pfErr) || pfErr.Check != "CHARSET_CHECK" {
+				t.Fatalf("expected CHARSET_CHECK error, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %q in error, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestColumnIncompatibility_LeavesEnumValuesToCharsetCheck(t *testing.T) {
+	s := ColumnDefinition{ColumnName: "status", ColumnType: "enum('new','paid')", IsNullable: "NO"}
+	d := ColumnDefinition{ColumnName: "status", ColumnType: "enum('new')", IsNullable: "NO"}
+	if reason := columnIncompatibility(s, d); reason != "" {
+		t.Errorf("enum value differences belong to CHARSET_CHECK, got %q", reason)
+	}
+	d.ColumnType = "set('new','paid')"
+	if reason := columnIncompatibility(s, d); reason != "column type mismatch" {
+		t.Errorf("enum to set must stay a type mismatch, got %q", reason)
+	}
+}
+
+func TestParseEnumType(t *testing.T) {
+	kind, values, ok := parseEnumType("enum('a','it''s','x,y')")
+	if !ok || kind != "enum" || !reflect.DeepEqual(values, []string{"a", "it's", "x,y"}) {
+		t.Errorf("parseEnumType = %q %q %v", kind, values, ok)
+	}
+	if _, _, ok := parseEnumType("varchar(10)"); ok {
+		t.Error("varchar must not parse as an enum")
+	}
+}
@@ -25,6 +25,16 @@ type CopyStats struct {
 	Duration      time.Duration // Time taken for copy operation
 	TablesSkipped int           // Tables with no rows to copy
 	RowsPerTable  map[string]int64
+	// RowsSkipped counts rows INSERT IGNORE skipped because the destination
+	// already held them (attempted minus RowsAffected), plus rows an
+	// ExistingFilter removed before the copy; SkippedPerTable breaks it down
+	// by table and only lists tables with skips.
+	RowsSkipped     int64
+	SkippedPerTable map[string]int64
+	// FailedTables maps each table whose copy failed and was rolled back to
+	// its error. Only populated with SetContinueOnError(true); descendants of
+	// a failed table are not copied and not listed.
+	FailedTables map[string]error
 }
 
 // CopyPhase manages the transactional copy of discovered records from source to destination.
@@ -44,7 +54,19 @@ type CopyPhase struct {
 	safetyCfg    config.SafetyConfig
 	logger       *logger.Logger
 	strictInsert bool
-	batchSize    int // fetch+insert chunk size; 0 => defaultCopyBatchSize
+	upsert       bool              // INSERT ... ON DUPLICATE KEY UPDATE (processing.copy_mode: upsert)
+	batchSize    int               // fetch+insert chunk size; 0 => defaultCopyBatchSize
+	selectLists  map[string]string // table -> resolved SELECT column list for filtered tables
+	transforms   *Transforms       // column transforms applied before INSERT; nil => none
+	// continueOnError isolates a failing table behind a savepoint instead of
+	// aborting the whole copy (processing.continue_on_error).
+	continueOnError bool
+	existing        *ExistingFilter   // drops PKs already on the destination before copying; nil => off
+	rateLimiter     *RowRateLimiter   // rows/second budget drawn before each chunk; nil => unlimited
+	runDate         time.Time         // dates destination_table templates without a date column
+	onTable         func(string)      // called as each table starts copying; nil => none
+	writer          DestinationWriter // replaces the INSERT statements (processing.copy_writer); nil => INSERT
+	stmtTimeout     time.Duration     // per-statement limit (processing.statement_timeout); 0 => none
 }
 
 const defaultCopyBatchSize = 200
@@ -96,6 +118,7 @@ func NewCopyPhase(
 		graph:     g,
 		safetyCfg: safetyCfg,
 		logger:    log,
+		runDate:   time.Now(),
 	}, nil
 }
 
@@ -104,6 +127,67 @@ func (cp *CopyPhase) SetStrictInsert(strict bool) {
 	cp.strictInsert = strict
 }
 
+// SetUpsert switches copy to INSERT ... ON DUPLICATE KEY UPDATE, overwriting
+// any destination row that already holds a copied PK so partially-copied
+// batches can be re-copied. Takes precedence over SetStrictInsert.
+func (cp *CopyPhase) SetUpsert(upsert bool) {
+	cp.upsert = upsert
+}
+
+// SetTransforms sets the column transforms applied to each fetched row before
+// it is inserted into the destination. nil disables transforms.
+func (cp *CopyPhase) SetTransforms(t *Transforms) {
+	cp.transforms = t
+}
+
+// SetContinueOnError makes a per-table copy failure non-fatal: each table is
+// copied behind a savepoint, a failing table is rolled back to it and recorded
+// in CopyStats.FailedTables, its descendants are not copied, and the remaining
+// tables are still committed. Context cancellation still aborts the copy.
+func (cp *CopyPhase) SetContinueOnError(enabled bool) {
+	cp.continueOnError = enabled
+}
+
+// SetExistingFilter makes Copy look up which PKs the destination already holds
+// and copy only the rest (processing.skip_existing). nil disables the lookup.
+func (cp *CopyPhase) SetExistingFilter(f *ExistingFilter) {
+	cp.existing = f
+}
+
+// SetRateLimiter makes each fetch+insert chunk wait for its rows on l
+// (processing.max_rows_per_second). nil disables the limit.
+func (cp *CopyPhase) SetRateLimiter(l *RowRateLimiter) {
+	cp.rateLimiter = l
+}
+
+// SetTableObserver registers fn, called with each table's name as the copy
+// starts on it (orchestrator progress snapshots). nil removes it.
+func (cp *CopyPhase) SetTableObserver(fn func(table string)) {
+	cp.onTable = fn
+}
+
+// SetRunDate sets the job run date that fills the {year} and {month}
+// placeholders of destination_table templates without a
+// destination_date_column. Defaults to the time NewCopyPhase was called;
+// callers pass the same date to the verifier.
+func (cp *CopyPhase) SetRunDate(t time.Time) {
+	cp.runDate = t
+}
+
+// SetWriter makes the copy phase write rows through w instead of INSERT
+// statements (processing.copy_writer). nil restores the INSERT path.
+func (cp *CopyPhase) SetWriter(w DestinationWriter) {
+	cp.writer = w
+}
+
+// SetStatementTimeout bounds each statement of the copy: source SELECTs get
+// a MySQL MAX_EXECUTION_TIME hint and destination writes run under a
+// deadline of this length, failing with a *StatementTimeoutError
+// (processing.statement_timeout). 0 (the default) sets no limit.
+func (cp *CopyPhase) SetStatementTimeout(timeout time.Duration) {
+	cp.stmtTimeout = timeout
+}
+
 // StrictInsert reports whether the copy phase uses plain (strict) INSERT rather
 // than INSERT IGNORE. Strict mode aborts on any duplicate, which means a pending
 // batch whose destination copy already committed cannot be safely re-copied on
@@ -145,7 +229,8 @@ func (cp *CopyPhase) Copy(ctx context.Context, recordSet *RecordSet) (*CopyStats
 	startTime := time.Now()
 
 	stats := &CopyStats{
-		RowsPerTable: make(map[string]int64),
+		RowsPerTable:    make(map[string]int64),
+		SkippedPerTable: make(map[string]int64),
 	}
 
 	// Loud warning when FK checks are disabled. This is an advanced option that
@@ -157,6 +242,18 @@ func (cp *CopyPhase) Copy(ctx context.Context, recordSet *RecordSet) (*CopyStats
 			"Use only when you have verified the copy order and accept the risk.")
 	}
 
+	if cp.existing != nil {
+		filtered, existingStats, err := cp.existing.Filter(ctx, recordSet)
+		if err != nil {
+			return nil, err
+		}
+		recordSet = filtered
+		stats.RowsSkipped += existingStats.RowsExisting
+		for table, n := range existingStats.ExistingPerTable {
+			stats.SkippedPerTable[table] += n
+		}
+	}
+
 	// Checkout a dedicated connection so any session state (FOREIGN_KEY_CHECKS)
 	// is contained to this conn and cannot leak back to the pool.
 	conn, err := cp.destDB.Conn(ctx)
@@ -204,14 +301,16 @@ func (cp *CopyPhase) Copy(ctx context.Context, recordSet *RecordSet) (*CopyStats
 	}
 
 	// Get copy order from dependency graph (parent tables first)
-	copyOrder, err := cp.graph.CopyOrder()
+	copyOrder, err := cp.graph.CopyOrderContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get copy order: %w", err)
 	}
 
 	cp.logger.Infof("Starting copy phase for %d tables in dependency order", len(copyOrder))
 
-	// Copy tables in order: root table first, then children
+	// Copy tables in order: root table first, then children. blocked holds
+	// descendants of tables that failed under continueOnError.
+	blocked := make(map[string]bool)
 	for _, table := range copyOrder {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
@@ -226,8 +325,37 @@ func (cp *CopyPhase) Copy(ctx context.Context, recordSet *RecordSet) (*CopyStats
 			continue
 		}
 
+		if blocked[table] {
+			cp.logger.Warnw("Skipping table: an ancestor failed to copy", logger.FieldTable, table)
+			stats.TablesSkipped++
+			continue
+		}
+
+		if cp.onTable != nil {
+			cp.onTable(table)
+		}
+
 		// GA-P3-F3-T3 and GA-P3-F3-T4: Copy table (root or child)
-		rowsCopied, err := cp.copyTable(ctx, tx, table, pks)
+		tableStart := time.Now()
+		var rowsCopied, rowsSkipped int64
+		if cp.continueOnError {
+			rowsCopied, rowsSkipped, err = cp.copyTableIsolated(ctx, tx, table, pks)
+		} else {
+			rowsCopied, rowsSkipped, err = cp.copyTable(ctx, tx, table, pks)
+		}
+		var tableErr *tableCopyError
+		if errors.As(err, &tableErr) {
+			cp.logger.Errorw("Table copy failed, continuing with independent tables",
+				logger.FieldTable, table, "error", tableErr.err)
+			if stats.FailedTables == nil {
+				stats.FailedTables = make(map[string]error)
+			}
+			stats.FailedTables[table] = tableErr.err
+			for _, d := range cp.graph.DescendantsOf(table) {
+				blocked[d] = true
+			}
+			continue
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to copy table %s: %w", table, err)
 		}
@@ -236,7 +364,16 @@ func (cp *CopyPhase) Copy(ctx context.Context, recordSet *RecordSet) (*CopyStats
 		stats.RowsCopied += rowsCopied
 		stats.RowsPerTable[table] = rowsCopied
 
-		cp.logger.Debugf("Copied %d rows from table %q", rowsCopied, table)
+		cp.logger.Debugw("Copied rows from table",
+			logger.FieldTable, table,
+			logger.FieldRows, rowsCopied,
+			logger.FieldDuration, time.Since(tableStart).Milliseconds(),
+		)
+		if rowsSkipped > 0 {
+			stats.RowsSkipped += rowsSkipped
+			stats.SkippedPerTable[table] += rowsSkipped
+			cp.logger.Infof("Table %q: %d rows already present, skipped", table, rowsSkipped)
+		}
 	}
 
 	// Re-enable FK checks before commit so the reset is part of the same
@@ -261,66 +398,106 @@ func (cp *CopyPhase) Copy(ctx context.Context, recordSet *RecordSet) (*CopyStats
 	// GA-P3-F3-T8: Populate final statistics
 	stats.Duration = time.Since(startTime)
 
-	cp.logger.Infof("Copy phase complete: %d tables, %d rows, duration: %s",
-		stats.TablesCopied,
-		stats.RowsCopied,
-		stats.Duration,
+	cp.logger.Infow("Copy phase complete",
+		"tables", stats.TablesCopied,
+		logger.FieldRows, stats.RowsCopied,
+		"skipped", stats.RowsSkipped,
+		logger.FieldDuration, stats.Duration.Milliseconds(),
 	)
 
 	return stats, nil
 }
 
+// copySavepoint is the savepoint each table is copied behind when
+// continueOnError is set.
+const copySavepoint = "goarchive_copy_table"
+
+// tableCopyError marks a copy failure confined to one table and already
+// rolled back to its savepoint, so Copy can record it and carry on.
+type tableCopyError struct {
+	err error
+}
+
+func (e *tableCopyError) Error() string { return e.err.Error() }
+
+// copyTableIsolated runs copyTable behind a savepoint. A table failure is
+// rolled back and returned as *tableCopyError; cancellation and savepoint
+// errors are returned as-is and abort the copy.
+func (cp *CopyPhase) copyTableIsolated(ctx context.Context, tx *sql.Tx, table string, pks []interface{}) (int64, int64, error) {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+copySavepoint); err != nil {
+		return 0, 0, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	copied, skipped, err := cp.copyTable(ctx, tx, table, pks)
+	if err == nil {
+		return copied, skipped, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return 0, 0, err
+	}
+	if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+copySavepoint); rbErr != nil {
+		return 0, 0, fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rbErr)
+	}
+	return 0, 0, &tableCopyError{err: err}
+}
+
 // copyTable copies all specified records for one table, in batchSize-sized
 // chunks. Each chunk is one SELECT (fetch) followed by one INSERT, all inside
 // the caller's single destination transaction tx.
 //
 // GA-P3-F3-T5: Uses INSERT IGNORE for idempotent inserts (unless strictInsert)
-func (cp *CopyPhase) copyTable(ctx context.Context, tx *sql.Tx, table string, pks []interface{}) (int64, error) {
+//
+// Returns the rows written and the rows INSERT IGNORE skipped as already present.
+func (cp *CopyPhase) copyTable(ctx context.Context, tx *sql.Tx, table string, pks []interface{}) (int64, int64, error) {
 	if len(pks) == 0 {
-		return 0, nil
+		return 0, 0, nil
 	}
+	// A table reachable through several parents must not be fetched twice
+	// across chunks: the second INSERT would count the rows as skipped, or
+	// fail as duplicates in strict mode.
+	pks = uniquePKs(pks)
 
-	chunk := cp.effectiveBatchSize()
-	var rowsCopied int64
+	chunk := cp.graph.BatchSizeFor(table, cp.effectiveBatchSize())
+	var rowsCopied, rowsSkipped int64
 
 	for start := 0; start < len(pks); start += chunk {
 		if err := ctx.Err(); err != nil {
-			return rowsCopied, fmt.Errorf("copy interrupted: %w", err)
+			return rowsCopied, rowsSkipped, fmt.Errorf("copy interrupted: %w", err)
 		}
 		end := start + chunk
 		if end > len(pks) {
 			end = len(pks)
 		}
-		copied, err := cp.copyChunk(ctx, tx, table, pks[start:end])
+		if err := cp.rateLimiter.Wait(ctx, end-start); err != nil {
+			return rowsCopied, rowsSkipped, fmt.Errorf("copy interrupted during rate limit wait: %w", err)
+		}
+		copied, skipped, err := cp.copyChunk(ctx, tx, table, pks[start:end])
 		if err != nil {
-			return rowsCopied, err
+			return rowsCopied, rowsSkipped, err
 		}
 		rowsCopied += copied
+		rowsSkipped += skipped
 	}
-	return rowsCopied, nil
+	return rowsCopied, rowsSkipped, nil
 }
 
 // copyChunk fetches one chunk of rows from source and inserts them into dest
 // within tx. Rows are inserted via one or more INSERTs — split into
 // sub-batches of at most maxRowsPerInsert(len(columns)) rows so no single
-// statement exceeds MySQL's 65,535-placeholder limit.
-func (cp *CopyPhase) copyChunk(ctx context.Context, tx *sql.Tx, table string, pks []interface{}) (int64, error) {
+// statement exceeds MySQL's 65,535-placeholder limit. Returns the rows written
+// and the rows skipped as already present.
+func (cp *CopyPhase) copyChunk(ctx context.Context, tx *sql.Tx, table string, pks []interface{}) (int64, int64, error) {
 	pkColumn := cp.graph.GetPK(table)
 
-	placeholders := make([]string, len(pks))
-	for i := range placeholders {
-		placeholders[i] = "?"
+	selectList, err := cp.selectList(ctx, table)
+	if err != nil {
+		return 0, 0, err
 	}
-	selectQuery := fmt.Sprintf(
-		"SELECT * FROM %s WHERE %s IN (%s)",
-		sqlutil.QuoteIdentifier(table),
-		sqlutil.QuoteIdentifier(pkColumn),
-		strings.Join(placeholders, ", "),
-	)
 
-	rows, err := cp.sourceDB.QueryContext(ctx, selectQuery, pks...)
+	selectQuery := selectByPKQuery(selectList, table, pkColumn, sqlutil.Placeholders(len(pks), ", "))
+
+	rows, err := cp.sourceDB.QueryContext(ctx, sqlutil.WithMaxExecutionTime(selectQuery, cp.stmtTimeout), pks...)
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch rows from source for %s: %w", table, err)
+		return 0, 0, fmt.Errorf("failed to fetch rows from source for %s: %w", table, err)
 	}
 	defer func() {
 		if cerr := rows.Close(); cerr != nil {
@@ -330,11 +507,19 @@ func (cp *CopyPhase) copyChunk(ctx context.Context, tx *sql.Tx, table string, pk
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get columns for %s: %w", table, err)
+		return 0, 0, fmt.Errorf("failed to get columns for %s: %w", table, err)
+	}
+
+	transforms := cp.transforms.forColumns(table, columns)
+	route, err := cp.destinationRoute(table, columns)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	batchValues := make([]interface{}, 0, len(columns)*len(pks))
-	rowsInBatch := 0
+	// Rows are grouped by destination table, in first-seen order; without a
+	// per-row destination_date_column there is a single group.
+	var destinations []string
+	batches := make(map[string][]interface{})
 	for rows.Next() {
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
@@ -342,37 +527,180 @@ func (cp *CopyPhase) copyChunk(ctx context.Context, tx *sql.Tx, table string, pk
 			valuePtrs[i] = &values[i]
 		}
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return 0, fmt.Errorf("failed to scan row for %s: %w", table, err)
+			return 0, 0, fmt.Errorf("failed to scan row for %s: %w", table, err)
 		}
-		batchValues = append(batchValues, values...)
-		rowsInBatch++
+		// Route on the source value, before any transform rewrites it.
+		dest, err := route(values)
+		if err != nil {
+			return 0, 0, err
+		}
+		for i, fn := range transforms {
+			if fn != nil {
+				values[i] = fn(values[i])
+			}
+		}
+		if _, ok := batches[dest]; !ok {
+			destinations = append(destinations, dest)
+		}
+		batches[dest] = append(batches[dest], values...)
 	}
 	if err := rows.Err(); err != nil {
-		return 0, fmt.Errorf("error iterating rows for %s: %w", table, err)
+		return 0, 0, fmt.Errorf("error iterating rows for %s: %w", table, err)
+	}
+
+	var rowsCopied, rowsSkipped int64
+	for _, dest := range destinations {
+		copied, skipped, err := cp.insertRows(ctx, tx, table, dest, columns, batches[dest])
+		rowsCopied += copied
+		rowsSkipped += skipped
+		if err != nil {
+			return rowsCopied, rowsSkipped, err
+		}
+	}
+	return rowsCopied, rowsSkipped, nil
+}
+
+// destinationRoute returns the function naming the destination table for a
+// fetched row of table (values in columns order): table itself, its
+// destination_table template expanded for the run date, or expanded per row
+// from its destination_date_column.
+func (cp *CopyPhase) destinationRoute(table string, columns []string) (func(values []interface{}) (string, error), error) {
+	dateColumn := cp.graph.DestinationDateColumn(table)
+	if dateColumn == "" {
+		dest := cp.graph.DestinationTable(table, cp.runDate)
+		return func([]interface{}) (string, error) { return dest, nil }, nil
+	}
+	for i, col := range columns {
+		if strings.EqualFold(col, dateColumn) {
+			return func(values []interface{}) (string, error) {
+				return cp.graph.DestinationTableForValue(table, values[i])
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("destination date column %s is not among the columns copied from %s", dateColumn, table)
+}
+
+// insertRows inserts the flattened rows of table (len(values) is a multiple
+// of len(columns)) into destination table dest within tx. Returns the rows
+// written and the rows skipped as already present.
+func (cp *CopyPhase) insertRows(ctx context.Context, tx *sql.Tx, table, dest string, columns []string, values []interface{}) (int64, int64, error) {
+	if len(columns) == 0 {
+		return 0, 0, nil
 	}
-	if rowsInBatch == 0 {
-		return 0, nil
+	rowCount := len(values) / len(columns)
+	if cp.writer != nil {
+		return cp.writeRows(ctx, tx, dest, columns, rowCount, values)
 	}
 
 	// MySQL hard-limits a single prepared statement to 65,535 placeholders.
-	// A chunk whose columns*rowsInBatch would exceed that is split into
+	// A chunk whose columns*rowCount would exceed that is split into
 	// multiple INSERTs, each within the clamp, so wide tables never abort a
 	// run that could otherwise succeed with smaller INSERTs.
 	maxRows := maxRowsPerInsert(len(columns))
-	var rowsCopied int64
-	for off := 0; off < rowsInBatch; off += maxRows {
-		n := rowsInBatch - off
+	var rowsCopied, rowsSkipped int64
+	for off := 0; off < rowCount; off += maxRows {
+		n := rowCount - off
 		if n > maxRows {
 			n = maxRows
 		}
-		vals := batchValues[off*len(columns) : (off+n)*len(columns)]
-		affected, err := cp.execInsertBatch(ctx, tx, table, columns, n, vals)
+		vals := values[off*len(columns) : (off+n)*len(columns)]
+		var affected int64
+		err := withStatementTimeout(ctx, cp.stmtTimeout, func(ctx context.Context) (err error) {
+			affected, err = cp.execInsertBatch(ctx, tx, table, dest, columns, n, vals)
+			return err
+		})
 		if err != nil {
-			return rowsCopied, err
+			return rowsCopied, rowsSkipped, err
 		}
 		rowsCopied += affected
+		// Only INSERT IGNORE affects fewer rows than it was given: the
+		// difference already existed on the destination.
+		if affected < int64(n) {
+			rowsSkipped += int64(n) - affected
+		}
 	}
-	return rowsCopied, nil
+	return rowsCopied, rowsSkipped, nil
+}
+
+// writeRows writes rows through cp.writer. Writers skip duplicate keys, so
+// in strict mode a shortfall is reported as *ErrDestinationDuplicate, naming
+// the key from the statement's first duplicate-entry warning.
+func (cp *CopyPhase) writeRows(ctx context.Context, tx *sql.Tx, dest string, columns []string, rowCount int, values []interface{}) (int64, int64, error) {
+	var affected int64
+	err := withStatementTimeout(ctx, cp.stmtTimeout, func(ctx context.Context) (err error) {
+		affected, err = cp.writer.WriteRows(ctx, tx, dest, columns, rowCount, values)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if affected >= int64(rowCount) {
+		return affected, 0, nil
+	}
+	if cp.strictInsert {
+		return affected, 0, duplicateFromWarnings(ctx, tx, dest)
+	}
+	return affected, int64(rowCount) - affected, nil
+}
+
+// duplicateFromWarnings builds the *ErrDestinationDuplicate for a statement
+// that skipped duplicate keys into dest from its SHOW WARNINGS.
+func duplicateFromWarnings(ctx context.Context, tx *sql.Tx, dest string) error {
+	dupErr := &ErrDestinationDuplicate{Table: dest, ConflictingPK: "unknown", RawMySQLError: "duplicate entry skipped"}
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return dupErr
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			break
+		}
+		if code == mysqlErrDuplicateEntry {
+			dupErr.ConflictingPK = extractDuplicatePK(message)
+			dupErr.RawMySQLError = message
+			break
+		}
+	}
+	return dupErr
+}
+
+// selectByPKQuery renders the copy phase's source read for one chunk of PKs;
+// placeholders is the IN list (the planner passes a symbolic one).
+func selectByPKQuery(selectList, table, pkColumn, placeholders string) string {
+	return fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s IN (%s)",
+		selectList,
+		sqlutil.QuoteIdentifier(table),
+		sqlutil.QuoteIdentifier(pkColumn),
+		placeholders,
+	)
+}
+
+// selectList returns the SELECT column list for table: "*" unless the table
+// has a column filter. Exclusion filters need the source column list, which is
+// read once per table and cached. The INSERT column list is taken from the
+// fetched rows, so excluded columns never reach the destination.
+func (cp *CopyPhase) selectList(ctx context.Context, table string) (string, error) {
+	filter := cp.graph.GetColumnFilter(table)
+	if filter == nil {
+		return "*", nil
+	}
+	if list, ok := cp.selectLists[table]; ok {
+		return list, nil
+	}
+
+	list, err := filter.SelectList(ctx, cp.sourceDB, table)
+	if err != nil {
+		return "", err
+	}
+	if cp.selectLists == nil {
+		cp.selectLists = make(map[string]string)
+	}
+	cp.selectLists[table] = list
+	return list, nil
 }
 
 // maxRowsPerInsert returns the largest number of rows whose combined
@@ -390,33 +718,47 @@ func maxRowsPerInsert(columnCount int) int {
 	return n
 }
 
-// execInsertBatch inserts rowCount rows (values already flattened in
-// row-major order, len == rowCount*len(columns)) into table within tx, using
-// INSERT IGNORE or strict INSERT per cp.strictInsert, and maps a strict-mode
-// duplicate to *ErrDestinationDuplicate. Returns RowsAffected.
-func (cp *CopyPhase) execInsertBatch(ctx context.Context, tx *sql.Tx, table string, columns []string, rowCount int, values []interface{}) (int64, error) {
-	insertQuery := cp.buildInsertIgnoreBatchQuery(table, columns, rowCount)
-	if cp.strictInsert {
-		insertQuery = cp.buildInsertBatchQuery(table, columns, rowCount)
-	}
-	result, err := tx.ExecContext(ctx, insertQuery, values...)
+// execInsertBatch inserts rowCount rows of table (values already flattened
+// in row-major order, len == rowCount*len(columns)) into destination table
+// dest within tx, using an upsert, INSERT IGNORE or strict INSERT per
+// cp.upsert/cp.strictInsert, and maps a strict-mode duplicate to
+// *ErrDestinationDuplicate. Returns RowsAffected, or rowCount for an upsert
+// (MySQL reports 2 per updated row).
+func (cp *CopyPhase) execInsertBatch(ctx context.Context, tx *sql.Tx, table, dest string, columns []string, rowCount int, values []interface{}) (int64, error) {
+	result, err := tx.ExecContext(ctx, cp.buildInsertQuery(table, dest, columns, rowCount), values...)
 	if err != nil {
 		if cp.strictInsert {
 			var mysqlErr *mysql.MySQLError
 			if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
 				return 0, &ErrDestinationDuplicate{
-					Table:         table,
+					Table:         dest,
 					ConflictingPK: extractDuplicatePK(mysqlErr.Message),
 					RawMySQLError: mysqlErr.Message,
 				}
 			}
 		}
-		return 0, fmt.Errorf("failed to insert batch into %s: %w", table, err)
+		return 0, fmt.Errorf("failed to insert batch into %s: %w", dest, err)
+	}
+	if cp.upsert {
+		return int64(rowCount), nil
 	}
 	affected, _ := result.RowsAffected()
 	return affected, nil
 }
 
+// buildInsertQuery builds the multi-row INSERT of rowCount rows of table
+// into dest for the copy mode: an upsert, a strict INSERT or INSERT IGNORE.
+func (cp *CopyPhase) buildInsertQuery(table, dest string, columns []string, rowCount int) string {
+	switch {
+	case cp.upsert:
+		return cp.buildUpsertBatchQuery(table, dest, columns, rowCount)
+	case cp.strictInsert:
+		return cp.buildInsertBatchQuery(dest, columns, rowCount)
+	default:
+		return cp.buildInsertIgnoreBatchQuery(dest, columns, rowCount)
+	}
+}
+
 func (cp *CopyPhase) buildInsertIgnoreBatchQuery(table string, columns []string, rowCount int) string {
 	// Column list: (`col1`, `col2`, `col3`)
 	quotedColumns := make([]string, len(columns))
@@ -450,6 +792,29 @@ func (cp *CopyPhase) buildInsertBatchQuery(table string, columns []string, rowCo
 	return strings.Replace(query, "INSERT IGNORE INTO", "INSERT INTO", 1)
 }
 
+// buildUpsertBatchQuery builds a multi-row INSERT ... ON DUPLICATE KEY UPDATE
+// into dest that overwrites every non-PK column (table's PK) of an existing
+// row with the new values.
+func (cp *CopyPhase) buildUpsertBatchQuery(table, dest string, columns []string, rowCount int) string {
+	pkColumn := cp.graph.GetPK(table)
+	var updates []string
+	for _, col := range columns {
+		if strings.EqualFold(col, pkColumn) {
+			continue
+		}
+		quoted := sqlutil.QuoteIdentifier(col)
+		updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", quoted, quoted))
+	}
+	if len(updates) == 0 {
+		// PK-only table: nothing to overwrite, but the clause still turns a
+		// duplicate into a no-op instead of an error.
+		quoted := sqlutil.QuoteIdentifier(pkColumn)
+		updates = append(updates, fmt.Sprintf("%s = %s", quoted, quoted))
+	}
+	return cp.buildInsertBatchQuery(dest, columns, rowCount) +
+		" ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+}
+
 func extractDuplicatePK(mysqlMsg string) string {
 	first := strings.IndexByte(mysqlMsg, '\'')
 	if first == -1 {
@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-sql-driver/mysql"
@@ -396,6 +397,37 @@ func TestCopyPhase_InsertError_Rollback(t *testing.T) {
 	assert.NoError(t, destMock.ExpectationsWereMet())
 }
 
+func TestCopyPhase_ForeignKeyChecksRestoredAfterFailedBatch(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	cp, _ := NewCopyPhase(sourceDB, destDB, createSimpleGraph(), config.SafetyConfig{DisableForeignKeyChecks: true}, logger.NewDefault())
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Alice"))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(sql.ErrTxDone)
+	destMock.ExpectRollback()
+	// SET is not rolled back: the connection is reset after the rollback,
+	// before it returns to the pool.
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := cp.Copy(context.Background(), &RecordSet{
+		RootPKs: []interface{}{int64(1)},
+		Records: map[string][]interface{}{"customers": {int64(1)}},
+	})
+
+	assert.Error(t, err)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
+
 func TestCopyPhase_ContextCancellation(t *testing.T) {
 	sourceDB, _, _ := sqlmock.New()
 	defer func() { _ = sourceDB.Close() }()
@@ -771,6 +803,82 @@ func TestCopyChunk_StrictDuplicateOnLaterSubBatch(t *testing.T) {
 
 // Helper functions
 
+func TestCopyPhase_ColumnFilter_Exclude(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createSimpleGraph()
+	g.SetColumnFilter("customers", &graph.ColumnFilter{Exclude: []string{"password_hash"}})
+	cp, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, logger.NewDefault())
+	cp.SetBatchSize(1)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{int64(1), int64(2)},
+		Records: map[string][]interface{}{
+			"customers": {int64(1), int64(2)},
+		},
+	}
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	// Column list is read once and reused for every chunk.
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` LIMIT 0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "password_hash"}))
+	for _, pk := range []int64{1, 2} {
+		sourceMock.ExpectQuery("SELECT `id`, `name` FROM `customers` WHERE `id` IN \\(\\?\\)").
+			WithArgs(pk).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(pk, "Alice"))
+		destMock.ExpectExec("INSERT IGNORE INTO `customers` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\)$").
+			WithArgs(pk, "Alice").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	destMock.ExpectCommit()
+
+	stats, err := cp.Copy(context.Background(), recordSet)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.RowsCopied)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestCopyPhase_ColumnFilter_Include(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createSimpleGraph()
+	g.SetColumnFilter("customers", &graph.ColumnFilter{Include: []string{"id", "email"}})
+	cp, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, logger.NewDefault())
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{int64(1)},
+		Records: map[string][]interface{}{
+			"customers": {int64(1)},
+		},
+	}
+
+	// Include lists need no column lookup.
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT `id`, `email` FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "alice@example.com"))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers` \\(`id`, `email`\\) VALUES").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	destMock.ExpectCommit()
+
+	_, err := cp.Copy(context.Background(), recordSet)
+
+	require.NoError(t, err)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
+
 func createSimpleGraph() *graph.Graph {
 	jobCfg := &config.JobConfig{
 		RootTable:  "customers",
@@ -799,3 +907,210 @@ func createMultiLevelGraph() *graph.Graph {
 	g, _ := builder.Build()
 	return g
 }
+
+func TestCopyPhase_CopyModeStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+		upsert bool
+		insert string
+	}{
+		{"insert-ignore", false, false, "INSERT IGNORE INTO `customers` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\)$"},
+		{"insert", true, false, "INSERT INTO `customers` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\)$"},
+		{"upsert", false, true, "INSERT INTO `customers` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\) ON DUPLICATE KEY UPDATE `name` = VALUES\\(`name`\\)$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceDB, sourceMock, _ := sqlmock.New()
+			defer func() { _ = sourceDB.Close() }()
+			destDB, destMock, _ := sqlmock.New()
+			defer func() { _ = destDB.Close() }()
+
+			cp, _ := NewCopyPhase(sourceDB, destDB, createSimpleGraph(), config.SafetyConfig{}, logger.NewDefault())
+			cp.SetStrictInsert(tt.strict)
+			cp.SetUpsert(tt.upsert)
+
+			destMock.ExpectBegin()
+			destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+			sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Alice"))
+			destMock.ExpectExec(tt.insert).
+				WithArgs(int64(1), "Alice").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			destMock.ExpectCommit()
+
+			_, err := cp.Copy(context.Background(), &RecordSet{
+				RootPKs: []interface{}{int64(1)},
+				Records: map[string][]interface{}{"customers": {int64(1)}},
+			})
+			require.NoError(t, err)
+			assert.NoError(t, sourceMock.ExpectationsWereMet())
+			assert.NoError(t, destMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCopyPhase_UpsertOverwritesExistingKeys(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	cp, _ := NewCopyPhase(sourceDB, destDB, createSimpleGraph(), config.SafetyConfig{}, logger.NewDefault())
+	cp.SetUpsert(true)
+
+	// Re-copy of a partially copied batch: row 1 already exists on the
+	// destination (MySQL reports 2 affected rows for an updated row), row 2 is new.
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Alice").AddRow(int64(2), "Bob"))
+	destMock.ExpectExec("ON DUPLICATE KEY UPDATE").
+		WithArgs(int64(1), "Alice", int64(2), "Bob").
+		WillReturnResult(sqlmock.NewResult(2, 3))
+	destMock.ExpectCommit()
+
+	stats, err := cp.Copy(context.Background(), &RecordSet{
+		RootPKs: []interface{}{int64(1), int64(2)},
+		Records: map[string][]interface{}{"customers": {int64(1), int64(2)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.RowsCopied)
+	assert.False(t, cp.StrictInsert(), "upsert re-copies must not be gated as strict")
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestBuildUpsertBatchQuery_PKOnlyTable(t *testing.T) {
+	cp := &CopyPhase{graph: createSimpleGraph()}
+	got := cp.buildUpsertBatchQuery("customers", "customers", []string{"id"}, 2)
+	want := "INSERT INTO `customers` (`id`) VALUES (?), (?) ON DUPLICATE KEY UPDATE `id` = `id`"
+	assert.Equal(t, want, got)
+}
+
+func TestCopyPhase_InsertIgnoreReportsSkippedRows(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	cp, _ := NewCopyPhase(sourceDB, destDB, createSimpleGraph(), config.SafetyConfig{}, logger.NewDefault())
+
+	// Rows 1 and 2 were copied by an earlier, interrupted run; only row 3 is new.
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(int64(1), "Alice").AddRow(int64(2), "Bob").AddRow(int64(3), "Carol"))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	destMock.ExpectCommit()
+
+	stats, err := cp.Copy(context.Background(), &RecordSet{
+		RootPKs: []interface{}{int64(1), int64(2), int64(3)},
+		Records: map[string][]interface{}{"customers": {int64(1), int64(2), int64(3)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.RowsCopied)
+	assert.Equal(t, int64(2), stats.RowsSkipped)
+	assert.Equal(t, map[string]int64{"customers": 2}, stats.SkippedPerTable)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestCopyPhase_StrictInsertReportsNoSkips(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	cp, _ := NewCopyPhase(sourceDB, destDB, createSimpleGraph(), config.SafetyConfig{}, logger.NewDefault())
+	cp.SetStrictInsert(true)
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Alice").AddRow(int64(2), "Bob"))
+	destMock.ExpectExec("INSERT INTO `customers`").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	destMock.ExpectCommit()
+
+	stats, err := cp.Copy(context.Background(), &RecordSet{
+		RootPKs: []interface{}{int64(1), int64(2)},
+		Records: map[string][]interface{}{"customers": {int64(1), int64(2)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.RowsSkipped)
+	assert.Empty(t, stats.SkippedPerTable)
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestCopyPhase_DestinationTableTemplate(t *testing.T) {
+	newGraph := func(dateColumn string) *graph.Graph {
+		g, err := graph.NewBuilder(&config.JobConfig{
+			RootTable: "orders", PrimaryKey: "id",
+			DestinationTable: "orders_{year}{month}", DestinationDateColumn: dateColumn,
+		}).Build()
+		require.NoError(t, err)
+		return g
+	}
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{int64(1), int64(2)},
+		Records: map[string][]interface{}{"orders": {int64(1), int64(2)}},
+	}
+
+	t.Run("run date", func(t *testing.T) {
+		sourceDB, sourceMock, _ := sqlmock.New()
+		defer func() { _ = sourceDB.Close() }()
+		destDB, destMock, _ := sqlmock.New()
+		defer func() { _ = destDB.Close() }()
+
+		cp, _ := NewCopyPhase(sourceDB, destDB, newGraph(""), config.SafetyConfig{}, logger.NewDefault())
+		cp.SetRunDate(time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC))
+
+		destMock.ExpectBegin()
+		destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?, \\?\\)").
+			WithArgs(int64(1), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).
+				AddRow(1, "2022-12-31 10:00:00").AddRow(2, "2023-01-02 10:00:00"))
+		destMock.ExpectExec("INSERT IGNORE INTO `orders_202301` \\(`id`, `created_at`\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)$").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		destMock.ExpectCommit()
+
+		stats, err := cp.Copy(context.Background(), recordSet)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), stats.RowsPerTable["orders"])
+		assert.NoError(t, sourceMock.ExpectationsWereMet())
+		assert.NoError(t, destMock.ExpectationsWereMet())
+	})
+
+	t.Run("date column", func(t *testing.T) {
+		sourceDB, sourceMock, _ := sqlmock.New()
+		defer func() { _ = sourceDB.Close() }()
+		destDB, destMock, _ := sqlmock.New()
+		defer func() { _ = destDB.Close() }()
+
+		cp, _ := NewCopyPhase(sourceDB, destDB, newGraph("created_at"), config.SafetyConfig{}, logger.NewDefault())
+
+		destMock.ExpectBegin()
+		destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?, \\?\\)").
+			WithArgs(int64(1), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).
+				AddRow(1, "2022-12-31 10:00:00").AddRow(2, "2023-01-02 10:00:00"))
+		destMock.ExpectExec("INSERT IGNORE INTO `orders_202212` \\(`id`, `created_at`\\) VALUES \\(\\?, \\?\\)$").
+			WithArgs(int64(1), "2022-12-31 10:00:00").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		destMock.ExpectExec("INSERT IGNORE INTO `orders_202301` \\(`id`, `created_at`\\) VALUES \\(\\?, \\?\\)$").
+			WithArgs(int64(2), "2023-01-02 10:00:00").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		destMock.ExpectCommit()
+
+		stats, err := cp.Copy(context.Background(), recordSet)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), stats.RowsPerTable["orders"])
+		assert.NoError(t, sourceMock.ExpectationsWereMet())
+		assert.NoError(t, destMock.ExpectationsWereMet())
+	})
+}
@@ -28,6 +28,7 @@ type CopyOnlyResult struct {
 	Duration           time.Duration
 	TablesCopied       int
 	RecordsCopied      int64
+	RecordsSkipped     int64 // rows INSERT IGNORE found already present on the destination
 	TablesVerified     int
 	RecordsVerified    int64
 	VerificationMethod string
@@ -50,6 +51,9 @@ type CopyOnlyOrchestrator struct {
 	promptReader    io.Reader
 	staleAtStartup  bool
 	stopCh          <-chan struct{} // cooperative graceful-stop signal (nil = disabled)
+	stopMode        StopMode        // what a cooperative stop does to the in-flight batch
+	hooks           Hooks           // callbacks around each root's phases (NoopHooks by default)
+	skipMaintenance bool            // skip processing.post_copy_maintenance for this run
 }
 
 // NewCopyOnlyOrchestrator creates a new copy-only orchestrator.
@@ -77,6 +81,7 @@ func NewCopyOnlyOrchestrator(cfg *config.Config, jobName string, jobCfg *config.
 		processingCfg:   processingCfg,
 		verificationCfg: verificationCfg,
 		promptReader:    os.Stdin,
+		hooks:           NoopHooks{},
 	}, nil
 }
 
@@ -86,6 +91,25 @@ func (o *CopyOnlyOrchestrator) SetLogger(log *logger.Logger) {
 	o.logger = log
 }
 
+// SetHooks registers callbacks run around each root's discovery, copy and
+// verify phases. Discovery streams, so the copy and verify phases of each
+// discovered batch run, with their hooks, inside the root's discovery phase.
+// nil restores NoopHooks.
+func (o *CopyOnlyOrchestrator) SetHooks(hooks Hooks) {
+	if hooks == nil {
+		hooks = NoopHooks{}
+	}
+	o.hooks = hooks
+}
+
+// runPhase runs fn as phase of the root in info, between the hooks and under
+// the phase's timeout. A SELECT aborted at statement_timeout is reported as a
+// *StatementTimeoutError.
+func (o *CopyOnlyOrchestrator) runPhase(ctx context.Context, phase string, info PhaseInfo, fn func(ctx context.Context) error) error {
+	err := runPhase(ctx, o.hooks, o.logger, phase, phaseTimeout(o.processingCfg, phase), info, fn)
+	return asStatementTimeout(err, o.processingCfg.StatementTimeout)
+}
+
 // SetStopChannel wires the cooperative graceful-stop signal. When the channel
 // closes (first Ctrl-C), the loop finishes the in-flight batch and stops at the
 // next boundary. A nil channel disables cooperative stop.
@@ -93,13 +117,37 @@ func (o *CopyOnlyOrchestrator) SetStopChannel(stop <-chan struct{}) {
 	o.stopCh = stop
 }
 
+// SetStopMode selects whether a cooperative stop lets the in-flight batch
+// finish (StopAfterBatch, the default) or cancels it (StopImmediately).
+func (o *CopyOnlyOrchestrator) SetStopMode(mode StopMode) {
+	o.stopMode = mode
+}
+
+// SetSkipMaintenance skips processing.post_copy_maintenance for this run.
+func (o *CopyOnlyOrchestrator) SetSkipMaintenance(skip bool) {
+	o.skipMaintenance = skip
+}
+
+// weakestVerificationMethod is the verification method the copy mode
+// assumes: "count" when the job or any table verifies by count.
+func (o *CopyOnlyOrchestrator) weakestVerificationMethod() string {
+	if o.jobConfig == nil {
+		return o.verificationCfg.EffectiveMethod()
+	}
+	return o.jobConfig.WeakestVerification(o.verificationCfg).EffectiveMethod()
+}
+
 // Initialize builds dependency graph and computes copy order.
 func (o *CopyOnlyOrchestrator) Initialize() error {
 	if o.initialized {
 		return nil
 	}
+	if err := checkDistinctDatabases(o.config); err != nil {
+		return err
+	}
 
 	builder := graph.NewBuilder(o.jobConfig)
+	builder.SetLogger(o.logger)
 	g, err := builder.Build()
 	if err != nil {
 		return fmt.Errorf("failed to build dependency graph: %w", err)
@@ -109,6 +157,9 @@ func (o *CopyOnlyOrchestrator) Initialize() error {
 	if o.graph.HasCycle() {
 		return fmt.Errorf("cycle detected in dependency graph")
 	}
+	if err := checkReferenceKeys(o.graph); err != nil {
+		return err
+	}
 
 	copyOrder, err := o.graph.CopyOrder()
 	if err != nil {
@@ -171,7 +222,7 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 		o.logger.Warn(copyOnlySkipVerificationNote)
 	}
 	if !force {
-		if err := o.checkDestinationEmpty(ctx); err != nil {
+		if err := o.checkDestinationEmpty(ctx, result.StartedAt); err != nil {
 			return fail("preflight check failed: %w", err)
 		}
 	}
@@ -185,19 +236,24 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 		o.processingCfg.BatchSize,
 		jobState.LastProcessedRootPKID,
 	)
+	window, err := openIncrementalWindow(ctx, o.dbManager.Source, resumeMgr, o.jobConfig.IncrementalColumn, o.logger)
+	if err != nil {
+		return fail("failed to open incremental window: %w", err)
+	}
+	fetcher.SetIncrementalWindow(window)
+	fetcher.SetOrder(o.processingCfg.RootOrder)
 
-	discovery, err := NewRecordDiscovery(o.graph, o.dbManager.Source, o.processingCfg.BatchSize)
+	discovery, err := newJobDiscovery(o.dbManager, o.graph, o.processingCfg, o.logger.WithPhase("discovery"))
 	if err != nil {
 		return fail("failed to create record discovery: %w", err)
 	}
-	discovery.SetLogger(o.logger)
 
 	copyPhase, err := NewCopyPhase(
 		o.dbManager.Source,
 		o.dbManager.Destination,
 		o.graph,
 		o.config.Safety,
-		o.logger,
+		o.logger.WithPhase("copy"),
 	)
 	if err != nil {
 		return fail("failed to create copy phase: %w", err)
@@ -209,15 +265,19 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 	// faithful — and a later archive/purge of those source rows would then delete
 	// data that was never truly copied. Force strict INSERT (abort on duplicate)
 	// when the post-copy safety net is weak: count verification, verification
-	// skipped, or a destination secondary UNIQUE index.
-	effectiveMethod := o.verificationCfg.EffectiveMethod()
+	// skipped, or a destination secondary UNIQUE index. A count
+	// verification_method on any table counts as count verification.
+	effectiveMethod := o.weakestVerificationMethod()
 	destUniqueIdx, err := destinationSecondaryUniqueIndexes(ctx, o.dbManager.Destination,
 		o.config.Destination.Database, o.graph.AllNodes())
 	if err != nil {
 		return fail("failed to inspect destination unique indexes: %w", err)
 	}
-	strictInsert := shouldUseStrictInsert(effectiveMethod, o.verificationCfg.SkipVerification, len(destUniqueIdx) > 0)
-	if strictInsert && effectiveMethod != "count" {
+	strictInsert, upsert, err := resolveCopyMode(o.processingCfg.CopyMode, effectiveMethod, o.verificationCfg.SkipVerification, destUniqueIdx)
+	if err != nil {
+		return fail("%w", err)
+	}
+	if strictInsert && effectiveMethod != "count" && o.processingCfg.CopyMode != "insert" {
 		reason := "verification skipped (a silently-skipped row would leave an incomplete copy)"
 		if len(destUniqueIdx) > 0 {
 			reason = "destination secondary unique index present: " + strings.Join(destUniqueIdx, ", ")
@@ -225,21 +285,44 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 		o.logger.Warnw("Forcing strict INSERT (INSERT IGNORE disabled): a silently-skipped duplicate would leave an incomplete copy", "reason", reason)
 	}
 	copyPhase.SetStrictInsert(strictInsert)
+	copyPhase.SetUpsert(upsert)
+	writer, err := newDestinationWriter(ctx, o.dbManager.Destination, o.processingCfg.CopyWriter)
+	if err != nil {
+		return fail("%w", err)
+	}
+	copyPhase.SetWriter(writer)
+	copyPhase.SetStatementTimeout(o.processingCfg.StatementTimeout)
+	copyPhase.SetRateLimiter(NewRowRateLimiter(o.processingCfg.MaxRowsPerSecond))
 
 	dataVerifier, err := verifier.NewVerifier(
-		o.dbManager.Source,
+		o.dbManager.ReadSource(),
 		o.dbManager.Destination,
 		o.graph,
 		verifier.VerificationMethod(o.verificationCfg.EffectiveMethod()),
-		o.logger,
+		o.logger.WithPhase("verify"),
 	)
 	if err != nil {
 		return fail("failed to create verifier: %w", err)
 	}
+	if err := applyTransforms(o.jobConfig, copyPhase, dataVerifier); err != nil {
+		return fail("failed to configure column transforms: %w", err)
+	}
 
 	// Honor processing.batch_size for copy/verify/resume chunking, not just the
 	// root fetch (issue #8, Problem 2). Must run before replay and the batch loop.
 	o.applyChunkSizing(copyPhase, dataVerifier, resumeMgr)
+	dataVerifier.SetMaxInClauseSize(o.processingCfg.MaxInClauseSize)
+	dataVerifier.SetStatementTimeout(o.processingCfg.StatementTimeout)
+	dataVerifier.SetCountPrecheck(o.verificationCfg.CountPrecheck)
+	dataVerifier.SetCanonicalValues(o.verificationCfg.CanonicalValues)
+	dataVerifier.SetGlobalIgnoredColumns(o.verificationCfg.IgnoreColumns)
+	// destination_table templates are dated once per run, so copy and verify
+	// name the same tables.
+	copyPhase.SetRunDate(result.StartedAt)
+	dataVerifier.SetRunDate(result.StartedAt)
+
+	budgetCtx, cancelBudget := runtimeBudget(ctx, o.processingCfg.MaxRuntime)
+	defer cancelBudget()
 
 	if shouldResume {
 		if err := o.replayPendingPKs(ctx, resumeMgr, discovery, copyPhase, dataVerifier, fetcher, result); err != nil {
@@ -247,6 +330,7 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 		}
 	}
 
+	drained := false
 	for {
 		select {
 		case <-ctx.Done():
@@ -260,19 +344,25 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 			o.logger.Warn("Graceful stop requested - stopping at batch boundary (run again to resume)")
 			break
 		}
+		if budgetExceeded(ctx, budgetCtx) {
+			o.logger.Warnw("Runtime budget exhausted - stopping at batch boundary (run again to resume)",
+				"max_runtime", o.processingCfg.MaxRuntime)
+			return fail("%w", ErrRuntimeBudgetExceeded)
+		}
 
 		rootIDs, err := fetcher.FetchNextBatch(ctx)
 		if err != nil {
 			return fail("failed to fetch root IDs: %w", err)
 		}
 		if len(rootIDs) == 0 {
+			drained = true
 			break
 		}
 
 		// Operator pause switch: block before processing this batch while the
 		// sentinel file exists.
-		if err := newSentinelGate(o.processingCfg.SentinelFile, o.logger).wait(ctx, o.stopCh); err != nil {
-			return fail("%w", err)
+		if err := newSentinelGate(o.processingCfg.SentinelFile, o.logger).wait(budgetCtx, o.stopCh); err != nil {
+			return fail("%w", budgetErr(ctx, budgetCtx, err))
 		}
 		if stopRequested(o.stopCh) {
 			o.logger.Warn("Graceful stop requested - stopping at batch boundary (run again to resume)")
@@ -283,22 +373,34 @@ func (o *CopyOnlyOrchestrator) Execute(ctx context.Context, force bool) (result
 			return fail("failed to log pending batch entries: %w", err)
 		}
 
+		batchCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
 		for _, rootID := range rootIDs {
-			copied, err := o.processCopyOnlyRoot(ctx, rootID, discovery, copyPhase, dataVerifier, fetcher, resumeMgr, result)
+			copied, err := o.processCopyOnlyRoot(batchCtx, rootID, discovery, copyPhase, dataVerifier, fetcher, resumeMgr, result)
 			if err != nil {
+				release()
 				return fail("%w", err)
 			}
 			result.RecordsCopied += copied
 		}
+		release()
 
 		if o.processingCfg.SleepSeconds > 0 {
 			sleepDuration := time.Duration(o.processingCfg.SleepSeconds * float64(time.Second))
-			if err := interruptibleSleep(ctx, o.stopCh, sleepDuration); err != nil {
+			if err := interruptibleSleep(budgetCtx, o.stopCh, sleepDuration); err != nil && !budgetExceeded(ctx, budgetCtx) {
 				return fail("%w", err)
 			}
 		}
 	}
 
+	if err := window.complete(ctx, resumeMgr, o.jobName, drained && len(result.Errors) == 0); err != nil {
+		return fail("%w", err)
+	}
+
+	if !o.skipMaintenance {
+		runPostCopyMaintenance(ctx, o.dbManager.Destination, o.graph, o.processingCfg.Maintenance,
+			result.RecordsCopied, result.StartedAt, o.logger)
+	}
+
 	result.Success = len(result.Errors) == 0
 	result.CompletedAt = time.Now()
 	result.Duration = result.CompletedAt.Sub(result.StartedAt)
@@ -326,7 +428,8 @@ func (o *CopyOnlyOrchestrator) replayPendingPKs(ctx context.Context, resumeMgr *
 			preview = preview[:10]
 		}
 		return fmt.Errorf("job %q has %d 'pending' root PKs from a prior interrupted run and uses strict INSERT "+
-			"(forced by verification.method: count, --skip-verify, or a destination secondary unique index), so they "+
+			"(forced by verification.method: count, --skip-verify, a destination secondary unique index, or "+
+			"processing.copy_mode: insert), so they "+
 			"cannot be safely re-copied (their destination rows may already be committed, and a strict INSERT aborts "+
 			"on duplicate).\n\n"+
 			"To recover, choose one:\n"+
@@ -354,7 +457,9 @@ func (o *CopyOnlyOrchestrator) replayPendingPKs(ctx context.Context, resumeMgr *
 		if err != nil {
 			return err
 		}
-		copied, err := o.processCopyOnlyRoot(ctx, typedPK, discovery, copyPhase, dataVerifier, fetcher, resumeMgr, result)
+		rootCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
+		copied, err := o.processCopyOnlyRoot(rootCtx, typedPK, discovery, copyPhase, dataVerifier, fetcher, resumeMgr, result)
+		release()
 		if err != nil {
 			return fmt.Errorf("replay failed for pk=%s: %w", rawPK, err)
 		}
@@ -381,28 +486,31 @@ func (o *CopyOnlyOrchestrator) applyChunkSizing(copyPhase *CopyPhase, dataVerifi
 	resumeMgr.SetChunkSize(o.processingCfg.BatchSize)
 }
 
+// processCopyOnlyRoot copies and verifies the tree under rootID as
+// discovery streams it: each table's PKs are copied and verified in
+// discovery-batch-sized pieces as soon as the table is discovered, so only the
+// tables still waiting on a parent are held in memory rather than the whole
+// tree. Each piece is copied in its own destination transaction; a failed
+// root stays pending and is re-copied on resume.
 func (o *CopyOnlyOrchestrator) processCopyOnlyRoot(ctx context.Context, rootID interface{}, discovery *RecordDiscovery, copyPhase *CopyPhase, dataVerifier *verifier.Verifier, fetcher *RootIDFetcher, resumeMgr *ResumeManager, result *CopyOnlyResult) (int64, error) {
-	discovered, err := discovery.Discover(ctx, []interface{}{rootID})
-	if err != nil {
-		markFailedUnlessCanceled(ctx, resumeMgr, o.logger, o.jobName, rootID, err)
-		return 0, fmt.Errorf("discovery failed: %w", err)
-	}
-	copyStats, err := copyPhase.Copy(ctx, convertRecordSet(discovered))
+	info := PhaseInfo{JobName: o.jobName, RootPKs: []interface{}{rootID}}
+	var copied int64
+	verifiedTables := make(map[string]bool)
+	var batchErr error
+	err := o.runPhase(ctx, PhaseDiscovery, info, func(ctx context.Context) error {
+		return discovery.DiscoverStream(ctx, info.RootPKs, func(table string, pkBatch []interface{}) error {
+			batchErr = o.copyOnlyBatch(ctx, table, pkBatch, info, copyPhase, dataVerifier, verifiedTables, &copied, result)
+			return batchErr
+		})
+	})
 	if err != nil {
 		markFailedUnlessCanceled(ctx, resumeMgr, o.logger, o.jobName, rootID, err)
-		return 0, fmt.Errorf("copy failed: %w", err)
-	}
-	if !o.verificationCfg.SkipVerification {
-		verifyStats, err := dataVerifier.Verify(ctx, discovered)
-		if err != nil {
-			markFailedUnlessCanceled(ctx, resumeMgr, o.logger, o.jobName, rootID, err)
-			return 0, fmt.Errorf("verification failed: %w", err)
-		}
-		if verifyStats != nil {
-			result.TablesVerified += verifyStats.TablesVerified
-			result.RecordsVerified += verifyStats.TotalRows
+		if batchErr != nil {
+			return 0, batchErr
 		}
+		return 0, fmt.Errorf("discovery failed: %w", err)
 	}
+	result.TablesVerified += len(verifiedTables)
 	if err := resumeMgr.UpdateCheckpoint(ctx, o.jobName, rootID); err != nil {
 		return 0, fmt.Errorf("checkpoint update failed: %w", err)
 	}
@@ -410,7 +518,47 @@ func (o *CopyOnlyOrchestrator) processCopyOnlyRoot(ctx context.Context, rootID i
 	if err := resumeMgr.MarkCompleted(ctx, o.jobName, rootID); err != nil {
 		return 0, fmt.Errorf("failed to mark completed: %w", err)
 	}
-	return copyStats.RowsCopied, nil
+	return copied, nil
+}
+
+// copyOnlyBatch runs the copy and verify phases over one batch of table's
+// PKs yielded by DiscoverStream, adding the rows copied to copied and the
+// tables verified to verifiedTables. The batch is verified as it was copied,
+// as one IN (...) list, rather than re-chunked by the verify chunk size.
+func (o *CopyOnlyOrchestrator) copyOnlyBatch(ctx context.Context, table string, pkBatch []interface{}, info PhaseInfo, copyPhase *CopyPhase, dataVerifier *verifier.Verifier, verifiedTables map[string]bool, copied *int64, result *CopyOnlyResult) error {
+	info.Records = &types.RecordSet{
+		RootPKs: info.RootPKs,
+		Records: map[string][]interface{}{table: pkBatch},
+		Stats:   types.DiscoveryStats{TablesScanned: 1, RecordsFound: int64(len(pkBatch))},
+	}
+	var copyStats *CopyStats
+	err := o.runPhase(ctx, PhaseCopy, info, func(ctx context.Context) (err error) {
+		copyStats, err = copyPhase.Copy(ctx, convertRecordSet(info.Records))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("copy failed: %w", err)
+	}
+	*copied += copyStats.RowsCopied
+	result.RecordsSkipped += copyStats.RowsSkipped
+	if o.verificationCfg.SkipVerification {
+		return nil
+	}
+	var verifyStats *verifier.VerifyStats
+	err = o.runPhase(ctx, PhaseVerify, info, func(ctx context.Context) (err error) {
+		verifyStats, err = dataVerifier.VerifyBatches(ctx, map[string][][]interface{}{table: {pkBatch}})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	if verifyStats != nil {
+		for verified := range verifyStats.MethodPerTable {
+			verifiedTables[verified] = true
+		}
+		result.RecordsVerified += verifyStats.TotalRows
+	}
+	return nil
 }
 
 func (o *CopyOnlyOrchestrator) displayInfoOrPrompt(force bool) error {
@@ -436,19 +584,31 @@ func (o *CopyOnlyOrchestrator) displayInfoOrPrompt(force bool) error {
 	return nil
 }
 
-// checkDestinationEmpty verifies destination tables in copy order do not contain data.
-func (o *CopyOnlyOrchestrator) checkDestinationEmpty(ctx context.Context) error {
-	for _, table := range o.copyOrder {
-		query := fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", sqlutil.QuoteIdentifier(table))
+// checkDestinationEmpty verifies the destination tables of the copy order
+// do not contain data. destination_table templates are resolved like the
+// copy's: run-dated ones with runDate, row-dated ones to every existing
+// destination table matching the template.
+func (o *CopyOnlyOrchestrator) checkDestinationEmpty(ctx context.Context, runDate time.Time) error {
+	targets, err := resolveDestinationTargets(o.graph, o.copyOrder, runDate, func() ([]string, error) {
+		return schemaTableNames(ctx, o.dbManager.Destination, o.config.Destination.Database, o.logger)
+	})
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if target.unmatched {
+			continue
+		}
+		query := fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", sqlutil.QuoteIdentifier(target.dest))
 		var dummy int
 		err := o.dbManager.Destination.QueryRowContext(ctx, query).Scan(&dummy)
 		switch {
 		case err == nil:
-			return fmt.Errorf("destination table %q already contains data", table)
+			return fmt.Errorf("destination table %q already contains data", target.dest)
 		case errors.Is(err, sql.ErrNoRows):
 			continue
 		default:
-			return fmt.Errorf("failed to check destination table %s: %w", table, err)
+			return fmt.Errorf("failed to check destination table %s: %w", target.dest, err)
 		}
 	}
 	return nil
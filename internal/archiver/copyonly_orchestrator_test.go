@@ -2,6 +2,7 @@ package archiver
 
 import (
 	"context"
+	"database/sql/driver"
 	"strings"
 	"testing"
 	"time"
@@ -152,12 +153,51 @@ func TestCopyOnlyOrchestrator_CheckDestinationEmpty(t *testing.T) {
 
 	mock.ExpectQuery("SELECT 1 FROM `users` LIMIT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 
-	err = orch.checkDestinationEmpty(context.Background())
+	err = orch.checkDestinationEmpty(context.Background(), time.Now())
 	if err == nil {
 		t.Fatal("expected destination not empty error")
 	}
 }
 
+func TestCopyOnlyOrchestrator_CheckDestinationEmpty_DestinationTemplates(t *testing.T) {
+	cfg := createTestConfig()
+	jobCfg := createTestJobConfig()
+	// orders is run-dated; order_items is dated by each row's created_at.
+	jobCfg.Relations[0].DestinationTable = "orders_{year}{month}"
+	jobCfg.Relations[0].Relations[0].DestinationTable = "order_items_{year}"
+	jobCfg.Relations[0].Relations[0].DestinationDateColumn = "created_at"
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock new failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	orch, err := NewCopyOnlyOrchestrator(cfg, "test_job", jobCfg, &database.Manager{Destination: db})
+	if err != nil {
+		t.Fatalf("NewCopyOnlyOrchestrator failed: %v", err)
+	}
+	if err := orch.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT TABLE_NAME FROM information_schema.TABLES").
+		WithArgs(cfg.Destination.Database).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).
+			AddRow("order_items_2022").AddRow("order_items_2023").AddRow("orders"))
+	for _, table := range []string{"users", "orders_202301", "order_items_2022", "order_items_2023", "profiles"} {
+		mock.ExpectQuery("SELECT 1 FROM `" + table + "` LIMIT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	}
+
+	runDate := time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)
+	if err := orch.checkDestinationEmpty(context.Background(), runDate); err != nil {
+		t.Fatalf("checkDestinationEmpty failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expectations: %v", err)
+	}
+}
+
 // TestCopyOnlyOrchestrator_Execute_ResetsStatusOnLockTimeout exercises the
 // defer-Idle guarantee: if anything fails after UpdateJobStatus(Running) but
 // before completion, job_status must end up Idle so later runs are not blocked.
@@ -396,3 +436,84 @@ func TestCopyOnlyOrchestrator_WiresBatchSizeIntoChunking(t *testing.T) {
 		t.Errorf("resume chunk size = %d, want %d (batch_size ignored)", got, batchSize)
 	}
 }
+
+// TestProcessCopyOnlyRoot_StreamsBatchesIntoCopyAndVerify proves copy-only
+// copies and verifies each table batch as discovery yields it: the root is
+// copied before its orders are discovered, and the orders, split by the
+// discovery batch size, are copied and verified one batch at a time.
+func TestProcessCopyOnlyRoot_StreamsBatchesIntoCopyAndVerify(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createMultiLevelGraph()
+	log := logger.NewDefault()
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodCount, log)
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+	hooks := &recordingHooks{}
+	o := &CopyOnlyOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1},
+		verificationCfg: config.VerificationConfig{Method: "count"},
+	}
+	o.SetHooks(hooks)
+
+	expectBatch := func(table string, cols []string, row ...driver.Value) {
+		destMock.ExpectBegin()
+		destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		sourceMock.ExpectQuery("SELECT \\* FROM `" + table + "` WHERE `id` IN \\(\\?\\)").
+			WithArgs(row[0]).
+			WillReturnRows(sqlmock.NewRows(cols).AddRow(row...))
+		destMock.ExpectExec("INSERT IGNORE INTO `" + table + "`").WillReturnResult(sqlmock.NewResult(0, 1))
+		destMock.ExpectCommit()
+		sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `" + table + "`").
+			WithArgs(row[0]).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `" + table + "`").
+			WithArgs(row[0]).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	}
+	expectBatch("customers", []string{"id", "name"}, int64(1), "c")
+	sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)).AddRow(int64(11)))
+	expectBatch("orders", []string{"id", "customer_id"}, int64(10), int64(1))
+	expectBatch("orders", []string{"id", "customer_id"}, int64(11), int64(1))
+	archMock.ExpectExec("UPDATE .*archiver_job.* SET last_processed_root_pk_id").
+		WithArgs("1", "job1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result := &CopyOnlyResult{}
+	copied, err := o.processCopyOnlyRoot(context.Background(), int64(1), discovery, copyPhase, dataVerifier, fetcher, resumeMgr, result)
+	if err != nil {
+		t.Fatalf("processCopyOnlyRoot: %v", err)
+	}
+	if copied != 3 || result.RecordsVerified != 3 || result.TablesVerified != 2 {
+		t.Errorf("copied=%d verified=%d tables=%d, want 3, 3, 2", copied, result.RecordsVerified, result.TablesVerified)
+	}
+	wantCalls := []string{"before:discovery"}
+	for i := 0; i < 3; i++ {
+		wantCalls = append(wantCalls, "before:copy", "after:copy", "before:verify", "after:verify")
+	}
+	wantCalls = append(wantCalls, "after:discovery")
+	if strings.Join(hooks.calls, ",") != strings.Join(wantCalls, ",") {
+		t.Errorf("hook calls = %v, want %v", hooks.calls, wantCalls)
+	}
+	for _, mock := range []sqlmock.Sqlmock{sourceMock, destMock, archMock} {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	}
+}
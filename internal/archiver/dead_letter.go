@@ -0,0 +1,72 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// failedRecordsTable returns the quoted qualified name of the dead-letter
+// table.
+func (r *ResumeManager) failedRecordsTable() string {
+	return sqlutil.QuoteIdentifier(r.jobSchema) + "." + sqlutil.QuoteIdentifier("goarchive_failed_records")
+}
+
+// RecordFailedRecords upserts pks of table into goarchive_failed_records
+// (processing.dead_letter), creating the table on first use. A PK already
+// recorded for the job and table gets its attempts incremented and its
+// error and last_attempt replaced, so rows that fail run after run stand out
+// by their attempt count. Chunked multi-row INSERT.
+func (r *ResumeManager) RecordFailedRecords(ctx context.Context, jobName, table string, pks []interface{}, cause error) error {
+	if len(pks) == 0 {
+		return nil
+	}
+	if !r.failedRecordsReady {
+		if _, err := r.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	job_name VARCHAR(255) NOT NULL,
+	table_name VARCHAR(64) NOT NULL,
+	pk VARCHAR(255) NOT NULL,
+	error_message TEXT,
+	attempts INT NOT NULL DEFAULT 1,
+	last_attempt DATETIME NOT NULL,
+	PRIMARY KEY (job_name, table_name, pk)
+) ENGINE=InnoDB`, r.failedRecordsTable())); err != nil {
+			return fmt.Errorf("failed to create goarchive_failed_records in schema %q: %w", r.jobSchema, err)
+		}
+		r.failedRecordsReady = true
+	}
+
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+	chunk := r.effectiveChunkSize()
+	for start := 0; start < len(pks); start += chunk {
+		end := start + chunk
+		if end > len(pks) {
+			end = len(pks)
+		}
+		group := pks[start:end]
+
+		tuples := make([]string, len(group))
+		args := make([]interface{}, 0, len(group)*4)
+		for i, pk := range group {
+			pkID, err := formatPK(pk)
+			if err != nil {
+				return fmt.Errorf("unsupported PK type %T: %w", pk, err)
+			}
+			tuples[i] = "(?, ?, ?, ?, 1, NOW())"
+			args = append(args, jobName, table, pkID, message)
+		}
+		query := fmt.Sprintf("INSERT INTO %s (job_name, table_name, pk, error_message, attempts, last_attempt) VALUES %s "+
+			"ON DUPLICATE KEY UPDATE attempts = attempts + 1, error_message = VALUES(error_message), last_attempt = VALUES(last_attempt)",
+			r.failedRecordsTable(), strings.Join(tuples, ", "))
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to record failed rows of table %s: %w", table, err)
+		}
+	}
+	r.logger.Warnf("Recorded %d failed rows of table %s for job %q in goarchive_failed_records", len(pks), table, jobName)
+	return nil
+}
@@ -5,7 +5,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/dbsmedya/goarchive/internal/graph"
@@ -22,6 +23,10 @@ type DeleteStats struct {
 	Duration        time.Duration // Time taken for delete operation
 	TablesSkipped   int           // Tables with no rows to delete
 	RowsPerTable    map[string]int64
+	// ResidualRows maps each table whose deleted PKs are still (or again)
+	// present on the source to how many remain, e.g. rows a trigger
+	// re-inserted. Only filled by the post-delete check.
+	ResidualRows map[string]int64
 }
 
 // DeletePhase handles deletion of archived records from the source database.
@@ -37,12 +42,61 @@ type DeletePhase struct {
 	graph        *graph.Graph
 	batchSize    int     // GA-P4-F2-T2: Batch delete size
 	sleepSeconds float64 // Throttle: pause between delete chunks (0 = disabled)
+	maxIn        int     // cap on PKs per DELETE ... IN (...); 0 => batchSize only
 	logger       *logger.Logger
 
+	// transactional wraps each Delete call (one root-PK group) in a single
+	// transaction instead of auto-committing every chunk (safety.transactional_delete).
+	transactional bool
+
+	// audit, when set, records every executed DELETE (safety.delete_audit_log).
+	// In transactional mode entries wait in pendingAudit until COMMIT.
+	audit        *DeleteAuditLog
+	pendingAudit []auditedDelete
+
+	// backup, when set, receives every row of a record set before it is
+	// deleted (safety.delete_backup).
+	backup *DeleteBackup
+
+	// orphanCheck is safety.orphan_check: when set, Delete first looks for
+	// child rows referencing the doomed parents that discovery did not find.
+	orphanCheck string
+	// undeclaredFKs caches, per graph table, the source foreign keys that
+	// reference it without being a graph edge; nil until first read.
+	undeclaredFKs map[string][]referencingFK
+
+	// cascaded maps tables removed by an ON DELETE CASCADE parent FK to that
+	// parent. Their explicit DELETE is skipped (safety.skip_cascaded_deletes).
+	cascaded map[string]string
+
 	// sleepFn is an injectable seam for the inter-chunk throttle sleep so unit
 	// tests can assert the throttle deterministically without waiting. When nil,
 	// the real (context-interruptible) sleep is used.
 	sleepFn func(ctx context.Context, d time.Duration) error
+
+	// rateLimiter is the rows/second budget drawn before each auto-committed
+	// chunk (processing.max_rows_per_second); nil => unlimited.
+	rateLimiter *RowRateLimiter
+
+	// sortPKs deletes each table's PKs in ascending order
+	// (processing.sort_delete_pks).
+	sortPKs bool
+
+	// onTable is called as each table starts deleting; nil => none.
+	onTable func(string)
+
+	// postDeleteCheck re-counts the deleted PKs on the source after each
+	// Delete (safety.post_delete_check).
+	postDeleteCheck bool
+
+	// disableFKChecks runs each Delete on a dedicated connection with
+	// FOREIGN_KEY_CHECKS=0 (safety.disable_source_foreign_key_checks).
+	disableFKChecks bool
+
+	// stmtTimeout bounds each DELETE (statement deadline) and orphan-check
+	// SELECT (MAX_EXECUTION_TIME hint); 0 => none
+	// (processing.statement_timeout).
+	stmtTimeout time.Duration
 }
 
 // NewDeletePhase creates a new delete phase coordinator.
@@ -53,6 +107,9 @@ func NewDeletePhase(db *sql.DB, g *graph.Graph, batchSize int, log *logger.Logge
 	if g == nil {
 		return nil, fmt.Errorf("graph is nil")
 	}
+	if err := g.CheckIdentifiers(); err != nil {
+		return nil, fmt.Errorf("invalid identifier in graph: %w", err)
+	}
 	if log == nil {
 		log = logger.NewDefault()
 	}
@@ -70,13 +127,133 @@ func NewDeletePhase(db *sql.DB, g *graph.Graph, batchSize int, log *logger.Logge
 	}, nil
 }
 
+// auditedDelete is a DELETE held back from the audit log until its
+// transaction commits.
+type auditedDelete struct {
+	table        string
+	pks          []interface{}
+	rowsAffected int64
+}
+
+// execer is the subset of *sql.DB / *sql.Tx the delete statements run on.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// deleteSession is the subset of *sql.DB / *sql.Conn a record set is deleted
+// on: directly, or in a transaction.
+type deleteSession interface {
+	execer
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
 // Delete executes the delete phase for the given record set.
 // It deletes all tables in reverse dependency order (children first, then parents).
 //
 // GA-P4-F2-T1: Processes tables in reverse topological order
 // GA-P4-F2-T4: Uses auto-commit (no transaction) to avoid long locks
 // GA-P4-F2-T5: Returns delete statistics
+//
+// With SetTransactional(true) the whole record set is deleted in one
+// transaction: any failure rolls back every delete of the group, and the
+// caller only sees success after COMMIT.
+//
+// With SetPostDeleteCheck(true) the deleted PKs are then re-counted on the
+// source; rows still present are reported in DeleteStats.ResidualRows and
+// returned as a *ResidualRowsError alongside the stats.
 func (dp *DeletePhase) Delete(ctx context.Context, recordSet *RecordSet) (*DeleteStats, error) {
+	stats, err := dp.deleteRecordSet(ctx, recordSet)
+	if err != nil || !dp.postDeleteCheck {
+		return stats, err
+	}
+	return stats, dp.checkResidual(ctx, recordSet, stats)
+}
+
+// deleteRecordSet runs the orphan check and deletes recordSet, in one
+// transaction when transactional.
+func (dp *DeletePhase) deleteRecordSet(ctx context.Context, recordSet *RecordSet) (*DeleteStats, error) {
+	if err := dp.checkOrphans(ctx, recordSet); err != nil {
+		return nil, err
+	}
+	if err := dp.backupRecordSet(ctx, recordSet); err != nil {
+		return nil, err
+	}
+
+	var session deleteSession = dp.db
+	if dp.disableFKChecks {
+		conn, err := dp.foreignKeyChecksOff(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer dp.restoreForeignKeyChecks(conn)
+		session = conn
+	}
+
+	if !dp.transactional {
+		return dp.deleteAll(ctx, session, recordSet)
+	}
+
+	tx, err := session.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin delete transaction: %w", err)
+	}
+	dp.pendingAudit = nil
+	defer func() { dp.pendingAudit = nil }()
+
+	stats, err := dp.deleteAll(ctx, tx, recordSet)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			dp.logger.Errorf("Failed to roll back delete transaction: %v", rbErr)
+		} else {
+			dp.logger.Warnf("Rolled back delete transaction: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit delete transaction: %w", err)
+	}
+
+	for _, d := range dp.pendingAudit {
+		if err := dp.audit.RecordDelete(d.table, d.pks, d.rowsAffected); err != nil {
+			return nil, fmt.Errorf("deletes committed but audit log failed: %w", err)
+		}
+	}
+	return stats, nil
+}
+
+// foreignKeyChecksOff checks out a dedicated source connection and disables
+// FOREIGN_KEY_CHECKS on it, so the session variable cannot reach other users
+// of the pool. The caller must hand the connection to restoreForeignKeyChecks.
+func (dp *DeletePhase) foreignKeyChecksOff(ctx context.Context) (*sql.Conn, error) {
+	conn, err := dp.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "SET SESSION FOREIGN_KEY_CHECKS = 0"); err != nil {
+		if closeErr := conn.Close(); closeErr != nil {
+			dp.logger.Warnf("Failed to close source connection: %v", closeErr)
+		}
+		return nil, fmt.Errorf("failed to disable FOREIGN_KEY_CHECKS: %w", err)
+	}
+	dp.logger.Debug("Disabled FOREIGN_KEY_CHECKS for delete phase")
+	return conn, nil
+}
+
+// restoreForeignKeyChecks re-enables FOREIGN_KEY_CHECKS on conn and returns
+// it to the pool. It runs whether the deletes succeeded or not (SET is not
+// transactional) and ignores the caller's context, which may be cancelled.
+func (dp *DeletePhase) restoreForeignKeyChecks(conn *sql.Conn) {
+	if _, err := conn.ExecContext(context.Background(), "SET SESSION FOREIGN_KEY_CHECKS = 1"); err != nil {
+		dp.logger.Errorf("Failed to reset FOREIGN_KEY_CHECKS on source connection: %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		dp.logger.Warnf("Failed to close source connection: %v", err)
+	}
+}
+
+// deleteAll deletes every table of recordSet on ex in reverse dependency order.
+func (dp *DeletePhase) deleteAll(ctx context.Context, ex execer, recordSet *RecordSet) (*DeleteStats, error) {
 	startTime := time.Now()
 
 	stats := &DeleteStats{
@@ -84,7 +261,7 @@ func (dp *DeletePhase) Delete(ctx context.Context, recordSet *RecordSet) (*Delet
 	}
 
 	// GA-P4-F2-T1: Get delete order (reverse topological - children first)
-	deleteOrder, err := dp.graph.DeleteOrder()
+	deleteOrder, err := dp.graph.DeleteOrderContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get delete order: %w", err)
 	}
@@ -98,6 +275,12 @@ func (dp *DeletePhase) Delete(ctx context.Context, recordSet *RecordSet) (*Delet
 			return nil, fmt.Errorf("delete interrupted: %w", err)
 		}
 
+		if parent, ok := dp.cascaded[table]; ok {
+			dp.logger.Infof("Skipping table %q (rows removed by ON DELETE CASCADE from %q)", table, parent)
+			stats.TablesSkipped++
+			continue
+		}
+
 		pks, exists := recordSet.Records[table]
 		if !exists || len(pks) == 0 {
 			// Table has no records to delete
@@ -106,8 +289,13 @@ func (dp *DeletePhase) Delete(ctx context.Context, recordSet *RecordSet) (*Delet
 			continue
 		}
 
+		if dp.onTable != nil {
+			dp.onTable(table)
+		}
+
 		// GA-P4-F2-T3: Delete table using primary keys
-		rowsDeleted, err := dp.deleteTable(ctx, table, pks)
+		tableStart := time.Now()
+		rowsDeleted, err := dp.deleteTable(ctx, ex, table, pks)
 		if err != nil {
 			return nil, fmt.Errorf("failed to delete from table %s: %w", table, err)
 		}
@@ -117,16 +305,20 @@ func (dp *DeletePhase) Delete(ctx context.Context, recordSet *RecordSet) (*Delet
 		stats.RowsPerTable[table] = rowsDeleted
 
 		// GA-P4-F2-T5: Delete progress logging
-		dp.logger.Infof("Deleted %d rows from table %q", rowsDeleted, table)
+		dp.logger.Infow("Deleted rows from table",
+			logger.FieldTable, table,
+			logger.FieldRows, rowsDeleted,
+			logger.FieldDuration, time.Since(tableStart).Milliseconds(),
+		)
 	}
 
 	// Populate final statistics
 	stats.Duration = time.Since(startTime)
 
-	dp.logger.Infof("Delete phase complete: %d tables, %d rows deleted, duration: %s",
-		stats.TablesProcessed,
-		stats.RowsDeleted,
-		stats.Duration,
+	dp.logger.Infow("Delete phase complete",
+		"tables", stats.TablesProcessed,
+		logger.FieldRows, stats.RowsDeleted,
+		logger.FieldDuration, stats.Duration.Milliseconds(),
 	)
 
 	return stats, nil
@@ -138,7 +330,7 @@ func (dp *DeletePhase) Delete(ctx context.Context, recordSet *RecordSet) (*Delet
 // GA-P4-F2-T3: PK-based deletes
 // GA-P4-F2-T4: Delete without transaction (auto-commit for each batch)
 // GA-P4-F2-T6: Idempotent deletes (no error if already deleted)
-func (dp *DeletePhase) deleteTable(ctx context.Context, table string, pks []interface{}) (int64, error) {
+func (dp *DeletePhase) deleteTable(ctx context.Context, ex execer, table string, pks []interface{}) (int64, error) {
 	if len(pks) == 0 {
 		return 0, nil
 	}
@@ -148,25 +340,35 @@ func (dp *DeletePhase) deleteTable(ctx context.Context, table string, pks []inte
 
 	var totalDeleted int64
 
+	// Never delete (or audit) the same PK twice, even when it reached the
+	// record set through several parent paths.
+	pks = uniquePKs(pks)
+	if dp.sortPKs {
+		pks = sortedPKs(pks)
+	}
+
 	// GA-P4-F2-T2: Process in batches to avoid large IN clauses
-	totalBatches := (len(pks) + dp.batchSize - 1) / dp.batchSize
+	batches := sqlutil.ChunkValues(pks, sqlutil.InClauseSize(dp.graph.BatchSizeFor(table, dp.batchSize), dp.maxIn))
+	totalBatches := len(batches)
 
-	for batchNum := 0; batchNum < totalBatches; batchNum++ {
+	for batchNum, batchPKs := range batches {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return totalDeleted, fmt.Errorf("delete interrupted: %w", err)
 		}
 
-		start := batchNum * dp.batchSize
-		end := start + dp.batchSize
-		if end > len(pks) {
-			end = len(pks)
+		// Like the chunk sleep, the rate limit is skipped in transactional
+		// mode, where waiting would only hold row locks longer.
+		if !dp.transactional {
+			if err := dp.rateLimiter.Wait(ctx, len(batchPKs)); err != nil {
+				return totalDeleted, fmt.Errorf("delete interrupted during rate limit wait: %w", err)
+			}
 		}
-		batchPKs := pks[start:end]
 
 		// GA-P4-F2-T3: Execute PK-based delete
 		// GA-P4-F2-T4: No transaction - each DELETE is auto-committed
-		rowsDeleted, err := dp.executeDelete(ctx, table, pkColumn, batchPKs)
+		chunkStart := time.Now()
+		rowsDeleted, err := dp.executeDelete(ctx, ex, table, pkColumn, batchPKs)
 		if err != nil {
 			return totalDeleted, fmt.Errorf("batch %d/%d failed: %w", batchNum+1, totalBatches, err)
 		}
@@ -174,15 +376,20 @@ func (dp *DeletePhase) deleteTable(ctx context.Context, table string, pks []inte
 		totalDeleted += rowsDeleted
 
 		// GA-P4-F2-T5: Log batch progress
-		if totalBatches > 1 {
-			dp.logger.Debugf("Deleted %d rows from %s (batch %d/%d)",
-				rowsDeleted, table, batchNum+1, totalBatches)
-		}
+		dp.logger.Debugw("Deleted batch",
+			logger.FieldTable, table,
+			logger.FieldBatch, batchNum+1,
+			"batches", totalBatches,
+			logger.FieldRows, rowsDeleted,
+			logger.FieldDuration, time.Since(chunkStart).Milliseconds(),
+		)
 
 		// Replication-lag throttle: pause between delete chunks (not after the
 		// last chunk of this table) so a replica can drain the binlog this
 		// auto-committed DELETE just generated before the next one is issued.
-		if dp.sleepSeconds > 0 && batchNum < totalBatches-1 {
+		// Skipped in transactional mode: nothing reaches the binlog before
+		// COMMIT, so sleeping would only hold row locks longer.
+		if dp.sleepSeconds > 0 && !dp.transactional && batchNum < totalBatches-1 {
 			d := time.Duration(dp.sleepSeconds * float64(time.Second))
 			if err := dp.sleepBetweenChunks(ctx, d); err != nil {
 				return totalDeleted, fmt.Errorf("delete interrupted during throttle sleep: %w", err)
@@ -193,6 +400,76 @@ func (dp *DeletePhase) deleteTable(ctx context.Context, table string, pks []inte
 	return totalDeleted, nil
 }
 
+// sortedPKs returns a copy of pks in ascending order. Integer PKs, including
+// the []byte digits MySQL returns for them, compare numerically; anything
+// else compares by its text.
+func sortedPKs(pks []interface{}) []interface{} {
+	sorted := append([]interface{}(nil), pks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return pkLess(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+func pkLess(a, b interface{}) bool {
+	if x, ok := pkInt(a); ok {
+		if y, ok := pkInt(b); ok {
+			return x < y
+		}
+	}
+	if x, ok := pkUint(a); ok {
+		if y, ok := pkUint(b); ok {
+			return x < y
+		}
+	}
+	return pkText(a) < pkText(b)
+}
+
+func pkText(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func pkInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	}
+	i, err := strconv.ParseInt(pkText(v), 10, 64)
+	return i, err == nil
+}
+
+// pkUint covers the unsigned BIGINT range pkInt cannot hold.
+func pkUint(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		i, _ := pkInt(v)
+		return uint64(i), i >= 0
+	}
+	u, err := strconv.ParseUint(pkText(v), 10, 64)
+	return u, err == nil
+}
+
 // sleepBetweenChunks pauses for d between delete chunks, honoring context
 // cancellation. Uses the injected sleepFn when set (tests); otherwise a real
 // interruptible sleep.
@@ -213,26 +490,20 @@ func (dp *DeletePhase) sleepBetweenChunks(ctx context.Context, d time.Duration)
 // GA-P4-F2-T3: PK-based delete using IN clause
 // GA-P4-F2-T4: Auto-commit (no explicit transaction)
 // GA-P4-F2-T6: Idempotent (no error if 0 rows deleted)
-func (dp *DeletePhase) executeDelete(ctx context.Context, table, pkColumn string, pks []interface{}) (int64, error) {
+func (dp *DeletePhase) executeDelete(ctx context.Context, ex execer, table, pkColumn string, pks []interface{}) (int64, error) {
 	if len(pks) == 0 {
 		return 0, nil
 	}
 
-	// Build DELETE query with IN clause
-	placeholders := make([]string, len(pks))
-	for i := range placeholders {
-		placeholders[i] = "?"
-	}
-
 	// GA-P4-F2-T3: PK-based DELETE
-	query := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)",
-		sqlutil.QuoteIdentifier(table),
-		sqlutil.QuoteIdentifier(pkColumn),
-		strings.Join(placeholders, ","),
-	)
+	query := deleteByPKQuery(table, pkColumn, sqlutil.Placeholders(len(pks), ","))
 
 	// GA-P4-F2-T4: Execute without transaction (auto-commit)
-	result, err := dp.db.ExecContext(ctx, query, pks...)
+	var result sql.Result
+	err := withStatementTimeout(ctx, dp.stmtTimeout, func(ctx context.Context) (err error) {
+		result, err = ex.ExecContext(ctx, query, pks...)
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("delete failed: %w", err)
 	}
@@ -242,6 +513,10 @@ func (dp *DeletePhase) executeDelete(ctx context.Context, table, pkColumn string
 		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
+	if err := dp.recordAudit(table, pks, rowsAffected); err != nil {
+		return rowsAffected, err
+	}
+
 	// GA-P4-F2-T6: Idempotent - warn if 0 rows deleted (may have been deleted already)
 	if rowsAffected == 0 {
 		dp.logger.Debugf("No rows deleted from %s for %d PKs (may have been deleted already)", table, len(pks))
@@ -252,6 +527,93 @@ func (dp *DeletePhase) executeDelete(ctx context.Context, table, pkColumn string
 	return rowsAffected, nil
 }
 
+// deleteByPKQuery renders the delete phase's statement for one chunk of PKs;
+// placeholders is the IN list (the planner passes a symbolic one).
+func deleteByPKQuery(table, pkColumn, placeholders string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)",
+		sqlutil.QuoteIdentifier(table),
+		sqlutil.QuoteIdentifier(pkColumn),
+		placeholders,
+	)
+}
+
+// checkOrphans runs the pre-delete referential integrity check according to
+// orphanCheck: warn logs each report, abort fails with an *OrphanError before
+// any row is deleted.
+func (dp *DeletePhase) checkOrphans(ctx context.Context, recordSet *RecordSet) error {
+	if dp.orphanCheck == OrphanCheckOff {
+		return nil
+	}
+	reports, err := dp.findOrphans(ctx, recordSet)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+	if dp.orphanCheck == OrphanCheckAbort {
+		return &OrphanError{Reports: reports}
+	}
+	for _, r := range reports {
+		dp.logger.Warnf("Orphan check: %s", r)
+	}
+	return nil
+}
+
+// SetOrphanCheck enables the pre-delete referential integrity check:
+// OrphanCheckWarn logs undiscovered child rows that reference rows about to be
+// deleted, OrphanCheckAbort refuses to delete the record set. It costs one
+// SELECT per relation or undeclared foreign key per chunk of parent PKs, plus
+// one information_schema read per DeletePhase.
+func (dp *DeletePhase) SetOrphanCheck(mode string) {
+	dp.orphanCheck = mode
+}
+
+// recordAudit writes (or, in transactional mode, queues until COMMIT) the
+// audit line for one executed DELETE. No-op without an audit log.
+func (dp *DeletePhase) recordAudit(table string, pks []interface{}, rowsAffected int64) error {
+	if dp.audit == nil {
+		return nil
+	}
+	if dp.transactional {
+		dp.pendingAudit = append(dp.pendingAudit, auditedDelete{table: table, pks: pks, rowsAffected: rowsAffected})
+		return nil
+	}
+	return dp.audit.RecordDelete(table, pks, rowsAffected)
+}
+
+// SetAuditLog records every DELETE this phase executes in audit. The caller
+// owns audit and closes it (writing the summary line) when the job ends. nil
+// disables auditing (the default).
+func (dp *DeletePhase) SetAuditLog(audit *DeleteAuditLog) {
+	dp.audit = audit
+}
+
+// SetMaxInClauseSize caps the number of PKs bound into one DELETE's
+// IN (...) list, independently of batch_delete_size. Each capped chunk is a
+// separate auto-committed DELETE (and a throttle point). 0 (the default)
+// leaves the cap at the batch size.
+func (dp *DeletePhase) SetMaxInClauseSize(n int) {
+	dp.maxIn = n
+}
+
+// SetStatementTimeout bounds each DELETE statement with a deadline of this
+// length, failing with a *StatementTimeoutError, and each orphan-check SELECT
+// with a MySQL MAX_EXECUTION_TIME hint (processing.statement_timeout). 0 (the
+// default) sets no limit.
+func (dp *DeletePhase) SetStatementTimeout(timeout time.Duration) {
+	dp.stmtTimeout = timeout
+}
+
+// SetTransactional makes each Delete call run in one source transaction, so a
+// failure part-way through a root-PK group rolls back the group's deletes
+// instead of leaving it partially deleted. Off (auto-commit per chunk) by
+// default; enabling it holds row locks for the whole group, so keep
+// batch_size modest. The inter-chunk throttle sleep is skipped while on.
+func (dp *DeletePhase) SetTransactional(enabled bool) {
+	dp.transactional = enabled
+}
+
 // SetSleepSeconds sets the inter-chunk throttle pause (in seconds) applied
 // between delete chunks. 0 disables the throttle (the default). Negative values
 // are ignored. Use this to limit binlog generation / replication lag on the
@@ -261,3 +623,44 @@ func (dp *DeletePhase) SetSleepSeconds(s float64) {
 		dp.sleepSeconds = s
 	}
 }
+
+// SetRateLimiter makes each delete chunk wait for its rows on l
+// (processing.max_rows_per_second). nil disables the limit. Not applied in
+// transactional mode.
+func (dp *DeletePhase) SetRateLimiter(l *RowRateLimiter) {
+	dp.rateLimiter = l
+}
+
+// SetSortPKs makes the phase delete each table's PKs in ascending order
+// (processing.sort_delete_pks) instead of discovery order. Every DELETE
+// chunk of a table then takes its row locks in the same order as any other
+// sorted delete of that table, so concurrent runs over overlapping rows wait
+// on each other rather than deadlock (MySQL 1213). Ordering only holds
+// within one table; tables are still deleted in reverse dependency order.
+// Off by default.
+func (dp *DeletePhase) SetSortPKs(enabled bool) {
+	dp.sortPKs = enabled
+}
+
+// SetTableObserver registers fn, called with each table's name as the delete
+// starts on it (orchestrator progress snapshots). nil removes it.
+func (dp *DeletePhase) SetTableObserver(fn func(table string)) {
+	dp.onTable = fn
+}
+
+// SetForeignKeyChecksDisabled makes each Delete run on a dedicated source
+// connection with FOREIGN_KEY_CHECKS=0 (safety.disable_source_foreign_key_checks),
+// restored before the connection returns to the pool even when a delete
+// fails. ON DELETE CASCADE does not fire while checks are off, so it must not
+// be combined with SetCascadedTables. Off by default.
+func (dp *DeletePhase) SetForeignKeyChecksDisabled(disabled bool) {
+	dp.disableFKChecks = disabled
+}
+
+// SetCascadedTables sets the tables whose explicit DELETE is skipped because an
+// ON DELETE CASCADE foreign key from the mapped parent already removes their
+// rows (see PreflightChecker.CascadeDeletedTables). Skipped tables count toward
+// DeleteStats.TablesSkipped. A nil map disables skipping (the default).
+func (dp *DeletePhase) SetCascadedTables(cascaded map[string]string) {
+	dp.cascaded = cascaded
+}
@@ -0,0 +1,239 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// Delete backup formats (safety.delete_backup_format).
+const (
+	DeleteBackupJSON = "json"
+	DeleteBackupSQL  = "sql"
+)
+
+// deleteBackupEntry is one JSON line of a json-format delete backup: one full
+// source row. Binary column values are base64 encoded.
+type deleteBackupEntry struct {
+	Time  string                 `json:"ts"`
+	Job   string                 `json:"job"`
+	Table string                 `json:"table"`
+	Row   map[string]interface{} `json:"row"`
+}
+
+// DeleteBackup receives a copy of every source row the delete phase is about
+// to delete, read just before the first DELETE of each record set, so a
+// botched run can be restored from it independently of the destination. In
+// the json format each row is one JSON line; in the sql format one
+// INSERT statement. For files, each table's rows are fsynced before any row
+// is deleted.
+type DeleteBackup struct {
+	mu     sync.Mutex
+	w      io.Writer
+	file   *os.File // set when the backup owns a file; synced and closed by it
+	job    string
+	format string
+	now    func() time.Time
+}
+
+// NewDeleteBackup writes backup rows for job to w in format (DeleteBackupJSON
+// or DeleteBackupSQL). The caller owns w.
+func NewDeleteBackup(w io.Writer, job, format string) (*DeleteBackup, error) {
+	switch format {
+	case "", DeleteBackupJSON:
+		format = DeleteBackupJSON
+	case DeleteBackupSQL:
+	default:
+		return nil, fmt.Errorf("unsupported delete backup format %q", format)
+	}
+	return &DeleteBackup{w: w, job: job, format: format, now: time.Now}, nil
+}
+
+// OpenDeleteBackup opens (creating if needed) the backup file at path in
+// append mode, so successive runs extend the same backup.
+func OpenDeleteBackup(path, job, format string) (*DeleteBackup, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delete backup: %w", err)
+	}
+	b, err := NewDeleteBackup(f, job, format)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	b.file = f
+	return b, nil
+}
+
+// Close closes the file if the backup opened it. Safe to call more than once.
+func (b *DeleteBackup) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.file == nil {
+		return nil
+	}
+	err := b.file.Close()
+	b.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close delete backup: %w", err)
+	}
+	return nil
+}
+
+// writeRows writes every row of rows, read from table, and syncs the file.
+func (b *DeleteBackup) writeRows(table string, rows *sql.Rows) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	binary := make([]bool, len(cols))
+	if colTypes, err := rows.ColumnTypes(); err == nil && len(colTypes) == len(cols) {
+		for i, ct := range colTypes {
+			binary[i] = types.IsBinaryType(ct.DatabaseTypeName())
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			if raw, ok := v.([]byte); ok && !binary[i] {
+				values[i] = string(raw)
+			}
+		}
+		var line []byte
+		if b.format == DeleteBackupSQL {
+			line = []byte(backupInsert(table, cols, values))
+		} else {
+			row := make(map[string]interface{}, len(cols))
+			for i, col := range cols {
+				row[col] = values[i]
+			}
+			line, err = json.Marshal(deleteBackupEntry{
+				Time:  b.now().UTC().Format(time.RFC3339Nano),
+				Job:   b.job,
+				Table: table,
+				Row:   row,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to encode backup row: %w", err)
+			}
+		}
+		if _, err := b.w.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write delete backup: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if b.file != nil {
+		if err := b.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync delete backup: %w", err)
+		}
+	}
+	return nil
+}
+
+// backupInsert renders one row as an INSERT statement.
+func backupInsert(table string, cols []string, values []interface{}) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = sqlLiteral(v)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+		sqlutil.QuoteIdentifier(table), sqlutil.SelectList(cols), strings.Join(literals, ", "))
+}
+
+var sqlStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// sqlLiteral renders a scanned column value as a MySQL literal: NULL,
+// quoted and escaped strings, binary values as 0x hex, numbers as is.
+func sqlLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + sqlStringEscaper.Replace(val) + "'"
+	case []byte:
+		if len(val) == 0 {
+			return "''"
+		}
+		return "0x" + strings.ToUpper(hex.EncodeToString(val))
+	case bool:
+		if val {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return "'" + val.Format("2006-01-02 15:04:05.999999") + "'"
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	default:
+		return "'" + sqlStringEscaper.Replace(fmt.Sprint(val)) + "'"
+	}
+}
+
+// backupRecordSet copies every row of recordSet to the backup before any of
+// them is deleted. Tables whose rows an ON DELETE CASCADE removes are backed
+// up too. No-op without a backup.
+func (dp *DeletePhase) backupRecordSet(ctx context.Context, recordSet *RecordSet) error {
+	if dp.backup == nil {
+		return nil
+	}
+	deleteOrder, err := dp.graph.DeleteOrderContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get delete order: %w", err)
+	}
+	for _, table := range deleteOrder {
+		pks := uniquePKs(recordSet.Records[table])
+		if len(pks) == 0 {
+			continue
+		}
+		pkColumn := dp.graph.GetPK(table)
+		for _, chunk := range sqlutil.ChunkValues(pks, sqlutil.InClauseSize(dp.graph.BatchSizeFor(table, dp.batchSize), dp.maxIn)) {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("delete backup interrupted: %w", err)
+			}
+			query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN (%s)",
+				sqlutil.QuoteIdentifier(table), sqlutil.QuoteIdentifier(pkColumn), sqlutil.Placeholders(len(chunk), ", "))
+			rows, err := dp.db.QueryContext(ctx, query, chunk...)
+			if err != nil {
+				return fmt.Errorf("failed to read %s rows for delete backup: %w", table, err)
+			}
+			err = dp.backup.writeRows(table, rows)
+			_ = rows.Close() // Ignore error during cleanup
+			if err != nil {
+				return fmt.Errorf("failed to back up %s rows: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SetBackup writes every row this phase deletes to backup first. The caller
+// owns backup and closes it when the job ends. nil disables the backup (the
+// default).
+func (dp *DeletePhase) SetBackup(backup *DeleteBackup) {
+	dp.backup = backup
+}
@@ -0,0 +1,113 @@
+package archiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBackupTestPhase(t *testing.T) (*DeletePhase, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	g := graph.NewGraph("orders", "id")
+	g.AddNode("order_items", &graph.Node{Name: "order_items", ForeignKey: "order_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddEdge("orders", "order_items")
+	dp, err := NewDeletePhase(db, g, 500, logger.NewDefault())
+	require.NoError(t, err)
+	return dp, mock
+}
+
+// TestDelete_BackupWrittenBeforeAnyDelete proves every row of the record set
+// is in the backup before the first DELETE runs: the first DELETE fails, yet
+// both tables' rows are already backed up.
+func TestDelete_BackupWrittenBeforeAnyDelete(t *testing.T) {
+	dp, mock := newBackupTestPhase(t)
+	path := filepath.Join(t.TempDir(), "backup.jsonl")
+	backup, err := OpenDeleteBackup(path, "job1", DeleteBackupJSON)
+	require.NoError(t, err)
+	defer func() { _ = backup.Close() }()
+	dp.SetBackup(backup)
+
+	mock.ExpectQuery("SELECT \\* FROM `order_items` WHERE `id` IN \\(\\?, \\?\\)").
+		WithArgs(100, 101).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "sku"}).
+			AddRow(int64(100), int64(10), "A-1").
+			AddRow(int64(101), int64(10), nil))
+	mock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?\\)").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(int64(10), "closed"))
+	mock.ExpectExec("DELETE FROM `order_items`").
+		WillReturnError(fmt.Errorf("lock wait timeout"))
+
+	_, err = dp.Delete(context.Background(), &RecordSet{Records: map[string][]interface{}{
+		"orders":      {10},
+		"order_items": {100, 101},
+	}})
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	var backedUp []string
+	for _, line := range lines {
+		var entry deleteBackupEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, "job1", entry.Job)
+		backedUp = append(backedUp, fmt.Sprintf("%s:%v", entry.Table, entry.Row["id"]))
+	}
+	assert.Equal(t, []string{"order_items:100", "order_items:101", "orders:10"}, backedUp)
+}
+
+func TestDelete_BackupSQLFormat(t *testing.T) {
+	dp, mock := newBackupTestPhase(t)
+	var buf bytes.Buffer
+	backup, err := NewDeleteBackup(&buf, "job1", DeleteBackupSQL)
+	require.NoError(t, err)
+	dp.SetBackup(backup)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?\\)").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "note", "created_at", "paid"}).
+			AddRow(int64(10), "it's done", created, nil))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN \\(\\?\\)").
+		WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = dp.Delete(context.Background(), &RecordSet{Records: map[string][]interface{}{"orders": {10}}})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t,
+		"INSERT INTO `orders` (`id`, `note`, `created_at`, `paid`) VALUES (10, 'it\\'s done', '2024-01-02 03:04:05', NULL);\n",
+		buf.String())
+}
+
+func TestSQLLiteral(t *testing.T) {
+	assert.Equal(t, "NULL", sqlLiteral(nil))
+	assert.Equal(t, "42", sqlLiteral(int64(42)))
+	assert.Equal(t, "1.5", sqlLiteral(1.5))
+	assert.Equal(t, "0xDEAD", sqlLiteral([]byte{0xde, 0xad}))
+	assert.Equal(t, `'a\\b\nc'`, sqlLiteral("a\\b\nc"))
+}
+
+func TestNewDeleteBackup_RejectsUnknownFormat(t *testing.T) {
+	_, err := NewDeleteBackup(&bytes.Buffer{}, "job1", "csv")
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package archiver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// ErrMaxDeleteRowsExceeded is returned when a batch would take a run over
+// safety.max_delete_rows. Nothing of that batch has been deleted.
+var ErrMaxDeleteRowsExceeded = errors.New("safety.max_delete_rows exceeded")
+
+// ErrDeleteNotConfirmed is returned before a delete when
+// safety.require_confirmation is set and the job's confirm_token does not
+// match it. Nothing of that batch has been deleted.
+var ErrDeleteNotConfirmed = errors.New("deletes not confirmed by the job's confirm_token")
+
+// deleteCap enforces safety.max_delete_rows and safety.require_confirmation
+// over one run.
+type deleteCap struct {
+	max         int64 // 0 => no cap
+	override    bool  // --force-max-delete-rows
+	scheduled   int64 // discovered rows admitted for delete so far this run
+	unconfirmed error // confirmDeletes result for the run; nil => deletes allowed
+}
+
+// reset starts a new run capped at max rows.
+func (c *deleteCap) reset(max int64) {
+	c.max = max
+	c.scheduled = 0
+}
+
+// admit counts records against the cap, or returns ErrMaxDeleteRowsExceeded
+// without counting them when they would exceed it.
+func (c *deleteCap) admit(records *types.RecordSet) error {
+	var rows int64
+	if records != nil {
+		for _, pks := range records.Records {
+			rows += int64(len(pks))
+		}
+	}
+	if c.max > 0 && !c.override && c.scheduled+rows > c.max {
+		return fmt.Errorf("%w: batch of %d rows would bring this run to %d rows, above the cap of %d; check the job's where clause, or raise the cap or pass --force-max-delete-rows if this is intended",
+			ErrMaxDeleteRowsExceeded, rows, c.scheduled+rows, c.max)
+	}
+	c.scheduled += rows
+	return nil
+}
+
+// confirmed returns the run's ErrDeleteNotConfirmed, if any. Checked just
+// before each delete, after copy and verification.
+func (c *deleteCap) confirmed() error {
+	return c.unconfirmed
+}
+
+// confirmDeletes returns ErrDeleteNotConfirmed unless deletes for the job are
+// confirmed (or confirmation is not required). The error never carries the
+// expected token: it is printed by goarchive validate, so a run's logs
+// cannot be used to confirm it.
+func confirmDeletes(safety config.SafetyConfig, jobName string, job *config.JobConfig) error {
+	if !safety.RequireConfirmation {
+		return nil
+	}
+	if job.ConfirmToken == job.ExpectedConfirmToken(jobName) {
+		return nil
+	}
+	if job.ConfirmToken == "" {
+		return fmt.Errorf("%w: safety.require_confirmation is set and job %q has no confirm_token; run goarchive validate to print the job's token and set it as jobs.%s.confirm_token",
+			ErrDeleteNotConfirmed, jobName, jobName)
+	}
+	return fmt.Errorf("%w: jobs.%s.confirm_token does not match the job (the token changes with the job's where clause); run goarchive validate to print the current one",
+		ErrDeleteNotConfirmed, jobName)
+}
@@ -0,0 +1,136 @@
+package archiver
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// expectFullRootBatch sets up a whole processBatch of root 1 (order 10):
+// discovery, copy, verify, delete and completion.
+func expectFullRootBatch(p *hookTestPhases) {
+	expectGatedRootCopy(p.sourceMock, p.destMock, 1, 10, 1)
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("DELETE FROM `orders`").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("DELETE FROM `customers`").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectBegin()
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectCommit()
+}
+
+func TestProcessBatch_MaxDeleteRowsAbortsBeforeDelete(t *testing.T) {
+	hooks := &recordingHooks{}
+	p := newHookTestPhases(t, hooks)
+	p.o.deleteCap.reset(1)
+
+	// Discovery finds a customer and an order: 2 rows against a cap of 1.
+	// No copy, verify or delete statement is expected.
+	p.sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)))
+
+	err := p.processBatch(1)
+	require.ErrorIs(t, err, ErrMaxDeleteRowsExceeded)
+	require.Contains(t, err.Error(), "batch of 2 rows would bring this run to 2 rows, above the cap of 1")
+	require.Equal(t, []string{"before:discovery", "after:discovery"}, hooks.calls)
+	p.expectationsMet(t)
+}
+
+func TestProcessBatch_MaxDeleteRowsAllowsBatchUnderCap(t *testing.T) {
+	p := newHookTestPhases(t, nil)
+	p.o.deleteCap.reset(2)
+
+	expectFullRootBatch(p)
+	require.NoError(t, p.processBatch(1))
+	require.Equal(t, int64(2), p.o.deleteCap.scheduled)
+	p.expectationsMet(t)
+}
+
+func TestProcessBatch_ForceMaxDeleteRowsLiftsCap(t *testing.T) {
+	p := newHookTestPhases(t, nil)
+	p.o.deleteCap.reset(1)
+	p.o.SetForceMaxDeleteRows(true)
+
+	expectFullRootBatch(p)
+	require.NoError(t, p.processBatch(1))
+	p.expectationsMet(t)
+}
+
+func TestDeleteCap_CountsAcrossBatches(t *testing.T) {
+	var c deleteCap
+	c.reset(5)
+	batch := &RecordSet{Records: map[string][]interface{}{"customers": {1, 2}, "orders": {10}}}
+	require.NoError(t, c.admit(batch))
+	require.ErrorIs(t, c.admit(batch), ErrMaxDeleteRowsExceeded, "3 + 3 rows exceed 5")
+	require.Equal(t, int64(3), c.scheduled, "a rejected batch is not counted")
+
+	c.reset(0)
+	require.NoError(t, c.admit(batch), "0 means no cap")
+}
+
+func TestConfirmDeletes(t *testing.T) {
+	required := config.SafetyConfig{RequireConfirmation: true}
+	job := &config.JobConfig{RootTable: "customers", Where: "created_at < '2020-01-01'"}
+	token := job.ExpectedConfirmToken("job1")
+	require.Len(t, token, 12)
+
+	require.NoError(t, confirmDeletes(config.SafetyConfig{}, "job1", job), "confirmation not required")
+
+	err := confirmDeletes(required, "job1", job)
+	require.ErrorIs(t, err, ErrDeleteNotConfirmed)
+	require.Contains(t, err.Error(), "jobs.job1.confirm_token")
+	require.NotContains(t, err.Error(), token, "the error must not reveal the token")
+
+	job.ConfirmToken = "deadbeef0000"
+	err = confirmDeletes(required, "job1", job)
+	require.ErrorIs(t, err, ErrDeleteNotConfirmed)
+	require.NotContains(t, err.Error(), token, "the error must not reveal the token")
+
+	job.ConfirmToken = token
+	require.NoError(t, confirmDeletes(required, "job1", job))
+
+	// The token is per job and changes with the where clause.
+	require.ErrorIs(t, confirmDeletes(required, "job2", job), ErrDeleteNotConfirmed)
+	widened := &config.JobConfig{RootTable: "customers", Where: "created_at < '2030-01-01'", ConfirmToken: token}
+	require.ErrorIs(t, confirmDeletes(required, "job1", widened), ErrDeleteNotConfirmed)
+}
+
+func TestProcessBatch_UnconfirmedCopiesAndVerifiesButDoesNotDelete(t *testing.T) {
+	for name, token := range map[string]string{"missing": "", "wrong": "000000000000"} {
+		t.Run(name, func(t *testing.T) {
+			hooks := &recordingHooks{}
+			p := newHookTestPhases(t, hooks)
+			job := &config.JobConfig{RootTable: "customers", ConfirmToken: token}
+			p.o.deleteCap.unconfirmed = confirmDeletes(config.SafetyConfig{RequireConfirmation: true}, "job1", job)
+
+			// Copy and verify run and the batch is marked copied, so a
+			// confirmed run replays its delete. No DELETE is expected.
+			expectGatedRootCopy(p.sourceMock, p.destMock, 1, 10, 1)
+			p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+				WithArgs(LogStatusCopied, "1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := p.processBatch(1)
+			require.ErrorIs(t, err, ErrDeleteNotConfirmed)
+			require.NotContains(t, hooks.calls, "before:delete")
+			p.expectationsMet(t)
+		})
+	}
+}
+
+func TestProcessBatch_ConfirmedTokenDeletes(t *testing.T) {
+	job := &config.JobConfig{RootTable: "customers"}
+	job.ConfirmToken = job.ExpectedConfirmToken("job1")
+	p := newHookTestPhases(t, nil)
+	p.o.deleteCap.unconfirmed = confirmDeletes(config.SafetyConfig{RequireConfirmation: true}, "job1", job)
+
+	expectFullRootBatch(p)
+	require.NoError(t, p.processBatch(1))
+	p.expectationsMet(t)
+}
@@ -3,11 +3,16 @@ package archiver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/dbsmedya/goarchive/internal/logger"
 )
@@ -281,6 +286,41 @@ func TestDelete_SkipsEmptyTables(t *testing.T) {
 	}
 }
 
+func TestDelete_SkipsCascadedTables(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := createDeleteTestGraph()
+	dp, _ := NewDeletePhase(db, g, 500, logger.NewDefault())
+	dp.SetCascadedTables(map[string]string{"order_items": "orders"})
+
+	// order_items is removed by the orders CASCADE FK: no DELETE is issued for it.
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").
+		WithArgs(10, 11, 12, 13, 14, 15).
+		WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").
+		WithArgs(1, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	stats, err := dp.Delete(context.Background(), createDeleteRecordSet())
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if stats.TablesProcessed != 2 {
+		t.Errorf("Expected 2 tables processed, got %d", stats.TablesProcessed)
+	}
+	if stats.TablesSkipped != 1 {
+		t.Errorf("Expected 1 table skipped, got %d", stats.TablesSkipped)
+	}
+	if _, ok := stats.RowsPerTable["order_items"]; ok {
+		t.Error("Cascaded table should not report deleted rows")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
 func TestDelete_BatchProcessing(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer func() { _ = db.Close() }()
@@ -336,6 +376,87 @@ func TestDelete_BatchProcessing(t *testing.T) {
 	}
 }
 
+func TestDelete_StructuredLogFields(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	logPath := filepath.Join(t.TempDir(), "delete.log")
+	base, err := logger.New(&config.LoggingConfig{Level: "debug", Format: "json", Output: logPath, FileOnly: true})
+	if err != nil {
+		t.Fatalf("logger.New failed: %v", err)
+	}
+	log := base.WithJob("archive_orders").WithPhase("delete")
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 2, log)
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users":       {1},
+			"orders":      {10},
+			"order_items": {100, 101, 102},
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").
+		WithArgs(100, 101).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").
+		WithArgs(102).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").
+		WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := dp.Delete(context.Background(), recordSet); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	_ = base.Close()
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	batchEntries, tableEntries := 0, 0
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %q: %v", line, err)
+		}
+		if entry[logger.FieldJob] != "archive_orders" || entry[logger.FieldPhase] != "delete" {
+			t.Errorf("entry missing job/phase context: %v", entry)
+		}
+
+		var want []string
+		switch entry["msg"] {
+		case "Deleted batch":
+			batchEntries++
+			want = []string{logger.FieldTable, logger.FieldBatch, logger.FieldRows, logger.FieldDuration}
+		case "Deleted rows from table":
+			tableEntries++
+			want = []string{logger.FieldTable, logger.FieldRows, logger.FieldDuration}
+		}
+		for _, key := range want {
+			if _, ok := entry[key]; !ok {
+				t.Errorf("%q entry missing key %q: %v", entry["msg"], key, entry)
+			}
+		}
+	}
+
+	if batchEntries != 4 {
+		t.Errorf("expected 4 batch entries, got %d", batchEntries)
+	}
+	if tableEntries != 3 {
+		t.Errorf("expected 3 table entries, got %d", tableEntries)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestDelete_QueryError(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer func() { _ = db.Close() }()
@@ -729,3 +850,475 @@ func TestDelete_DeleteOrderError(t *testing.T) {
 		t.Error("Expected error for cyclic graph")
 	}
 }
+
+func TestDeleteTable_MaxInClauseSizeSplitsStatements(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 5000, logger.NewDefault())
+	dp.SetMaxInClauseSize(1000)
+
+	pks := make([]interface{}, 5000)
+	for i := range pks {
+		pks[i] = int64(i + 1)
+	}
+	for i := 0; i < 5; i++ {
+		mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").
+			WillReturnResult(sqlmock.NewResult(0, 1000))
+	}
+
+	deleted, err := dp.deleteTable(context.Background(), db, "order_items", pks)
+	if err != nil {
+		t.Fatalf("deleteTable failed: %v", err)
+	}
+	if deleted != 5000 {
+		t.Errorf("expected 5000 rows deleted across statements, got %d", deleted)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected exactly five DELETE statements: %v", err)
+	}
+}
+
+// ============================================================================
+// Transactional Delete Tests
+// ============================================================================
+
+func TestDelete_Transactional_CommitsGroup(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 1000, logger.NewDefault())
+	dp.SetTransactional(true)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	stats, err := dp.Delete(context.Background(), createDeleteRecordSet())
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if stats.RowsDeleted != 21 {
+		t.Errorf("Expected 21 rows deleted, got %d", stats.RowsDeleted)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDelete_Transactional_MidGroupFailureRollsBack(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 1000, logger.NewDefault())
+	dp.SetTransactional(true)
+
+	// order_items succeeds, orders fails: users must not be attempted and the
+	// order_items delete must be rolled back rather than committed.
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectRollback()
+
+	stats, err := dp.Delete(context.Background(), createDeleteRecordSet())
+	if err == nil {
+		t.Fatal("Expected error from mid-group failure")
+	}
+	if stats != nil {
+		t.Errorf("Expected nil stats on rollback, got %+v", stats)
+	}
+	if !strings.Contains(err.Error(), "orders") {
+		t.Errorf("Expected error to name the failing table, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDelete_Transactional_BeginFailure(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 1000, logger.NewDefault())
+	dp.SetTransactional(true)
+
+	mock.ExpectBegin().WillReturnError(errors.New("too many connections"))
+
+	if _, err := dp.Delete(context.Background(), createDeleteRecordSet()); err == nil {
+		t.Fatal("Expected error when BEGIN fails")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDelete_Transactional_SkipsThrottleSleep(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("users", "id")
+	dp, _ := NewDeletePhase(db, g, 2, logger.NewDefault())
+	dp.SetTransactional(true)
+	dp.SetSleepSeconds(5)
+	slept := 0
+	dp.sleepFn = func(context.Context, time.Duration) error { slept++; return nil }
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rs := &RecordSet{RootPKs: []interface{}{1, 2, 3}, Records: map[string][]interface{}{"users": {1, 2, 3}}}
+	if _, err := dp.Delete(context.Background(), rs); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if slept != 0 {
+		t.Errorf("Expected no throttle sleeps inside a transaction, got %d", slept)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+// ============================================================================
+// Orphan Check Tests
+// ============================================================================
+
+func createOrphanCheckGraph() *graph.Graph {
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", &graph.Node{Name: "orders", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	return g
+}
+
+// expectSchemaForeignKeys expects the orphan check's information_schema read
+// and returns fks as (table, column, referenced table, referenced column).
+func expectSchemaForeignKeys(mock sqlmock.Sqlmock, fks ...[4]string) {
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"})
+	for _, fk := range fks {
+		rows.AddRow(fk[0], fk[1], fk[2], fk[3])
+	}
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").WillReturnRows(rows)
+}
+
+func TestDelete_OrphanCheckClean(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createOrphanCheckGraph(), 500, logger.NewDefault())
+	dp.SetOrphanCheck(OrphanCheckAbort)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1, 2},
+		Records: map[string][]interface{}{
+			"users":  {1, 2},
+			"orders": {10, 11},
+		},
+	}
+
+	expectSchemaForeignKeys(mock, [4]string{"orders", "user_id", "users", "id"})
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").
+		WithArgs(10, 11).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	stats, err := dp.Delete(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if stats.RowsDeleted != 4 {
+		t.Errorf("Expected 4 rows deleted, got %d", stats.RowsDeleted)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Mock expectations not met: %v", err)
+	}
+}
+
+func TestDelete_OrphanCheckAbort(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createOrphanCheckGraph(), 500, logger.NewDefault())
+	dp.SetOrphanCheck(OrphanCheckAbort)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1, 2},
+		Records: map[string][]interface{}{
+			"users":  {1, 2},
+			"orders": {10},
+		},
+	}
+
+	// Order 12 was inserted after discovery.
+	expectSchemaForeignKeys(mock, [4]string{"orders", "user_id", "users", "id"})
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(12))
+
+	_, err := dp.Delete(context.Background(), recordSet)
+	var orphanErr *OrphanError
+	if !errors.As(err, &orphanErr) {
+		t.Fatalf("Expected *OrphanError, got %v", err)
+	}
+	if len(orphanErr.Reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(orphanErr.Reports))
+	}
+	r := orphanErr.Reports[0]
+	if r.ParentTable != "users" || r.ChildTable != "orders" || r.Count != 1 {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+	if !strings.Contains(err.Error(), "orders.user_id") {
+		t.Errorf("Expected error to name orders.user_id, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Mock expectations not met (no DELETE expected): %v", err)
+	}
+}
+
+func TestDelete_OrphanCheckWarnProceeds(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createOrphanCheckGraph(), 500, logger.NewDefault())
+	dp.SetOrphanCheck(OrphanCheckWarn)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users": {1},
+		},
+	}
+
+	expectSchemaForeignKeys(mock, [4]string{"orders", "user_id", "users", "id"})
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := dp.Delete(context.Background(), recordSet); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Mock expectations not met: %v", err)
+	}
+}
+
+func TestDelete_OrphanCheckUndeclaredForeignKeys(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	// The parent's batch size override does not apply to the check.
+	g := createOrphanCheckGraph()
+	g.GetNode("users").BatchSize = 1
+	dp, _ := NewDeletePhase(db, g, 500, logger.NewDefault())
+	dp.SetOrphanCheck(OrphanCheckAbort)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1, 2},
+		Records: map[string][]interface{}{
+			"users":  {1, 2},
+			"orders": {10, 11},
+		},
+	}
+
+	// orders.referrer_id and the ungraphed sessions.user_id also reference
+	// users.id; invoices.order_no references a non-PK column and is ignored.
+	expectSchemaForeignKeys(mock,
+		[4]string{"invoices", "order_no", "orders", "number"},
+		[4]string{"orders", "referrer_id", "users", "id"},
+		[4]string{"orders", "user_id", "users", "id"},
+		[4]string{"sessions", "user_id", "users", "id"},
+	)
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `referrer_id` IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11).AddRow(13))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `sessions` WHERE `user_id` IN \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	_, err := dp.Delete(context.Background(), recordSet)
+	var orphanErr *OrphanError
+	if !errors.As(err, &orphanErr) {
+		t.Fatalf("Expected *OrphanError, got %v", err)
+	}
+	if len(orphanErr.Reports) != 2 {
+		t.Fatalf("Expected 2 reports, got %+v", orphanErr.Reports)
+	}
+	if r := orphanErr.Reports[0]; r.ChildTable != "orders" || r.ForeignKey != "referrer_id" || r.Count != 1 {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+	if r := orphanErr.Reports[1]; r.ChildTable != "sessions" || r.Count != 3 || len(r.ChildPKs) != 0 {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+	if !strings.Contains(err.Error(), "3 row(s) in sessions.user_id reference users through a foreign key the job does not declare") {
+		t.Errorf("Expected error to name sessions.user_id, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Mock expectations not met (no DELETE expected): %v", err)
+	}
+}
+
+func TestDelete_RelationBatchSizeOverride(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", &graph.Node{Name: "orders", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N", BatchSize: 2})
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+
+	dp, _ := NewDeletePhase(db, g, 500, logger.NewDefault())
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1, 2, 3},
+		Records: map[string][]interface{}{
+			"users":  {1, 2, 3},
+			"orders": {10, 11, 12},
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN \\(\\?,\\?\\)").
+		WithArgs(10, 11).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN \\(\\?\\)").
+		WithArgs(12).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN \\(\\?,\\?,\\?\\)").
+		WithArgs(1, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if _, err := dp.Delete(context.Background(), recordSet); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Mock expectations not met: %v", err)
+	}
+}
+
+func TestDeleteTable_SortPKsEmitsAscendingBatches(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 3, logger.NewDefault())
+	dp.SetSortPKs(true)
+
+	pks := []interface{}{int64(9), []byte("10"), int64(2), int64(7), []byte("1"), int64(5), int64(3)}
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").
+		WithArgs([]byte("1"), int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").
+		WithArgs(int64(5), int64(7), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").
+		WithArgs([]byte("10")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	deleted, err := dp.deleteTable(context.Background(), db, "order_items", pks)
+	if err != nil {
+		t.Fatalf("deleteTable failed: %v", err)
+	}
+	if deleted != 7 {
+		t.Errorf("expected 7 rows deleted, got %d", deleted)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected batches in ascending PK order: %v", err)
+	}
+	if pks[0] != int64(9) {
+		t.Errorf("sorting must not reorder the caller's PK slice, got %v first", pks[0])
+	}
+}
+
+func TestSortedPKs_MixedTypes(t *testing.T) {
+	got := sortedPKs([]interface{}{uint64(1 << 63), int64(-1), "b", int64(4), "a", uint64(3)})
+	want := []interface{}{int64(-1), uint64(3), int64(4), uint64(1 << 63), "a", "b"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortedPKs = %v, want %v", got, want)
+		}
+	}
+}
+
+// ============================================================================
+// FOREIGN_KEY_CHECKS Tests
+// ============================================================================
+
+func TestDelete_ForeignKeyChecksBracketDeletes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 1000, logger.NewDefault())
+	dp.SetForeignKeyChecksDisabled(true)
+
+	mock.ExpectExec("SET SESSION FOREIGN_KEY_CHECKS = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("SET SESSION FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if _, err := dp.Delete(context.Background(), createDeleteRecordSet()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDelete_ForeignKeyChecksRestoredOnFailure(t *testing.T) {
+	for _, transactional := range []bool{false, true} {
+		db, mock, _ := sqlmock.New()
+
+		dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 1000, logger.NewDefault())
+		dp.SetForeignKeyChecksDisabled(true)
+		dp.SetTransactional(transactional)
+
+		mock.ExpectExec("SET SESSION FOREIGN_KEY_CHECKS = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+		if transactional {
+			mock.ExpectBegin()
+		}
+		mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 12))
+		mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WillReturnError(errors.New("lock wait timeout"))
+		if transactional {
+			mock.ExpectRollback()
+		}
+		// The session variable outlives the failed batch and the rollback.
+		mock.ExpectExec("SET SESSION FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if _, err := dp.Delete(context.Background(), createDeleteRecordSet()); err == nil {
+			t.Fatalf("transactional=%v: expected error from failed batch", transactional)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("transactional=%v: FOREIGN_KEY_CHECKS not restored: %v", transactional, err)
+		}
+		_ = db.Close()
+	}
+}
+
+func TestDelete_ForeignKeyChecksUntouchedByDefault(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 1000, logger.NewDefault())
+
+	// Any SET statement would be unexpected and fail the first DELETE.
+	mock.ExpectExec("DELETE FROM `order_items` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if _, err := dp.Delete(context.Background(), createDeleteRecordSet()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
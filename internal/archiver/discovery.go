@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/dbsmedya/goarchive/internal/logger"
 	"github.com/dbsmedya/goarchive/internal/sqlutil"
@@ -24,6 +25,10 @@ type RecordDiscovery struct {
 	db        *sql.DB
 	batchSize int
 	logger    *logger.Logger
+	estimate  bool // run EstimateCounts before each Discover
+	maxIn     int  // cap on values per IN (...) list; 0 => batchSize only
+	// stmtTimeout is the MAX_EXECUTION_TIME hint on every lookup; 0 => none
+	stmtTimeout time.Duration
 }
 
 // NewRecordDiscovery creates a new discovery service with the given dependency graph,
@@ -34,6 +39,9 @@ func NewRecordDiscovery(g *graph.Graph, db *sql.DB, batchSize int) (*RecordDisco
 	if g == nil {
 		return nil, fmt.Errorf("graph is nil")
 	}
+	if err := g.CheckIdentifiers(); err != nil {
+		return nil, fmt.Errorf("invalid identifier in graph: %w", err)
+	}
 	if batchSize <= 0 {
 		batchSize = 1000 // Default batch size for IN clause
 	}
@@ -76,42 +84,123 @@ func (d *RecordDiscovery) Discover(ctx context.Context, rootPKs []interface{}) (
 		Records: make(map[string][]interface{}),
 	}
 
-	// Add root table records
 	rootTable := d.graph.Root
-	result.Records[rootTable] = rootPKs
 
+	if d.estimate {
+		counts, err := d.EstimateCounts(ctx, rootPKs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate discovery: %w", err)
+		}
+		for _, n := range counts {
+			result.Stats.EstimatedRecords += n
+		}
+		d.logger.Debugf("Estimated %d records across %d tables", result.Stats.EstimatedRecords, len(counts))
+	}
+
+	maxLevel := 0
+	d.logger.Infof("Starting graph discovery from root table %q with %d PKs", rootTable, len(rootPKs))
+
+	err := d.walk(ctx, rootPKs, func(table string, pks []interface{}, level int) error {
+		result.Records[table] = pks
+		if level > maxLevel {
+			maxLevel = level
+		}
+		return nil
+	})
+	if err != nil {
+		if err == ctx.Err() {
+			result.Stats.Duration = time.Since(startTime)
+			d.logger.Warnf("Discovery interrupted: %v", err)
+			return result, err
+		}
+		return nil, err
+	}
+
+	// GA-P3-F2-T5: Populate statistics
+	result.Stats.TablesScanned = len(result.Records)
+	for _, pks := range result.Records {
+		result.Stats.RecordsFound += int64(len(pks))
+	}
+	result.Stats.BFSLevels = maxLevel + 1
+	result.Stats.Duration = time.Since(startTime)
+
+	// GA-P3-F2-T5: Log completion statistics
+	d.logger.Infow("Discovery complete",
+		"tables", result.Stats.TablesScanned,
+		logger.FieldRows, result.Stats.RecordsFound,
+		"levels", result.Stats.BFSLevels,
+		logger.FieldDuration, result.Stats.Duration.Milliseconds(),
+	)
+
+	return result, nil
+}
+
+// DiscoverStream is the streaming form of Discover: instead of returning a
+// RecordSet, it calls yield with each table's PKs in batches of at most the
+// discovery batch size. Tables are yielded in topological (copy) order, once
+// each and only after all of their parents, so a consumer can copy batches as
+// they arrive without violating FK order; iterate the batches in reverse table
+// order to delete. A table's PK set is dropped as soon as its children have
+// been discovered, so peak memory is bounded by the tables still waiting on
+// a parent rather than the whole tree. Returning an error from yield stops the
+// traversal and is returned unchanged. The pkBatch slice must not be retained
+// after yield returns if the caller needs memory to stay bounded. A batch can
+// be verified as it is with Verifier.VerifyBatches, as copy-only does.
+func (d *RecordDiscovery) DiscoverStream(ctx context.Context, rootPKs []interface{}, yield func(table string, pkBatch []interface{}) error) error {
+	if len(rootPKs) == 0 {
+		return nil
+	}
+	return d.walk(ctx, rootPKs, func(table string, pks []interface{}, _ int) error {
+		size := d.graph.BatchSizeFor(table, d.batchSize)
+		for i := 0; i < len(pks); i += size {
+			end := i + size
+			if end > len(pks) {
+				end = len(pks)
+			}
+			if err := yield(table, pks[i:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// walk traverses the graph in topological order starting from rootPKs and
+// calls visit once per table that has rows, with the table's complete,
+// deduplicated PK set and its BFS level (longest distance from the root).
+// Child PKs are accumulated across every parent edge before the child is
+// visited. Each table's PKs are released once its children are discovered.
+func (d *RecordDiscovery) walk(ctx context.Context, rootPKs []interface{}, visit func(table string, pks []interface{}, level int) error) error {
 	order, err := d.graph.CopyOrder()
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute discovery order: %w", err)
+		return fmt.Errorf("failed to compute discovery order: %w", err)
 	}
 
+	rootTable := d.graph.Root
+	pending := map[string][]interface{}{rootTable: rootPKs}
 	levels := map[string]int{rootTable: 0}
-	maxLevel := 0
-	d.logger.Infof("Starting graph discovery from root table %q with %d PKs", rootTable, len(rootPKs))
 
-	// One dedup set per child table, held for the whole traversal — child PKs
-	// arriving via different parent edges dedup without rebuilding a map per edge.
+	// One dedup set per child table, held until the table is visited — child
+	// PKs arriving via different parent edges dedup without rebuilding a map per edge.
 	seen := make(map[string]map[interface{}]struct{}, len(order))
 
 	// Process tables in topological order and accumulate child PKs across all parent paths.
 	for _, table := range order {
 		// Check for context cancellation (graceful shutdown)
-		select {
-		case <-ctx.Done():
-			result.Stats.Duration = time.Since(startTime)
-			d.logger.Warnf("Discovery interrupted: %v", ctx.Err())
-			return result, ctx.Err()
-		default:
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		parentPKs := result.Records[table]
+		parentPKs := pending[table]
+		delete(pending, table)
+		delete(seen, table)
 		if len(parentPKs) == 0 {
 			continue
 		}
 
 		level := levels[table]
-		if level > maxLevel {
-			maxLevel = level
+		if err := visit(table, parentPKs, level); err != nil {
+			return err
 		}
 
 		children := d.graph.GetChildren(table)
@@ -126,11 +215,12 @@ func (d *RecordDiscovery) Discover(ctx context.Context, rootPKs []interface{}) (
 		for _, childTable := range children {
 			// GA-P3-F2-T2: Fetch child IDs via database query
 			if d.db == nil {
-				return nil, fmt.Errorf("discovery database is nil")
+				return fmt.Errorf("discovery database is nil")
 			}
+			fetchStart := time.Now()
 			childPKs, err := d.fetchChildIDs(ctx, table, childTable, parentPKs)
 			if err != nil {
-				return nil, fmt.Errorf("failed to discover %s records: %w", childTable, err)
+				return fmt.Errorf("failed to discover %s records: %w", childTable, err)
 			}
 
 			if len(childPKs) == 0 {
@@ -138,11 +228,15 @@ func (d *RecordDiscovery) Discover(ctx context.Context, rootPKs []interface{}) (
 				continue
 			}
 
-			d.logger.Debugf("Discovered %d %s records from %d parent PKs",
-				len(childPKs), childTable, len(parentPKs))
+			d.logger.Debugw("Discovered child records",
+				logger.FieldTable, childTable,
+				"parent_table", table,
+				logger.FieldRows, len(childPKs),
+				logger.FieldDuration, time.Since(fetchStart).Milliseconds(),
+			)
 
-			set := tableSeen(seen, result.Records[childTable], childTable)
-			result.Records[childTable] = appendUnique(result.Records[childTable], childPKs, set)
+			set := tableSeen(seen, pending[childTable], childTable)
+			pending[childTable] = appendUnique(pending[childTable], childPKs, set)
 
 			nextLevel := level + 1
 			if current, ok := levels[childTable]; !ok || nextLevel > current {
@@ -151,23 +245,119 @@ func (d *RecordDiscovery) Discover(ctx context.Context, rootPKs []interface{}) (
 		}
 	}
 
-	// GA-P3-F2-T5: Populate statistics
-	result.Stats.TablesScanned = len(result.Records)
-	for _, pks := range result.Records {
-		result.Stats.RecordsFound += int64(len(pks))
+	return nil
+}
+
+// SetMaxInClauseSize caps the number of parent PKs bound into one
+// IN (...) list, independently of the batch size. Larger sets are split into
+// several queries whose results are combined. 0 (the default) leaves the cap
+// at the batch size.
+func (d *RecordDiscovery) SetMaxInClauseSize(n int) {
+	d.maxIn = n
+}
+
+// SetStatementTimeout bounds each discovery SELECT with a MySQL
+// MAX_EXECUTION_TIME hint (processing.statement_timeout). 0 (the default)
+// adds no hint.
+func (d *RecordDiscovery) SetStatementTimeout(timeout time.Duration) {
+	d.stmtTimeout = timeout
+}
+
+// SetEstimateCounts makes Discover run EstimateCounts first and record the
+// total in DiscoveryStats.EstimatedRecords, so progress can be reported
+// against an expected size. It costs one COUNT(*) per table per batch.
+func (d *RecordDiscovery) SetEstimateCounts(enabled bool) {
+	d.estimate = enabled
+}
+
+// EstimateCounts returns the number of rows each table would contribute for
+// rootPKs without materializing any PK lists. It walks the graph in the same
+// topological order as Discover, but instead of fetching child PKs it issues a
+// single SELECT COUNT(*) per table whose WHERE nests the parents' selections as
+// IN-subqueries:
+//
+//	SELECT COUNT(*) FROM order_items WHERE order_id IN
+//	    (SELECT id FROM orders WHERE user_id IN (?, ?))
+//
+// A table with several parents ORs one IN-subquery per parent edge, so rows
+// reachable by more than one path are counted once, as Discover dedups them.
+// The root table's count is len(rootPKs). Root PKs are chunked by the
+// discovery batch size (capped by SetMaxInClauseSize); a multi-parent row reached from different chunks is
+// counted once per chunk, so the result is an upper bound in that case.
+func (d *RecordDiscovery) EstimateCounts(ctx context.Context, rootPKs []interface{}) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(rootPKs) == 0 {
+		return counts, nil
+	}
+	if d.db == nil {
+		return nil, fmt.Errorf("discovery database is nil")
 	}
-	result.Stats.BFSLevels = maxLevel + 1
-	result.Stats.Duration = time.Since(startTime)
 
-	// GA-P3-F2-T5: Log completion statistics
-	d.logger.Infof("Discovery complete: %d tables, %d records, %d levels, duration: %s",
-		result.Stats.TablesScanned,
-		result.Stats.RecordsFound,
-		result.Stats.BFSLevels,
-		result.Stats.Duration,
-	)
+	order, err := d.graph.CopyOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute discovery order: %w", err)
+	}
 
-	return result, nil
+	rootTable := d.graph.Root
+	counts[rootTable] = int64(len(rootPKs))
+
+	type selection struct {
+		sql  string // IN-list body: placeholders for the root, a subquery otherwise
+		args []interface{}
+	}
+
+	for _, chunk := range sqlutil.ChunkValues(rootPKs, sqlutil.InClauseSize(d.batchSize, d.maxIn)) {
+		selections := map[string]selection{
+			rootTable: {sql: sqlutil.Placeholders(len(chunk), ", "), args: chunk},
+		}
+
+		for _, table := range order {
+			if table == rootTable {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			var conds []string
+			var args []interface{}
+			for _, parent := range d.graph.GetParents(table) {
+				parentSel, ok := selections[parent]
+				if !ok {
+					continue
+				}
+				meta := d.graph.GetEdgeMeta(parent, table)
+				if meta == nil {
+					return nil, fmt.Errorf("no edge metadata found for %s -> %s", parent, table)
+				}
+				cond, binds := childLookup(d.graph, table, meta.ForeignKey, parentSel.sql)
+				conds = append(conds, cond)
+				for i := 0; i < binds; i++ {
+					args = append(args, parentSel.args...)
+				}
+			}
+			if len(conds) == 0 {
+				continue
+			}
+			where := strings.Join(conds, " OR ")
+
+			var n int64
+			query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", sqlutil.QuoteIdentifier(table), where)
+			query = sqlutil.WithMaxExecutionTime(query, d.stmtTimeout)
+			if err := d.db.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+				return nil, fmt.Errorf("failed to estimate %s records: %w", table, err)
+			}
+			counts[table] += n
+
+			selections[table] = selection{
+				sql: fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+					sqlutil.QuoteIdentifier(d.graph.GetPK(table)), sqlutil.QuoteIdentifier(table), where),
+				args: args,
+			}
+		}
+	}
+
+	return counts, nil
 }
 
 // tableSeen returns the persistent dedup set for table, creating it on first
@@ -178,27 +368,42 @@ func tableSeen(seen map[string]map[interface{}]struct{}, existing []interface{},
 	if !ok {
 		m = make(map[interface{}]struct{}, len(existing))
 		for _, v := range existing {
-			m[v] = struct{}{}
+			m[types.PKKey(v)] = struct{}{}
 		}
 		seen[table] = m
 	}
 	return m
 }
 
-// appendUnique appends incoming PKs not already in seen. PK values are int64
-// or string (fetchChildIDs converts []byte), so they are valid map keys and
-// the map distinguishes types the same way the old "%T:%v" string keys did.
+// appendUnique appends incoming PKs not already in seen. Keys come from
+// types.PKKey, so binary ([]byte) PKs dedupe by content and the map still
+// distinguishes types the same way the old "%T:%v" string keys did.
 func appendUnique(existing, incoming []interface{}, seen map[interface{}]struct{}) []interface{} {
 	for _, v := range incoming {
-		if _, ok := seen[v]; ok {
+		key := types.PKKey(v)
+		if _, ok := seen[key]; ok {
 			continue
 		}
-		seen[v] = struct{}{}
+		seen[key] = struct{}{}
 		existing = append(existing, v)
 	}
 	return existing
 }
 
+// uniquePKs returns pks without repeated values (by types.PKKey), in first
+// occurrence order. pks itself is returned when it has no duplicates.
+func uniquePKs(pks []interface{}) []interface{} {
+	seen := make(map[interface{}]struct{}, len(pks))
+	for i, v := range pks {
+		key := types.PKKey(v)
+		if _, ok := seen[key]; ok {
+			return appendUnique(append([]interface{}(nil), pks[:i]...), pks[i+1:], seen)
+		}
+		seen[key] = struct{}{}
+	}
+	return pks
+}
+
 // fetchChildIDs queries the child table for all PKs that reference the parent PKs.
 //
 // GA-P3-F2-T2: Fetch child IDs using SQL queries with IN clause
@@ -212,8 +417,17 @@ func appendUnique(existing, incoming []interface{}, seen map[interface{}]struct{
 // so every returned value is already unique; cross-chunk dedup happens in
 // appendUnique.
 //
+// A relation with discovery_query runs that query instead, with each
+// {parent_pks} replaced by the chunk's placeholders.
+//
+// A child row whose foreign key is NULL references no parent: `fk IN (...)`
+// is never true for it, so it is not discovered and stays in the source.
+// NULL parent keys are dropped before the lookup for the same reason (see
+// CountNullForeignKeys to size such rows).
+//
 // For large parent PK sets, the query is chunked to avoid exceeding database limits.
 func (d *RecordDiscovery) fetchChildIDs(ctx context.Context, parentTable, childTable string, parentPKs []interface{}) ([]interface{}, error) {
+	parentPKs = withoutNulls(parentPKs)
 	if len(parentPKs) == 0 {
 		return []interface{}{}, nil
 	}
@@ -240,59 +454,172 @@ func (d *RecordDiscovery) fetchChildIDs(ctx context.Context, parentTable, childT
 
 	// Chunk parent PKs to avoid exceeding IN clause limits
 	// MySQL default max_allowed_packet is 64MB, but IN clause with 1000+ items can be slow
-	for i := 0; i < len(parentPKs); i += d.batchSize {
-		end := i + d.batchSize
-		if end > len(parentPKs) {
-			end = len(parentPKs)
-		}
-		chunk := parentPKs[i:end]
+	size := sqlutil.InClauseSize(d.graph.BatchSizeFor(childTable, d.batchSize), d.maxIn)
+	for n, chunk := range sqlutil.ChunkValues(parentPKs, size) {
+		i, end := n*size, n*size+len(chunk)
 
 		// child_pk is the table's PRIMARY KEY (preflight enforces a single-column PK),
 		// so every returned value is already unique; cross-chunk dedup happens in
 		// appendUnique.
-		placeholders := make([]string, len(chunk))
-		for j := range placeholders {
-			placeholders[j] = "?"
-		}
-
 		query := fmt.Sprintf(
 			"SELECT %s FROM %s WHERE %s IN (%s)",
 			sqlutil.QuoteIdentifier(childPK),
-			sqlutil.QuoteIdentifier(childTable),
+			d.fromClause(childTable),
 			sqlutil.QuoteIdentifier(foreignKey),
-			strings.Join(placeholders, ", "),
+			sqlutil.Placeholders(len(chunk), ", "),
 		)
+		args := chunk
+		if tmpl := d.discoveryQuery(childTable); tmpl != "" {
+			// The relation's own lookup, used verbatim apart from the parent
+			// PK placeholders (bound once per occurrence).
+			query = strings.ReplaceAll(tmpl, config.ParentPKsPlaceholder, sqlutil.Placeholders(len(chunk), ", "))
+			args = nil
+			for j := strings.Count(tmpl, config.ParentPKsPlaceholder); j > 0; j-- {
+				args = append(args, chunk...)
+			}
+		}
 
-		rows, err := d.db.QueryContext(ctx, query, chunk...)
+		rows, err := d.db.QueryContext(ctx, sqlutil.WithMaxExecutionTime(query, d.stmtTimeout), args...)
 		if err != nil {
 			return nil, fmt.Errorf("query failed for %s (chunk %d-%d): %w", childTable, i, end, err)
 		}
 
 		// Scan results
-		for rows.Next() {
-			var pk interface{}
-			if err := rows.Scan(&pk); err != nil {
-				_ = rows.Close() // Ignore error during cleanup of failed operation
-				return nil, fmt.Errorf("failed to scan %s PK: %w", childTable, err)
-			}
+		pks, err := scanPKs(rows)
+		_ = rows.Close() // Ignore error during cleanup
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s PKs: %w", childTable, err)
+		}
+		allChildPKs = append(allChildPKs, pks...)
+	}
 
-			// MySQL driver returns int64 for integers, []byte for strings
-			// Convert []byte to string for consistency
-			if b, ok := pk.([]byte); ok {
-				pk = string(b)
+	return allChildPKs, nil
+}
+
+// withoutNulls returns pks without nil values; pks itself when it has none.
+func withoutNulls(pks []interface{}) []interface{} {
+	for i, v := range pks {
+		if v != nil {
+			continue
+		}
+		kept := append([]interface{}(nil), pks[:i]...)
+		for _, v := range pks[i+1:] {
+			if v != nil {
+				kept = append(kept, v)
 			}
+		}
+		return kept
+	}
+	return pks
+}
 
-			allChildPKs = append(allChildPKs, pk)
+// CountNullForeignKeys counts, for every relation table, the rows whose
+// foreign key is NULL. Discovery never reaches them, so they are never
+// archived with a parent; the counts let operators notice optional relations
+// holding rows that no job will move. Tables with discovery_query are not
+// counted, and tables without NULL foreign keys are omitted.
+func (d *RecordDiscovery) CountNullForeignKeys(ctx context.Context) (map[string]int64, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("discovery database is nil")
+	}
+	counts := make(map[string]int64)
+	err := d.graph.ForEachInCopyOrderContext(ctx, func(table string) error {
+		node := d.graph.GetNode(table)
+		if table == d.graph.Root || node == nil || node.DiscoveryQuery != "" {
+			return nil
+		}
+		var conds []string
+		for _, parent := range d.graph.GetParents(table) {
+			if meta := d.graph.GetEdgeMeta(parent, table); meta != nil && meta.ForeignKey != "" {
+				conds = append(conds, sqlutil.QuoteIdentifier(meta.ForeignKey)+" IS NULL")
+			}
+		}
+		if len(conds) == 0 {
+			return nil
+		}
+		// A row of a table with several parents is unreachable only when
+		// every one of its foreign keys is NULL.
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s",
+			sqlutil.QuoteIdentifier(table), strings.Join(conds, " AND "))
+		var n int64
+		if err := d.db.QueryRowContext(ctx, sqlutil.WithMaxExecutionTime(query, d.stmtTimeout)).Scan(&n); err != nil {
+			return fmt.Errorf("failed to count NULL foreign keys in %s: %w", table, err)
+		}
+		if n > 0 {
+			counts[table] = n
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
 
-		if err := rows.Err(); err != nil {
-			_ = rows.Close() // Ignore error during cleanup of failed operation
-			return nil, fmt.Errorf("error iterating %s results: %w", childTable, err)
+// scanPKs reads a single-column PK result set. Values go through
+// types.NormalizePK: the MySQL driver returns []byte for every non-integer
+// column, so the column type decides whether a value is a binary PK (kept as
+// []byte) or a text/DECIMAL PK (converted to string).
+//
+// A result with any other column count is rejected before a row is read:
+// PK lookups must select exactly the key column, never SELECT *, so wide
+// rows (BLOB/TEXT payloads) are not fetched while traversing the graph.
+func scanPKs(rows *sql.Rows) ([]interface{}, error) {
+	if cols, err := rows.Columns(); err != nil {
+		return nil, err
+	} else if len(cols) != 1 {
+		return nil, fmt.Errorf("primary key lookup must select exactly the key column, got %d columns (%s)",
+			len(cols), strings.Join(cols, ", "))
+	}
+
+	binary := false
+	if cols, err := rows.ColumnTypes(); err == nil && len(cols) == 1 {
+		binary = types.IsBinaryType(cols[0].DatabaseTypeName())
+	}
+
+	var pks []interface{}
+	for rows.Next() {
+		var pk interface{}
+		if err := rows.Scan(&pk); err != nil {
+			return nil, err
 		}
-		_ = rows.Close() // Ignore error during cleanup of failed operation
+		pks = append(pks, types.NormalizePK(pk, binary))
 	}
+	return pks, rows.Err()
+}
 
-	return allChildPKs, nil
+// discoveryQuery returns the relation's discovery_query template for table,
+// or "" when its children are looked up by foreign key.
+func (d *RecordDiscovery) discoveryQuery(table string) string {
+	if node := d.graph.GetNode(table); node != nil {
+		return node.DiscoveryQuery
+	}
+	return ""
+}
+
+// childLookup returns the condition matching rows of child whose parent is in
+// parentSel (an IN-list body: placeholders or a subquery) and how many times
+// parentSel's arguments must be bound for it: `fk IN (parentSel)`, or
+// `child_pk IN (<discovery_query>)` with parentSel substituted for each
+// {parent_pks} when the relation sets discovery_query.
+func childLookup(g *graph.Graph, child, foreignKey, parentSel string) (string, int) {
+	node := g.GetNode(child)
+	if node == nil || node.DiscoveryQuery == "" {
+		return fmt.Sprintf("%s IN (%s)", sqlutil.QuoteIdentifier(foreignKey), parentSel), 1
+	}
+	return fmt.Sprintf("%s IN (%s)", sqlutil.QuoteIdentifier(g.GetPK(child)),
+			strings.ReplaceAll(node.DiscoveryQuery, config.ParentPKsPlaceholder, parentSel)),
+		strings.Count(node.DiscoveryQuery, config.ParentPKsPlaceholder)
+}
+
+// fromClause returns the quoted table reference for a discovery lookup on
+// table, with a MySQL FORCE INDEX hint when the relation sets use_index.
+func (d *RecordDiscovery) fromClause(table string) string {
+	from := sqlutil.QuoteIdentifier(table)
+	if node := d.graph.GetNode(table); node != nil && node.IndexHint != "" {
+		from += fmt.Sprintf(" FORCE INDEX (%s)", sqlutil.QuoteIdentifier(node.IndexHint))
+	}
+	return from
 }
 
 // SetLogger sets a custom logger for the discovery service.
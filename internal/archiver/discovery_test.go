@@ -2,12 +2,20 @@ package archiver
 
 import (
 	"context"
+	"database/sql/driver"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/verifier"
+	"github.com/stretchr/testify/require"
 )
 
 // ============================================================================
@@ -708,3 +716,648 @@ func TestTableSeen_SeedsFromExistingRecords(t *testing.T) {
 		t.Fatalf("expected persistent set with 3 keys on second call, got %d", len(same))
 	}
 }
+
+// expectEstimateCounts programs one COUNT(*) expectation per non-root table,
+// in any order, answering with counts[table].
+func expectEstimateCounts(mock sqlmock.Sqlmock, counts map[string]int) {
+	mock.MatchExpectationsInOrder(false)
+	for table, n := range counts {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `" + table + "` WHERE").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(n))
+	}
+}
+
+func TestEstimateCounts_MatchesDiscover(t *testing.T) {
+	g := createTestGraph()
+	rootPKs := []interface{}{int64(1), int64(2), int64(3)}
+
+	full, err := newSimulatedRecordDiscovery(t, g, 0, rootPKs...).Discover(context.Background(), rootPKs)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+	expected := map[string]int{}
+	for table, pks := range full.Records {
+		if table != g.Root {
+			expected[table] = len(pks)
+		}
+	}
+	expectEstimateCounts(mock, expected)
+
+	discovery, _ := NewRecordDiscovery(g, db, 0)
+	counts, err := discovery.EstimateCounts(context.Background(), rootPKs)
+	if err != nil {
+		t.Fatalf("EstimateCounts failed: %v", err)
+	}
+
+	for table, pks := range full.Records {
+		if counts[table] != int64(len(pks)) {
+			t.Errorf("table %s: estimate %d, discovered %d", table, counts[table], len(pks))
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestEstimateCounts_NestsParentSelections(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	// Diamond: D is reachable through B and C, so its count ORs both paths and
+	// repeats the root PK arguments for each.
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `B` WHERE `a_id` IN \\(\\?, \\?\\)$").
+		WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(4))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `C` WHERE `a_id` IN \\(\\?, \\?\\)$").
+		WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(2))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `D` WHERE "+
+		"`b_id` IN \\(SELECT `id` FROM `B` WHERE `a_id` IN \\(\\?, \\?\\)\\) OR "+
+		"`c_id` IN \\(SELECT `id` FROM `C` WHERE `a_id` IN \\(\\?, \\?\\)\\)$").
+		WithArgs(1, 2, 1, 2).WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(5))
+
+	discovery, _ := NewRecordDiscovery(createDiamondGraph(), db, 0)
+	counts, err := discovery.EstimateCounts(context.Background(), []interface{}{1, 2})
+	if err != nil {
+		t.Fatalf("EstimateCounts failed: %v", err)
+	}
+	if counts["A"] != 2 || counts["B"] != 4 || counts["C"] != 2 || counts["D"] != 5 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDiscover_EstimateCountsPopulatesStats(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", nil)
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(2))
+	mock.ExpectQuery("SELECT `id` FROM `orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+
+	discovery, _ := NewRecordDiscovery(g, db, 0)
+	discovery.SetEstimateCounts(true)
+	result, err := discovery.Discover(context.Background(), []interface{}{int64(1)})
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if result.Stats.EstimatedRecords != 3 || result.Stats.RecordsFound != 3 {
+		t.Errorf("expected 3 estimated and 3 found, got %+v", result.Stats)
+	}
+}
+
+func TestDiscoverStream_ParentsBeforeChildren(t *testing.T) {
+	g := createDiamondGraph()
+	rootPKs := []interface{}{"a1", "a2", "a3"}
+	discovery := newSimulatedRecordDiscovery(t, g, 2, rootPKs...)
+
+	var tables []string
+	streamed := map[string]int{}
+	err := discovery.DiscoverStream(context.Background(), rootPKs, func(table string, pkBatch []interface{}) error {
+		if len(pkBatch) == 0 || len(pkBatch) > 2 {
+			t.Errorf("batch for %s has %d PKs, want 1..2", table, len(pkBatch))
+		}
+		if len(tables) == 0 || tables[len(tables)-1] != table {
+			tables = append(tables, table)
+		}
+		streamed[table] += len(pkBatch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DiscoverStream failed: %v", err)
+	}
+
+	// Each table is yielded in one contiguous run, after all of its parents.
+	position := map[string]int{}
+	for i, table := range tables {
+		if _, dup := position[table]; dup {
+			t.Fatalf("table %s yielded in more than one run: %v", table, tables)
+		}
+		position[table] = i
+	}
+	for _, edge := range g.AllEdges() {
+		if position[edge.From] >= position[edge.To] {
+			t.Errorf("%s yielded before its parent %s: %v", edge.To, edge.From, tables)
+		}
+	}
+	if streamed["A"] != 3 {
+		t.Errorf("expected 3 root PKs streamed, got %d", streamed["A"])
+	}
+}
+
+func TestDiscoverStream_MatchesDiscover(t *testing.T) {
+	g := createTestGraph()
+	rootPKs := []interface{}{"user1", "user2", "user3"}
+
+	full, err := newSimulatedRecordDiscovery(t, g, 2, rootPKs...).Discover(context.Background(), rootPKs)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	streamed := map[string][]interface{}{}
+	err = newSimulatedRecordDiscovery(t, g, 2, rootPKs...).DiscoverStream(context.Background(), rootPKs,
+		func(table string, pkBatch []interface{}) error {
+			streamed[table] = append(streamed[table], pkBatch...)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("DiscoverStream failed: %v", err)
+	}
+
+	if len(streamed) != len(full.Records) {
+		t.Fatalf("streamed %d tables, Discover found %d", len(streamed), len(full.Records))
+	}
+	for table, pks := range full.Records {
+		if fmt.Sprint(streamed[table]) != fmt.Sprint(pks) {
+			t.Errorf("table %s: streamed %v, discovered %v", table, streamed[table], pks)
+		}
+	}
+}
+
+func TestDiscoverStream_StopsOnYieldError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	discovery, _ := NewRecordDiscovery(createTestGraph(), db, 0)
+	stop := fmt.Errorf("consumer full")
+	err := discovery.DiscoverStream(context.Background(), []interface{}{int64(1)}, func(string, []interface{}) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("expected yield error to be returned, got %v", err)
+	}
+	// No child queries after the root batch was rejected.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestFetchChildIDs_MaxInClauseSizeSplitsQueries(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	discovery, _ := NewRecordDiscovery(createTestGraph(), db, 5000)
+	discovery.SetMaxInClauseSize(1000)
+
+	parentPKs := make([]interface{}, 5000)
+	for i := range parentPKs {
+		parentPKs[i] = int64(i + 1)
+	}
+	for i := 0; i < 5; i++ {
+		rows := sqlmock.NewRows([]string{"id"})
+		for j := 0; j < 3; j++ {
+			rows.AddRow(int64(i*10 + j))
+		}
+		mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN").WillReturnRows(rows)
+	}
+
+	childPKs, err := discovery.fetchChildIDs(context.Background(), "users", "orders", parentPKs)
+	if err != nil {
+		t.Fatalf("fetchChildIDs failed: %v", err)
+	}
+	if len(childPKs) != 15 {
+		t.Errorf("expected 15 child PKs across five queries, got %d", len(childPKs))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected exactly five queries: %v", err)
+	}
+}
+
+func TestDiscover_IndexHint(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", &graph.Node{Name: "orders", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N", IndexHint: "idx_user_id"})
+	g.AddNode("profiles", &graph.Node{Name: "profiles", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-1"})
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("users", "profiles", "user_id", "id", "1-1")
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT `id` FROM `orders` FORCE INDEX \\(`idx_user_id`\\) WHERE `user_id` IN \\(\\?\\)$").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery("SELECT `id` FROM `profiles` WHERE `user_id` IN \\(\\?\\)$").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	if _, err := discovery.Discover(context.Background(), []interface{}{1}); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDiscover_DiscoveryQueryUsedVerbatim(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	// Polymorphic association: comments belong to orders only when
+	// commentable_type says so. The placeholder appears twice, so the parent
+	// PKs are bound twice.
+	tmpl := "SELECT `id` FROM `comments` WHERE `commentable_type` = 'Order' AND `commentable_id` IN ({parent_pks})" +
+		" UNION SELECT `id` FROM `comments` WHERE `legacy_order_id` IN ({parent_pks})"
+	g := graph.NewGraph("orders", "id")
+	g.AddNode("comments", &graph.Node{Name: "comments", ForeignKey: "commentable_id", ReferenceKey: "id",
+		DependencyType: "1-N", DiscoveryQuery: tmpl})
+	g.AddEdgeWithMeta("orders", "comments", "commentable_id", "id", "1-N")
+	g.SetPK("comments", "id")
+
+	mock.ExpectQuery("SELECT `id` FROM `comments` WHERE `commentable_type` = 'Order' AND `commentable_id` IN (?, ?)"+
+		" UNION SELECT `id` FROM `comments` WHERE `legacy_order_id` IN (?, ?)").
+		WithArgs(1, 2, 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	recordSet, err := discovery.Discover(context.Background(), []interface{}{1, 2})
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if got := recordSet.Records["comments"]; len(got) != 2 {
+		t.Errorf("comments = %v, want [10 11]", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestEstimateCounts_DiscoveryQuerySubquery(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("orders", "id")
+	g.AddNode("comments", &graph.Node{Name: "comments", ForeignKey: "commentable_id", ReferenceKey: "id",
+		DependencyType: "1-N",
+		DiscoveryQuery: "SELECT `id` FROM `comments` WHERE `commentable_type` = 'Order' AND `commentable_id` IN ({parent_pks})"})
+	g.AddEdgeWithMeta("orders", "comments", "commentable_id", "id", "1-N")
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM `comments` WHERE `id` IN "+
+		"(SELECT `id` FROM `comments` WHERE `commentable_type` = 'Order' AND `commentable_id` IN (?, ?))").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(3))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	counts, err := discovery.EstimateCounts(context.Background(), []interface{}{1, 2})
+	if err != nil {
+		t.Fatalf("EstimateCounts failed: %v", err)
+	}
+	if counts["comments"] != 3 {
+		t.Errorf("comments count = %d, want 3", counts["comments"])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDiscover_NullForeignKeysNotDiscovered(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("orders", "id")
+	g.AddNode("notes", &graph.Node{Name: "notes", ForeignKey: "order_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddNode("tags", &graph.Node{Name: "tags", ForeignKey: "note_id", ReferenceKey: "note_ref", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("orders", "notes", "order_id", "id", "1-N")
+	g.AddEdgeWithMeta("notes", "tags", "note_id", "note_ref", "1-N")
+
+	// notes holds (10, order 1), (11, order 2), (12, NULL) and (13, NULL):
+	// the lookup binds only the parent keys, so the NULL-FK rows never match.
+	mock.ExpectQuery("SELECT `id` FROM `notes` WHERE `order_id` IN (?, ?)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+	mock.ExpectQuery("SELECT `id` FROM `tags` WHERE `note_id` IN (?, ?)").
+		WithArgs(10, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	recordSet, err := discovery.Discover(context.Background(), []interface{}{1, 2})
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if got := recordSet.Records["notes"]; !reflect.DeepEqual(got, []interface{}{int64(10), int64(11)}) {
+		t.Errorf("notes = %v, want [10 11]", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+
+	// NULL parent keys are never bound: they cannot match any foreign key.
+	mock.ExpectQuery("SELECT `id` FROM `tags` WHERE `note_id` IN (?)").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if _, err := discovery.fetchChildIDs(context.Background(), "notes", "tags", []interface{}{nil, 10, nil}); err != nil {
+		t.Fatalf("fetchChildIDs failed: %v", err)
+	}
+	if pks, err := discovery.fetchChildIDs(context.Background(), "notes", "tags", []interface{}{nil}); err != nil || len(pks) != 0 {
+		t.Errorf("all-NULL parents: got %v, %v; want no query and no PKs", pks, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestCountNullForeignKeys(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	// Diamond A -> B, C -> D: D is unreachable only when both of its
+	// foreign keys are NULL. Tables without NULL foreign keys are omitted.
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT COUNT(*) FROM `B` WHERE `a_id` IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(3))
+	mock.ExpectQuery("SELECT COUNT(*) FROM `C` WHERE `a_id` IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(0))
+	mock.ExpectQuery("SELECT COUNT(*) FROM `D` WHERE `b_id` IS NULL AND `c_id` IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(1))
+
+	discovery, _ := NewRecordDiscovery(createDiamondGraph(), db, 100)
+	counts, err := discovery.CountNullForeignKeys(context.Background())
+	if err != nil {
+		t.Fatalf("CountNullForeignKeys failed: %v", err)
+	}
+	if want := map[string]int64{"B": 3, "D": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDiscover_SelectsOnlyPrimaryKeys(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("users", "id")
+	g.AddNode("documents", &graph.Node{Name: "documents", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddNode("pages", &graph.Node{Name: "pages", ForeignKey: "doc_id", ReferenceKey: "doc_id", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("users", "documents", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("documents", "pages", "doc_id", "doc_id", "1-N")
+	g.SetPK("documents", "doc_id")
+	g.SetPK("pages", "page_id")
+
+	// Exact matches: the select list is the child's PK column and nothing
+	// else, so BLOB payload columns are never read during traversal.
+	mock.ExpectQuery("SELECT `doc_id` FROM `documents` WHERE `user_id` IN (?)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"doc_id"}).AddRow(10))
+	mock.ExpectQuery("SELECT `page_id` FROM `pages` WHERE `doc_id` IN (?)").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"page_id"}).AddRow(100))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	if _, err := discovery.Discover(context.Background(), []interface{}{1}); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestScanPKs_RejectsWideResults(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectQuery("SELECT \\*").WillReturnRows(sqlmock.NewRows([]string{"id", "payload"}).AddRow(1, []byte("blob")))
+	rows, err := db.Query("SELECT * FROM documents")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if _, err := scanPKs(rows); err == nil || !strings.Contains(err.Error(), "got 2 columns (id, payload)") {
+		t.Errorf("expected a column-count error, got %v", err)
+	}
+}
+
+func TestDiscover_RelationBatchSizeOverride(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := graph.NewGraph("users", "id")
+	g.AddNode("documents", &graph.Node{Name: "documents", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N", BatchSize: 2})
+	g.AddNode("orders", &graph.Node{Name: "orders", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("users", "documents", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+
+	mock.MatchExpectationsInOrder(false)
+	// documents is chunked by its own batch_size of 2...
+	mock.ExpectQuery("SELECT `id` FROM `documents` WHERE `user_id` IN \\(\\?, \\?\\)$").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+	mock.ExpectQuery("SELECT `id` FROM `documents` WHERE `user_id` IN \\(\\?\\)$").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// ...while orders uses the discovery default.
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN \\(\\?, \\?, \\?\\)$").
+		WithArgs(1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	if _, err := discovery.Discover(context.Background(), []interface{}{1, 2, 3}); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestScanPKs_NormalizesByColumnType(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	uuidBin := []byte{0x11, 0xee, 0x8c, 0x90, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b}
+	mock.ExpectQuery("SELECT bin").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("BINARY", []byte{})).AddRow(uuidBin))
+	mock.ExpectQuery("SELECT txt").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("DECIMAL", []byte{})).AddRow([]byte("12.50")))
+
+	rows, err := db.Query("SELECT bin")
+	require.NoError(t, err)
+	pks, err := scanPKs(rows)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{uuidBin}, pks, "binary PKs stay []byte")
+
+	rows, err = db.Query("SELECT txt")
+	require.NoError(t, err)
+	pks, err = scanPKs(rows)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"12.50"}, pks, "DECIMAL PKs become strings")
+}
+
+// TestNonIntegerPKs_DiscoverVerifyDelete runs BINARY(16), CHAR(36) UUID and
+// DECIMAL child PKs through discovery, count and SHA256 verification, and
+// delete, checking each phase binds them back with the type it discovered.
+func TestNonIntegerPKs_DiscoverVerifyDelete(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	sourceMock.MatchExpectationsInOrder(false)
+	destMock.MatchExpectationsInOrder(false)
+
+	g := createTestGraph()
+	orderA := []byte{0x11, 0xee, 0x8c, 0x90, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	orderB := []byte{0x11, 0xee, 0x8c, 0x90, 0xff, 0xfe, 0xfd, 0xfc, 0, 0, 0, 0, 0, 0, 0, 1}
+	item := "6f1c2b9e-3a4d-4e5f-8a7b-1c2d3e4f5a6b"
+	profile := "12.50"
+
+	// Discovery: orders has a BINARY(16) PK (orderA comes back twice to
+	// exercise dedup), order_items a CHAR(36) UUID and profiles a DECIMAL.
+	sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRowsWithColumnDefinition(sqlmock.NewColumn("id").OfType("BINARY", []byte{})).
+			AddRow(orderA).AddRow(orderB).AddRow(orderA))
+	sourceMock.ExpectQuery("SELECT `id` FROM `profiles` WHERE `user_id` IN").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRowsWithColumnDefinition(sqlmock.NewColumn("id").OfType("DECIMAL", []byte{})).
+			AddRow([]byte(profile)))
+	sourceMock.ExpectQuery("SELECT `id` FROM `order_items` WHERE `order_id` IN").
+		WithArgs(orderA, orderB).
+		WillReturnRows(sqlmock.NewRowsWithColumnDefinition(sqlmock.NewColumn("id").OfType("CHAR", []byte{})).
+			AddRow([]byte(item)))
+
+	discovery, err := NewRecordDiscovery(g, sourceDB, 100)
+	require.NoError(t, err)
+	recordSet, err := discovery.Discover(context.Background(), []interface{}{int64(1)})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{orderA, orderB}, recordSet.Records["orders"], "binary PKs dedupe by content and stay []byte")
+	require.Equal(t, []interface{}{item}, recordSet.Records["order_items"])
+	require.Equal(t, []interface{}{profile}, recordSet.Records["profiles"])
+
+	args := map[string][]driver.Value{
+		"users":       {int64(1)},
+		"orders":      {orderA, orderB},
+		"order_items": {item},
+		"profiles":    {profile},
+	}
+	rows := map[string]func() *sqlmock.Rows{
+		"users": func() *sqlmock.Rows { return sqlmock.NewRows([]string{"id"}).AddRow(int64(1)) },
+		"orders": func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"id", "user_id"}).AddRow(orderA, 1).AddRow(orderB, 1)
+		},
+		"order_items": func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"id", "order_id"}).AddRow([]byte(item), orderA)
+		},
+		"profiles": func() *sqlmock.Rows { return sqlmock.NewRows([]string{"id", "user_id"}).AddRow([]byte(profile), 1) },
+	}
+	for table, a := range args {
+		count := strconv.Itoa(len(a))
+		for _, mock := range []sqlmock.Sqlmock{sourceMock, destMock} {
+			mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `" + table + "`").
+				WithArgs(a...).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+			mock.ExpectQuery("SELECT \\* FROM `" + table + "` WHERE `id` IN .* ORDER BY `id`").
+				WithArgs(a...).
+				WillReturnRows(rows[table]())
+		}
+	}
+
+	log := logger.NewDefault()
+	for _, method := range []verifier.VerificationMethod{verifier.MethodCount, verifier.MethodSHA256} {
+		v, err := verifier.NewVerifier(sourceDB, destDB, g, method, log)
+		require.NoError(t, err)
+		stats, err := v.Verify(context.Background(), recordSet)
+		require.NoError(t, err, "method %s", method)
+		require.Equal(t, 4, stats.TablesPassed, "method %s", method)
+	}
+
+	for table, a := range args {
+		sourceMock.ExpectExec("DELETE FROM `" + table + "` WHERE `id` IN").
+			WithArgs(a...).
+			WillReturnResult(sqlmock.NewResult(0, int64(len(a))))
+	}
+	dp, err := NewDeletePhase(sourceDB, g, 100, log)
+	require.NoError(t, err)
+	stats, err := dp.Delete(context.Background(), recordSet)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), stats.RowsDeleted)
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestDiamond_CopyAndDeleteEachPKOnce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	g := createDiamondGraph()
+
+	// D row 101 references both B 10 and C 20.
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT `id` FROM `B` WHERE `a_id` IN").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery("SELECT `id` FROM `C` WHERE `a_id` IN").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	mock.ExpectQuery("SELECT `id` FROM `D` WHERE `b_id` IN").WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100).AddRow(101))
+	mock.ExpectQuery("SELECT `id` FROM `D` WHERE `c_id` IN").WithArgs(20).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(101).AddRow(102))
+
+	discovery, _ := NewRecordDiscovery(g, db, 100)
+	recordSet, err := discovery.Discover(context.Background(), []interface{}{1})
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if got := recordSet.Records["D"]; len(got) != 3 {
+		t.Fatalf("expected D deduplicated to 3 PKs, got %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unfulfilled discovery expectations: %v", err)
+	}
+
+	// Even a record set that repeats a PK across chunks copies and deletes it once.
+	dupes := []interface{}{int64(100), int64(101), int64(101), int64(102)}
+
+	srcDB, srcMock, _ := sqlmock.New()
+	defer func() { _ = srcDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	cp, _ := NewCopyPhase(srcDB, destDB, g, config.SafetyConfig{}, logger.NewDefault())
+	cp.SetBatchSize(2)
+	cp.SetStrictInsert(true)
+	srcMock.ExpectQuery("SELECT \\* FROM `D` WHERE `id` IN \\(\\?, \\?\\)").WithArgs(int64(100), int64(101)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100).AddRow(101))
+	srcMock.ExpectQuery("SELECT \\* FROM `D` WHERE `id` IN \\(\\?\\)").WithArgs(int64(102)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(102))
+	destMock.ExpectBegin()
+	destMock.ExpectExec("INSERT INTO `D`").WithArgs(int64(100), int64(101)).WillReturnResult(sqlmock.NewResult(0, 2))
+	destMock.ExpectExec("INSERT INTO `D`").WithArgs(int64(102)).WillReturnResult(sqlmock.NewResult(0, 1))
+	tx, _ := destDB.Begin()
+	copied, skipped, err := cp.copyTable(context.Background(), tx, "D", dupes)
+	if err != nil || copied != 3 || skipped != 0 {
+		t.Errorf("copyTable = %d copied, %d skipped, %v; want 3, 0, nil", copied, skipped, err)
+	}
+	if err := srcMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled copy source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled copy destination expectations: %v", err)
+	}
+
+	delDB, delMock, _ := sqlmock.New()
+	defer func() { _ = delDB.Close() }()
+	dp, _ := NewDeletePhase(delDB, g, 2, logger.NewDefault())
+	delMock.ExpectExec("DELETE FROM `D` WHERE `id` IN \\(\\?,\\?\\)").WithArgs(int64(100), int64(101)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	delMock.ExpectExec("DELETE FROM `D` WHERE `id` IN \\(\\?\\)").WithArgs(int64(102)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	deleted, err := dp.deleteTable(context.Background(), delDB, "D", dupes)
+	if err != nil || deleted != 3 {
+		t.Errorf("deleteTable = %d, %v; want 3, nil", deleted, err)
+	}
+	if err := delMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled delete expectations: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EstimateArchiveSize returns the on-disk size (DATA_LENGTH + INDEX_LENGTH from
+// information_schema.TABLES) of every graph table in the source schema, and
+// their sum. It is an upper bound: it assumes every row matches the job's
+// WHERE, which is the safe side for a free-space check. InnoDB statistics are
+// approximate, so treat the result as an estimate rather than an exact size.
+func (p *PreflightChecker) EstimateArchiveSize(ctx context.Context) (int64, map[string]int64, error) {
+	tables := p.graph.AllNodes()
+	if len(tables) == 0 {
+		return 0, map[string]int64{}, nil
+	}
+
+	query := `
+		SELECT TABLE_NAME, COALESCE(DATA_LENGTH, 0) + COALESCE(INDEX_LENGTH, 0)
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ?
+		AND TABLE_NAME IN (?)`
+
+	placeholders := make([]string, len(tables))
+	args := make([]interface{}, 0, len(tables)+1)
+	args = append(args, p.sourceDBName)
+	for i, table := range tables {
+		placeholders[i] = "?"
+		args = append(args, table)
+	}
+	query = strings.Replace(query, "(?)", "("+strings.Join(placeholders, ",")+")", 1)
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query table sizes: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			p.logger.Warnf("Failed to close rows: %v", err)
+		}
+	}()
+
+	var total int64
+	perTable := make(map[string]int64, len(tables))
+	for rows.Next() {
+		var table string
+		var size int64
+		if err := rows.Scan(&table, &size); err != nil {
+			return 0, nil, err
+		}
+		perTable[table] = size
+		total += size
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	return total, perTable, nil
+}
+
+// SetDiskSpaceLimit configures CheckDestinationFreeSpace: freeBytes is the
+// space available to the destination (0 disables the check, since MySQL does
+// not expose filesystem free space), and margin multiplies the size estimate
+// to leave headroom for binlogs, undo, and index growth.
+func (p *PreflightChecker) SetDiskSpaceLimit(freeBytes int64, margin float64) {
+	p.diskFreeBytes = freeBytes
+	p.diskSpaceMargin = margin
+}
+
+// CheckDestinationFreeSpace fails with DISK_SPACE_CHECK when the estimated
+// archive size, scaled by the configured margin, exceeds the destination free
+// space set via SetDiskSpaceLimit. This stops a job before it fills the
+// destination mid-run. A no-op when no free space is configured.
+func (p *PreflightChecker) CheckDestinationFreeSpace(ctx context.Context) error {
+	if p.diskFreeBytes <= 0 {
+		return nil
+	}
+	p.logger.Debug("Checking destination free space...")
+
+	total, perTable, err := p.EstimateArchiveSize(ctx)
+	if err != nil {
+		return err
+	}
+
+	margin := p.diskSpaceMargin
+	if margin < 1 {
+		margin = 1
+	}
+	required := int64(float64(total) * margin)
+	if required > p.diskFreeBytes {
+		tables := make([]string, 0, len(perTable))
+		for table := range perTable {
+			tables = append(tables, table)
+		}
+		sort.Slice(tables, func(i, j int) bool { return perTable[tables[i]] > perTable[tables[j]] })
+		details := make([]string, len(tables))
+		for i, table := range tables {
+			details[i] = fmt.Sprintf("%s(%d bytes)", table, perTable[table])
+		}
+		return &PreflightError{
+			Check: "DISK_SPACE_CHECK",
+			Message: fmt.Sprintf("Estimated archive size %d bytes x margin %.2f = %d bytes exceeds destination free space %d bytes",
+				total, margin, required, p.diskFreeBytes),
+			Tables: details,
+		}
+	}
+
+	p.logger.Debugf("Destination free space check PASSED (estimated %d bytes, %d bytes free)", required, p.diskFreeBytes)
+	return nil
+}
@@ -0,0 +1,89 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+func expectTableSizes(mock sqlmock.Sqlmock, sizes map[string]int64) {
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "size"})
+	for _, table := range []string{"users", "orders", "order_items"} {
+		if size, ok := sizes[table]; ok {
+			rows.AddRow(table, size)
+		}
+	}
+	mock.ExpectQuery("SELECT TABLE_NAME, COALESCE\\(DATA_LENGTH, 0\\)").
+		WillReturnRows(rows)
+}
+
+func TestEstimateArchiveSize_SumsTables(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	checker, _ := NewPreflightChecker(db, "testdb", createPreflightTestGraph(), logger.NewDefault())
+	expectTableSizes(mock, map[string]int64{"users": 1000, "orders": 5000, "order_items": 20000})
+
+	total, perTable, err := checker.EstimateArchiveSize(context.Background())
+	if err != nil {
+		t.Fatalf("EstimateArchiveSize failed: %v", err)
+	}
+	if total != 26000 {
+		t.Errorf("expected total 26000, got %d", total)
+	}
+	if perTable["orders"] != 5000 || len(perTable) != 3 {
+		t.Errorf("unexpected per-table sizes: %v", perTable)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCheckDestinationFreeSpace_Exceeded(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	checker, _ := NewPreflightChecker(db, "testdb", createPreflightTestGraph(), logger.NewDefault())
+	checker.SetDiskSpaceLimit(30000, 1.2)
+	expectTableSizes(mock, map[string]int64{"users": 1000, "orders": 5000, "order_items": 20000})
+
+	// 26000 * 1.2 = 31200 > 30000
+	err := checker.CheckDestinationFreeSpace(context.Background())
+	var pfErr *PreflightError
+	if !errors.As(err, &pfErr) || pfErr.Check != "DISK_SPACE_CHECK" {
+		t.Fatalf("expected DISK_SPACE_CHECK error, got %v", err)
+	}
+	if len(pfErr.Tables) != 3 || pfErr.Tables[0] != "order_items(20000 bytes)" {
+		t.Errorf("expected tables sorted by size, got %v", pfErr.Tables)
+	}
+}
+
+func TestCheckDestinationFreeSpace_WithinLimit(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	checker, _ := NewPreflightChecker(db, "testdb", createPreflightTestGraph(), logger.NewDefault())
+	checker.SetDiskSpaceLimit(40000, 1.2)
+	expectTableSizes(mock, map[string]int64{"users": 1000, "orders": 5000, "order_items": 20000})
+
+	if err := checker.CheckDestinationFreeSpace(context.Background()); err != nil {
+		t.Fatalf("expected check to pass, got %v", err)
+	}
+}
+
+func TestCheckDestinationFreeSpace_DisabledByDefault(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	checker, _ := NewPreflightChecker(db, "testdb", createPreflightTestGraph(), logger.NewDefault())
+
+	if err := checker.CheckDestinationFreeSpace(context.Background()); err != nil {
+		t.Fatalf("expected no-op, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no queries: %v", err)
+	}
+}
@@ -136,13 +136,14 @@ func (e *Estimator) estimateChildCount(ctx context.Context, table string) (int64
 		sqlutil.QuoteIdentifier(hops[len(hops)-1].ref),
 		sqlutil.QuoteIdentifier(e.graph.Root), where)
 	for i := len(hops) - 2; i >= 0; i-- {
-		sub = fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)",
+		cond, _ := childLookup(e.graph, hops[i].parent, hops[i+1].fk, sub)
+		sub = fmt.Sprintf("SELECT %s FROM %s WHERE %s",
 			sqlutil.QuoteIdentifier(hops[i].ref),
-			sqlutil.QuoteIdentifier(hops[i].parent),
-			sqlutil.QuoteIdentifier(hops[i+1].fk), sub)
+			sqlutil.QuoteIdentifier(hops[i].parent), cond)
 	}
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IN (%s)",
-		sqlutil.QuoteIdentifier(table), sqlutil.QuoteIdentifier(hops[0].fk), sub)
+	cond, _ := childLookup(e.graph, table, hops[0].fk, sub)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s",
+		sqlutil.QuoteIdentifier(table), cond)
 
 	var count int64
 	if err := e.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
@@ -228,7 +229,8 @@ func (e *Estimator) DisplayExecutionPlan(result *EstimateResult) {
 		fmt.Print(" (job-specific)")
 	}
 	fmt.Println()
-	fmt.Printf("  Foreign key checks: %v\n", !e.cfg.Safety.DisableForeignKeyChecks)
+	fmt.Printf("  Foreign key checks: %v (destination), %v (source)\n",
+		!e.cfg.Safety.DisableForeignKeyChecks, !e.cfg.Safety.DisableSourceForeignKeyChecks)
 
 	if result.Config.Replica.Enabled {
 		fmt.Printf("  Replication lag monitoring: enabled\n")
@@ -0,0 +1,146 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// ExistingFilter removes from a record set the PKs the destination already
+// holds (processing.skip_existing), so repeated archive windows over
+// already-archived rows only copy what is missing. The filtered set is used
+// for the copy alone: verification and delete still cover every discovered
+// PK, and SHA256 verification proves the skipped rows match the source.
+type ExistingFilter struct {
+	destDB    *sql.DB
+	graph     *graph.Graph
+	batchSize int // lookup chunk size; 0 => one IN list per table up to maxIn
+	maxIn     int // cap on values per IN (...) list; 0 => batchSize only
+	logger    *logger.Logger
+}
+
+// ExistingFilterStats reports how many discovered rows were already on the
+// destination.
+type ExistingFilterStats struct {
+	RowsExisting     int64
+	ExistingPerTable map[string]int64 // only tables with existing rows
+}
+
+// NewExistingFilter creates a destination lookup filter.
+func NewExistingFilter(destDB *sql.DB, g *graph.Graph, log *logger.Logger) (*ExistingFilter, error) {
+	if destDB == nil {
+		return nil, fmt.Errorf("destination database is nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("graph is nil")
+	}
+	if log == nil {
+		log = logger.NewDefault()
+	}
+	return &ExistingFilter{destDB: destDB, graph: g, logger: log}, nil
+}
+
+// SetBatchSize sets the lookup chunk size. Per-table batch_size overrides
+// still apply.
+func (f *ExistingFilter) SetBatchSize(n int) {
+	f.batchSize = n
+}
+
+// SetMaxInClauseSize caps the number of PKs bound into one IN (...) list.
+// 0 disables the cap.
+func (f *ExistingFilter) SetMaxInClauseSize(n int) {
+	f.maxIn = n
+}
+
+// Filter returns a copy of recordSet without the PKs found on the
+// destination. recordSet itself is not modified. Tables whose every PK
+// exists are dropped from the returned set.
+func (f *ExistingFilter) Filter(ctx context.Context, recordSet *RecordSet) (*RecordSet, *ExistingFilterStats, error) {
+	stats := &ExistingFilterStats{ExistingPerTable: make(map[string]int64)}
+	out := &RecordSet{
+		RootPKs: recordSet.RootPKs,
+		Records: make(map[string][]interface{}, len(recordSet.Records)),
+		Stats:   recordSet.Stats,
+	}
+
+	copyOrder, err := f.graph.CopyOrderContext(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get copy order: %w", err)
+	}
+	for _, table := range copyOrder {
+		pks := recordSet.Records[table]
+		if len(pks) == 0 {
+			continue
+		}
+		existing, err := f.existingPKs(ctx, table, pks)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(existing) == 0 {
+			out.Records[table] = pks
+			continue
+		}
+
+		missing := make([]interface{}, 0, len(pks)-len(existing))
+		for _, pk := range pks {
+			key, err := types.EncodePK(pk)
+			if err != nil {
+				return nil, nil, fmt.Errorf("table %s: %w", table, err)
+			}
+			if !existing[key] {
+				missing = append(missing, pk)
+			}
+		}
+		if skipped := int64(len(pks) - len(missing)); skipped > 0 {
+			stats.RowsExisting += skipped
+			stats.ExistingPerTable[table] = skipped
+		}
+		if len(missing) > 0 {
+			out.Records[table] = missing
+		}
+	}
+
+	if stats.RowsExisting > 0 {
+		f.logger.Infow("Skipping rows already on destination",
+			logger.FieldRows, stats.RowsExisting,
+			"per_table", stats.ExistingPerTable,
+		)
+	}
+	return out, stats, nil
+}
+
+// existingPKs returns the canonical text form (types.EncodePK) of every PK in
+// pks that the destination table holds. Matching on text keeps a root PK
+// converted to uint64 equal to the int64 the driver scans back.
+func (f *ExistingFilter) existingPKs(ctx context.Context, table string, pks []interface{}) (map[string]bool, error) {
+	pkColumn := f.graph.GetPK(table)
+	found := make(map[string]bool)
+	size := sqlutil.InClauseSize(f.graph.BatchSizeFor(table, f.batchSize), f.maxIn)
+	for _, chunk := range sqlutil.ChunkValues(pks, size) {
+		query := selectByPKQuery(sqlutil.QuoteIdentifier(pkColumn), table, pkColumn, sqlutil.Placeholders(len(chunk), ","))
+		rows, err := f.destDB.QueryContext(ctx, query, chunk...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up existing %s rows on destination: %w", table, err)
+		}
+		scanned, err := scanPKs(rows)
+		if closeErr := rows.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read existing %s rows on destination: %w", table, err)
+		}
+		for _, pk := range scanned {
+			key, err := types.EncodePK(pk)
+			if err != nil {
+				return nil, fmt.Errorf("table %s: %w", table, err)
+			}
+			found[key] = true
+		}
+	}
+	return found, nil
+}
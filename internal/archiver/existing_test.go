@@ -0,0 +1,120 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExistingFilter_Validation(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	_, err := NewExistingFilter(nil, createSimpleGraph(), nil)
+	require.Error(t, err)
+	_, err = NewExistingFilter(db, nil, nil)
+	require.Error(t, err)
+	f, err := NewExistingFilter(db, createSimpleGraph(), nil)
+	require.NoError(t, err)
+	require.NotNil(t, f.logger)
+}
+
+func TestExistingFilter_DropsPKsOnDestination(t *testing.T) {
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	f, err := NewExistingFilter(destDB, createMultiLevelGraph(), logger.NewDefault())
+	require.NoError(t, err)
+	f.SetMaxInClauseSize(2)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{int64(1)},
+		Records: map[string][]interface{}{
+			"customers": {int64(1)},
+			"orders":    {int64(10), int64(11), int64(12), int64(13)},
+		},
+	}
+
+	// The customer and half of its orders were archived by an earlier window.
+	destMock.ExpectQuery("SELECT `id` FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+	destMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `id` IN \\(\\?,\\?\\)").
+		WithArgs(int64(10), int64(11)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)))
+	destMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `id` IN \\(\\?,\\?\\)").
+		WithArgs(int64(12), int64(13)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(12)))
+
+	filtered, stats, err := f.Filter(context.Background(), recordSet)
+	require.NoError(t, err)
+	require.NotContains(t, filtered.Records, "customers")
+	require.Equal(t, []interface{}{int64(11), int64(13)}, filtered.Records["orders"])
+	require.Equal(t, int64(3), stats.RowsExisting)
+	require.Equal(t, map[string]int64{"customers": 1, "orders": 2}, stats.ExistingPerTable)
+
+	// The input set is untouched: verify and delete still see every PK.
+	require.Len(t, recordSet.Records["orders"], 4)
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestCopyPhase_ExistingFilterCopiesOnlyMissing(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createMultiLevelGraph()
+	log := logger.NewDefault()
+	cp, err := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	require.NoError(t, err)
+	f, err := NewExistingFilter(destDB, g, log)
+	require.NoError(t, err)
+	cp.SetExistingFilter(f)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{int64(1), int64(2)},
+		Records: map[string][]interface{}{
+			"customers": {int64(1), int64(2)},
+			"orders":    {int64(10), int64(11), int64(20), int64(21)},
+		},
+	}
+
+	destMock.ExpectQuery("SELECT `id` FROM `customers` WHERE `id` IN").
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+	destMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `id` IN").
+		WithArgs(int64(10), int64(11), int64(20), int64(21)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)).AddRow(int64(11)))
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(2), "b"))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").
+		WithArgs(int64(2), "b").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?, \\?\\)").
+		WithArgs(int64(20), int64(21)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id"}).
+			AddRow(int64(20), int64(2)).
+			AddRow(int64(21), int64(2)))
+	destMock.ExpectExec("INSERT IGNORE INTO `orders`").
+		WithArgs(int64(20), int64(2), int64(21), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	destMock.ExpectCommit()
+
+	stats, err := cp.Copy(context.Background(), recordSet)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stats.RowsCopied)
+	require.Equal(t, int64(3), stats.RowsSkipped)
+	require.Equal(t, map[string]int64{"customers": 1, "orders": 2}, stats.SkippedPerTable)
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
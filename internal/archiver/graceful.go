@@ -15,11 +15,100 @@ package archiver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dbsmedya/goarchive/internal/logger"
 )
 
+// StopMode selects what an orchestrator does with the in-flight batch when a
+// cooperative stop is requested (the stop channel closes on the first signal).
+type StopMode int
+
+const (
+	// StopAfterBatch lets the in-flight batch run to a terminal state — copy,
+	// verify, delete and the checkpoint commit — and stops at the next batch
+	// boundary. This is the default.
+	StopAfterBatch StopMode = iota
+	// StopImmediately cancels the in-flight batch as soon as stop is requested.
+	// The batch's roots are left pending (or copied) and the checkpoint stays
+	// at the last completed batch; status-aware replay finishes them on the
+	// next run. Use it when the shutdown grace period is shorter than a batch.
+	StopImmediately
+)
+
+// String returns the --stop-mode flag value for m.
+func (m StopMode) String() string {
+	if m == StopImmediately {
+		return "immediate"
+	}
+	return "finish-batch"
+}
+
+// ParseStopMode parses a --stop-mode flag value ("finish-batch" or
+// "immediate"). An empty string selects StopAfterBatch.
+func ParseStopMode(s string) (StopMode, error) {
+	switch s {
+	case "", "finish-batch":
+		return StopAfterBatch, nil
+	case "immediate":
+		return StopImmediately, nil
+	default:
+		return StopAfterBatch, fmt.Errorf("invalid stop mode %q: must be 'finish-batch' or 'immediate'", s)
+	}
+}
+
+// batchContext returns the context for one batch (or one root in the copy-only
+// and purge loops). Under StopImmediately it is canceled as soon as stop
+// closes; otherwise ctx is returned unchanged and only a hard stop (ctx
+// cancel) interrupts the batch. The returned release func must be called
+// when the batch ends.
+func batchContext(ctx context.Context, stop <-chan struct{}, mode StopMode) (context.Context, context.CancelFunc) {
+	if mode != StopImmediately || stop == nil {
+		return ctx, func() {}
+	}
+	batchCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-batchCtx.Done():
+		}
+	}()
+	return batchCtx, cancel
+}
+
+// ErrRuntimeBudgetExceeded is returned when processing.max_runtime elapses.
+// The loop stops at a batch boundary, after the previous batch's checkpoint
+// committed, so the next run resumes from that checkpoint.
+var ErrRuntimeBudgetExceeded = errors.New("runtime budget exceeded")
+
+// runtimeBudget returns a context that expires maxRuntime after the call.
+// Batches keep running on the parent context; the loops only consult the
+// budget at batch boundaries (and while sleeping or paused), so an expired
+// budget never interrupts a batch. maxRuntime <= 0 means no budget.
+func runtimeBudget(ctx context.Context, maxRuntime time.Duration) (context.Context, context.CancelFunc) {
+	if maxRuntime <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, maxRuntime)
+}
+
+// budgetExceeded reports whether the runtime budget ran out while the parent
+// context is still live (a canceled parent is a hard stop, not a budget stop).
+func budgetExceeded(ctx, budget context.Context) bool {
+	return ctx.Err() == nil && errors.Is(budget.Err(), context.DeadlineExceeded)
+}
+
+// budgetErr maps an error from a budget-bound wait to ErrRuntimeBudgetExceeded
+// when the budget is what ended it.
+func budgetErr(ctx, budget context.Context, err error) error {
+	if budgetExceeded(ctx, budget) {
+		return ErrRuntimeBudgetExceeded
+	}
+	return err
+}
+
 // stopRequested reports whether a cooperative graceful stop has been requested
 // (the stop channel is closed). A nil channel never reports stop — that is the
 // default for tests and any caller that did not wire a stop channel.
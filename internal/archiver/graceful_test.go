@@ -86,3 +86,77 @@ func TestInterruptibleSleep_ContextCancelReturnsErr(t *testing.T) {
 		t.Fatalf("expected context.Canceled, got %v", err)
 	}
 }
+
+func TestParseStopMode(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    StopMode
+		wantErr bool
+	}{
+		{"", StopAfterBatch, false},
+		{"finish-batch", StopAfterBatch, false},
+		{"immediate", StopImmediately, false},
+		{"now", StopAfterBatch, true},
+	} {
+		got, err := ParseStopMode(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseStopMode(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseStopMode(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+		if !tc.wantErr && tc.in != "" && got.String() != tc.in {
+			t.Errorf("String() = %q, want %q", got.String(), tc.in)
+		}
+	}
+}
+
+func TestBatchContext(t *testing.T) {
+	stop := make(chan struct{})
+	finishCtx, releaseFinish := batchContext(context.Background(), stop, StopAfterBatch)
+	defer releaseFinish()
+	immediateCtx, releaseImmediate := batchContext(context.Background(), stop, StopImmediately)
+	defer releaseImmediate()
+
+	close(stop)
+	select {
+	case <-immediateCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("immediate mode must cancel the batch context when stop closes")
+	}
+	if finishCtx.Err() != nil {
+		t.Errorf("finish-batch mode must leave the batch context alive, got %v", finishCtx.Err())
+	}
+}
+
+func TestRuntimeBudget(t *testing.T) {
+	ctx := context.Background()
+
+	unlimited, cancel := runtimeBudget(ctx, 0)
+	defer cancel()
+	if _, ok := unlimited.Deadline(); ok {
+		t.Fatal("max_runtime 0 must not set a deadline")
+	}
+
+	budget, cancelBudget := runtimeBudget(ctx, time.Millisecond)
+	defer cancelBudget()
+	<-budget.Done()
+	if !budgetExceeded(ctx, budget) {
+		t.Fatal("expired budget with a live parent must report budgetExceeded")
+	}
+	if err := budgetErr(ctx, budget, budget.Err()); !errors.Is(err, ErrRuntimeBudgetExceeded) {
+		t.Fatalf("budgetErr = %v, want ErrRuntimeBudgetExceeded", err)
+	}
+
+	parent, cancelParent := context.WithCancel(ctx)
+	budget, cancelBudget2 := runtimeBudget(parent, time.Hour)
+	defer cancelBudget2()
+	cancelParent()
+	if budgetExceeded(parent, budget) {
+		t.Fatal("a canceled parent is a hard stop, not a budget stop")
+	}
+	if err := budgetErr(parent, budget, budget.Err()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("budgetErr = %v, want context.Canceled", err)
+	}
+}
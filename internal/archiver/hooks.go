@@ -0,0 +1,112 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// Phase names passed to Hooks.
+const (
+	PhaseDiscovery = "discovery"
+	PhaseCopy      = "copy"
+	PhaseVerify    = "verify"
+	PhaseDelete    = "delete"
+)
+
+// PhaseInfo describes the batch a phase runs on.
+type PhaseInfo struct {
+	JobName string
+	RootPKs []interface{}
+	// Records is the batch's discovered record set; nil around discovery.
+	Records *types.RecordSet
+	// Err is the phase's error. Only set for AfterPhase.
+	Err error
+}
+
+// Hooks are callbacks an orchestrator runs around the discovery, copy,
+// verify and delete phases of every batch, e.g. to disable a trigger, warm a
+// cache or send a notification. A BeforePhase error aborts the phase (and
+// fails the batch) without running it. AfterPhase runs whether or not the
+// phase succeeded; its error is logged and does not fail the batch, as the
+// phase has already run.
+type Hooks interface {
+	BeforePhase(ctx context.Context, phase string, info PhaseInfo) error
+	AfterPhase(ctx context.Context, phase string, info PhaseInfo) error
+}
+
+// NoopHooks is the default Hooks: every callback does nothing.
+type NoopHooks struct{}
+
+// BeforePhase implements Hooks.
+func (NoopHooks) BeforePhase(context.Context, string, PhaseInfo) error { return nil }
+
+// AfterPhase implements Hooks.
+func (NoopHooks) AfterPhase(context.Context, string, PhaseInfo) error { return nil }
+
+// CommandHooks runs the executable at Path (a job's phase_hook) around every
+// phase, without a shell or arguments. The hook point and batch are passed
+// in the environment: GOARCHIVE_HOOK ("before" or "after"), GOARCHIVE_PHASE,
+// GOARCHIVE_JOB, GOARCHIVE_ROOT_PKS (the number of root PKs), and, where
+// known, GOARCHIVE_RECORDS (discovered rows) and GOARCHIVE_ERROR (after a
+// failed phase). A non-zero exit is returned with the command's output.
+type CommandHooks struct {
+	Path string
+}
+
+// BeforePhase implements Hooks.
+func (h CommandHooks) BeforePhase(ctx context.Context, phase string, info PhaseInfo) error {
+	return h.run(ctx, "before", phase, info)
+}
+
+// AfterPhase implements Hooks.
+func (h CommandHooks) AfterPhase(ctx context.Context, phase string, info PhaseInfo) error {
+	return h.run(ctx, "after", phase, info)
+}
+
+func (h CommandHooks) run(ctx context.Context, point, phase string, info PhaseInfo) error {
+	cmd := exec.CommandContext(ctx, h.Path)
+	cmd.Env = append(os.Environ(),
+		"GOARCHIVE_HOOK="+point,
+		"GOARCHIVE_PHASE="+phase,
+		"GOARCHIVE_JOB="+info.JobName,
+		"GOARCHIVE_ROOT_PKS="+strconv.Itoa(len(info.RootPKs)))
+	if info.Records != nil {
+		cmd.Env = append(cmd.Env, "GOARCHIVE_RECORDS="+strconv.FormatInt(info.Records.Stats.RecordsFound, 10))
+	}
+	if info.Err != nil {
+		cmd.Env = append(cmd.Env, "GOARCHIVE_ERROR="+info.Err.Error())
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", h.Path, err, msg)
+		}
+		return fmt.Errorf("%s: %w", h.Path, err)
+	}
+	return nil
+}
+
+// runPhase runs fn as phase between hooks' BeforePhase and AfterPhase. A nil
+// hooks runs fn alone. fn gets a context bounded by timeout (see
+// withPhaseTimeout); the hooks get ctx.
+func runPhase(ctx context.Context, hooks Hooks, log *logger.Logger, phase string, timeout time.Duration, info PhaseInfo, fn func(ctx context.Context) error) error {
+	if hooks == nil {
+		return withPhaseTimeout(ctx, phase, timeout, fn)
+	}
+	if err := hooks.BeforePhase(ctx, phase, info); err != nil {
+		return fmt.Errorf("before-%s hook failed: %w", phase, err)
+	}
+	err := withPhaseTimeout(ctx, phase, timeout, fn)
+	info.Err = err
+	if hookErr := hooks.AfterPhase(ctx, phase, info); hookErr != nil {
+		log.Warnw("After-phase hook failed", "phase", phase, "error", hookErr)
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/types"
+	"github.com/dbsmedya/goarchive/internal/verifier"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHooks records every callback as "before:<phase>"/"after:<phase>"
+// and fails BeforePhase of failBefore.
+type recordingHooks struct {
+	calls      []string
+	failBefore string
+	afterErrs  map[string]error
+}
+
+func (h *recordingHooks) BeforePhase(_ context.Context, phase string, _ PhaseInfo) error {
+	h.calls = append(h.calls, "before:"+phase)
+	if phase == h.failBefore {
+		return errors.New("hook refused")
+	}
+	return nil
+}
+
+func (h *recordingHooks) AfterPhase(_ context.Context, phase string, info PhaseInfo) error {
+	h.calls = append(h.calls, "after:"+phase)
+	if h.afterErrs == nil {
+		h.afterErrs = make(map[string]error)
+	}
+	h.afterErrs[phase] = info.Err
+	return nil
+}
+
+type hookTestPhases struct {
+	sourceMock, destMock, archMock sqlmock.Sqlmock
+	o                              *ArchiveOrchestrator
+	discovery                      *RecordDiscovery
+	copyPhase                      *CopyPhase
+	dataVerifier                   *verifier.Verifier
+	deletePhase                    *DeletePhase
+	fetcher                        *RootIDFetcher
+	resumeMgr                      *ResumeManager
+}
+
+func newHookTestPhases(t *testing.T, hooks Hooks) *hookTestPhases {
+	t.Helper()
+	sourceDB, sourceMock, _ := sqlmock.New()
+	destDB, destMock, _ := sqlmock.New()
+	archDB, archMock, _ := sqlmock.New()
+	t.Cleanup(func() {
+		_ = sourceDB.Close()
+		_ = destDB.Close()
+		_ = archDB.Close()
+	})
+
+	g := createMultiLevelGraph()
+	log := logger.NewDefault()
+	p := &hookTestPhases{sourceMock: sourceMock, destMock: destMock, archMock: archMock}
+	p.discovery, _ = NewRecordDiscovery(g, sourceDB, 1000)
+	p.copyPhase, _ = NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	p.dataVerifier, _ = verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodCount, log)
+	p.deletePhase, _ = NewDeletePhase(sourceDB, g, 1000, log)
+	p.fetcher = NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	p.resumeMgr, _ = NewResumeManager(archDB, log, "testdb")
+	p.resumeMgr.setJobID(7)
+
+	p.o = &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000},
+		verificationCfg: config.VerificationConfig{Method: "count"},
+	}
+	p.o.SetHooks(hooks)
+	return p
+}
+
+func (p *hookTestPhases) processBatch(rootID int64) error {
+	_, err := p.o.processBatch(context.Background(), []interface{}{rootID},
+		batchFull, false, nil,
+		p.discovery, p.copyPhase, p.dataVerifier, p.deletePhase, p.fetcher, p.resumeMgr, nil)
+	return err
+}
+
+func (p *hookTestPhases) expectationsMet(t *testing.T) {
+	t.Helper()
+	require.NoError(t, p.sourceMock.ExpectationsWereMet())
+	require.NoError(t, p.destMock.ExpectationsWereMet())
+	require.NoError(t, p.archMock.ExpectationsWereMet())
+}
+
+func TestProcessBatch_HooksWrapEachPhaseInOrder(t *testing.T) {
+	hooks := &recordingHooks{}
+	p := newHookTestPhases(t, hooks)
+
+	expectGatedRootCopy(p.sourceMock, p.destMock, 1, 10, 1)
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("DELETE FROM `orders`").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("DELETE FROM `customers`").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectBegin()
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectCommit()
+
+	require.NoError(t, p.processBatch(1))
+	require.Equal(t, []string{
+		"before:discovery", "after:discovery",
+		"before:copy", "after:copy",
+		"before:verify", "after:verify",
+		"before:delete", "after:delete",
+	}, hooks.calls)
+	p.expectationsMet(t)
+}
+
+func TestProcessBatch_FailingBeforePhaseSkipsPhase(t *testing.T) {
+	hooks := &recordingHooks{failBefore: PhaseCopy}
+	p := newHookTestPhases(t, hooks)
+
+	// Only discovery runs: no copy, verify or delete statement is expected.
+	p.sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)))
+
+	err := p.processBatch(1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "before-copy hook failed: hook refused")
+	require.Equal(t, []string{"before:discovery", "after:discovery", "before:copy"}, hooks.calls)
+	p.expectationsMet(t)
+}
+
+func TestProcessBatch_AfterPhaseSeesPhaseError(t *testing.T) {
+	hooks := &recordingHooks{}
+	p := newHookTestPhases(t, hooks)
+
+	p.sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnError(errors.New("connection lost"))
+
+	require.Error(t, p.processBatch(1))
+	require.Equal(t, []string{"before:discovery", "after:discovery"}, hooks.calls)
+	require.ErrorContains(t, hooks.afterErrs[PhaseDiscovery], "connection lost")
+	p.expectationsMet(t)
+}
+
+func TestSetHooks_NilRestoresNoop(t *testing.T) {
+	o := &ArchiveOrchestrator{}
+	o.SetHooks(nil)
+	require.Equal(t, NoopHooks{}, o.hooks)
+}
+
+// writeHookScript writes an executable shell script to a temp dir.
+func writeHookScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0700))
+	return path
+}
+
+func TestCommandHooks_PassesPhaseInEnvironment(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "calls")
+	hooks := CommandHooks{Path: writeHookScript(t,
+		`echo "$GOARCHIVE_HOOK $GOARCHIVE_PHASE $GOARCHIVE_JOB $GOARCHIVE_ROOT_PKS ${GOARCHIVE_RECORDS:--} ${GOARCHIVE_ERROR:--}" >> `+out+"\n")}
+
+	ctx := context.Background()
+	info := PhaseInfo{JobName: "orders", RootPKs: []interface{}{1, 2}}
+	require.NoError(t, hooks.BeforePhase(ctx, PhaseDiscovery, info))
+	info.Records = &types.RecordSet{Stats: types.DiscoveryStats{RecordsFound: 7}}
+	info.Err = errors.New("copy failed")
+	require.NoError(t, hooks.AfterPhase(ctx, PhaseCopy, info))
+
+	calls, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"before discovery orders 2 - -",
+		"after copy orders 2 7 copy failed",
+	}, strings.Split(strings.TrimSpace(string(calls)), "\n"))
+}
+
+func TestCommandHooks_NonZeroExitFailsPhase(t *testing.T) {
+	hooks := CommandHooks{Path: writeHookScript(t, "echo 'trigger still enabled'\nexit 3\n")}
+	err := hooks.BeforePhase(context.Background(), PhaseDelete, PhaseInfo{JobName: "orders"})
+	require.ErrorContains(t, err, "exit status 3")
+	require.ErrorContains(t, err, "trigger still enabled")
+}
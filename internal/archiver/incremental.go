@@ -0,0 +1,172 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// incrementalTimeLayout is the MySQL DATE_FORMAT pattern watermarks are
+// captured and stored in; MySQL compares it with DATETIME and TIMESTAMP
+// columns directly.
+const incrementalTimeLayout = "%Y-%m-%d %H:%i:%s.%f"
+
+// incrementalWindow limits a run to root rows whose incremental_column lies
+// in (since, until]. until is the source server's NOW() when the run
+// started, so rows written while it runs are left to the next run and
+// neither host's clock skew matters; since is the previous successful run's
+// until, empty on the first run.
+type incrementalWindow struct {
+	column string
+	since  string
+	until  string
+}
+
+// conditions returns the SQL appended to the root fetch's WHERE clause and
+// its arguments. A nil window adds nothing.
+func (w *incrementalWindow) conditions() (string, []interface{}) {
+	if w == nil {
+		return "", nil
+	}
+	col := sqlutil.QuoteIdentifier(w.column)
+	if w.since == "" {
+		return fmt.Sprintf(" AND %s <= ?", col), []interface{}{w.until}
+	}
+	return fmt.Sprintf(" AND %s > ? AND %s <= ?", col, col), []interface{}{w.since, w.until}
+}
+
+// complete advances the job's watermark to w's upper bound when the run
+// succeeded: every root row of the window was fetched and processed without
+// errors. A stopped, timed-out or partially failed run leaves the window
+// pending for the next run. A nil window does nothing.
+func (w *incrementalWindow) complete(ctx context.Context, resumeMgr *ResumeManager, jobName string, succeeded bool) error {
+	if w == nil || !succeeded {
+		return nil
+	}
+	return resumeMgr.AdvanceWatermark(ctx, jobName)
+}
+
+// openIncrementalWindow returns the window for this run of an
+// incremental_column job, or nil when column is empty. An earlier run that
+// did not complete left its window pending; it is reused so that rows
+// behind that run's PK checkpoint are not skipped. Otherwise the upper
+// bound is captured from source and recorded as pending before any row is
+// processed.
+func openIncrementalWindow(ctx context.Context, source *sql.DB, resumeMgr *ResumeManager, column string, log *logger.Logger) (*incrementalWindow, error) {
+	if column == "" {
+		return nil, nil
+	}
+	since, until, err := resumeMgr.LoadWatermark(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if until != "" {
+		log.Infow("Reusing incremental window of the interrupted run",
+			"column", column, "since", since, "until", until)
+		return &incrementalWindow{column: column, since: since, until: until}, nil
+	}
+
+	if err := source.QueryRowContext(ctx, "SELECT DATE_FORMAT(NOW(6), ?)", incrementalTimeLayout).Scan(&until); err != nil {
+		return nil, fmt.Errorf("failed to read source server time: %w", err)
+	}
+	if err := resumeMgr.SetPendingWatermark(ctx, until); err != nil {
+		return nil, err
+	}
+	log.Infow("Incremental window opened", "column", column, "since", since, "until", until)
+	return &incrementalWindow{column: column, since: since, until: until}, nil
+}
+
+// watermarkTable returns the quoted qualified name of the watermark table.
+func (r *ResumeManager) watermarkTable() string {
+	return sqlutil.QuoteIdentifier(r.jobSchema) + "." + sqlutil.QuoteIdentifier("archiver_job_watermark")
+}
+
+// LoadWatermark returns the job's committed watermark and the upper bound of
+// a run that has not completed, each empty when unset. It creates the
+// watermark table on first use.
+func (r *ResumeManager) LoadWatermark(ctx context.Context) (watermark, pendingUntil string, err error) {
+	if err := r.requireLogTable(); err != nil {
+		return "", "", err
+	}
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	job_id BIGINT NOT NULL PRIMARY KEY,
+	watermark VARCHAR(32) DEFAULT NULL,
+	pending_until VARCHAR(32) DEFAULT NULL,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB`, r.watermarkTable())); err != nil {
+		return "", "", fmt.Errorf("failed to create archiver_job_watermark in schema %q: %w", r.jobSchema, err)
+	}
+
+	var mark, pending sql.NullString
+	err = r.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT watermark, pending_until FROM %s WHERE job_id = ?", r.watermarkTable()),
+		r.jobID,
+	).Scan(&mark, &pending)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load watermark: %w", err)
+	}
+	return mark.String, pending.String, nil
+}
+
+// SetPendingWatermark records until as the upper bound of the run in
+// progress; AdvanceWatermark commits it.
+func (r *ResumeManager) SetPendingWatermark(ctx context.Context, until string) error {
+	if err := r.requireLogTable(); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (job_id, pending_until) VALUES (?, ?) ON DUPLICATE KEY UPDATE pending_until = VALUES(pending_until)", r.watermarkTable()),
+		r.jobID, until,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record pending watermark: %w", err)
+	}
+	return nil
+}
+
+// AdvanceWatermark commits the pending upper bound as the job's watermark
+// and clears the root PK checkpoint in one transaction, so the next run
+// scans the new window from the first PK.
+func (r *ResumeManager) AdvanceWatermark(ctx context.Context, jobName string) error {
+	if err := r.requireLogTable(); err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin watermark tx: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Warnf("Failed to rollback watermark tx: %v", rbErr)
+			}
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf("UPDATE %s SET watermark = pending_until, pending_until = NULL WHERE job_id = ? AND pending_until IS NOT NULL", r.watermarkTable()),
+		r.jobID,
+	); err != nil {
+		return fmt.Errorf("failed to advance watermark: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf("UPDATE %s SET last_processed_root_pk_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE job_name = ?", r.jobTable),
+		jobName,
+	); err != nil {
+		return fmt.Errorf("failed to reset checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit watermark tx: %w", err)
+	}
+	committed = true
+	r.logger.Infof("Job %q watermark advanced", jobName)
+	return nil
+}
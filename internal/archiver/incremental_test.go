@@ -0,0 +1,146 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+func newWatermarkTestResumeManager(t *testing.T) (*ResumeManager, sqlmock.Sqlmock) {
+	t.Helper()
+	rm, mock := newReplayTestResumeManager(t)
+	rm.setJobID(7)
+	return rm, mock
+}
+
+func TestRootIDFetcher_AppliesIncrementalWindow(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	fetcher := NewRootIDFetcher(db, "orders", "id", "status = 'closed'", 2, nil)
+	fetcher.SetIncrementalWindow(&incrementalWindow{
+		column: "updated_at",
+		since:  "2026-01-01 00:00:00.000000",
+		until:  "2026-01-02 00:00:00.000000",
+	})
+
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE (status = 'closed') AND `updated_at` > ? AND `updated_at` <= ? ORDER BY `id` ASC LIMIT ?").
+		WithArgs("2026-01-01 00:00:00.000000", "2026-01-02 00:00:00.000000", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(9))
+	ids, err := fetcher.FetchNextBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(3), int64(9)}, ids)
+
+	fetcher.UpdateCheckpoint(int64(9))
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE (status = 'closed') AND `updated_at` > ? AND `updated_at` <= ? AND `id` > ? ORDER BY `id` ASC LIMIT ?").
+		WithArgs("2026-01-01 00:00:00.000000", "2026-01-02 00:00:00.000000", int64(9), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	ids, err = fetcher.FetchNextBatch(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRootIDFetcher_FirstIncrementalRunHasNoLowerBound(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	fetcher := NewRootIDFetcher(db, "orders", "id", "1=1", 5, nil)
+	fetcher.SetIncrementalWindow(&incrementalWindow{column: "updated_at", until: "2026-01-02 00:00:00.000000"})
+
+	mock.ExpectQuery("SELECT `id` FROM `orders` WHERE (1=1) AND `updated_at` <= ? ORDER BY `id` ASC LIMIT ?").
+		WithArgs("2026-01-02 00:00:00.000000", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = fetcher.FetchNextBatch(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpenIncrementalWindow_CapturesSourceTime(t *testing.T) {
+	src, srcMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = src.Close() }()
+	rm, rmMock := newWatermarkTestResumeManager(t)
+
+	rmMock.ExpectExec("CREATE TABLE IF NOT EXISTS `goarchive`.`archiver_job_watermark`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rmMock.ExpectQuery("SELECT watermark, pending_until FROM `goarchive`.`archiver_job_watermark` WHERE job_id = \\?").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"watermark", "pending_until"}).AddRow("2026-01-01 00:00:00.000000", nil))
+	srcMock.ExpectQuery("SELECT DATE_FORMAT\\(NOW\\(6\\), \\?\\)").
+		WithArgs(incrementalTimeLayout).
+		WillReturnRows(sqlmock.NewRows([]string{"now"}).AddRow("2026-01-02 00:00:00.000000"))
+	rmMock.ExpectExec("INSERT INTO `goarchive`.`archiver_job_watermark` \\(job_id, pending_until\\)").
+		WithArgs(int64(7), "2026-01-02 00:00:00.000000").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w, err := openIncrementalWindow(context.Background(), src, rm, "updated_at", logger.NewDefault())
+	require.NoError(t, err)
+	assert.Equal(t, &incrementalWindow{
+		column: "updated_at",
+		since:  "2026-01-01 00:00:00.000000",
+		until:  "2026-01-02 00:00:00.000000",
+	}, w)
+	assert.NoError(t, srcMock.ExpectationsWereMet())
+	assert.NoError(t, rmMock.ExpectationsWereMet())
+}
+
+func TestOpenIncrementalWindow_ReusesPendingWindow(t *testing.T) {
+	src, srcMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = src.Close() }()
+	rm, rmMock := newWatermarkTestResumeManager(t)
+
+	rmMock.ExpectExec("CREATE TABLE IF NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
+	rmMock.ExpectQuery("SELECT watermark, pending_until").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"watermark", "pending_until"}).AddRow(nil, "2026-01-02 00:00:00.000000"))
+
+	w, err := openIncrementalWindow(context.Background(), src, rm, "updated_at", logger.NewDefault())
+	require.NoError(t, err)
+	assert.Equal(t, "", w.since)
+	assert.Equal(t, "2026-01-02 00:00:00.000000", w.until)
+	// The interrupted run's bound is kept: the source clock is not read again.
+	assert.NoError(t, srcMock.ExpectationsWereMet())
+	assert.NoError(t, rmMock.ExpectationsWereMet())
+}
+
+func TestOpenIncrementalWindow_DisabledWithoutColumn(t *testing.T) {
+	w, err := openIncrementalWindow(context.Background(), nil, nil, "", logger.NewDefault())
+	require.NoError(t, err)
+	assert.Nil(t, w)
+	assert.NoError(t, w.complete(context.Background(), nil, "job", true))
+}
+
+func TestIncrementalWindow_AdvancesOnlyOnSuccess(t *testing.T) {
+	w := &incrementalWindow{column: "updated_at", until: "2026-01-02 00:00:00.000000"}
+
+	t.Run("failed run keeps the watermark", func(t *testing.T) {
+		rm, rmMock := newWatermarkTestResumeManager(t)
+		require.NoError(t, w.complete(context.Background(), rm, "job", false))
+		// No statement may run: any would be unexpected.
+		assert.NoError(t, rmMock.ExpectationsWereMet())
+	})
+
+	t.Run("successful run advances it and resets the checkpoint", func(t *testing.T) {
+		rm, rmMock := newWatermarkTestResumeManager(t)
+		rmMock.ExpectBegin()
+		rmMock.ExpectExec("UPDATE `goarchive`.`archiver_job_watermark` SET watermark = pending_until, pending_until = NULL WHERE job_id = \\? AND pending_until IS NOT NULL").
+			WithArgs(int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		rmMock.ExpectExec("UPDATE `goarchive`.`archiver_job` SET last_processed_root_pk_id = NULL").
+			WithArgs("job").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		rmMock.ExpectCommit()
+
+		require.NoError(t, w.complete(context.Background(), rm, "job", true))
+		assert.NoError(t, rmMock.ExpectationsWereMet())
+	})
+}
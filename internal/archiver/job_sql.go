@@ -0,0 +1,57 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// destructiveSQL matches statements that drop or rewrite data or schema. Job
+// SQL is the operator's own configuration, so such statements still run;
+// they are only called out in the log.
+var destructiveSQL = regexp.MustCompile(`(?i)^\s*(DROP|TRUNCATE|DELETE|ALTER|RENAME|REPLACE|UPDATE)\b`)
+
+// jobSQL runs a job's pre_sql/post_sql statements on the source and
+// destination around an archive run.
+type jobSQL struct {
+	source      *sql.DB
+	destination *sql.DB
+	job         *config.JobConfig
+	logger      *logger.Logger
+}
+
+// before runs pre_sql on the source, then destination_pre_sql on the
+// destination, stopping at the first error.
+func (j jobSQL) before(ctx context.Context) error {
+	if err := j.run(ctx, j.source, "pre_sql", j.job.PreSQL); err != nil {
+		return err
+	}
+	return j.run(ctx, j.destination, "destination_pre_sql", j.job.DestinationPreSQL)
+}
+
+// after runs post_sql on the source, then destination_post_sql on the
+// destination, stopping at the first error.
+func (j jobSQL) after(ctx context.Context) error {
+	if err := j.run(ctx, j.source, "post_sql", j.job.PostSQL); err != nil {
+		return err
+	}
+	return j.run(ctx, j.destination, "destination_post_sql", j.job.DestinationPostSQL)
+}
+
+func (j jobSQL) run(ctx context.Context, db *sql.DB, key string, stmts []string) error {
+	for i, stmt := range stmts {
+		if destructiveSQL.MatchString(stmt) {
+			j.logger.Warnw("Running destructive job SQL statement", "setting", key, "index", i, "statement", stmt)
+		} else {
+			j.logger.Infow("Running job SQL statement", "setting", key, "index", i, "statement", stmt)
+		}
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%s[%d] failed: %w", key, i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobSQL_RunsStatementsInOrder(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = destDB.Close() }()
+
+	j := jobSQL{source: sourceDB, destination: destDB, logger: logger.NewDefault(), job: &config.JobConfig{
+		PreSQL:             []string{"ALTER TABLE orders ALTER INDEX idx_status INVISIBLE", "UPDATE flags SET archiving = 1"},
+		PostSQL:            []string{"UPDATE flags SET archiving = 0", "ALTER TABLE orders ALTER INDEX idx_status VISIBLE"},
+		DestinationPreSQL:  []string{"SET GLOBAL innodb_flush_log_at_trx_commit = 2"},
+		DestinationPostSQL: []string{"SET GLOBAL innodb_flush_log_at_trx_commit = 1"},
+	}}
+
+	sourceMock.ExpectExec("ALTER TABLE orders ALTER INDEX idx_status INVISIBLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectExec("UPDATE flags SET archiving = 1").WillReturnResult(sqlmock.NewResult(0, 1))
+	destMock.ExpectExec("SET GLOBAL innodb_flush_log_at_trx_commit = 2").WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, j.before(context.Background()))
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+
+	sourceMock.ExpectExec("UPDATE flags SET archiving = 0").WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectExec("ALTER TABLE orders ALTER INDEX idx_status VISIBLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	destMock.ExpectExec("SET GLOBAL innodb_flush_log_at_trx_commit = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, j.after(context.Background()))
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestJobSQL_PreStatementErrorStops(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = destDB.Close() }()
+
+	j := jobSQL{source: sourceDB, destination: destDB, logger: logger.NewDefault(), job: &config.JobConfig{
+		PreSQL:            []string{"UPDATE flags SET archiving = 1", "UPDATE other SET x = 1"},
+		DestinationPreSQL: []string{"SELECT 1"},
+	}}
+
+	sourceMock.ExpectExec("UPDATE flags SET archiving = 1").WillReturnError(fmt.Errorf("Table 'flags' doesn't exist"))
+	err := j.before(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre_sql[0] failed")
+	// Neither the second source statement nor the destination ones ran.
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestDestructiveSQL(t *testing.T) {
+	for stmt, want := range map[string]bool{
+		"DROP INDEX idx ON orders":        true,
+		"  truncate table staging":        true,
+		"delete from flags":               true,
+		"ALTER TABLE orders ADD INDEX i":  true,
+		"SET SESSION sql_log_bin = 0":     false,
+		"INSERT INTO runs VALUES (NOW())": false,
+		"ANALYZE TABLE orders":            false,
+	} {
+		assert.Equal(t, want, destructiveSQL.MatchString(stmt), stmt)
+	}
+}
@@ -0,0 +1,138 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// Table maintenance operations (processing.post_copy_maintenance).
+const (
+	MaintenanceAnalyze  = "analyze"
+	MaintenanceOptimize = "optimize"
+)
+
+// TableMaintenance refreshes the destination tables of a graph after a run
+// has bulk-inserted into them: ANALYZE TABLE recomputes index statistics,
+// OPTIMIZE TABLE also rebuilds the table and its indexes. Both only touch
+// the destination; the source is never maintained.
+type TableMaintenance struct {
+	destDB    *sql.DB
+	graph     *graph.Graph
+	operation string
+	logger    *logger.Logger
+}
+
+// NewTableMaintenance creates the maintenance step for operation
+// (MaintenanceAnalyze or MaintenanceOptimize).
+func NewTableMaintenance(destDB *sql.DB, g *graph.Graph, operation string, log *logger.Logger) (*TableMaintenance, error) {
+	if destDB == nil {
+		return nil, fmt.Errorf("destination database is nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("graph is nil")
+	}
+	if operation != MaintenanceAnalyze && operation != MaintenanceOptimize {
+		return nil, fmt.Errorf("unknown table maintenance %q: must be %q or %q", operation, MaintenanceAnalyze, MaintenanceOptimize)
+	}
+	if log == nil {
+		log = logger.NewDefault()
+	}
+	return &TableMaintenance{destDB: destDB, graph: g, operation: operation, logger: log}, nil
+}
+
+// Run maintains each destination table of the graph in copy order, one
+// statement per table, logging how long each took. A destination_table
+// template is expanded with runDate; a template dated by a row column names
+// one table per date, so those tables are skipped with a warning. Run stops
+// at the first failing table or when ctx is canceled.
+func (m *TableMaintenance) Run(ctx context.Context, runDate time.Time) error {
+	verb := strings.ToUpper(m.operation)
+	start := time.Now()
+	tables := 0
+	seen := make(map[string]bool)
+	err := m.graph.ForEachInCopyOrderContext(ctx, func(table string) error {
+		if m.graph.DestinationDateColumn(table) != "" {
+			m.logger.Warnf("Table %s: destination_table %s is dated per row; %s TABLE skipped",
+				table, m.graph.GetNode(table).DestinationTable, verb)
+			return nil
+		}
+		dest := m.graph.DestinationTable(table, runDate)
+		if seen[dest] {
+			return nil
+		}
+		seen[dest] = true
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tableStart := time.Now()
+		if err := m.maintain(ctx, verb, dest); err != nil {
+			return err
+		}
+		tables++
+		m.logger.Infow("Destination table maintained",
+			"table", dest,
+			"operation", m.operation,
+			"duration_ms", time.Since(tableStart).Milliseconds())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.Infow("Destination table maintenance complete",
+		"operation", m.operation,
+		"tables", tables,
+		"duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+// maintain runs "<verb> TABLE dest". MySQL reports a failure as a result
+// row with Msg_type "error" rather than a statement error, so the rows are
+// read and the first error row is returned.
+func (m *TableMaintenance) maintain(ctx context.Context, verb, dest string) error {
+	query := fmt.Sprintf("%s TABLE %s", verb, sqlutil.QuoteIdentifier(dest))
+	rows, err := m.destDB.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("%s TABLE %s failed: %w", verb, dest, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name, op, msgType, msgText sql.NullString
+		if err := rows.Scan(&name, &op, &msgType, &msgText); err != nil {
+			return fmt.Errorf("%s TABLE %s: failed to read result: %w", verb, dest, err)
+		}
+		if strings.EqualFold(msgType.String, "error") {
+			return fmt.Errorf("%s TABLE %s failed: %s", verb, dest, msgText.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s TABLE %s failed: %w", verb, dest, err)
+	}
+	return nil
+}
+
+// runPostCopyMaintenance runs processing.post_copy_maintenance (operation)
+// on the destination once a run has copied rows; it does nothing when
+// operation is empty or no row was copied. Maintenance only refreshes
+// statistics of rows already copied and verified, so a failure, including
+// cancellation, is logged and does not fail the run.
+func runPostCopyMaintenance(ctx context.Context, destDB *sql.DB, g *graph.Graph, operation string, copied int64, runDate time.Time, log *logger.Logger) {
+	if operation == "" || copied == 0 {
+		return
+	}
+	maintenance, err := NewTableMaintenance(destDB, g, operation, log)
+	if err == nil {
+		err = maintenance.Run(ctx, runDate)
+	}
+	if err != nil {
+		log.Warnf("Post-copy table maintenance failed: %v", err)
+	}
+}
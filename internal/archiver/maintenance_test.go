@@ -0,0 +1,88 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+var maintenanceResultColumns = []string{"Table", "Op", "Msg_type", "Msg_text"}
+
+func TestNewTableMaintenance_Validation(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	_, err := NewTableMaintenance(nil, createSimpleGraph(), MaintenanceAnalyze, nil)
+	require.Error(t, err)
+	_, err = NewTableMaintenance(db, nil, MaintenanceAnalyze, nil)
+	require.Error(t, err)
+	_, err = NewTableMaintenance(db, createSimpleGraph(), "vacuum", nil)
+	require.Error(t, err)
+	m, err := NewTableMaintenance(db, createSimpleGraph(), MaintenanceOptimize, nil)
+	require.NoError(t, err)
+	require.NotNil(t, m.logger)
+}
+
+func TestPostCopyMaintenance_AnalyzesEachCopiedTable(t *testing.T) {
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	// Parents first, like the copy.
+	destMock.ExpectQuery("ANALYZE TABLE `customers`").
+		WillReturnRows(sqlmock.NewRows(maintenanceResultColumns).AddRow("dst.customers", "analyze", "status", "OK"))
+	destMock.ExpectQuery("ANALYZE TABLE `orders`").
+		WillReturnRows(sqlmock.NewRows(maintenanceResultColumns).AddRow("dst.orders", "analyze", "status", "OK"))
+
+	runPostCopyMaintenance(context.Background(), destDB, createMultiLevelGraph(), MaintenanceAnalyze, 5, time.Now(), logger.NewDefault())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestPostCopyMaintenance_DisabledOrNothingCopied(t *testing.T) {
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	// Any statement would fail the expectations below.
+	runPostCopyMaintenance(context.Background(), destDB, createMultiLevelGraph(), "", 5, time.Now(), logger.NewDefault())
+	runPostCopyMaintenance(context.Background(), destDB, createMultiLevelGraph(), MaintenanceOptimize, 0, time.Now(), logger.NewDefault())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+func TestTableMaintenance_OptimizeTemplatesAndErrors(t *testing.T) {
+	g, err := graph.NewBuilder(&config.JobConfig{
+		RootTable:        "customers",
+		PrimaryKey:       "id",
+		DestinationTable: "customers_{year}",
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "customer_id", DependencyType: "1-N",
+				DestinationTable: "orders_{year}{month}", DestinationDateColumn: "created_at"},
+		},
+	}).Build()
+	require.NoError(t, err)
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	m, err := NewTableMaintenance(destDB, g, MaintenanceOptimize, logger.NewDefault())
+	require.NoError(t, err)
+
+	// The run-dated template is expanded; the row-dated one is skipped.
+	// MySQL reports a failed OPTIMIZE as an error row, not a statement error.
+	destMock.ExpectQuery("OPTIMIZE TABLE `customers_2024`").
+		WillReturnRows(sqlmock.NewRows(maintenanceResultColumns).
+			AddRow("dst.customers_2024", "optimize", "note", "Table does not support optimize, doing recreate + analyze instead").
+			AddRow("dst.customers_2024", "optimize", "error", "Lock wait timeout exceeded"))
+
+	err = m.Run(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.ErrorContains(t, err, "OPTIMIZE TABLE customers_2024 failed: Lock wait timeout exceeded")
+	require.NoError(t, destMock.ExpectationsWereMet())
+
+	// A canceled context stops before any statement.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, m.Run(ctx, time.Now()), context.Canceled)
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
@@ -4,6 +4,7 @@ package archiver
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -27,25 +28,61 @@ type ArchiveResult struct {
 	TablesCopied       int
 	TablesDeleted      int
 	RecordsCopied      int64
+	RecordsSkipped     int64 // rows INSERT IGNORE found already present on the destination
 	RecordsDeleted     int64
 	TablesVerified     int
 	RecordsVerified    int64
 	VerificationMethod string
-	Errors             []error
-	Success            bool
+	// Tables holds per-table totals, keyed by table name.
+	Tables  map[string]*TableResult
+	Errors  []error
+	Success bool
 }
 
-// CheckpointCallback is called after each root PK is processed for crash recovery.
-type CheckpointCallback func(rootPK interface{}, status string) error
+// TableError is a copy or verification failure confined to one table, recorded
+// in ArchiveResult.Errors under processing.continue_on_error instead of
+// aborting the run.
+type TableError struct {
+	Table string
+	Phase string // "copy" or "verify"
+	Err   error
+}
+
+func (e *TableError) Error() string {
+	return fmt.Sprintf("%s failed for table %s: %v", e.Phase, e.Table, e.Err)
+}
+
+func (e *TableError) Unwrap() error { return e.Err }
+
+// CheckpointStatus is the lifecycle state reported to a CheckpointCallback
+// for a root PK. The values are plain strings, so callers that compared the
+// former free-form status against "completed" keep working.
+type CheckpointStatus string
+
+const (
+	StatusStarted   CheckpointStatus = "started"   // batch picked up, before discovery
+	StatusCopied    CheckpointStatus = "copied"    // copy committed to the destination
+	StatusVerified  CheckpointStatus = "verified"  // verification passed
+	StatusDeleted   CheckpointStatus = "deleted"   // source rows deleted
+	StatusCompleted CheckpointStatus = "completed" // completion bookkeeping committed
+	StatusFailed    CheckpointStatus = "failed"    // a phase failed; the root stays pending or copied
+)
+
+// CheckpointCallback is called for each root PK at every phase boundary of
+// its batch, so an external system can track the root's lifecycle.
+type CheckpointCallback func(rootPK interface{}, status CheckpointStatus) error
 
 // BatchStats holds per-batch processing totals returned by processBatch so the
 // caller can aggregate run-level result fields.
 type BatchStats struct {
 	RootsProcessed  int
 	RecordsCopied   int64
+	RecordsSkipped  int64
 	RecordsDeleted  int64
 	TablesVerified  int
 	RecordsVerified int64
+	TableErrors     []error // *TableError per isolated failure (continue_on_error)
+	Tables          map[string]*TableResult
 }
 
 // batchMode selects how a batch is recovered/processed.
@@ -84,6 +121,11 @@ type ArchiveOrchestrator struct {
 	force           bool
 	staleAtStartup  bool
 	stopCh          <-chan struct{} // cooperative graceful-stop signal (nil = disabled)
+	stopMode        StopMode        // what a cooperative stop does to the in-flight batch
+	hooks           Hooks           // callbacks around each batch phase (NoopHooks by default)
+	progress        runProgress     // run state behind Snapshot
+	deleteCap       deleteCap       // safety.max_delete_rows for the current run
+	skipMaintenance bool            // skip processing.post_copy_maintenance for this run
 }
 
 // NewOrchestrator creates a new archive orchestrator with the given configuration
@@ -115,6 +157,7 @@ func NewOrchestrator(cfg *config.Config, jobName string, jobCfg *config.JobConfi
 		logger:          log,
 		processingCfg:   processingCfg,
 		verificationCfg: verificationCfg,
+		hooks:           NoopHooks{},
 		lagFactory: func(db *sql.DB, safety config.SafetyConfig, log *logger.Logger) (lagWaiter, error) {
 			lm, err := NewLagMonitor(db, safety, log)
 			if err != nil {
@@ -139,8 +182,13 @@ func (o *ArchiveOrchestrator) Initialize() error {
 		"root_table", o.jobConfig.RootTable,
 	)
 
+	if err := checkDistinctDatabases(o.config); err != nil {
+		return err
+	}
+
 	// Build dependency graph from job configuration
 	builder := graph.NewBuilder(o.jobConfig)
+	builder.SetLogger(o.logger)
 	g, err := builder.Build()
 	if err != nil {
 		return fmt.Errorf("failed to build dependency graph: %w", err)
@@ -152,6 +200,9 @@ func (o *ArchiveOrchestrator) Initialize() error {
 	if err := o.ValidateGraph(); err != nil {
 		return err
 	}
+	if err := checkReferenceKeys(o.graph); err != nil {
+		return err
+	}
 
 	// Compute copy order (parent-first, topological sort)
 	copyOrder, err := o.graph.CopyOrder()
@@ -221,6 +272,22 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 		Errors:             make([]error, 0),
 		Success:            false,
 	}
+	o.progress.update(func(s *Snapshot) { *s = Snapshot{StartedAt: result.StartedAt} })
+	o.deleteCap.reset(o.config.Safety.MaxDeleteRows)
+	o.deleteCap.unconfirmed = confirmDeletes(o.config.Safety, o.jobName, o.jobConfig)
+	if o.processingCfg.ResultFile != "" {
+		// Registered first so it runs last, after every other deferred
+		// step has settled the result.
+		defer func() {
+			if result.CompletedAt.IsZero() {
+				result.CompletedAt = time.Now()
+				result.Duration = result.CompletedAt.Sub(result.StartedAt)
+			}
+			if werr := WriteArchiveReport(o.processingCfg.ResultFile, result); werr != nil {
+				o.logger.Errorf("Failed to write result file: %v", werr)
+			}
+		}()
+	}
 	fail := func(format string, args ...interface{}) (*ArchiveResult, error) {
 		err := fmt.Errorf(format, args...)
 		result.Errors = append(result.Errors, err)
@@ -234,6 +301,10 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 		"sleep_seconds", o.processingCfg.SleepSeconds,
 		"verification_method", o.verificationCfg.EffectiveMethod(),
 		"skip_verification", o.verificationCfg.SkipVerification,
+		"gate_deletes", o.verificationCfg.GateDeletes,
+		"max_runtime", o.processingCfg.MaxRuntime,
+		"continue_on_error", o.processingCfg.ContinueOnError,
+		"skip_existing", o.processingCfg.SkipExisting,
 	)
 	if o.verificationCfg.SkipVerification {
 		o.logger.Warn(skipVerificationBanner)
@@ -254,6 +325,27 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 	jobState := startup.jobState
 	o.staleAtStartup = startup.staleAtStartup
 	ctx = startup.runCtx
+
+	// pre_sql runs under the job lock; post_sql runs when pre_sql succeeded,
+	// also after a failed or interrupted run, so the operator's teardown is
+	// never skipped.
+	jobStatements := jobSQL{source: o.dbManager.Source, destination: o.dbManager.Destination, job: o.jobConfig, logger: o.logger}
+	if err := jobStatements.before(ctx); err != nil {
+		return fail("%w", err)
+	}
+	defer func() {
+		postErr := jobStatements.after(context.WithoutCancel(ctx))
+		switch {
+		case postErr == nil:
+		case err != nil:
+			o.logger.Errorf("Job post SQL failed: %v", postErr)
+		default:
+			result.Success = false
+			result.Errors = append(result.Errors, postErr)
+			err = postErr
+		}
+	}()
+
 	if err := loadRootPKMeta(ctx, o.dbManager.Source, o.graph); err != nil {
 		return fail("failed to load root PK metadata: %w", err)
 	}
@@ -294,24 +386,34 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 		o.processingCfg.BatchSize,
 		jobState.LastProcessedRootPKID,
 	)
+	window, err := openIncrementalWindow(ctx, o.dbManager.Source, resumeMgr, o.jobConfig.IncrementalColumn, o.logger)
+	if err != nil {
+		return fail("failed to open incremental window: %w", err)
+	}
+	fetcher.SetIncrementalWindow(window)
+	fetcher.SetOrder(o.processingCfg.RootOrder)
 
-	discovery, err := NewRecordDiscovery(o.graph, o.dbManager.Source, o.processingCfg.BatchSize)
+	discovery, err := newJobDiscovery(o.dbManager, o.graph, o.processingCfg, o.logger.WithPhase("discovery"))
 	if err != nil {
 		return fail("failed to create record discovery: %w", err)
 	}
-	discovery.SetLogger(o.logger)
+	if o.processingCfg.CountNullFKs {
+		o.reportNullForeignKeys(ctx, discovery, result)
+	}
 
 	copyPhase, err := NewCopyPhase(
 		o.dbManager.Source,
 		o.dbManager.Destination,
 		o.graph,
 		o.config.Safety,
-		o.logger,
+		o.logger.WithPhase("copy"),
 	)
 	if err != nil {
 		return fail("failed to create copy phase: %w", err)
 	}
-	effectiveVerificationMethod := o.verificationCfg.EffectiveMethod()
+	// A count verification_method on any table weakens the safety net for
+	// that table, so the copy mode follows the weakest method.
+	weakestMethod := o.weakestVerificationMethod()
 	// Decide whether INSERT IGNORE is safe. INSERT IGNORE silently skips a row
 	// whose key already exists on the destination; if that skip went undetected
 	// the source row could then be deleted without a faithful copy. We force a
@@ -323,8 +425,11 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 	if err != nil {
 		return fail("failed to inspect destination unique indexes: %w", err)
 	}
-	strictInsert := shouldUseStrictInsert(effectiveVerificationMethod, o.verificationCfg.SkipVerification, len(destUniqueIdx) > 0)
-	if strictInsert && effectiveVerificationMethod != "count" {
+	strictInsert, upsert, err := resolveCopyMode(o.processingCfg.CopyMode, weakestMethod, o.verificationCfg.SkipVerification, destUniqueIdx)
+	if err != nil {
+		return fail("%w", err)
+	}
+	if strictInsert && weakestMethod != "count" && o.processingCfg.CopyMode != "insert" {
 		reason := "verification skipped (no post-copy check before delete)"
 		if len(destUniqueIdx) > 0 {
 			reason = "destination secondary unique index present: " + strings.Join(destUniqueIdx, ", ")
@@ -333,34 +438,83 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 			"reason", reason)
 	}
 	copyPhase.SetStrictInsert(strictInsert)
+	copyPhase.SetUpsert(upsert)
+	writer, err := newDestinationWriter(ctx, o.dbManager.Destination, o.processingCfg.CopyWriter)
+	if err != nil {
+		return fail("%w", err)
+	}
+	copyPhase.SetWriter(writer)
 	copyPhase.SetBatchSize(o.processingCfg.BatchSize)
+	copyPhase.SetStatementTimeout(o.processingCfg.StatementTimeout)
+	copyPhase.SetContinueOnError(o.processingCfg.ContinueOnError)
+	// One limiter per run: copy and delete share the rows/second budget.
+	rateLimiter := NewRowRateLimiter(o.processingCfg.MaxRowsPerSecond)
+	copyPhase.SetRateLimiter(rateLimiter)
+	copyPhase.SetTableObserver(o.progress.setTable)
+	if o.processingCfg.SkipExisting {
+		existing, err := NewExistingFilter(o.dbManager.Destination, o.graph, o.logger.WithPhase("copy"))
+		if err != nil {
+			return fail("failed to create destination lookup: %w", err)
+		}
+		existing.SetBatchSize(o.processingCfg.BatchSize)
+		existing.SetMaxInClauseSize(o.processingCfg.MaxInClauseSize)
+		copyPhase.SetExistingFilter(existing)
+	}
 
-	dataVerifier, err := verifier.NewVerifier(
-		o.dbManager.Source,
-		o.dbManager.Destination,
-		o.graph,
-		verifier.VerificationMethod(effectiveVerificationMethod),
-		o.logger,
-	)
+	dataVerifier, err := newJobVerifier(o.dbManager, o.graph, o.verificationCfg, o.processingCfg, o.logger.WithPhase("verify"))
 	if err != nil {
 		return fail("failed to create verifier: %w", err)
 	}
-	dataVerifier.SetChunkSize(o.processingCfg.BatchSize)
+	dataVerifier.SetTableObserver(o.progress.setTable)
+	// destination_table templates are dated once per run, so copy and verify
+	// name the same tables.
+	copyPhase.SetRunDate(result.StartedAt)
+	dataVerifier.SetRunDate(result.StartedAt)
+	if err := applyTransforms(o.jobConfig, copyPhase, dataVerifier); err != nil {
+		return fail("failed to configure column transforms: %w", err)
+	}
 
-	deletePhase, err := NewDeletePhase(
-		o.dbManager.Source,
-		o.graph,
-		o.processingCfg.BatchDeleteSize,
-		o.logger,
-	)
+	deletePhase, err := newJobDeletePhase(o.dbManager, o.graph, o.processingCfg, o.logger.WithPhase("delete"))
 	if err != nil {
 		return fail("failed to create delete phase: %w", err)
 	}
-	// Throttle deletes (between batch_delete_size chunks) to limit binlog/replication lag.
-	deletePhase.SetSleepSeconds(o.processingCfg.DeleteSleepSeconds)
+	deletePhase.SetRateLimiter(rateLimiter)
+	deletePhase.SetTableObserver(o.progress.setTable)
+	deletePhase.SetTransactional(o.config.Safety.TransactionalDelete)
+	deletePhase.SetOrphanCheck(o.config.Safety.OrphanCheck)
+	deletePhase.SetPostDeleteCheck(o.config.Safety.PostDeleteCheck)
+	deletePhase.SetForeignKeyChecksDisabled(o.config.Safety.DisableSourceForeignKeyChecks)
+	if err := applyCascadeSkips(ctx, o.dbManager.Source, o.config.Source.Database, o.graph, o.config.Safety, o.logger, deletePhase); err != nil {
+		return fail("failed to load cascade rules: %w", err)
+	}
+	audit, err := openDeleteAudit(o.config.Safety, o.jobName, deletePhase)
+	if err != nil {
+		return fail("%w", err)
+	}
+	if audit != nil {
+		defer func() {
+			if cerr := audit.Close(); cerr != nil {
+				o.logger.Errorf("Failed to finalize delete audit log: %v", cerr)
+			}
+		}()
+	}
+	backup, err := openDeleteBackup(o.config.Safety, o.jobName, deletePhase)
+	if err != nil {
+		return fail("%w", err)
+	}
+	if backup != nil {
+		defer func() {
+			if cerr := backup.Close(); cerr != nil {
+				o.logger.Errorf("Failed to close delete backup: %v", cerr)
+			}
+		}()
+	}
 
 	resumeMgr.SetChunkSize(o.processingCfg.BatchSize)
 
+	budgetCtx, cancelBudget := runtimeBudget(ctx, o.processingCfg.MaxRuntime)
+	defer cancelBudget()
+
 	if shouldResume {
 		if err := o.resumePending(ctx, resumeMgr,
 			discovery, copyPhase, dataVerifier, deletePhase, fetcher, lagMonitor, checkpoint, result); err != nil {
@@ -371,6 +525,7 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 	// Batch processing loop
 	batchNum := 0
 	totalProcessed := int64(0)
+	drained := false
 
 	for {
 		select {
@@ -388,6 +543,14 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 			break
 		}
 
+		// The runtime budget is checked at the same boundary, so it never
+		// interrupts a batch and the last checkpoint is already committed.
+		if budgetExceeded(ctx, budgetCtx) {
+			o.logger.Warnw("Runtime budget exhausted - stopping at batch boundary (run again to resume)",
+				"max_runtime", o.processingCfg.MaxRuntime)
+			return fail("%w", ErrRuntimeBudgetExceeded)
+		}
+
 		// Fetch next batch of root IDs
 		rootIDs, err := fetcher.FetchNextBatch(ctx)
 		if err != nil {
@@ -397,6 +560,7 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 		// Empty batch = job complete
 		if len(rootIDs) == 0 {
 			o.logger.Info("No more root IDs to process - job complete")
+			drained = true
 			break
 		}
 
@@ -405,8 +569,8 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 		// we reach here the previous batch has fully completed (copy+verify+delete+
 		// CompleteBatch). A finished job exits via the empty-fetch check above
 		// rather than pausing forever.
-		if err := newSentinelGate(o.processingCfg.SentinelFile, o.logger).wait(ctx, o.stopCh); err != nil {
-			return fail("%w", err)
+		if err := newSentinelGate(o.processingCfg.SentinelFile, o.logger).wait(budgetCtx, o.stopCh); err != nil {
+			return fail("%w", budgetErr(ctx, budgetCtx, err))
 		}
 		// A Ctrl-C during the sentinel pause ends the pause (wait returns nil);
 		// honor it here before starting the next batch.
@@ -431,28 +595,44 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 				return fail("lag monitor error: %w", err)
 			}
 		}
-		batchStats, err := o.processBatch(ctx, rootIDs, batchFull, true /* advanceCheckpoint */, checkpoint,
+		o.progress.update(func(s *Snapshot) { s.Batch = batchNum })
+		batchCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
+		batchStats, err := o.processBatch(batchCtx, rootIDs, batchFull, true /* advanceCheckpoint */, checkpoint,
 			discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, lagMonitor)
+		release()
 		if err != nil {
 			return fail("processBatch failed: %w", err)
 		}
+		o.progress.update(func(s *Snapshot) { s.BatchesDone = batchNum })
 		result.RecordsCopied += batchStats.RecordsCopied
+		result.RecordsSkipped += batchStats.RecordsSkipped
 		result.RecordsDeleted += batchStats.RecordsDeleted
 		result.TablesVerified += batchStats.TablesVerified
 		result.RecordsVerified += batchStats.RecordsVerified
+		mergeTableResults(&result.Tables, batchStats.Tables)
+		result.Errors = append(result.Errors, batchStats.TableErrors...)
 		totalProcessed += int64(batchStats.RootsProcessed)
 
 		// Sleep between batches (skipped early on a cooperative stop; the loop-top
 		// stopRequested check then breaks).
 		if o.processingCfg.SleepSeconds > 0 {
 			sleepDuration := time.Duration(o.processingCfg.SleepSeconds * float64(time.Second))
-			if err := interruptibleSleep(ctx, o.stopCh, sleepDuration); err != nil {
+			if err := interruptibleSleep(budgetCtx, o.stopCh, sleepDuration); err != nil && !budgetExceeded(ctx, budgetCtx) {
 				o.logger.Warn("Context cancelled during batch sleep")
 				return fail("%w", err)
 			}
 		}
 	}
 
+	if err := window.complete(ctx, resumeMgr, o.jobName, drained && len(result.Errors) == 0); err != nil {
+		return fail("%w", err)
+	}
+
+	if !o.skipMaintenance {
+		runPostCopyMaintenance(ctx, o.dbManager.Destination, o.graph, o.processingCfg.Maintenance,
+			result.RecordsCopied, result.StartedAt, o.logger)
+	}
+
 	// Finalize result
 	result.Success = len(result.Errors) == 0
 	result.CompletedAt = time.Now()
@@ -464,6 +644,7 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 		"duration", result.Duration,
 		"success", result.Success,
 		"records_copied", result.RecordsCopied,
+		"records_skipped", result.RecordsSkipped,
 		"records_deleted", result.RecordsDeleted,
 		"tables_verified", result.TablesVerified,
 		"records_verified", result.RecordsVerified,
@@ -472,12 +653,147 @@ func (o *ArchiveOrchestrator) Execute(ctx context.Context, checkpoint Checkpoint
 	return result, nil
 }
 
+// weakestVerificationMethod is the verification method the run's safety
+// decisions assume: "count" when the job or any table verifies by count.
+func (o *ArchiveOrchestrator) weakestVerificationMethod() string {
+	if o.jobConfig == nil {
+		return o.verificationCfg.EffectiveMethod()
+	}
+	return o.jobConfig.WeakestVerification(o.verificationCfg).EffectiveMethod()
+}
+
+// checkReferenceKeys refuses a graph with a relation that does not join on
+// its parent's primary key (graph.ValidateReferenceKeys), since discovery
+// would silently follow the wrong rows.
+func checkReferenceKeys(g *graph.Graph) error {
+	if problems := g.ValidateReferenceKeys(); len(problems) > 0 {
+		return fmt.Errorf("invalid relation reference keys: %w", errors.Join(problems...))
+	}
+	return nil
+}
+
+// checkDistinctDatabases refuses a source and destination that reach the
+// same database: the run would copy rows onto themselves and then delete
+// them. safety.allow_same_database disables the check.
+func checkDistinctDatabases(cfg *config.Config) error {
+	if cfg.Safety.AllowSameDatabase {
+		return nil
+	}
+	if database.SameDatabase(context.Background(), &cfg.Source, &cfg.Destination) {
+		return fmt.Errorf("source and destination resolve to the same database (%s:%d/%s); "+
+			"set safety.allow_same_database only if they are different servers",
+			cfg.Source.Host, cfg.Source.Port, cfg.Source.Database)
+	}
+	return nil
+}
+
+// newJobDiscovery creates the record discovery for a job. Discovery only
+// reads, so it runs against the source read replica when one is configured.
+func newJobDiscovery(dbm *database.Manager, g *graph.Graph, processing config.ProcessingConfig, log *logger.Logger) (*RecordDiscovery, error) {
+	discovery, err := NewRecordDiscovery(g, dbm.ReadSource(), processing.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	discovery.SetLogger(log)
+	discovery.SetMaxInClauseSize(processing.MaxInClauseSize)
+	discovery.SetStatementTimeout(processing.StatementTimeout)
+	return discovery, nil
+}
+
+// newJobVerifier creates the verifier for a job. Tables verify with the job's
+// method unless their relation sets verification_method; the weakest method
+// only drives the copy mode, never the other tables' verification.
+func newJobVerifier(dbm *database.Manager, g *graph.Graph, verification config.VerificationConfig, processing config.ProcessingConfig, log *logger.Logger) (*verifier.Verifier, error) {
+	dataVerifier, err := verifier.NewVerifier(dbm.ReadSource(), dbm.Destination, g,
+		verifier.VerificationMethod(verification.EffectiveMethod()), log)
+	if err != nil {
+		return nil, err
+	}
+	dataVerifier.SetChunkSize(processing.BatchSize)
+	dataVerifier.SetMaxInClauseSize(processing.MaxInClauseSize)
+	dataVerifier.SetStatementTimeout(processing.StatementTimeout)
+	dataVerifier.SetCountPrecheck(verification.CountPrecheck)
+	dataVerifier.SetCanonicalValues(verification.CanonicalValues)
+	dataVerifier.SetGlobalIgnoredColumns(verification.IgnoreColumns)
+	return dataVerifier, nil
+}
+
+// reportNullForeignKeys logs and records in result the relation rows with a
+// NULL foreign key (processing.count_null_foreign_keys). The counts are
+// informational, so a failing count is logged and the run goes on.
+func (o *ArchiveOrchestrator) reportNullForeignKeys(ctx context.Context, discovery *RecordDiscovery, result *ArchiveResult) {
+	counts, err := discovery.CountNullForeignKeys(ctx)
+	if err != nil {
+		o.logger.Warnf("Failed to count NULL foreign keys: %v", err)
+		return
+	}
+	tables := make([]string, 0, len(counts))
+	for table := range counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		o.logger.Warnw("Rows with a NULL foreign key are not archived with any parent",
+			"table", table, "rows", counts[table])
+		mergeTableResults(&result.Tables, map[string]*TableResult{table: {NullForeignKeys: counts[table]}})
+	}
+}
+
+// openDeleteAudit opens safety.delete_audit_log for job and attaches it to dp.
+// Returns nil when no audit log is configured; otherwise the caller must Close
+// the returned log when the run ends so the summary line is written.
+func openDeleteAudit(safety config.SafetyConfig, job string, dp *DeletePhase) (*DeleteAuditLog, error) {
+	if safety.DeleteAuditLog == "" {
+		return nil, nil
+	}
+	audit, err := OpenDeleteAuditLog(safety.DeleteAuditLog, job)
+	if err != nil {
+		return nil, err
+	}
+	dp.SetAuditLog(audit)
+	return audit, nil
+}
+
+// openDeleteBackup opens safety.delete_backup for job and attaches it to dp.
+// Returns nil when no backup is configured; otherwise the caller must Close
+// the returned backup when the run ends.
+func openDeleteBackup(safety config.SafetyConfig, job string, dp *DeletePhase) (*DeleteBackup, error) {
+	if safety.DeleteBackup == "" {
+		return nil, nil
+	}
+	backup, err := OpenDeleteBackup(safety.DeleteBackup, job, safety.DeleteBackupFormat)
+	if err != nil {
+		return nil, err
+	}
+	dp.SetBackup(backup)
+	return backup, nil
+}
+
+// newJobDeletePhase creates the delete phase for a job. Deletes always run
+// against the source primary, never the read replica.
+func newJobDeletePhase(dbm *database.Manager, g *graph.Graph, processing config.ProcessingConfig, log *logger.Logger) (*DeletePhase, error) {
+	deletePhase, err := NewDeletePhase(dbm.Source, g, processing.BatchDeleteSize, log)
+	if err != nil {
+		return nil, err
+	}
+	// Throttle deletes (between batch_delete_size chunks) to limit binlog/replication lag.
+	deletePhase.SetSleepSeconds(processing.DeleteSleepSeconds)
+	deletePhase.SetMaxInClauseSize(processing.MaxInClauseSize)
+	deletePhase.SetSortPKs(processing.SortDeletePKs)
+	deletePhase.SetStatementTimeout(processing.StatementTimeout)
+	return deletePhase, nil
+}
+
 // processBatch runs a whole batch of root PKs through the pipeline, then performs
 // the atomic T3 bookkeeping (CompleteBatch). In batchFull it also records the
 // durable 'copied' marker after a successful copy+verify (MarkBatchCopied).
-// advanceCheckpoint advances the checkpoint to the numeric max PK (main loop
+// advanceCheckpoint advances the checkpoint to the batch's last PK (main loop
 // only; both replay paths pass false). The checkpoint callback, when non-nil, is
-// invoked once per root with "completed" after T3 commits.
+// invoked once per root at each phase boundary: StatusStarted, StatusCopied,
+// StatusVerified (unless verification is skipped), StatusDeleted and, after T3
+// commits, StatusCompleted. A batch that fails (or, under continue_on_error,
+// keeps failed tables) reports StatusFailed instead; a canceled one reports
+// nothing further.
 //
 // On any error, the batch's PKs are left in their current non-terminal status
 // (pending or copied) — NEVER MarkFailed — so status-aware replay recovers them.
@@ -494,42 +810,124 @@ func (o *ArchiveOrchestrator) processBatch(
 	fetcher *RootIDFetcher,
 	resumeMgr *ResumeManager,
 	lagMonitor lagWaiter,
-) (*BatchStats, error) {
+) (_ *BatchStats, err error) {
 	stats := &BatchStats{}
 	if len(rootIDs) == 0 {
 		return stats, nil
 	}
+	if mode == batchFull && o.verificationCfg.GateDeletes && len(rootIDs) > 1 {
+		return o.processBatchGated(ctx, rootIDs, advanceCheckpoint, checkpoint,
+			discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, lagMonitor)
+	}
+
+	o.reportStatus(checkpoint, rootIDs, StatusStarted)
+	defer func() {
+		if err != nil && ctx.Err() == nil {
+			o.reportStatus(checkpoint, rootIDs, StatusFailed)
+		}
+	}()
 
-	discovered, err := discovery.Discover(ctx, rootIDs)
+	info := PhaseInfo{JobName: o.jobName, RootPKs: rootIDs}
+	var discovered *types.RecordSet
+	err = o.runPhase(ctx, PhaseDiscovery, info, func(ctx context.Context) (err error) {
+		discovered, err = discovery.Discover(ctx, rootIDs)
+		return err
+	})
 	if err != nil {
 		return stats, fmt.Errorf("discovery failed: %w", err)
 	}
+	if err := o.deleteCap.admit(discovered); err != nil {
+		return stats, err
+	}
 	recordSet := convertRecordSet(discovered)
+	info.Records = discovered
 
 	if mode == batchFull {
-		copyStats, err := copyPhase.Copy(ctx, recordSet)
+		var copyStats *CopyStats
+		err := o.runPhase(ctx, PhaseCopy, info, func(ctx context.Context) (err error) {
+			copyStats, err = copyPhase.Copy(ctx, recordSet)
+			return err
+		})
 		if err != nil {
 			return stats, fmt.Errorf("copy failed: %w", err)
 		}
 		stats.RecordsCopied = copyStats.RowsCopied
+		stats.RecordsSkipped = copyStats.RowsSkipped
+		for table, n := range copyStats.RowsPerTable {
+			stats.table(table).RowsCopied += n
+		}
+		for table, n := range copyStats.SkippedPerTable {
+			stats.table(table).RowsSkipped += n
+		}
 
+		// Tables that failed to copy, and their descendants (never copied),
+		// are left out of verification.
+		var failed []string
+		for table := range copyStats.FailedTables {
+			failed = append(failed, table)
+		}
+		sort.Strings(failed)
+		for _, table := range failed {
+			stats.TableErrors = append(stats.TableErrors, &TableError{Table: table, Phase: "copy", Err: copyStats.FailedTables[table]})
+		}
+		toVerify := discovered
+		if len(failed) > 0 {
+			toVerify = withoutTables(discovered, o.graphBranches(failed, false))
+		} else {
+			o.reportStatus(checkpoint, rootIDs, StatusCopied)
+		}
+
+		var verifyStats *verifier.VerifyStats
 		if !o.verificationCfg.SkipVerification {
-			verifyStats, err := dataVerifier.Verify(ctx, discovered)
-			if err != nil {
+			err := o.runPhase(ctx, PhaseVerify, PhaseInfo{JobName: o.jobName, RootPKs: rootIDs, Records: toVerify}, func(ctx context.Context) (err error) {
+				verifyStats, err = dataVerifier.Verify(ctx, toVerify)
+				return err
+			})
+			if err != nil && !(o.processingCfg.ContinueOnError && errors.Is(err, verifier.ErrMismatch)) {
 				return stats, fmt.Errorf("verification failed: %w", err)
 			}
 			if verifyStats != nil {
 				stats.TablesVerified += verifyStats.TablesVerified
 				stats.RecordsVerified += verifyStats.TotalRows
+				stats.addVerifyResults(verifyStats.Tables)
+				if err != nil {
+					for _, table := range verifyStats.FailedTables {
+						failed = append(failed, table)
+						stats.TableErrors = append(stats.TableErrors, &TableError{Table: table, Phase: "verify", Err: err})
+					}
+				}
 			}
 		}
 
+		if len(failed) > 0 {
+			if o.processingCfg.DeadLetter {
+				if err := o.recordDeadLetters(ctx, stats.TableErrors, discovered, verifyStats, resumeMgr); err != nil {
+					return stats, err
+				}
+			}
+			partial, err := o.finishPartialBatch(ctx, stats, rootIDs, advanceCheckpoint, recordSet, failed,
+				deletePhase, fetcher, resumeMgr, lagMonitor)
+			if err == nil {
+				o.reportStatus(checkpoint, rootIDs, StatusFailed)
+			}
+			return partial, err
+		}
+		if !o.verificationCfg.SkipVerification {
+			o.reportStatus(checkpoint, rootIDs, StatusVerified)
+		}
+
 		// T1.5: durable "copy+verify succeeded, safe to delete" marker.
 		if err := resumeMgr.MarkBatchCopied(ctx, o.jobName, rootIDs); err != nil {
 			return stats, fmt.Errorf("mark batch copied failed: %w", err)
 		}
 	}
 
+	// Unconfirmed, the batch stays copied and pending, and a confirmed run
+	// replays its delete.
+	if err := o.deleteCap.confirmed(); err != nil {
+		return stats, err
+	}
+
 	// Re-check replication lag immediately before the binlog-heavy delete phase
 	// (issue #2). The pre-batch check above can be stale by now: copy+verify may
 	// have taken many seconds, during which lag can climb back above threshold.
@@ -540,14 +938,21 @@ func (o *ArchiveOrchestrator) processBatch(
 		}
 	}
 
-	deleteStats, err := deletePhase.Delete(ctx, recordSet)
+	var deleteStats *DeleteStats
+	err = o.runPhase(ctx, PhaseDelete, info, func(ctx context.Context) (err error) {
+		deleteStats, err = deletePhase.Delete(ctx, recordSet)
+		return err
+	})
 	if err != nil {
 		return stats, fmt.Errorf("delete failed: %w", err)
 	}
 	stats.RecordsDeleted = deleteStats.RowsDeleted
+	stats.addDeleted(deleteStats.RowsPerTable)
+	o.reportStatus(checkpoint, rootIDs, StatusDeleted)
 
-	// T3: atomic completion (+ optional checkpoint). rootIDs come from a numeric
-	// ORDER BY pkColumn ASC on the main loop, so the last element is the max PK.
+	// T3: atomic completion (+ optional checkpoint). rootIDs come from the
+	// fetcher's ORDER BY pkColumn on the main loop, so the last element is the
+	// max PK (the min with root_order desc) and the next page starts past it.
 	var checkpointPK interface{}
 	if advanceCheckpoint {
 		checkpointPK = rootIDs[len(rootIDs)-1]
@@ -557,17 +962,195 @@ func (o *ArchiveOrchestrator) processBatch(
 	}
 	if advanceCheckpoint {
 		fetcher.UpdateCheckpoint(checkpointPK)
+		o.progress.checkpointed(checkpointPK)
 	}
 
-	if checkpoint != nil {
-		for _, rootID := range rootIDs {
-			if err := checkpoint(rootID, "completed"); err != nil {
-				o.logger.Warnw("Checkpoint callback failed", "error", err)
+	o.reportStatus(checkpoint, rootIDs, StatusCompleted)
+
+	stats.RootsProcessed = len(rootIDs)
+	o.progress.rootsDone(stats)
+	return stats, nil
+}
+
+// reportStatus invokes checkpoint, when non-nil, with status for each root.
+// Callback errors are logged and never fail the batch.
+func (o *ArchiveOrchestrator) reportStatus(checkpoint CheckpointCallback, rootIDs []interface{}, status CheckpointStatus) {
+	if checkpoint == nil {
+		return
+	}
+	for _, rootID := range rootIDs {
+		if err := checkpoint(rootID, status); err != nil {
+			o.logger.Warnw("Checkpoint callback failed", "status", status, "error", err)
+		}
+	}
+}
+
+// recordDeadLetters writes the rows of each failed table to the dead-letter
+// table (processing.dead_letter): the mismatched PKs of a verification
+// failure when the verifier could list them, otherwise every PK of the table
+// in the batch. finishPartialBatch keeps all of them in the source.
+func (o *ArchiveOrchestrator) recordDeadLetters(ctx context.Context, tableErrors []error,
+	discovered *types.RecordSet, verifyStats *verifier.VerifyStats, resumeMgr *ResumeManager) error {
+	mismatched := make(map[string][]interface{})
+	if verifyStats != nil {
+		for _, result := range verifyStats.Tables {
+			if len(result.MismatchedPKs) > 0 {
+				mismatched[result.Table] = result.MismatchedPKs
 			}
 		}
 	}
+	for _, e := range tableErrors {
+		var tableErr *TableError
+		if !errors.As(e, &tableErr) {
+			continue
+		}
+		pks := discovered.Records[tableErr.Table]
+		if tableErr.Phase == "verify" && mismatched[tableErr.Table] != nil {
+			pks = mismatched[tableErr.Table]
+		}
+		if err := resumeMgr.RecordFailedRecords(ctx, o.jobName, tableErr.Table, pks, tableErr.Err); err != nil {
+			return fmt.Errorf("dead-letter recording failed: %w", err)
+		}
+	}
+	return nil
+}
 
-	stats.RootsProcessed = len(rootIDs)
+// finishPartialBatch completes a batch in which some tables failed copy or
+// verification under continue_on_error. Every failed table is kept in the
+// source together with its descendants (not copied or not verified) and its
+// ancestors (their rows are still referenced by the kept rows); the remaining
+// clean branches are deleted. The root table is an ancestor of every table,
+// so the batch's roots stay 'pending' and the next run's resume retries them
+// through the full pipeline. The checkpoint still advances so this run moves
+// on to the next batch instead of refetching the same roots.
+func (o *ArchiveOrchestrator) finishPartialBatch(
+	ctx context.Context,
+	stats *BatchStats,
+	rootIDs []interface{},
+	advanceCheckpoint bool,
+	recordSet *RecordSet,
+	failed []string,
+	deletePhase *DeletePhase,
+	fetcher *RootIDFetcher,
+	resumeMgr *ResumeManager,
+	lagMonitor lagWaiter,
+) (*BatchStats, error) {
+	kept := o.graphBranches(failed, true)
+	o.logger.Warnw("Tables failed copy or verification - deleting clean branches only",
+		"failed", failed,
+		"kept", len(kept),
+	)
+
+	deleteSet := withoutTables(recordSet, kept)
+	if len(deleteSet.Records) > 0 {
+		if err := o.deleteCap.confirmed(); err != nil {
+			return stats, err
+		}
+		if lagMonitor != nil {
+			if err := lagMonitor.WaitForLag(ctx); err != nil {
+				return stats, fmt.Errorf("lag monitor error before delete: %w", err)
+			}
+		}
+		var deleteStats *DeleteStats
+		err := o.runPhase(ctx, PhaseDelete, PhaseInfo{JobName: o.jobName, RootPKs: rootIDs, Records: deleteSet}, func(ctx context.Context) (err error) {
+			deleteStats, err = deletePhase.Delete(ctx, deleteSet)
+			return err
+		})
+		if err != nil {
+			return stats, fmt.Errorf("delete failed: %w", err)
+		}
+		stats.RecordsDeleted = deleteStats.RowsDeleted
+		stats.addDeleted(deleteStats.RowsPerTable)
+	}
+
+	if advanceCheckpoint {
+		checkpointPK := rootIDs[len(rootIDs)-1]
+		if err := resumeMgr.CompleteBatch(ctx, o.jobName, nil, checkpointPK); err != nil {
+			return stats, fmt.Errorf("batch completion bookkeeping failed: %w", err)
+		}
+		fetcher.UpdateCheckpoint(checkpointPK)
+		o.progress.checkpointed(checkpointPK)
+	}
+	o.progress.rootsDone(stats)
+	return stats, nil
+}
+
+// graphBranches returns tables plus all their descendants and, with
+// ancestors set, all their ancestors.
+func (o *ArchiveOrchestrator) graphBranches(tables []string, ancestors bool) map[string]bool {
+	branch := make(map[string]bool)
+	for _, table := range tables {
+		branch[table] = true
+		for _, d := range o.graph.DescendantsOf(table) {
+			branch[d] = true
+		}
+		if ancestors {
+			for _, a := range o.graph.AncestorsOf(table) {
+				branch[a] = true
+			}
+		}
+	}
+	return branch
+}
+
+// withoutTables returns a shallow copy of rs without the records of drop.
+func withoutTables(rs *types.RecordSet, drop map[string]bool) *types.RecordSet {
+	out := &types.RecordSet{
+		RootPKs: rs.RootPKs,
+		Records: make(map[string][]interface{}, len(rs.Records)),
+		Stats:   rs.Stats,
+	}
+	for table, pks := range rs.Records {
+		if !drop[table] {
+			out.Records[table] = pks
+		}
+	}
+	return out
+}
+
+// processBatchGated is processBatch for verification.gate_deletes: each root
+// PK runs copy→verify→delete→complete on its own, so a verification failure
+// stops the batch before that root's rows are deleted. Roots before it are
+// already completed; it and the roots after it stay pending for replay. The
+// checkpoint only advances once every root has completed.
+func (o *ArchiveOrchestrator) processBatchGated(
+	ctx context.Context,
+	rootIDs []interface{},
+	advanceCheckpoint bool,
+	checkpoint CheckpointCallback,
+	discovery *RecordDiscovery,
+	copyPhase *CopyPhase,
+	dataVerifier *verifier.Verifier,
+	deletePhase *DeletePhase,
+	fetcher *RootIDFetcher,
+	resumeMgr *ResumeManager,
+	lagMonitor lagWaiter,
+) (*BatchStats, error) {
+	stats := &BatchStats{}
+	for _, rootID := range rootIDs {
+		rootStats, err := o.processBatch(ctx, []interface{}{rootID}, batchFull, false, checkpoint,
+			discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, lagMonitor)
+		stats.RecordsCopied += rootStats.RecordsCopied
+		stats.RecordsSkipped += rootStats.RecordsSkipped
+		stats.RecordsDeleted += rootStats.RecordsDeleted
+		stats.TablesVerified += rootStats.TablesVerified
+		stats.RecordsVerified += rootStats.RecordsVerified
+		stats.RootsProcessed += rootStats.RootsProcessed
+		stats.TableErrors = append(stats.TableErrors, rootStats.TableErrors...)
+		mergeTableResults(&stats.Tables, rootStats.Tables)
+		if err != nil {
+			return stats, fmt.Errorf("root pk=%v: %w", rootID, err)
+		}
+	}
+
+	if advanceCheckpoint {
+		checkpointPK := rootIDs[len(rootIDs)-1]
+		if err := resumeMgr.CompleteBatch(ctx, o.jobName, nil, checkpointPK); err != nil {
+			return stats, fmt.Errorf("batch completion bookkeeping failed: %w", err)
+		}
+		fetcher.UpdateCheckpoint(checkpointPK)
+		o.progress.checkpointed(checkpointPK)
+	}
 	return stats, nil
 }
 
@@ -598,7 +1181,7 @@ func (o *ArchiveOrchestrator) resumePending(
 	}
 
 	// count-mode cannot safely re-derive ANY non-terminal rows.
-	if o.verificationCfg.EffectiveMethod() == "count" {
+	if o.weakestVerificationMethod() == "count" {
 		total := len(copied) + len(pending)
 		preview := append(append([]string{}, copied...), pending...)
 		if len(preview) > 10 {
@@ -630,7 +1213,7 @@ func (o *ArchiveOrchestrator) resumePending(
 		}
 		return fmt.Errorf(
 			"job %q has %d 'pending' root PKs from a prior interrupted run and uses strict INSERT "+
-				"(forced by --skip-verify or a destination secondary unique index), so they cannot be "+
+				"(forced by --skip-verify, a destination secondary unique index, or processing.copy_mode: insert), so they cannot be "+
 				"safely re-copied (their destination rows may already be committed, and a strict INSERT "+
 				"aborts on duplicate).\n\n"+
 				"To recover, choose one:\n"+
@@ -724,15 +1307,20 @@ func (o *ArchiveOrchestrator) recoverChunks(
 				return fmt.Errorf("lag monitor error: %w", err)
 			}
 		}
-		batchStats, err := o.processBatch(ctx, typed, mode, false /* advanceCheckpoint */, checkpoint,
+		batchCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
+		batchStats, err := o.processBatch(batchCtx, typed, mode, false /* advanceCheckpoint */, checkpoint,
 			discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, lagMonitor)
+		release()
 		if err != nil {
 			return fmt.Errorf("recovery processBatch failed: %w", err)
 		}
 		result.RecordsCopied += batchStats.RecordsCopied
+		result.RecordsSkipped += batchStats.RecordsSkipped
 		result.RecordsDeleted += batchStats.RecordsDeleted
 		result.TablesVerified += batchStats.TablesVerified
 		result.RecordsVerified += batchStats.RecordsVerified
+		mergeTableResults(&result.Tables, batchStats.Tables)
+		result.Errors = append(result.Errors, batchStats.TableErrors...)
 	}
 	return nil
 }
@@ -778,6 +1366,16 @@ func (o *ArchiveOrchestrator) SetForce(force bool) {
 	o.force = force
 }
 
+// SetForceMaxDeleteRows lets the run delete past safety.max_delete_rows.
+func (o *ArchiveOrchestrator) SetForceMaxDeleteRows(force bool) {
+	o.deleteCap.override = force
+}
+
+// SetSkipMaintenance skips processing.post_copy_maintenance for this run.
+func (o *ArchiveOrchestrator) SetSkipMaintenance(skip bool) {
+	o.skipMaintenance = skip
+}
+
 // SetStopChannel wires the cooperative graceful-stop signal. When the channel
 // closes (first Ctrl-C), the batch loop finishes the in-flight batch and stops at
 // the next boundary. A nil channel disables cooperative stop.
@@ -785,12 +1383,52 @@ func (o *ArchiveOrchestrator) SetStopChannel(stop <-chan struct{}) {
 	o.stopCh = stop
 }
 
+// SetStopMode selects whether a cooperative stop lets the in-flight batch
+// finish (StopAfterBatch, the default) or cancels it (StopImmediately).
+func (o *ArchiveOrchestrator) SetStopMode(mode StopMode) {
+	o.stopMode = mode
+}
+
 // SetLogger sets a custom logger for the orchestrator. Call before
 // Initialize/Execute so all phases inherit it.
 func (o *ArchiveOrchestrator) SetLogger(log *logger.Logger) {
 	o.logger = log
 }
 
+// Snapshot returns the current state of the running job: phase, table,
+// batch and record counters and the last checkpoint PK. It is safe to call
+// from any goroutine, e.g. a signal handler or an HTTP status endpoint.
+func (o *ArchiveOrchestrator) Snapshot() Snapshot {
+	s := o.progress.snapshot()
+	s.JobName = o.jobName
+	return s
+}
+
+// LogSnapshot writes Snapshot to the orchestrator's log without
+// interrupting the job.
+func (o *ArchiveOrchestrator) LogSnapshot() {
+	logSnapshot(o.logger, o.Snapshot())
+}
+
+// runPhase runs fn as phase of the batch in info, recording it in the run
+// progress and between the registered hooks. A SELECT aborted at
+// statement_timeout is reported as a *StatementTimeoutError.
+func (o *ArchiveOrchestrator) runPhase(ctx context.Context, phase string, info PhaseInfo, fn func(ctx context.Context) error) error {
+	o.progress.enterPhase(phase, len(info.RootPKs))
+	defer o.progress.leavePhase()
+	err := runPhase(ctx, o.hooks, o.logger, phase, phaseTimeout(o.processingCfg, phase), info, fn)
+	return asStatementTimeout(err, o.processingCfg.StatementTimeout)
+}
+
+// SetHooks registers callbacks run around each batch's discovery, copy,
+// verify and delete phases. nil restores NoopHooks.
+func (o *ArchiveOrchestrator) SetHooks(hooks Hooks) {
+	if hooks == nil {
+		hooks = NoopHooks{}
+	}
+	o.hooks = hooks
+}
+
 // convertRecordSet converts types.RecordSet to archiver.RecordSet
 func convertRecordSet(ts *types.RecordSet) *RecordSet {
 	return &RecordSet{
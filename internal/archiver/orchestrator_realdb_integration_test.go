@@ -151,8 +151,8 @@ func TestExecute_CheckpointCallback(t *testing.T) {
 	}
 
 	var checkpointCalled int
-	var lastStatus string
-	checkpoint := func(rootPK interface{}, status string) error {
+	var lastStatus CheckpointStatus
+	checkpoint := func(rootPK interface{}, status CheckpointStatus) error {
 		checkpointCalled++
 		lastStatus = status
 		return nil
@@ -170,7 +170,7 @@ func TestExecute_CheckpointCallback(t *testing.T) {
 		t.Error("Checkpoint callback was not called")
 	}
 
-	if lastStatus != "completed" {
+	if lastStatus != StatusCompleted {
 		t.Errorf("Expected final status 'completed', got %s", lastStatus)
 	}
 }
@@ -202,7 +202,7 @@ func TestExecute_CheckpointCallbackError(t *testing.T) {
 	}
 
 	checkpointError := errors.New("checkpoint failed")
-	checkpoint := func(rootPK interface{}, status string) error {
+	checkpoint := func(rootPK interface{}, status CheckpointStatus) error {
 		return checkpointError
 	}
 
@@ -2,16 +2,20 @@ package archiver
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/database"
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/types"
 	"github.com/dbsmedya/goarchive/internal/verifier"
 	"github.com/stretchr/testify/require"
 )
@@ -207,6 +211,46 @@ func TestInitialize_Success(t *testing.T) {
 	}
 }
 
+func TestInitialize_RefusesSameDatabase(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *config.Config)
+		wantErr bool
+	}{
+		{"same server and schema", func(cfg *config.Config) {
+			cfg.Destination.Host, cfg.Destination.Port, cfg.Destination.Database = "127.0.0.1", 3306, cfg.Source.Database
+		}, true},
+		{"same server and schema with override", func(cfg *config.Config) {
+			cfg.Destination.Host, cfg.Destination.Port, cfg.Destination.Database = "127.0.0.1", 3306, cfg.Source.Database
+			cfg.Safety.AllowSameDatabase = true
+		}, false},
+		{"same server different schema", func(cfg *config.Config) {
+			cfg.Destination.Host, cfg.Destination.Port, cfg.Destination.Database = "localhost", 3306, "test_archive"
+		}, false},
+		{"distinct servers", func(cfg *config.Config) {}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			tt.mutate(cfg)
+			orch, err := NewOrchestrator(cfg, "test_job", createTestJobConfig(), mockDBManager(cfg))
+			require.NoError(t, err)
+			err = orch.Initialize()
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "same database")
+				require.False(t, orch.initialized)
+				return
+			}
+			require.NoError(t, err)
+
+			copyOnly, err := NewCopyOnlyOrchestrator(cfg, "test_job", createTestJobConfig(), mockDBManager(cfg))
+			require.NoError(t, err)
+			require.NoError(t, copyOnly.Initialize())
+		})
+	}
+}
+
 func TestInitialize_Idempotent(t *testing.T) {
 	cfg := createTestConfig()
 	jobCfg := createTestJobConfig()
@@ -319,6 +363,23 @@ func TestValidateGraph_Cycle(t *testing.T) {
 	}
 }
 
+func TestCheckReferenceKeys_Mismatch(t *testing.T) {
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", &graph.Node{ForeignKey: "user_id", ReferenceKey: "uuid", DependencyType: "1-N"})
+	g.SetPK("orders", "id")
+	g.AddEdgeWithMeta("users", "orders", "user_id", "uuid", "1-N")
+
+	err := checkReferenceKeys(g)
+	if err == nil || !strings.Contains(err.Error(), "edge users -> orders references users.uuid") {
+		t.Fatalf("expected reference key mismatch error, got %v", err)
+	}
+
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	if err := checkReferenceKeys(g); err != nil {
+		t.Errorf("expected no error after fixing the reference key, got %v", err)
+	}
+}
+
 // ============================================================================
 // Execute Tests
 // ============================================================================
@@ -541,6 +602,52 @@ func TestProcessBatchDeleteOnlySkipsCopyVerify(t *testing.T) {
 	require.NoError(t, archMock.ExpectationsWereMet())
 }
 
+// TestProcessBatchTransactionalDeleteFailureSkipsCompletion proves that with
+// safety.transactional_delete a failed COMMIT leaves the batch incomplete: the
+// T3 CompleteBatch bookkeeping (and thus the checkpoint) must not advance.
+func TestProcessBatchTransactionalDeleteFailureSkipsCompletion(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, _, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createSimpleGraph()
+	log := logger.NewDefault()
+
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodSHA256, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	deletePhase.SetTransactional(true)
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	o := &ArchiveOrchestrator{
+		jobName:       "job1",
+		logger:        log,
+		graph:         g,
+		processingCfg: config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000},
+	}
+
+	sourceMock.ExpectBegin()
+	sourceMock.ExpectExec("DELETE FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectCommit().WillReturnError(errors.New("connection lost"))
+
+	_, err := o.processBatch(context.Background(), []interface{}{int64(1)},
+		batchDeleteOnly, true, nil,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "commit")
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
 // TestProcessBatchDeleteOnlyLagErrorGatesDelete proves the pre-delete lag
 // re-check (issue #2) gates the delete phase in batchDeleteOnly mode: when
 // WaitForLag errors, neither the source DELETE nor the T3 CompleteBatch
@@ -780,3 +887,797 @@ func TestResumePendingRefusesStrictInsertWithPending(t *testing.T) {
 	}
 	require.NoError(t, archMock.ExpectationsWereMet())
 }
+
+func newRoutingTestGraph() *graph.Graph {
+	g := graph.NewGraph("users", "id")
+	g.AddNode("orders", &graph.Node{Name: "orders", ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	return g
+}
+
+func TestJobPhases_ReadReplicaRouting(t *testing.T) {
+	primaryDB, primaryMock, _ := sqlmock.New()
+	defer func() { _ = primaryDB.Close() }()
+	replicaDB, replicaMock, _ := sqlmock.New()
+	defer func() { _ = replicaDB.Close() }()
+
+	dbm := &database.Manager{Source: primaryDB, SourceRead: replicaDB}
+	g := newRoutingTestGraph()
+	processing := config.ProcessingConfig{BatchSize: 100, BatchDeleteSize: 100}
+	log := logger.NewDefault()
+
+	// Discovery reads from the replica only.
+	replicaMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+
+	discovery, err := newJobDiscovery(dbm, g, processing, log)
+	require.NoError(t, err)
+	rs, err := discovery.Discover(context.Background(), []interface{}{1})
+	require.NoError(t, err)
+
+	// Deletes go to the primary only.
+	primaryMock.ExpectExec("DELETE FROM `orders`").WillReturnResult(sqlmock.NewResult(0, 1))
+	primaryMock.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	deletePhase, err := newJobDeletePhase(dbm, g, processing, log)
+	require.NoError(t, err)
+	_, err = deletePhase.Delete(context.Background(), convertRecordSet(rs))
+	require.NoError(t, err)
+
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+	require.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestJobPhases_NoReadReplicaUsesPrimary(t *testing.T) {
+	primaryDB, primaryMock, _ := sqlmock.New()
+	defer func() { _ = primaryDB.Close() }()
+
+	dbm := &database.Manager{Source: primaryDB}
+	primaryMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `user_id` IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	discovery, err := newJobDiscovery(dbm, newRoutingTestGraph(), config.ProcessingConfig{BatchSize: 100}, logger.NewDefault())
+	require.NoError(t, err)
+	_, err = discovery.Discover(context.Background(), []interface{}{1})
+	require.NoError(t, err)
+	require.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+// TestJobVerifier_RelationCountOverrideKeepsJobMethod proves a relation's
+// verification_method: count only weakens that table: its siblings still
+// verify with the job's sha256 method.
+func TestJobVerifier_RelationCountOverrideKeepsJobMethod(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	jobCfg := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N", VerificationMethod: "count"},
+			{Table: "profiles", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-1"},
+		},
+	}
+	g, err := graph.NewBuilder(jobCfg).Build()
+	require.NoError(t, err)
+	verification := jobCfg.GetJobVerification(config.VerificationConfig{Method: "sha256"})
+
+	dbm := &database.Manager{Source: sourceDB, Destination: destDB}
+	v, err := newJobVerifier(dbm, g, verification, config.ProcessingConfig{BatchSize: 100}, logger.NewDefault())
+	require.NoError(t, err)
+
+	rowsFor := func(id int) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id"}).AddRow(id, 1)
+	}
+	for _, mock := range []sqlmock.Sqlmock{sourceMock, destMock} {
+		mock.MatchExpectationsInOrder(false)
+		mock.ExpectQuery("SELECT \\* FROM `users` WHERE `id` IN \\(\\?\\) ORDER BY `id`").
+			WithArgs(1).WillReturnRows(rowsFor(1))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders`").
+			WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+		mock.ExpectQuery("SELECT \\* FROM `profiles` WHERE `id` IN \\(\\?\\) ORDER BY `id`").
+			WithArgs(20).WillReturnRows(rowsFor(20))
+	}
+
+	stats, err := v.Verify(context.Background(), &types.RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{"users": {1}, "orders": {10}, "profiles": {20}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]verifier.VerificationMethod{
+		"users": verifier.MethodSHA256, "orders": verifier.MethodCount, "profiles": verifier.MethodSHA256,
+	}, stats.MethodPerTable)
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+}
+
+// signalingLagWaiter simulates a SIGTERM that arrives mid-batch: the pre-delete
+// lag re-check (after copy+verify+MarkBatchCopied) closes the stop channel,
+// then gives an immediate-mode batch context time to observe it.
+type signalingLagWaiter struct {
+	stop chan struct{}
+}
+
+func (s *signalingLagWaiter) WaitForLag(ctx context.Context) error {
+	close(s.stop)
+	select {
+	case <-ctx.Done():
+	case <-time.After(200 * time.Millisecond):
+	}
+	return nil
+}
+
+// newStopModeFixture wires a batchFull pipeline over the single-table
+// customers graph and expects the copy and MarkBatchCopied of root 20.
+func newStopModeFixture(t *testing.T, mode StopMode) (*ArchiveOrchestrator, sqlmock.Sqlmock, sqlmock.Sqlmock, sqlmock.Sqlmock, func(ctx context.Context) (*RootIDFetcher, error)) {
+	t.Helper()
+	sourceDB, sourceMock, _ := sqlmock.New()
+	t.Cleanup(func() { _ = sourceDB.Close() })
+	destDB, destMock, _ := sqlmock.New()
+	t.Cleanup(func() { _ = destDB.Close() })
+	archDB, archMock, _ := sqlmock.New()
+	t.Cleanup(func() { _ = archDB.Close() })
+
+	g := createSimpleGraph()
+	g.SetRootPKMeta("bigint", false)
+	log := logger.NewDefault()
+
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodSHA256, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	stop := make(chan struct{})
+	o := &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000},
+		verificationCfg: config.VerificationConfig{Method: "sha256", SkipVerification: true},
+	}
+	o.SetStopChannel(stop)
+	o.SetStopMode(mode)
+
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(20)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(20, "p"))
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").WillReturnResult(sqlmock.NewResult(0, 1))
+	destMock.ExpectCommit()
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "20").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// run processes root 20 the way Execute's batch loop does and returns the
+	// fetcher so the caller can inspect the in-memory checkpoint.
+	run := func(ctx context.Context) (*RootIDFetcher, error) {
+		fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, int64(10))
+		batchCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
+		defer release()
+		_, err := o.processBatch(batchCtx, []interface{}{int64(20)}, batchFull, true, nil,
+			discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, &signalingLagWaiter{stop: stop})
+		return fetcher, err
+	}
+	return o, sourceMock, destMock, archMock, run
+}
+
+// TestStopAfterBatch_SignalMidBatchCompletesCheckpoint proves the default stop
+// mode lets a batch interrupted by SIGTERM run to completion: the delete runs
+// and the checkpoint commits at the batch's last PK, so the loop-top
+// stopRequested check then stops at a clean boundary.
+func TestStopAfterBatch_SignalMidBatchCompletesCheckpoint(t *testing.T) {
+	o, sourceMock, destMock, archMock, run := newStopModeFixture(t, StopAfterBatch)
+
+	sourceMock.ExpectExec("DELETE FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(20)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectBegin()
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "20").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectExec("UPDATE .*archiver_job.* SET last_processed_root_pk_id").
+		WithArgs("20", "job1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectCommit()
+
+	fetcher, err := run(context.Background())
+	require.NoError(t, err)
+	require.True(t, stopRequested(o.stopCh), "the simulated signal must have fired mid-batch")
+	require.Equal(t, int64(20), fetcher.checkpoint, "checkpoint must reflect the completed batch")
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// TestStopImmediately_SignalMidBatchLeavesCheckpoint proves the immediate
+// stop mode cancels the in-flight batch: no source DELETE, no completion
+// transaction, and the checkpoint stays at the previous completed batch.
+func TestStopImmediately_SignalMidBatchLeavesCheckpoint(t *testing.T) {
+	_, sourceMock, destMock, archMock, run := newStopModeFixture(t, StopImmediately)
+
+	fetcher, err := run(context.Background())
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled), "expected cancellation, got %v", err)
+	require.Equal(t, int64(10), fetcher.checkpoint, "checkpoint must not advance past the last completed batch")
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// slowLagWaiter makes a batch outlive a tiny runtime budget.
+type slowLagWaiter struct {
+	d time.Duration
+}
+
+func (s slowLagWaiter) WaitForLag(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.d):
+		return nil
+	}
+}
+
+// TestRuntimeBudget_StopsBetweenBatches proves processing.max_runtime never
+// interrupts a batch: a budget that expires mid-batch lets the batch delete
+// and commit its checkpoint, and the loop then stops at the boundary with
+// ErrRuntimeBudgetExceeded while leaving the job resumable.
+func TestRuntimeBudget_StopsBetweenBatches(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createSimpleGraph()
+	g.SetRootPKMeta("bigint", false)
+	log := logger.NewDefault()
+
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodSHA256, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	o := &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000, MaxRuntime: 10 * time.Millisecond},
+		verificationCfg: config.VerificationConfig{Method: "sha256", SkipVerification: true},
+	}
+
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(20)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(20, "p"))
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").WillReturnResult(sqlmock.NewResult(0, 1))
+	destMock.ExpectCommit()
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "20").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectExec("DELETE FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(20)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectBegin()
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "20").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectExec("UPDATE .*archiver_job.* SET last_processed_root_pk_id").
+		WithArgs("20", "job1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectCommit()
+
+	ctx := context.Background()
+	budgetCtx, cancel := runtimeBudget(ctx, o.processingCfg.MaxRuntime)
+	defer cancel()
+	require.False(t, budgetExceeded(ctx, budgetCtx), "budget must not be spent before the first batch")
+
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, int64(10))
+	_, err := o.processBatch(ctx, []interface{}{int64(20)}, batchFull, true, nil,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, slowLagWaiter{d: 50 * time.Millisecond})
+	require.NoError(t, err, "an expiring budget must not interrupt the in-flight batch")
+	require.Equal(t, int64(20), fetcher.checkpoint, "checkpoint must reflect the completed batch")
+
+	// The next loop iteration stops at the boundary with the budget error.
+	require.True(t, budgetExceeded(ctx, budgetCtx))
+	require.ErrorIs(t, budgetErr(ctx, budgetCtx, budgetCtx.Err()), ErrRuntimeBudgetExceeded)
+	require.Equal(t, JobStatusIdle, finalJobStatus(fmt.Errorf("stop: %w", ErrRuntimeBudgetExceeded)),
+		"a budget stop must leave the job resumable, not failed")
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// expectGatedRootCopy expects discovery, copy and count verification of one
+// customers root with a single order; ordersDestCount is what the destination
+// reports for the order, so 0 makes verification fail on orders.
+func expectGatedRootCopy(sourceMock, destMock sqlmock.Sqlmock, rootID, orderID int64, ordersDestCount int) {
+	sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(rootID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(orderID))
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN").
+		WithArgs(rootID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(rootID, "c"))
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN").
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id"}).AddRow(orderID, rootID))
+	destMock.ExpectExec("INSERT IGNORE INTO `orders`").WillReturnResult(sqlmock.NewResult(0, 1))
+	destMock.ExpectCommit()
+
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `customers`").
+		WithArgs(rootID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `customers`").
+		WithArgs(rootID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders`").
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders`").
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(ordersDestCount))
+}
+
+// TestProcessBatchGateDeletes_VerificationFailureBlocksDelete proves that with
+// verification.gate_deletes a batch runs root by root: root 1 verifies and is
+// deleted and completed, root 2's orders fail verification, so neither of its
+// tables is ever deleted and the checkpoint does not advance.
+func TestProcessBatchGateDeletes_VerificationFailureBlocksDelete(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createMultiLevelGraph()
+	log := logger.NewDefault()
+
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodCount, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	o := &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000},
+		verificationCfg: config.VerificationConfig{Method: "count", GateDeletes: true},
+	}
+
+	// Root 1: verified, then deleted child-first and completed.
+	expectGatedRootCopy(sourceMock, destMock, 1, 10, 1)
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectExec("DELETE FROM `orders` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectExec("DELETE FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectBegin()
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectCommit()
+
+	// Root 2: the destination is missing its order. No MarkBatchCopied, no
+	// DELETE of orders or customers, no completion or checkpoint.
+	expectGatedRootCopy(sourceMock, destMock, 2, 20, 0)
+
+	stats, err := o.processBatch(context.Background(), []interface{}{int64(1), int64(2)},
+		batchFull, true, nil,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "root pk=2")
+	require.Contains(t, err.Error(), "verification failed")
+	require.Equal(t, 1, stats.RootsProcessed)
+	require.Equal(t, int64(2), stats.RecordsDeleted)
+	require.Nil(t, fetcher.checkpoint, "checkpoint must not advance past an unverified root")
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// TestProcessBatchGateDeletes_AdvancesCheckpointAfterAllRoots proves a fully
+// verified gated batch completes each root on its own and then commits the
+// batch checkpoint once.
+func TestProcessBatchGateDeletes_AdvancesCheckpointAfterAllRoots(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createMultiLevelGraph()
+	log := logger.NewDefault()
+
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodCount, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	o := &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000},
+		verificationCfg: config.VerificationConfig{Method: "count", GateDeletes: true},
+	}
+
+	for _, root := range []struct{ id, order int64 }{{1, 10}, {2, 20}} {
+		pk := fmt.Sprint(root.id)
+		expectGatedRootCopy(sourceMock, destMock, root.id, root.order, 1)
+		archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+			WithArgs(LogStatusCopied, pk).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		sourceMock.ExpectExec("DELETE FROM `orders`").WithArgs(root.order).WillReturnResult(sqlmock.NewResult(0, 1))
+		sourceMock.ExpectExec("DELETE FROM `customers`").WithArgs(root.id).WillReturnResult(sqlmock.NewResult(0, 1))
+		archMock.ExpectBegin()
+		archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+			WithArgs(LogStatusCompleted, pk).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		archMock.ExpectCommit()
+	}
+	archMock.ExpectBegin()
+	archMock.ExpectExec("UPDATE .*archiver_job.* SET last_processed_root_pk_id").
+		WithArgs("2", "job1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectCommit()
+
+	stats, err := o.processBatch(context.Background(), []interface{}{int64(1), int64(2)},
+		batchFull, true, nil,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.RootsProcessed)
+	require.Equal(t, int64(4), stats.RecordsDeleted)
+	require.Equal(t, int64(2), fetcher.checkpoint)
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// TestProcessBatch_CheckpointStatusSequence records the statuses reported to
+// the checkpoint callback: a verified batch walks every lifecycle state, and
+// a batch failing verification stops at copied and reports failed.
+func TestProcessBatch_CheckpointStatusSequence(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createMultiLevelGraph()
+	log := logger.NewDefault()
+
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodCount, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	o := &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000},
+		verificationCfg: config.VerificationConfig{Method: "count"},
+	}
+
+	var statuses []string
+	checkpoint := func(rootPK interface{}, status CheckpointStatus) error {
+		statuses = append(statuses, fmt.Sprintf("%v:%s", rootPK, status))
+		return nil
+	}
+
+	// Root 1 is verified, deleted and completed.
+	expectGatedRootCopy(sourceMock, destMock, 1, 10, 1)
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectExec("DELETE FROM `orders`").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(0, 1))
+	sourceMock.ExpectExec("DELETE FROM `customers`").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectBegin()
+	archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectCommit()
+
+	_, err := o.processBatch(context.Background(), []interface{}{int64(1)},
+		batchFull, false, checkpoint,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1:started", "1:copied", "1:verified", "1:deleted", "1:completed"}, statuses)
+
+	// Root 2's order is missing on the destination.
+	statuses = nil
+	expectGatedRootCopy(sourceMock, destMock, 2, 20, 0)
+
+	_, err = o.processBatch(context.Background(), []interface{}{int64(2)},
+		batchFull, false, checkpoint,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.Error(t, err)
+	require.Equal(t, []string{"2:started", "2:copied", "2:failed"}, statuses)
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// createBranchedGraph builds customers -> orders -> order_items plus a
+// sibling customers -> notes branch.
+func createBranchedGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	g, err := graph.BuildFromJob(&config.JobConfig{
+		RootTable:  "customers",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{
+				Table: "orders", PrimaryKey: "id", ForeignKey: "customer_id", DependencyType: "1-N",
+				Relations: []config.Relation{
+					{Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N"},
+				},
+			},
+			{Table: "notes", PrimaryKey: "id", ForeignKey: "customer_id", DependencyType: "1-N"},
+		},
+	})
+	require.NoError(t, err)
+	return g
+}
+
+func expectBranchedDiscovery(sourceMock sqlmock.Sqlmock) {
+	sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)))
+	sourceMock.ExpectQuery("SELECT `id` FROM `notes` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(30)))
+	sourceMock.ExpectQuery("SELECT `id` FROM `order_items` WHERE `order_id` IN \\(\\?\\)").
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(100)))
+}
+
+func expectCountMatch(sourceMock, destMock sqlmock.Sqlmock, table string, pk int64, destCount int) {
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `" + table + "`").
+		WithArgs(pk).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `" + table + "`").
+		WithArgs(pk).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(destCount))
+}
+
+func expectSavepointCopy(sourceMock, destMock sqlmock.Sqlmock, table string, pk int64, insertErr error) {
+	destMock.ExpectExec("SAVEPOINT goarchive_copy_table").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `" + table + "` WHERE `id` IN").
+		WithArgs(pk).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(pk))
+	insert := destMock.ExpectExec("INSERT IGNORE INTO `" + table + "`")
+	if insertErr != nil {
+		insert.WillReturnError(insertErr)
+		destMock.ExpectExec("ROLLBACK TO SAVEPOINT goarchive_copy_table").WillReturnResult(sqlmock.NewResult(0, 0))
+		return
+	}
+	insert.WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func newContinueOnErrorOrchestrator(t *testing.T, g *graph.Graph, sourceDB, destDB, archDB *sql.DB) (*ArchiveOrchestrator, *RecordDiscovery, *CopyPhase, *verifier.Verifier, *DeletePhase, *RootIDFetcher, *ResumeManager) {
+	t.Helper()
+	log := logger.NewDefault()
+	discovery, _ := NewRecordDiscovery(g, sourceDB, 1000)
+	copyPhase, _ := NewCopyPhase(sourceDB, destDB, g, config.SafetyConfig{}, log)
+	copyPhase.SetContinueOnError(true)
+	dataVerifier, _ := verifier.NewVerifier(sourceDB, destDB, g, verifier.MethodCount, log)
+	deletePhase, _ := NewDeletePhase(sourceDB, g, 1000, log)
+	fetcher := NewRootIDFetcher(sourceDB, "customers", "id", "", 1000, nil)
+	resumeMgr, _ := NewResumeManager(archDB, log, "testdb")
+	resumeMgr.setJobID(7)
+
+	o := &ArchiveOrchestrator{
+		jobName:         "job1",
+		logger:          log,
+		graph:           g,
+		processingCfg:   config.ProcessingConfig{BatchSize: 1000, BatchDeleteSize: 1000, ContinueOnError: true},
+		verificationCfg: config.VerificationConfig{Method: "count"},
+	}
+	return o, discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr
+}
+
+// expectPartialCompletion expects the checkpoint-only CompleteBatch of a
+// partial batch: the root stays pending (no log_status update).
+func expectPartialCompletion(archMock sqlmock.Sqlmock) {
+	archMock.ExpectBegin()
+	archMock.ExpectExec("UPDATE .*archiver_job.* SET last_processed_root_pk_id").
+		WithArgs("1", "job1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	archMock.ExpectCommit()
+}
+
+// TestProcessBatchContinueOnError_CopyFailureIsolatesBranch proves that with
+// processing.continue_on_error a failed orders copy is rolled back to its
+// savepoint, order_items (its descendant) is neither copied nor verified, the
+// clean notes branch is still copied, verified and deleted, and customers
+// (an ancestor of the failure) and the failed branch are never deleted.
+func TestProcessBatchContinueOnError_CopyFailureIsolatesBranch(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createBranchedGraph(t)
+	o, discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr := newContinueOnErrorOrchestrator(t, g, sourceDB, destDB, archDB)
+
+	expectBranchedDiscovery(sourceMock)
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSavepointCopy(sourceMock, destMock, "customers", 1, nil)
+	expectSavepointCopy(sourceMock, destMock, "orders", 10, fmt.Errorf("Unknown column 'legacy' in 'field list'"))
+	expectSavepointCopy(sourceMock, destMock, "notes", 30, nil)
+	destMock.ExpectCommit()
+
+	expectCountMatch(sourceMock, destMock, "customers", 1, 1)
+	expectCountMatch(sourceMock, destMock, "notes", 30, 1)
+
+	sourceMock.ExpectExec("DELETE FROM `notes` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(30)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPartialCompletion(archMock)
+
+	stats, err := o.processBatch(context.Background(), []interface{}{int64(1)},
+		batchFull, true, nil,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.NoError(t, err)
+	require.Len(t, stats.TableErrors, 1)
+	var tableErr *TableError
+	require.ErrorAs(t, stats.TableErrors[0], &tableErr)
+	require.Equal(t, "orders", tableErr.Table)
+	require.Equal(t, "copy", tableErr.Phase)
+	require.Equal(t, int64(1), stats.RecordsDeleted)
+	require.Equal(t, 0, stats.RootsProcessed, "a partial root is not completed")
+	require.Equal(t, int64(1), fetcher.checkpoint)
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// TestProcessBatchContinueOnError_VerifyFailureIsolatesBranch proves a
+// verification mismatch on order_items keeps it, orders and customers in the
+// source while the clean notes branch is deleted.
+func TestProcessBatchContinueOnError_VerifyFailureIsolatesBranch(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createBranchedGraph(t)
+	o, discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr := newContinueOnErrorOrchestrator(t, g, sourceDB, destDB, archDB)
+
+	expectBranchedDiscovery(sourceMock)
+
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSavepointCopy(sourceMock, destMock, "customers", 1, nil)
+	expectSavepointCopy(sourceMock, destMock, "orders", 10, nil)
+	expectSavepointCopy(sourceMock, destMock, "notes", 30, nil)
+	expectSavepointCopy(sourceMock, destMock, "order_items", 100, nil)
+	destMock.ExpectCommit()
+
+	expectCountMatch(sourceMock, destMock, "customers", 1, 1)
+	expectCountMatch(sourceMock, destMock, "orders", 10, 1)
+	expectCountMatch(sourceMock, destMock, "notes", 30, 1)
+	expectCountMatch(sourceMock, destMock, "order_items", 100, 0)
+
+	sourceMock.ExpectExec("DELETE FROM `notes` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(30)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPartialCompletion(archMock)
+
+	stats, err := o.processBatch(context.Background(), []interface{}{int64(1)},
+		batchFull, true, nil,
+		discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+	require.NoError(t, err)
+	require.Len(t, stats.TableErrors, 1)
+	var tableErr *TableError
+	require.ErrorAs(t, stats.TableErrors[0], &tableErr)
+	require.Equal(t, "order_items", tableErr.Table)
+	require.Equal(t, "verify", tableErr.Phase)
+	require.ErrorIs(t, tableErr, verifier.ErrMismatch)
+	require.Equal(t, int64(1), stats.RecordsDeleted)
+	require.Equal(t, &TableResult{RowsCopied: 1, RowsVerified: 1, VerifyFailures: 1}, stats.Tables["order_items"])
+	require.Equal(t, &TableResult{RowsCopied: 1, RowsVerified: 1, RowsDeleted: 1}, stats.Tables["notes"])
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
+
+// TestProcessBatchDeadLetter_RecordsRepeatedCopyFailure proves that with
+// processing.dead_letter an orders copy failing on two runs is recorded in
+// goarchive_failed_records both times (created once, then upserted so
+// attempts grows) while orders, order_items and customers are never deleted.
+func TestProcessBatchDeadLetter_RecordsRepeatedCopyFailure(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+	archDB, archMock, _ := sqlmock.New()
+	defer func() { _ = archDB.Close() }()
+
+	g := createBranchedGraph(t)
+	o, discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr := newContinueOnErrorOrchestrator(t, g, sourceDB, destDB, archDB)
+	o.processingCfg.DeadLetter = true
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		expectBranchedDiscovery(sourceMock)
+
+		destMock.ExpectBegin()
+		destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		expectSavepointCopy(sourceMock, destMock, "customers", 1, nil)
+		expectSavepointCopy(sourceMock, destMock, "orders", 10, fmt.Errorf("Incorrect string value: '\\xF0\\x9F' for column 'note'"))
+		expectSavepointCopy(sourceMock, destMock, "notes", 30, nil)
+		destMock.ExpectCommit()
+
+		expectCountMatch(sourceMock, destMock, "customers", 1, 1)
+		expectCountMatch(sourceMock, destMock, "notes", 30, 1)
+
+		if attempt == 1 {
+			archMock.ExpectExec("CREATE TABLE IF NOT EXISTS `testdb`.`goarchive_failed_records`").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		archMock.ExpectExec("INSERT INTO `testdb`.`goarchive_failed_records` .* VALUES \\(\\?, \\?, \\?, \\?, 1, NOW\\(\\)\\) "+
+			"ON DUPLICATE KEY UPDATE attempts = attempts \\+ 1").
+			WithArgs("job1", "orders", "10", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, int64(attempt)))
+
+		sourceMock.ExpectExec("DELETE FROM `notes` WHERE `id` IN \\(\\?\\)").
+			WithArgs(int64(30)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectPartialCompletion(archMock)
+
+		stats, err := o.processBatch(context.Background(), []interface{}{int64(1)},
+			batchFull, true, nil,
+			discovery, copyPhase, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+		require.NoError(t, err, "attempt %d", attempt)
+		require.Len(t, stats.TableErrors, 1)
+		require.Equal(t, int64(1), stats.RecordsDeleted, "only the clean notes branch is deleted")
+	}
+
+	require.NoError(t, sourceMock.ExpectationsWereMet())
+	require.NoError(t, destMock.ExpectationsWereMet())
+	require.NoError(t, archMock.ExpectationsWereMet())
+}
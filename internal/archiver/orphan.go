@@ -0,0 +1,248 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// Orphan check modes (safety.orphan_check).
+const (
+	OrphanCheckOff   = ""
+	OrphanCheckWarn  = "warn"
+	OrphanCheckAbort = "abort"
+)
+
+// OrphanReport describes child rows that reference to-be-deleted parent rows
+// but are not part of the discovered record set, so deleting the parents
+// would leave them dangling (or, with ON DELETE CASCADE, remove them without
+// an archived copy).
+type OrphanReport struct {
+	ParentTable string
+	ChildTable  string
+	ForeignKey  string
+	ChildPKs    []interface{} // undiscovered child PKs (capped at orphanSampleSize)
+	Count       int64         // total undiscovered child rows
+}
+
+// orphanSampleSize caps the child PKs kept per report for logging.
+const orphanSampleSize = 10
+
+func (r OrphanReport) String() string {
+	if len(r.ChildPKs) == 0 {
+		return fmt.Sprintf("%d row(s) in %s.%s reference %s through a foreign key the job does not declare",
+			r.Count, r.ChildTable, r.ForeignKey, r.ParentTable)
+	}
+	return fmt.Sprintf("%d row(s) in %s.%s reference %s but were not discovered (e.g. PKs %v)",
+		r.Count, r.ChildTable, r.ForeignKey, r.ParentTable, r.ChildPKs)
+}
+
+// OrphanError is returned by the delete phase in OrphanCheckAbort mode.
+type OrphanError struct {
+	Reports []OrphanReport
+}
+
+func (e *OrphanError) Error() string {
+	msgs := make([]string, len(e.Reports))
+	for i, r := range e.Reports {
+		msgs[i] = r.String()
+	}
+	return "referential integrity check failed, nothing deleted: " + strings.Join(msgs, "; ")
+}
+
+// findOrphans checks every graph edge whose parent has rows in recordSet:
+// it lists the child rows referencing those parent PKs on the delete
+// connection and reports any child PK missing from recordSet. Such rows were
+// inserted after discovery or are reached through a relation that is not
+// declared on this edge. Edges into a relation with discovery_query are not
+// checked: its foreign key alone does not say which rows belong to the
+// parent. Foreign keys of the source schema that reference a parent's
+// primary key but are not declared as a relation (see undeclaredForeignKeys)
+// are checked the same way; rows of a table outside the graph are only
+// counted. Run it immediately before deleting.
+func (dp *DeletePhase) findOrphans(ctx context.Context, recordSet *RecordSet) ([]OrphanReport, error) {
+	order, err := dp.graph.CopyOrderContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table order: %w", err)
+	}
+	undeclared, err := dp.undeclaredForeignKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []OrphanReport
+	for _, parent := range order {
+		parentPKs := recordSet.Records[parent]
+		if len(parentPKs) == 0 {
+			continue
+		}
+		children := append([]string(nil), dp.graph.GetChildren(parent)...)
+		sort.Strings(children)
+		for _, child := range children {
+			if node := dp.graph.GetNode(child); node != nil && node.DiscoveryQuery != "" {
+				continue
+			}
+			meta := dp.graph.GetEdgeMeta(parent, child)
+			if meta == nil || meta.ForeignKey == "" {
+				return nil, fmt.Errorf("no edge metadata for %s -> %s", parent, child)
+			}
+			report, err := dp.orphansOnEdge(ctx, parent, child, meta.ForeignKey, parentPKs, recordSet.Records[child])
+			if err != nil {
+				return nil, err
+			}
+			if report != nil {
+				reports = append(reports, *report)
+			}
+		}
+		for _, fk := range undeclared[parent] {
+			var report *OrphanReport
+			if dp.graph.GetNode(fk.table) != nil {
+				report, err = dp.orphansOnEdge(ctx, parent, fk.table, fk.column, parentPKs, recordSet.Records[fk.table])
+			} else {
+				report, err = dp.countReferencing(ctx, parent, fk.table, fk.column, parentPKs)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if report != nil {
+				reports = append(reports, *report)
+			}
+		}
+	}
+	return reports, nil
+}
+
+// referencingFK is a single-column foreign key: table.column references the
+// primary key of another table.
+type referencingFK struct {
+	table  string
+	column string
+}
+
+// undeclaredForeignKeys returns, per graph table, the single-column foreign
+// keys of the source schema that reference its primary key but are not
+// declared as a relation from it, ordered by table and column. They are read
+// from information_schema.KEY_COLUMN_USAGE once per DeletePhase.
+func (dp *DeletePhase) undeclaredForeignKeys(ctx context.Context) (map[string][]referencingFK, error) {
+	if dp.undeclaredFKs != nil {
+		return dp.undeclaredFKs, nil
+	}
+
+	tables := dp.graph.AllNodes()
+	args := make([]interface{}, len(tables))
+	for i, table := range tables {
+		args[i] = table
+	}
+	query := fmt.Sprintf(`
+		SELECT TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE kcu
+		WHERE TABLE_SCHEMA = DATABASE()
+		AND REFERENCED_TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IN (%s)
+		AND (SELECT COUNT(*) FROM information_schema.KEY_COLUMN_USAGE k2
+			WHERE k2.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+			AND k2.TABLE_NAME = kcu.TABLE_NAME
+			AND k2.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME) = 1
+		ORDER BY TABLE_NAME, COLUMN_NAME`, sqlutil.Placeholders(len(tables), ","))
+
+	rows, err := dp.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("orphan check: failed to read foreign keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	undeclared := make(map[string][]referencingFK)
+	for rows.Next() {
+		var fk referencingFK
+		var parent, parentColumn string
+		if err := rows.Scan(&fk.table, &fk.column, &parent, &parentColumn); err != nil {
+			return nil, fmt.Errorf("orphan check: failed to read foreign keys: %w", err)
+		}
+		if !strings.EqualFold(parentColumn, dp.graph.GetPK(parent)) {
+			continue
+		}
+		if meta := dp.graph.GetEdgeMeta(parent, fk.table); meta != nil && strings.EqualFold(meta.ForeignKey, fk.column) {
+			continue
+		}
+		undeclared[parent] = append(undeclared[parent], fk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("orphan check: failed to read foreign keys: %w", err)
+	}
+	dp.undeclaredFKs = undeclared
+	return undeclared, nil
+}
+
+// countReferencing reports the rows of child, a table outside the graph,
+// whose fk references one of parentPKs.
+func (dp *DeletePhase) countReferencing(ctx context.Context, parent, child, fk string, parentPKs []interface{}) (*OrphanReport, error) {
+	report := &OrphanReport{ParentTable: parent, ChildTable: child, ForeignKey: fk}
+	for _, chunk := range sqlutil.ChunkValues(parentPKs, sqlutil.InClauseSize(dp.batchSize, dp.maxIn)) {
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IN (%s)",
+			sqlutil.QuoteIdentifier(child),
+			sqlutil.QuoteIdentifier(fk),
+			sqlutil.Placeholders(len(chunk), ","),
+		)
+		var n int64
+		if err := dp.db.QueryRowContext(ctx, sqlutil.WithMaxExecutionTime(query, dp.stmtTimeout), chunk...).Scan(&n); err != nil {
+			return nil, fmt.Errorf("orphan check on %s failed: %w", child, err)
+		}
+		report.Count += n
+	}
+
+	if report.Count == 0 {
+		return nil, nil
+	}
+	return report, nil
+}
+
+func (dp *DeletePhase) orphansOnEdge(ctx context.Context, parent, child, fk string, parentPKs, discovered []interface{}) (*OrphanReport, error) {
+	known := make(map[string]struct{}, len(discovered))
+	for _, pk := range discovered {
+		key, err := formatPK(pk)
+		if err != nil {
+			return nil, fmt.Errorf("orphan check on %s: %w", child, err)
+		}
+		known[key] = struct{}{}
+	}
+
+	childPK := dp.graph.GetPK(child)
+	report := &OrphanReport{ParentTable: parent, ChildTable: child, ForeignKey: fk}
+	for _, chunk := range sqlutil.ChunkValues(parentPKs, sqlutil.InClauseSize(dp.batchSize, dp.maxIn)) {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)",
+			sqlutil.QuoteIdentifier(childPK),
+			sqlutil.QuoteIdentifier(child),
+			sqlutil.QuoteIdentifier(fk),
+			sqlutil.Placeholders(len(chunk), ","),
+		)
+		rows, err := dp.db.QueryContext(ctx, sqlutil.WithMaxExecutionTime(query, dp.stmtTimeout), chunk...)
+		if err != nil {
+			return nil, fmt.Errorf("orphan check on %s failed: %w", child, err)
+		}
+		pks, err := scanPKs(rows)
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("orphan check on %s: %w", child, err)
+		}
+		for _, pk := range pks {
+			key, err := formatPK(pk)
+			if err != nil {
+				return nil, fmt.Errorf("orphan check on %s: %w", child, err)
+			}
+			if _, ok := known[key]; ok {
+				continue
+			}
+			report.Count++
+			if len(report.ChildPKs) < orphanSampleSize {
+				report.ChildPKs = append(report.ChildPKs, key)
+			}
+		}
+	}
+
+	if report.Count == 0 {
+		return nil, nil
+	}
+	return report, nil
+}
@@ -0,0 +1,205 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// defaultOrphanScanBatchSize is the page size of the orphan scan when neither
+// the scanner nor the table sets a batch size.
+const defaultOrphanScanBatchSize = 1000
+
+// OrphanScanner finds child rows in the source whose foreign key points to a
+// parent row that no longer exists, for every child -> parent edge of the
+// job graph, and can delete them. Unlike the pre-delete orphan check, it
+// scans whole tables and is independent of any archive batch.
+type OrphanScanner struct {
+	db        *sql.DB
+	graph     *graph.Graph
+	batchSize int // scan page size; 0 => defaultOrphanScanBatchSize
+	logger    *logger.Logger
+
+	// deleteCap applies safety.max_delete_rows and
+	// safety.require_confirmation to Clean (SetDeleteGuards).
+	deleteCap deleteCap
+}
+
+// OrphanScanResult reports the orphaned rows found by a scan.
+type OrphanScanResult struct {
+	// Orphans maps each table to its orphaned PKs, deduplicated when a row
+	// is orphaned on more than one parent edge. Tables without orphans are
+	// absent.
+	Orphans       map[string][]interface{}
+	CountPerTable map[string]int64
+	RowsOrphaned  int64
+	// Deleted holds the delete statistics after Clean; nil after Scan.
+	Deleted *DeleteStats
+}
+
+// NewOrphanScanner creates an orphan scanner on the source database.
+func NewOrphanScanner(db *sql.DB, g *graph.Graph, log *logger.Logger) (*OrphanScanner, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("graph is nil")
+	}
+	if log == nil {
+		log = logger.NewDefault()
+	}
+	return &OrphanScanner{db: db, graph: g, logger: log}, nil
+}
+
+// SetBatchSize sets how many orphan PKs one scan query returns. Per-table
+// batch_size overrides still apply.
+func (s *OrphanScanner) SetBatchSize(n int) {
+	s.batchSize = n
+}
+
+// SetDeleteGuards makes Clean honour the same safety.max_delete_rows cap
+// and safety.require_confirmation token as archive and purge for job
+// jobName. force lifts the cap (--force-max-delete-rows).
+func (s *OrphanScanner) SetDeleteGuards(safety config.SafetyConfig, jobName string, job *config.JobConfig, force bool) {
+	s.deleteCap.reset(safety.MaxDeleteRows)
+	s.deleteCap.override = force
+	s.deleteCap.unconfirmed = confirmDeletes(safety, jobName, job)
+}
+
+// Scan lists the orphaned rows of every non-root table, in copy order. A row
+// with a NULL foreign key references nothing and is not an orphan. Tables
+// whose relation sets discovery_query are skipped, since their foreign key
+// alone does not identify the parent row.
+func (s *OrphanScanner) Scan(ctx context.Context) (*OrphanScanResult, error) {
+	order, err := s.graph.CopyOrderContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table order: %w", err)
+	}
+
+	result := &OrphanScanResult{
+		Orphans:       make(map[string][]interface{}),
+		CountPerTable: make(map[string]int64),
+	}
+	for _, child := range order {
+		if node := s.graph.GetNode(child); node != nil && node.DiscoveryQuery != "" {
+			continue
+		}
+		parents := append([]string(nil), s.graph.GetParents(child)...)
+		sort.Strings(parents)
+		seen := make(map[string]bool)
+		for _, parent := range parents {
+			meta := s.graph.GetEdgeMeta(parent, child)
+			if meta == nil || meta.ForeignKey == "" || meta.ReferenceKey == "" {
+				return nil, fmt.Errorf("no edge metadata for %s -> %s", parent, child)
+			}
+			pks, err := s.orphansOnEdge(ctx, parent, child, meta)
+			if err != nil {
+				return nil, err
+			}
+			for _, pk := range pks {
+				key, err := types.EncodePK(pk)
+				if err != nil {
+					return nil, fmt.Errorf("orphan scan on %s: %w", child, err)
+				}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				result.Orphans[child] = append(result.Orphans[child], pk)
+			}
+		}
+		if n := int64(len(result.Orphans[child])); n > 0 {
+			result.CountPerTable[child] = n
+			result.RowsOrphaned += n
+			s.logger.Infow("Found orphaned rows",
+				logger.FieldTable, child,
+				logger.FieldRows, n,
+			)
+		}
+	}
+	return result, nil
+}
+
+// Clean scans for orphans and deletes them through dp, which deletes in
+// reverse dependency order with its usual chunking, throttling and audit
+// log. Rows referencing a deleted orphan are orphaned in turn and are found
+// by the next scan. Nothing is deleted when the orphans exceed the
+// SetDeleteGuards cap or deletes are not confirmed.
+func (s *OrphanScanner) Clean(ctx context.Context, dp *DeletePhase) (*OrphanScanResult, error) {
+	if dp == nil {
+		return nil, fmt.Errorf("delete phase is nil")
+	}
+	result, err := s.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsOrphaned == 0 {
+		return result, nil
+	}
+	orphans := &RecordSet{Records: result.Orphans}
+	if err := s.deleteCap.confirmed(); err != nil {
+		return nil, err
+	}
+	if err := s.deleteCap.admit(orphans); err != nil {
+		return nil, err
+	}
+	stats, err := dp.Delete(ctx, orphans)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete orphaned rows: %w", err)
+	}
+	result.Deleted = stats
+	return result, nil
+}
+
+// orphansOnEdge pages through the child rows whose foreign key matches no
+// parent row, keyed on the child PK.
+func (s *OrphanScanner) orphansOnEdge(ctx context.Context, parent, child string, meta *graph.EdgeMeta) ([]interface{}, error) {
+	pkColumn := sqlutil.QuoteIdentifier(s.graph.GetPK(child))
+	fk := sqlutil.QuoteIdentifier(meta.ForeignKey)
+	ref := sqlutil.QuoteIdentifier(meta.ReferenceKey)
+	base := fmt.Sprintf("SELECT c.%s FROM %s c LEFT JOIN %s p ON p.%s = c.%s WHERE c.%s IS NOT NULL AND p.%s IS NULL",
+		pkColumn, sqlutil.QuoteIdentifier(child), sqlutil.QuoteIdentifier(parent), ref, fk, fk, ref)
+
+	limit := s.graph.BatchSizeFor(child, s.batchSize)
+	if limit <= 0 {
+		limit = defaultOrphanScanBatchSize
+	}
+
+	var orphans []interface{}
+	var last interface{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("orphan scan interrupted: %w", err)
+		}
+		query, args := base, []interface{}{}
+		if last != nil {
+			query += fmt.Sprintf(" AND c.%s > ?", pkColumn)
+			args = append(args, last)
+		}
+		query += fmt.Sprintf(" ORDER BY c.%s LIMIT %d", pkColumn, limit)
+
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("orphan scan on %s -> %s failed: %w", parent, child, err)
+		}
+		page, err := scanPKs(rows)
+		if closeErr := rows.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("orphan scan on %s -> %s: %w", parent, child, err)
+		}
+		orphans = append(orphans, page...)
+		if len(page) < limit {
+			return orphans, nil
+		}
+		last = page[len(page)-1]
+	}
+}
@@ -0,0 +1,144 @@
+package archiver
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const orphanScanOrdersQuery = "SELECT c.`id` FROM `orders` c LEFT JOIN `customers` p ON p.`id` = c.`customer_id` " +
+	"WHERE c.`customer_id` IS NOT NULL AND p.`id` IS NULL"
+
+func TestNewOrphanScanner_Validation(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	_, err := NewOrphanScanner(nil, createMultiLevelGraph(), nil)
+	require.Error(t, err)
+	_, err = NewOrphanScanner(db, nil, nil)
+	require.Error(t, err)
+	s, err := NewOrphanScanner(db, createMultiLevelGraph(), nil)
+	require.NoError(t, err)
+	require.NotNil(t, s.logger)
+}
+
+func TestOrphanScanner_ScanPagesByChildPK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	s, err := NewOrphanScanner(db, createMultiLevelGraph(), logger.NewDefault())
+	require.NoError(t, err)
+	s.SetBatchSize(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta(orphanScanOrdersQuery + " ORDER BY c.`id` LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)).AddRow(int64(7)))
+	mock.ExpectQuery(regexp.QuoteMeta(orphanScanOrdersQuery + " AND c.`id` > ? ORDER BY c.`id` LIMIT 2")).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(9)))
+
+	result, err := s.Scan(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int64(3), int64(7), int64(9)}, result.Orphans["orders"])
+	require.Equal(t, map[string]int64{"orders": 3}, result.CountPerTable)
+	require.Equal(t, int64(3), result.RowsOrphaned)
+	require.NotContains(t, result.Orphans, "customers")
+	require.Nil(t, result.Deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrphanScanner_CleanDeletesOnlyOrphans(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := createMultiLevelGraph()
+	s, err := NewOrphanScanner(db, g, logger.NewDefault())
+	require.NoError(t, err)
+	dp, err := NewDeletePhase(db, g, 500, logger.NewDefault())
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(orphanScanOrdersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)).AddRow(int64(7)))
+	// Only the orphaned orders are deleted; customers is never touched.
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `orders` WHERE `id` IN (?,?)")).
+		WithArgs(int64(3), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	result, err := s.Clean(context.Background(), dp)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.RowsOrphaned)
+	require.NotNil(t, result.Deleted)
+	require.Equal(t, int64(2), result.Deleted.RowsDeleted)
+	require.Equal(t, map[string]int64{"orders": 2}, result.Deleted.RowsPerTable)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrphanScanner_CleanNothingToDelete(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := createMultiLevelGraph()
+	s, err := NewOrphanScanner(db, g, logger.NewDefault())
+	require.NoError(t, err)
+	dp, err := NewDeletePhase(db, g, 500, logger.NewDefault())
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(orphanScanOrdersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	result, err := s.Clean(context.Background(), dp)
+	require.NoError(t, err)
+	require.Zero(t, result.RowsOrphaned)
+	require.Nil(t, result.Deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrphanScanner_CleanHonoursDeleteGuards(t *testing.T) {
+	job := &config.JobConfig{RootTable: "customers", Where: "1=1"}
+	for _, tt := range []struct {
+		name    string
+		safety  config.SafetyConfig
+		token   string
+		force   bool
+		wantErr error
+	}{
+		{name: "over the cap", safety: config.SafetyConfig{MaxDeleteRows: 1}, wantErr: ErrMaxDeleteRowsExceeded},
+		{name: "not confirmed", safety: config.SafetyConfig{RequireConfirmation: true}, wantErr: ErrDeleteNotConfirmed},
+		{name: "forced past the cap", safety: config.SafetyConfig{MaxDeleteRows: 1}, force: true},
+		{name: "confirmed", safety: config.SafetyConfig{RequireConfirmation: true}, token: job.ExpectedConfirmToken("job1")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer func() { _ = db.Close() }()
+
+			g := createMultiLevelGraph()
+			s, err := NewOrphanScanner(db, g, logger.NewDefault())
+			require.NoError(t, err)
+			guarded := *job
+			guarded.ConfirmToken = tt.token
+			s.SetDeleteGuards(tt.safety, "job1", &guarded, tt.force)
+			dp, err := NewDeletePhase(db, g, 500, logger.NewDefault())
+			require.NoError(t, err)
+
+			mock.ExpectQuery(regexp.QuoteMeta(orphanScanOrdersQuery)).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)).AddRow(int64(7)))
+			if tt.wantErr == nil {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `orders` WHERE `id` IN (?,?)")).
+					WithArgs(int64(3), int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+
+			_, err = s.Clean(context.Background(), dp)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
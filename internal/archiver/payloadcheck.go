@@ -65,16 +65,17 @@ func (p *PayloadValidator) Validate(ctx context.Context) error {
 	p.logger.Infof("Destination max_allowed_packet = %d bytes", maxPacket)
 
 	for _, table := range copyOrder {
+		batchSize := p.graph.BatchSizeFor(table, p.batchSize)
 		columns, err := p.tableColumns(ctx, table)
 		if err != nil {
 			return fmt.Errorf("table %q: %w", table, err)
 		}
 		// Exact check (valid even for empty tables).
-		if err := checkPlaceholderLimit(table, len(columns), p.batchSize); err != nil {
+		if err := checkPlaceholderLimit(table, len(columns), batchSize); err != nil {
 			return err
 		}
 
-		sampled, rowBytes, err := p.measureSample(ctx, table, columns)
+		sampled, rowBytes, err := p.measureSample(ctx, table, columns, batchSize)
 		if err != nil {
 			return fmt.Errorf("table %q: %w", table, err)
 		}
@@ -84,14 +85,14 @@ func (p *PayloadValidator) Validate(ctx context.Context) error {
 		}
 		// Project to a full batch_size chunk when the sample was smaller.
 		projected := rowBytes
-		if sampled < p.batchSize {
-			projected = rowBytes * p.batchSize / sampled
+		if sampled < batchSize {
+			projected = rowBytes * batchSize / sampled
 			p.logger.Infof("table %q: only %d rows available; projected full-chunk payload ≈ %d bytes", table, sampled, projected)
 		}
 		if int64(projected) >= maxPacket {
 			return fmt.Errorf(
 				"table %q: a batch_size of %d would build an INSERT of ≈%d bytes, exceeding destination max_allowed_packet=%d; lower batch_size",
-				table, p.batchSize, projected, maxPacket)
+				table, batchSize, projected, maxPacket)
 		}
 		if p.jobCfg.RootTable != table {
 			p.logger.Infof("table %q: packet check is APPROXIMATE (child sample uses arbitrary rows, not discovery-resolved rows)", table)
@@ -126,7 +127,7 @@ func (p *PayloadValidator) tableColumns(ctx context.Context, table string) ([]st
 // measureSample fetches up to batchSize rows, builds the real INSERT, executes
 // it inside a destination transaction, then rolls back. Returns (#rows sampled,
 // approximate INSERT byte size, error).
-func (p *PayloadValidator) measureSample(ctx context.Context, table string, columns []string) (int, int, error) {
+func (p *PayloadValidator) measureSample(ctx context.Context, table string, columns []string, batchSize int) (int, int, error) {
 	pkColumn := p.graph.GetPK(table)
 	var query string
 	if p.jobCfg.RootTable == table && strings.TrimSpace(p.jobCfg.Where) != "" {
@@ -139,10 +140,10 @@ func (p *PayloadValidator) measureSample(ctx context.Context, table string, colu
 		// a large production table looks like an indefinite hang.
 		query = fmt.Sprintf("SELECT * FROM %s WHERE (%s) ORDER BY %s ASC LIMIT %d",
 			sqlutil.QuoteIdentifier(table), p.jobCfg.Where,
-			sqlutil.QuoteIdentifier(pkColumn), p.batchSize)
+			sqlutil.QuoteIdentifier(pkColumn), batchSize)
 	} else {
 		query = fmt.Sprintf("SELECT * FROM %s LIMIT %d",
-			sqlutil.QuoteIdentifier(table), p.batchSize)
+			sqlutil.QuoteIdentifier(table), batchSize)
 	}
 
 	rows, err := p.source.QueryContext(ctx, query)
@@ -151,7 +152,7 @@ func (p *PayloadValidator) measureSample(ctx context.Context, table string, colu
 	}
 	defer func() { _ = rows.Close() }()
 
-	values := make([]interface{}, 0, len(columns)*p.batchSize)
+	values := make([]interface{}, 0, len(columns)*batchSize)
 	count := 0
 	for rows.Next() {
 		rowVals := make([]interface{}, len(columns))
@@ -0,0 +1,65 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+// ErrPhaseTimeout matches (with errors.Is) every PhaseTimeoutError.
+var ErrPhaseTimeout = errors.New("phase timeout exceeded")
+
+// PhaseTimeoutError is returned when one batch phase outlives its
+// processing.<phase>_timeout. It deliberately does not wrap
+// context.DeadlineExceeded: the phase failed, the job was not interrupted, so
+// the batch must not be treated as a clean cancellation.
+type PhaseTimeoutError struct {
+	Phase   string // PhaseDiscovery, PhaseCopy, PhaseVerify or PhaseDelete
+	Timeout time.Duration
+	Err     error // the phase's own error, kept for its message
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("%s phase exceeded its %s timeout: %v", e.Phase, e.Timeout, e.Err)
+}
+
+// Is reports whether target is ErrPhaseTimeout.
+func (e *PhaseTimeoutError) Is(target error) bool {
+	return target == ErrPhaseTimeout
+}
+
+// phaseTimeout returns the processing timeout configured for phase; 0 when
+// the phase inherits the job context.
+func phaseTimeout(cfg config.ProcessingConfig, phase string) time.Duration {
+	switch phase {
+	case PhaseDiscovery:
+		return cfg.DiscoveryTimeout
+	case PhaseCopy:
+		return cfg.CopyTimeout
+	case PhaseVerify:
+		return cfg.VerifyTimeout
+	case PhaseDelete:
+		return cfg.DeleteTimeout
+	default:
+		return 0
+	}
+}
+
+// withPhaseTimeout runs fn with a child of ctx that expires after timeout
+// (ctx itself when timeout is 0). An error from fn after that deadline, while
+// ctx is still live, is returned as a *PhaseTimeoutError.
+func withPhaseTimeout(ctx context.Context, phase string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := fn(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return &PhaseTimeoutError{Phase: phase, Timeout: timeout, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBatch_DiscoveryTimeoutFailsDiscovery(t *testing.T) {
+	hooks := &recordingHooks{}
+	p := newHookTestPhases(t, hooks)
+	p.o.processingCfg.DiscoveryTimeout = 20 * time.Millisecond
+
+	p.sourceMock.ExpectQuery("SELECT `id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)))
+
+	err := p.processBatch(1)
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrPhaseTimeout)
+	require.NotErrorIs(t, err, context.DeadlineExceeded, "a phase timeout is a failure, not a cancellation")
+	var timeoutErr *PhaseTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, PhaseDiscovery, timeoutErr.Phase)
+	require.Contains(t, err.Error(), "discovery failed: discovery phase exceeded its 20ms timeout")
+	require.Equal(t, []string{"before:discovery", "after:discovery"}, hooks.calls, "no later phase runs")
+	require.ErrorIs(t, hooks.afterErrs[PhaseDiscovery], ErrPhaseTimeout)
+	require.NoError(t, p.sourceMock.ExpectationsWereMet())
+}
+
+func TestProcessBatch_DiscoveryTimeoutLeavesOtherPhasesAlone(t *testing.T) {
+	p := newHookTestPhases(t, nil)
+	p.o.processingCfg.DiscoveryTimeout = 20 * time.Millisecond
+
+	// The delete outlasts the discovery timeout but has no timeout of its own.
+	expectGatedRootCopy(p.sourceMock, p.destMock, 1, 10, 1)
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("DELETE FROM `orders`").WithArgs(int64(10)).
+		WillDelayFor(60 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("DELETE FROM `customers`").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectBegin()
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectCommit()
+
+	require.NoError(t, p.processBatch(1))
+	p.expectationsMet(t)
+}
+
+func TestWithPhaseTimeout_ParentCancellationIsNotATimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := withPhaseTimeout(ctx, PhaseCopy, time.Hour, func(ctx context.Context) error { return ctx.Err() })
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, ErrPhaseTimeout)
+}
@@ -0,0 +1,205 @@
+package archiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// planInList is the symbolic IN (...) list shown in statement templates; at
+// run time it holds one placeholder per PK in the chunk.
+const planInList = "?, ..."
+
+// planOtherColumns stands in for the columns read at run time in INSERT
+// templates.
+const planOtherColumns = "<other columns>"
+
+// PlanReport is the structured plan for one job: every table with its copy
+// and delete position, optional row estimate, and the statement templates
+// the copy and delete phases run for it.
+type PlanReport struct {
+	Job              string      `json:"job"`
+	RootTable        string      `json:"root_table"`
+	Where            string      `json:"where,omitempty"`
+	CopyMode         string      `json:"copy_mode"`
+	BatchSize        int         `json:"batch_size"`
+	BatchDeleteSize  int         `json:"batch_delete_size"`
+	Estimated        bool        `json:"estimated"`
+	EstimatedBatches int64       `json:"estimated_batches,omitempty"`
+	Tables           []TablePlan `json:"tables"`
+}
+
+// TablePlan is one table's entry in a PlanReport. Positions are 1-based.
+// EstimatedRows is nil when the plan was built without estimates.
+type TablePlan struct {
+	Table          string `json:"table"`
+	CopyPosition   int    `json:"copy_position"`
+	DeletePosition int    `json:"delete_position"`
+	EstimatedRows  *int64 `json:"estimated_rows,omitempty"`
+	CopySelect     string `json:"copy_select"`
+	CopyInsert     string `json:"copy_insert"`
+	Delete         string `json:"delete"`
+}
+
+// Planner builds a PlanReport from a job's dependency graph and effective
+// processing and verification settings. It does not touch a database; row
+// estimates come from an Estimator run passed to Plan.
+type Planner struct {
+	jobName      string
+	jobCfg       *config.JobConfig
+	graph        *graph.Graph
+	processing   config.ProcessingConfig
+	verification config.VerificationConfig
+}
+
+// NewPlanner creates a planner for jobName.
+func NewPlanner(jobName string, jobCfg *config.JobConfig, g *graph.Graph, processing config.ProcessingConfig, verification config.VerificationConfig) (*Planner, error) {
+	if jobCfg == nil {
+		return nil, fmt.Errorf("job config cannot be nil")
+	}
+	if g == nil {
+		return nil, fmt.Errorf("graph cannot be nil")
+	}
+	return &Planner{
+		jobName:      jobName,
+		jobCfg:       jobCfg,
+		graph:        g,
+		processing:   processing,
+		verification: verification,
+	}, nil
+}
+
+// Plan builds the report with tables in copy order. estimate may be nil, in
+// which case no row counts are reported.
+func (p *Planner) Plan(estimate *EstimateResult) (*PlanReport, error) {
+	copyOrder, err := p.graph.CopyOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute copy order: %w", err)
+	}
+	deleteOrder, err := p.graph.DeleteOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute delete order: %w", err)
+	}
+	deletePos := make(map[string]int, len(deleteOrder))
+	for i, table := range deleteOrder {
+		deletePos[table] = i + 1
+	}
+
+	// Without a destination connection, secondary unique indexes are unknown;
+	// the archive run may still upgrade INSERT IGNORE to a strict INSERT.
+	weakest := p.jobCfg.WeakestVerification(p.verification)
+	strict, upsert, err := resolveCopyMode(p.processing.CopyMode, weakest.EffectiveMethod(), weakest.SkipVerification, nil)
+	if err != nil {
+		return nil, err
+	}
+	copyMode := "insert-ignore"
+	switch {
+	case upsert:
+		copyMode = "upsert"
+	case strict:
+		copyMode = "insert"
+	}
+
+	report := &PlanReport{
+		Job:             p.jobName,
+		RootTable:       p.jobCfg.RootTable,
+		Where:           p.jobCfg.Where,
+		CopyMode:        copyMode,
+		BatchSize:       p.processing.BatchSize,
+		BatchDeleteSize: p.processing.BatchDeleteSize,
+		Tables:          make([]TablePlan, 0, len(copyOrder)),
+	}
+	if estimate != nil {
+		report.Estimated = true
+		report.EstimatedBatches = estimate.EstimatedBatches
+	}
+
+	// The copy phase's own INSERT builders, for one row of each table.
+	inserts := &CopyPhase{graph: p.graph, strictInsert: strict, upsert: upsert}
+	for i, table := range copyOrder {
+		pk := p.graph.GetPK(table)
+		tp := TablePlan{
+			Table:          table,
+			CopyPosition:   i + 1,
+			DeletePosition: deletePos[table],
+			CopySelect:     selectByPKQuery(p.selectList(table), table, pk, planInList),
+			CopyInsert:     inserts.buildInsertQuery(table, p.destinationTable(table), p.insertColumns(table), 1),
+			Delete:         deleteByPKQuery(table, pk, planInList),
+		}
+		if estimate != nil {
+			rows := estimate.ChildCounts[table]
+			if table == estimate.RootTable {
+				rows = estimate.RootCount
+			}
+			tp.EstimatedRows = &rows
+		}
+		report.Tables = append(report.Tables, tp)
+	}
+	return report, nil
+}
+
+// selectList mirrors CopyPhase.selectList without a database: an exclusion
+// filter needs the live column list, so it is shown symbolically.
+func (p *Planner) selectList(table string) string {
+	filter := p.graph.GetColumnFilter(table)
+	switch {
+	case filter == nil:
+		return "*"
+	case filter.NeedsColumnList():
+		return "<all columns except " + sqlutil.SelectList(filter.Exclude) + ">"
+	default:
+		return sqlutil.SelectList(filter.Include)
+	}
+}
+
+// destinationTable returns the table the copy phase inserts table's rows
+// into: its destination_table template, shown unexpanded, or table itself.
+func (p *Planner) destinationTable(table string) string {
+	if p.graph.HasDestinationTemplate(table) {
+		return p.graph.GetNode(table).DestinationTable
+	}
+	return table
+}
+
+// insertColumns returns the columns shown in table's INSERT template: its
+// include list, or its PK followed by planOtherColumns when the column list
+// is only known at run time.
+func (p *Planner) insertColumns(table string) []string {
+	if filter := p.graph.GetColumnFilter(table); filter != nil && !filter.NeedsColumnList() {
+		return filter.Include
+	}
+	return []string{p.graph.GetPK(table), planOtherColumns}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *PlanReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes the report as a per-table listing.
+func (r *PlanReport) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Job: %s (root: %s, copy mode: %s)\n", r.Job, r.RootTable, r.CopyMode)
+	if r.Estimated {
+		fmt.Fprintf(&sb, "Estimated batches: %d (batch size %d)\n", r.EstimatedBatches, r.BatchSize)
+	}
+	for _, t := range r.Tables {
+		rows := "not estimated"
+		if t.EstimatedRows != nil {
+			rows = fmt.Sprintf("~%d rows", *t.EstimatedRows)
+		}
+		fmt.Fprintf(&sb, "\n  %s  (copy #%d, delete #%d, %s)\n", t.Table, t.CopyPosition, t.DeletePosition, rows)
+		fmt.Fprintf(&sb, "    copy:   %s\n", t.CopySelect)
+		fmt.Fprintf(&sb, "            %s\n", t.CopyInsert)
+		fmt.Fprintf(&sb, "    delete: %s\n", t.Delete)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
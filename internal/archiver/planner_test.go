@@ -0,0 +1,183 @@
+package archiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createPlannerJob() *config.JobConfig {
+	return &config.JobConfig{
+		RootTable:  "customers",
+		PrimaryKey: "id",
+		Where:      "created_at < '2020-01-01'",
+		Columns:    &config.ColumnSelection{Include: []string{"id", "name"}},
+		Relations: []config.Relation{
+			{
+				Table:          "orders",
+				PrimaryKey:     "order_id",
+				ForeignKey:     "customer_id",
+				DependencyType: "1-N",
+				Relations: []config.Relation{
+					{
+						Table:          "order_items",
+						PrimaryKey:     "id",
+						ForeignKey:     "order_id",
+						DependencyType: "1-N",
+					},
+				},
+			},
+			{
+				Table:          "notes",
+				PrimaryKey:     "id",
+				ForeignKey:     "customer_id",
+				DependencyType: "1-N",
+				Columns:        &config.ColumnSelection{Exclude: []string{"body"}},
+			},
+		},
+	}
+}
+
+func newTestPlanner(t *testing.T, processing config.ProcessingConfig) *Planner {
+	t.Helper()
+	jobCfg := createPlannerJob()
+	g, err := graph.BuildFromJob(jobCfg)
+	require.NoError(t, err)
+	p, err := NewPlanner("archive_customers", jobCfg, g, processing, config.VerificationConfig{Method: "sha256"})
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewPlanner_Validation(t *testing.T) {
+	g := createSimpleGraph()
+	_, err := NewPlanner("job", nil, g, config.ProcessingConfig{}, config.VerificationConfig{})
+	assert.Error(t, err)
+	_, err = NewPlanner("job", &config.JobConfig{RootTable: "customers"}, nil, config.ProcessingConfig{}, config.VerificationConfig{})
+	assert.Error(t, err)
+}
+
+func TestPlanner_Positions(t *testing.T) {
+	p := newTestPlanner(t, config.ProcessingConfig{BatchSize: 500, BatchDeleteSize: 250})
+	report, err := p.Plan(nil)
+	require.NoError(t, err)
+
+	copyOrder, err := p.graph.CopyOrder()
+	require.NoError(t, err)
+	deleteOrder, err := p.graph.DeleteOrder()
+	require.NoError(t, err)
+
+	require.Len(t, report.Tables, 4)
+	seen := make(map[string]bool)
+	for i, tp := range report.Tables {
+		seen[tp.Table] = true
+		assert.Equal(t, i+1, tp.CopyPosition)
+		assert.Equal(t, copyOrder[tp.CopyPosition-1], tp.Table)
+		assert.Equal(t, deleteOrder[tp.DeletePosition-1], tp.Table)
+		assert.Nil(t, tp.EstimatedRows)
+	}
+	for _, table := range []string{"customers", "orders", "order_items", "notes"} {
+		assert.True(t, seen[table], "missing table %s", table)
+	}
+
+	byTable := make(map[string]TablePlan)
+	for _, tp := range report.Tables {
+		byTable[tp.Table] = tp
+	}
+	assert.Equal(t, 1, byTable["customers"].CopyPosition)
+	assert.Equal(t, 4, byTable["customers"].DeletePosition)
+	assert.Less(t, byTable["orders"].CopyPosition, byTable["order_items"].CopyPosition)
+	assert.Greater(t, byTable["orders"].DeletePosition, byTable["order_items"].DeletePosition)
+
+	assert.Equal(t, "archive_customers", report.Job)
+	assert.Equal(t, "customers", report.RootTable)
+	assert.Equal(t, 500, report.BatchSize)
+	assert.Equal(t, 250, report.BatchDeleteSize)
+	assert.False(t, report.Estimated)
+}
+
+func TestPlanner_StatementShapes(t *testing.T) {
+	p := newTestPlanner(t, config.ProcessingConfig{})
+	report, err := p.Plan(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "insert-ignore", report.CopyMode)
+
+	byTable := make(map[string]TablePlan)
+	for _, tp := range report.Tables {
+		byTable[tp.Table] = tp
+	}
+
+	assert.Equal(t, "SELECT `id`, `name` FROM `customers` WHERE `id` IN (?, ...)", byTable["customers"].CopySelect)
+	assert.Equal(t, "SELECT * FROM `orders` WHERE `order_id` IN (?, ...)", byTable["orders"].CopySelect)
+	assert.Equal(t, "SELECT <all columns except `body`> FROM `notes` WHERE `id` IN (?, ...)", byTable["notes"].CopySelect)
+	assert.Equal(t, "INSERT IGNORE INTO `customers` (`id`, `name`) VALUES (?, ?)", byTable["customers"].CopyInsert)
+	assert.Equal(t, "INSERT IGNORE INTO `orders` (`order_id`, `<other columns>`) VALUES (?, ?)", byTable["orders"].CopyInsert)
+	assert.Equal(t, "DELETE FROM `orders` WHERE `order_id` IN (?, ...)", byTable["orders"].Delete)
+	assert.Equal(t, "DELETE FROM `order_items` WHERE `id` IN (?, ...)", byTable["order_items"].Delete)
+}
+
+func TestPlanner_CopyModes(t *testing.T) {
+	// Count verification upgrades the default INSERT IGNORE to a strict INSERT.
+	jobCfg := createPlannerJob()
+	g, err := graph.BuildFromJob(jobCfg)
+	require.NoError(t, err)
+	counted, err := NewPlanner("job", jobCfg, g, config.ProcessingConfig{}, config.VerificationConfig{Method: "count"})
+	require.NoError(t, err)
+	report, err := counted.Plan(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "insert", report.CopyMode)
+
+	strict := newTestPlanner(t, config.ProcessingConfig{CopyMode: "insert"})
+	report, err = strict.Plan(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "insert", report.CopyMode)
+	assert.Equal(t, "INSERT INTO `customers` (`id`, `name`) VALUES (?, ?)", report.Tables[0].CopyInsert)
+
+	upsert := newTestPlanner(t, config.ProcessingConfig{CopyMode: "upsert"})
+	report, err = upsert.Plan(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "upsert", report.CopyMode)
+	assert.Equal(t, "INSERT INTO `customers` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", report.Tables[0].CopyInsert)
+}
+
+func TestPlanner_WithEstimates(t *testing.T) {
+	p := newTestPlanner(t, config.ProcessingConfig{BatchSize: 100})
+	report, err := p.Plan(&EstimateResult{
+		RootTable:        "customers",
+		RootCount:        1000,
+		ChildCounts:      map[string]int64{"orders": 5000, "order_items": 20000, "notes": 300},
+		EstimatedBatches: 10,
+	})
+	require.NoError(t, err)
+	assert.True(t, report.Estimated)
+	assert.Equal(t, int64(10), report.EstimatedBatches)
+
+	want := map[string]int64{"customers": 1000, "orders": 5000, "order_items": 20000, "notes": 300}
+	for _, tp := range report.Tables {
+		require.NotNil(t, tp.EstimatedRows, tp.Table)
+		assert.Equal(t, want[tp.Table], *tp.EstimatedRows, tp.Table)
+	}
+}
+
+func TestPlanReport_Output(t *testing.T) {
+	p := newTestPlanner(t, config.ProcessingConfig{})
+	report, err := p.Plan(nil)
+	require.NoError(t, err)
+
+	var jsonBuf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&jsonBuf))
+	var decoded PlanReport
+	require.NoError(t, json.Unmarshal(jsonBuf.Bytes(), &decoded))
+	assert.Equal(t, report.Tables, decoded.Tables)
+	assert.NotContains(t, jsonBuf.String(), "estimated_rows")
+
+	var textBuf bytes.Buffer
+	require.NoError(t, report.WriteText(&textBuf))
+	out := textBuf.String()
+	assert.Contains(t, out, "customers  (copy #1, delete #4, not estimated)")
+	assert.Contains(t, out, "DELETE FROM `order_items` WHERE `id` IN (?, ...)")
+}
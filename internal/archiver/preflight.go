@@ -7,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/graph"
@@ -30,6 +32,21 @@ func (e *PreflightError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Check, e.Message)
 }
 
+// PreflightErrors collects every failed check when the checker runs with
+// SetAggregateErrors(true), so operators can fix all problems in one pass.
+type PreflightErrors []*PreflightError
+
+func (e PreflightErrors) Error() string {
+	if len(e) == 0 {
+		return ""
+	}
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d preflight checks failed:\n  - %s", len(e), strings.Join(msgs, "\n  - "))
+}
+
 // StorageEngineResult holds storage engine check results.
 //
 // GA-P4-F3-T1: Storage engine check
@@ -85,7 +102,27 @@ type PreflightChecker struct {
 	logger            *logger.Logger
 	fkCache           []ForeignKeyResult
 	fkCacheLoaded     bool
-	verification      config.VerificationConfig
+	// columnCache holds getTableColumns results during RunWithProfile, so the
+	// schema and charset checks read each table's columns once per side.
+	columnCache  map[columnCacheKey][]ColumnDefinition
+	verification config.VerificationConfig
+	// allowExtraDestColumns tolerates trailing nullable destination-only columns.
+	allowExtraDestColumns bool
+	// diskFreeBytes/diskSpaceMargin drive DISK_SPACE_CHECK (0 free = disabled).
+	diskFreeBytes   int64
+	diskSpaceMargin float64
+	// rejectGeneratedColumns turns the generated-column warning into an error.
+	rejectGeneratedColumns bool
+	// multiPath is safety.multi_path: how MULTI_PATH_CHECK reports tables
+	// with several parents ("" or "dedupe", "warn", "error").
+	multiPath string
+	// aggregateErrors runs every check and returns PreflightErrors instead of
+	// stopping at the first failure.
+	aggregateErrors bool
+	// runDate expands run-dated destination_table templates, as the copy
+	// will; destTables caches the destination schema's table names.
+	runDate    time.Time
+	destTables []string
 }
 
 // NewPreflightChecker creates a new preflight checker.
@@ -110,6 +147,7 @@ func NewPreflightChecker(db *sql.DB, sourceDBName string, g *graph.Graph, log *l
 		destinationDBName: "",
 		graph:             g,
 		logger:            log,
+		runDate:           time.Now(),
 	}, nil
 }
 
@@ -137,108 +175,124 @@ func (p *PreflightChecker) ConfigureDestination(db *sql.DB, destinationDBName, j
 // GA-P4-F3-T7: Validate command implementation
 func (p *PreflightChecker) RunWithProfile(ctx context.Context, profile PreflightProfile, forceTriggers bool, enforceFKVisibility bool) error {
 	p.logger.Info("Running preflight checks...")
+	p.columnCache = make(map[columnCacheKey][]ColumnDefinition)
+	p.destTables = nil
+	defer func() { p.columnCache = nil }()
 
 	// Get all tables from graph
 	tables := p.graph.AllNodes()
 
-	// GA-P4-F3-T2: Table existence check
+	// GA-P4-F3-T2: Table existence check. Every later check queries these
+	// tables, so a failure here stops the run even when aggregating errors.
 	if err := p.ValidateTablesExist(ctx, tables); err != nil {
 		return err
 	}
 
+	var steps []func() error
+
 	// Validate configured PK columns exist and are explicitly defined.
-	if err := p.ValidatePrimaryKeyColumns(ctx, tables); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidatePrimaryKeyColumns(ctx, tables) })
 
 	// Reject composite primary keys: GoArchive identifies and DELETES rows by a
 	// single PK column, so a multi-column PK would over-match (review P1-1).
-	if err := p.ValidateSingleColumnPrimaryKey(ctx, tables); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidateSingleColumnPrimaryKey(ctx, tables) })
 
 	rootTable := p.graph.Root
-	if err := p.ValidateRootPKNumeric(ctx, rootTable, p.graph.GetPK(rootTable)); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidateRootPKNumeric(ctx, rootTable, p.graph.GetPK(rootTable)) })
 
 	// GA-P4-F3-T1: Storage engine check
-	if err := p.ValidateStorageEngine(ctx, tables); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidateStorageEngine(ctx, tables) })
 
 	// INVISIBLE_COLUMN_CHECK: reject participating INVISIBLE columns. Rows are
 	// copied with SELECT *, which omits invisible columns, so their values would
 	// be silently dropped from the copy and the verification hash and then
 	// deleted from the source (issue #23). Runs for every profile.
-	if err := p.ValidateNoInvisibleColumns(ctx, tables); err != nil {
-		return err
+	steps = append(steps, func() error { return p.ValidateNoInvisibleColumns(ctx, tables) })
+
+	// GENERATED_COLUMN_CHECK: warn about generated/AUTO_INCREMENT columns
+	// (fatal for generated columns under safety.reject_generated_columns).
+	steps = append(steps, func() error { return p.ValidateGeneratedColumns(ctx, tables) })
+
+	// UNSUPPORTED_TYPE_CHECK: copied columns whose values the copy path
+	// cannot transfer faithfully (spatial types). Purge copies nothing.
+	if profile != PreflightProfileSourceOnly {
+		steps = append(steps, func() error { return p.ValidateSupportedColumnTypes(ctx, tables) })
 	}
 
+	// MULTI_PATH_CHECK: tables reachable through several parents are
+	// deduplicated; safety.multi_path can make them a warning or a failure.
+	steps = append(steps, p.ValidateMultiPath)
+
 	// Tracking-schema privileges are needed by every command that writes
 	// archiver_job / per-job logs (archive, purge, copy-only), independent of
 	// the data-table destination checks below.
 	if p.destinationDB != nil && p.jobSchemaName != "" {
-		if err := p.ValidateJobSchemaPermissions(ctx); err != nil {
-			return err
-		}
+		steps = append(steps, func() error { return p.ValidateJobSchemaPermissions(ctx) })
 	}
 
 	// Destination checks ensure copy target is safe before archive execution.
 	if profile != PreflightProfileSourceOnly && p.destinationDB != nil && p.destinationDBName != "" {
-		if err := p.ValidateDestinationTablesExist(ctx, tables); err != nil {
-			return err
-		}
-		if err := p.ValidateDestinationSchemaCompatibility(ctx, tables); err != nil {
-			return err
-		}
-		if err := p.ValidateDestinationWritePermissions(ctx, tables); err != nil {
-			return err
-		}
-		if err := p.ValidateDestinationInsertTriggers(ctx, tables); err != nil {
-			return err
-		}
+		steps = append(steps,
+			func() error { return p.ValidateDestinationTablesExist(ctx, tables) },
+			func() error { return p.ValidateDestinationSchemaCompatibility(ctx, tables) },
+			func() error { return p.ValidateCharsetCompatibility(ctx, tables) },
+			func() error { return p.ValidateDestinationWritePermissions(ctx, tables) },
+			func() error { return p.ValidateDestinationInsertTriggers(ctx, tables) },
+			func() error { return p.CheckDestinationFreeSpace(ctx) },
+		)
 	}
 
 	// GA-P4-F3-T3: FK index check
-	if err := p.ValidateForeignKeyIndexes(ctx); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidateForeignKeyIndexes(ctx) })
+
+	// INDEX_HINT_CHECK: every relation use_index must name an existing index,
+	// otherwise MySQL rejects the FORCE INDEX discovery query at runtime.
+	steps = append(steps, func() error { return p.ValidateIndexHints(ctx) })
 
 	// FK_COVERAGE_VISIBILITY_CHECK: coverage is only trustworthy if we can see
 	// constraints in every schema. Fail closed before relying on it — except for
 	// copy-only, which never deletes from source (no external cascade can fire).
 	if enforceFKVisibility {
-		if err := p.ValidateForeignKeyMetadataVisibility(ctx); err != nil {
-			return err
-		}
+		steps = append(steps, func() error { return p.ValidateForeignKeyMetadataVisibility(ctx) })
 	}
 
 	// FK_COVERAGE_CHECK: Validate all FK constraints are covered by relations
 	// This MUST be checked before triggers - missing relations are a bigger problem
-	if err := p.ValidateForeignKeyCoverage(ctx); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidateForeignKeyCoverage(ctx) })
 
 	// INTERNAL_FK_COVERAGE: Validate all internal FK relationships match graph edges
-	if err := p.ValidateInternalFKCoverage(ctx); err != nil {
-		return err
-	}
+	steps = append(steps, func() error { return p.ValidateInternalFKCoverage(ctx) })
 
 	if profile == PreflightProfileFull || profile == PreflightProfileSourceOnly {
-		if err := p.ValidateSourceDeletePermissions(ctx, tables); err != nil {
-			return err
-		}
-
-		// GA-P4-F3-T4 & T5: DELETE trigger detection (with force flag)
-		if err := p.ValidateTriggers(ctx, tables, forceTriggers); err != nil {
-			return err
+		steps = append(steps,
+			func() error { return p.ValidateSourceDeletePermissions(ctx, tables) },
+			// GA-P4-F3-T4 & T5: DELETE trigger detection (with force flag)
+			func() error { return p.ValidateTriggers(ctx, tables, forceTriggers) },
+			// GA-P4-F3-T6: CASCADE rule warning
+			func() error { return p.WarnCascadeRules(ctx) },
+		)
+	}
+
+	// Fail fast by default; with SetAggregateErrors(true) keep going past
+	// check failures (PreflightError) and report them together. Query or
+	// connection errors still stop the run, since later checks would hit them too.
+	var failures PreflightErrors
+	for _, step := range steps {
+		err := step()
+		if err == nil {
+			continue
 		}
-
-		// GA-P4-F3-T6: CASCADE rule warning
-		if err := p.WarnCascadeRules(ctx); err != nil {
+		var pfErr *PreflightError
+		if !p.aggregateErrors || !errors.As(err, &pfErr) {
+			if len(failures) > 0 {
+				return errors.Join(failures, err)
+			}
 			return err
 		}
+		failures = append(failures, pfErr)
+	}
+	if len(failures) > 0 {
+		return failures
 	}
 
 	p.logger.Info("All preflight checks PASSED")
@@ -563,6 +617,232 @@ func (p *PreflightChecker) ValidateNoInvisibleColumns(ctx context.Context, table
 	return nil
 }
 
+// ValidateGeneratedColumns reports GENERATED (virtual/stored) and AUTO_INCREMENT
+// columns in participating tables. Neither breaks the copy on its own: SELECT *
+// materialises generated values, which are inserted into plain destination
+// columns (a generated destination column is rejected by
+// DEST_SCHEMA_COMPATIBILITY_CHECK), and explicit PK values are inserted so the
+// destination AUTO_INCREMENT never renumbers rows — except a stored value of 0,
+// which MySQL renumbers unless sql_mode has NO_AUTO_VALUE_ON_ZERO. Both are
+// logged as warnings; with SetRejectGeneratedColumns(true) generated columns
+// fail the check (GENERATED_COLUMN_CHECK) for operators who want the archive to
+// hold only source-of-truth data.
+func (p *PreflightChecker) ValidateGeneratedColumns(ctx context.Context, tables []string) error {
+	p.logger.Debug("Checking for generated and AUTO_INCREMENT columns...")
+	if len(tables) == 0 {
+		return nil
+	}
+
+	const query = `
+		SELECT TABLE_NAME, COLUMN_NAME, EXTRA
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ?
+		AND TABLE_NAME IN (?)
+		AND (EXTRA LIKE '%GENERATED%' OR EXTRA LIKE '%auto_increment%')`
+
+	placeholders := make([]string, len(tables))
+	args := make([]interface{}, len(tables)+1)
+	args[0] = p.sourceDBName
+	for i, table := range tables {
+		placeholders[i] = "?"
+		args[i+1] = table
+	}
+
+	fullQuery := strings.Replace(query, "(?)", "("+strings.Join(placeholders, ",")+")", 1)
+
+	rows, err := p.db.QueryContext(ctx, fullQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query generated columns: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			p.logger.Warnf("Failed to close rows: %v", err)
+		}
+	}()
+
+	var generated, autoIncrement []string
+	for rows.Next() {
+		var table, column, extra string
+		if err := rows.Scan(&table, &column, &extra); err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s.%s", table, column)
+		if isGeneratedColumn(extra) {
+			generated = append(generated, name)
+		} else if strings.Contains(strings.ToLower(extra), "auto_increment") {
+			autoIncrement = append(autoIncrement, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(autoIncrement) > 0 {
+		p.logger.Warnf("AUTO_INCREMENT columns detected (%d): %v. Explicit values are copied; a stored value of 0 is renumbered by an AUTO_INCREMENT destination unless sql_mode includes NO_AUTO_VALUE_ON_ZERO",
+			len(autoIncrement), autoIncrement)
+	}
+
+	if len(generated) > 0 {
+		if p.rejectGeneratedColumns {
+			return &PreflightError{
+				Check: "GENERATED_COLUMN_CHECK",
+				Message: "Generated columns are rejected (safety.reject_generated_columns). Their computed values " +
+					"would be copied as plain data; disable the option or remove these tables from the archive",
+				Tables: generated,
+			}
+		}
+		p.logger.Warnf("Generated columns detected (%d): %v. Computed values are copied into plain destination columns",
+			len(generated), generated)
+	}
+
+	p.logger.Debug("Generated column check PASSED")
+	return nil
+}
+
+// unsupportedColumnTypes are the DATA_TYPEs the copy path cannot transfer
+// faithfully: spatial values are read in MySQL's internal SRID+WKB form,
+// which a multi-row INSERT or LOAD DATA binds as a plain binary string
+// rather than through ST_GeomFromWKB, so the archived geometry is rejected
+// or corrupted. JSON is not listed: it is read as its normalized text and
+// re-parsed by the destination, which round-trips every value.
+var unsupportedColumnTypes = map[string]bool{
+	"geometry":           true,
+	"point":              true,
+	"linestring":         true,
+	"polygon":            true,
+	"multipoint":         true,
+	"multilinestring":    true,
+	"multipolygon":       true,
+	"geometrycollection": true,
+	"geomcollection":     true,
+}
+
+// ValidateSupportedColumnTypes fails (UNSUPPORTED_TYPE_CHECK) when a copied
+// column of a participating table has a type in unsupportedColumnTypes,
+// naming each column as table.column (type). Columns left out of the copy by
+// a columns.include/exclude selection are not checked.
+func (p *PreflightChecker) ValidateSupportedColumnTypes(ctx context.Context, tables []string) error {
+	p.logger.Debug("Checking for unsupported column types...")
+	if len(tables) == 0 {
+		return nil
+	}
+
+	const query = `
+		SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ?
+		AND TABLE_NAME IN (?)
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`
+
+	placeholders := make([]string, len(tables))
+	args := make([]interface{}, len(tables)+1)
+	args[0] = p.sourceDBName
+	for i, table := range tables {
+		placeholders[i] = "?"
+		args[i+1] = table
+	}
+
+	fullQuery := strings.Replace(query, "(?)", "("+strings.Join(placeholders, ",")+")", 1)
+
+	rows, err := p.db.QueryContext(ctx, fullQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query column types: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			p.logger.Warnf("Failed to close rows: %v", err)
+		}
+	}()
+
+	var unsupported []string
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return err
+		}
+		if !unsupportedColumnTypes[strings.ToLower(dataType)] {
+			continue
+		}
+		if len(p.graph.GetColumnFilter(table).Apply([]string{column})) == 0 {
+			continue
+		}
+		unsupported = append(unsupported, fmt.Sprintf("%s.%s (%s)", table, column, strings.ToUpper(dataType)))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(unsupported) > 0 {
+		return &PreflightError{
+			Check: "UNSUPPORTED_TYPE_CHECK",
+			Message: "Spatial columns cannot be copied faithfully: their values are read in MySQL's internal " +
+				"geometry format and inserted as plain binary strings, so the archived geometry would be " +
+				"rejected or corrupted. Leave these columns out with columns.exclude or remove these tables " +
+				"from the archive",
+			Tables: unsupported,
+		}
+	}
+
+	p.logger.Debug("Unsupported column type check PASSED")
+	return nil
+}
+
+// ValidateMultiPath reports tables reachable from the root through more than
+// one parent. Discovery collects such a table's rows from every parent and
+// deduplicates them, so each PK is copied and deleted once; the check only
+// decides how visible that is. With SetMultiPath("warn") the tables are
+// logged as a warning, with "error" the check fails (MULTI_PATH_CHECK).
+func (p *PreflightChecker) ValidateMultiPath() error {
+	multi := p.graph.MultiPathTables()
+	if len(multi) == 0 {
+		return nil
+	}
+	tables := make([]string, 0, len(multi))
+	for table := range multi {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	paths := make([]string, len(tables))
+	for i, table := range tables {
+		paths[i] = fmt.Sprintf("%s <- %s", table, strings.Join(multi[table], ", "))
+	}
+
+	switch p.multiPath {
+	case "error":
+		return &PreflightError{
+			Check: "MULTI_PATH_CHECK",
+			Message: "Tables are reachable through more than one parent (safety.multi_path: error): " +
+				strings.Join(paths, "; "),
+			Tables: tables,
+		}
+	case "warn":
+		p.logger.Warnf("Tables reachable through more than one parent (%d): %s. Their rows are deduplicated across paths",
+			len(tables), strings.Join(paths, "; "))
+	default:
+		p.logger.Debugf("Deduplicating rows of tables reachable through more than one parent: %s", strings.Join(paths, "; "))
+	}
+	return nil
+}
+
+// SetMultiPath sets how ValidateMultiPath reports tables with several
+// parents: "warn" logs a warning, "error" fails the check, anything else
+// ("dedupe", the default) only logs at debug level.
+func (p *PreflightChecker) SetMultiPath(mode string) {
+	p.multiPath = mode
+}
+
+// SetAggregateErrors switches RunWithProfile from fail-fast (the default) to
+// running every check and returning all failures as PreflightErrors.
+func (p *PreflightChecker) SetAggregateErrors(aggregate bool) {
+	p.aggregateErrors = aggregate
+}
+
+// SetRejectGeneratedColumns makes ValidateGeneratedColumns fail instead of
+// warn when a participating table has a generated column.
+func (p *PreflightChecker) SetRejectGeneratedColumns(reject bool) {
+	p.rejectGeneratedColumns = reject
+}
+
 // ValidateForeignKeyIndexes checks that all foreign key columns have indexes.
 //
 // GA-P4-F3-T3: FK index check
@@ -607,6 +887,41 @@ func (p *PreflightChecker) ValidateForeignKeyIndexes(ctx context.Context) error
 	return nil
 }
 
+// ValidateIndexHints checks that each table's configured index hint (relation
+// use_index) names an index that exists on that table in the source schema.
+func (p *PreflightChecker) ValidateIndexHints(ctx context.Context) error {
+	const query = `
+		SELECT COUNT(*)
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ?
+		AND TABLE_NAME = ?
+		AND INDEX_NAME = ?`
+
+	var missing []string
+	for _, table := range p.graph.AllNodes() {
+		node := p.graph.GetNode(table)
+		if node == nil || node.IndexHint == "" {
+			continue
+		}
+		var count int
+		if err := p.db.QueryRowContext(ctx, query, p.sourceDBName, table, node.IndexHint).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check index %s on %s: %w", node.IndexHint, table, err)
+		}
+		if count == 0 {
+			missing = append(missing, fmt.Sprintf("%s.%s", table, node.IndexHint))
+		}
+	}
+
+	if len(missing) > 0 {
+		return &PreflightError{
+			Check:   "INDEX_HINT_CHECK",
+			Message: "use_index names an index that does not exist on the table",
+			Tables:  missing,
+		}
+	}
+	return nil
+}
+
 // inGraph reports whether (schema, table) is a node in the archive graph. Graph
 // nodes always live in the source schema, so a same-named table in another
 // schema is NOT in the graph.
@@ -1037,44 +1352,150 @@ type ColumnDefinition struct {
 	Collation       string // empty for non-string columns
 }
 
-// ValidateDestinationTablesExist checks that all graph tables exist in destination DB.
-func (p *PreflightChecker) ValidateDestinationTablesExist(ctx context.Context, tables []string) error {
-	if p.destinationDB == nil {
-		return fmt.Errorf("destination database not configured; call ConfigureDestination first")
+// destinationTarget is one destination table that rows of a source table
+// are copied into. unmatched marks a row-dated template no destination table
+// matches yet; only the existence check reports it.
+type destinationTarget struct {
+	source, dest string
+	unmatched    bool
+}
+
+// label names the target in check failures: the table, or the table and its
+// computed destination.
+func (t destinationTarget) label() string {
+	if t.source == t.dest {
+		return t.source
 	}
-	p.logger.Debug("Checking destination table existence...")
+	return fmt.Sprintf("%s->%s", t.source, t.dest)
+}
+
+// destinationTargets resolves the destination tables the destination checks
+// examine for tables (see resolveDestinationTargets).
+func (p *PreflightChecker) destinationTargets(ctx context.Context, tables []string) ([]destinationTarget, error) {
+	return resolveDestinationTargets(p.graph, tables, p.runDate, func() ([]string, error) {
+		return p.destinationTableNames(ctx)
+	})
+}
+
+// resolveDestinationTargets resolves the destination tables of tables. A
+// table without a destination_table template is copied into a table of the
+// same name and a run-dated template is expanded with runDate, like the copy.
+// A template dated by a row column (destination_date_column) can target any
+// month, so every destination table listed by existing that matches its
+// pattern is a target; when none exists the template itself is the target,
+// marked unmatched, and DEST_TABLE_EXISTENCE_CHECK reports it.
+func resolveDestinationTargets(g *graph.Graph, tables []string, runDate time.Time, existing func() ([]string, error)) ([]destinationTarget, error) {
+	targets := make([]destinationTarget, 0, len(tables))
+	for _, table := range tables {
+		if g.DestinationDateColumn(table) == "" {
+			targets = append(targets, destinationTarget{source: table, dest: g.DestinationTable(table, runDate)})
+			continue
+		}
+		tmpl := g.GetNode(table).DestinationTable
+		pattern := regexp.MustCompile("^" + strings.NewReplacer(
+			`\{year\}`, `[0-9]{4}`,
+			`\{month\}`, `[0-9]{2}`,
+		).Replace(regexp.QuoteMeta(tmpl)) + "$")
+		names, err := existing()
+		if err != nil {
+			return nil, err
+		}
+		matched := false
+		for _, name := range names {
+			if pattern.MatchString(name) {
+				targets = append(targets, destinationTarget{source: table, dest: name})
+				matched = true
+			}
+		}
+		if !matched {
+			targets = append(targets, destinationTarget{source: table, dest: tmpl, unmatched: true})
+		}
+	}
+	return targets, nil
+}
+
+// targetDestinations returns the names of the existing destination tables
+// of targets.
+func targetDestinations(targets []destinationTarget) []string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		if !t.unmatched {
+			names = append(names, t.dest)
+		}
+	}
+	return names
+}
 
+// destinationTableNames lists the destination schema's tables, sorted, read
+// once per checker.
+func (p *PreflightChecker) destinationTableNames(ctx context.Context) ([]string, error) {
+	if p.destTables != nil {
+		return p.destTables, nil
+	}
+	names, err := schemaTableNames(ctx, p.destinationDB, p.destinationDBName, p.logger)
+	if err != nil {
+		return nil, err
+	}
+	p.destTables = names
+	return names, nil
+}
+
+// schemaTableNames lists the tables of schema on db, sorted.
+func schemaTableNames(ctx context.Context, db *sql.DB, schema string, log *logger.Logger) ([]string, error) {
 	const query = `
 		SELECT TABLE_NAME
 		FROM information_schema.TABLES
 		WHERE TABLE_SCHEMA = ?`
 
-	rows, err := p.destinationDB.QueryContext(ctx, query, p.destinationDBName)
+	rows, err := db.QueryContext(ctx, query, schema)
 	if err != nil {
-		return fmt.Errorf("failed to query destination tables: %w", err)
+		return nil, fmt.Errorf("failed to query destination tables: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			p.logger.Warnf("Failed to close rows: %v", err)
+			log.Warnf("Failed to close rows: %v", err)
 		}
 	}()
 
-	existingTables := make(map[string]bool)
+	names := []string{}
 	for rows.Next() {
 		var tableName string
 		if err := rows.Scan(&tableName); err != nil {
-			return err
+			return nil, err
 		}
-		existingTables[tableName] = true
+		names = append(names, tableName)
 	}
 	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ValidateDestinationTablesExist checks that all graph tables exist in destination DB.
+func (p *PreflightChecker) ValidateDestinationTablesExist(ctx context.Context, tables []string) error {
+	if p.destinationDB == nil {
+		return fmt.Errorf("destination database not configured; call ConfigureDestination first")
+	}
+	p.logger.Debug("Checking destination table existence...")
+
+	names, err := p.destinationTableNames(ctx)
+	if err != nil {
+		return err
+	}
+	existingTables := make(map[string]bool, len(names))
+	for _, name := range names {
+		existingTables[name] = true
+	}
+	targets, err := p.destinationTargets(ctx, tables)
+	if err != nil {
 		return err
 	}
 
 	var missingTables []string
-	for _, table := range tables {
-		if !existingTables[table] {
-			missingTables = append(missingTables, table)
+	for _, t := range targets {
+		if !existingTables[t.dest] {
+			missingTables = append(missingTables, t.label())
 		}
 	}
 
@@ -1102,13 +1523,13 @@ func (p *PreflightChecker) ValidateDestinationTablesExist(ctx context.Context, t
 // generated, MySQL rejects explicit inserts with Error 3105 even under INSERT
 // IGNORE. A source-generated column writing into a plain destination column is
 // fine — SELECT materialises the value and the destination accepts it.
-// charsetStrict controls whether a charset mismatch is fatal (true) or only
-// a warning (false, used when sha256 verification will catch any corruption).
-func columnIncompatibility(s, d ColumnDefinition, charsetStrict bool) string {
+// Character sets, collations and ENUM/SET value lists are left to
+// ValidateCharsetCompatibility (CHARSET_CHECK).
+func columnIncompatibility(s, d ColumnDefinition) string {
 	if s.ColumnName != d.ColumnName {
 		return "column name mismatch"
 	}
-	if normalizeColumnType(s.ColumnType) != normalizeColumnType(d.ColumnType) {
+	if !sameEnumKind(s.ColumnType, d.ColumnType) && normalizeColumnType(s.ColumnType) != normalizeColumnType(d.ColumnType) {
 		return "column type mismatch"
 	}
 	if s.IsNullable == "YES" && d.IsNullable == "NO" {
@@ -1123,10 +1544,6 @@ func columnIncompatibility(s, d ColumnDefinition, charsetStrict bool) string {
 	if isGeneratedColumn(d.Extra) {
 		return "destination column is generated (copy inserts explicit values for every column; MySQL rejects them with Error 3105 even under INSERT IGNORE)"
 	}
-	if charsetStrict && s.CharacterSet != d.CharacterSet {
-		return fmt.Sprintf("character set mismatch (source=%s, destination=%s): copying can silently transliterate or truncate text and count verification cannot detect it; align charsets or use sha256 verification",
-			s.CharacterSet, d.CharacterSet)
-	}
 	return ""
 }
 
@@ -1152,51 +1569,68 @@ func normalizeColumnType(t string) string {
 
 // ValidateDestinationSchemaCompatibility ensures destination tables can receive
 // copies of the source tables: identical column names, order, and types, with
-// the same primary key. The destination is allowed to drop secondary indexes,
+// the same primary key. Charsets and ENUM/SET values are compared separately
+// by ValidateCharsetCompatibility. The destination is allowed to drop secondary indexes,
 // auto_increment, and column defaults, and to relax NOT NULL — see
-// columnIncompatibility for the exact rules.
+// columnIncompatibility for the exact rules. Extra trailing destination columns
+// fail unless SetAllowExtraDestinationColumns is enabled.
 func (p *PreflightChecker) ValidateDestinationSchemaCompatibility(ctx context.Context, tables []string) error {
 	if p.destinationDB == nil {
 		return fmt.Errorf("destination database not configured; call ConfigureDestination first")
 	}
 	p.logger.Debug("Checking destination schema compatibility...")
 
+	targets, err := p.destinationTargets(ctx, tables)
+	if err != nil {
+		return err
+	}
 	var incompatible []string
-	for _, table := range tables {
-		sourceColumns, err := p.getTableColumns(ctx, p.db, p.sourceDBName, table)
+	for _, target := range targets {
+		if target.unmatched {
+			continue
+		}
+		table := target.label()
+		sourceColumns, err := p.getTableColumns(ctx, p.db, p.sourceDBName, target.source)
 		if err != nil {
-			return fmt.Errorf("failed to read source schema for %s: %w", table, err)
+			return fmt.Errorf("failed to read source schema for %s: %w", target.source, err)
 		}
-		destColumns, err := p.getTableColumns(ctx, p.destinationDB, p.destinationDBName, table)
+		destColumns, err := p.getTableColumns(ctx, p.destinationDB, p.destinationDBName, target.dest)
 		if err != nil {
-			return fmt.Errorf("failed to read destination schema for %s: %w", table, err)
+			return fmt.Errorf("failed to read destination schema for %s: %w", target.dest, err)
+		}
+
+		if len(destColumns) > len(sourceColumns) && p.allowExtraDestColumns {
+			extra := destColumns[len(sourceColumns):]
+			if reason := p.extraColumnsIncompatibility(extra); reason != "" {
+				incompatible = append(incompatible, fmt.Sprintf("%s(%s)", table, reason))
+				continue
+			}
+			for _, d := range extra {
+				p.logger.Warnf("Table %s: destination has extra column %s %s (allowed by safety.allow_extra_destination_columns; it will be left NULL)",
+					table, d.ColumnName, d.ColumnType)
+			}
+			destColumns = destColumns[:len(sourceColumns)]
 		}
 
 		if len(sourceColumns) != len(destColumns) {
-			incompatible = append(incompatible, fmt.Sprintf("%s(column count mismatch: source=%d destination=%d)", table, len(sourceColumns), len(destColumns)))
+			hint := ""
+			if len(destColumns) > len(sourceColumns) {
+				hint = "; trailing nullable destination-only columns can be allowed with safety.allow_extra_destination_columns"
+			}
+			incompatible = append(incompatible, fmt.Sprintf("%s(column count mismatch: source=%d destination=%d%s)", table, len(sourceColumns), len(destColumns), hint))
 			continue
 		}
 
-		charsetStrict := p.charsetMismatchFatal()
 		for i := range sourceColumns {
 			s := sourceColumns[i]
 			d := destColumns[i]
-			if reason := columnIncompatibility(s, d, charsetStrict); reason != "" {
+			if reason := columnIncompatibility(s, d); reason != "" {
 				incompatible = append(incompatible, fmt.Sprintf("%s(position %d: %s; source=%s %s nullable=%s key=%s extra=%s, destination=%s %s nullable=%s key=%s extra=%s)",
 					table, s.OrdinalPosition, reason,
 					s.ColumnName, s.ColumnType, s.IsNullable, s.ColumnKey, s.Extra,
 					d.ColumnName, d.ColumnType, d.IsNullable, d.ColumnKey, d.Extra))
 				break
 			}
-			// Emit advisory warnings for charset/collation differences that are
-			// not fatal in this run (non-strict path: sha256 verification active).
-			if s.CharacterSet != d.CharacterSet {
-				p.logger.Warnf("Table %s column %s: charset differs (source=%s destination=%s); sha256 verification will fail before delete if data is altered",
-					table, s.ColumnName, s.CharacterSet, d.CharacterSet)
-			} else if s.Collation != d.Collation {
-				p.logger.Warnf("Table %s column %s: collation differs (source=%s destination=%s); stored bytes are identical but comparisons/sorting may differ in the archive",
-					table, s.ColumnName, s.Collation, d.Collation)
-			}
 		}
 	}
 
@@ -1212,6 +1646,29 @@ func (p *PreflightChecker) ValidateDestinationSchemaCompatibility(ctx context.Co
 	return nil
 }
 
+// extraColumnsIncompatibility reports why destination-only trailing columns
+// cannot be tolerated, or "" when they can. Copy names every source column in
+// its INSERT, so an extra column only needs to accept NULL. sha256
+// verification hashes SELECT * on both sides and would always fail.
+func (p *PreflightChecker) extraColumnsIncompatibility(extra []ColumnDefinition) string {
+	if !p.verification.SkipVerification && p.verification.EffectiveMethod() == "sha256" {
+		return "destination has extra columns, which sha256 verification cannot compare; use count verification or drop the columns"
+	}
+	for _, d := range extra {
+		if d.IsNullable != "YES" || isGeneratedColumn(d.Extra) {
+			return fmt.Sprintf("destination-only column %s must be nullable and not generated", d.ColumnName)
+		}
+	}
+	return ""
+}
+
+// SetAllowExtraDestinationColumns lets ValidateDestinationSchemaCompatibility
+// accept destination tables that append nullable columns after the source
+// columns (additive schema drift). Wired from safety.allow_extra_destination_columns.
+func (p *PreflightChecker) SetAllowExtraDestinationColumns(allow bool) {
+	p.allowExtraDestColumns = allow
+}
+
 // formatGrantee converts CURRENT_USER() output (user@host) into the quoted
 // GRANTEE format used by information_schema privilege tables ('user'@'host').
 // Verified against MySQL 8.4: the GRANTEE column is built by plain
@@ -1282,7 +1739,11 @@ func (p *PreflightChecker) ValidateDestinationWritePermissions(ctx context.Conte
 		return err
 	}
 
-	missing, err := p.tablesMissingPrivilege(ctx, p.destinationDB, grantees, p.destinationDBName, tables, "INSERT")
+	targets, err := p.destinationTargets(ctx, tables)
+	if err != nil {
+		return err
+	}
+	missing, err := p.tablesMissingPrivilege(ctx, p.destinationDB, grantees, p.destinationDBName, targetDestinations(targets), "INSERT")
 	if err != nil {
 		return err
 	}
@@ -1464,7 +1925,18 @@ func (p *PreflightChecker) ValidateSourceDeletePermissions(ctx context.Context,
 	return nil
 }
 
+// columnCacheKey identifies one table's columns on one server.
+type columnCacheKey struct {
+	db     *sql.DB
+	schema string
+	table  string
+}
+
 func (p *PreflightChecker) getTableColumns(ctx context.Context, db *sql.DB, dbName, table string) ([]ColumnDefinition, error) {
+	key := columnCacheKey{db: db, schema: dbName, table: table}
+	if columns, ok := p.columnCache[key]; ok {
+		return columns, nil
+	}
 	const query = `
 		SELECT
 			ORDINAL_POSITION,
@@ -1502,6 +1974,9 @@ func (p *PreflightChecker) getTableColumns(ctx context.Context, db *sql.DB, dbNa
 		return nil, err
 	}
 
+	if p.columnCache != nil {
+		p.columnCache[key] = columns
+	}
 	return columns, nil
 }
 
@@ -1582,7 +2057,11 @@ func (p *PreflightChecker) ValidateDestinationInsertTriggers(ctx context.Context
 	}
 	p.logger.Debug("Checking destination INSERT triggers...")
 
-	triggers, err := p.CheckInsertTriggers(ctx, tables)
+	targets, err := p.destinationTargets(ctx, tables)
+	if err != nil {
+		return err
+	}
+	triggers, err := p.CheckInsertTriggers(ctx, targetDestinations(targets))
 	if err != nil {
 		return err
 	}
@@ -1664,7 +2143,12 @@ func (p *PreflightChecker) SetVerification(v config.VerificationConfig) {
 	p.verification = v
 }
 
-// charsetMismatchFatal reports whether a charset difference must fail preflight.
+// charsetMismatchFatal reports whether a charset difference must fail
+// preflight: only sha256 and server_checksum compare the stored text.
 func (p *PreflightChecker) charsetMismatchFatal() bool {
-	return p.verification.SkipVerification || p.verification.EffectiveMethod() != "sha256"
+	switch p.verification.EffectiveMethod() {
+	case "sha256", "server_checksum":
+		return p.verification.SkipVerification
+	}
+	return true
 }
@@ -170,7 +170,7 @@ func TestIntegrationSchemaCompatibility_StricterDestinationRejected(t *testing.T
 }
 
 // TestIntegrationSchemaCompatibility_CharsetMismatch verifies real
-// information_schema charset metadata drives the check: latin1 destination
+// information_schema charset metadata drives CHARSET_CHECK: latin1 destination
 // column fails under (default) count verification, passes with a warning
 // under sha256.
 func TestIntegrationSchemaCompatibility_CharsetMismatch(t *testing.T) {
@@ -198,7 +198,10 @@ func TestIntegrationSchemaCompatibility_CharsetMismatch(t *testing.T) {
 		t.Fatalf("failed to create destination table: %v", err)
 	}
 
-	err := checker.ValidateDestinationSchemaCompatibility(ctx, []string{schemaRelaxTable})
+	if err := checker.ValidateDestinationSchemaCompatibility(ctx, []string{schemaRelaxTable}); err != nil {
+		t.Fatalf("expected schema compatibility to leave charsets to CHARSET_CHECK, got: %v", err)
+	}
+	err := checker.ValidateCharsetCompatibility(ctx, []string{schemaRelaxTable})
 	if err == nil {
 		t.Fatal("expected charset mismatch error under count verification, got nil")
 	}
@@ -207,7 +210,7 @@ func TestIntegrationSchemaCompatibility_CharsetMismatch(t *testing.T) {
 	}
 
 	checker.SetVerification(config.VerificationConfig{Method: "sha256"})
-	if err := checker.ValidateDestinationSchemaCompatibility(ctx, []string{schemaRelaxTable}); err != nil {
+	if err := checker.ValidateCharsetCompatibility(ctx, []string{schemaRelaxTable}); err != nil {
 		t.Fatalf("expected charset mismatch to pass under sha256 verification, got: %v", err)
 	}
 }
@@ -0,0 +1,88 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+// PreviewRows fetches up to limit full source rows of table whose primary key
+// is in pks, keyed by column name, so operators can inspect what a batch would
+// archive. The PKs are looked up in IN (...) chunks like discovery; no further
+// chunk is queried once limit rows are read. Text columns come back as
+// strings, binary columns as []byte. Rows are in server order, not pks order.
+func (d *RecordDiscovery) PreviewRows(ctx context.Context, table string, pks []interface{}, limit int) ([]map[string]interface{}, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("preview limit must be positive, got %d", limit)
+	}
+	if d.graph.GetNode(table) == nil {
+		return nil, fmt.Errorf("table %q is not part of the job graph", table)
+	}
+
+	pkColumn := d.graph.GetPK(table)
+	var rows []map[string]interface{}
+	size := sqlutil.InClauseSize(d.graph.BatchSizeFor(table, d.batchSize), d.maxIn)
+	for _, chunk := range sqlutil.ChunkValues(pks, size) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("preview interrupted: %w", err)
+		}
+		query := fmt.Sprintf(
+			"SELECT * FROM %s WHERE %s IN (%s) LIMIT ?",
+			sqlutil.QuoteIdentifier(table),
+			sqlutil.QuoteIdentifier(pkColumn),
+			sqlutil.Placeholders(len(chunk), ", "),
+		)
+		args := append(append(make([]interface{}, 0, len(chunk)+1), chunk...), limit-len(rows))
+		result, err := d.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to preview %s rows: %w", table, err)
+		}
+		rows, err = scanPreviewRows(result, rows)
+		_ = result.Close() // Ignore error during cleanup
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s rows: %w", table, err)
+		}
+		if len(rows) >= limit {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// scanPreviewRows appends each row of rows to dst as a column-name map.
+func scanPreviewRows(rows *sql.Rows, dst []map[string]interface{}) ([]map[string]interface{}, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	binary := make([]bool, len(cols))
+	if colTypes, err := rows.ColumnTypes(); err == nil && len(colTypes) == len(cols) {
+		for i, ct := range colTypes {
+			binary[i] = types.IsBinaryType(ct.DatabaseTypeName())
+		}
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok && !binary[i] {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		dst = append(dst, row)
+	}
+	return dst, rows.Err()
+}
@@ -0,0 +1,74 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewRows_MapsColumnsAndStopsAtLimit(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	discovery, err := NewRecordDiscovery(createTestGraph(), db, 2)
+	require.NoError(t, err)
+
+	// Chunks of 2 PKs: the second chunk only asks for the one row still
+	// missing, and the third chunk is never queried.
+	mock.ExpectQuery("SELECT * FROM `orders` WHERE `id` IN (?, ?) LIMIT ?").
+		WithArgs(10, 11, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "note"}).
+			AddRow(int64(10), []byte("closed"), nil).
+			AddRow(int64(11), []byte("open"), []byte("rush")))
+	mock.ExpectQuery("SELECT * FROM `orders` WHERE `id` IN (?, ?) LIMIT ?").
+		WithArgs(12, 13, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "note"}).
+			AddRow(int64(12), []byte("closed"), nil))
+
+	rows, err := discovery.PreviewRows(context.Background(), "orders", []interface{}{10, 11, 12, 13, 14}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"id": int64(10), "status": "closed", "note": nil},
+		{"id": int64(11), "status": "open", "note": "rush"},
+		{"id": int64(12), "status": "closed", "note": nil},
+	}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreviewRows_FewerRowsThanLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	discovery, err := NewRecordDiscovery(createTestGraph(), db, 100)
+	require.NoError(t, err)
+
+	// A PK deleted since discovery simply yields no row.
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE `id` IN \\(\\?, \\?\\) LIMIT \\?").
+		WithArgs(1, 2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), []byte("Alice")))
+
+	rows, err := discovery.PreviewRows(context.Background(), "users", []interface{}{1, 2}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"id": int64(1), "name": "Alice"}}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreviewRows_RejectsBadInput(t *testing.T) {
+	discovery, err := NewRecordDiscovery(createTestGraph(), nil, 100)
+	require.NoError(t, err)
+
+	_, err = discovery.PreviewRows(context.Background(), "users", []interface{}{1}, 0)
+	assert.ErrorContains(t, err, "limit must be positive")
+	_, err = discovery.PreviewRows(context.Background(), "invoices", []interface{}{1}, 5)
+	assert.ErrorContains(t, err, "not part of the job graph")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = discovery.PreviewRows(ctx, "users", []interface{}{1}, 5)
+	assert.ErrorIs(t, err, context.Canceled)
+}
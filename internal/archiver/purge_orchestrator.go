@@ -10,6 +10,7 @@ import (
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/dbsmedya/goarchive/internal/logger"
 	"github.com/dbsmedya/goarchive/internal/types"
+	"github.com/dbsmedya/goarchive/internal/verifier"
 )
 
 // PurgeResult contains statistics and status of purge operation.
@@ -20,6 +21,7 @@ type PurgeResult struct {
 	Duration         time.Duration
 	BatchesProcessed int
 	RecordsDeleted   int64
+	RecordsVerified  int64 // rows confirmed on the destination (SetVerifyDestination)
 	Success          bool
 }
 
@@ -36,6 +38,14 @@ type PurgeOrchestrator struct {
 	force          bool
 	staleAtStartup bool
 	stopCh         <-chan struct{} // cooperative graceful-stop signal (nil = disabled)
+	stopMode       StopMode        // what a cooperative stop does to the in-flight batch
+	deleteCap      deleteCap       // safety.max_delete_rows for the current run
+
+	// verifyDestination makes purge a "delete after archive" pass: each root's
+	// records must verify against the destination before they are deleted.
+	verifyDestination bool
+
+	hooks Hooks // callbacks around each root's phases (NoopHooks by default)
 }
 
 // NewPurgeOrchestrator creates a new purge orchestrator.
@@ -57,6 +67,7 @@ func NewPurgeOrchestrator(cfg *config.Config, jobName string, jobCfg *config.Job
 		dbManager:     dbManager,
 		logger:        logger.NewDefault(),
 		processingCfg: jobCfg.GetJobProcessing(cfg.Processing),
+		hooks:         NoopHooks{},
 	}, nil
 }
 
@@ -67,6 +78,7 @@ func (o *PurgeOrchestrator) Initialize() error {
 	}
 
 	builder := graph.NewBuilder(o.jobConfig)
+	builder.SetLogger(o.logger)
 	g, err := builder.Build()
 	if err != nil {
 		return fmt.Errorf("failed to build dependency graph: %w", err)
@@ -74,6 +86,9 @@ func (o *PurgeOrchestrator) Initialize() error {
 	if g.HasCycle() {
 		return fmt.Errorf("dependency cycle detected in graph")
 	}
+	if err := checkReferenceKeys(g); err != nil {
+		return err
+	}
 
 	o.graph = g
 	o.initialized = true
@@ -94,6 +109,8 @@ func (o *PurgeOrchestrator) Execute(ctx context.Context) (result *PurgeResult, e
 		StartedAt: time.Now(),
 		Success:   false,
 	}
+	o.deleteCap.reset(o.config.Safety.MaxDeleteRows)
+	o.deleteCap.unconfirmed = confirmDeletes(o.config.Safety, o.jobName, o.jobConfig)
 
 	startup, err := beginJobStartup(ctx, o.dbManager.Destination, o.logger, o.jobName, o.jobConfig.RootTable, JobTypePurge, "purge", o.force, o.config.Destination.EffectiveJobSchema())
 	if err != nil {
@@ -127,29 +144,73 @@ func (o *PurgeOrchestrator) Execute(ctx context.Context) (result *PurgeResult, e
 		o.processingCfg.BatchSize,
 		jobState.LastProcessedRootPKID,
 	)
+	window, err := openIncrementalWindow(ctx, o.dbManager.Source, resumeMgr, o.jobConfig.IncrementalColumn, o.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open incremental window: %w", err)
+	}
+	fetcher.SetIncrementalWindow(window)
+	fetcher.SetOrder(o.processingCfg.RootOrder)
 
-	discovery, err := NewRecordDiscovery(o.graph, o.dbManager.Source, o.processingCfg.BatchSize)
+	discovery, err := newJobDiscovery(o.dbManager, o.graph, o.processingCfg, o.logger.WithPhase("discovery"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create record discovery: %w", err)
 	}
-	discovery.SetLogger(o.logger)
-	deletePhase, err := NewDeletePhase(o.dbManager.Source, o.graph, o.processingCfg.BatchDeleteSize, o.logger)
+	deletePhase, err := newJobDeletePhase(o.dbManager, o.graph, o.processingCfg, o.logger.WithPhase("delete"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create delete phase: %w", err)
 	}
-	// Throttle deletes (between batch_delete_size chunks) to limit binlog/replication lag.
-	deletePhase.SetSleepSeconds(o.processingCfg.DeleteSleepSeconds)
+	deletePhase.SetRateLimiter(NewRowRateLimiter(o.processingCfg.MaxRowsPerSecond))
+	deletePhase.SetTransactional(o.config.Safety.TransactionalDelete)
+	deletePhase.SetOrphanCheck(o.config.Safety.OrphanCheck)
+	deletePhase.SetPostDeleteCheck(o.config.Safety.PostDeleteCheck)
+	deletePhase.SetForeignKeyChecksDisabled(o.config.Safety.DisableSourceForeignKeyChecks)
+	if err := applyCascadeSkips(ctx, o.dbManager.Source, o.config.Source.Database, o.graph, o.config.Safety, o.logger, deletePhase); err != nil {
+		return nil, fmt.Errorf("failed to load cascade rules: %w", err)
+	}
+	var dataVerifier *verifier.Verifier
+	if o.verifyDestination {
+		dataVerifier, err = o.newDestinationVerifier()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create verifier: %w", err)
+		}
+	}
+	audit, err := openDeleteAudit(o.config.Safety, o.jobName, deletePhase)
+	if err != nil {
+		return nil, err
+	}
+	if audit != nil {
+		defer func() {
+			if cerr := audit.Close(); cerr != nil {
+				o.logger.Errorf("Failed to finalize delete audit log: %v", cerr)
+			}
+		}()
+	}
+	backup, err := openDeleteBackup(o.config.Safety, o.jobName, deletePhase)
+	if err != nil {
+		return nil, err
+	}
+	if backup != nil {
+		defer func() {
+			if cerr := backup.Close(); cerr != nil {
+				o.logger.Errorf("Failed to close delete backup: %v", cerr)
+			}
+		}()
+	}
 
 	// Honor processing.batch_size for resume bookkeeping chunking (issue #8,
 	// Problem 2). Must run before replay and the batch loop.
 	o.applyResumeChunkSizing(resumeMgr)
 
+	budgetCtx, cancelBudget := runtimeBudget(ctx, o.processingCfg.MaxRuntime)
+	defer cancelBudget()
+
 	if shouldResume {
-		if err := o.replayPendingPKs(ctx, resumeMgr, discovery, deletePhase, fetcher); err != nil {
+		if err := o.replayPendingPKs(ctx, resumeMgr, discovery, dataVerifier, deletePhase, fetcher); err != nil {
 			return nil, fmt.Errorf("pending replay failed: %w", err)
 		}
 	}
 
+	drained := false
 	for {
 		select {
 		case <-ctx.Done():
@@ -163,19 +224,25 @@ func (o *PurgeOrchestrator) Execute(ctx context.Context) (result *PurgeResult, e
 			o.logger.Warn("Graceful stop requested - stopping at batch boundary (run again to resume)")
 			break
 		}
+		if budgetExceeded(ctx, budgetCtx) {
+			o.logger.Warnw("Runtime budget exhausted - stopping at batch boundary (run again to resume)",
+				"max_runtime", o.processingCfg.MaxRuntime)
+			return result, ErrRuntimeBudgetExceeded
+		}
 
 		rootIDs, err := fetcher.FetchNextBatch(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch root IDs: %w", err)
 		}
 		if len(rootIDs) == 0 {
+			drained = true
 			break
 		}
 
 		// Operator pause switch: block before processing this batch while the
 		// sentinel file exists.
-		if err := newSentinelGate(o.processingCfg.SentinelFile, o.logger).wait(ctx, o.stopCh); err != nil {
-			return nil, err
+		if err := newSentinelGate(o.processingCfg.SentinelFile, o.logger).wait(budgetCtx, o.stopCh); err != nil {
+			return nil, budgetErr(ctx, budgetCtx, err)
 		}
 		if stopRequested(o.stopCh) {
 			o.logger.Warn("Graceful stop requested - stopping at batch boundary (run again to resume)")
@@ -187,29 +254,36 @@ func (o *PurgeOrchestrator) Execute(ctx context.Context) (result *PurgeResult, e
 			return nil, fmt.Errorf("failed to log pending batch entries: %w", err)
 		}
 
+		batchCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
 		for _, rootID := range rootIDs {
-			deleted, err := o.processPurgeRoot(ctx, rootID, discovery, deletePhase, fetcher, resumeMgr)
+			deleted, err := o.processPurgeRoot(batchCtx, rootID, discovery, dataVerifier, deletePhase, fetcher, resumeMgr, result)
 			if err != nil {
+				release()
 				return nil, err
 			}
 			result.RecordsDeleted += deleted
 		}
+		release()
 
 		if o.processingCfg.SleepSeconds > 0 {
 			sleepDuration := time.Duration(o.processingCfg.SleepSeconds * float64(time.Second))
-			if err := interruptibleSleep(ctx, o.stopCh, sleepDuration); err != nil {
+			if err := interruptibleSleep(budgetCtx, o.stopCh, sleepDuration); err != nil && !budgetExceeded(ctx, budgetCtx) {
 				return result, err
 			}
 		}
 	}
 
+	if err := window.complete(ctx, resumeMgr, o.jobName, drained); err != nil {
+		return nil, err
+	}
+
 	result.CompletedAt = time.Now()
 	result.Duration = result.CompletedAt.Sub(result.StartedAt)
 	result.Success = true
 	return result, nil
 }
 
-func (o *PurgeOrchestrator) replayPendingPKs(ctx context.Context, resumeMgr *ResumeManager, discovery *RecordDiscovery, deletePhase *DeletePhase, fetcher *RootIDFetcher) error {
+func (o *PurgeOrchestrator) replayPendingPKs(ctx context.Context, resumeMgr *ResumeManager, discovery *RecordDiscovery, dataVerifier *verifier.Verifier, deletePhase *DeletePhase, fetcher *RootIDFetcher) error {
 	pending, dataType, unsigned, err := pendingReplayPKs(ctx, resumeMgr, o.jobName, o.graph)
 	if err != nil {
 		return err
@@ -236,7 +310,10 @@ func (o *PurgeOrchestrator) replayPendingPKs(ctx context.Context, resumeMgr *Res
 		if err != nil {
 			return err
 		}
-		if _, err := o.processPurgeRoot(ctx, typedPK, discovery, deletePhase, fetcher, resumeMgr); err != nil {
+		rootCtx, release := batchContext(ctx, o.stopCh, o.stopMode)
+		_, err = o.processPurgeRoot(rootCtx, typedPK, discovery, dataVerifier, deletePhase, fetcher, resumeMgr, nil)
+		release()
+		if err != nil {
 			return fmt.Errorf("replay failed for pk=%s: %w", rawPK, err)
 		}
 	}
@@ -253,13 +330,76 @@ func (o *PurgeOrchestrator) applyResumeChunkSizing(resumeMgr *ResumeManager) {
 	resumeMgr.SetChunkSize(o.processingCfg.BatchSize)
 }
 
-func (o *PurgeOrchestrator) processPurgeRoot(ctx context.Context, rootID interface{}, discovery *RecordDiscovery, deletePhase *DeletePhase, fetcher *RootIDFetcher, resumeMgr *ResumeManager) (int64, error) {
-	discovered, err := discovery.Discover(ctx, []interface{}{rootID})
+// newDestinationVerifier builds the verifier used by SetVerifyDestination,
+// honoring the job's verification method and transformed-column exclusions.
+func (o *PurgeOrchestrator) newDestinationVerifier() (*verifier.Verifier, error) {
+	if o.dbManager.Destination == nil {
+		return nil, fmt.Errorf("destination connection is required to verify before purge")
+	}
+	verification := o.jobConfig.GetJobVerification(o.config.Verification)
+	v, err := verifier.NewVerifier(o.dbManager.ReadSource(), o.dbManager.Destination, o.graph,
+		verifier.VerificationMethod(verification.EffectiveMethod()), o.logger.WithPhase("verify"))
+	if err != nil {
+		return nil, err
+	}
+	v.SetChunkSize(o.processingCfg.BatchSize)
+	v.SetMaxInClauseSize(o.processingCfg.MaxInClauseSize)
+	v.SetStatementTimeout(o.processingCfg.StatementTimeout)
+	v.SetCountPrecheck(verification.CountPrecheck)
+	v.SetCanonicalValues(verification.CanonicalValues)
+	v.SetGlobalIgnoredColumns(verification.IgnoreColumns)
+	transforms, err := TransformsFromJob(o.jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	ignoreVerifyColumns(o.jobConfig, transforms, v)
+	return v, nil
+}
+
+// processPurgeRoot discovers and deletes one root's records. With a non-nil
+// dataVerifier the records must first verify against the destination; a
+// mismatch fails the root before anything is deleted. result (nil during
+// replay) accumulates verified row counts.
+func (o *PurgeOrchestrator) processPurgeRoot(ctx context.Context, rootID interface{}, discovery *RecordDiscovery, dataVerifier *verifier.Verifier, deletePhase *DeletePhase, fetcher *RootIDFetcher, resumeMgr *ResumeManager, result *PurgeResult) (int64, error) {
+	info := PhaseInfo{JobName: o.jobName, RootPKs: []interface{}{rootID}}
+	var discovered *types.RecordSet
+	err := o.runPhase(ctx, PhaseDiscovery, info, func(ctx context.Context) (err error) {
+		discovered, err = discovery.Discover(ctx, []interface{}{rootID})
+		return err
+	})
 	if err != nil {
 		markFailedUnlessCanceled(ctx, resumeMgr, o.logger, o.jobName, rootID, err)
 		return 0, fmt.Errorf("discovery failed: %w", err)
 	}
-	deleteStats, err := deletePhase.Delete(ctx, convertRecordSet(discovered))
+	info.Records = discovered
+	if dataVerifier != nil {
+		var verifyStats *verifier.VerifyStats
+		err := o.runPhase(ctx, PhaseVerify, info, func(ctx context.Context) (err error) {
+			verifyStats, err = dataVerifier.Verify(ctx, discovered)
+			return err
+		})
+		if err != nil {
+			markFailedUnlessCanceled(ctx, resumeMgr, o.logger, o.jobName, rootID, err)
+			return 0, fmt.Errorf("destination verification failed, nothing deleted: %w", err)
+		}
+		if result != nil && verifyStats != nil {
+			result.RecordsVerified += verifyStats.TotalRows
+		}
+	}
+	// Over the cap or unconfirmed, the root stays pending rather than failed:
+	// nothing is wrong with it, and a run with a higher cap, the override or
+	// the confirm token replays it.
+	if err := o.deleteCap.admit(discovered); err != nil {
+		return 0, err
+	}
+	if err := o.deleteCap.confirmed(); err != nil {
+		return 0, err
+	}
+	var deleteStats *DeleteStats
+	err = o.runPhase(ctx, PhaseDelete, info, func(ctx context.Context) (err error) {
+		deleteStats, err = deletePhase.Delete(ctx, convertRecordSet(discovered))
+		return err
+	})
 	if err != nil {
 		markFailedUnlessCanceled(ctx, resumeMgr, o.logger, o.jobName, rootID, err)
 		return 0, fmt.Errorf("delete failed: %w", err)
@@ -274,11 +414,24 @@ func (o *PurgeOrchestrator) processPurgeRoot(ctx context.Context, rootID interfa
 	return deleteStats.RowsDeleted, nil
 }
 
+// SetVerifyDestination requires each root's discovered records to verify
+// against the destination (job verification method) before they are deleted,
+// for deleting separately after an earlier copy-only run. Off by default:
+// plain purge never reads the destination data tables.
+func (o *PurgeOrchestrator) SetVerifyDestination(verify bool) {
+	o.verifyDestination = verify
+}
+
 // SetForce controls heartbeat-aware advisory lock bypass.
 func (o *PurgeOrchestrator) SetForce(force bool) {
 	o.force = force
 }
 
+// SetForceMaxDeleteRows lets the run delete past safety.max_delete_rows.
+func (o *PurgeOrchestrator) SetForceMaxDeleteRows(force bool) {
+	o.deleteCap.override = force
+}
+
 // SetStopChannel wires the cooperative graceful-stop signal. When the channel
 // closes (first Ctrl-C), the loop finishes the in-flight batch and stops at the
 // next boundary. A nil channel disables cooperative stop.
@@ -286,8 +439,31 @@ func (o *PurgeOrchestrator) SetStopChannel(stop <-chan struct{}) {
 	o.stopCh = stop
 }
 
+// SetStopMode selects whether a cooperative stop lets the in-flight batch
+// finish (StopAfterBatch, the default) or cancels it (StopImmediately).
+func (o *PurgeOrchestrator) SetStopMode(mode StopMode) {
+	o.stopMode = mode
+}
+
 // SetLogger sets a custom logger for the orchestrator. Call before
 // Initialize/Execute so all phases inherit it.
 func (o *PurgeOrchestrator) SetLogger(log *logger.Logger) {
 	o.logger = log
 }
+
+// SetHooks registers callbacks run around each root's discovery, verify
+// (with SetVerifyDestination) and delete phases. nil restores NoopHooks.
+func (o *PurgeOrchestrator) SetHooks(hooks Hooks) {
+	if hooks == nil {
+		hooks = NoopHooks{}
+	}
+	o.hooks = hooks
+}
+
+// runPhase runs fn as phase of the root in info, between the hooks and under
+// the phase's timeout. A SELECT aborted at statement_timeout is reported as a
+// *StatementTimeoutError.
+func (o *PurgeOrchestrator) runPhase(ctx context.Context, phase string, info PhaseInfo, fn func(ctx context.Context) error) error {
+	err := runPhase(ctx, o.hooks, o.logger, phase, phaseTimeout(o.processingCfg, phase), info, fn)
+	return asStatementTimeout(err, o.processingCfg.StatementTimeout)
+}
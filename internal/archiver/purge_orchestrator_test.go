@@ -1,11 +1,14 @@
 package archiver
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/dbsmedya/goarchive/internal/config"
 	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/verifier"
 )
 
 // TestPurgeOrchestrator_WiresBatchSizeIntoResumeChunking guards issue #8
@@ -36,3 +39,93 @@ func TestPurgeOrchestrator_WiresBatchSizeIntoResumeChunking(t *testing.T) {
 		t.Errorf("resume chunk size = %d, want %d (batch_size ignored)", got, batchSize)
 	}
 }
+
+// newVerifiedPurgeFixture wires processPurgeRoot for a root-only customers
+// graph with count verification against a mocked destination.
+func newVerifiedPurgeFixture(t *testing.T) (o *PurgeOrchestrator, discovery *RecordDiscovery, v *verifier.Verifier, dp *DeletePhase,
+	fetcher *RootIDFetcher, rm *ResumeManager, srcMock, dstMock, rmMock sqlmock.Sqlmock) {
+	t.Helper()
+	srcDB, srcMock, _ := sqlmock.New()
+	dstDB, dstMock, _ := sqlmock.New()
+	t.Cleanup(func() { _ = srcDB.Close(); _ = dstDB.Close() })
+
+	log := logger.NewDefault()
+	g := createSimpleGraph()
+	discovery, _ = NewRecordDiscovery(g, srcDB, 100)
+	v, _ = verifier.NewVerifier(srcDB, dstDB, g, verifier.MethodCount, log)
+	dp, _ = NewDeletePhase(srcDB, g, 100, log)
+	fetcher = NewRootIDFetcher(srcDB, "customers", "id", "1=1", 100, "")
+	rm, rmMock = newReplayTestResumeManager(t)
+	o = &PurgeOrchestrator{jobName: "purge_customers", logger: log}
+	return
+}
+
+func TestProcessPurgeRoot_VerifyDestinationMismatchSkipsDelete(t *testing.T) {
+	o, discovery, v, dp, fetcher, rm, srcMock, dstMock, rmMock := newVerifiedPurgeFixture(t)
+
+	srcMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `customers`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	dstMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `customers`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	rmMock.ExpectExec("UPDATE .* SET log_status = \\?, error_message").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result := &PurgeResult{}
+	deleted, err := o.processPurgeRoot(context.Background(), int64(1), discovery, v, dp, fetcher, rm, result)
+	if err == nil || !strings.Contains(err.Error(), "nothing deleted") {
+		t.Fatalf("expected destination verification error, got %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted = %d, want 0", deleted)
+	}
+	// No DELETE was expected on the source mock: an unexpected Exec would have
+	// failed the delete phase with a different error.
+	for name, m := range map[string]sqlmock.Sqlmock{"source": srcMock, "destination": dstMock, "resume": rmMock} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestProcessPurgeRoot_VerifyDestinationMatchDeletes(t *testing.T) {
+	o, discovery, v, dp, fetcher, rm, srcMock, dstMock, rmMock := newVerifiedPurgeFixture(t)
+
+	srcMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `customers`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	dstMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `customers`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	srcMock.ExpectExec("DELETE FROM `customers`").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	rmMock.ExpectExec("UPDATE .* SET last_processed_root_pk_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	rmMock.ExpectExec("UPDATE .* SET log_status").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result := &PurgeResult{}
+	deleted, err := o.processPurgeRoot(context.Background(), int64(1), discovery, v, dp, fetcher, rm, result)
+	if err != nil {
+		t.Fatalf("processPurgeRoot: %v", err)
+	}
+	if deleted != 1 || result.RecordsVerified != 1 {
+		t.Errorf("deleted = %d, verified = %d; want 1, 1", deleted, result.RecordsVerified)
+	}
+	for name, m := range map[string]sqlmock.Sqlmock{"source": srcMock, "destination": dstMock, "resume": rmMock} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestProcessPurgeRoot_WithoutVerifierNeverReadsDestination(t *testing.T) {
+	o, discovery, _, dp, fetcher, rm, srcMock, dstMock, rmMock := newVerifiedPurgeFixture(t)
+
+	srcMock.ExpectExec("DELETE FROM `customers`").WillReturnResult(sqlmock.NewResult(0, 1))
+	rmMock.ExpectExec("UPDATE .* SET last_processed_root_pk_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	rmMock.ExpectExec("UPDATE .* SET log_status").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := o.processPurgeRoot(context.Background(), int64(1), discovery, nil, dp, fetcher, rm, nil); err != nil {
+		t.Fatalf("processPurgeRoot: %v", err)
+	}
+	if err := dstMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination: %v", err)
+	}
+	if err := srcMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package archiver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/graph"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// createAwkwardNameGraph uses a reserved word, a name with a backtick and a
+// name with a space, all legal in MySQL once quoted.
+func createAwkwardNameGraph() *graph.Graph {
+	g := graph.NewGraph("order", "key")
+	g.AddNode("line`items", &graph.Node{Name: "line`items", ForeignKey: "order id", ReferenceKey: "key", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("order", "line`items", "order id", "key", "1-N")
+	g.SetPK("line`items", "groups")
+	return g
+}
+
+func TestDiscovery_QuotesAwkwardIdentifiers(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	d, err := NewRecordDiscovery(createAwkwardNameGraph(), db, 100)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `groups` FROM `line``items` WHERE `order id` IN (?)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"groups"}).AddRow(int64(10)))
+
+	records, err := d.Discover(context.Background(), []interface{}{int64(1)})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int64(10)}, records.Records["line`items"])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_QuotesAwkwardIdentifiers(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = db.Close() }()
+
+	dp, err := NewDeletePhase(db, createAwkwardNameGraph(), 100, logger.NewDefault())
+	require.NoError(t, err)
+
+	mock.ExpectExec("DELETE FROM `line``items` WHERE `groups` IN (?)").
+		WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `order` WHERE `key` IN (?)").
+		WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = dp.Delete(context.Background(), &types.RecordSet{
+		RootPKs: []interface{}{int64(1)},
+		Records: map[string][]interface{}{
+			"order":      {int64(1)},
+			"line`items": {int64(10)},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPhases_RejectInvalidIdentifiers(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	g := createAwkwardNameGraph()
+	g.GetNode("line`items").ForeignKey = "order_id "
+
+	_, err := NewRecordDiscovery(g, db, 100)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "ends with a space"), err.Error())
+	_, err = NewDeletePhase(db, g, 100, logger.NewDefault())
+	require.ErrorContains(t, err, "table line`items foreign key")
+}
@@ -0,0 +1,91 @@
+package archiver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RowRateLimiter is a token bucket metering rows per second
+// (processing.max_rows_per_second). Copy and delete draw from one limiter per
+// run before each chunk, so the load follows the row count rather than a
+// fixed pause per batch. It is safe for concurrent use, so parallel workers
+// can share one budget. A nil *RowRateLimiter never waits.
+type RowRateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // rows per second
+	tokens float64   // available rows; negative while callers owe time
+	last   time.Time // last refill
+
+	// now and sleep are injectable seams so tests can drive a fake clock.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRowRateLimiter returns a limiter admitting rowsPerSecond rows per
+// second, or nil (unlimited) when rowsPerSecond is not positive. The bucket
+// starts empty and holds at most one second of rows, so a run cannot burst
+// ahead of the configured rate.
+func NewRowRateLimiter(rowsPerSecond float64) *RowRateLimiter {
+	if rowsPerSecond <= 0 {
+		return nil
+	}
+	return &RowRateLimiter{
+		rate:  rowsPerSecond,
+		now:   time.Now,
+		sleep: sleepContext,
+	}
+}
+
+// Wait blocks until rows rows may be processed, or until ctx is done. A
+// request larger than the bucket is admitted after the time it costs at the
+// configured rate, so chunks bigger than one second of rows still pass.
+// On cancellation the unspent reservation is returned to the bucket.
+func (l *RowRateLimiter) Wait(ctx context.Context, rows int) error {
+	if l == nil || rows <= 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	if l.last.IsZero() {
+		l.last = now
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(rows)
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := l.sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.tokens += float64(rows)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// sleepContext sleeps for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package archiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances only when the limiter sleeps.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+	return nil
+}
+
+func newFakeLimiter(rate float64) (*RowRateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	l := NewRowRateLimiter(rate)
+	l.now = clock.Now
+	l.sleep = clock.Sleep
+	return l, clock
+}
+
+func TestRowRateLimiter_NRowsTakeNOverR(t *testing.T) {
+	const rate = 500.0
+	l, clock := newFakeLimiter(rate)
+	start := clock.Now()
+
+	// Uneven chunks, as copy and delete issue them: 10,000 rows in total.
+	rows := 0
+	for _, chunk := range []int{200, 1000, 50, 750} {
+		for i := 0; i < 4; i++ {
+			require.NoError(t, l.Wait(context.Background(), chunk))
+			rows += chunk
+		}
+	}
+	require.Equal(t, 8000, rows)
+	require.NoError(t, l.Wait(context.Background(), 2000))
+	rows += 2000
+
+	elapsed := clock.Now().Sub(start)
+	want := time.Duration(float64(rows) / rate * float64(time.Second))
+	require.InDelta(t, want.Seconds(), elapsed.Seconds(), 0.01, "elapsed %v, want ~%v", elapsed, want)
+}
+
+func TestRowRateLimiter_SharedAcrossWorkers(t *testing.T) {
+	// Real clock: 4 workers x 1000 rows on one 20,000 rows/s budget must take
+	// ~200ms together, not the ~50ms each worker would need on its own.
+	l := NewRowRateLimiter(20000)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				_ = l.Wait(context.Background(), 100)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestRowRateLimiter_Cancellation(t *testing.T) {
+	l := NewRowRateLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Wait(ctx, 3600) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after cancellation")
+	}
+}
+
+func TestRowRateLimiter_Disabled(t *testing.T) {
+	require.Nil(t, NewRowRateLimiter(0))
+	var l *RowRateLimiter
+	require.NoError(t, l.Wait(context.Background(), 1_000_000))
+}
@@ -0,0 +1,80 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// ResidualRowsError is returned by Delete when the post-delete check finds
+// deleted PKs still present on the source. The deletes themselves were
+// executed (and committed).
+type ResidualRowsError struct {
+	Residual map[string]int64 // table -> rows remaining
+}
+
+func (e *ResidualRowsError) Error() string {
+	tables := make([]string, 0, len(e.Residual))
+	for table := range e.Residual {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	msgs := make([]string, len(tables))
+	for i, table := range tables {
+		msgs[i] = fmt.Sprintf("%s=%d", table, e.Residual[table])
+	}
+	return "post-delete check found deleted rows still in source: " + strings.Join(msgs, ", ")
+}
+
+// checkResidual counts, on the source connection, the rows of every table of
+// recordSet whose PKs were just deleted, including tables skipped because an
+// ON DELETE CASCADE removes them. Tables with rows left are recorded in
+// stats.ResidualRows and reported as a *ResidualRowsError.
+func (dp *DeletePhase) checkResidual(ctx context.Context, recordSet *RecordSet, stats *DeleteStats) error {
+	order, err := dp.graph.DeleteOrderContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get delete order: %w", err)
+	}
+
+	for _, table := range order {
+		pks := recordSet.Records[table]
+		if len(pks) == 0 {
+			continue
+		}
+		pkColumn := dp.graph.GetPK(table)
+		var remaining int64
+		for _, chunk := range sqlutil.ChunkValues(pks, sqlutil.InClauseSize(dp.graph.BatchSizeFor(table, dp.batchSize), dp.maxIn)) {
+			query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IN (%s)",
+				sqlutil.QuoteIdentifier(table), sqlutil.QuoteIdentifier(pkColumn), sqlutil.Placeholders(len(chunk), ","))
+			var count int64
+			if err := dp.db.QueryRowContext(ctx, query, chunk...).Scan(&count); err != nil {
+				return fmt.Errorf("post-delete check of table %s failed: %w", table, err)
+			}
+			remaining += count
+		}
+		if remaining == 0 {
+			continue
+		}
+		if stats.ResidualRows == nil {
+			stats.ResidualRows = make(map[string]int64)
+		}
+		stats.ResidualRows[table] = remaining
+		dp.logger.Errorf("Post-delete check: %d deleted row(s) of table %s are still in source", remaining, table)
+	}
+
+	if len(stats.ResidualRows) > 0 {
+		return &ResidualRowsError{Residual: stats.ResidualRows}
+	}
+	return nil
+}
+
+// SetPostDeleteCheck makes Delete re-count the deleted PKs of every table on
+// the source afterwards (safety.post_delete_check) and fail with a
+// *ResidualRowsError when any remain, e.g. re-inserted by a trigger. It costs
+// one SELECT COUNT(*) per table per chunk. Off by default.
+func (dp *DeletePhase) SetPostDeleteCheck(enabled bool) {
+	dp.postDeleteCheck = enabled
+}
@@ -0,0 +1,90 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+func TestDelete_PostDeleteCheckClean(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 500, logger.NewDefault())
+	dp.SetPostDeleteCheck(true)
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{"users": {1}, "orders": {10, 11}},
+	}
+
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WithArgs(10, 11).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders` WHERE `id` IN \\(\\?,\\?\\)").WithArgs(10, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users` WHERE `id` IN \\(\\?\\)").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+
+	stats, err := dp.Delete(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if stats.ResidualRows != nil {
+		t.Errorf("expected no residual rows, got %v", stats.ResidualRows)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDelete_PostDeleteCheckResidual(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 500, logger.NewDefault())
+	dp.SetPostDeleteCheck(true)
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{"users": {1}, "orders": {10, 11}},
+	}
+
+	mock.ExpectExec("DELETE FROM `orders` WHERE `id` IN").WithArgs(10, 11).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	// A trigger re-inserted one of the orders.
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders`").WithArgs(10, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+
+	stats, err := dp.Delete(context.Background(), recordSet)
+	var residualErr *ResidualRowsError
+	if !errors.As(err, &residualErr) {
+		t.Fatalf("expected *ResidualRowsError, got %v", err)
+	}
+	if err.Error() != "post-delete check found deleted rows still in source: orders=1" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stats == nil || stats.RowsDeleted != 3 || stats.ResidualRows["orders"] != 1 || len(stats.ResidualRows) != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestDelete_PostDeleteCheckOffByDefault(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer func() { _ = db.Close() }()
+
+	dp, _ := NewDeletePhase(db, createDeleteTestGraph(), 500, logger.NewDefault())
+	mock.ExpectExec("DELETE FROM `users` WHERE `id` IN").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := dp.Delete(context.Background(), &RecordSet{Records: map[string][]interface{}{"users": {1}}}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package archiver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/verifier"
+)
+
+// TableResult is one table's totals over a batch or a run.
+type TableResult struct {
+	RowsCopied   int64 `json:"rows_copied"`
+	RowsSkipped  int64 `json:"rows_skipped"`
+	RowsDeleted  int64 `json:"rows_deleted"`
+	RowsVerified int64 `json:"rows_verified"`
+	// VerifyFailures counts the batches in which the table failed
+	// verification.
+	VerifyFailures int `json:"verify_failures"`
+	// NullForeignKeys is the table's row count with a NULL foreign key when
+	// processing.count_null_foreign_keys is set; discovery never reaches
+	// those rows.
+	NullForeignKeys int64 `json:"null_foreign_keys,omitempty"`
+}
+
+// table returns the batch's totals for table, creating them on first use.
+func (s *BatchStats) table(name string) *TableResult {
+	if s.Tables == nil {
+		s.Tables = make(map[string]*TableResult)
+	}
+	t, ok := s.Tables[name]
+	if !ok {
+		t = &TableResult{}
+		s.Tables[name] = t
+	}
+	return t
+}
+
+func (s *BatchStats) addVerifyResults(results []verifier.TableVerifyResult) {
+	for _, r := range results {
+		t := s.table(r.Table)
+		t.RowsVerified += r.SourceCount
+		if !r.Match {
+			t.VerifyFailures++
+		}
+	}
+}
+
+func (s *BatchStats) addDeleted(rowsPerTable map[string]int64) {
+	for table, n := range rowsPerTable {
+		s.table(table).RowsDeleted += n
+	}
+}
+
+// mergeTableResults adds src's per-table totals into *dst.
+func mergeTableResults(dst *map[string]*TableResult, src map[string]*TableResult) {
+	if len(src) == 0 {
+		return
+	}
+	if *dst == nil {
+		*dst = make(map[string]*TableResult, len(src))
+	}
+	for table, r := range src {
+		t, ok := (*dst)[table]
+		if !ok {
+			t = &TableResult{}
+			(*dst)[table] = t
+		}
+		t.RowsCopied += r.RowsCopied
+		t.RowsSkipped += r.RowsSkipped
+		t.RowsDeleted += r.RowsDeleted
+		t.RowsVerified += r.RowsVerified
+		t.VerifyFailures += r.VerifyFailures
+		t.NullForeignKeys += r.NullForeignKeys
+	}
+}
+
+// ArchiveReport is the JSON form of an ArchiveResult written to
+// processing.result_file for dashboards and post-run automation.
+type ArchiveReport struct {
+	Job                string                  `json:"job"`
+	Success            bool                    `json:"success"`
+	StartedAt          time.Time               `json:"started_at"`
+	CompletedAt        time.Time               `json:"completed_at"`
+	DurationSeconds    float64                 `json:"duration_seconds"`
+	VerificationMethod string                  `json:"verification_method"`
+	RecordsCopied      int64                   `json:"records_copied"`
+	RecordsSkipped     int64                   `json:"records_skipped"`
+	RecordsDeleted     int64                   `json:"records_deleted"`
+	TablesVerified     int                     `json:"tables_verified"`
+	RecordsVerified    int64                   `json:"records_verified"`
+	Tables             map[string]*TableResult `json:"tables"`
+	Errors             []ReportError           `json:"errors"`
+}
+
+// ReportError is one error of a run. Table and Phase are set for a
+// continue_on_error table failure (TableError).
+type ReportError struct {
+	Table   string `json:"table,omitempty"`
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewArchiveReport converts result into its report form.
+func NewArchiveReport(result *ArchiveResult) ArchiveReport {
+	report := ArchiveReport{
+		Job:                result.JobName,
+		Success:            result.Success,
+		StartedAt:          result.StartedAt,
+		CompletedAt:        result.CompletedAt,
+		DurationSeconds:    result.Duration.Seconds(),
+		VerificationMethod: result.VerificationMethod,
+		RecordsCopied:      result.RecordsCopied,
+		RecordsSkipped:     result.RecordsSkipped,
+		RecordsDeleted:     result.RecordsDeleted,
+		TablesVerified:     result.TablesVerified,
+		RecordsVerified:    result.RecordsVerified,
+		Tables:             result.Tables,
+		Errors:             make([]ReportError, 0, len(result.Errors)),
+	}
+	if report.Tables == nil {
+		report.Tables = map[string]*TableResult{}
+	}
+	for _, err := range result.Errors {
+		entry := ReportError{Message: err.Error()}
+		var tableErr *TableError
+		if errors.As(err, &tableErr) {
+			entry.Table, entry.Phase = tableErr.Table, tableErr.Phase
+		}
+		report.Errors = append(report.Errors, entry)
+	}
+	return report
+}
+
+// WriteArchiveReport writes result as indented JSON to path. The report is
+// written to a temporary file in the same directory and renamed over path,
+// so a reader never sees a partial file.
+func WriteArchiveReport(path string, result *ArchiveResult) error {
+	data, err := json.MarshalIndent(NewArchiveReport(result), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result report: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create result report %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write result report %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write result report %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write result report %s: %w", path, err)
+	}
+	return nil
+}
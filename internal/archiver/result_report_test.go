@@ -0,0 +1,85 @@
+package archiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArchiveReport_RoundTrip(t *testing.T) {
+	started := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	result := &ArchiveResult{
+		JobName:            "archive_old_orders",
+		StartedAt:          started,
+		CompletedAt:        started.Add(90 * time.Second),
+		Duration:           90 * time.Second,
+		RecordsCopied:      12,
+		RecordsSkipped:     1,
+		RecordsDeleted:     9,
+		TablesVerified:     2,
+		RecordsVerified:    12,
+		VerificationMethod: "count",
+		Tables: map[string]*TableResult{
+			"orders":      {RowsCopied: 3, RowsDeleted: 3, RowsVerified: 3},
+			"order_items": {RowsCopied: 9, RowsSkipped: 1, RowsDeleted: 6, RowsVerified: 9, VerifyFailures: 1},
+		},
+		Errors: []error{
+			&TableError{Table: "order_items", Phase: "verify", Err: fmt.Errorf("count mismatch")},
+			fmt.Errorf("runtime budget exceeded"),
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	require.NoError(t, WriteArchiveReport(path, result))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got ArchiveReport
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	assert.Equal(t, "archive_old_orders", got.Job)
+	assert.False(t, got.Success)
+	assert.True(t, started.Equal(got.StartedAt))
+	assert.True(t, started.Add(90*time.Second).Equal(got.CompletedAt))
+	assert.Equal(t, 90.0, got.DurationSeconds)
+	assert.Equal(t, "count", got.VerificationMethod)
+	assert.Equal(t, int64(12), got.RecordsCopied)
+	assert.Equal(t, int64(1), got.RecordsSkipped)
+	assert.Equal(t, int64(9), got.RecordsDeleted)
+	assert.Equal(t, 2, got.TablesVerified)
+	assert.Equal(t, int64(12), got.RecordsVerified)
+	assert.Equal(t, result.Tables, got.Tables)
+	assert.Equal(t, []ReportError{
+		{Table: "order_items", Phase: "verify", Message: "verify failed for table order_items: count mismatch"},
+		{Message: "runtime budget exceeded"},
+	}, got.Errors)
+
+	// A later run replaces the file.
+	require.NoError(t, WriteArchiveReport(path, &ArchiveResult{JobName: "archive_old_orders", Success: true}))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	got = ArchiveReport{}
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.True(t, got.Success)
+	assert.Empty(t, got.Tables)
+	assert.Empty(t, got.Errors)
+}
+
+func TestMergeTableResults(t *testing.T) {
+	var total map[string]*TableResult
+	mergeTableResults(&total, map[string]*TableResult{"orders": {RowsCopied: 2, RowsDeleted: 2}})
+	mergeTableResults(&total, map[string]*TableResult{
+		"orders": {RowsCopied: 3, RowsDeleted: 1, VerifyFailures: 1},
+		"notes":  {RowsCopied: 1},
+	})
+	assert.Equal(t, map[string]*TableResult{
+		"orders": {RowsCopied: 5, RowsDeleted: 3, VerifyFailures: 1},
+		"notes":  {RowsCopied: 1},
+	}, total)
+}
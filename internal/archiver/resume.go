@@ -11,6 +11,7 @@ import (
 
 	"github.com/dbsmedya/goarchive/internal/logger"
 	"github.com/dbsmedya/goarchive/internal/sqlutil"
+	"github.com/dbsmedya/goarchive/internal/types"
 )
 
 // JobStatus represents the state of an archive job.
@@ -89,6 +90,8 @@ type ResumeManager struct {
 	jobTable  string // quoted qualified name, e.g. `goarchive`.`archiver_job`
 	jobID     int64  // resolved in GetOrCreateJobWithType
 	logTable  string // quoted qualified name, e.g. `goarchive`.`archiver_job_log_42`; empty until resolved
+
+	failedRecordsReady bool // goarchive_failed_records created by this manager
 }
 
 // NewResumeManager creates a resume manager. jobSchema is the schema holding
@@ -665,37 +668,7 @@ func (r *ResumeManager) ShouldResume(ctx context.Context, jobName string) (bool,
 	return false, nil
 }
 
+// formatPK encodes a PK for the log and job tables (see types.EncodePK).
 func formatPK(pk interface{}) (string, error) {
-	switch v := pk.(type) {
-	case nil:
-		return "", fmt.Errorf("pk is nil")
-	case string:
-		return v, nil
-	case []byte:
-		return string(v), nil
-	case int:
-		return strconv.Itoa(v), nil
-	case int8:
-		return strconv.FormatInt(int64(v), 10), nil
-	case int16:
-		return strconv.FormatInt(int64(v), 10), nil
-	case int32:
-		return strconv.FormatInt(int64(v), 10), nil
-	case int64:
-		return strconv.FormatInt(v, 10), nil
-	case uint:
-		return strconv.FormatUint(uint64(v), 10), nil
-	case uint8:
-		return strconv.FormatUint(uint64(v), 10), nil
-	case uint16:
-		return strconv.FormatUint(uint64(v), 10), nil
-	case uint32:
-		return strconv.FormatUint(uint64(v), 10), nil
-	case uint64:
-		return strconv.FormatUint(v, 10), nil
-	case fmt.Stringer:
-		return v.String(), nil
-	default:
-		return "", fmt.Errorf("unsupported pk type: %T", pk)
-	}
+	return types.EncodePK(pk)
 }
@@ -0,0 +1,143 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/database"
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// RunnerJob is one job a Runner executes. Run does the whole job, including
+// taking its advisory lock (ArchiveOrchestrator.Execute locks on the job
+// name), and returns its result.
+type RunnerJob struct {
+	Name string
+	Run  func(ctx context.Context) (*ArchiveResult, error)
+}
+
+// JobOutcome is what one job of a Runner run came to.
+type JobOutcome struct {
+	Job    string
+	Result *ArchiveResult // nil when Run failed before producing one, or Skipped
+	Err    error
+	// Skipped is set for a job that never started because an earlier job
+	// failed and the runner does not continue on error.
+	Skipped bool
+}
+
+// Failed reports whether the job errored or finished unsuccessfully.
+func (o JobOutcome) Failed() bool {
+	return o.Err != nil || (o.Result != nil && !o.Result.Success)
+}
+
+// Runner executes several jobs, one after another by default or up to
+// SetParallelism at a time, and collects one JobOutcome per job. By default
+// the first failed job stops the jobs that have not started yet (jobs
+// already running finish); SetContinueOnError runs every job regardless.
+type Runner struct {
+	parallelism     int
+	continueOnError bool
+	logger          *logger.Logger
+}
+
+// NewRunner creates a sequential runner that stops at the first failure.
+func NewRunner(log *logger.Logger) *Runner {
+	if log == nil {
+		log = logger.NewDefault()
+	}
+	return &Runner{parallelism: 1, logger: log}
+}
+
+// SetParallelism sets how many jobs run at once. Values < 1 are ignored.
+func (r *Runner) SetParallelism(n int) {
+	if n >= 1 {
+		r.parallelism = n
+	}
+}
+
+// SetContinueOnError runs the remaining jobs after a job fails instead of
+// skipping them.
+func (r *Runner) SetContinueOnError(enabled bool) {
+	r.continueOnError = enabled
+}
+
+// Run executes jobs and returns their outcomes in the order of jobs. Each
+// job takes the advisory lock of its own name, so a name listed twice is
+// refused up front rather than left to contend with itself.
+func (r *Runner) Run(ctx context.Context, jobs []RunnerJob) ([]JobOutcome, error) {
+	seen := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		if seen[job.Name] {
+			return nil, fmt.Errorf("job %q is listed more than once", job.Name)
+		}
+		seen[job.Name] = true
+	}
+
+	outcomes := make([]JobOutcome, len(jobs))
+	var (
+		mu      sync.Mutex
+		stopped bool
+		wg      sync.WaitGroup
+	)
+	slots := make(chan struct{}, r.parallelism)
+	for i, job := range jobs {
+		slots <- struct{}{}
+		mu.Lock()
+		skip := stopped || ctx.Err() != nil
+		mu.Unlock()
+		if skip {
+			<-slots
+			outcomes[i] = JobOutcome{Job: job.Name, Skipped: true, Err: ctx.Err()}
+			r.logger.Warnw("Skipping job", "job", job.Name)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, job RunnerJob) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.logger.Infow("Starting job", "job", job.Name)
+			result, err := job.Run(ctx)
+			outcome := JobOutcome{Job: job.Name, Result: result, Err: err}
+			outcomes[i] = outcome
+			if outcome.Failed() {
+				r.logger.Errorw("Job failed", "job", job.Name, "error", err)
+				if !r.continueOnError {
+					mu.Lock()
+					stopped = true
+					mu.Unlock()
+				}
+				return
+			}
+			r.logger.Infow("Job finished", "job", job.Name)
+		}(i, job)
+	}
+	wg.Wait()
+	return outcomes, nil
+}
+
+// ArchiveRunnerJob returns the RunnerJob that archives named: it creates,
+// initializes and executes an ArchiveOrchestrator on dbManager. configure,
+// when non-nil, adjusts the orchestrator (logger, stop channel, force)
+// before it runs.
+func ArchiveRunnerJob(cfg *config.Config, named config.NamedJob, dbManager *database.Manager, configure func(*ArchiveOrchestrator)) RunnerJob {
+	return RunnerJob{
+		Name: named.Name,
+		Run: func(ctx context.Context) (*ArchiveResult, error) {
+			orch, err := NewOrchestrator(cfg, named.Name, named.Job, dbManager)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create orchestrator: %w", err)
+			}
+			if configure != nil {
+				configure(orch)
+			}
+			if err := orch.Initialize(); err != nil {
+				return nil, fmt.Errorf("orchestrator initialization failed: %w", err)
+			}
+			return orch.Execute(ctx, nil)
+		},
+	}
+}
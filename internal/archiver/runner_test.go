@@ -0,0 +1,120 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/lock"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_RunsJobsInOrderWithDistinctLocks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	var locks []string
+	job := func(name string, copied int64) RunnerJob {
+		lockName := lock.GenerateJobLockName(name)
+		mock.ExpectQuery("SELECT GET_LOCK\\(\\?, \\?\\)").WithArgs(lockName, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK(?, ?)"}).AddRow(1))
+		mock.ExpectQuery("SELECT CONNECTION_ID\\(\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"CONNECTION_ID()"}).AddRow(42))
+		mock.ExpectQuery("SELECT RELEASE_LOCK\\(\\?\\)").WithArgs(lockName).
+			WillReturnRows(sqlmock.NewRows([]string{"RELEASE_LOCK(?)"}).AddRow(1))
+		return RunnerJob{Name: name, Run: func(ctx context.Context) (*ArchiveResult, error) {
+			jobLock := lock.NewJobLock(db, name)
+			acquired, err := jobLock.TryAcquire(ctx)
+			if err != nil || !acquired {
+				return nil, fmt.Errorf("lock not acquired: %v", err)
+			}
+			locks = append(locks, lockName)
+			defer func() { _, _ = jobLock.ReleaseLock(ctx) }()
+			return &ArchiveResult{JobName: name, RecordsCopied: copied, Success: true}, nil
+		}}
+	}
+
+	outcomes, err := NewRunner(logger.NewDefault()).Run(context.Background(),
+		[]RunnerJob{job("archive_orders", 10), job("archive_sessions", 20)})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, []string{"goarchive:job:archive_orders", "goarchive:job:archive_sessions"}, locks)
+	require.Len(t, outcomes, 2)
+	assert.Equal(t, "archive_orders", outcomes[0].Job)
+	assert.Equal(t, int64(10), outcomes[0].Result.RecordsCopied)
+	assert.Equal(t, "archive_sessions", outcomes[1].Job)
+	assert.Equal(t, int64(20), outcomes[1].Result.RecordsCopied)
+	for _, o := range outcomes {
+		assert.False(t, o.Failed())
+		assert.False(t, o.Skipped)
+	}
+}
+
+func TestRunner_FailureStopsRemainingJobs(t *testing.T) {
+	var ran []string
+	job := func(name string, err error) RunnerJob {
+		return RunnerJob{Name: name, Run: func(context.Context) (*ArchiveResult, error) {
+			ran = append(ran, name)
+			return &ArchiveResult{JobName: name, Success: err == nil}, err
+		}}
+	}
+	jobs := []RunnerJob{job("a", nil), job("b", fmt.Errorf("copy failed")), job("c", nil)}
+
+	outcomes, err := NewRunner(nil).Run(context.Background(), jobs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ran)
+	assert.False(t, outcomes[0].Failed())
+	assert.True(t, outcomes[1].Failed())
+	assert.True(t, outcomes[2].Skipped)
+
+	ran = nil
+	r := NewRunner(nil)
+	r.SetContinueOnError(true)
+	outcomes, err = r.Run(context.Background(), jobs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, ran)
+	assert.False(t, outcomes[2].Skipped)
+	assert.False(t, outcomes[2].Failed())
+}
+
+func TestRunner_Parallelism(t *testing.T) {
+	var running, peak int32
+	var mu sync.Mutex
+	job := func(name string) RunnerJob {
+		return RunnerJob{Name: name, Run: func(context.Context) (*ArchiveResult, error) {
+			n := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			if n > peak {
+				peak = n
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return &ArchiveResult{JobName: name, Success: true}, nil
+		}}
+	}
+
+	r := NewRunner(nil)
+	r.SetParallelism(2)
+	outcomes, err := r.Run(context.Background(), []RunnerJob{job("a"), job("b"), job("c"), job("d")})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), peak)
+	for i, name := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, name, outcomes[i].Job)
+		assert.Equal(t, name, outcomes[i].Result.JobName)
+	}
+}
+
+func TestRunner_RejectsDuplicateJobs(t *testing.T) {
+	noop := func(context.Context) (*ArchiveResult, error) { return &ArchiveResult{Success: true}, nil }
+	_, err := NewRunner(nil).Run(context.Background(), []RunnerJob{{Name: "a", Run: noop}, {Name: "a", Run: noop}})
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package archiver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// Snapshot is a point-in-time view of a running archive job, for debugging a
+// job that looks stuck (see ArchiveOrchestrator.Snapshot).
+type Snapshot struct {
+	JobName   string
+	StartedAt time.Time // zero until Execute starts
+	// Phase is the batch phase in progress (PhaseDiscovery, PhaseCopy,
+	// PhaseVerify or PhaseDelete); empty between phases.
+	Phase          string
+	PhaseStartedAt time.Time
+	// Table is the table the current copy, verify or delete phase is working
+	// on; empty during discovery and between phases.
+	Table string
+	// Batch is the number of the batch in progress (or last run) in this
+	// execution's main loop; BatchesDone counts those that completed.
+	Batch       int
+	BatchesDone int
+	// BatchRoots is how many root PKs the batch in progress holds.
+	BatchRoots       int
+	RootsProcessed   int64
+	RecordsCopied    int64
+	RecordsVerified  int64
+	RecordsDeleted   int64
+	LastCheckpointPK interface{} // nil until the checkpoint first advances
+}
+
+// runProgress is the mutable state behind Snapshot. The orchestrator updates
+// it from the run goroutine; Snapshot may read it from any goroutine.
+type runProgress struct {
+	mu   sync.Mutex
+	snap Snapshot
+}
+
+func (p *runProgress) update(fn func(s *Snapshot)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(&p.snap)
+}
+
+func (p *runProgress) snapshot() Snapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.snap
+}
+
+// enterPhase records that phase started on a batch of roots root PKs.
+func (p *runProgress) enterPhase(phase string, roots int) {
+	p.update(func(s *Snapshot) {
+		s.Phase = phase
+		s.PhaseStartedAt = time.Now()
+		s.Table = ""
+		s.BatchRoots = roots
+	})
+}
+
+// leavePhase clears the current phase and table.
+func (p *runProgress) leavePhase() {
+	p.update(func(s *Snapshot) {
+		s.Phase = ""
+		s.PhaseStartedAt = time.Time{}
+		s.Table = ""
+	})
+}
+
+// setTable is the phases' table observer.
+func (p *runProgress) setTable(table string) {
+	p.update(func(s *Snapshot) { s.Table = table })
+}
+
+// rootsDone adds a processed group of roots and its record counts.
+func (p *runProgress) rootsDone(stats *BatchStats) {
+	p.update(func(s *Snapshot) {
+		s.RootsProcessed += int64(stats.RootsProcessed)
+		s.RecordsCopied += stats.RecordsCopied
+		s.RecordsVerified += stats.RecordsVerified
+		s.RecordsDeleted += stats.RecordsDeleted
+	})
+}
+
+// checkpointed records that the checkpoint advanced to pk.
+func (p *runProgress) checkpointed(pk interface{}) {
+	p.update(func(s *Snapshot) { s.LastCheckpointPK = pk })
+}
+
+// logSnapshot writes s to log at info level, without stopping the job.
+func logSnapshot(log *logger.Logger, s Snapshot) {
+	log.Infow("Job state snapshot",
+		"job", s.JobName,
+		"started_at", s.StartedAt,
+		"phase", s.Phase,
+		"phase_started_at", s.PhaseStartedAt,
+		logger.FieldTable, s.Table,
+		logger.FieldBatch, s.Batch,
+		"batches_done", s.BatchesDone,
+		"batch_roots", s.BatchRoots,
+		"roots_processed", s.RootsProcessed,
+		"records_copied", s.RecordsCopied,
+		"records_verified", s.RecordsVerified,
+		"records_deleted", s.RecordsDeleted,
+		"last_checkpoint_pk", s.LastCheckpointPK,
+	)
+}
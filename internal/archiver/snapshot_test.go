@@ -0,0 +1,83 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotHooks takes an orchestrator Snapshot in every AfterPhase, while
+// the phase is still the current one.
+type snapshotHooks struct {
+	NoopHooks
+	o     *ArchiveOrchestrator
+	snaps map[string]Snapshot
+}
+
+func (h *snapshotHooks) AfterPhase(_ context.Context, phase string, _ PhaseInfo) error {
+	h.snaps[phase] = h.o.Snapshot()
+	return nil
+}
+
+func TestSnapshot_ReflectsProgressMidRun(t *testing.T) {
+	hooks := &snapshotHooks{snaps: make(map[string]Snapshot)}
+	p := newHookTestPhases(t, hooks)
+	hooks.o = p.o
+	p.copyPhase.SetTableObserver(p.o.progress.setTable)
+	p.dataVerifier.SetTableObserver(p.o.progress.setTable)
+	p.deletePhase.SetTableObserver(p.o.progress.setTable)
+
+	for _, root := range []struct{ id, order int64 }{{1, 10}, {2, 20}} {
+		expectGatedRootCopy(p.sourceMock, p.destMock, root.id, root.order, 1)
+		p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+			WithArgs(LogStatusCopied, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		p.sourceMock.ExpectExec("DELETE FROM `orders`").WithArgs(root.order).WillReturnResult(sqlmock.NewResult(0, 1))
+		p.sourceMock.ExpectExec("DELETE FROM `customers`").WithArgs(root.id).WillReturnResult(sqlmock.NewResult(0, 1))
+		p.archMock.ExpectBegin()
+		p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+			WithArgs(LogStatusCompleted, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		p.archMock.ExpectExec("UPDATE .*archiver_job.* SET last_processed_root_pk_id").
+			WithArgs(sqlmock.AnyArg(), "job1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		p.archMock.ExpectCommit()
+	}
+
+	_, err := p.o.processBatch(context.Background(), []interface{}{int64(1)}, batchFull, true, nil,
+		p.discovery, p.copyPhase, p.dataVerifier, p.deletePhase, p.fetcher, p.resumeMgr, nil)
+	require.NoError(t, err)
+
+	copySnap := hooks.snaps[PhaseCopy]
+	require.Equal(t, "job1", copySnap.JobName)
+	require.Equal(t, PhaseCopy, copySnap.Phase)
+	require.Equal(t, "orders", copySnap.Table, "table observer tracks the last table copied")
+	require.Equal(t, 1, copySnap.BatchRoots)
+	require.False(t, copySnap.PhaseStartedAt.IsZero())
+	require.Zero(t, copySnap.RootsProcessed)
+	require.Nil(t, copySnap.LastCheckpointPK)
+
+	_, err = p.o.processBatch(context.Background(), []interface{}{int64(2)}, batchFull, true, nil,
+		p.discovery, p.copyPhase, p.dataVerifier, p.deletePhase, p.fetcher, p.resumeMgr, nil)
+	require.NoError(t, err)
+
+	// Mid-run during root 2: root 1's progress is visible.
+	deleteSnap := hooks.snaps[PhaseDelete]
+	require.Equal(t, PhaseDelete, deleteSnap.Phase)
+	require.Equal(t, "customers", deleteSnap.Table, "root table is deleted last")
+	require.Equal(t, int64(1), deleteSnap.RootsProcessed)
+	require.Equal(t, int64(2), deleteSnap.RecordsCopied)
+	require.Equal(t, int64(2), deleteSnap.RecordsDeleted)
+	require.Equal(t, int64(1), deleteSnap.LastCheckpointPK)
+
+	// Between batches no phase is current and both roots are counted.
+	final := p.o.Snapshot()
+	require.Empty(t, final.Phase)
+	require.Empty(t, final.Table)
+	require.Equal(t, int64(2), final.RootsProcessed)
+	require.Equal(t, int64(4), final.RecordsDeleted)
+	require.Equal(t, int64(2), final.LastCheckpointPK)
+	p.expectationsMet(t)
+}
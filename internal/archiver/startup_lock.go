@@ -3,6 +3,7 @@ package archiver
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -17,9 +18,10 @@ const heartbeatFailureLimit = 3
 
 // finalJobStatus picks the status to persist on orchestrator exit.
 // Non-nil execErr → Failed (visible in archiver_job for post-mortem).
-// Nil execErr → Idle (clean completion).
+// Nil execErr → Idle (clean completion). An exhausted runtime budget is a
+// clean stop at a batch boundary, so it also leaves the job Idle.
 func finalJobStatus(execErr error) JobStatus {
-	if execErr != nil {
+	if execErr != nil && !errors.Is(execErr, ErrRuntimeBudgetExceeded) {
 		return JobStatusFailed
 	}
 	return JobStatusIdle
@@ -86,7 +88,22 @@ func beginJobStartup(
 		return nil, fmt.Errorf("failed to initialize resume tables: %w", err)
 	}
 
-	rootLock := lock.NewRootTableLock(destDB, rootTable)
+	// Both advisory locks are taken on one pinned session rather than on
+	// whichever pooled connection is free, so the job lock kept for the run
+	// is acquired, kept alive and released on the same MySQL connection.
+	lockConn, err := destDB.Conn(ctx)
+	if err != nil {
+		cancelRun()
+		return nil, fmt.Errorf("failed to get advisory lock connection: %w", err)
+	}
+	lockConnOwned := true
+	defer func() {
+		if lockConnOwned {
+			_ = lockConn.Close()
+		}
+	}()
+
+	rootLock := lock.NewConnAdvisoryLock(lockConn, lock.GenerateRootTableLockName(rootTable))
 	rootHeld, err := rootLock.AcquireLock(ctx, lock.TimeoutMedium)
 	if err != nil {
 		cancelRun()
@@ -114,7 +131,7 @@ func beginJobStartup(
 		return nil, err
 	}
 
-	jobLock := lock.NewJobLock(destDB, jobName)
+	jobLock := lock.NewConnAdvisoryLock(lockConn, lock.GenerateJobLockName(jobName))
 	acquiredJob, err := jobLock.TryAcquire(ctx)
 	if err != nil {
 		cancelRun()
@@ -238,7 +255,11 @@ func beginJobStartup(
 		if jobLockHeld {
 			_, _ = jobLock.ReleaseLock(context.Background())
 		}
+		if err := lockConn.Close(); err != nil {
+			log.Warnw("advisory lock connection close error", "error", err)
+		}
 	}
+	lockConnOwned = false
 
 	startup.resumeMgr = resumeMgr
 	startup.jobState = jobState
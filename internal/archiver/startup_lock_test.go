@@ -2,6 +2,7 @@ package archiver
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -14,6 +15,7 @@ func TestFinalJobStatus(t *testing.T) {
 		{"nil error -> idle", nil, JobStatusIdle},
 		{"non-nil error -> failed", errors.New("boom"), JobStatusFailed},
 		{"wrapped error -> failed", &customErr{}, JobStatusFailed},
+		{"runtime budget -> idle", fmt.Errorf("stop: %w", ErrRuntimeBudgetExceeded), JobStatusIdle},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
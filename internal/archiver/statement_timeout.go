@@ -0,0 +1,69 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mysql "github.com/go-sql-driver/mysql"
+)
+
+// ErrStatementTimeout matches (with errors.Is) every StatementTimeoutError.
+var ErrStatementTimeout = errors.New("statement timeout exceeded")
+
+// mysqlErrQueryTimeout is ER_QUERY_TIMEOUT, returned for a SELECT that
+// outlives its MAX_EXECUTION_TIME hint.
+const mysqlErrQueryTimeout = 3024
+
+// StatementTimeoutError is returned when a single statement outlives
+// processing.statement_timeout: a SELECT aborted by its MAX_EXECUTION_TIME
+// hint, or an INSERT/DELETE whose statement deadline expired. Like
+// PhaseTimeoutError it does not wrap context.DeadlineExceeded, since the job
+// was not interrupted. The failing batch keeps its rows in the source, so the
+// next run (or resume) processes them again.
+type StatementTimeoutError struct {
+	Timeout time.Duration
+	Err     error // the statement's own error, kept for its message
+}
+
+func (e *StatementTimeoutError) Error() string {
+	return fmt.Sprintf("statement exceeded its %s statement_timeout: %v", e.Timeout, e.Err)
+}
+
+// Is reports whether target is ErrStatementTimeout.
+func (e *StatementTimeoutError) Is(target error) bool {
+	return target == ErrStatementTimeout
+}
+
+// withStatementTimeout runs one write statement fn with a child of ctx that
+// expires after timeout (ctx itself when timeout is 0). An error from fn after
+// that deadline, while ctx is still live, is returned as a
+// *StatementTimeoutError. A canceled statement leaves its connection unusable,
+// so the surrounding transaction fails and rolls back.
+func withStatementTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	stmtCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := fn(stmtCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stmtCtx.Err(), context.DeadlineExceeded) {
+		return &StatementTimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
+
+// asStatementTimeout returns err as a *StatementTimeoutError when it stems
+// from a SELECT the server aborted at its MAX_EXECUTION_TIME hint, and err
+// unchanged otherwise.
+func asStatementTimeout(err error, timeout time.Duration) error {
+	if err == nil || timeout <= 0 || errors.Is(err, ErrStatementTimeout) {
+		return err
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrQueryTimeout {
+		return &StatementTimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
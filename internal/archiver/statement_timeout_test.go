@@ -0,0 +1,106 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysql "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+// stmtTimeoutHint is the MAX_EXECUTION_TIME prefix, as a regexp, for a 2s statement_timeout.
+const stmtTimeoutHint = "SELECT /\\*\\+ MAX_EXECUTION_TIME\\(2000\\) \\*/ "
+
+func setStatementTimeout(p *hookTestPhases, timeout time.Duration) {
+	p.o.processingCfg.StatementTimeout = timeout
+	p.discovery.SetStatementTimeout(timeout)
+	p.copyPhase.SetStatementTimeout(timeout)
+	p.dataVerifier.SetStatementTimeout(timeout)
+	p.deletePhase.SetStatementTimeout(timeout)
+}
+
+func TestProcessBatch_StatementTimeoutHintsSelects(t *testing.T) {
+	p := newHookTestPhases(t, nil)
+	setStatementTimeout(p, 2*time.Second)
+
+	// Every discovery, copy and verify SELECT carries the stmtTimeoutHint; the writes
+	// (INSERT, DELETE) are bounded by a context deadline instead.
+	p.sourceMock.ExpectQuery(stmtTimeoutHint + "`id` FROM `orders` WHERE `customer_id` IN \\(\\?\\)$").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)))
+	p.destMock.ExpectBegin()
+	p.destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	p.sourceMock.ExpectQuery(stmtTimeoutHint + "\\* FROM `customers` WHERE `id` IN").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "c"))
+	p.destMock.ExpectExec("^INSERT IGNORE INTO `customers`").WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectQuery(stmtTimeoutHint + "\\* FROM `orders` WHERE `id` IN").
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id"}).AddRow(int64(10), int64(1)))
+	p.destMock.ExpectExec("^INSERT IGNORE INTO `orders`").WillReturnResult(sqlmock.NewResult(0, 1))
+	p.destMock.ExpectCommit()
+	for _, c := range []struct {
+		table string
+		pk    int64
+	}{{"customers", 1}, {"orders", 10}} {
+		p.sourceMock.ExpectQuery(stmtTimeoutHint + "COUNT\\(\\*\\) FROM `" + c.table + "`").
+			WithArgs(c.pk).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		p.destMock.ExpectQuery(stmtTimeoutHint + "COUNT\\(\\*\\) FROM `" + c.table + "`").
+			WithArgs(c.pk).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	}
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCopied, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("^DELETE FROM `orders`").WithArgs(int64(10)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.sourceMock.ExpectExec("^DELETE FROM `customers`").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectBegin()
+	p.archMock.ExpectExec("UPDATE .*archiver_job_log_\\d+. SET log_status").
+		WithArgs(LogStatusCompleted, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	p.archMock.ExpectCommit()
+
+	require.NoError(t, p.processBatch(1))
+	p.expectationsMet(t)
+}
+
+func TestProcessBatch_SelectStatementTimeoutFailsBatch(t *testing.T) {
+	p := newHookTestPhases(t, nil)
+	setStatementTimeout(p, 2*time.Second)
+
+	p.sourceMock.ExpectQuery(stmtTimeoutHint + "`id` FROM `orders`").
+		WithArgs(int64(1)).
+		WillReturnError(&mysql.MySQLError{Number: 3024,
+			Message: "Query execution was interrupted, maximum statement execution time exceeded"})
+
+	err := p.processBatch(1)
+	require.ErrorIs(t, err, ErrStatementTimeout)
+	require.Contains(t, err.Error(), "statement exceeded its 2s statement_timeout")
+	require.NoError(t, p.sourceMock.ExpectationsWereMet())
+}
+
+func TestExecuteDelete_StatementTimeoutFailsStatement(t *testing.T) {
+	p := newHookTestPhases(t, nil)
+	p.deletePhase.SetStatementTimeout(20 * time.Millisecond)
+
+	p.sourceMock.ExpectExec("^DELETE FROM `orders`").WithArgs(int64(10)).
+		WillDelayFor(time.Second).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := p.deletePhase.executeDelete(context.Background(), p.deletePhase.db, "orders", "id", []interface{}{int64(10)})
+	require.ErrorIs(t, err, ErrStatementTimeout)
+	require.NotErrorIs(t, err, context.DeadlineExceeded, "a statement timeout is a failure, not a cancellation")
+	var timeoutErr *StatementTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, 20*time.Millisecond, timeoutErr.Timeout)
+}
+
+func TestWithStatementTimeout_ParentCancellationIsNotATimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := withStatementTimeout(ctx, time.Hour, func(ctx context.Context) error { return ctx.Err() })
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, ErrStatementTimeout)
+}
@@ -29,6 +29,28 @@ func shouldUseStrictInsert(method string, skipVerification, destHasUniqueIndex b
 	return method == "count" || skipVerification || destHasUniqueIndex
 }
 
+// resolveCopyMode maps processing.copy_mode to the copy phase's INSERT form.
+// "insert" is always strict. "upsert" (INSERT ... ON DUPLICATE KEY UPDATE)
+// leaves each destination row equal to its source row whatever was there
+// before, so it is safe under any verification method — except with a
+// destination secondary UNIQUE index, where a collision on that index would
+// overwrite a different row; that combination is refused. The default
+// ("insert-ignore" or empty) keeps the shouldUseStrictInsert upgrade.
+func resolveCopyMode(copyMode, method string, skipVerification bool, destUniqueIdx []string) (strict, upsert bool, err error) {
+	switch copyMode {
+	case "insert":
+		return true, false, nil
+	case "upsert":
+		if len(destUniqueIdx) > 0 {
+			return false, false, fmt.Errorf("copy_mode upsert cannot be used with destination secondary unique indexes (%s): "+
+				"a collision on one would overwrite a different destination row", strings.Join(destUniqueIdx, ", "))
+		}
+		return false, true, nil
+	default:
+		return shouldUseStrictInsert(method, skipVerification, len(destUniqueIdx) > 0), false, nil
+	}
+}
+
 // destinationSecondaryUniqueIndexes returns "table.index" descriptors for every
 // participating destination table that carries a non-PRIMARY UNIQUE index.
 // Their presence forces strict insert (see shouldUseStrictInsert) because
@@ -38,6 +38,39 @@ func TestShouldUseStrictInsert(t *testing.T) {
 	}
 }
 
+func TestResolveCopyMode(t *testing.T) {
+	uniq := []string{"orders.uk_ref"}
+	tests := []struct {
+		name       string
+		mode       string
+		method     string
+		skipVerify bool
+		uniqueIdx  []string
+		wantStrict bool
+		wantUpsert bool
+		wantErr    bool
+	}{
+		{"default keeps INSERT IGNORE", "", "sha256", false, nil, false, false, false},
+		{"default upgraded under count", "insert-ignore", "count", false, nil, true, false, false},
+		{"insert always strict", "insert", "sha256", false, nil, true, false, false},
+		{"upsert under count", "upsert", "count", false, nil, false, true, false},
+		{"upsert with skip-verify", "upsert", "sha256", true, nil, false, true, false},
+		{"upsert refused with unique index", "upsert", "sha256", false, uniq, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strict, upsert, err := resolveCopyMode(tt.mode, tt.method, tt.skipVerify, tt.uniqueIdx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveCopyMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strict != tt.wantStrict || upsert != tt.wantUpsert {
+				t.Fatalf("resolveCopyMode() = (strict %v, upsert %v), want (%v, %v)",
+					strict, upsert, tt.wantStrict, tt.wantUpsert)
+			}
+		})
+	}
+}
+
 func TestDestinationSecondaryUniqueIndexes_Found(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -0,0 +1,223 @@
+package archiver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/verifier"
+)
+
+// TransformFunc maps a source column value (as returned by the driver, usually
+// []byte, int64, float64, time.Time, or nil) to the value written to the
+// archive.
+type TransformFunc func(interface{}) interface{}
+
+// builtinTransforms are the transforms selectable by name from job config.
+var builtinTransforms = map[string]TransformFunc{
+	"sha256": transformSHA256,
+	"redact": transformRedact,
+	"null":   transformNull,
+}
+
+// transformSHA256 replaces a value with the hex SHA-256 of its text form.
+// NULL stays NULL so the archive still distinguishes missing values.
+func transformSHA256(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	var b []byte
+	switch val := v.(type) {
+	case []byte:
+		b = val
+	case string:
+		b = []byte(val)
+	default:
+		b = []byte(fmt.Sprint(val))
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// transformRedact replaces a non-NULL value with a fixed marker. Intended for
+// text columns; the destination column must accept the marker string.
+func transformRedact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return "REDACTED"
+}
+
+// transformNull drops the value. The destination column must be nullable.
+func transformNull(interface{}) interface{} {
+	return nil
+}
+
+// Transforms is a registry of column transforms applied row-by-row by the
+// copy phase, keyed by table and column. Transformed columns no longer match
+// the source, so the verifier must skip them (see applyTransforms).
+type Transforms struct {
+	funcs map[string]map[string]TransformFunc // table -> lower(column) -> transform
+	names map[string]map[string]string        // table -> lower(column) -> configured column name
+}
+
+// NewTransforms creates an empty transform registry.
+func NewTransforms() *Transforms {
+	return &Transforms{
+		funcs: make(map[string]map[string]TransformFunc),
+		names: make(map[string]map[string]string),
+	}
+}
+
+// Register sets a custom transform for table.column, replacing any existing one.
+func (t *Transforms) Register(table, column string, fn TransformFunc) {
+	if t.funcs[table] == nil {
+		t.funcs[table] = make(map[string]TransformFunc)
+		t.names[table] = make(map[string]string)
+	}
+	key := strings.ToLower(column)
+	t.funcs[table][key] = fn
+	t.names[table][key] = column
+}
+
+// RegisterBuiltin sets a built-in transform ("sha256", "redact", or "null")
+// for table.column.
+func (t *Transforms) RegisterBuiltin(table, column, name string) error {
+	fn, ok := builtinTransforms[name]
+	if !ok {
+		return fmt.Errorf("unknown transform %q for %s.%s", name, table, column)
+	}
+	t.Register(table, column, fn)
+	return nil
+}
+
+// Tables returns the tables that have at least one transform, sorted.
+func (t *Transforms) Tables() []string {
+	if t == nil {
+		return nil
+	}
+	tables := make([]string, 0, len(t.funcs))
+	for table := range t.funcs {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// Columns returns the transformed columns of table, sorted.
+func (t *Transforms) Columns(table string) []string {
+	if t == nil {
+		return nil
+	}
+	columns := make([]string, 0, len(t.names[table]))
+	for _, column := range t.names[table] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// forColumns returns the transforms for a result set of table, aligned with
+// columns (nil entries for untransformed columns), or nil if none apply.
+func (t *Transforms) forColumns(table string, columns []string) []TransformFunc {
+	if t == nil || len(t.funcs[table]) == 0 {
+		return nil
+	}
+	var aligned []TransformFunc
+	for i, column := range columns {
+		if fn, ok := t.funcs[table][strings.ToLower(column)]; ok {
+			if aligned == nil {
+				aligned = make([]TransformFunc, len(columns))
+			}
+			aligned[i] = fn
+		}
+	}
+	return aligned
+}
+
+// TransformsFromJob builds the registry from the columns.transform blocks of
+// a job's root table and relations.
+func TransformsFromJob(job *config.JobConfig) (*Transforms, error) {
+	t := NewTransforms()
+	if job == nil {
+		return t, nil
+	}
+	if err := t.registerSelection(job.RootTable, job.Columns); err != nil {
+		return nil, err
+	}
+	var walk func(relations []config.Relation) error
+	walk = func(relations []config.Relation) error {
+		for _, rel := range relations {
+			if err := t.registerSelection(rel.Table, rel.Columns); err != nil {
+				return err
+			}
+			if err := walk(rel.Relations); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(job.Relations); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Transforms) registerSelection(table string, sel *config.ColumnSelection) error {
+	if sel == nil {
+		return nil
+	}
+	for column, name := range sel.Transform {
+		if err := t.RegisterBuiltin(table, column, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTransforms installs the job's column transforms on the copy phase and
+// excludes the transformed columns from verification, since their archived
+// values differ from the source by design, along with each table's
+// columns.verify_ignore.
+func applyTransforms(job *config.JobConfig, cp *CopyPhase, v *verifier.Verifier) error {
+	transforms, err := TransformsFromJob(job)
+	if err != nil {
+		return err
+	}
+	cp.SetTransforms(transforms)
+	ignoreVerifyColumns(job, transforms, v)
+	return nil
+}
+
+// ignoreVerifyColumns excludes from v's row comparison, per table, the
+// transformed columns and those listed in columns.verify_ignore.
+func ignoreVerifyColumns(job *config.JobConfig, transforms *Transforms, v *verifier.Verifier) {
+	ignored := make(map[string][]string)
+	for _, table := range transforms.Tables() {
+		ignored[table] = append(ignored[table], transforms.Columns(table)...)
+	}
+	add := func(table string, sel *config.ColumnSelection) {
+		if sel != nil {
+			ignored[table] = append(ignored[table], sel.VerifyIgnore...)
+		}
+	}
+	var walk func(relations []config.Relation)
+	walk = func(relations []config.Relation) {
+		for _, rel := range relations {
+			add(rel.Table, rel.Columns)
+			walk(rel.Relations)
+		}
+	}
+	if job != nil {
+		add(job.RootTable, job.Columns)
+		walk(job.Relations)
+	}
+	for table, columns := range ignored {
+		if len(columns) > 0 {
+			v.SetIgnoredColumns(table, columns)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package archiver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTransforms(t *testing.T) {
+	sum := sha256.Sum256([]byte("alice@example.com"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), transformSHA256([]byte("alice@example.com")))
+	assert.Equal(t, transformSHA256("alice@example.com"), transformSHA256([]byte("alice@example.com")))
+	assert.Nil(t, transformSHA256(nil))
+	assert.Equal(t, "REDACTED", transformRedact("secret"))
+	assert.Nil(t, transformRedact(nil))
+	assert.Nil(t, transformNull(int64(42)))
+}
+
+func TestTransformsFromJob(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "customers",
+		PrimaryKey: "id",
+		Columns:    &config.ColumnSelection{Transform: map[string]string{"email": "sha256", "phone": "null"}},
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "customer_id", DependencyType: "1-N",
+				Columns: &config.ColumnSelection{Transform: map[string]string{"notes": "redact"}}},
+		},
+	}
+
+	transforms, err := TransformsFromJob(job)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"customers", "orders"}, transforms.Tables())
+	assert.Equal(t, []string{"email", "phone"}, transforms.Columns("customers"))
+
+	aligned := transforms.forColumns("customers", []string{"id", "EMAIL", "name"})
+	require.Len(t, aligned, 3)
+	assert.Nil(t, aligned[0])
+	assert.NotNil(t, aligned[1], "column match is case-insensitive")
+	assert.Nil(t, transforms.forColumns("orders", []string{"id", "total"}))
+
+	job.Columns.Transform["email"] = "rot13"
+	_, err = TransformsFromJob(job)
+	assert.ErrorContains(t, err, `unknown transform "rot13"`)
+}
+
+func TestCopyPhase_MaskedEmailColumn(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	cp, _ := NewCopyPhase(sourceDB, destDB, createSimpleGraph(), config.SafetyConfig{}, logger.NewDefault())
+	transforms := NewTransforms()
+	require.NoError(t, transforms.RegisterBuiltin("customers", "email", "sha256"))
+	transforms.Register("customers", "name", func(v interface{}) interface{} { return "customer" })
+	cp.SetTransforms(transforms)
+
+	recordSet := &RecordSet{
+		RootPKs: []interface{}{int64(1)},
+		Records: map[string][]interface{}{
+			"customers": {int64(1)},
+		},
+	}
+
+	sum := sha256.Sum256([]byte("alice@example.com"))
+	destMock.ExpectBegin()
+	destMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	sourceMock.ExpectQuery("SELECT \\* FROM `customers` WHERE `id` IN \\(\\?\\)").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(int64(1), "Alice", []byte("alice@example.com")))
+	// The destination receives the transform output, not the source value.
+	destMock.ExpectExec("INSERT IGNORE INTO `customers`").
+		WithArgs(int64(1), "customer", hex.EncodeToString(sum[:])).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	destMock.ExpectCommit()
+
+	_, err := cp.Copy(context.Background(), recordSet)
+
+	require.NoError(t, err)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, destMock.ExpectationsWereMet())
+}
@@ -0,0 +1,227 @@
+package archiver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mysql "github.com/go-sql-driver/mysql"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// Copy writer names accepted by processing.copy_writer.
+const (
+	CopyWriterInsert   = "insert"
+	CopyWriterLoadData = "load-data"
+)
+
+// DestinationWriter writes fetched rows into a destination table inside the
+// copy phase's transaction, in place of its multi-row INSERT statements.
+// Duplicate keys must be skipped, not fail the statement: the copy phase
+// counts the shortfall as skipped rows, or reports it as a duplicate in
+// strict mode.
+type DestinationWriter interface {
+	// WriteRows writes rowCount rows (values flattened in row-major order,
+	// len == rowCount*len(columns)) into destination table dest within tx and
+	// returns the rows written.
+	WriteRows(ctx context.Context, tx *sql.Tx, dest string, columns []string, rowCount int, values []interface{}) (int64, error)
+}
+
+// loadDataTimeLayout renders time.Time values the way MySQL parses DATETIME
+// and TIMESTAMP input, keeping microseconds.
+const loadDataTimeLayout = "2006-01-02 15:04:05.999999"
+
+// loadDataReaderSeq makes each staged batch's reader handler name unique.
+var loadDataReaderSeq atomic.Uint64
+
+// LoadDataWriter writes each batch with LOAD DATA LOCAL INFILE. The batch is
+// staged as CSV in memory and streamed through a reader handler registered
+// with the MySQL driver for the duration of the statement, so nothing is
+// written to disk. The destination server must allow local_infile (see
+// CheckLocalInfile). Duplicate keys are skipped (IGNORE); any other warning
+// the load raises fails the batch.
+type LoadDataWriter struct{}
+
+// NewLoadDataWriter creates a LOAD DATA LOCAL INFILE writer.
+func NewLoadDataWriter() *LoadDataWriter {
+	return &LoadDataWriter{}
+}
+
+// WriteRows implements DestinationWriter.
+func (w *LoadDataWriter) WriteRows(ctx context.Context, tx *sql.Tx, dest string, columns []string, rowCount int, values []interface{}) (int64, error) {
+	if rowCount == 0 || len(columns) == 0 {
+		return 0, nil
+	}
+	payload := stageLoadData(len(columns), values)
+	reader := fmt.Sprintf("goarchive-load-%d", loadDataReaderSeq.Add(1))
+	mysql.RegisterReaderHandler(reader, func() io.Reader { return bytes.NewReader(payload) })
+	defer mysql.DeregisterReaderHandler(reader)
+
+	result, err := tx.ExecContext(ctx, loadDataStatement(reader, dest, columns))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load batch into %s: %w", dest, err)
+	}
+	if err := checkLoadDataWarnings(ctx, tx, dest); err != nil {
+		return 0, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected, nil
+}
+
+// checkLoadDataWarnings fails a LOAD DATA statement on tx that raised any
+// warning other than a skipped duplicate key. IGNORE downgrades truncation,
+// conversion and missing-field errors to warnings, which would otherwise
+// store altered rows that a count verification cannot tell apart; the
+// caller rolls the batch back.
+func checkLoadDataWarnings(ctx context.Context, tx *sql.Tx, dest string) error {
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return fmt.Errorf("failed to read LOAD DATA warnings for %s: %w", dest, err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return fmt.Errorf("failed to read LOAD DATA warnings for %s: %w", dest, err)
+		}
+		if code != mysqlErrDuplicateEntry {
+			return fmt.Errorf("LOAD DATA into %s altered data: %s %d: %s", dest, level, code, message)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read LOAD DATA warnings for %s: %w", dest, err)
+	}
+	return nil
+}
+
+// loadDataStatement returns the LOAD DATA statement that reads the staged
+// CSV of reader into dest's columns. CHARACTER SET binary loads the bytes
+// unconverted, as the INSERT path sends them.
+func loadDataStatement(reader, dest string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = sqlutil.QuoteIdentifier(col)
+	}
+	return fmt.Sprintf(
+		"LOAD DATA LOCAL INFILE 'Reader::%s' IGNORE INTO TABLE %s CHARACTER SET binary "+
+			"FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '\\\\' "+
+			"LINES TERMINATED BY '\\n' (%s)",
+		reader, sqlutil.QuoteIdentifier(dest), strings.Join(quoted, ", "),
+	)
+}
+
+// stageLoadData renders values (columnCount per row) as the CSV
+// loadDataStatement reads: one line per row, NULL as \N, numbers bare and
+// everything else quoted and escaped.
+func stageLoadData(columnCount int, values []interface{}) []byte {
+	var buf bytes.Buffer
+	for i, v := range values {
+		if i > 0 {
+			if i%columnCount == 0 {
+				buf.WriteByte('\n')
+			} else {
+				buf.WriteByte(',')
+			}
+		}
+		appendLoadDataField(&buf, v)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// appendLoadDataField writes one field of a staged row.
+func appendLoadDataField(buf *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString(`\N`)
+	case int64:
+		buf.WriteString(strconv.FormatInt(val, 10))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(val), 10))
+	case int:
+		buf.WriteString(strconv.Itoa(val))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(val, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+	case float32:
+		buf.WriteString(strconv.FormatFloat(float64(val), 'g', -1, 32))
+	case bool:
+		if val {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	case []byte:
+		appendLoadDataQuoted(buf, val)
+	case string:
+		appendLoadDataQuoted(buf, []byte(val))
+	case time.Time:
+		appendLoadDataQuoted(buf, []byte(val.Format(loadDataTimeLayout)))
+	default:
+		appendLoadDataQuoted(buf, []byte(fmt.Sprint(val)))
+	}
+}
+
+// appendLoadDataQuoted writes b enclosed in double quotes, escaping the
+// enclosing and escape characters and the bytes MySQL would otherwise read
+// as line terminators or end of data.
+func appendLoadDataQuoted(buf *bytes.Buffer, b []byte) {
+	buf.WriteByte('"')
+	for _, c := range b {
+		switch c {
+		case '\\', '"':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case 0:
+			buf.WriteString(`\0`)
+		case 0x1a:
+			buf.WriteString(`\Z`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// CheckLocalInfile returns an error unless the server db connects to accepts
+// LOAD DATA LOCAL INFILE (the local_infile system variable).
+func CheckLocalInfile(ctx context.Context, db *sql.DB) error {
+	var enabled sql.NullInt64
+	if err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.local_infile").Scan(&enabled); err != nil {
+		return fmt.Errorf("failed to read local_infile: %w", err)
+	}
+	if !enabled.Valid || enabled.Int64 != 1 {
+		return fmt.Errorf("copy_writer %q requires local_infile=ON on the destination server (SET GLOBAL local_infile = 1)", CopyWriterLoadData)
+	}
+	return nil
+}
+
+// newDestinationWriter returns the DestinationWriter for processing.copy_writer,
+// or nil for the default INSERT path. The load-data writer is only returned
+// once the destination is confirmed to accept it.
+func newDestinationWriter(ctx context.Context, destDB *sql.DB, name string) (DestinationWriter, error) {
+	switch name {
+	case "", CopyWriterInsert:
+		return nil, nil
+	case CopyWriterLoadData:
+		if err := CheckLocalInfile(ctx, destDB); err != nil {
+			return nil, err
+		}
+		return NewLoadDataWriter(), nil
+	default:
+		return nil, fmt.Errorf("unknown copy_writer %q", name)
+	}
+}
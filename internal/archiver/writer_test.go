@@ -0,0 +1,167 @@
+package archiver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDataStatement(t *testing.T) {
+	got := loadDataStatement("goarchive-load-7", "orders_2026", []string{"id", "note"})
+	want := "LOAD DATA LOCAL INFILE 'Reader::goarchive-load-7' IGNORE INTO TABLE `orders_2026` CHARACTER SET binary " +
+		`FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' ESCAPED BY '\\' ` +
+		`LINES TERMINATED BY '\n' (` + "`id`, `note`)"
+	assert.Equal(t, want, got)
+}
+
+func TestStageLoadData(t *testing.T) {
+	created := time.Date(2026, 3, 4, 5, 6, 7, 123000000, time.UTC)
+	values := []interface{}{
+		int64(1), "plain", created, nil,
+		uint64(2), []byte("a \"quoted\", back\\slash\nline"), 1.5, true,
+		int64(-3), string([]byte{'x', 0, 0x1a, '\r'}), nil, false,
+	}
+
+	got := string(stageLoadData(4, values))
+	want := `1,"plain","2026-03-04 05:06:07.123",\N` + "\n" +
+		`2,"a \"quoted\", back\\slash\nline",1.5,1` + "\n" +
+		`-3,"x\0\Z\r",\N,0` + "\n"
+	assert.Equal(t, want, got)
+}
+
+func TestLoadDataWriter_WriteRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^LOAD DATA LOCAL INFILE 'Reader::goarchive-load-[0-9]+' IGNORE INTO TABLE `orders` CHARACTER SET binary .* \\(`id`, `status`\\)$").
+		WithoutArgs().
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SHOW WARNINGS").WillReturnRows(sqlmock.NewRows([]string{"Level", "Code", "Message"}))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	n, err := NewLoadDataWriter().WriteRows(context.Background(), tx, "orders", []string{"id", "status"}, 2,
+		[]interface{}{int64(1), "open", int64(2), "closed"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	require.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadDataWriter_FailsOnDataWarnings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^LOAD DATA LOCAL INFILE").WillReturnResult(sqlmock.NewResult(0, 1))
+	// A skipped duplicate is fine; a truncated value is not.
+	mock.ExpectQuery("SHOW WARNINGS").WillReturnRows(sqlmock.NewRows([]string{"Level", "Code", "Message"}).
+		AddRow("Warning", 1062, "Duplicate entry '1' for key 'orders.PRIMARY'").
+		AddRow("Warning", 1265, "Data truncated for column 'status' at row 2"))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = NewLoadDataWriter().WriteRows(context.Background(), tx, "orders", []string{"id", "status"}, 2,
+		[]interface{}{int64(1), "open", int64(2), "closed-but-too-long"})
+	assert.ErrorContains(t, err, "LOAD DATA into orders altered data: Warning 1265: Data truncated for column 'status' at row 2")
+	require.NoError(t, tx.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckLocalInfile(t *testing.T) {
+	for _, tt := range []struct {
+		value   interface{}
+		wantErr bool
+	}{
+		{value: int64(1)},
+		{value: int64(0), wantErr: true},
+	} {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectQuery("SELECT @@GLOBAL.local_infile").
+			WillReturnRows(sqlmock.NewRows([]string{"@@GLOBAL.local_infile"}).AddRow(tt.value))
+
+		err = CheckLocalInfile(context.Background(), db)
+		if tt.wantErr {
+			assert.ErrorContains(t, err, "local_infile=ON")
+		} else {
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}
+}
+
+func TestNewDestinationWriter_DefaultsToInsert(t *testing.T) {
+	for _, name := range []string{"", CopyWriterInsert} {
+		w, err := newDestinationWriter(context.Background(), nil, name)
+		require.NoError(t, err)
+		assert.Nil(t, w, "copy_writer %q must keep the INSERT path", name)
+	}
+}
+
+// fakeWriter reports affected rows written, or err.
+type fakeWriter struct {
+	affected int64
+	err      error
+	calls    int
+}
+
+func (w *fakeWriter) WriteRows(context.Context, *sql.Tx, string, []string, int, []interface{}) (int64, error) {
+	w.calls++
+	return w.affected, w.err
+}
+
+func TestCopyPhase_WriterReplacesInsert(t *testing.T) {
+	ctx := context.Background()
+	columns := []string{"id", "status"}
+	values := []interface{}{int64(1), "open", int64(2), "closed"}
+
+	t.Run("skipped duplicates are counted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() { _ = db.Close() }()
+		mock.ExpectBegin()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+
+		w := &fakeWriter{affected: 1}
+		cp := &CopyPhase{writer: w}
+		copied, skipped, err := cp.insertRows(ctx, tx, "orders", "orders", columns, values)
+		require.NoError(t, err)
+		assert.Equal(t, 1, w.calls)
+		assert.Equal(t, int64(1), copied)
+		assert.Equal(t, int64(1), skipped)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("strict mode reports the duplicate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() { _ = db.Close() }()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SHOW WARNINGS").WillReturnRows(sqlmock.NewRows([]string{"Level", "Code", "Message"}).
+			AddRow("Warning", 1062, "Duplicate entry '2' for key 'orders.PRIMARY'"))
+		tx, err := db.Begin()
+		require.NoError(t, err)
+
+		cp := &CopyPhase{writer: &fakeWriter{affected: 1}, strictInsert: true}
+		_, _, err = cp.insertRows(ctx, tx, "orders", "orders", columns, values)
+		var dupErr *ErrDestinationDuplicate
+		require.True(t, errors.As(err, &dupErr), "got %v", err)
+		assert.Equal(t, "orders", dupErr.Table)
+		assert.Equal(t, "2", dupErr.ConflictingPK)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
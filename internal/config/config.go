@@ -1,6 +1,14 @@
 // Package config provides configuration structures and loading for GoArchive.
 package config
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"time"
+)
+
 // Config represents the complete application configuration.
 type Config struct {
 	Source       DatabaseConfig       `yaml:"source" mapstructure:"source"`
@@ -11,6 +19,7 @@ type Config struct {
 	Safety       SafetyConfig         `yaml:"safety" mapstructure:"safety"`
 	Verification VerificationConfig   `yaml:"verification" mapstructure:"verification"`
 	Logging      LoggingConfig        `yaml:"logging" mapstructure:"logging"`
+	Health       HealthConfig         `yaml:"health" mapstructure:"health"`
 }
 
 // DatabaseConfig represents a MySQL database connection configuration.
@@ -23,10 +32,88 @@ type DatabaseConfig struct {
 	// JobSchema is the schema holding GoArchive's tracking tables
 	// (archiver_job, archiver_job_log_<id>). Empty (default) resolves to
 	// Database. A DBA must pre-create this schema and grant CREATE + CRUD.
-	JobSchema          string `yaml:"job_schema" mapstructure:"job_schema"`
-	TLS                string `yaml:"tls" mapstructure:"tls"` // disable, preferred, skip-verify, required
+	JobSchema string `yaml:"job_schema" mapstructure:"job_schema"`
+	TLS       string `yaml:"tls" mapstructure:"tls"` // disable, preferred, skip-verify, required, verify-ca, verify-full
+	// TLSCACert is a PEM CA bundle used to verify the server certificate
+	// (required for verify-ca). TLSClientCert/TLSClientKey are a PEM client
+	// certificate and key for servers that require X.509 authentication;
+	// set both or neither.
+	TLSCACert          string `yaml:"tls_ca_cert" mapstructure:"tls_ca_cert"`
+	TLSClientCert      string `yaml:"tls_client_cert" mapstructure:"tls_client_cert"`
+	TLSClientKey       string `yaml:"tls_client_key" mapstructure:"tls_client_key"`
 	MaxConnections     int    `yaml:"max_connections" mapstructure:"max_connections"`
 	MaxIdleConnections int    `yaml:"max_idle_connections" mapstructure:"max_idle_connections"`
+	// ConnMaxLifetimeSeconds and ConnMaxIdleTimeSeconds bound how long a pooled
+	// connection may live / sit idle before it is closed and replaced. Keep them
+	// below the server's wait_timeout and any proxy idle timeout. 0 uses the
+	// built-in defaults (600s / 300s).
+	ConnMaxLifetimeSeconds int `yaml:"conn_max_lifetime_seconds" mapstructure:"conn_max_lifetime_seconds"`
+	ConnMaxIdleTimeSeconds int `yaml:"conn_max_idle_time_seconds" mapstructure:"conn_max_idle_time_seconds"`
+	// ReadReplica (source only) routes discovery and source-side verification
+	// reads to a read replica. Copy reads and deletes always use the primary.
+	// Empty user/password/database/tls/port inherit from the primary. Nil
+	// (default) reads everything from the primary.
+	ReadReplica *DatabaseConfig `yaml:"read_replica" mapstructure:"read_replica"`
+	// SSHTunnel routes the MySQL connection through an SSH bastion. Nil
+	// (default) connects directly. A read replica does not inherit it.
+	SSHTunnel *SSHTunnelConfig `yaml:"ssh_tunnel" mapstructure:"ssh_tunnel"`
+}
+
+// SSHTunnelConfig is the SSH bastion a database connection is tunnelled
+// through. The bastion opens the TCP connection to host:port of the database,
+// so that address is resolved from the bastion, not from this machine.
+type SSHTunnelConfig struct {
+	Host string `yaml:"host" mapstructure:"host"`
+	Port int    `yaml:"port" mapstructure:"port"` // 0 = 22
+	User string `yaml:"user" mapstructure:"user"`
+	// KeyPath is an unencrypted PEM/OpenSSH private key used to authenticate.
+	KeyPath string `yaml:"key_path" mapstructure:"key_path"`
+	// KnownHostsFile verifies the bastion's host key. Empty uses
+	// ~/.ssh/known_hosts.
+	KnownHostsFile string `yaml:"known_hosts_file" mapstructure:"known_hosts_file"`
+}
+
+// TLS modes accepted by DatabaseConfig.TLS.
+const (
+	TLSDisable    = "disable"
+	TLSPreferred  = "preferred"   // TLS if the server supports it, no verification (default)
+	TLSSkipVerify = "skip-verify" // always TLS, certificate not verified
+	TLSRequired   = "required"    // always TLS, chain and hostname verified
+	TLSVerifyCA   = "verify-ca"   // always TLS, chain verified against tls_ca_cert, hostname not checked
+	TLSVerifyFull = "verify-full" // always TLS, chain and hostname verified
+)
+
+// ReadReplicaConfig returns the effective read replica connection settings,
+// filling unset fields from db. Returns nil when no read replica is configured.
+func (db *DatabaseConfig) ReadReplicaConfig() *DatabaseConfig {
+	if db.ReadReplica == nil {
+		return nil
+	}
+	rr := *db.ReadReplica
+	rr.ReadReplica = nil
+	if rr.Port == 0 {
+		rr.Port = db.Port
+	}
+	if rr.User == "" {
+		rr.User = db.User
+		if rr.Password == "" {
+			rr.Password = db.Password
+		}
+	}
+	if rr.Database == "" {
+		rr.Database = db.Database
+	}
+	if rr.TLS == "" {
+		rr.TLS = db.TLS
+		if rr.TLSCACert == "" {
+			rr.TLSCACert = db.TLSCACert
+		}
+		if rr.TLSClientCert == "" && rr.TLSClientKey == "" {
+			rr.TLSClientCert = db.TLSClientCert
+			rr.TLSClientKey = db.TLSClientKey
+		}
+	}
+	return &rr
 }
 
 // ReplicaConfig represents the replica database for replication lag monitoring.
@@ -48,35 +135,146 @@ type JobConfig struct {
 	PrimaryKey   string                 `yaml:"primary_key" mapstructure:"primary_key"`
 	Where        string                 `yaml:"where" mapstructure:"where"`
 	Relations    []Relation             `yaml:"relations" mapstructure:"relations"`
+	Columns      *ColumnSelection       `yaml:"columns,omitempty" mapstructure:"columns"` // Root table column selection
 	Processing   *ProcessingOverrides   `yaml:"processing,omitempty" mapstructure:"processing"`
 	Verification *VerificationOverrides `yaml:"verification,omitempty" mapstructure:"verification"`
 	Logging      *LoggingConfig         `yaml:"logging,omitempty" mapstructure:"logging"`
+	// DestinationTable and DestinationDateColumn route the root table's rows
+	// into a computed destination table (see Relation.DestinationTable).
+	DestinationTable      string `yaml:"destination_table,omitempty" mapstructure:"destination_table"`
+	DestinationDateColumn string `yaml:"destination_date_column,omitempty" mapstructure:"destination_date_column"`
+	// ExcludeTables prunes these tables and all of their descendants from
+	// the graph `goarchive relations` builds from the database's foreign
+	// keys. A table listed in Relations cannot be excluded, directly or
+	// through an ancestor.
+	ExcludeTables []string `yaml:"exclude_tables,omitempty" mapstructure:"exclude_tables"`
+	// IncrementalColumn names a DATETIME/TIMESTAMP column of the root table
+	// for recurring jobs: each run only processes root rows with a value
+	// after the previous successful run's start, read from the source
+	// server's clock (the watermark), in addition to where. Empty (default)
+	// processes every row matching where.
+	IncrementalColumn string `yaml:"incremental_column,omitempty" mapstructure:"incremental_column"`
+	// PreSQL and PostSQL are statements an archive run executes on the
+	// source, in order, before its first batch and after its last (e.g. to
+	// disable an index or flip a flag table and restore it afterwards).
+	// DestinationPreSQL and DestinationPostSQL do the same on the
+	// destination. A failing pre statement fails the run before any row is
+	// touched; post statements run once the pre statements succeeded,
+	// whether or not the run did.
+	PreSQL             []string `yaml:"pre_sql,omitempty" mapstructure:"pre_sql"`
+	PostSQL            []string `yaml:"post_sql,omitempty" mapstructure:"post_sql"`
+	DestinationPreSQL  []string `yaml:"destination_pre_sql,omitempty" mapstructure:"destination_pre_sql"`
+	DestinationPostSQL []string `yaml:"destination_post_sql,omitempty" mapstructure:"destination_post_sql"`
+	// ConfirmToken confirms this job's deletes under
+	// safety.require_confirmation; it must equal ExpectedConfirmToken.
+	ConfirmToken string `yaml:"confirm_token,omitempty" mapstructure:"confirm_token"`
+	// PhaseHook is an executable run before and after every discovery,
+	// copy, verify and delete phase of archive, copy-only and purge (e.g.
+	// to disable a trigger or send a notification), with the hook point,
+	// phase and job in GOARCHIVE_* environment variables. A non-zero exit
+	// before a phase fails it without running it. Empty (default) runs none.
+	PhaseHook string `yaml:"phase_hook,omitempty" mapstructure:"phase_hook"`
 }
 
 // ProcessingOverrides is the per-job processing block. Pointer fields
 // distinguish "not set — inherit global" (nil) from an explicit value, so a
 // job can set sleep_seconds: 0 to disable a global sleep.
 type ProcessingOverrides struct {
-	BatchSize          *int     `yaml:"batch_size,omitempty" mapstructure:"batch_size"`
-	BatchDeleteSize    *int     `yaml:"batch_delete_size,omitempty" mapstructure:"batch_delete_size"`
-	SleepSeconds       *float64 `yaml:"sleep_seconds,omitempty" mapstructure:"sleep_seconds"`
-	DeleteSleepSeconds *float64 `yaml:"delete_sleep_seconds,omitempty" mapstructure:"delete_sleep_seconds"`
-	SentinelFile       *string  `yaml:"sentinel_file,omitempty" mapstructure:"sentinel_file"`
+	BatchSize          *int           `yaml:"batch_size,omitempty" mapstructure:"batch_size"`
+	BatchDeleteSize    *int           `yaml:"batch_delete_size,omitempty" mapstructure:"batch_delete_size"`
+	SleepSeconds       *float64       `yaml:"sleep_seconds,omitempty" mapstructure:"sleep_seconds"`
+	DeleteSleepSeconds *float64       `yaml:"delete_sleep_seconds,omitempty" mapstructure:"delete_sleep_seconds"`
+	MaxRowsPerSecond   *float64       `yaml:"max_rows_per_second,omitempty" mapstructure:"max_rows_per_second"`
+	SentinelFile       *string        `yaml:"sentinel_file,omitempty" mapstructure:"sentinel_file"`
+	MaxInClauseSize    *int           `yaml:"max_in_clause_size,omitempty" mapstructure:"max_in_clause_size"`
+	CopyMode           *string        `yaml:"copy_mode,omitempty" mapstructure:"copy_mode"`
+	CopyWriter         *string        `yaml:"copy_writer,omitempty" mapstructure:"copy_writer"`
+	MaxRuntime         *time.Duration `yaml:"max_runtime,omitempty" mapstructure:"max_runtime"`
+	DiscoveryTimeout   *time.Duration `yaml:"discovery_timeout,omitempty" mapstructure:"discovery_timeout"`
+	CopyTimeout        *time.Duration `yaml:"copy_timeout,omitempty" mapstructure:"copy_timeout"`
+	VerifyTimeout      *time.Duration `yaml:"verify_timeout,omitempty" mapstructure:"verify_timeout"`
+	DeleteTimeout      *time.Duration `yaml:"delete_timeout,omitempty" mapstructure:"delete_timeout"`
+	StatementTimeout   *time.Duration `yaml:"statement_timeout,omitempty" mapstructure:"statement_timeout"`
+	ContinueOnError    *bool          `yaml:"continue_on_error,omitempty" mapstructure:"continue_on_error"`
+	DeadLetter         *bool          `yaml:"dead_letter,omitempty" mapstructure:"dead_letter"`
+	ResultFile         *string        `yaml:"result_file,omitempty" mapstructure:"result_file"`
+	CountNullFKs       *bool          `yaml:"count_null_foreign_keys,omitempty" mapstructure:"count_null_foreign_keys"`
+	SkipExisting       *bool          `yaml:"skip_existing,omitempty" mapstructure:"skip_existing"`
+	SortDeletePKs      *bool          `yaml:"sort_delete_pks,omitempty" mapstructure:"sort_delete_pks"`
+	RootOrder          *string        `yaml:"root_order,omitempty" mapstructure:"root_order"`
+	Maintenance        *string        `yaml:"post_copy_maintenance,omitempty" mapstructure:"post_copy_maintenance"`
 }
 
 // VerificationOverrides is the per-job verification block.
 type VerificationOverrides struct {
 	Method           string `yaml:"method,omitempty" mapstructure:"method"`
 	SkipVerification *bool  `yaml:"skip_verification,omitempty" mapstructure:"skip_verification"`
+	GateDeletes      *bool  `yaml:"gate_deletes,omitempty" mapstructure:"gate_deletes"`
+	CountPrecheck    *bool  `yaml:"count_precheck,omitempty" mapstructure:"count_precheck"`
+	CanonicalValues  *bool  `yaml:"canonical_values,omitempty" mapstructure:"canonical_values"`
+	// IgnoreColumns replaces the global verification.ignore_columns when set.
+	IgnoreColumns []string `yaml:"ignore_columns,omitempty" mapstructure:"ignore_columns"`
 }
 
+// ParentPKsPlaceholder marks where a relation's discovery_query takes the
+// parent primary keys, e.g. "... WHERE commentable_id IN ({parent_pks})".
+const ParentPKsPlaceholder = "{parent_pks}"
+
 // Relation represents a table relationship for dependency resolution.
 type Relation struct {
-	Table          string     `yaml:"table" mapstructure:"table"`
-	PrimaryKey     string     `yaml:"primary_key" mapstructure:"primary_key"` // PK column name (required)
-	ForeignKey     string     `yaml:"foreign_key" mapstructure:"foreign_key"`
-	DependencyType string     `yaml:"dependency_type" mapstructure:"dependency_type"` // "1-1" or "1-N"
-	Relations      []Relation `yaml:"relations" mapstructure:"relations"`             // Nested relations
+	Table          string           `yaml:"table" mapstructure:"table"`
+	PrimaryKey     string           `yaml:"primary_key" mapstructure:"primary_key"` // PK column name (required)
+	ForeignKey     string           `yaml:"foreign_key" mapstructure:"foreign_key"`
+	DependencyType string           `yaml:"dependency_type" mapstructure:"dependency_type"` // "1-1" or "1-N"
+	Columns        *ColumnSelection `yaml:"columns,omitempty" mapstructure:"columns"`       // Copied columns (nil = all)
+	Relations      []Relation       `yaml:"relations" mapstructure:"relations"`             // Nested relations
+	// UseIndex forces discovery's `WHERE foreign_key IN (...)` lookup on this
+	// table to use the named index (FORCE INDEX). Preflight checks it exists.
+	UseIndex string `yaml:"use_index,omitempty" mapstructure:"use_index"`
+	// DiscoveryQuery replaces discovery's `WHERE foreign_key IN (...)` lookup
+	// on this table with a raw SELECT of the table's primary key, for
+	// relations a plain foreign key cannot express (e.g. polymorphic
+	// associations). ParentPKsPlaceholder marks where the parent PK list goes;
+	// it is bound as parameters, never spliced in as literals. Empty (default)
+	// uses foreign_key.
+	DiscoveryQuery string `yaml:"discovery_query,omitempty" mapstructure:"discovery_query"`
+	// BatchSize overrides the chunk size used for this table by discovery,
+	// copy and verification (processing.batch_size) and by delete
+	// (processing.batch_delete_size). Lower it for tables with wide rows.
+	// 0 (default) keeps the global sizes.
+	BatchSize int `yaml:"batch_size,omitempty" mapstructure:"batch_size"`
+	// VerificationMethod overrides the job's verification method ("count",
+	// "sha256" or "server_checksum") for this table only. Empty (default) uses the job method.
+	VerificationMethod string `yaml:"verification_method,omitempty" mapstructure:"verification_method"`
+	// DestinationTable names the destination table rows of this table are
+	// copied into and verified against, as a template whose {year} and
+	// {month} placeholders are filled from DestinationDateColumn's value for
+	// each row, or from the job run date when that is empty (e.g.
+	// "orders_{year}{month}" -> orders_202301). Rows are still read from and
+	// deleted in Table. Empty (default) uses Table on the destination.
+	DestinationTable      string `yaml:"destination_table,omitempty" mapstructure:"destination_table"`
+	DestinationDateColumn string `yaml:"destination_date_column,omitempty" mapstructure:"destination_date_column"`
+	// Priority orders this table among tables whose parents are all copied:
+	// higher first, still after every parent. Delete order is the reverse.
+	// 0 (default) for every table keeps the usual order.
+	Priority int `yaml:"priority,omitempty" mapstructure:"priority"`
+}
+
+// ColumnSelection limits which columns of a table are copied to the archive
+// and compared by verification, and how their values are masked. Set at most
+// one of Include or Exclude; the primary key is always required. Columns left
+// out of the copy receive their destination default (usually NULL), and in
+// archive mode their source values are deleted with the row.
+type ColumnSelection struct {
+	Include []string `yaml:"include,omitempty" mapstructure:"include"` // Copy only these columns
+	Exclude []string `yaml:"exclude,omitempty" mapstructure:"exclude"` // Copy every column except these
+	// Transform maps a column to a built-in transform ("sha256", "redact",
+	// "null") applied during copy. Transformed columns are not verified.
+	Transform map[string]string `yaml:"transform,omitempty" mapstructure:"transform"`
+	// VerifyIgnore lists columns copied as usual but left out of sha256 and
+	// server_checksum row comparison for this table, for values that
+	// legitimately differ after the copy. The primary key cannot be ignored.
+	VerifyIgnore []string `yaml:"verify_ignore,omitempty" mapstructure:"verify_ignore"`
 }
 
 // ProcessingConfig represents batch processing settings.
@@ -88,10 +286,96 @@ type ProcessingConfig struct {
 	// chunks (every batch_delete_size rows) to limit binlog generation and
 	// replication lag. 0 (default) disables the throttle.
 	DeleteSleepSeconds float64 `yaml:"delete_sleep_seconds" mapstructure:"delete_sleep_seconds"`
+	// MaxRowsPerSecond caps the rows the copy and delete phases process per
+	// second with a token bucket shared by both phases, drawn before each
+	// chunk in proportion to its size. 0 (default) means no limit.
+	MaxRowsPerSecond float64 `yaml:"max_rows_per_second" mapstructure:"max_rows_per_second"`
 	// SentinelFile, when set, is an operator pause switch. Before each batch, if
 	// this file exists, processing pauses and re-checks every second until the
 	// file is removed. Empty (default) disables the pause switch.
 	SentinelFile string `yaml:"sentinel_file" mapstructure:"sentinel_file"`
+	// MaxInClauseSize caps how many PKs are bound into one WHERE ... IN (...)
+	// list by discovery, verification, and delete, independently of the batch
+	// sizes. Larger sets are split into several statements. Lower it when
+	// large batches hit max_allowed_packet. 0 (default) means no extra cap.
+	MaxInClauseSize int `yaml:"max_in_clause_size" mapstructure:"max_in_clause_size"`
+	// CopyMode selects the destination INSERT form: "insert-ignore" (default;
+	// upgraded to strict INSERT when the post-copy safety net is weak),
+	// "insert" (always strict, abort on duplicate) or "upsert"
+	// (INSERT ... ON DUPLICATE KEY UPDATE, so re-copies overwrite rows).
+	CopyMode string `yaml:"copy_mode" mapstructure:"copy_mode"`
+	// CopyWriter selects how copied rows reach the destination: "insert"
+	// (default; multi-row INSERT statements) or "load-data" (each chunk is
+	// staged as CSV in memory and streamed with LOAD DATA LOCAL INFILE, which
+	// requires local_infile on the destination server). load-data cannot be
+	// combined with copy_mode upsert.
+	CopyWriter string `yaml:"copy_writer" mapstructure:"copy_writer"`
+	// MaxRuntime bounds how long one run may process batches (e.g. "2h" for a
+	// maintenance window). When it elapses the run stops at the next batch
+	// boundary with the checkpoint committed; the next run resumes from it.
+	// 0 (default) means no limit.
+	MaxRuntime time.Duration `yaml:"max_runtime" mapstructure:"max_runtime"`
+	// DiscoveryTimeout, CopyTimeout, VerifyTimeout and DeleteTimeout bound
+	// one batch's discovery, copy, verify or delete phase. A phase that runs
+	// longer is interrupted and fails the batch with an error naming the
+	// phase. 0 (default) means the phase only inherits the job context.
+	DiscoveryTimeout time.Duration `yaml:"discovery_timeout" mapstructure:"discovery_timeout"`
+	CopyTimeout      time.Duration `yaml:"copy_timeout" mapstructure:"copy_timeout"`
+	VerifyTimeout    time.Duration `yaml:"verify_timeout" mapstructure:"verify_timeout"`
+	DeleteTimeout    time.Duration `yaml:"delete_timeout" mapstructure:"delete_timeout"`
+	// StatementTimeout bounds each single copy, verify, discovery and
+	// delete statement of a batch, so one runaway statement cannot hold its
+	// locks for the whole phase: SELECTs carry a MySQL MAX_EXECUTION_TIME
+	// hint, and INSERT/DELETE statements run under a context deadline of
+	// this length. A statement that exceeds it fails its batch, whose rows
+	// stay in the source for the next run. 0 (default) means no
+	// per-statement limit.
+	StatementTimeout time.Duration `yaml:"statement_timeout" mapstructure:"statement_timeout"`
+	// ContinueOnError isolates copy and verification failures to the failing
+	// table instead of aborting the archive run. The failing table, its
+	// descendants and its ancestors are kept in the source for that batch
+	// (their roots stay pending and are retried on the next run), clean
+	// sibling branches are still deleted, and the run reports unsuccessful.
+	ContinueOnError bool `yaml:"continue_on_error" mapstructure:"continue_on_error"`
+	// DeadLetter records the PKs of tables that fail under ContinueOnError
+	// in the goarchive_failed_records table of the job schema, one row per
+	// (job, table, pk) with the last error and an attempt count that grows
+	// on every failing run. The rows stay in the source like any other
+	// failed branch. Requires ContinueOnError.
+	DeadLetter bool `yaml:"dead_letter" mapstructure:"dead_letter"`
+	// ResultFile is a path an archive run writes its result to as JSON
+	// when it ends, successful or not: job, start and completion times,
+	// totals, per-table rows copied, deleted and verified, verification
+	// failures and errors. The file is replaced on every run. Empty
+	// (default) writes nothing.
+	ResultFile string `yaml:"result_file" mapstructure:"result_file"`
+	// CountNullFKs counts, once at the start of an archive run, the rows of
+	// each relation table whose foreign key is NULL. Discovery never matches
+	// such rows to a parent, so they are never archived; a non-zero count is
+	// logged as a warning and reported per table.
+	CountNullFKs bool `yaml:"count_null_foreign_keys" mapstructure:"count_null_foreign_keys"`
+	// SkipExisting looks up which discovered PKs the destination already
+	// holds before each archive copy and copies only the missing rows. The
+	// skipped rows are still verified and deleted, so it requires SHA256
+	// verification to prove the existing destination rows match the source.
+	SkipExisting bool `yaml:"skip_existing" mapstructure:"skip_existing"`
+	// SortDeletePKs deletes each table's PKs in ascending order, so every
+	// DELETE chunk of a table locks its rows in the same order as any other
+	// sorted deleter of that table. Concurrent archive or purge runs over
+	// overlapping tables then queue on row locks instead of deadlocking
+	// (MySQL 1213).
+	SortDeletePKs bool `yaml:"sort_delete_pks" mapstructure:"sort_delete_pks"`
+	// RootOrder is the order root PKs are fetched and processed in: "asc"
+	// (default; oldest first for auto-increment keys) or "desc". The resume
+	// checkpoint bounds the scan in that direction, so a job's order must
+	// not change while it has a checkpoint.
+	RootOrder string `yaml:"root_order" mapstructure:"root_order"`
+	// Maintenance is the statement an archive or copy-only run issues on
+	// each destination table of the graph once its batches are done, to
+	// refresh statistics left stale by bulk inserts: "analyze" (ANALYZE
+	// TABLE) or "optimize" (OPTIMIZE TABLE, which also rebuilds the table
+	// and its indexes). Empty (default) runs nothing.
+	Maintenance string `yaml:"post_copy_maintenance" mapstructure:"post_copy_maintenance"`
 }
 
 // SafetyConfig represents safety settings for archive operations.
@@ -99,12 +383,96 @@ type SafetyConfig struct {
 	LagThreshold            int  `yaml:"lag_threshold" mapstructure:"lag_threshold"`
 	CheckInterval           int  `yaml:"check_interval" mapstructure:"check_interval"`
 	DisableForeignKeyChecks bool `yaml:"disable_foreign_key_checks" mapstructure:"disable_foreign_key_checks"`
+	// DisableSourceForeignKeyChecks runs the source DELETEs with
+	// FOREIGN_KEY_CHECKS=0. Separate from DisableForeignKeyChecks, which only
+	// covers the destination copy: deleting with checks off can orphan source
+	// rows that reference the archived ones from outside the graph.
+	DisableSourceForeignKeyChecks bool `yaml:"disable_source_foreign_key_checks" mapstructure:"disable_source_foreign_key_checks"`
+	// SkipCascadedDeletes lets the delete phase skip tables whose rows are
+	// removed by an ON DELETE CASCADE foreign key from a parent in the graph.
+	// Opt-in, and rejected with DisableSourceForeignKeyChecks: cascades do not
+	// fire when the source session disables FK checks.
+	SkipCascadedDeletes bool `yaml:"skip_cascaded_deletes" mapstructure:"skip_cascaded_deletes"`
+	// AllowExtraDestinationColumns accepts destination tables that append
+	// nullable columns after the source columns (additive schema drift).
+	AllowExtraDestinationColumns bool `yaml:"allow_extra_destination_columns" mapstructure:"allow_extra_destination_columns"`
+	// DestinationFreeSpaceMB is the free space available to the destination
+	// database. When set, preflight fails (DISK_SPACE_CHECK) if the estimated
+	// archive size times DiskSpaceMargin exceeds it. 0 disables the check.
+	DestinationFreeSpaceMB int64   `yaml:"destination_free_space_mb" mapstructure:"destination_free_space_mb"`
+	DiskSpaceMargin        float64 `yaml:"disk_space_margin" mapstructure:"disk_space_margin"`
+	// RejectGeneratedColumns fails preflight (GENERATED_COLUMN_CHECK) when a
+	// participating table has a generated column instead of only warning.
+	RejectGeneratedColumns bool `yaml:"reject_generated_columns" mapstructure:"reject_generated_columns"`
+	// TransactionalDelete runs each batch's source deletes in one transaction,
+	// so a mid-batch failure rolls the whole batch back instead of leaving it
+	// partially deleted. Holds row locks until the batch commits.
+	TransactionalDelete bool `yaml:"transactional_delete" mapstructure:"transactional_delete"`
+	// DeleteAuditLog is a file that receives one JSON line per source DELETE
+	// (table, PKs, rows affected) plus a summary line per run. Appended to
+	// and fsynced per line. Empty (default) disables the audit log.
+	DeleteAuditLog string `yaml:"delete_audit_log" mapstructure:"delete_audit_log"`
+	// DeleteBackup is a file that receives every source row a delete is
+	// about to remove, read just before the first DELETE of each batch, so a
+	// botched run can be restored without the destination. Appended to and
+	// fsynced per table. Empty (default) disables the backup.
+	DeleteBackup string `yaml:"delete_backup" mapstructure:"delete_backup"`
+	// DeleteBackupFormat is "json" (default; one JSON line per row) or "sql"
+	// (one INSERT statement per row).
+	DeleteBackupFormat string `yaml:"delete_backup_format" mapstructure:"delete_backup_format"`
+	// OrphanCheck re-reads each relation just before deleting and looks for
+	// child rows that reference the parents being deleted but were not
+	// discovered (e.g. inserted after discovery): "warn" logs them, "abort"
+	// fails the batch before deleting. Empty (default) disables the check.
+	OrphanCheck string `yaml:"orphan_check" mapstructure:"orphan_check"`
+	// MultiPath is what preflight does with tables reachable from the root
+	// through more than one parent (MULTI_PATH_CHECK). Their rows are always
+	// deduplicated, so each PK is copied and deleted once: "dedupe" (default)
+	// accepts them silently, "warn" logs them, "error" fails preflight.
+	MultiPath string `yaml:"multi_path" mapstructure:"multi_path"`
+	// AllowSameDatabase disables the guard that refuses to archive when the
+	// source and destination resolve to the same server and database. Only
+	// for addresses that reach different servers, e.g. a routing proxy.
+	AllowSameDatabase bool `yaml:"allow_same_database" mapstructure:"allow_same_database"`
+	// MaxDeleteRows caps the rows one archive or purge run may delete,
+	// counted from each batch's discovered records before the batch is
+	// copied or deleted. A batch that would take the run over the cap fails
+	// before any of its rows are deleted (e.g. a where clause that matches
+	// the whole table). 0 (default) means no cap.
+	MaxDeleteRows int64 `yaml:"max_delete_rows" mapstructure:"max_delete_rows"`
+	// PostDeleteCheck re-counts each batch's deleted PKs on the source after
+	// the delete and fails the batch if any row is still there (e.g.
+	// re-inserted by a trigger). Off by default: one extra COUNT per table.
+	PostDeleteCheck bool `yaml:"post_delete_check" mapstructure:"post_delete_check"`
+	// RequireConfirmation makes archive and purge refuse to delete source
+	// rows of a job unless its JobConfig.ConfirmToken matches. Copy and
+	// verification still run, so batches are left copied and pending.
+	RequireConfirmation bool `yaml:"require_confirmation" mapstructure:"require_confirmation"`
 }
 
 // VerificationConfig represents data verification settings.
 type VerificationConfig struct {
-	Method           string `yaml:"method" mapstructure:"method"` // "count" or "sha256"
+	Method           string `yaml:"method" mapstructure:"method"` // "count", "sha256" or "server_checksum"
 	SkipVerification bool   `yaml:"skip_verification" mapstructure:"skip_verification"`
+	// GateDeletes runs archive batches one root PK at a time — copy, verify,
+	// delete — so a verification failure stops the run before that root's
+	// rows are deleted, while roots already verified are deleted and
+	// completed. Requires verification (incompatible with skip_verification).
+	GateDeletes bool `yaml:"gate_deletes" mapstructure:"gate_deletes"`
+	// CountPrecheck counts a table's PKs on both sides before its sha256
+	// row fetch and skips the fetch when neither side has any of the rows.
+	CountPrecheck bool `yaml:"count_precheck" mapstructure:"count_precheck"`
+	// CanonicalValues makes sha256 verification serialize values in a
+	// canonical form (DECIMAL without trailing zeros, floats rounded to 15
+	// significant digits, times in UTC, text as hex) so rows that are equal
+	// in MySQL never hash differently. Off keeps the historical format.
+	CanonicalValues bool `yaml:"canonical_values" mapstructure:"canonical_values"`
+	// IgnoreColumns lists columns left out of sha256 and server_checksum
+	// row comparison in every table that has them, e.g. updated_at when a
+	// destination trigger or default rewrites it on insert. The columns are
+	// still copied and row counts still prove every row arrived. A table's
+	// primary key is never ignored. See also ColumnSelection.VerifyIgnore.
+	IgnoreColumns []string `yaml:"ignore_columns,omitempty" mapstructure:"ignore_columns"`
 }
 
 // EffectiveMethod returns the verifier method after applying defaults.
@@ -134,20 +502,36 @@ type LoggingConfig struct {
 	FileOnly bool   `yaml:"file_only" mapstructure:"file_only"` // suppress stdout tee when output is a file
 }
 
+// HealthConfig configures the optional HTTP health server that archive,
+// copy-only and purge run alongside the job for liveness/readiness probes.
+type HealthConfig struct {
+	Port    int    `yaml:"port" mapstructure:"port"`       // 0 (default) disables the server
+	Address string `yaml:"address" mapstructure:"address"` // interface to bind; empty = all
+}
+
+// ListenAddr returns the host:port the health server listens on.
+func (h HealthConfig) ListenAddr() string {
+	return net.JoinHostPort(h.Address, strconv.Itoa(h.Port))
+}
+
 // DefaultConfig returns a Config with sensible default values.
 func DefaultConfig() *Config {
 	return &Config{
 		Source: DatabaseConfig{
-			Port:               3306,
-			TLS:                "preferred",
-			MaxConnections:     10,
-			MaxIdleConnections: 5,
+			Port:                   3306,
+			TLS:                    "preferred",
+			MaxConnections:         10,
+			MaxIdleConnections:     5,
+			ConnMaxLifetimeSeconds: 600,
+			ConnMaxIdleTimeSeconds: 300,
 		},
 		Destination: DatabaseConfig{
-			Port:               3306,
-			TLS:                "preferred",
-			MaxConnections:     10,
-			MaxIdleConnections: 5,
+			Port:                   3306,
+			TLS:                    "preferred",
+			MaxConnections:         10,
+			MaxIdleConnections:     5,
+			ConnMaxLifetimeSeconds: 600,
+			ConnMaxIdleTimeSeconds: 300,
 		},
 		Replica: ReplicaConfig{
 			Enabled: false,
@@ -162,6 +546,7 @@ func DefaultConfig() *Config {
 			LagThreshold:            10,
 			CheckInterval:           5,
 			DisableForeignKeyChecks: false,
+			DiskSpaceMargin:         1.2,
 		},
 		Verification: VerificationConfig{
 			Method:           "count",
@@ -212,9 +597,63 @@ func (jc *JobConfig) GetJobProcessing(global ProcessingConfig) ProcessingConfig
 	if jc.Processing.DeleteSleepSeconds != nil {
 		result.DeleteSleepSeconds = *jc.Processing.DeleteSleepSeconds
 	}
+	if jc.Processing.MaxRowsPerSecond != nil {
+		result.MaxRowsPerSecond = *jc.Processing.MaxRowsPerSecond
+	}
 	if jc.Processing.SentinelFile != nil {
 		result.SentinelFile = *jc.Processing.SentinelFile
 	}
+	if jc.Processing.MaxInClauseSize != nil {
+		result.MaxInClauseSize = *jc.Processing.MaxInClauseSize
+	}
+	if jc.Processing.CopyMode != nil {
+		result.CopyMode = *jc.Processing.CopyMode
+	}
+	if jc.Processing.CopyWriter != nil {
+		result.CopyWriter = *jc.Processing.CopyWriter
+	}
+	if jc.Processing.MaxRuntime != nil {
+		result.MaxRuntime = *jc.Processing.MaxRuntime
+	}
+	if jc.Processing.DiscoveryTimeout != nil {
+		result.DiscoveryTimeout = *jc.Processing.DiscoveryTimeout
+	}
+	if jc.Processing.CopyTimeout != nil {
+		result.CopyTimeout = *jc.Processing.CopyTimeout
+	}
+	if jc.Processing.VerifyTimeout != nil {
+		result.VerifyTimeout = *jc.Processing.VerifyTimeout
+	}
+	if jc.Processing.DeleteTimeout != nil {
+		result.DeleteTimeout = *jc.Processing.DeleteTimeout
+	}
+	if jc.Processing.StatementTimeout != nil {
+		result.StatementTimeout = *jc.Processing.StatementTimeout
+	}
+	if jc.Processing.ContinueOnError != nil {
+		result.ContinueOnError = *jc.Processing.ContinueOnError
+	}
+	if jc.Processing.DeadLetter != nil {
+		result.DeadLetter = *jc.Processing.DeadLetter
+	}
+	if jc.Processing.ResultFile != nil {
+		result.ResultFile = *jc.Processing.ResultFile
+	}
+	if jc.Processing.CountNullFKs != nil {
+		result.CountNullFKs = *jc.Processing.CountNullFKs
+	}
+	if jc.Processing.SkipExisting != nil {
+		result.SkipExisting = *jc.Processing.SkipExisting
+	}
+	if jc.Processing.SortDeletePKs != nil {
+		result.SortDeletePKs = *jc.Processing.SortDeletePKs
+	}
+	if jc.Processing.RootOrder != nil {
+		result.RootOrder = *jc.Processing.RootOrder
+	}
+	if jc.Processing.Maintenance != nil {
+		result.Maintenance = *jc.Processing.Maintenance
+	}
 	return result
 }
 
@@ -260,5 +699,65 @@ func (jc *JobConfig) GetJobVerification(global VerificationConfig) VerificationC
 	if jc.Verification.SkipVerification != nil {
 		result.SkipVerification = *jc.Verification.SkipVerification
 	}
+	if jc.Verification.GateDeletes != nil {
+		result.GateDeletes = *jc.Verification.GateDeletes
+	}
+	if jc.Verification.CountPrecheck != nil {
+		result.CountPrecheck = *jc.Verification.CountPrecheck
+	}
+	if jc.Verification.CanonicalValues != nil {
+		result.CanonicalValues = *jc.Verification.CanonicalValues
+	}
+	if jc.Verification.IgnoreColumns != nil {
+		result.IgnoreColumns = jc.Verification.IgnoreColumns
+	}
 	return result
 }
+
+// WeakestVerification returns the job's merged verification config v (see
+// GetJobVerification) with Method lowered to "count" when any relation
+// overrides verification_method to count, or from "sha256" to
+// "server_checksum" when any relation uses that. Decisions that rely on
+// SHA256 proving row equality (INSERT IGNORE, resume, skip_existing,
+// preflight strictness) must hold for every table, so they use this.
+func (jc *JobConfig) WeakestVerification(v VerificationConfig) VerificationConfig {
+	if v.EffectiveMethod() != "count" && relationsUseMethod(jc.Relations, "count") {
+		v.Method = "count"
+	}
+	if v.EffectiveMethod() == "sha256" && relationsUseMethod(jc.Relations, "server_checksum") {
+		v.Method = "server_checksum"
+	}
+	return v
+}
+
+// HasDestinationTemplate reports whether the root table or any relation sets
+// destination_table.
+func (jc *JobConfig) HasDestinationTemplate() bool {
+	return jc.DestinationTable != "" || relationsHaveDestination(jc.Relations)
+}
+
+// ExpectedConfirmToken is the confirm_token that confirms deletes for the
+// job: the first 12 hex digits of the SHA-256 of the job name and its where
+// clause, so editing the where clause invalidates an earlier confirmation.
+func (jc *JobConfig) ExpectedConfirmToken(jobName string) string {
+	sum := sha256.Sum256([]byte(jobName + "\x00" + jc.Where))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func relationsHaveDestination(relations []Relation) bool {
+	for _, rel := range relations {
+		if rel.DestinationTable != "" || relationsHaveDestination(rel.Relations) {
+			return true
+		}
+	}
+	return false
+}
+
+func relationsUseMethod(relations []Relation, method string) bool {
+	for _, rel := range relations {
+		if rel.VerificationMethod == method || relationsUseMethod(rel.Relations, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -185,6 +188,30 @@ func TestGetJobProcessing_ExplicitZeroSleepOverridesGlobal(t *testing.T) {
 	}
 }
 
+func TestGetJobProcessing_CopyModeOverride(t *testing.T) {
+	upsert := "upsert"
+	global := ProcessingConfig{BatchSize: 1000, CopyMode: "insert-ignore"}
+	if got := (&JobConfig{}).GetJobProcessing(global).CopyMode; got != "insert-ignore" {
+		t.Fatalf("job without processing block must inherit copy_mode, got %q", got)
+	}
+	jc := &JobConfig{Processing: &ProcessingOverrides{CopyMode: &upsert}}
+	if got := jc.GetJobProcessing(global).CopyMode; got != "upsert" {
+		t.Fatalf("job copy_mode must override global, got %q", got)
+	}
+}
+
+func TestGetJobProcessing_ContinueOnErrorOverride(t *testing.T) {
+	off := false
+	global := ProcessingConfig{BatchSize: 1000, ContinueOnError: true}
+	if !(&JobConfig{}).GetJobProcessing(global).ContinueOnError {
+		t.Fatal("job without processing block must inherit continue_on_error")
+	}
+	jc := &JobConfig{Processing: &ProcessingOverrides{ContinueOnError: &off}}
+	if jc.GetJobProcessing(global).ContinueOnError {
+		t.Fatal("job continue_on_error: false must override global")
+	}
+}
+
 func TestGetJobVerification_JobCanReenableVerification(t *testing.T) {
 	off := false
 	global := VerificationConfig{Method: "count", SkipVerification: true}
@@ -309,3 +336,112 @@ func TestDatabaseConfig_EffectiveJobSchema(t *testing.T) {
 		}
 	})
 }
+
+func TestReadReplicaConfig_InheritsFromPrimary(t *testing.T) {
+	primary := DatabaseConfig{
+		Host: "primary", Port: 3307, User: "archiver", Password: "secret",
+		Database: "prod", TLS: "required",
+	}
+	if primary.ReadReplicaConfig() != nil {
+		t.Fatal("ReadReplicaConfig() should be nil when read_replica is unset")
+	}
+
+	primary.ReadReplica = &DatabaseConfig{Host: "replica", MaxConnections: 4}
+	rr := primary.ReadReplicaConfig()
+	want := DatabaseConfig{
+		Host: "replica", Port: 3307, User: "archiver", Password: "secret",
+		Database: "prod", TLS: "required", MaxConnections: 4,
+	}
+	if *rr != want {
+		t.Errorf("ReadReplicaConfig() = %+v, want %+v", *rr, want)
+	}
+
+	// A different user must bring its own password.
+	primary.ReadReplica = &DatabaseConfig{Host: "replica", User: "reader"}
+	if rr := primary.ReadReplicaConfig(); rr.Password != "" {
+		t.Errorf("Password = %q, want empty for an overridden user", rr.Password)
+	}
+}
+
+func TestDatabaseConfig_RedactsPassword(t *testing.T) {
+	const secret = "hunter2-s3cret"
+	db := DatabaseConfig{
+		Host:        "db.internal",
+		Port:        3306,
+		User:        "archiver",
+		Password:    secret,
+		Database:    "production",
+		ReadReplica: &DatabaseConfig{Host: "ro.internal", Password: secret},
+	}
+	cfg := &Config{
+		Source:      db,
+		Destination: db,
+		Replica:     ReplicaConfig{Enabled: true, Host: "replica.internal", User: "monitor", Password: secret},
+	}
+
+	jsonCfg, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	jsonDB, err := json.Marshal(&db)
+	if err != nil {
+		t.Fatalf("marshal database config: %v", err)
+	}
+
+	outputs := map[string]string{
+		"String":      db.String(),
+		"%v":          fmt.Sprintf("%v", db),
+		"%+v pointer": fmt.Sprintf("%+v", &db),
+		"%+v Config":  fmt.Sprintf("%+v", *cfg),
+		"MarshalJSON": string(jsonDB),
+		"Config JSON": string(jsonCfg),
+		"Replica %+v": fmt.Sprintf("%+v", cfg.Replica),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, secret) {
+			t.Errorf("%s leaks the password: %s", name, out)
+		}
+		if !strings.Contains(out, redacted) {
+			t.Errorf("%s does not show the mask: %s", name, out)
+		}
+	}
+	for _, name := range []string{"String", "MarshalJSON"} {
+		for _, want := range []string{"db.internal", "archiver", "production"} {
+			if !strings.Contains(outputs[name], want) {
+				t.Errorf("%s missing %q: %s", name, want, outputs[name])
+			}
+		}
+	}
+
+	if got := db.RevealPassword(); got != secret {
+		t.Errorf("RevealPassword() = %q, want the plaintext secret", got)
+	}
+	if db.Password != secret {
+		t.Error("formatting must not modify the stored password")
+	}
+}
+
+func TestDatabaseConfig_EmptyPasswordNotMasked(t *testing.T) {
+	db := DatabaseConfig{Host: "localhost", User: "root"}
+	if strings.Contains(db.String(), redacted) {
+		t.Errorf("empty password should not be shown as set: %s", db.String())
+	}
+}
+
+func TestJobConfig_ExpectedConfirmToken(t *testing.T) {
+	job := &JobConfig{RootTable: "orders", Where: "created_at < '2020-01-01'"}
+	token := job.ExpectedConfirmToken("archive_orders")
+	if len(token) != 12 {
+		t.Fatalf("expected a 12-digit token, got %q", token)
+	}
+	if again := job.ExpectedConfirmToken("archive_orders"); again != token {
+		t.Errorf("token not stable: %q then %q", token, again)
+	}
+	if other := job.ExpectedConfirmToken("purge_orders"); other == token {
+		t.Errorf("different jobs share token %q", token)
+	}
+	widened := &JobConfig{RootTable: "orders", Where: "1=1"}
+	if w := widened.ExpectedConfirmToken("archive_orders"); w == token {
+		t.Errorf("changed where clause kept token %q", token)
+	}
+}
@@ -63,3 +63,24 @@ func TestValidateProcessing_DeleteSleepSeconds(t *testing.T) {
 		}
 	}
 }
+
+// TestMaxRowsPerSecond verifies the job override and that a negative
+// max_rows_per_second is rejected.
+func TestMaxRowsPerSecond(t *testing.T) {
+	rate := 250.0
+	jc := &JobConfig{Processing: &ProcessingOverrides{MaxRowsPerSecond: &rate}}
+	if got := jc.GetJobProcessing(ProcessingConfig{MaxRowsPerSecond: 1000}); got.MaxRowsPerSecond != 250 {
+		t.Errorf("expected job max_rows_per_second 250, got %v", got.MaxRowsPerSecond)
+	}
+
+	bad := &Config{Processing: ProcessingConfig{BatchSize: 1, BatchDeleteSize: 1, MaxRowsPerSecond: -1}}
+	found := false
+	for _, e := range bad.validateProcessing() {
+		if e.Field == "processing.max_rows_per_second" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected a validation error for negative max_rows_per_second")
+	}
+}
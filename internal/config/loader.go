@@ -2,20 +2,69 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
-// Load reads configuration from the specified file path.
-// It supports YAML files only.
+// Load reads configuration from the specified file path. The format is
+// chosen by extension: .toml files are TOML, anything else is YAML.
+//
+// String values may reference environment variables as ${NAME}; see
+// LoadFromReader for the expansion rules.
 func Load(configPath string) (*Config, error) {
-	v := viper.New()
+	format := formatFromPath(configPath)
+
+	f, err := os.Open(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return LoadFromReader(f, format)
+}
+
+// LoadFromReader parses configuration of the given format ("yaml", "yml" or
+// "toml") from r on top of DefaultConfig.
+//
+// Every ${NAME} in a string value is replaced by the environment variable
+// NAME, so secrets such as passwords can stay out of the file. A referenced
+// variable that is unset is an error rather than an empty string, and a
+// single $ not followed by {NAME} is left as-is. Expansion runs on parsed
+// values, so references inside comments are ignored.
+//
+// Keys that do not correspond to a config field are rejected, catching
+// typos like batchsize that would otherwise silently fall back to defaults.
+func LoadFromReader(r io.Reader, format string) (*Config, error) {
+	format = strings.ToLower(format)
+	if format == "yml" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "toml" {
+		return nil, fmt.Errorf("unsupported config format %q: use yaml or toml", format)
+	}
 
-	v.SetConfigFile(configPath)
-	v.SetConfigType("yaml")
+	raw := viper.New()
+	raw.SetConfigType(format)
+	if err := raw.ReadConfig(r); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
 
-	// Read the config file
-	if err := v.ReadInConfig(); err != nil {
+	var missing []string
+	settings := raw.AllSettings()
+	expandEnv(settings, &missing)
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("config references unset environment variable(s): %s", strings.Join(dedupe(missing), ", "))
+	}
+
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
@@ -23,13 +72,65 @@ func Load(configPath string) (*Config, error) {
 	cfg := DefaultConfig()
 
 	// Unmarshal into config struct
-	if err := v.Unmarshal(cfg); err != nil {
+	if err := v.UnmarshalExact(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
 	return cfg, nil
 }
 
+// formatFromPath maps a config file extension to a LoadFromReader format.
+// Anything but .toml is read as YAML, so names like archiver.yaml.example
+// or extensionless files keep loading as they always have.
+func formatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return "toml"
+	}
+	return "yaml"
+}
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv walks a decoded config tree and substitutes ${NAME} references
+// in string values. Names of unset variables are appended to missing.
+func expandEnv(value interface{}, missing *[]string) interface{} {
+	switch val := value.(type) {
+	case string:
+		return envRefPattern.ReplaceAllStringFunc(val, func(ref string) string {
+			name := envRefPattern.FindStringSubmatch(ref)[1]
+			env, ok := os.LookupEnv(name)
+			if !ok {
+				*missing = append(*missing, name)
+				return ref
+			}
+			return env
+		})
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = expandEnv(item, missing)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = expandEnv(item, missing)
+		}
+		return val
+	default:
+		return value
+	}
+}
+
+// dedupe removes adjacent duplicates from a sorted slice.
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
 // GetJob retrieves a specific job configuration by name.
 func (c *Config) GetJob(name string) (*JobConfig, error) {
 	job, exists := c.Jobs[name]
@@ -48,6 +149,36 @@ func (c *Config) ListJobs() []string {
 	return jobs
 }
 
+// NamedJob is one entry of the jobs map with its name.
+type NamedJob struct {
+	Name string
+	Job  *JobConfig
+}
+
+// SelectJobs returns the jobs called names, in that order, for running
+// several jobs in one process. With no names it returns every job, sorted
+// by name. An unknown or repeated name is an error.
+func (c *Config) SelectJobs(names []string) ([]NamedJob, error) {
+	if len(names) == 0 {
+		names = c.ListJobs()
+		sort.Strings(names)
+	}
+	jobs := make([]NamedJob, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("job %q is listed more than once", name)
+		}
+		seen[name] = true
+		job, err := c.GetJob(name)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, NamedJob{Name: name, Job: job})
+	}
+	return jobs, nil
+}
+
 // ApplyOverrides applies CLI flag overrides to the global configuration.
 // Processing settings (batch sizes, sleeps) are config-file-only: one
 // archiver.yaml holds many jobs and a single CLI value cannot be correct for
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -104,16 +106,32 @@ logging:
 }
 
 func TestLoadWithEnvVars(t *testing.T) {
+	t.Setenv("TEST_DB_HOST", "db.internal")
+	t.Setenv("TEST_DB_USER", "archiver")
+	t.Setenv("TEST_DB_PASS", "s3cr$t")
+
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "test-env.yaml")
 
 	configContent := `
+# ${NOT_SET_ANYWHERE} in a comment is ignored
 source:
   host: ${TEST_DB_HOST}
   port: 3306
   user: ${TEST_DB_USER}
   password: ${TEST_DB_PASS}
   database: testdb
+destination:
+  password: literal$dollar
+jobs:
+  job1:
+    root_table: orders
+    primary_key: id
+    where: "note = '${TEST_DB_USER}'"
+    relations:
+      - table: ${TEST_DB_USER}_items
+        primary_key: id
+        foreign_key: order_id
 `
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
 		t.Fatalf("failed to write test config: %v", err)
@@ -124,14 +142,170 @@ source:
 		t.Fatalf("failed to load config: %v", err)
 	}
 
-	if cfg.Source.Host != "${TEST_DB_HOST}" {
-		t.Errorf("expected source host '${TEST_DB_HOST}', got %s", cfg.Source.Host)
+	if cfg.Source.Host != "db.internal" {
+		t.Errorf("expected source host 'db.internal', got %s", cfg.Source.Host)
+	}
+	if cfg.Source.User != "archiver" {
+		t.Errorf("expected source user 'archiver', got %s", cfg.Source.User)
+	}
+	if cfg.Source.Password != "s3cr$t" {
+		t.Errorf("expected source password 's3cr$t', got %s", cfg.Source.Password)
 	}
-	if cfg.Source.User != "${TEST_DB_USER}" {
-		t.Errorf("expected source user '${TEST_DB_USER}', got %s", cfg.Source.User)
+	if cfg.Destination.Password != "literal$dollar" {
+		t.Errorf("expected bare $ to be kept, got %s", cfg.Destination.Password)
 	}
-	if cfg.Source.Password != "${TEST_DB_PASS}" {
-		t.Errorf("expected source password '${TEST_DB_PASS}', got %s", cfg.Source.Password)
+	job := cfg.Jobs["job1"]
+	if job.Where != "note = 'archiver'" {
+		t.Errorf("expected expanded where, got %s", job.Where)
+	}
+	if len(job.Relations) != 1 || job.Relations[0].Table != "archiver_items" {
+		t.Errorf("expected expansion inside relations, got %+v", job.Relations)
+	}
+}
+
+func TestLoadFromReader_MissingEnvVar(t *testing.T) {
+	t.Setenv("TEST_DB_HOST", "db.internal")
+
+	_, err := LoadFromReader(strings.NewReader(`
+source:
+  host: ${TEST_DB_HOST}
+  user: ${GOARCHIVE_TEST_UNSET_USER}
+  password: ${GOARCHIVE_TEST_UNSET_PASS}
+destination:
+  password: ${GOARCHIVE_TEST_UNSET_PASS}
+`), "yaml")
+	if err == nil {
+		t.Fatal("expected error for unset environment variables")
+	}
+	want := "GOARCHIVE_TEST_UNSET_PASS, GOARCHIVE_TEST_UNSET_USER"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to list %q, got: %v", want, err)
+	}
+}
+
+func TestLoadFromReader_TOML(t *testing.T) {
+	t.Setenv("TEST_DB_PASS", "tomlpass")
+
+	cfg, err := LoadFromReader(strings.NewReader(`
+[source]
+host = "localhost"
+port = 3306
+password = "${TEST_DB_PASS}"
+
+[processing]
+batch_size = 250
+
+[jobs.archive_orders]
+root_table = "orders"
+primary_key = "id"
+where = "1=1"
+
+[[jobs.archive_orders.relations]]
+table = "order_items"
+primary_key = "id"
+foreign_key = "order_id"
+`), "toml")
+	if err != nil {
+		t.Fatalf("failed to load TOML config: %v", err)
+	}
+	if cfg.Source.Password != "tomlpass" {
+		t.Errorf("expected expanded password, got %s", cfg.Source.Password)
+	}
+	if cfg.Processing.BatchSize != 250 {
+		t.Errorf("expected batch_size 250, got %d", cfg.Processing.BatchSize)
+	}
+	if cfg.Processing.BatchDeleteSize != DefaultConfig().Processing.BatchDeleteSize {
+		t.Errorf("expected default batch_delete_size to survive, got %d", cfg.Processing.BatchDeleteSize)
+	}
+	job := cfg.Jobs["archive_orders"]
+	if len(job.Relations) != 1 || job.Relations[0].ForeignKey != "order_id" {
+		t.Errorf("expected one relation with foreign_key order_id, got %+v", job.Relations)
+	}
+}
+
+func TestLoadFromReader_MaxRuntime(t *testing.T) {
+	cfg, err := LoadFromReader(strings.NewReader(`
+processing:
+  max_runtime: 2h
+jobs:
+  archive_orders:
+    root_table: orders
+    primary_key: id
+    where: "1=1"
+    processing:
+      max_runtime: 90m
+`), "yaml")
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Processing.MaxRuntime != 2*time.Hour {
+		t.Errorf("expected max_runtime 2h, got %s", cfg.Processing.MaxRuntime)
+	}
+	if got := cfg.GetJobProcessing("archive_orders").MaxRuntime; got != 90*time.Minute {
+		t.Errorf("expected job max_runtime 90m, got %s", got)
+	}
+}
+
+func TestLoadFromReader_PhaseTimeouts(t *testing.T) {
+	cfg, err := LoadFromReader(strings.NewReader(`
+processing:
+  discovery_timeout: 30s
+  delete_timeout: 2m
+  statement_timeout: 10s
+jobs:
+  archive_orders:
+    root_table: orders
+    primary_key: id
+    where: "1=1"
+    processing:
+      discovery_timeout: 1m
+      statement_timeout: 5s
+`), "yaml")
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	got := cfg.GetJobProcessing("archive_orders")
+	if got.DiscoveryTimeout != time.Minute || got.DeleteTimeout != 2*time.Minute || got.CopyTimeout != 0 {
+		t.Errorf("unexpected job phase timeouts: discovery=%s copy=%s delete=%s",
+			got.DiscoveryTimeout, got.CopyTimeout, got.DeleteTimeout)
+	}
+	if cfg.Processing.StatementTimeout != 10*time.Second || got.StatementTimeout != 5*time.Second {
+		t.Errorf("unexpected statement_timeout: global=%s job=%s", cfg.Processing.StatementTimeout, got.StatementTimeout)
+	}
+}
+
+func TestLoadFromReader_UnknownKey(t *testing.T) {
+	_, err := LoadFromReader(strings.NewReader(`
+processing:
+  batchsize: 100
+`), "yaml")
+	if err == nil || !strings.Contains(err.Error(), "batchsize") {
+		t.Errorf("expected unknown key error naming batchsize, got: %v", err)
+	}
+}
+
+func TestLoadUnsupportedFormat(t *testing.T) {
+	if _, err := LoadFromReader(strings.NewReader(`{}`), "json"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	// Unknown extensions are read as YAML.
+	path := filepath.Join(t.TempDir(), "archiver.conf")
+	if err := os.WriteFile(path, []byte("source:\n  host: db1\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected .conf to load as YAML, got: %v", err)
+	}
+	if cfg.Source.Host != "db1" {
+		t.Errorf("expected source host db1, got %s", cfg.Source.Host)
+	}
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	if _, err := Load("../../configs/archiver.yaml.example"); err != nil {
+		t.Errorf("example config must load without unknown keys: %v", err)
 	}
 }
 
@@ -281,3 +455,35 @@ func TestApplyOverridesPartial(t *testing.T) {
 		t.Error("expected skip_verify to be true after override")
 	}
 }
+
+func TestSelectJobs(t *testing.T) {
+	cfg := &Config{Jobs: map[string]JobConfig{
+		"sessions": {RootTable: "sessions"},
+		"orders":   {RootTable: "orders"},
+		"logs":     {RootTable: "logs"},
+	}}
+
+	all, err := cfg.SelectJobs(nil)
+	if err != nil {
+		t.Fatalf("SelectJobs(nil) failed: %v", err)
+	}
+	var names []string
+	for _, j := range all {
+		names = append(names, j.Name+"="+j.Job.RootTable)
+	}
+	if got := strings.Join(names, ","); got != "logs=logs,orders=orders,sessions=sessions" {
+		t.Errorf("SelectJobs(nil) = %s", got)
+	}
+
+	picked, err := cfg.SelectJobs([]string{"sessions", "orders"})
+	if err != nil || len(picked) != 2 || picked[0].Name != "sessions" || picked[1].Name != "orders" {
+		t.Errorf("SelectJobs(sessions, orders) = %v, %v", picked, err)
+	}
+
+	if _, err := cfg.SelectJobs([]string{"orders", "missing"}); err == nil {
+		t.Error("expected error for an unknown job")
+	}
+	if _, err := cfg.SelectJobs([]string{"orders", "orders"}); err == nil {
+		t.Error("expected error for a repeated job")
+	}
+}
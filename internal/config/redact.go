@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// redacted replaces secrets in String and MarshalJSON output.
+const redacted = "****"
+
+func maskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// RevealPassword returns the plaintext password. It exists so the few code
+// paths that genuinely need the secret (DSN building) are easy to audit;
+// everything else should format the config, which masks it.
+func (db *DatabaseConfig) RevealPassword() string {
+	return db.Password
+}
+
+// String formats the connection settings with the password masked, so a
+// DatabaseConfig (or a Config embedding one) is safe to pass to a logger or
+// fmt verb such as %v and %+v.
+func (db DatabaseConfig) String() string {
+	replica := "<nil>"
+	if db.ReadReplica != nil {
+		replica = db.ReadReplica.String()
+	}
+	return fmt.Sprintf("DatabaseConfig{Host: %s, Port: %d, User: %s, Password: %s, Database: %s, TLS: %s, ReadReplica: %s}",
+		db.Host, db.Port, db.User, maskSecret(db.Password), db.Database, db.TLS, replica)
+}
+
+// MarshalJSON encodes the config with the password masked. Structured
+// loggers that serialise values reflectively go through this path.
+func (db DatabaseConfig) MarshalJSON() ([]byte, error) {
+	type plain DatabaseConfig // drops methods to avoid recursion
+	masked := plain(db)
+	masked.Password = maskSecret(db.Password)
+	return json.Marshal(masked)
+}
+
+// String formats the replica settings with the password masked.
+func (r ReplicaConfig) String() string {
+	return fmt.Sprintf("ReplicaConfig{Enabled: %t, Host: %s, Port: %d, User: %s, Password: %s, ReplicationChannel: %s}",
+		r.Enabled, r.Host, r.Port, r.User, maskSecret(r.Password), r.ReplicationChannel)
+}
+
+// MarshalJSON encodes the replica settings with the password masked.
+func (r ReplicaConfig) MarshalJSON() ([]byte, error) {
+	type plain ReplicaConfig
+	masked := plain(r)
+	masked.Password = maskSecret(r.Password)
+	return json.Marshal(masked)
+}
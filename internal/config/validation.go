@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dbsmedya/goarchive/internal/sqlutil"
 )
@@ -32,6 +35,7 @@ func (e ValidationErrors) Error() string {
 }
 
 // Validate checks the configuration for required fields and valid values.
+// All problems are collected rather than stopping at the first one.
 func (c *Config) Validate() error {
 	var errors ValidationErrors
 
@@ -59,7 +63,14 @@ func (c *Config) Validate() error {
 			Message: "at least one job must be defined",
 		})
 	}
-	for name, job := range c.Jobs {
+	// Visit jobs in name order so the error list is stable between runs.
+	names := make([]string, 0, len(c.Jobs))
+	for name := range c.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		job := c.Jobs[name]
 		if err := c.validateJob(name, &job); err != nil {
 			errors = append(errors, err...)
 		}
@@ -85,6 +96,13 @@ func (c *Config) Validate() error {
 		errors = append(errors, err...)
 	}
 
+	if c.Health.Port < 0 || c.Health.Port > 65535 {
+		errors = append(errors, ValidationError{
+			Field:   "health.port",
+			Message: "port must be between 1 and 65535, or 0 to disable the health server",
+		})
+	}
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -122,12 +140,9 @@ func (c *Config) validateDatabase(prefix string, db *DatabaseConfig) ValidationE
 		})
 	}
 
-	validTLS := map[string]bool{"disable": true, "preferred": true, "skip-verify": true, "required": true, "": true}
-	if !validTLS[db.TLS] {
-		errors = append(errors, ValidationError{
-			Field:   prefix + ".tls",
-			Message: "tls must be 'disable', 'preferred', 'skip-verify', or 'required'",
-		})
+	errors = append(errors, validateTLS(prefix, db)...)
+	if db.SSHTunnel != nil {
+		errors = append(errors, validateSSHTunnel(prefix+".ssh_tunnel", db.SSHTunnel)...)
 	}
 
 	if db.MaxConnections < 0 {
@@ -144,6 +159,38 @@ func (c *Config) validateDatabase(prefix string, db *DatabaseConfig) ValidationE
 		})
 	}
 
+	if db.MaxConnections > 0 && db.MaxIdleConnections > db.MaxConnections {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".max_idle_connections",
+			Message: fmt.Sprintf("max_idle_connections (%d) cannot exceed max_connections (%d)", db.MaxIdleConnections, db.MaxConnections),
+		})
+	}
+
+	if db.ConnMaxLifetimeSeconds < 0 {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".conn_max_lifetime_seconds",
+			Message: "conn_max_lifetime_seconds cannot be negative",
+		})
+	}
+
+	if db.ConnMaxIdleTimeSeconds < 0 {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".conn_max_idle_time_seconds",
+			Message: "conn_max_idle_time_seconds cannot be negative",
+		})
+	}
+
+	if db.ReadReplica != nil {
+		if prefix != "source" {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".read_replica",
+				Message: "read_replica is only supported on source",
+			})
+		} else {
+			errors = append(errors, c.validateDatabase(prefix+".read_replica", db.ReadReplicaConfig())...)
+		}
+	}
+
 	// job_schema is destination-only; source ignores it.
 	if prefix == "destination" && db.JobSchema != "" && !sqlutil.IsValidIdentifier(db.JobSchema) {
 		errors = append(errors, ValidationError{
@@ -155,6 +202,110 @@ func (c *Config) validateDatabase(prefix string, db *DatabaseConfig) ValidationE
 	return errors
 }
 
+// validateTLS checks the TLS mode and that any certificate files it
+// references exist, so a typo fails validation rather than the first connect.
+func validateTLS(prefix string, db *DatabaseConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	switch db.TLS {
+	case "", TLSDisable, TLSPreferred, TLSSkipVerify, TLSRequired, TLSVerifyCA, TLSVerifyFull:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".tls",
+			Message: "tls must be 'disable', 'preferred', 'skip-verify', 'required', 'verify-ca', or 'verify-full'",
+		})
+		return errors
+	}
+
+	hasFiles := db.TLSCACert != "" || db.TLSClientCert != "" || db.TLSClientKey != ""
+	if hasFiles && (db.TLS == "" || db.TLS == TLSDisable || db.TLS == TLSPreferred) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".tls",
+			Message: "tls_ca_cert, tls_client_cert and tls_client_key require tls 'skip-verify', 'required', 'verify-ca', or 'verify-full'",
+		})
+	}
+
+	if db.TLS == TLSVerifyCA && db.TLSCACert == "" {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".tls_ca_cert",
+			Message: "tls_ca_cert is required when tls is 'verify-ca'",
+		})
+	}
+
+	if (db.TLSClientCert == "") != (db.TLSClientKey == "") {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".tls_client_key",
+			Message: "tls_client_cert and tls_client_key must be set together",
+		})
+	}
+
+	for _, f := range []struct{ field, path string }{
+		{"tls_ca_cert", db.TLSCACert},
+		{"tls_client_cert", db.TLSClientCert},
+		{"tls_client_key", db.TLSClientKey},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   prefix + "." + f.field,
+				Message: fmt.Sprintf("cannot read %s: %v", f.path, err),
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateSSHTunnel checks the bastion settings and that the key and
+// known_hosts files exist.
+func validateSSHTunnel(prefix string, t *SSHTunnelConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if t.Host == "" {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".host",
+			Message: "host is required",
+		})
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".port",
+			Message: "port must be between 1 and 65535 (0 uses 22)",
+		})
+	}
+	if t.User == "" {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".user",
+			Message: "user is required",
+		})
+	}
+	if t.KeyPath == "" {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".key_path",
+			Message: "key_path is required",
+		})
+	}
+
+	for _, f := range []struct{ field, path string }{
+		{"key_path", t.KeyPath},
+		{"known_hosts_file", t.KnownHostsFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   prefix + "." + f.field,
+				Message: fmt.Sprintf("cannot read %s: %v", f.path, err),
+			})
+		}
+	}
+
+	return errors
+}
+
 func (c *Config) validateReplica() ValidationErrors {
 	var errors ValidationErrors
 
@@ -182,6 +333,8 @@ func (c *Config) validateReplica() ValidationErrors {
 	return errors
 }
 
+// validateJob validates job with every field path rooted at jobs.<name>. Job
+// overrides are merged onto c's global settings before they are checked.
 func (c *Config) validateJob(name string, job *JobConfig) ValidationErrors {
 	var errors ValidationErrors
 	prefix := fmt.Sprintf("jobs.%s", name)
@@ -217,6 +370,70 @@ func (c *Config) validateJob(name string, job *JobConfig) ValidationErrors {
 		})
 	}
 
+	if err := validateColumnSelection(prefix+".columns", job.Columns, job.PrimaryKey); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := validateDestinationTable(prefix, job.DestinationTable, job.DestinationDateColumn, job.Columns); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if job.IncrementalColumn != "" && !sqlutil.IsValidIdentifier(job.IncrementalColumn) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".incremental_column",
+			Message: "must contain only alphanumeric characters and underscores",
+		})
+	}
+
+	for _, list := range []struct {
+		key   string
+		stmts []string
+	}{
+		{"pre_sql", job.PreSQL},
+		{"post_sql", job.PostSQL},
+		{"destination_pre_sql", job.DestinationPreSQL},
+		{"destination_post_sql", job.DestinationPostSQL},
+	} {
+		for i, stmt := range list.stmts {
+			if strings.TrimSpace(stmt) == "" {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.%s[%d]", prefix, list.key, i),
+					Message: "statement cannot be empty",
+				})
+			}
+		}
+	}
+
+	if job.PhaseHook != "" {
+		if info, err := os.Stat(job.PhaseHook); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".phase_hook",
+				Message: fmt.Sprintf("cannot read %s: %v", job.PhaseHook, err),
+			})
+		} else if info.IsDir() {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".phase_hook",
+				Message: fmt.Sprintf("%s is a directory, not an executable", job.PhaseHook),
+			})
+		}
+	}
+
+	for i, table := range job.ExcludeTables {
+		field := fmt.Sprintf("%s.exclude_tables[%d]", prefix, i)
+		switch {
+		case !sqlutil.IsValidIdentifier(table):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "must contain only alphanumeric characters and underscores",
+			})
+		case table == job.RootTable:
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "cannot exclude the root table",
+			})
+		}
+	}
+
 	// Validate relations recursively
 	for i, rel := range job.Relations {
 		relPrefix := fmt.Sprintf("%s.relations[%d]", prefix, i)
@@ -239,11 +456,193 @@ func (c *Config) validateJob(name string, job *JobConfig) ValidationErrors {
 		}
 	}
 
+	// skip_existing trusts rows already on the destination; only a SHA256
+	// verification of the full record set proves they match the source.
+	processing := job.GetJobProcessing(c.Processing)
+	if processing.SkipExisting {
+		verification := job.WeakestVerification(job.GetJobVerification(c.Verification))
+		if verification.SkipVerification || verification.EffectiveMethod() != "sha256" {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".processing.skip_existing",
+				Message: "skip_existing requires verification.method: sha256 without skip_verification",
+			})
+		}
+		// The destination lookup reads tables by their source name.
+		if job.HasDestinationTemplate() {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".processing.skip_existing",
+				Message: "skip_existing cannot be used with destination_table",
+			})
+		}
+	}
+	// Only continue_on_error keeps a failing table from aborting the run, so
+	// it is the only mode with failed rows to record.
+	if processing.DeadLetter && !processing.ContinueOnError {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".processing.dead_letter",
+			Message: "dead_letter requires continue_on_error",
+		})
+	}
+
+	// Validate the effective (merged) logging config so errors in job-level
+	// overrides are reported against the job that set them.
+	if job.Logging != nil {
+		merged := job.GetJobLogging(c.Logging)
+		errors = append(errors, validateLoggingConfig(merged, prefix+".logging")...)
+	}
+
 	return errors
 }
 
 const maxRelationDepth = 10
 
+// destinationPlaceholders are the destination_table template placeholders,
+// replaced by sample values that keep a valid template a valid identifier.
+var destinationPlaceholders = strings.NewReplacer("{year}", "2000", "{month}", "01")
+
+// validateDestinationTable checks a table's destination_table template and
+// destination_date_column: the expanded template must be a safe identifier,
+// and the date column needs a template and must be copied.
+func validateDestinationTable(prefix, tmpl, dateColumn string, sel *ColumnSelection) ValidationErrors {
+	var errors ValidationErrors
+	if tmpl != "" && !sqlutil.IsValidIdentifier(destinationPlaceholders.Replace(tmpl)) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".destination_table",
+			Message: "must contain only alphanumeric characters, underscores and the {year} and {month} placeholders",
+		})
+	}
+	if dateColumn == "" {
+		return errors
+	}
+	switch {
+	case tmpl == "":
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".destination_date_column",
+			Message: "destination_date_column requires destination_table",
+		})
+	case !sqlutil.IsValidIdentifier(dateColumn):
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".destination_date_column",
+			Message: "must contain only alphanumeric characters and underscores",
+		})
+	case sel != nil && !selectsColumn(sel, dateColumn):
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".destination_date_column",
+			Message: fmt.Sprintf("column %q must be copied (check columns.include/exclude)", dateColumn),
+		})
+	}
+	return errors
+}
+
+// selectsColumn reports whether sel copies column.
+func selectsColumn(sel *ColumnSelection, column string) bool {
+	if len(sel.Include) > 0 {
+		return containsString(sel.Include, column)
+	}
+	return !containsString(sel.Exclude, column)
+}
+
+// validateColumnSelection checks a table's columns block: include and exclude
+// are mutually exclusive, names must be safe identifiers, the primary key
+// must be copied untransformed (it is how rows are verified, resumed, and
+// deleted), and transforms must be known and apply to copied columns.
+func validateColumnSelection(prefix string, sel *ColumnSelection, pk string) ValidationErrors {
+	if sel == nil {
+		return nil
+	}
+	var errors ValidationErrors
+
+	if len(sel.Include) > 0 && len(sel.Exclude) > 0 {
+		errors = append(errors, ValidationError{
+			Field:   prefix,
+			Message: "set either include or exclude, not both",
+		})
+	}
+
+	lists := map[string][]string{"include": sel.Include, "exclude": sel.Exclude, "verify_ignore": sel.VerifyIgnore}
+	fields := make([]string, 0, len(lists))
+	for field := range lists {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		for _, col := range lists[field] {
+			if !sqlutil.IsValidIdentifier(col) {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.%s", prefix, field),
+					Message: fmt.Sprintf("column %q must contain only alphanumeric characters and underscores", col),
+				})
+			}
+		}
+	}
+
+	if pk != "" && len(sel.Include) > 0 && !containsString(sel.Include, pk) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".include",
+			Message: fmt.Sprintf("must include primary key column %q", pk),
+		})
+	}
+	if pk != "" && containsString(sel.Exclude, pk) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".exclude",
+			Message: fmt.Sprintf("cannot exclude primary key column %q", pk),
+		})
+	}
+	if pk != "" && containsString(sel.VerifyIgnore, pk) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".verify_ignore",
+			Message: fmt.Sprintf("cannot ignore primary key column %q in verification", pk),
+		})
+	}
+
+	columns := make([]string, 0, len(sel.Transform))
+	for col := range sel.Transform {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	for _, col := range columns {
+		field := fmt.Sprintf("%s.transform.%s", prefix, col)
+		switch {
+		case !validTransforms[sel.Transform[col]]:
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("unknown transform %q (must be sha256, redact, or null)", sel.Transform[col]),
+			})
+		case !sqlutil.IsValidIdentifier(col):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "column must contain only alphanumeric characters and underscores",
+			})
+		case strings.EqualFold(col, pk):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "cannot transform the primary key column",
+			})
+		case containsString(sel.Exclude, col) || (len(sel.Include) > 0 && !containsString(sel.Include, col)):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "cannot transform a column that is not copied",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validTransforms lists the built-in column transforms (see archiver.Transforms).
+var validTransforms = map[string]bool{"sha256": true, "redact": true, "null": true}
+
+// containsString reports whether list holds the column name s. MySQL column
+// names are case-insensitive, so the match is too.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) validateRelation(prefix string, rel *Relation, depth int) ValidationErrors {
 	var errors ValidationErrors
 
@@ -299,6 +698,52 @@ func (c *Config) validateRelation(prefix string, rel *Relation, depth int) Valid
 		})
 	}
 
+	if err := validateColumnSelection(prefix+".columns", rel.Columns, rel.PrimaryKey); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if err := validateDestinationTable(prefix, rel.DestinationTable, rel.DestinationDateColumn, rel.Columns); err != nil {
+		errors = append(errors, err...)
+	}
+
+	if rel.UseIndex != "" && !sqlutil.IsValidIdentifier(rel.UseIndex) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".use_index",
+			Message: "must contain only alphanumeric characters and underscores",
+		})
+	}
+
+	if rel.DiscoveryQuery != "" {
+		if !strings.Contains(rel.DiscoveryQuery, ParentPKsPlaceholder) {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".discovery_query",
+				Message: "discovery_query must contain the " + ParentPKsPlaceholder + " placeholder",
+			})
+		}
+		if rel.UseIndex != "" {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".use_index",
+				Message: "use_index cannot be combined with discovery_query; put the index hint in the query",
+			})
+		}
+	}
+
+	if rel.BatchSize < 0 {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".batch_size",
+			Message: "batch_size cannot be negative",
+		})
+	}
+
+	switch rel.VerificationMethod {
+	case "", "count", "sha256", "server_checksum":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".verification_method",
+			Message: "verification_method must be 'count', 'sha256', or 'server_checksum'",
+		})
+	}
+
 	// Validate nested relations
 	for i, nested := range rel.Relations {
 		nestedPrefix := fmt.Sprintf("%s.relations[%d]", prefix, i)
@@ -345,6 +790,87 @@ func (c *Config) validateProcessingConfig(prefix string, processing *ProcessingC
 		})
 	}
 
+	if processing.MaxRowsPerSecond < 0 {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".max_rows_per_second",
+			Message: "max_rows_per_second cannot be negative",
+		})
+	}
+
+	if processing.MaxInClauseSize < 0 || processing.MaxInClauseSize > sqlutil.MaxPlaceholders {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".max_in_clause_size",
+			Message: fmt.Sprintf("max_in_clause_size must be between 0 and %d", sqlutil.MaxPlaceholders),
+		})
+	}
+
+	if processing.MaxRuntime < 0 {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".max_runtime",
+			Message: "max_runtime cannot be negative",
+		})
+	}
+
+	for _, timeout := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"discovery_timeout", processing.DiscoveryTimeout},
+		{"copy_timeout", processing.CopyTimeout},
+		{"verify_timeout", processing.VerifyTimeout},
+		{"delete_timeout", processing.DeleteTimeout},
+		{"statement_timeout", processing.StatementTimeout},
+	} {
+		if timeout.value < 0 {
+			errors = append(errors, ValidationError{
+				Field:   prefix + "." + timeout.field,
+				Message: timeout.field + " cannot be negative",
+			})
+		}
+	}
+
+	validCopyModes := map[string]bool{"": true, "insert": true, "insert-ignore": true, "upsert": true}
+	if !validCopyModes[processing.CopyMode] {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".copy_mode",
+			Message: "copy_mode must be 'insert', 'insert-ignore', or 'upsert'",
+		})
+	}
+
+	switch processing.RootOrder {
+	case "", "asc", "desc":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".root_order",
+			Message: "root_order must be 'asc' or 'desc'",
+		})
+	}
+
+	switch processing.Maintenance {
+	case "", "analyze", "optimize":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".post_copy_maintenance",
+			Message: "post_copy_maintenance must be 'analyze' or 'optimize'",
+		})
+	}
+
+	switch processing.CopyWriter {
+	case "", "insert":
+	case "load-data":
+		if processing.CopyMode == "upsert" {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".copy_writer",
+				Message: "copy_writer 'load-data' cannot be combined with copy_mode 'upsert'",
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".copy_writer",
+			Message: "copy_writer must be 'insert' or 'load-data'",
+		})
+	}
+
 	return errors
 }
 
@@ -365,6 +891,61 @@ func (c *Config) validateSafety() ValidationErrors {
 		})
 	}
 
+	if c.Safety.DestinationFreeSpaceMB < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "safety.destination_free_space_mb",
+			Message: "destination_free_space_mb cannot be negative",
+		})
+	}
+
+	if c.Safety.MaxDeleteRows < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "safety.max_delete_rows",
+			Message: "max_delete_rows cannot be negative",
+		})
+	}
+
+	switch c.Safety.DeleteBackupFormat {
+	case "", "json", "sql":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "safety.delete_backup_format",
+			Message: "delete_backup_format must be 'json' or 'sql'",
+		})
+	}
+
+	switch c.Safety.OrphanCheck {
+	case "", "warn", "abort":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "safety.orphan_check",
+			Message: "orphan_check must be 'warn' or 'abort'",
+		})
+	}
+
+	switch c.Safety.MultiPath {
+	case "", "dedupe", "warn", "error":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "safety.multi_path",
+			Message: "multi_path must be 'dedupe', 'warn' or 'error'",
+		})
+	}
+
+	if c.Safety.SkipCascadedDeletes && c.Safety.DisableSourceForeignKeyChecks {
+		errors = append(errors, ValidationError{
+			Field:   "safety.skip_cascaded_deletes",
+			Message: "skip_cascaded_deletes cannot be combined with disable_source_foreign_key_checks: cascades do not fire while FK checks are off",
+		})
+	}
+
+	if c.Safety.DestinationFreeSpaceMB > 0 && c.Safety.DiskSpaceMargin < 1 {
+		errors = append(errors, ValidationError{
+			Field:   "safety.disk_space_margin",
+			Message: "disk_space_margin must be at least 1.0",
+		})
+	}
+
 	return errors
 }
 
@@ -375,14 +956,30 @@ func (c *Config) validateVerification() ValidationErrors {
 func (c *Config) validateVerificationConfig(prefix string, verification *VerificationConfig, requireMethod bool) ValidationErrors {
 	var errors ValidationErrors
 
-	validMethods := map[string]bool{"count": true, "sha256": true}
+	if verification.GateDeletes && verification.SkipVerification {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".gate_deletes",
+			Message: "gate_deletes requires verification; it cannot be combined with skip_verification or --skip-verify",
+		})
+	}
+
+	for _, col := range verification.IgnoreColumns {
+		if !sqlutil.IsValidIdentifier(col) {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".ignore_columns",
+				Message: fmt.Sprintf("column %q must contain only alphanumeric characters and underscores", col),
+			})
+		}
+	}
+
+	validMethods := map[string]bool{"count": true, "sha256": true, "server_checksum": true}
 	if !requireMethod && verification.Method == "" {
 		return errors
 	}
 	if !validMethods[verification.Method] {
 		errors = append(errors, ValidationError{
 			Field:   prefix + ".method",
-			Message: "method must be 'count' or 'sha256'",
+			Message: "method must be 'count', 'sha256', or 'server_checksum'",
 		})
 	}
 
@@ -390,19 +987,7 @@ func (c *Config) validateVerificationConfig(prefix string, verification *Verific
 }
 
 func (c *Config) validateLogging() ValidationErrors {
-	errors := validateLoggingConfig(c.Logging, "logging")
-
-	// Validate each job's effective (merged) logging config so errors in
-	// job-level overrides are reported against the job that set them.
-	for name, job := range c.Jobs {
-		if job.Logging == nil {
-			continue
-		}
-		merged := job.GetJobLogging(c.Logging)
-		errors = append(errors, validateLoggingConfig(merged, "jobs."+name+".logging")...)
-	}
-
-	return errors
+	return validateLoggingConfig(c.Logging, "logging")
 }
 
 func validateLoggingConfig(lc LoggingConfig, prefix string) ValidationErrors {
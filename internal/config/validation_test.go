@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidConfig(t *testing.T) {
@@ -672,6 +676,447 @@ func TestCheckIntervalZeroWithReplicaEnabled(t *testing.T) {
 	}
 }
 
+func TestDiskSpaceSettingsValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	cfg.Safety.DestinationFreeSpaceMB = 1024
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected default margin to validate, got: %v", err)
+	}
+
+	cfg.Safety.DiskSpaceMargin = 0.5
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "safety.disk_space_margin") {
+		t.Errorf("expected error about safety.disk_space_margin, got: %v", err)
+	}
+
+	cfg.Safety.DiskSpaceMargin = 1.2
+	cfg.Safety.DestinationFreeSpaceMB = -1
+	err = cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "safety.destination_free_space_mb") {
+		t.Errorf("expected error about safety.destination_free_space_mb, got: %v", err)
+	}
+}
+
+func TestOrphanCheckValidation(t *testing.T) {
+	for _, mode := range []string{"", "warn", "abort", "fail"} {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+		}
+		cfg.Safety.OrphanCheck = mode
+
+		err := cfg.Validate()
+		if mode == "fail" {
+			if err == nil || !strings.Contains(err.Error(), "safety.orphan_check") {
+				t.Errorf("orphan_check=%q: expected error about safety.orphan_check, got: %v", mode, err)
+			}
+		} else if err != nil {
+			t.Errorf("orphan_check=%q: unexpected error: %v", mode, err)
+		}
+	}
+}
+
+func TestValidate_ColumnSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		root      *ColumnSelection
+		relation  *ColumnSelection
+		wantField string // empty = valid
+	}{
+		{"no selection", nil, nil, ""},
+		{"root exclude", &ColumnSelection{Exclude: []string{"password_hash"}}, nil, ""},
+		{"relation include with pk", nil, &ColumnSelection{Include: []string{"id", "order_id"}}, ""},
+		{"include and exclude", &ColumnSelection{Include: []string{"id"}, Exclude: []string{"name"}}, nil, "jobs.test_job.columns"},
+		{"include without pk", nil, &ColumnSelection{Include: []string{"order_id"}}, "relations[0].columns.include"},
+		{"exclude pk", &ColumnSelection{Exclude: []string{"id"}}, nil, "jobs.test_job.columns.exclude"},
+		{"exclude pk other case", &ColumnSelection{Exclude: []string{"ID"}}, nil, "jobs.test_job.columns.exclude"},
+		{"include pk other case", nil, &ColumnSelection{Include: []string{"Id", "order_id"}}, ""},
+		{"transform pk other case", nil, &ColumnSelection{Transform: map[string]string{"ID": "null"}}, "relations[0].columns.transform.ID"},
+		{"invalid column name", nil, &ColumnSelection{Exclude: []string{"a`b"}}, "relations[0].columns.exclude"},
+		{"transform", &ColumnSelection{Transform: map[string]string{"email": "sha256"}}, nil, ""},
+		{"unknown transform", &ColumnSelection{Transform: map[string]string{"email": "md5"}}, nil, "jobs.test_job.columns.transform.email"},
+		{"transform pk", nil, &ColumnSelection{Transform: map[string]string{"id": "null"}}, "relations[0].columns.transform.id"},
+		{"transform excluded column", &ColumnSelection{Exclude: []string{"email"}, Transform: map[string]string{"email": "redact"}}, nil, "jobs.test_job.columns.transform.email"},
+		{"verify_ignore", nil, &ColumnSelection{VerifyIgnore: []string{"updated_at"}}, ""},
+		{"verify_ignore pk", &ColumnSelection{VerifyIgnore: []string{"id"}}, nil, "jobs.test_job.columns.verify_ignore"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+			cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+			cfg.Jobs = map[string]JobConfig{
+				"test_job": {
+					RootTable: "orders", PrimaryKey: "id", Where: "1=1", Columns: tt.root,
+					Relations: []Relation{
+						{Table: "items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N", Columns: tt.relation},
+					},
+				},
+			}
+
+			err := cfg.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected valid config, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("expected error about %s, got: %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestMaxInClauseSizeValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	cfg.Processing.MaxInClauseSize = 1000
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	for _, n := range []int{-1, 70000} {
+		cfg.Processing.MaxInClauseSize = n
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), "processing.max_in_clause_size") {
+			t.Errorf("max_in_clause_size=%d: expected validation error, got: %v", n, err)
+		}
+	}
+}
+
+func TestConnectionPoolValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(db *DatabaseConfig)
+		wantField string // empty = valid
+	}{
+		{"defaults", func(db *DatabaseConfig) {}, ""},
+		{"idle equals open", func(db *DatabaseConfig) { db.MaxConnections, db.MaxIdleConnections = 4, 4 }, ""},
+		{"idle exceeds open", func(db *DatabaseConfig) { db.MaxConnections, db.MaxIdleConnections = 4, 8 }, "source.max_idle_connections"},
+		{"negative lifetime", func(db *DatabaseConfig) { db.ConnMaxLifetimeSeconds = -1 }, "source.conn_max_lifetime_seconds"},
+		{"negative idle time", func(db *DatabaseConfig) { db.ConnMaxIdleTimeSeconds = -1 }, "source.conn_max_idle_time_seconds"},
+		{"read replica", func(db *DatabaseConfig) { db.ReadReplica = &DatabaseConfig{Host: "replica"} }, ""},
+		{"read replica missing host", func(db *DatabaseConfig) { db.ReadReplica = &DatabaseConfig{} }, "source.read_replica.host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+			cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+			cfg.Jobs = map[string]JobConfig{
+				"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+			}
+			tt.mutate(&cfg.Source)
+
+			err := cfg.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected valid config, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("expected error about %s, got: %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestRelationUseIndexValidation(t *testing.T) {
+	for _, tt := range []struct {
+		index   string
+		wantErr bool
+	}{
+		{"", false},
+		{"idx_order_id", false},
+		{"idx`; DROP", true},
+	} {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {
+				RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+				Relations: []Relation{
+					{Table: "items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N", UseIndex: tt.index},
+				},
+			},
+		}
+
+		err := cfg.Validate()
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "relations[0].use_index") {
+				t.Errorf("use_index=%q: expected error about use_index, got: %v", tt.index, err)
+			}
+		} else if err != nil {
+			t.Errorf("use_index=%q: expected valid config, got: %v", tt.index, err)
+		}
+	}
+}
+
+func TestRelationDiscoveryQueryValidation(t *testing.T) {
+	for _, tt := range []struct {
+		query, index string
+		wantErr      string
+	}{
+		{"", "", ""},
+		{"SELECT id FROM items WHERE kind = 'Order' AND owner_id IN ({parent_pks})", "", ""},
+		{"SELECT id FROM items WHERE kind = 'Order'", "", "relations[0].discovery_query"},
+		{"SELECT id FROM items WHERE owner_id IN ({parent_pks})", "idx_owner", "relations[0].use_index"},
+	} {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {
+				RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+				Relations: []Relation{
+					{Table: "items", PrimaryKey: "id", ForeignKey: "owner_id", DependencyType: "1-N",
+						DiscoveryQuery: tt.query, UseIndex: tt.index},
+				},
+			},
+		}
+
+		err := cfg.Validate()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("discovery_query=%q: expected error about %s, got: %v", tt.query, tt.wantErr, err)
+			}
+		} else if err != nil {
+			t.Errorf("discovery_query=%q: expected valid config, got: %v", tt.query, err)
+		}
+	}
+}
+
+func TestGateDeletesValidation(t *testing.T) {
+	on := true
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+	cfg.Verification.GateDeletes = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("gate_deletes with verification should be valid, got: %v", err)
+	}
+
+	cfg.ApplyOverrides("", "", true) // --skip-verify
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "verification.gate_deletes") {
+		t.Errorf("expected error about verification.gate_deletes, got: %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Verification.SkipVerification = true
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+			Verification: &VerificationOverrides{GateDeletes: &on}},
+	}
+	err = cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "jobs.test_job.verification.gate_deletes") {
+		t.Errorf("expected error about jobs.test_job.verification.gate_deletes, got: %v", err)
+	}
+}
+
+func TestVerificationIgnoreColumnsValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Verification.IgnoreColumns = []string{"updated_at"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+			Verification: &VerificationOverrides{IgnoreColumns: []string{"synced_at"}}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("ignore_columns should be valid, got: %v", err)
+	}
+	if got := cfg.GetJobVerification("test_job").IgnoreColumns; !reflect.DeepEqual(got, []string{"synced_at"}) {
+		t.Errorf("job ignore_columns should replace the global list, got %v", got)
+	}
+
+	cfg.Jobs["test_job"] = JobConfig{RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+		Verification: &VerificationOverrides{IgnoreColumns: []string{"a`b"}}}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "jobs.test_job.verification.ignore_columns") {
+		t.Errorf("expected error about jobs.test_job.verification.ignore_columns, got: %v", err)
+	}
+}
+
+func TestRelationBatchSizeValidation(t *testing.T) {
+	for _, tt := range []struct {
+		size    int
+		wantErr bool
+	}{
+		{0, false},
+		{50, false},
+		{-1, true},
+	} {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {
+				RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+				Relations: []Relation{
+					{Table: "items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N", BatchSize: tt.size},
+				},
+			},
+		}
+
+		err := cfg.Validate()
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "relations[0].batch_size") {
+				t.Errorf("batch_size=%d: expected error about batch_size, got: %v", tt.size, err)
+			}
+		} else if err != nil {
+			t.Errorf("batch_size=%d: expected valid config, got: %v", tt.size, err)
+		}
+	}
+}
+
+func TestReadReplicaSourceOnly(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst",
+		ReadReplica: &DatabaseConfig{Host: "replica"}}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "destination.read_replica") {
+		t.Errorf("expected error about destination.read_replica, got: %v", err)
+	}
+}
+
+func TestCopyModeValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	for _, mode := range []string{"", "insert", "insert-ignore", "upsert"} {
+		cfg.Processing.CopyMode = mode
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("copy_mode=%q: expected valid config, got: %v", mode, err)
+		}
+	}
+
+	cfg.Processing.CopyMode = "replace"
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "processing.copy_mode") {
+		t.Errorf("expected error about processing.copy_mode, got: %v", err)
+	}
+}
+
+func TestCopyWriterValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	for _, writer := range []string{"", "insert", "load-data"} {
+		cfg.Processing.CopyWriter = writer
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("copy_writer=%q: expected valid config, got: %v", writer, err)
+		}
+	}
+
+	cfg.Processing.CopyWriter = "bulk"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "processing.copy_writer") {
+		t.Errorf("expected error about processing.copy_writer, got: %v", err)
+	}
+
+	cfg.Processing.CopyWriter = "load-data"
+	cfg.Processing.CopyMode = "upsert"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("expected load-data/upsert conflict, got: %v", err)
+	}
+}
+
+func TestRootOrderValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	for _, order := range []string{"", "asc", "desc"} {
+		cfg.Processing.RootOrder = order
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("root_order=%q: expected valid config, got: %v", order, err)
+		}
+	}
+
+	cfg.Processing.RootOrder = "random"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "processing.root_order") {
+		t.Errorf("expected error about processing.root_order, got: %v", err)
+	}
+
+	cfg.Processing.RootOrder = ""
+	desc := "DESC"
+	cfg.Jobs["test_job"] = JobConfig{RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+		Processing: &ProcessingOverrides{RootOrder: &desc}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jobs.test_job.processing.root_order") {
+		t.Errorf("expected error about the job's root_order, got: %v", err)
+	}
+}
+
+func TestPostCopyMaintenanceValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	for _, op := range []string{"", "analyze", "optimize"} {
+		cfg.Processing.Maintenance = op
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("post_copy_maintenance=%q: expected valid config, got: %v", op, err)
+		}
+	}
+
+	cfg.Processing.Maintenance = "analyze"
+	repair := "repair"
+	cfg.Jobs["test_job"] = JobConfig{RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+		Processing: &ProcessingOverrides{Maintenance: &repair}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jobs.test_job.processing.post_copy_maintenance") {
+		t.Errorf("expected error about the job's post_copy_maintenance, got: %v", err)
+	}
+
+	none := ""
+	cfg.Jobs["test_job"] = JobConfig{RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+		Processing: &ProcessingOverrides{Maintenance: &none}}
+	if got := cfg.GetJobProcessing("test_job").Maintenance; got != "" {
+		t.Errorf("job override should disable maintenance, got %q", got)
+	}
+}
+
 func TestValidate_RelationMaxDepthExceeded(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Password: "pass", Database: "src"}
@@ -961,3 +1406,532 @@ func TestJobLoggingValidation(t *testing.T) {
 		}
 	})
 }
+
+func TestValidate_JobFieldPaths(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	validJob := func() *JobConfig {
+		return &JobConfig{
+			RootTable:  "orders",
+			PrimaryKey: "id",
+			Where:      "1=1",
+			Relations: []Relation{
+				{
+					Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id",
+					Relations: []Relation{
+						{Table: "item_notes", PrimaryKey: "id", ForeignKey: "item_id"},
+						{Table: "item_tags", PrimaryKey: "id", ForeignKey: "item_id"},
+					},
+				},
+			},
+		}
+	}
+
+	validate := func(job *JobConfig) error {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "testdb"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3307, User: "root", Database: "archivedb"}
+		cfg.Jobs = map[string]JobConfig{"job": *job}
+		return cfg.Validate()
+	}
+
+	if err := validate(validJob()); err != nil {
+		t.Fatalf("expected valid job, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(job *JobConfig)
+		field  string
+	}{
+		{"missing root_table", func(j *JobConfig) { j.RootTable = "" }, "jobs.job.root_table"},
+		{"missing primary_key", func(j *JobConfig) { j.PrimaryKey = "" }, "jobs.job.primary_key"},
+		{"missing where", func(j *JobConfig) { j.Where = " " }, "jobs.job.where"},
+		{"missing relation table", func(j *JobConfig) { j.Relations[0].Table = "" }, "jobs.job.relations[0].table"},
+		{"missing nested foreign_key", func(j *JobConfig) { j.Relations[0].Relations[1].ForeignKey = "" }, "jobs.job.relations[0].relations[1].foreign_key"},
+		{"missing nested primary_key", func(j *JobConfig) { j.Relations[0].Relations[0].PrimaryKey = "" }, "jobs.job.relations[0].relations[0].primary_key"},
+		{"bad dependency_type", func(j *JobConfig) { j.Relations[0].DependencyType = "N-N" }, "jobs.job.relations[0].dependency_type"},
+		{"zero batch_size", func(j *JobConfig) { j.Processing = &ProcessingOverrides{BatchSize: intPtr(0)} }, "jobs.job.processing.batch_size"},
+		{"negative batch_delete_size", func(j *JobConfig) { j.Processing = &ProcessingOverrides{BatchDeleteSize: intPtr(-1)} }, "jobs.job.processing.batch_delete_size"},
+		{"bad verification method", func(j *JobConfig) { j.Verification = &VerificationOverrides{Method: "md5"} }, "jobs.job.verification.method"},
+		{"bad logging level", func(j *JobConfig) { j.Logging = &LoggingConfig{Level: "trace"} }, "jobs.job.logging.level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := validJob()
+			tt.mutate(job)
+			err := validate(job)
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("expected ValidationErrors, got %T", err)
+			}
+			found := false
+			for _, ve := range verrs {
+				if ve.Field == tt.field {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error for %s, got: %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestValidate_AggregatesAllErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Port: 3306, User: "root", Database: "testdb", TLS: "always"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3307, User: "root", Database: "archivedb"}
+	cfg.Processing.BatchSize = 0
+	cfg.Verification.Method = "crc"
+	cfg.Jobs = map[string]JobConfig{
+		"b_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+			Relations: []Relation{{Table: "items", PrimaryKey: "id"}}},
+		"a_job": {PrimaryKey: "id", Where: "1=1"},
+	}
+
+	err := cfg.Validate()
+	verrs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors, got %T (%v)", err, err)
+	}
+
+	var fields []string
+	for _, ve := range verrs {
+		fields = append(fields, ve.Field)
+	}
+	want := []string{
+		"source.host",
+		"source.tls",
+		"jobs.a_job.root_table",
+		"jobs.b_job.relations[0].foreign_key",
+		"processing.batch_size",
+		"verification.method",
+	}
+	if fmt.Sprint(fields) != fmt.Sprint(want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestTLSCertificateValidation(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caPath, []byte("placeholder"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name    string
+		db      DatabaseConfig
+		wantErr string // field expected in the error; "" means valid
+	}{
+		{"verify-full with CA", DatabaseConfig{TLS: "verify-full", TLSCACert: caPath}, ""},
+		{"verify-full system roots", DatabaseConfig{TLS: "verify-full"}, ""},
+		{"verify-ca with CA", DatabaseConfig{TLS: "verify-ca", TLSCACert: caPath}, ""},
+		{"verify-ca without CA", DatabaseConfig{TLS: "verify-ca"}, "source.tls_ca_cert"},
+		{"missing CA file", DatabaseConfig{TLS: "required", TLSCACert: missing}, "source.tls_ca_cert"},
+		{"missing client key file", DatabaseConfig{TLS: "required", TLSClientCert: caPath, TLSClientKey: missing}, "source.tls_client_key"},
+		{"client cert without key", DatabaseConfig{TLS: "required", TLSClientCert: caPath}, "source.tls_client_key"},
+		{"certs with preferred", DatabaseConfig{TLS: "preferred", TLSCACert: caPath}, "source.tls"},
+		{"unknown mode", DatabaseConfig{TLS: "verify"}, "source.tls"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.db
+			db.Host, db.Port, db.User, db.Database = "localhost", 3306, "root", "testdb"
+			errs := validateTLS("source", &db)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			found := false
+			for _, e := range errs {
+				if e.Field == tt.wantErr {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error on %s, got %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestSSHTunnelValidation(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyPath, []byte("placeholder"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name    string
+		tunnel  SSHTunnelConfig
+		wantErr string // field expected in the error; "" means valid
+	}{
+		{"valid with default port", SSHTunnelConfig{Host: "bastion", User: "jump", KeyPath: keyPath}, ""},
+		{"missing host", SSHTunnelConfig{User: "jump", KeyPath: keyPath}, "source.ssh_tunnel.host"},
+		{"missing user", SSHTunnelConfig{Host: "bastion", KeyPath: keyPath}, "source.ssh_tunnel.user"},
+		{"missing key_path", SSHTunnelConfig{Host: "bastion", User: "jump"}, "source.ssh_tunnel.key_path"},
+		{"unreadable key", SSHTunnelConfig{Host: "bastion", User: "jump", KeyPath: missing}, "source.ssh_tunnel.key_path"},
+		{"unreadable known_hosts", SSHTunnelConfig{Host: "bastion", User: "jump", KeyPath: keyPath, KnownHostsFile: missing}, "source.ssh_tunnel.known_hosts_file"},
+		{"bad port", SSHTunnelConfig{Host: "bastion", Port: 70000, User: "jump", KeyPath: keyPath}, "source.ssh_tunnel.port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateSSHTunnel("source.ssh_tunnel", &tt.tunnel)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			found := false
+			for _, e := range errs {
+				if e.Field == tt.wantErr {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error on %s, got %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestMaxRuntimeValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	cfg.Processing.MaxRuntime = 2 * time.Hour
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got: %v", err)
+	}
+
+	cfg.Processing.MaxRuntime = -time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "processing.max_runtime") {
+		t.Errorf("expected error about max_runtime, got: %v", err)
+	}
+
+	cfg.Processing.MaxRuntime = 0
+	cfg.Processing.VerifyTimeout = -time.Second
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "processing.verify_timeout") {
+		t.Errorf("expected error about verify_timeout, got: %v", err)
+	}
+}
+
+func TestSkipExistingRequiresSHA256(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+	cfg.Processing.SkipExisting = true
+
+	cfg.Verification.Method = "count"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jobs.test_job.processing.skip_existing") {
+		t.Errorf("expected skip_existing error under count verification, got: %v", err)
+	}
+
+	cfg.Verification.Method = "sha256"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config with sha256, got: %v", err)
+	}
+
+	cfg.Verification.SkipVerification = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "skip_existing") {
+		t.Errorf("expected skip_existing error with skip_verification, got: %v", err)
+	}
+}
+
+func TestRelationVerificationMethod(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Verification.Method = "sha256"
+	job := JobConfig{
+		RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+		Relations: []Relation{{
+			Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id",
+			Relations: []Relation{{Table: "item_notes", PrimaryKey: "id", ForeignKey: "item_id", VerificationMethod: "md5"}},
+		}},
+	}
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jobs.test_job.relations[0].relations[0].verification_method") {
+		t.Errorf("expected verification_method error, got: %v", err)
+	}
+
+	job.Relations[0].Relations[0].VerificationMethod = "count"
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got: %v", err)
+	}
+	if got := job.WeakestVerification(job.GetJobVerification(cfg.Verification)).EffectiveMethod(); got != "count" {
+		t.Errorf("WeakestVerification method = %q, want count", got)
+	}
+
+	// skip_existing needs SHA256 on every table.
+	cfg.Processing.SkipExisting = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "skip_existing") {
+		t.Errorf("expected skip_existing error with a count relation, got: %v", err)
+	}
+
+	job.Relations[0].Relations[0].VerificationMethod = "sha256"
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config with sha256 everywhere, got: %v", err)
+	}
+	if got := job.WeakestVerification(job.GetJobVerification(cfg.Verification)).EffectiveMethod(); got != "sha256" {
+		t.Errorf("WeakestVerification method = %q, want sha256", got)
+	}
+
+	// server_checksum is content-aware but weaker than SHA256.
+	job.Relations[0].Relations[0].VerificationMethod = "server_checksum"
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "skip_existing") {
+		t.Errorf("expected skip_existing error with a server_checksum relation, got: %v", err)
+	}
+	if got := job.WeakestVerification(job.GetJobVerification(cfg.Verification)).EffectiveMethod(); got != "server_checksum" {
+		t.Errorf("WeakestVerification method = %q, want server_checksum", got)
+	}
+}
+
+func TestDestinationTableValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	validJob := func() JobConfig {
+		return JobConfig{
+			RootTable: "orders", PrimaryKey: "id", Where: "1=1",
+			DestinationTable: "orders_{year}{month}",
+			Relations: []Relation{{
+				Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id",
+				DestinationTable: "order_items_{year}", DestinationDateColumn: "created_at",
+			}},
+		}
+	}
+
+	cfg.Jobs = map[string]JobConfig{"test_job": validJob()}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*JobConfig)
+		field  string
+	}{
+		{"unknown placeholder", func(j *JobConfig) { j.DestinationTable = "orders_{day}" }, "jobs.test_job.destination_table"},
+		{"unsafe name", func(j *JobConfig) { j.Relations[0].DestinationTable = "items`_{year}" }, "jobs.test_job.relations[0].destination_table"},
+		{"date column without template", func(j *JobConfig) {
+			j.DestinationTable = ""
+			j.DestinationDateColumn = "created_at"
+		}, "jobs.test_job.destination_date_column"},
+		{"date column not copied", func(j *JobConfig) {
+			j.Relations[0].Columns = &ColumnSelection{Exclude: []string{"created_at"}}
+		}, "jobs.test_job.relations[0].destination_date_column"},
+		{"skip_existing", func(j *JobConfig) {
+			skip := true
+			j.Processing = &ProcessingOverrides{SkipExisting: &skip}
+			j.Verification = &VerificationOverrides{Method: "sha256"}
+		}, "jobs.test_job.processing.skip_existing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := validJob()
+			tt.modify(&job)
+			cfg.Jobs = map[string]JobConfig{"test_job": job}
+			if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("expected %s error, got: %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestIncrementalColumnValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	job := JobConfig{RootTable: "orders", PrimaryKey: "id", Where: "1=1", IncrementalColumn: "updated_at"}
+
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	job.IncrementalColumn = "updated_at; DROP TABLE orders"
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jobs.test_job.incremental_column") {
+		t.Errorf("expected incremental_column error, got: %v", err)
+	}
+}
+
+func TestExcludeTablesValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	job := JobConfig{RootTable: "users", PrimaryKey: "id", Where: "1=1", ExcludeTables: []string{"audit_log"}}
+
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	job.ExcludeTables = []string{"audit_log", "users", "bad-name"}
+	cfg.Jobs = map[string]JobConfig{"test_job": job}
+	err := cfg.Validate()
+	for _, field := range []string{"jobs.test_job.exclude_tables[1]", "jobs.test_job.exclude_tables[2]"} {
+		if err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("expected %s error, got: %v", field, err)
+		}
+	}
+}
+
+func TestPhaseHookValidation(t *testing.T) {
+	dir := t.TempDir()
+	hook := filepath.Join(dir, "hook.sh")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	for _, tt := range []struct {
+		hook    string
+		wantErr bool
+	}{
+		{hook, false},
+		{filepath.Join(dir, "missing.sh"), true},
+		{dir, true},
+	} {
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {RootTable: "users", PrimaryKey: "id", Where: "1=1", PhaseHook: tt.hook},
+		}
+		err := cfg.Validate()
+		if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "jobs.test_job.phase_hook")) {
+			t.Errorf("phase_hook %s: expected jobs.test_job.phase_hook error, got: %v", tt.hook, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("phase_hook %s: unexpected error: %v", tt.hook, err)
+		}
+	}
+}
+
+func TestSkipCascadedDeletesRequiresForeignKeyChecks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+	cfg.Safety.SkipCascadedDeletes = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("skip_cascaded_deletes alone: unexpected error: %v", err)
+	}
+
+	// Destination-only FK checks do not affect source cascades.
+	cfg.Safety.DisableForeignKeyChecks = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("skip_cascaded_deletes with disable_foreign_key_checks: unexpected error: %v", err)
+	}
+
+	cfg.Safety.DisableSourceForeignKeyChecks = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "safety.skip_cascaded_deletes") {
+		t.Errorf("expected error about safety.skip_cascaded_deletes, got: %v", err)
+	}
+}
+
+func TestMultiPathValidation(t *testing.T) {
+	for _, mode := range []string{"", "dedupe", "warn", "error", "ignore"} {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+		}
+		cfg.Safety.MultiPath = mode
+
+		err := cfg.Validate()
+		if mode == "ignore" {
+			if err == nil || !strings.Contains(err.Error(), "safety.multi_path") {
+				t.Errorf("multi_path=%q: expected error about safety.multi_path, got: %v", mode, err)
+			}
+		} else if err != nil {
+			t.Errorf("multi_path=%q: unexpected error: %v", mode, err)
+		}
+	}
+}
+
+func TestHealthPortValidation(t *testing.T) {
+	for port, wantErr := range map[int]bool{0: false, 8080: false, -1: true, 70000: true} {
+		cfg := DefaultConfig()
+		cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+		cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+		cfg.Jobs = map[string]JobConfig{
+			"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+		}
+		cfg.Health.Port = port
+
+		err := cfg.Validate()
+		if wantErr && (err == nil || !strings.Contains(err.Error(), "health.port")) {
+			t.Errorf("health.port=%d: expected error about health.port, got: %v", port, err)
+		} else if !wantErr && err != nil {
+			t.Errorf("health.port=%d: unexpected error: %v", port, err)
+		}
+	}
+	if got := (HealthConfig{Address: "127.0.0.1", Port: 9090}).ListenAddr(); got != "127.0.0.1:9090" {
+		t.Errorf("ListenAddr() = %q", got)
+	}
+}
+
+func TestDeadLetterRequiresContinueOnError(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	cfg.Processing.DeadLetter = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "jobs.test_job.processing.dead_letter") {
+		t.Errorf("expected error about dead_letter, got: %v", err)
+	}
+
+	cfg.Processing.ContinueOnError = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got: %v", err)
+	}
+}
+
+func TestDeleteBackupFormatValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Source = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "src"}
+	cfg.Destination = DatabaseConfig{Host: "localhost", Port: 3306, User: "root", Database: "dst"}
+	cfg.Jobs = map[string]JobConfig{
+		"test_job": {RootTable: "orders", PrimaryKey: "id", Where: "1=1"},
+	}
+
+	for _, format := range []string{"", "json", "sql"} {
+		cfg.Safety.DeleteBackupFormat = format
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("delete_backup_format=%q: expected valid config, got: %v", format, err)
+		}
+	}
+	cfg.Safety.DeleteBackupFormat = "csv"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "safety.delete_backup_format") {
+		t.Errorf("expected error about delete_backup_format, got: %v", err)
+	}
+}
@@ -20,6 +20,10 @@ const (
 	defaultMaxIdleConns = 5
 	defaultConnMaxLife  = 10 * time.Minute
 	defaultConnMaxIdle  = 5 * time.Minute
+
+	defaultConnectAttempts = 5
+	defaultConnectBackoff  = time.Second
+	maxConnectBackoff      = 10 * time.Second
 )
 
 // Manager handles database connections for source, destination, and replica.
@@ -27,7 +31,35 @@ type Manager struct {
 	Source      *sql.DB
 	Destination *sql.DB
 	Replica     *sql.DB
-	config      *config.Config
+	// SourceRead is the source read replica (source.read_replica), nil when
+	// not configured. Use ReadSource rather than reading it directly.
+	SourceRead *sql.DB
+	config     *config.Config
+
+	// open creates one unverified pool (default: connect); tests substitute
+	// a fake driver.
+	open func(name string, cfg *config.DatabaseConfig) (*sql.DB, error)
+	// connectTries and connectBackoff override the attempts per connection
+	// and the first retry delay (doubled up to maxConnectBackoff); 0 keeps
+	// defaultConnectAttempts and defaultConnectBackoff. Tests shorten them.
+	connectTries   int
+	connectBackoff time.Duration
+
+	// tunnels holds the SSH tunnel of each connection label that has one;
+	// retries reuse it and Close ends it. sshConnect performs the SSH
+	// handshake (default: dialSSH); tests substitute a fake.
+	tunnels    map[string]*sshTunnel
+	sshConnect sshConnectFunc
+}
+
+// ReadSource returns the handle for read-only source queries (discovery and
+// source-side verification): the read replica when configured, otherwise the
+// primary. Writes must always go through Source.
+func (m *Manager) ReadSource() *sql.DB {
+	if m.SourceRead != nil {
+		return m.SourceRead
+	}
+	return m.Source
 }
 
 // NewManager creates a new database manager from configuration.
@@ -66,6 +98,18 @@ func (m *Manager) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to connect to destination database: %w", err)
 	}
 
+	// Connect to source read replica if configured
+	if readCfg := m.config.Source.ReadReplicaConfig(); readCfg != nil {
+		m.SourceRead, err = m.connectWithRetry(ctx, "source read replica", readCfg)
+		if err != nil {
+			_ = m.Source.Close()      // Ignore error during cleanup of failed connection
+			_ = m.Destination.Close() // Ignore error during cleanup of failed connection
+			m.Source = nil
+			m.Destination = nil
+			return fmt.Errorf("failed to connect to source read replica: %w", err)
+		}
+	}
+
 	// Connect to replica if enabled
 	if m.config.Replica.Enabled {
 		replicaCfg := &config.DatabaseConfig{
@@ -78,8 +122,12 @@ func (m *Manager) Connect(ctx context.Context) error {
 		if err != nil {
 			_ = m.Source.Close()      // Ignore error during cleanup of failed connection
 			_ = m.Destination.Close() // Ignore error during cleanup of failed connection
+			if m.SourceRead != nil {
+				_ = m.SourceRead.Close() // Ignore error during cleanup of failed connection
+			}
 			m.Source = nil
 			m.Destination = nil
+			m.SourceRead = nil
 			return fmt.Errorf("failed to connect to replica database: %w", err)
 		}
 	}
@@ -87,42 +135,98 @@ func (m *Manager) Connect(ctx context.Context) error {
 	return nil
 }
 
-// connectWithRetry attempts to connect with exponential backoff.
+// connectWithRetry opens and pings a connection, retrying with exponential
+// backoff while the server is not ready (refused connections, DNS failures,
+// startup races). Permanent errors such as failed authentication are
+// returned at once, and the wait between attempts stops when ctx is done.
 func (m *Manager) connectWithRetry(ctx context.Context, name string, cfg *config.DatabaseConfig) (*sql.DB, error) {
-	var db *sql.DB
+	open := m.open
+	if open == nil {
+		open = m.connect
+	}
+	maxAttempts := m.connectTries
+	if maxAttempts <= 0 {
+		maxAttempts = defaultConnectAttempts
+	}
+	backoff := m.connectBackoff
+	if backoff <= 0 {
+		backoff = defaultConnectBackoff
+	}
+
 	var err error
+	for attempt := 1; ; attempt++ {
+		// open does not dial; its errors are configuration errors (bad TLS
+		// files, DSN) that no retry fixes.
+		db, openErr := open(name, cfg)
+		if openErr != nil {
+			return nil, openErr
+		}
+		if err = db.PingContext(ctx); err == nil {
+			return db, nil
+		}
+		_ = db.Close()
 
-	maxRetries := 3
-	backoff := time.Second
-
-	for i := 0; i < maxRetries; i++ {
-		db, err = m.connect(cfg)
-		if err == nil {
-			// Verify connection
-			if pingErr := db.PingContext(ctx); pingErr == nil {
-				return db, nil
-			} else {
-				_ = db.Close()
-				err = pingErr
-			}
+		if isPermanentConnectError(err) {
+			return nil, err
+		}
+		if attempt == maxAttempts {
+			return nil, fmt.Errorf("failed after %d attempts: %w", attempt, err)
 		}
 
-		if i < maxRetries-1 {
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-				backoff *= 2 // Exponential backoff
-			}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up after %d attempts (%v): %w", attempt, ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
 		}
 	}
+}
 
-	return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, err)
+// isPermanentConnectError reports whether retrying err cannot help: the
+// server answered and rejected the credentials or the database, or ctx
+// was canceled.
+func isPermanentConnectError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return false
+	}
+	switch myErr.Number {
+	case 1044, // ER_DBACCESS_DENIED_ERROR
+		1045, // ER_ACCESS_DENIED_ERROR
+		1049, // ER_BAD_DB_ERROR
+		1251, // ER_NOT_SUPPORTED_AUTH_MODE
+		1698: // ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+		return true
+	default:
+		return false
+	}
 }
 
-// connect creates a database connection.
-func (m *Manager) connect(cfg *config.DatabaseConfig) (*sql.DB, error) {
-	dsn := BuildDSN(cfg)
+// connect creates a database connection. name labels the connection and
+// keys its custom TLS config and SSH tunnel, if any.
+func (m *Manager) connect(name string, cfg *config.DatabaseConfig) (*sql.DB, error) {
+	dsnCfg := buildDSNConfig(cfg)
+	tlsName, err := registerTLSConfig(name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if tlsName != "" {
+		dsnCfg.TLSConfig = tlsName
+	}
+	network, err := m.sshNetwork(name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if network != "" {
+		dsnCfg.Net = network
+	}
+	dsn := dsnCfg.FormatDSN()
 
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -130,29 +234,75 @@ func (m *Manager) connect(cfg *config.DatabaseConfig) (*sql.DB, error) {
 	}
 
 	// Configure connection pool
-	maxOpen := cfg.MaxConnections
-	if maxOpen <= 0 {
-		maxOpen = defaultMaxOpenConns
-	}
-	db.SetMaxOpenConns(maxOpen)
+	pool := poolSettingsFor(cfg)
+	db.SetMaxOpenConns(pool.maxOpen)
+	db.SetMaxIdleConns(pool.maxIdle)
+	db.SetConnMaxLifetime(pool.maxLifetime)
+	db.SetConnMaxIdleTime(pool.maxIdleTime)
+
+	return db, nil
+}
 
-	maxIdle := cfg.MaxIdleConnections
-	if maxIdle <= 0 {
-		maxIdle = defaultMaxIdleConns
+// sshNetwork returns the driver network name that dials through cfg's SSH
+// tunnel, registering the tunnel on first use, or "" when cfg has none.
+func (m *Manager) sshNetwork(name string, cfg *config.DatabaseConfig) (string, error) {
+	if cfg.SSHTunnel == nil {
+		return "", nil
 	}
-	if maxIdle > maxOpen {
-		maxIdle = maxOpen
+	if _, ok := m.tunnels[name]; ok {
+		return sshNetworkName(name), nil
 	}
-	db.SetMaxIdleConns(maxIdle)
+	network, tunnel, err := registerSSHTunnel(name, cfg, m.sshConnect)
+	if err != nil {
+		return "", err
+	}
+	if m.tunnels == nil {
+		m.tunnels = make(map[string]*sshTunnel)
+	}
+	m.tunnels[name] = tunnel
+	return network, nil
+}
 
-	db.SetConnMaxLifetime(defaultConnMaxLife)
-	db.SetConnMaxIdleTime(defaultConnMaxIdle)
+// poolSettings are the database/sql pool limits applied to one connection.
+type poolSettings struct {
+	maxOpen     int
+	maxIdle     int
+	maxLifetime time.Duration
+	maxIdleTime time.Duration
+}
 
-	return db, nil
+// poolSettingsFor resolves cfg's pool fields, substituting defaults for unset
+// (zero) values and clamping idle connections to the open-connection limit.
+func poolSettingsFor(cfg *config.DatabaseConfig) poolSettings {
+	p := poolSettings{
+		maxOpen:     cfg.MaxConnections,
+		maxIdle:     cfg.MaxIdleConnections,
+		maxLifetime: time.Duration(cfg.ConnMaxLifetimeSeconds) * time.Second,
+		maxIdleTime: time.Duration(cfg.ConnMaxIdleTimeSeconds) * time.Second,
+	}
+	if p.maxOpen <= 0 {
+		p.maxOpen = defaultMaxOpenConns
+	}
+	if p.maxIdle <= 0 {
+		p.maxIdle = defaultMaxIdleConns
+	}
+	if p.maxIdle > p.maxOpen {
+		p.maxIdle = p.maxOpen
+	}
+	if p.maxLifetime <= 0 {
+		p.maxLifetime = defaultConnMaxLife
+	}
+	if p.maxIdleTime <= 0 {
+		p.maxIdleTime = defaultConnMaxIdle
+	}
+	return p
 }
 
-// BuildDSN constructs a MySQL DSN from configuration.
-func BuildDSN(cfg *config.DatabaseConfig) string {
+// buildDSNConfig constructs the driver config for a MySQL connection. Custom
+// TLS settings (CA bundle, client certificate, verify-ca) are applied by
+// Manager.Connect, which registers them with the driver; here verify-ca and
+// verify-full fall back to full verification.
+func buildDSNConfig(cfg *config.DatabaseConfig) *mysql.Config {
 	// Start from NewConfig() so the driver's safe defaults are preserved.
 	// A bare mysql.Config{} literal would zero AllowNativePasswords (default
 	// true), CheckConnLiveness (default true) and MaxAllowedPacket, emitting
@@ -161,7 +311,7 @@ func BuildDSN(cfg *config.DatabaseConfig) string {
 	// Cloud SQL) with "this user requires mysql native password authentication".
 	dsnCfg := mysql.NewConfig()
 	dsnCfg.User = cfg.User
-	dsnCfg.Passwd = cfg.Password
+	dsnCfg.Passwd = cfg.RevealPassword()
 	dsnCfg.Net = "tcp"
 	dsnCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
 	dsnCfg.DBName = cfg.Database
@@ -171,7 +321,7 @@ func BuildDSN(cfg *config.DatabaseConfig) string {
 	switch cfg.TLS {
 	case "disable":
 		dsnCfg.TLSConfig = "false"
-	case "required":
+	case "required", "verify-ca", "verify-full":
 		// Full verification: chain + hostname/IP. Use a CA-signed cert with the
 		// host in its SAN, or choose "skip-verify" for self-signed/Cloud SQL.
 		dsnCfg.TLSConfig = "true"
@@ -184,7 +334,7 @@ func BuildDSN(cfg *config.DatabaseConfig) string {
 		dsnCfg.TLSConfig = "preferred"
 	}
 
-	return dsnCfg.FormatDSN()
+	return dsnCfg
 }
 
 // Close closes all database connections gracefully.
@@ -198,6 +348,13 @@ func (m *Manager) Close() error {
 		m.Replica = nil
 	}
 
+	if m.SourceRead != nil {
+		if err := m.SourceRead.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("source read replica close: %w", err))
+		}
+		m.SourceRead = nil
+	}
+
 	if m.Destination != nil {
 		if err := m.Destination.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("destination close: %w", err))
@@ -212,6 +369,13 @@ func (m *Manager) Close() error {
 		m.Source = nil
 	}
 
+	for name, tunnel := range m.tunnels {
+		if err := tunnel.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s ssh tunnel close: %w", name, err))
+		}
+	}
+	m.tunnels = nil
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -236,6 +400,12 @@ func (m *Manager) Ping(ctx context.Context) error {
 		}
 	}
 
+	if m.SourceRead != nil {
+		if err := m.SourceRead.PingContext(ctx); err != nil {
+			return fmt.Errorf("source read replica ping failed: %w", err)
+		}
+	}
+
 	if m.Replica != nil {
 		if err := m.Replica.PingContext(ctx); err != nil {
 			return fmt.Errorf("replica ping failed: %w", err)
@@ -2,12 +2,19 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
 	"testing"
+	"time"
+
+	mysql "github.com/go-sql-driver/mysql"
 
 	"github.com/dbsmedya/goarchive/internal/config"
 )
 
-func TestBuildDSN(t *testing.T) {
+func TestBuildDSNConfig(t *testing.T) {
 	tests := []struct {
 		name             string
 		cfg              *config.DatabaseConfig
@@ -76,10 +83,10 @@ func TestBuildDSN(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildDSN(tt.cfg)
+			result := buildDSNConfig(tt.cfg).FormatDSN()
 			for _, expected := range tt.expectedContains {
 				if !contains(result, expected) {
-					t.Errorf("BuildDSN() = %q, expected to contain %q", result, expected)
+					t.Errorf("FormatDSN() = %q, expected to contain %q", result, expected)
 				}
 			}
 		})
@@ -147,7 +154,7 @@ func TestManagerCloseWithoutConnect(t *testing.T) {
 
 // Additional tests for Phase 2
 
-func TestBuildDSN_EdgeCases(t *testing.T) {
+func TestBuildDSNConfig_EdgeCases(t *testing.T) {
 	tests := []struct {
 		name             string
 		cfg              *config.DatabaseConfig
@@ -205,10 +212,10 @@ func TestBuildDSN_EdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildDSN(tt.cfg)
+			result := buildDSNConfig(tt.cfg).FormatDSN()
 			for _, expected := range tt.expectedContains {
 				if !contains(result, expected) {
-					t.Errorf("BuildDSN() = %q, expected to contain %q", result, expected)
+					t.Errorf("FormatDSN() = %q, expected to contain %q", result, expected)
 				}
 			}
 		})
@@ -267,7 +274,7 @@ func TestNewManager_WithReplicaEnabled(t *testing.T) {
 	}
 }
 
-func TestBuildDSN_TLSVariants(t *testing.T) {
+func TestBuildDSNConfig_TLSVariants(t *testing.T) {
 	tests := []struct {
 		name        string
 		tlsValue    string
@@ -278,6 +285,7 @@ func TestBuildDSN_TLSVariants(t *testing.T) {
 		{name: "TLS required", tlsValue: "required", expectedTLS: "tls=true"},
 		{name: "TLS skip-verify", tlsValue: "skip-verify", expectedTLS: "tls=skip-verify"},
 		{name: "TLS empty defaults to preferred", tlsValue: "", expectedTLS: "tls=preferred"},
+		{name: "TLS verify-full without custom config", tlsValue: "verify-full", expectedTLS: "tls=true"},
 	}
 
 	for _, tt := range tests {
@@ -290,18 +298,18 @@ func TestBuildDSN_TLSVariants(t *testing.T) {
 				Database: "testdb",
 				TLS:      tt.tlsValue,
 			}
-			result := BuildDSN(cfg)
+			result := buildDSNConfig(cfg).FormatDSN()
 			if !contains(result, tt.expectedTLS) {
-				t.Errorf("BuildDSN() = %q, should contain %q", result, tt.expectedTLS)
+				t.Errorf("FormatDSN() = %q, should contain %q", result, tt.expectedTLS)
 			}
 		})
 	}
 }
 
-// TestBuildDSN_AllowsNativePasswords guards against the struct-literal
+// TestBuildDSNConfig_AllowsNativePasswords guards against the struct-literal
 // regression that emitted allowNativePasswords=false, breaking servers whose
 // users authenticate via the mysql_native_password plugin (MySQL 8.4 / Cloud SQL).
-func TestBuildDSN_AllowsNativePasswords(t *testing.T) {
+func TestBuildDSNConfig_AllowsNativePasswords(t *testing.T) {
 	cfg := &config.DatabaseConfig{
 		Host:     "localhost",
 		Port:     3306,
@@ -310,13 +318,13 @@ func TestBuildDSN_AllowsNativePasswords(t *testing.T) {
 		Database: "testdb",
 		TLS:      "skip-verify",
 	}
-	result := BuildDSN(cfg)
+	result := buildDSNConfig(cfg).FormatDSN()
 	if contains(result, "allowNativePasswords=false") {
-		t.Errorf("BuildDSN() = %q, must not disable native passwords", result)
+		t.Errorf("FormatDSN() = %q, must not disable native passwords", result)
 	}
 }
 
-func TestBuildDSN_RequiredParams(t *testing.T) {
+func TestBuildDSNConfig_RequiredParams(t *testing.T) {
 	cfg := &config.DatabaseConfig{
 		Host:     "localhost",
 		Port:     3306,
@@ -326,7 +334,7 @@ func TestBuildDSN_RequiredParams(t *testing.T) {
 		TLS:      "preferred",
 	}
 
-	dsn := BuildDSN(cfg)
+	dsn := buildDSNConfig(cfg).FormatDSN()
 
 	// Verify required parameters are present
 	required := []string{
@@ -336,7 +344,7 @@ func TestBuildDSN_RequiredParams(t *testing.T) {
 
 	for _, param := range required {
 		if !contains(dsn, param) {
-			t.Errorf("BuildDSN() should contain %q", param)
+			t.Errorf("FormatDSN() should contain %q", param)
 		}
 	}
 }
@@ -383,3 +391,171 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestPoolSettingsFor(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.DatabaseConfig
+		want poolSettings
+	}{
+		{
+			name: "defaults when unset",
+			cfg:  config.DatabaseConfig{},
+			want: poolSettings{maxOpen: defaultMaxOpenConns, maxIdle: defaultMaxIdleConns, maxLifetime: defaultConnMaxLife, maxIdleTime: defaultConnMaxIdle},
+		},
+		{
+			name: "explicit values",
+			cfg:  config.DatabaseConfig{MaxConnections: 20, MaxIdleConnections: 8, ConnMaxLifetimeSeconds: 120, ConnMaxIdleTimeSeconds: 30},
+			want: poolSettings{maxOpen: 20, maxIdle: 8, maxLifetime: 2 * time.Minute, maxIdleTime: 30 * time.Second},
+		},
+		{
+			name: "idle clamped to open",
+			cfg:  config.DatabaseConfig{MaxConnections: 3},
+			want: poolSettings{maxOpen: 3, maxIdle: 3, maxLifetime: defaultConnMaxLife, maxIdleTime: defaultConnMaxIdle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := poolSettingsFor(&tt.cfg); got != tt.want {
+				t.Errorf("poolSettingsFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagerConnect_AppliesPoolSettings(t *testing.T) {
+	cfg := &config.Config{
+		Source: config.DatabaseConfig{
+			Host:                   "localhost",
+			Port:                   3306,
+			User:                   "root",
+			Database:               "sourcedb",
+			MaxConnections:         7,
+			MaxIdleConnections:     2,
+			ConnMaxLifetimeSeconds: 60,
+		},
+	}
+	manager := NewManager(cfg)
+
+	// sql.Open does not dial, so the pool can be inspected without a server.
+	db, err := manager.connect("source", &cfg.Source)
+	if err != nil {
+		t.Fatalf("connect() error = %v", err)
+	}
+	defer db.Close()
+
+	if got := db.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("MaxOpenConnections = %d, want 7", got)
+	}
+}
+
+func TestManagerReadSource(t *testing.T) {
+	primary, err := sql.Open("mysql", "root@tcp(primary:3306)/db")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer primary.Close()
+	replica, err := sql.Open("mysql", "root@tcp(replica:3306)/db")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer replica.Close()
+
+	m := &Manager{Source: primary}
+	if m.ReadSource() != primary {
+		t.Error("ReadSource() should fall back to Source without a read replica")
+	}
+
+	m.SourceRead = replica
+	if m.ReadSource() != replica {
+		t.Error("ReadSource() should return SourceRead when configured")
+	}
+}
+
+// flakyConnector fails the first failures dials with err, then succeeds.
+type flakyConnector struct {
+	failures int
+	err      error
+	dials    int
+}
+
+func (c *flakyConnector) Connect(context.Context) (driver.Conn, error) {
+	c.dials++
+	if c.dials <= c.failures {
+		return nil, c.err
+	}
+	return stubConn{}, nil
+}
+
+func (c *flakyConnector) Driver() driver.Driver { return nil }
+
+type stubConn struct{}
+
+func (stubConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (stubConn) Close() error                        { return nil }
+func (stubConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func TestConnectWithRetry(t *testing.T) {
+	notReady := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	authFailed := &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'root'"}
+
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantDials int
+		wantErr   bool
+	}{
+		{"succeeds after transient failures", 2, notReady, 3, false},
+		{"gives up at max attempts", 10, notReady, 4, true},
+		{"auth failure is not retried", 10, authFailed, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &flakyConnector{failures: tt.failures, err: tt.err}
+			m := NewManager(&config.Config{})
+			m.connectTries, m.connectBackoff = 4, time.Millisecond
+			m.open = func(string, *config.DatabaseConfig) (*sql.DB, error) {
+				return sql.OpenDB(conn), nil
+			}
+
+			db, err := m.connectWithRetry(context.Background(), "source", &config.DatabaseConfig{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("connectWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if db != nil {
+				_ = db.Close()
+			}
+			if conn.dials != tt.wantDials {
+				t.Errorf("dials = %d, want %d", conn.dials, tt.wantDials)
+			}
+			if tt.err == authFailed && !errors.Is(err, tt.err) {
+				t.Errorf("error = %v, want the auth error", err)
+			}
+		})
+	}
+}
+
+func TestConnectWithRetry_HonorsContext(t *testing.T) {
+	conn := &flakyConnector{failures: 10, err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no such host")}}
+	m := NewManager(&config.Config{})
+	m.connectTries, m.connectBackoff = 10, time.Hour
+	m.open = func(string, *config.DatabaseConfig) (*sql.DB, error) {
+		return sql.OpenDB(conn), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := m.connectWithRetry(ctx, "source", &config.DatabaseConfig{})
+	if !errors.Is(err, conn.err) {
+		t.Errorf("error = %v, want the last dial error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("connectWithRetry waited %v past the context deadline", elapsed)
+	}
+	if conn.dials != 1 {
+		t.Errorf("dials = %d, want 1", conn.dials)
+	}
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+// hostLookupTimeout bounds each DNS lookup made by SameDatabase.
+const hostLookupTimeout = 2 * time.Second
+
+// lookupHost resolves a host name; tests substitute a fake resolver.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// SameDatabase reports whether a and b address the same database on the
+// same server: equal ports (3306 when unset), equal database names
+// (case-insensitively) and hosts that are equal after normalization or
+// resolve to a common IP address. Loopback names and addresses are treated
+// as one host. A host that fails to resolve is compared by name only.
+func SameDatabase(ctx context.Context, a, b *config.DatabaseConfig) bool {
+	if effectivePort(a.Port) != effectivePort(b.Port) || !strings.EqualFold(a.Database, b.Database) {
+		return false
+	}
+	hostA, hostB := normalizeHost(a.Host), normalizeHost(b.Host)
+	if hostA == hostB {
+		return true
+	}
+
+	addrsA := resolveHost(ctx, hostA)
+	for addr := range resolveHost(ctx, hostB) {
+		if addrsA[addr] {
+			return true
+		}
+	}
+	return false
+}
+
+func effectivePort(port int) int {
+	if port == 0 {
+		return 3306
+	}
+	return port
+}
+
+// normalizeHost lowercases host, drops a trailing dot and maps every
+// loopback name or address to "localhost".
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" || host == "localhost" {
+		return "localhost"
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsLoopback() {
+			return "localhost"
+		}
+		return ip.String()
+	}
+	return host
+}
+
+// resolveHost returns the normalized addresses of host; a literal IP or
+// loopback resolves to itself.
+func resolveHost(ctx context.Context, host string) map[string]bool {
+	addrs := map[string]bool{host: true}
+	if host == "localhost" || net.ParseIP(host) != nil {
+		return addrs
+	}
+	ctx, cancel := context.WithTimeout(ctx, hostLookupTimeout)
+	defer cancel()
+	resolved, err := lookupHost(ctx, host)
+	if err != nil {
+		return addrs
+	}
+	for _, addr := range resolved {
+		addrs[normalizeHost(addr)] = true
+	}
+	return addrs
+}
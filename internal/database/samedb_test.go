@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+func TestSameDatabase(t *testing.T) {
+	hosts := map[string][]string{
+		"db1.internal":    {"10.0.0.5"},
+		"db1-alias.local": {"10.0.0.5"},
+		"db2.internal":    {"10.0.0.6"},
+	}
+	orig := lookupHost
+	lookupHost = func(_ context.Context, host string) ([]string, error) {
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	defer func() { lookupHost = orig }()
+
+	tests := []struct {
+		name string
+		a, b config.DatabaseConfig
+		want bool
+	}{
+		{"same host and schema",
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "DB1.internal.", Database: "shop"}, true},
+		{"same host different schema",
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop_archive"}, false},
+		{"distinct servers",
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "db2.internal", Port: 3306, Database: "shop"}, false},
+		{"different ports",
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "db1.internal", Port: 3307, Database: "shop"}, false},
+		{"alias resolving to the same address",
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "db1-alias.local", Port: 3306, Database: "shop"}, true},
+		{"host name and its IP",
+			config.DatabaseConfig{Host: "db1.internal", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "10.0.0.5", Port: 3306, Database: "shop"}, true},
+		{"loopback spellings",
+			config.DatabaseConfig{Host: "localhost", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "127.0.0.1", Port: 3306, Database: "SHOP"}, true},
+		{"unresolvable hosts compared by name",
+			config.DatabaseConfig{Host: "a.invalid", Port: 3306, Database: "shop"},
+			config.DatabaseConfig{Host: "b.invalid", Port: 3306, Database: "shop"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameDatabase(context.Background(), &tt.a, &tt.b); got != tt.want {
+				t.Errorf("SameDatabase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
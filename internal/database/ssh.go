@@ -0,0 +1,240 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mysql "github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+const defaultSSHPort = 22
+
+// sshHandshakeTimeout bounds connecting and authenticating to the bastion, so
+// a bastion that accepts TCP but stalls the handshake fails the dial instead
+// of hanging it.
+const sshHandshakeTimeout = 30 * time.Second
+
+// sshDialer opens connections from the far side of an established SSH
+// session; *ssh.Client satisfies it.
+type sshDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	Close() error
+}
+
+// sshConnectFunc performs the SSH handshake with the bastion at addr,
+// giving up when ctx is done. Tests substitute a fake to avoid a real SSH
+// server.
+type sshConnectFunc func(ctx context.Context, addr string, cfg *ssh.ClientConfig) (sshDialer, error)
+
+// dialSSH is the default sshConnectFunc. ssh.Dial ignores contexts and only
+// bounds the TCP connect, so the handshake runs under a connection deadline
+// of cfg.Timeout and the connection is closed if ctx is done first.
+func dialSSH(ctx context.Context, addr string, cfg *ssh.ClientConfig) (sshDialer, error) {
+	d := net.Dialer{Timeout: cfg.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(cfg.Timeout))
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if !stop() {
+		if err == nil {
+			_ = c.Close()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// sshTunnel lazily establishes one SSH session to the bastion and opens every
+// MySQL connection of a pool through it. A dial that fails other than by the
+// bastion refusing the channel drops the session so the next dial
+// reconnects, e.g. after the bastion restarted. mu only guards the fields:
+// handshakes and channel dials run unlocked, so a stalled one does not block
+// dials that can give up on their own context.
+type sshTunnel struct {
+	addr      string // bastion host:port
+	clientCfg *ssh.ClientConfig
+	connect   sshConnectFunc
+
+	mu         sync.Mutex
+	client     sshDialer
+	connecting chan struct{} // closed when the handshake in progress ends; nil when none
+}
+
+// newSSHTunnel builds the tunnel for cfg. It reads the private key and
+// known_hosts file but does not contact the bastion.
+func newSSHTunnel(cfg *config.SSHTunnelConfig, connect sshConnectFunc) (*sshTunnel, error) {
+	clientCfg, err := buildSSHClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSSHPort
+	}
+	if connect == nil {
+		connect = dialSSH
+	}
+	return &sshTunnel{
+		addr:      net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		clientCfg: clientCfg,
+		connect:   connect,
+	}, nil
+}
+
+// buildSSHClientConfig returns the client config for cfg: public key
+// authentication with KeyPath and host key verification against
+// KnownHostsFile (default ~/.ssh/known_hosts).
+func buildSSHClientConfig(cfg *config.SSHTunnelConfig) (*ssh.ClientConfig, error) {
+	pem, err := os.ReadFile(cfg.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ssh key_path: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh key %s: %w", cfg.KeyPath, err)
+	}
+
+	knownHostsFile := cfg.KnownHostsFile
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot locate ~/.ssh/known_hosts; set known_hosts_file: %w", err)
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ssh known_hosts: %w", err)
+	}
+
+	return &ssh.ClientConfig{
+		User:            cfg.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshHandshakeTimeout,
+	}, nil
+}
+
+// DialContext opens a TCP connection to addr from the bastion, connecting to
+// the bastion first if there is no session yet.
+func (t *sshTunnel) DialContext(ctx context.Context, addr string) (net.Conn, error) {
+	client, err := t.session(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ssh tunnel to %s: %w", t.addr, err)
+	}
+	conn, err := client.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		var refused *ssh.OpenChannelError
+		if !errors.As(err, &refused) {
+			t.drop(client)
+		}
+		return nil, fmt.Errorf("ssh tunnel to %s: dial %s: %w", t.addr, addr, err)
+	}
+	return conn, nil
+}
+
+// session returns the SSH session, connecting to the bastion when there is
+// none. Only one handshake runs at a time; concurrent callers wait for it
+// until their ctx is done.
+func (t *sshTunnel) session(ctx context.Context) (sshDialer, error) {
+	for {
+		t.mu.Lock()
+		if t.client != nil {
+			client := t.client
+			t.mu.Unlock()
+			return client, nil
+		}
+		if wait := t.connecting; wait != nil {
+			t.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		done := make(chan struct{})
+		t.connecting = done
+		t.mu.Unlock()
+
+		client, err := t.connect(ctx, t.addr, t.clientCfg)
+
+		t.mu.Lock()
+		t.connecting = nil
+		if err == nil {
+			t.client = client
+		}
+		t.mu.Unlock()
+		close(done)
+		return client, err
+	}
+}
+
+// drop closes client and forgets it if it is still the tunnel's session.
+func (t *sshTunnel) drop(client sshDialer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == client {
+		_ = t.client.Close()
+		t.client = nil
+	}
+}
+
+// Close ends the SSH session, if any. Connections opened through it are
+// closed with it.
+func (t *sshTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == nil {
+		return nil
+	}
+	err := t.client.Close()
+	t.client = nil
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// registerSSHTunnel registers a MySQL dial function that routes through
+// cfg's SSH tunnel under a network name derived from the connection label
+// and returns that name and the tunnel, or "" and nil when cfg has no tunnel.
+func registerSSHTunnel(name string, cfg *config.DatabaseConfig, connect sshConnectFunc) (string, *sshTunnel, error) {
+	if cfg.SSHTunnel == nil {
+		return "", nil, nil
+	}
+	tunnel, err := newSSHTunnel(cfg.SSHTunnel, connect)
+	if err != nil {
+		return "", nil, err
+	}
+	network := sshNetworkName(name)
+	mysql.RegisterDialContext(network, tunnel.DialContext)
+	return network, tunnel, nil
+}
+
+// sshNetworkName is the driver network name registered for name's tunnel.
+func sshNetworkName(name string) string {
+	return "goarchive-ssh-" + strings.ReplaceAll(name, " ", "-")
+}
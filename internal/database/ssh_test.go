@@ -0,0 +1,370 @@
+package database
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+// fakeSSHClient records the addresses dialled through it and returns one end
+// of a pipe, or dialErr.
+type fakeSSHClient struct {
+	dialed  []string
+	dialErr error
+	closed  bool
+}
+
+func (c *fakeSSHClient) DialContext(_ context.Context, network, addr string) (net.Conn, error) {
+	c.dialed = append(c.dialed, network+" "+addr)
+	if c.dialErr != nil {
+		return nil, c.dialErr
+	}
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+func (c *fakeSSHClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+// stallingSSHClient blocks dials to stallAddr until their context is done
+// and connects every other address.
+type stallingSSHClient struct {
+	stallAddr string
+}
+
+func (c *stallingSSHClient) DialContext(ctx context.Context, _, addr string) (net.Conn, error) {
+	if addr == c.stallAddr {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+func (c *stallingSSHClient) Close() error { return nil }
+
+// writeSSHTestFiles writes a private key and a known_hosts entry for
+// bastion.example.com and returns a tunnel config that uses them.
+func writeSSHTestFiles(t *testing.T) *config.SSHTunnelConfig {
+	t.Helper()
+	dir := t.TempDir()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	hostPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostKey, err := ssh.NewPublicKey(hostPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	knownHosts := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{"bastion.example.com:2222"}, hostKey) + "\n"
+	if err := os.WriteFile(knownHosts, []byte(line), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return &config.SSHTunnelConfig{
+		Host:           "bastion.example.com",
+		Port:           2222,
+		User:           "jump",
+		KeyPath:        keyPath,
+		KnownHostsFile: knownHosts,
+	}
+}
+
+func TestSSHTunnel_DialsThroughBastion(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+	client := &fakeSSHClient{}
+	var handshakes []string
+	var gotCfg *ssh.ClientConfig
+	connect := func(_ context.Context, addr string, cfg *ssh.ClientConfig) (sshDialer, error) {
+		handshakes = append(handshakes, addr)
+		gotCfg = cfg
+		return client, nil
+	}
+
+	tunnel, err := newSSHTunnel(tunCfg, connect)
+	if err != nil {
+		t.Fatalf("newSSHTunnel: %v", err)
+	}
+	if len(handshakes) != 0 {
+		t.Fatal("building the tunnel must not contact the bastion")
+	}
+
+	for i := 0; i < 2; i++ {
+		conn, err := tunnel.DialContext(context.Background(), "db.internal:3306")
+		if err != nil {
+			t.Fatalf("DialContext: %v", err)
+		}
+		_ = conn.Close()
+	}
+
+	if len(handshakes) != 1 || handshakes[0] != "bastion.example.com:2222" {
+		t.Errorf("handshakes = %v, want one to bastion.example.com:2222", handshakes)
+	}
+	if gotCfg.User != "jump" || len(gotCfg.Auth) != 1 || gotCfg.HostKeyCallback == nil || gotCfg.Timeout != sshHandshakeTimeout {
+		t.Errorf("unexpected client config: %+v", gotCfg)
+	}
+	if len(client.dialed) != 2 || client.dialed[0] != "tcp db.internal:3306" {
+		t.Errorf("dialed = %v", client.dialed)
+	}
+
+	if err := tunnel.Close(); err != nil || !client.closed {
+		t.Errorf("Close = %v, closed = %v", err, client.closed)
+	}
+}
+
+func TestSSHTunnel_DefaultPort(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+	tunCfg.Port = 0
+	tunnel, err := newSSHTunnel(tunCfg, nil)
+	if err != nil {
+		t.Fatalf("newSSHTunnel: %v", err)
+	}
+	if tunnel.addr != "bastion.example.com:22" {
+		t.Errorf("addr = %q, want port 22", tunnel.addr)
+	}
+}
+
+func TestSSHTunnel_ReconnectsAfterSessionFailure(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+	broken := &fakeSSHClient{dialErr: errors.New("connection reset")}
+	healthy := &fakeSSHClient{}
+	clients := []*fakeSSHClient{broken, healthy}
+	connect := func(context.Context, string, *ssh.ClientConfig) (sshDialer, error) {
+		c := clients[0]
+		clients = clients[1:]
+		return c, nil
+	}
+	tunnel, err := newSSHTunnel(tunCfg, connect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tunnel.DialContext(context.Background(), "db.internal:3306"); err == nil ||
+		!strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("expected dial error, got %v", err)
+	}
+	if !broken.closed {
+		t.Error("broken session should be closed")
+	}
+	conn, err := tunnel.DialContext(context.Background(), "db.internal:3306")
+	if err != nil {
+		t.Fatalf("expected reconnect, got %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestSSHTunnel_KeepsSessionWhenChannelRefused(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+	client := &fakeSSHClient{dialErr: &ssh.OpenChannelError{Reason: ssh.ConnectionFailed, Message: "refused"}}
+	handshakes := 0
+	tunnel, err := newSSHTunnel(tunCfg, func(context.Context, string, *ssh.ClientConfig) (sshDialer, error) {
+		handshakes++
+		return client, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := tunnel.DialContext(context.Background(), "db.internal:3306"); err == nil {
+			t.Fatal("expected dial error")
+		}
+	}
+	if handshakes != 1 || client.closed {
+		t.Errorf("handshakes = %d, closed = %v; a refused channel must keep the session", handshakes, client.closed)
+	}
+}
+
+// dialResult runs tunnel.DialContext(ctx) in the background and returns a
+// channel that receives its error.
+func dialResult(tunnel *sshTunnel, ctx context.Context, addr string) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		conn, err := tunnel.DialContext(ctx, addr)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		done <- err
+	}()
+	return done
+}
+
+// waitDial fails the test unless a dial started by dialResult ends with
+// context.Canceled within a few seconds.
+func waitDial(t *testing.T, done <-chan error, what string) {
+	t.Helper()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: got %v, want context.Canceled", what, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s: dial did not return after its context was canceled", what)
+	}
+}
+
+func TestSSHTunnel_StalledHandshakeHonorsContext(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+	started := make(chan struct{})
+	tunnel, err := newSSHTunnel(tunCfg, func(ctx context.Context, _ string, _ *ssh.ClientConfig) (sshDialer, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := dialResult(tunnel, ctx, "db.internal:3306")
+	<-started
+
+	// A second dial queued behind the stalled handshake gives up on its own
+	// context without waiting for the first.
+	waitCtx, waitCancel := context.WithCancel(context.Background())
+	second := dialResult(tunnel, waitCtx, "db.internal:3306")
+	waitCancel()
+	waitDial(t, second, "queued dial")
+
+	cancel()
+	waitDial(t, first, "handshaking dial")
+}
+
+func TestSSHTunnel_ChannelDialDoesNotBlockOthers(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+	tunnel, err := newSSHTunnel(tunCfg, func(context.Context, string, *ssh.ClientConfig) (sshDialer, error) {
+		return &stallingSSHClient{stallAddr: "slow.internal:3306"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := dialResult(tunnel, ctx, "slow.internal:3306")
+	select {
+	case err := <-dialResult(tunnel, context.Background(), "db.internal:3306"):
+		if err != nil {
+			t.Errorf("DialContext: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a stalled channel dial blocked another dial")
+	}
+	cancel()
+	waitDial(t, slow, "stalled channel dial")
+}
+
+func TestDialSSH_StalledHandshakeHonorsContext(t *testing.T) {
+	// The bastion accepts TCP but never speaks SSH.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+	cfg := &ssh.ClientConfig{User: "jump", HostKeyCallback: ssh.InsecureIgnoreHostKey(), Timeout: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := dialSSH(ctx, ln.Addr().String(), cfg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("canceled handshake: got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("handshake returned after %s", elapsed)
+	}
+
+	cfg.Timeout = 100 * time.Millisecond
+	if _, err := dialSSH(context.Background(), ln.Addr().String(), cfg); err == nil {
+		t.Error("expected the handshake timeout to fail the dial")
+	}
+}
+
+func TestBuildSSHClientConfig_Errors(t *testing.T) {
+	tunCfg := writeSSHTestFiles(t)
+
+	missingKey := *tunCfg
+	missingKey.KeyPath = filepath.Join(t.TempDir(), "missing")
+	if _, err := buildSSHClientConfig(&missingKey); err == nil || !strings.Contains(err.Error(), "key_path") {
+		t.Errorf("missing key: got %v", err)
+	}
+
+	badKey := *tunCfg
+	badKey.KeyPath = tunCfg.KnownHostsFile
+	if _, err := buildSSHClientConfig(&badKey); err == nil || !strings.Contains(err.Error(), "parse ssh key") {
+		t.Errorf("bad key: got %v", err)
+	}
+
+	missingHosts := *tunCfg
+	missingHosts.KnownHostsFile = filepath.Join(t.TempDir(), "missing")
+	if _, err := buildSSHClientConfig(&missingHosts); err == nil || !strings.Contains(err.Error(), "known_hosts") {
+		t.Errorf("missing known_hosts: got %v", err)
+	}
+}
+
+func TestManagerConnect_RegistersSSHTunnel(t *testing.T) {
+	cfg := &config.DatabaseConfig{Host: "db.internal", Port: 3306, User: "u", Database: "d", SSHTunnel: writeSSHTestFiles(t)}
+	m := &Manager{sshConnect: func(context.Context, string, *ssh.ClientConfig) (sshDialer, error) {
+		return &fakeSSHClient{}, nil
+	}}
+
+	network, err := m.sshNetwork("source read replica", cfg)
+	if err != nil {
+		t.Fatalf("sshNetwork: %v", err)
+	}
+	if network != "goarchive-ssh-source-read-replica" {
+		t.Errorf("network = %q", network)
+	}
+	tunnel := m.tunnels["source read replica"]
+	if again, _ := m.sshNetwork("source read replica", cfg); again != network || m.tunnels["source read replica"] != tunnel {
+		t.Error("retries must reuse the registered tunnel")
+	}
+
+	direct := *cfg
+	direct.SSHTunnel = nil
+	if network, err := m.sshNetwork("destination", &direct); network != "" || err != nil {
+		t.Errorf("no tunnel: got %q, %v", network, err)
+	}
+
+	if err := m.Close(); err != nil || m.tunnels != nil {
+		t.Errorf("Close = %v, tunnels = %v", err, m.tunnels)
+	}
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+// NotifyStateDump calls dump every time the process receives the signal
+// named name (e.g. "SIGQUIT" or "SIGUSR1"; see stateDumpSignals), for
+// debugging a job that looks stuck without stopping it. Catching the signal
+// replaces its default action, so SIGQUIT no longer exits with a goroutine
+// dump. The returned stop func unregisters the handler.
+func NotifyStateDump(name string, dump func()) (func(), error) {
+	sig, ok := stateDumpSignals[strings.ToUpper(name)]
+	if !ok {
+		names := make([]string, 0, len(stateDumpSignals))
+		for n := range stateDumpSignals {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unsupported state dump signal %q (supported: %s)", name, strings.Join(names, ", "))
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigChan, sig)
+	go func() {
+		for {
+			select {
+			case <-sigChan:
+				dump()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}, nil
+}
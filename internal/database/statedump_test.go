@@ -0,0 +1,40 @@
+//go:build !windows
+
+package database
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyStateDump_CallsDumpPerSignal(t *testing.T) {
+	if os.Getenv("CI") == "true" {
+		t.Skip("Skipping signal test in CI environment")
+	}
+
+	dumps := make(chan struct{}, 2)
+	stop, err := NotifyStateDump("sigusr1", func() { dumps <- struct{}{} })
+	if err != nil {
+		t.Fatalf("NotifyStateDump failed: %v", err)
+	}
+	defer stop()
+
+	for i := 0; i < 2; i++ {
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+		select {
+		case <-dumps:
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("signal %d did not trigger a dump", i+1)
+		}
+	}
+}
+
+func TestNotifyStateDump_UnsupportedSignal(t *testing.T) {
+	_, err := NotifyStateDump("SIGKILL", func() {})
+	if err == nil || !strings.Contains(err.Error(), "SIGQUIT, SIGUSR1, SIGUSR2") {
+		t.Fatalf("expected unsupported signal error listing the choices, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package database
+
+import (
+	"os"
+	"syscall"
+)
+
+// stateDumpSignals are the signals NotifyStateDump accepts, by name.
+var stateDumpSignals = map[string]os.Signal{
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package database
+
+import "os"
+
+// stateDumpSignals is empty: Windows delivers no signal NotifyStateDump
+// could catch without also stopping the job.
+var stateDumpSignals = map[string]os.Signal{}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	mysql "github.com/go-sql-driver/mysql"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+// BuildTLSConfig returns the custom tls.Config needed for cfg, or nil when
+// the driver's built-in handling of cfg.TLS is enough (no CA bundle, no
+// client certificate, and not verify-ca/verify-full).
+func BuildTLSConfig(cfg *config.DatabaseConfig) (*tls.Config, error) {
+	custom := cfg.TLS == config.TLSVerifyCA || cfg.TLS == config.TLSVerifyFull ||
+		cfg.TLSCACert != "" || cfg.TLSClientCert != ""
+	if !custom {
+		return nil, nil
+	}
+	switch cfg.TLS {
+	case "", config.TLSDisable, config.TLSPreferred:
+		return nil, fmt.Errorf("tls certificates require tls mode skip-verify, required, verify-ca, or verify-full (got %q)", cfg.TLS)
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.Host,
+	}
+
+	if cfg.TLSCACert != "" {
+		pem, err := os.ReadFile(cfg.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls_ca_cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls_ca_cert %s contains no PEM certificates", cfg.TLSCACert)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.TLSClientCert != "" || cfg.TLSClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSClientCert, cfg.TLSClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	switch cfg.TLS {
+	case config.TLSSkipVerify:
+		tlsCfg.InsecureSkipVerify = true
+	case config.TLSVerifyCA:
+		if tlsCfg.RootCAs == nil {
+			return nil, errors.New("tls mode verify-ca requires tls_ca_cert")
+		}
+		// Go cannot verify the chain without also checking the hostname, so
+		// disable the built-in check and verify the chain ourselves.
+		tlsCfg.InsecureSkipVerify = true
+		tlsCfg.VerifyPeerCertificate = verifyChainOnly(tlsCfg.RootCAs)
+	}
+
+	return tlsCfg, nil
+}
+
+// verifyChainOnly returns a VerifyPeerCertificate callback that accepts a
+// server certificate signed by roots, whatever host name it was issued for.
+func verifyChainOnly(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server presented no certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse server certificate: %w", err)
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
+
+// registerTLSConfig registers cfg's custom tls.Config with the MySQL driver
+// under a name derived from the connection label and returns that name, or
+// "" when cfg needs no custom config.
+func registerTLSConfig(name string, cfg *config.DatabaseConfig) (string, error) {
+	tlsCfg, err := BuildTLSConfig(cfg)
+	if err != nil || tlsCfg == nil {
+		return "", err
+	}
+	key := "goarchive-" + strings.ReplaceAll(name, " ", "-")
+	if err := mysql.RegisterTLSConfig(key, tlsCfg); err != nil {
+		return "", fmt.Errorf("failed to register tls config: %w", err)
+	}
+	return key, nil
+}
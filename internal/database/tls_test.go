@@ -0,0 +1,202 @@
+package database
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+// testCert is a generated certificate with its key, signed by parent (or
+// self-signed when parent is nil).
+type testCert struct {
+	cert *x509.Certificate
+	der  []byte
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, cn string, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{cn},
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return &testCert{cert: cert, der: der, key: key}
+}
+
+// writePEM writes c's certificate (and key, if keyPath is set) as PEM files.
+func (c *testCert) writePEM(t *testing.T, certPath, keyPath string) {
+	t.Helper()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+	if err := os.WriteFile(certPath, certPEM, 0600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if keyPath == "" {
+		return
+	}
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+}
+
+func TestBuildTLSConfig_NoCustomSettings(t *testing.T) {
+	for _, mode := range []string{"", "disable", "preferred", "skip-verify", "required"} {
+		tlsCfg, err := BuildTLSConfig(&config.DatabaseConfig{Host: "db", TLS: mode})
+		if err != nil || tlsCfg != nil {
+			t.Errorf("mode %q: expected nil config and no error, got %v, %v", mode, tlsCfg, err)
+		}
+	}
+}
+
+func TestBuildTLSConfig_CAAndClientCert(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "Test CA", true, nil)
+	client := newTestCert(t, "archiver", false, ca)
+	caPath := filepath.Join(dir, "ca.pem")
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+	ca.writePEM(t, caPath, "")
+	client.writePEM(t, certPath, keyPath)
+
+	tlsCfg, err := BuildTLSConfig(&config.DatabaseConfig{
+		Host:          "db.internal",
+		TLS:           config.TLSVerifyFull,
+		TLSCACert:     caPath,
+		TLSClientCert: certPath,
+		TLSClientKey:  keyPath,
+	})
+	if err != nil {
+		t.Fatalf("BuildTLSConfig: %v", err)
+	}
+
+	want := x509.NewCertPool()
+	want.AddCert(ca.cert)
+	if tlsCfg.RootCAs == nil || !tlsCfg.RootCAs.Equal(want) {
+		t.Error("RootCAs should contain exactly the configured CA")
+	}
+	if len(tlsCfg.Certificates) != 1 || string(tlsCfg.Certificates[0].Certificate[0]) != string(client.der) {
+		t.Error("client certificate not loaded")
+	}
+	if tlsCfg.ServerName != "db.internal" {
+		t.Errorf("ServerName = %q, want db.internal", tlsCfg.ServerName)
+	}
+	if tlsCfg.InsecureSkipVerify {
+		t.Error("verify-full must keep hostname verification enabled")
+	}
+	if tlsCfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", tlsCfg.MinVersion)
+	}
+}
+
+func TestBuildTLSConfig_VerifyCAIgnoresHostname(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "Test CA", true, nil)
+	server := newTestCert(t, "some-other-name", false, ca)
+	rogue := newTestCert(t, "db.internal", false, newTestCert(t, "Rogue CA", true, nil))
+	caPath := filepath.Join(dir, "ca.pem")
+	ca.writePEM(t, caPath, "")
+
+	tlsCfg, err := BuildTLSConfig(&config.DatabaseConfig{Host: "db.internal", TLS: config.TLSVerifyCA, TLSCACert: caPath})
+	if err != nil {
+		t.Fatalf("BuildTLSConfig: %v", err)
+	}
+	if !tlsCfg.InsecureSkipVerify || tlsCfg.VerifyPeerCertificate == nil {
+		t.Fatal("verify-ca must replace the built-in check with a chain-only verifier")
+	}
+	if err := tlsCfg.VerifyPeerCertificate([][]byte{server.der}, nil); err != nil {
+		t.Errorf("certificate signed by the CA should pass regardless of hostname: %v", err)
+	}
+	if err := tlsCfg.VerifyPeerCertificate([][]byte{rogue.der}, nil); err == nil {
+		t.Error("certificate from another CA must be rejected")
+	}
+	if err := tlsCfg.VerifyPeerCertificate(nil, nil); err == nil {
+		t.Error("missing server certificate must be rejected")
+	}
+}
+
+func TestBuildTLSConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.DatabaseConfig
+		want string
+	}{
+		{"missing CA file", config.DatabaseConfig{TLS: "verify-full", TLSCACert: filepath.Join(dir, "nope.pem")}, "tls_ca_cert"},
+		{"CA without PEM", config.DatabaseConfig{TLS: "verify-full", TLSCACert: garbage}, "no PEM certificates"},
+		{"bad client pair", config.DatabaseConfig{TLS: "required", TLSClientCert: garbage, TLSClientKey: garbage}, "client certificate"},
+		{"verify-ca without CA", config.DatabaseConfig{TLS: "verify-ca"}, "requires tls_ca_cert"},
+		{"certs with preferred", config.DatabaseConfig{TLS: "preferred", TLSCACert: garbage}, "require tls mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildTLSConfig(&tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRegisterTLSConfig_SetsDSNName(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "Test CA", true, nil)
+	caPath := filepath.Join(dir, "ca.pem")
+	ca.writePEM(t, caPath, "")
+
+	name, err := registerTLSConfig("source read replica", &config.DatabaseConfig{Host: "db", TLS: "verify-full", TLSCACert: caPath})
+	if err != nil {
+		t.Fatalf("registerTLSConfig: %v", err)
+	}
+	if name != "goarchive-source-read-replica" {
+		t.Errorf("name = %q", name)
+	}
+
+	name, err = registerTLSConfig("source", &config.DatabaseConfig{Host: "db", TLS: "required"})
+	if err != nil || name != "" {
+		t.Errorf("plain required mode should not register a config, got %q, %v", name, err)
+	}
+}
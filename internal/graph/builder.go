@@ -1,14 +1,18 @@
 package graph
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/dbsmedya/goarchive/internal/config"
+	"github.com/dbsmedya/goarchive/internal/logger"
 )
 
 // Builder constructs a dependency graph from job configuration.
 type Builder struct {
-	job *config.JobConfig
+	job    *config.JobConfig
+	logger *logger.Logger
 }
 
 // NewBuilder creates a new graph builder for the given job configuration.
@@ -16,6 +20,12 @@ func NewBuilder(job *config.JobConfig) *Builder {
 	return &Builder{job: job}
 }
 
+// SetLogger sets the logger that reports tables pruned by exclude_tables.
+// Defaults to logger.NewDefault().
+func (b *Builder) SetLogger(log *logger.Logger) {
+	b.logger = log
+}
+
 // Build constructs the dependency graph from the job configuration.
 // It parses all relations (including nested) and creates the graph structure.
 func (b *Builder) Build() (*Graph, error) {
@@ -33,12 +43,19 @@ func (b *Builder) Build() (*Graph, error) {
 
 	// Create graph with root table
 	g := NewGraph(b.job.RootTable, b.job.PrimaryKey)
+	g.SetColumnFilter(b.job.RootTable, columnFilterFromConfig(b.job.Columns))
+	g.Nodes[b.job.RootTable].DestinationTable = b.job.DestinationTable
+	g.Nodes[b.job.RootTable].DestinationDateColumn = b.job.DestinationDateColumn
 
 	// Parse all relations starting from root
 	if err := b.parseRelations(g, b.job.RootTable, b.job.PrimaryKey, b.job.Relations); err != nil {
 		return nil, fmt.Errorf("failed to parse relations: %w", err)
 	}
 
+	if err := b.pruneExcluded(g); err != nil {
+		return nil, err
+	}
+
 	// Validate graph structure (fail fast on cycles)
 	if err := g.Validate(); err != nil {
 		return nil, fmt.Errorf("graph validation failed: %w", err)
@@ -47,6 +64,55 @@ func (b *Builder) Build() (*Graph, error) {
 	return g, nil
 }
 
+// BuildFromSchema builds the job's graph from the foreign keys of db (see
+// the package-level BuildFromSchema) rooted at the job's root table, then
+// drops the job's exclude_tables. Tables listed in the job's relations must
+// survive the exclusion.
+func (b *Builder) BuildFromSchema(ctx context.Context, db *sql.DB, maxDepth int) (*Graph, error) {
+	if b.job == nil {
+		return nil, fmt.Errorf("job configuration is nil")
+	}
+	g, err := BuildFromSchema(ctx, db, b.job.RootTable, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.pruneExcluded(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// pruneExcluded removes the job's exclude_tables and their descendants from
+// g and logs what was removed. It fails if a table listed in the job's
+// relations would be removed.
+func (b *Builder) pruneExcluded(g *Graph) error {
+	if len(b.job.ExcludeTables) == 0 {
+		return nil
+	}
+	pruned, err := g.PruneTables(b.job.ExcludeTables, relationTables(b.job.Relations))
+	if err != nil {
+		return fmt.Errorf("failed to apply exclude_tables: %w", err)
+	}
+	if len(pruned) > 0 {
+		log := b.logger
+		if log == nil {
+			log = logger.NewDefault()
+		}
+		log.Infow("Pruned excluded tables from the graph", "tables", pruned)
+	}
+	return nil
+}
+
+// relationTables returns every table named in relations, recursively.
+func relationTables(relations []config.Relation) []string {
+	var tables []string
+	for _, rel := range relations {
+		tables = append(tables, rel.Table)
+		tables = append(tables, relationTables(rel.Relations)...)
+	}
+	return tables
+}
+
 // parseRelations recursively parses relations and adds them to the graph.
 // parentTable is the table these relations belong to.
 // parentPK is the primary key of the parent table (used as reference key for children).
@@ -78,16 +144,26 @@ func (b *Builder) parseRelations(g *Graph, parentTable, parentPK string, relatio
 
 		// Create node for this relation
 		node := &Node{
-			Name:           rel.Table,
-			ForeignKey:     rel.ForeignKey,
-			ReferenceKey:   parentPK,
-			DependencyType: depType,
-			IsRoot:         false,
+			Name:                  rel.Table,
+			ForeignKey:            rel.ForeignKey,
+			ReferenceKey:          parentPK,
+			DependencyType:        depType,
+			IsRoot:                false,
+			IndexHint:             rel.UseIndex,
+			DiscoveryQuery:        rel.DiscoveryQuery,
+			BatchSize:             rel.BatchSize,
+			VerificationMethod:    rel.VerificationMethod,
+			DestinationTable:      rel.DestinationTable,
+			DestinationDateColumn: rel.DestinationDateColumn,
+			Priority:              rel.Priority,
 		}
 		g.AddNode(rel.Table, node)
 
 		// Add edge from parent to child with metadata
-		g.AddEdgeWithMeta(parentTable, rel.Table, rel.ForeignKey, parentPK, depType)
+		if err := g.AddEdgeChecked(parentTable, rel.Table); err != nil {
+			return err
+		}
+		g.setEdgeMeta(parentTable, rel.Table, rel.ForeignKey, parentPK, depType)
 
 		// GA-P2-F1-T3: Enforce explicit primary key specification
 		// FAIL if primary_key is not specified - no default fallback to "id"
@@ -96,6 +172,7 @@ func (b *Builder) parseRelations(g *Graph, parentTable, parentPK string, relatio
 			return fmt.Errorf("primary_key is not specified for relation %q (explicit PK required, no default to 'id')", rel.Table)
 		}
 		g.SetPK(rel.Table, childPK)
+		g.SetColumnFilter(rel.Table, columnFilterFromConfig(rel.Columns))
 
 		// Recursively parse nested relations
 		if len(rel.Relations) > 0 {
@@ -108,6 +185,14 @@ func (b *Builder) parseRelations(g *Graph, parentTable, parentPK string, relatio
 	return nil
 }
 
+// columnFilterFromConfig converts a config column selection to a graph filter.
+func columnFilterFromConfig(sel *config.ColumnSelection) *ColumnFilter {
+	if sel == nil {
+		return nil
+	}
+	return &ColumnFilter{Include: sel.Include, Exclude: sel.Exclude}
+}
+
 // BuildFromJob is a convenience function that builds a graph directly from a job config.
 func BuildFromJob(job *config.JobConfig) (*Graph, error) {
 	return NewBuilder(job).Build()
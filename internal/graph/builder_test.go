@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -77,6 +78,83 @@ func TestBuild_SingleRelation(t *testing.T) {
 	}
 }
 
+func TestBuild_RelationBatchSize(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{Table: "documents", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N", BatchSize: 50},
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N"},
+		},
+	}
+
+	g, err := NewBuilder(job).Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	if got := g.BatchSizeFor("documents", 1000); got != 50 {
+		t.Errorf("documents: expected override 50, got %d", got)
+	}
+	if got := g.BatchSizeFor("orders", 1000); got != 1000 {
+		t.Errorf("orders: expected fallback 1000, got %d", got)
+	}
+	if got := g.BatchSizeFor("users", 500); got != 500 {
+		t.Errorf("root: expected fallback 500, got %d", got)
+	}
+	if got := g.BatchSizeFor("missing", 7); got != 7 {
+		t.Errorf("unknown table: expected fallback 7, got %d", got)
+	}
+}
+
+func TestBuild_RelationVerificationMethod(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{Table: "payments", PrimaryKey: "id", ForeignKey: "user_id", VerificationMethod: "sha256"},
+			{Table: "logins", PrimaryKey: "id", ForeignKey: "user_id"},
+		},
+	}
+
+	g, err := NewBuilder(job).Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	if got := g.GetNode("payments").VerificationMethod; got != "sha256" {
+		t.Errorf("payments: expected sha256, got %q", got)
+	}
+	if got := g.GetNode("logins").VerificationMethod; got != "" {
+		t.Errorf("logins: expected no override, got %q", got)
+	}
+}
+
+func TestBuild_RelationPriority(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{Table: "logins", PrimaryKey: "id", ForeignKey: "user_id"},
+			{Table: "payments", PrimaryKey: "id", ForeignKey: "user_id", Priority: 5},
+		},
+	}
+
+	g, err := NewBuilder(job).Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	if got := g.GetNode("payments").Priority; got != 5 {
+		t.Errorf("payments: expected priority 5, got %d", got)
+	}
+	order, err := g.CopyOrder()
+	if err != nil {
+		t.Fatalf("CopyOrder() failed: %v", err)
+	}
+	if want := []string{"users", "payments", "logins"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("CopyOrder() = %v, want %v", order, want)
+	}
+}
+
 func TestBuild_MultipleRelations(t *testing.T) {
 	job := &config.JobConfig{
 		RootTable:  "users",
@@ -175,6 +253,60 @@ func TestBuild_NestedRelations(t *testing.T) {
 	}
 }
 
+func TestEdgesFromTo_NestedRelations(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{
+				Table:          "orders",
+				PrimaryKey:     "order_id",
+				ForeignKey:     "user_id",
+				DependencyType: "1-N",
+				Relations: []config.Relation{
+					{Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N"},
+				},
+			},
+			{Table: "profiles", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-1"},
+		},
+	}
+	g, err := BuildFromJob(job)
+	if err != nil {
+		t.Fatalf("BuildFromJob() failed: %v", err)
+	}
+
+	from := g.EdgesFrom("users")
+	want := []EdgeInfo{
+		{Edge{From: "users", To: "orders"}, &EdgeMeta{ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N"}},
+		{Edge{From: "users", To: "profiles"}, &EdgeMeta{ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-1"}},
+	}
+	if !reflect.DeepEqual(from, want) {
+		t.Errorf("EdgesFrom(users) = %+v, want %+v", from, want)
+	}
+
+	to := g.EdgesTo("order_items")
+	want = []EdgeInfo{
+		{Edge{From: "orders", To: "order_items"}, &EdgeMeta{ForeignKey: "order_id", ReferenceKey: "order_id", DependencyType: "1-N"}},
+	}
+	if !reflect.DeepEqual(to, want) {
+		t.Errorf("EdgesTo(order_items) = %+v, want %+v", to, want)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		edges []EdgeInfo
+	}{
+		{"EdgesFrom(leaf)", g.EdgesFrom("order_items")},
+		{"EdgesTo(root)", g.EdgesTo("users")},
+		{"EdgesFrom(missing)", g.EdgesFrom("missing")},
+		{"EdgesTo(missing)", g.EdgesTo("missing")},
+	} {
+		if len(tc.edges) != 0 {
+			t.Errorf("%s = %+v, want empty", tc.name, tc.edges)
+		}
+	}
+}
+
 func TestBuild_DeepNesting(t *testing.T) {
 	job := &config.JobConfig{
 		RootTable:  "level1",
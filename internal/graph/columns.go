@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// ColumnFilter restricts the columns of a table that are copied and verified.
+// At most one of Include or Exclude is set (enforced by config validation).
+type ColumnFilter struct {
+	Include []string // Only these columns, in this order
+	Exclude []string // Every column except these
+}
+
+// Apply returns the selected columns. With Include set, Include is returned
+// as-is and all is ignored; with Exclude set, all is filtered in order. Column
+// names are compared case-insensitively, as MySQL does.
+func (f *ColumnFilter) Apply(all []string) []string {
+	if f == nil {
+		return all
+	}
+	if len(f.Include) > 0 {
+		return append([]string(nil), f.Include...)
+	}
+
+	excluded := make(map[string]bool, len(f.Exclude))
+	for _, col := range f.Exclude {
+		excluded[strings.ToLower(col)] = true
+	}
+	selected := make([]string, 0, len(all))
+	for _, col := range all {
+		if !excluded[strings.ToLower(col)] {
+			selected = append(selected, col)
+		}
+	}
+	return selected
+}
+
+// NeedsColumnList reports whether Apply needs the table's full column list,
+// i.e. the filter is exclusion-based.
+func (f *ColumnFilter) NeedsColumnList() bool {
+	return f != nil && len(f.Include) == 0 && len(f.Exclude) > 0
+}
+
+// Without returns a copy of the filter that also drops columns. A nil filter
+// yields an exclusion filter for columns.
+func (f *ColumnFilter) Without(columns ...string) *ColumnFilter {
+	if f == nil {
+		return &ColumnFilter{Exclude: append([]string(nil), columns...)}
+	}
+	if len(f.Include) > 0 {
+		return &ColumnFilter{Include: (&ColumnFilter{Exclude: columns}).Apply(f.Include)}
+	}
+	return &ColumnFilter{Exclude: append(append([]string(nil), f.Exclude...), columns...)}
+}
+
+// SelectList renders the SELECT column list for table: "*" for a nil filter,
+// otherwise the selected columns, quoted. Exclusion filters read the table's
+// columns from db first.
+func (f *ColumnFilter) SelectList(ctx context.Context, db *sql.DB, table string) (string, error) {
+	if f == nil {
+		return "*", nil
+	}
+
+	var all []string
+	if f.NeedsColumnList() {
+		var err error
+		if all, err = sqlutil.TableColumns(ctx, db, table); err != nil {
+			return "", err
+		}
+	}
+	columns := f.Apply(all)
+	if len(columns) == 0 {
+		return "", fmt.Errorf("column filter for %s selects no columns", table)
+	}
+	return sqlutil.SelectList(columns), nil
+}
+
+// SetColumnFilter sets the column filter for a table. A nil or empty filter
+// clears it, meaning all columns are selected.
+func (g *Graph) SetColumnFilter(table string, filter *ColumnFilter) {
+	if filter == nil || (len(filter.Include) == 0 && len(filter.Exclude) == 0) {
+		delete(g.columnFilters, table)
+		return
+	}
+	g.columnFilters[table] = filter
+}
+
+// GetColumnFilter returns the column filter for a table, or nil if all
+// columns are selected.
+func (g *Graph) GetColumnFilter(table string) *ColumnFilter {
+	return g.columnFilters[table]
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+func TestColumnFilter_Apply(t *testing.T) {
+	all := []string{"id", "name", "Password_Hash", "created_at"}
+
+	tests := []struct {
+		name   string
+		filter *ColumnFilter
+		want   []string
+	}{
+		{"nil filter selects all", nil, all},
+		{"include", &ColumnFilter{Include: []string{"id", "created_at"}}, []string{"id", "created_at"}},
+		{"exclude is case-insensitive", &ColumnFilter{Exclude: []string{"password_hash"}}, []string{"id", "name", "created_at"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Apply(all); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnFilter_Without(t *testing.T) {
+	var none *ColumnFilter
+	if got := none.Without("email"); !reflect.DeepEqual(got.Exclude, []string{"email"}) {
+		t.Errorf("nil.Without() = %+v", got)
+	}
+	include := &ColumnFilter{Include: []string{"id", "email", "name"}}
+	if got := include.Without("EMAIL"); !reflect.DeepEqual(got.Include, []string{"id", "name"}) {
+		t.Errorf("include.Without() = %+v", got)
+	}
+	exclude := &ColumnFilter{Exclude: []string{"secret"}}
+	if got := exclude.Without("email"); !reflect.DeepEqual(got.Exclude, []string{"secret", "email"}) {
+		t.Errorf("exclude.Without() = %+v", got)
+	}
+	if len(exclude.Exclude) != 1 {
+		t.Errorf("Without must not modify the receiver, got %+v", exclude)
+	}
+}
+
+func TestBuilder_SetsColumnFilters(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Columns:    &config.ColumnSelection{Exclude: []string{"password_hash"}},
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N",
+				Columns: &config.ColumnSelection{Include: []string{"id", "user_id", "total"}}},
+			{Table: "audit", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N"},
+		},
+	}
+	g, err := BuildFromJob(job)
+	if err != nil {
+		t.Fatalf("BuildFromJob failed: %v", err)
+	}
+
+	if f := g.GetColumnFilter("users"); f == nil || !f.NeedsColumnList() {
+		t.Errorf("expected exclude filter on users, got %+v", f)
+	}
+	if f := g.GetColumnFilter("orders"); f == nil || len(f.Include) != 3 {
+		t.Errorf("expected include filter on orders, got %+v", f)
+	}
+	if f := g.GetColumnFilter("audit"); f != nil {
+		t.Errorf("expected no filter on audit, got %+v", f)
+	}
+}
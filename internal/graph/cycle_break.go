@@ -0,0 +1,124 @@
+package graph
+
+import "sort"
+
+// SuggestCycleBreaks returns a small set of edges whose removal makes the graph
+// acyclic. It is a greedy heuristic, not a minimum feedback arc set: while a
+// cycle remains, it walks the cycle from its entry table (the root when it
+// participates, otherwise a table that also has a parent outside the cycle)
+// and proposes the edge that closes the loop back into that table. For a job
+// configuration this is the relation that points "back up" the tree, which is
+// usually the one to drop or restructure.
+//
+// Returns nil if the graph has no cycles. The receiver is not modified.
+func (g *Graph) SuggestCycleBreaks() []Edge {
+	work := &Graph{
+		Nodes:    g.Nodes,
+		Children: make(map[string][]string, len(g.Children)),
+		Root:     g.Root,
+	}
+	for parent, children := range g.Children {
+		work.Children[parent] = append([]string(nil), children...)
+	}
+
+	var breaks []Edge
+	for {
+		stuck := work.unprocessedNodes()
+		if len(stuck) == 0 {
+			return breaks
+		}
+
+		start, ok := work.cycleEntry(stuck)
+		if !ok {
+			return breaks
+		}
+		path := work.FindCyclePath(start, stuck)
+		if len(path) < 2 {
+			return breaks
+		}
+
+		edge := Edge{From: path[len(path)-2], To: start}
+		breaks = append(breaks, edge)
+		work.removeEdge(edge)
+	}
+}
+
+// unprocessedNodes runs Kahn's algorithm over Children and returns the set of
+// nodes that could not be processed (cycle participants and tables blocked
+// behind them).
+func (g *Graph) unprocessedNodes() map[string]bool {
+	inDegree := g.CalculateInDegrees()
+	queue := g.InitializeQueue(inDegree)
+
+	processed := make(map[string]bool, len(g.Nodes))
+	for !queue.IsEmpty() {
+		node, _ := queue.Dequeue()
+		processed[node] = true
+		for _, child := range g.Children[node] {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				queue.Enqueue(child)
+			}
+		}
+	}
+
+	stuck := make(map[string]bool)
+	for name := range g.Nodes {
+		if !processed[name] {
+			stuck[name] = true
+		}
+	}
+	return stuck
+}
+
+// cycleEntry picks a deterministic starting table for the next cycle to break.
+// Preference: the graph root, then a participant with a parent outside the
+// stuck set, then the alphabetically first participant.
+func (g *Graph) cycleEntry(stuck map[string]bool) (string, bool) {
+	var participants []string
+	for name := range stuck {
+		if g.canReachSelf(name, stuck) {
+			participants = append(participants, name)
+		}
+	}
+	if len(participants) == 0 {
+		return "", false
+	}
+	sort.Strings(participants)
+
+	for _, p := range participants {
+		if p == g.Root {
+			return p, true
+		}
+	}
+
+	hasOutsideParent := make(map[string]bool)
+	for parent, children := range g.Children {
+		if stuck[parent] {
+			continue
+		}
+		for _, child := range children {
+			hasOutsideParent[child] = true
+		}
+	}
+	for _, p := range participants {
+		if hasOutsideParent[p] {
+			return p, true
+		}
+	}
+
+	return participants[0], true
+}
+
+// removeEdge drops every parent -> child occurrence of edge from Children.
+// Only used on SuggestCycleBreaks' private working copy.
+func (g *Graph) removeEdge(edge Edge) {
+	children := g.Children[edge.From]
+	kept := children[:0]
+	for _, child := range children {
+		if child != edge.To {
+			kept = append(kept, child)
+		}
+	}
+	g.Children[edge.From] = kept
+}
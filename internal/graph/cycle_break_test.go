@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newEdgeGraph builds a graph from root plus parent->child pairs.
+func newEdgeGraph(root string, edges ...[2]string) *Graph {
+	g := NewGraph(root, "id")
+	for _, e := range edges {
+		for _, name := range e {
+			if !g.HasNode(name) {
+				g.AddNode(name, nil)
+			}
+		}
+		g.AddEdge(e[0], e[1])
+	}
+	return g
+}
+
+// assertBreaksAcyclic removes the suggested edges from a copy of g and checks
+// the result sorts cleanly.
+func assertBreaksAcyclic(t *testing.T, g *Graph, breaks []Edge) {
+	t.Helper()
+	if len(breaks) == 0 {
+		t.Fatal("expected at least one suggested break")
+	}
+	work := &Graph{Nodes: g.Nodes, Children: make(map[string][]string)}
+	for parent, children := range g.Children {
+		work.Children[parent] = append([]string(nil), children...)
+	}
+	for _, e := range breaks {
+		found := false
+		for _, child := range g.Children[e.From] {
+			if child == e.To {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("suggested edge %s -> %s does not exist in graph", e.From, e.To)
+		}
+		work.removeEdge(e)
+	}
+	if work.HasCycle() {
+		t.Errorf("graph still cyclic after removing %v", breaks)
+	}
+}
+
+func TestSuggestCycleBreaks_NoCycle(t *testing.T) {
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "C"})
+	if breaks := g.SuggestCycleBreaks(); breaks != nil {
+		t.Errorf("expected nil for acyclic graph, got %v", breaks)
+	}
+}
+
+func TestSuggestCycleBreaks_TwoNode(t *testing.T) {
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "A"})
+
+	breaks := g.SuggestCycleBreaks()
+	assertBreaksAcyclic(t, g, breaks)
+	if len(breaks) != 1 || breaks[0] != (Edge{From: "B", To: "A"}) {
+		t.Errorf("expected [B -> A], got %v", breaks)
+	}
+}
+
+func TestSuggestCycleBreaks_ThreeNode(t *testing.T) {
+	g := newEdgeGraph("root",
+		[2]string{"root", "A"},
+		[2]string{"A", "B"},
+		[2]string{"B", "C"},
+		[2]string{"C", "A"},
+	)
+
+	breaks := g.SuggestCycleBreaks()
+	assertBreaksAcyclic(t, g, breaks)
+	if len(breaks) != 1 || breaks[0] != (Edge{From: "C", To: "A"}) {
+		t.Errorf("expected back edge [C -> A], got %v", breaks)
+	}
+}
+
+func TestSuggestCycleBreaks_DiamondWithBackEdge(t *testing.T) {
+	// root -> left -> bottom, root -> right -> bottom, bottom -> root
+	g := newEdgeGraph("root",
+		[2]string{"root", "left"},
+		[2]string{"root", "right"},
+		[2]string{"left", "bottom"},
+		[2]string{"right", "bottom"},
+		[2]string{"bottom", "root"},
+	)
+
+	breaks := g.SuggestCycleBreaks()
+	assertBreaksAcyclic(t, g, breaks)
+	if len(breaks) != 1 || breaks[0] != (Edge{From: "bottom", To: "root"}) {
+		t.Errorf("expected [bottom -> root], got %v", breaks)
+	}
+}
+
+func TestSuggestCycleBreaks_DisjointCycles(t *testing.T) {
+	g := newEdgeGraph("root",
+		[2]string{"root", "A"},
+		[2]string{"A", "B"},
+		[2]string{"B", "A"},
+		[2]string{"root", "X"},
+		[2]string{"X", "Y"},
+		[2]string{"Y", "X"},
+	)
+
+	breaks := g.SuggestCycleBreaks()
+	assertBreaksAcyclic(t, g, breaks)
+	if len(breaks) != 2 {
+		t.Errorf("expected 2 breaks, got %v", breaks)
+	}
+}
+
+func TestSuggestCycleBreaks_DoesNotMutateGraph(t *testing.T) {
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "A"})
+	_ = g.SuggestCycleBreaks()
+	if edgeCount(g) != 2 {
+		t.Errorf("expected source graph to keep 2 edges, got %d", edgeCount(g))
+	}
+}
+
+func TestCycleError_IncludesSuggestions(t *testing.T) {
+	g := newEdgeGraph("root",
+		[2]string{"root", "A"},
+		[2]string{"A", "B"},
+		[2]string{"B", "A"},
+	)
+
+	_, err := g.TopologicalSort()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected *CycleError, got %v", err)
+	}
+	if len(cycleErr.Info.SuggestedBreaks) != 1 {
+		t.Fatalf("expected 1 suggested break, got %v", cycleErr.Info.SuggestedBreaks)
+	}
+	if !strings.Contains(err.Error(), "consider removing edge B -> A") {
+		t.Errorf("error message should suggest removing B -> A, got:\n%s", err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// destinationDateLayouts are the textual DATE/DATETIME/TIMESTAMP formats
+// MySQL returns when the driver does not parse times (parseTime=false).
+var destinationDateLayouts = []string{
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+// ExpandDestinationTemplate replaces the {year} (4 digits) and {month}
+// (2 digits) placeholders of a destination_table template with date's values.
+func ExpandDestinationTemplate(tmpl string, date time.Time) string {
+	return strings.NewReplacer(
+		"{year}", fmt.Sprintf("%04d", date.Year()),
+		"{month}", fmt.Sprintf("%02d", int(date.Month())),
+	).Replace(tmpl)
+}
+
+// HasDestinationTemplate reports whether table is copied into a computed
+// destination table rather than a table of the same name.
+func (g *Graph) HasDestinationTemplate(table string) bool {
+	node := g.GetNode(table)
+	return node != nil && node.DestinationTable != ""
+}
+
+// DestinationDateColumn returns the column whose value dates each row of
+// table for its destination template, or "" when the job run date is used.
+func (g *Graph) DestinationDateColumn(table string) string {
+	if node := g.GetNode(table); node != nil && node.DestinationTable != "" {
+		return node.DestinationDateColumn
+	}
+	return ""
+}
+
+// DestinationTable returns the destination table name for rows of table
+// dated date: the expanded destination template, or table itself when it
+// has none.
+func (g *Graph) DestinationTable(table string, date time.Time) string {
+	node := g.GetNode(table)
+	if node == nil || node.DestinationTable == "" {
+		return table
+	}
+	return ExpandDestinationTemplate(node.DestinationTable, date)
+}
+
+// DestinationTableForValue is DestinationTable for a row dated by the raw
+// value scanned from its destination date column: a time.Time, or the
+// DATE/DATETIME text MySQL returns without parseTime. NULL is an error, as a
+// row without a date has no destination.
+func (g *Graph) DestinationTableForValue(table string, value interface{}) (string, error) {
+	date, err := parseDestinationDate(value)
+	if err != nil {
+		return "", fmt.Errorf("table %s column %s: %w", table, g.DestinationDateColumn(table), err)
+	}
+	return g.DestinationTable(table, date), nil
+}
+
+func parseDestinationDate(value interface{}) (time.Time, error) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, fmt.Errorf("destination date is NULL")
+	case time.Time:
+		return v, nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return time.Time{}, fmt.Errorf("unsupported destination date value %v (%T)", v, v)
+	}
+	for _, layout := range destinationDateLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse destination date %q", text)
+}
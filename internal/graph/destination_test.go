@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+func TestDestinationTable(t *testing.T) {
+	g, err := NewBuilder(&config.JobConfig{
+		RootTable: "orders", PrimaryKey: "id", DestinationTable: "orders_{year}{month}",
+		Relations: []config.Relation{
+			{Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id",
+				DestinationTable: "items_{year}", DestinationDateColumn: "created_at"},
+			{Table: "notes", PrimaryKey: "id", ForeignKey: "order_id"},
+		},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	jan := time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)
+	if got := g.DestinationTable("orders", jan); got != "orders_202301" {
+		t.Errorf("orders: got %q, want orders_202301", got)
+	}
+	if got := g.DestinationTable("notes", jan); got != "notes" {
+		t.Errorf("notes: got %q, want notes", got)
+	}
+	if g.HasDestinationTemplate("notes") || !g.HasDestinationTemplate("order_items") {
+		t.Error("HasDestinationTemplate reported the wrong tables")
+	}
+	if got := g.DestinationDateColumn("order_items"); got != "created_at" {
+		t.Errorf("order_items date column: got %q", got)
+	}
+
+	for _, value := range []interface{}{
+		[]byte("2022-12-31 23:59:59"),
+		"2022-12-31",
+		time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC),
+	} {
+		got, err := g.DestinationTableForValue("order_items", value)
+		if err != nil || got != "items_2022" {
+			t.Errorf("value %v: got %q, %v; want items_2022", value, got, err)
+		}
+	}
+	for _, value := range []interface{}{nil, "not a date", int64(20221231)} {
+		if _, err := g.DestinationTableForValue("order_items", value); err == nil {
+			t.Errorf("value %v: expected error", value)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dbsmedya/goarchive/internal/sqlutil"
+)
+
+// namedIdentifier is a name the phases emit into SQL and what it names.
+type namedIdentifier struct {
+	kind string
+	name string
+}
+
+// CheckIdentifiers validates, with sqlutil.CheckIdentifier, every table,
+// primary key, foreign key, index hint, date column and column filter name
+// the phases emit into SQL, so a graph built by hand or from the live schema
+// fails before any query runs rather than mid-batch. Tables are checked in
+// name order and the first invalid name is returned.
+func (g *Graph) CheckIdentifiers() error {
+	tables := g.AllNodes()
+	sort.Strings(tables)
+	for _, table := range tables {
+		if err := sqlutil.CheckIdentifier(table); err != nil {
+			return fmt.Errorf("table: %w", err)
+		}
+		if err := sqlutil.CheckIdentifier(g.GetPK(table)); err != nil {
+			return fmt.Errorf("table %s primary key: %w", table, err)
+		}
+		for _, id := range g.optionalIdentifiers(table) {
+			if id.name == "" {
+				continue
+			}
+			if err := sqlutil.CheckIdentifier(id.name); err != nil {
+				return fmt.Errorf("table %s %s: %w", table, id.kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// optionalIdentifiers lists the names of table other than its own and its
+// primary key; unset names are empty.
+func (g *Graph) optionalIdentifiers(table string) []namedIdentifier {
+	var ids []namedIdentifier
+	if node := g.GetNode(table); node != nil {
+		ids = append(ids,
+			namedIdentifier{"foreign key", node.ForeignKey},
+			namedIdentifier{"reference key", node.ReferenceKey},
+			namedIdentifier{"index hint", node.IndexHint},
+			namedIdentifier{"destination date column", node.DestinationDateColumn})
+	}
+	for _, e := range g.EdgesTo(table) {
+		if e.Meta != nil {
+			ids = append(ids,
+				namedIdentifier{"foreign key", e.Meta.ForeignKey},
+				namedIdentifier{"reference key", e.Meta.ReferenceKey})
+		}
+	}
+	if filter := g.GetColumnFilter(table); filter != nil {
+		for _, col := range filter.Include {
+			ids = append(ids, namedIdentifier{"column", col})
+		}
+		for _, col := range filter.Exclude {
+			ids = append(ids, namedIdentifier{"column", col})
+		}
+	}
+	return ids
+}
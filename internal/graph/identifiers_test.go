@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// createIdentifierGraph names tables and columns with a reserved word, a
+// backtick and a space, all legal in MySQL once quoted.
+func createIdentifierGraph() *Graph {
+	g := NewGraph("order", "key")
+	g.AddNode("line`items", &Node{Name: "line`items", ForeignKey: "order id", ReferenceKey: "key", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("order", "line`items", "order id", "key", "1-N")
+	return g
+}
+
+func TestCheckIdentifiers(t *testing.T) {
+	g := createIdentifierGraph()
+	if err := g.CheckIdentifiers(); err != nil {
+		t.Fatalf("quotable names must pass, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(g *Graph)
+		wantErr string
+	}{
+		{"primary key", func(g *Graph) { g.SetPK("order", "") }, "table order primary key: identifier is empty"},
+		{"index hint", func(g *Graph) { g.GetNode("line`items").IndexHint = "idx " }, "table line`items index hint"},
+		{"edge metadata", func(g *Graph) {
+			g.setEdgeMeta("order", "line`items", strings.Repeat("c", 65), "key", "1-N")
+		}, "table line`items foreign key"},
+		{"column filter", func(g *Graph) {
+			g.SetColumnFilter("order", &ColumnFilter{Exclude: []string{"bad\x00"}})
+		}, "table order column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := createIdentifierGraph()
+			tt.mutate(g)
+			err := g.CheckIdentifiers()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package graph
 
 import (
+	"container/heap"
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -87,6 +89,7 @@ type CycleInfo struct {
 	UnprocessedNodes  []string // Nodes that couldn't be processed (part of or blocked by cycle)
 	CycleParticipants []string // Nodes that are actually part of a cycle (subset of UnprocessedNodes)
 	CyclePath         []string // Ordered path showing the cycle (e.g., [A, B, C, A])
+	SuggestedBreaks   []Edge   // Edges whose removal makes the graph acyclic (see SuggestCycleBreaks)
 }
 
 // CycleError represents a cycle detection error with detailed information about
@@ -130,13 +133,30 @@ func (e *CycleError) Error() string {
 		}
 	}
 
+	// Suggest a fix: each edge corresponds to a relation nested under From
+	for _, edge := range e.Info.SuggestedBreaks {
+		msg += fmt.Sprintf("\nSuggestion: consider removing edge %s -> %s", edge.From, edge.To)
+	}
+
 	return msg
 }
 
+// ctxCheckInterval is how many nodes the Kahn loops process between
+// ctx.Err() checks, keeping cancellation prompt without paying for a check
+// on every node.
+const ctxCheckInterval = 256
+
 // DetectIncompleteProcessing runs Kahn's algorithm and returns information
 // about any nodes that couldn't be processed. If all nodes are processed,
 // returns nil (no cycle). This is useful for diagnosing dependency issues.
 func (g *Graph) DetectIncompleteProcessing() *CycleInfo {
+	info, _ := g.DetectIncompleteProcessingContext(context.Background())
+	return info
+}
+
+// DetectIncompleteProcessingContext is DetectIncompleteProcessing with
+// cancellation: it returns ctx.Err() if ctx is done before the scan finishes.
+func (g *Graph) DetectIncompleteProcessingContext(ctx context.Context) (*CycleInfo, error) {
 	inDegree := g.CalculateInDegrees()
 	queue := g.InitializeQueue(inDegree)
 
@@ -144,6 +164,11 @@ func (g *Graph) DetectIncompleteProcessing() *CycleInfo {
 
 	// Process all reachable nodes
 	for !queue.IsEmpty() {
+		if len(processed)%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		node, _ := queue.Dequeue()
 		processed[node] = true
 
@@ -155,7 +180,7 @@ func (g *Graph) DetectIncompleteProcessing() *CycleInfo {
 		}
 	}
 
-	return g.buildCycleInfoFromProcessed(processed)
+	return g.buildCycleInfoFromProcessed(processed), nil
 }
 
 // HasCycle returns true if the dependency graph contains a cycle.
@@ -252,6 +277,12 @@ func (g *Graph) dfsCanReach(current, target string, visited, allowedNodes map[st
 // The result is a valid copy order (parent tables first, child tables after).
 // Returns ErrCycleDetected if the graph contains a cycle.
 func (g *Graph) TopologicalSort() ([]string, error) {
+	return g.TopologicalSortContext(context.Background())
+}
+
+// TopologicalSortContext is TopologicalSort with cancellation: the Kahn loop
+// checks ctx periodically and returns ctx.Err() once it is done.
+func (g *Graph) TopologicalSortContext(ctx context.Context) ([]string, error) {
 	// Step 1: Calculate in-degrees for all nodes
 	inDegree := g.CalculateInDegrees()
 
@@ -263,6 +294,12 @@ func (g *Graph) TopologicalSort() ([]string, error) {
 
 	// Step 3: Process nodes iteratively
 	for !queue.IsEmpty() {
+		if processed%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		// Dequeue the next node
 		node, _ := queue.Dequeue()
 
@@ -294,18 +331,116 @@ func (g *Graph) TopologicalSort() ([]string, error) {
 	return result, nil
 }
 
+// TopologicalSortWithPriorityContext is TopologicalSortContext honoring
+// Node.Priority: whenever several tables are ready (in-degree 0) the one
+// with the highest priority is taken first, ties broken by name. A table
+// still comes after all of its parents, whatever its priority. CopyOrder
+// uses it when any table sets a priority.
+func (g *Graph) TopologicalSortWithPriorityContext(ctx context.Context) ([]string, error) {
+	inDegree := g.CalculateInDegrees()
+
+	ready := &priorityHeap{g: g}
+	for name, degree := range inDegree {
+		if degree == 0 {
+			ready.names = append(ready.names, name)
+		}
+	}
+	heap.Init(ready)
+
+	result := make([]string, 0, len(g.Nodes))
+	for ready.Len() > 0 {
+		if len(result)%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		node := heap.Pop(ready).(string)
+		result = append(result, node)
+
+		for _, child := range g.GetChildren(node) {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				heap.Push(ready, child)
+			}
+		}
+	}
+
+	if len(result) != len(g.Nodes) {
+		processedSet := make(map[string]bool, len(result))
+		for _, node := range result {
+			processedSet[node] = true
+		}
+		return nil, &CycleError{Info: g.buildCycleInfoFromProcessed(processedSet)}
+	}
+
+	return result, nil
+}
+
+// hasPriorities reports whether any table sets a non-zero Node.Priority.
+func (g *Graph) hasPriorities() bool {
+	for _, node := range g.Nodes {
+		if node.Priority != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// priorityHeap is a max-heap of table names by Node.Priority for
+// TopologicalSortWithPriorityContext, breaking ties by name.
+type priorityHeap struct {
+	g     *Graph
+	names []string
+}
+
+func (h *priorityHeap) Len() int { return len(h.names) }
+
+func (h *priorityHeap) Less(i, j int) bool {
+	pi, pj := h.g.Nodes[h.names[i]].Priority, h.g.Nodes[h.names[j]].Priority
+	if pi != pj {
+		return pi > pj
+	}
+	return h.names[i] < h.names[j]
+}
+
+func (h *priorityHeap) Swap(i, j int) { h.names[i], h.names[j] = h.names[j], h.names[i] }
+
+func (h *priorityHeap) Push(x any) { h.names = append(h.names, x.(string)) }
+
+func (h *priorityHeap) Pop() any {
+	n := len(h.names)
+	x := h.names[n-1]
+	h.names = h.names[:n-1]
+	return x
+}
+
 // CopyOrder returns the order in which tables should be copied during archiving.
 // Parent tables are copied before child tables to satisfy foreign key constraints.
-// This is the topological order of the dependency graph.
+// This is the topological order of the dependency graph, honoring table
+// priorities (TopologicalSortWithPriorityContext) when any is set.
 func (g *Graph) CopyOrder() ([]string, error) {
-	return g.TopologicalSort()
+	return g.CopyOrderContext(context.Background())
+}
+
+// CopyOrderContext is CopyOrder with cancellation.
+func (g *Graph) CopyOrderContext(ctx context.Context) ([]string, error) {
+	if g.hasPriorities() {
+		return g.TopologicalSortWithPriorityContext(ctx)
+	}
+	return g.TopologicalSortContext(ctx)
 }
 
 // DeleteOrder returns the order in which tables should be deleted during archiving.
 // Child tables are deleted before parent tables to satisfy foreign key constraints.
-// This is the reverse of the topological order.
+// This is the reverse of the copy order (CopyOrder).
 func (g *Graph) DeleteOrder() ([]string, error) {
-	copyOrder, err := g.TopologicalSort()
+	return g.DeleteOrderContext(context.Background())
+}
+
+// DeleteOrderContext is DeleteOrder with cancellation.
+func (g *Graph) DeleteOrderContext(ctx context.Context) ([]string, error) {
+	copyOrder, err := g.CopyOrderContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -319,6 +454,69 @@ func (g *Graph) DeleteOrder() ([]string, error) {
 	return deleteOrder, nil
 }
 
+// ForEachInCopyOrderContext calls fn for every table in copy order (parents
+// first, honoring table priorities like CopyOrder) without building the
+// order as a slice, so callers can process each table as soon as it is
+// ordered. It stops at and returns the first error fn returns. On a cyclic
+// graph fn sees the tables outside the cycle, then a CycleError is returned.
+// Ready tables are taken FIFO, or highest priority first when any table sets
+// one.
+func (g *Graph) ForEachInCopyOrderContext(ctx context.Context, fn func(table string) error) error {
+	var push func(string)
+	var pop func() (string, bool)
+	if g.hasPriorities() {
+		ready := &priorityHeap{g: g}
+		push = func(name string) { heap.Push(ready, name) }
+		pop = func() (string, bool) {
+			if ready.Len() == 0 {
+				return "", false
+			}
+			return heap.Pop(ready).(string), true
+		}
+	} else {
+		queue := NewProcessingQueue()
+		push, pop = queue.Enqueue, queue.Dequeue
+	}
+	inDegree := g.CalculateInDegrees()
+	for name, d := range inDegree {
+		if d == 0 {
+			push(name)
+		}
+	}
+
+	visited := 0
+	for table, ok := pop(); ok; table, ok = pop() {
+		if visited%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if err := fn(table); err != nil {
+			return err
+		}
+		visited++
+		for _, child := range g.GetChildren(table) {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				push(child)
+			}
+		}
+	}
+
+	if visited != len(g.Nodes) {
+		// Every visited table reached in-degree 0; the rest are on or
+		// behind a cycle.
+		processed := make(map[string]bool, visited)
+		for name, d := range inDegree {
+			if d == 0 {
+				processed[name] = true
+			}
+		}
+		return &CycleError{Info: g.buildCycleInfoFromProcessed(processed)}
+	}
+	return nil
+}
+
 // Validate checks the graph for structural issues such as cycles.
 // This should be called after building the graph to fail fast at startup
 // rather than discovering issues during processing.
@@ -370,5 +568,6 @@ func (g *Graph) buildCycleInfoFromProcessed(processed map[string]bool) *CycleInf
 		UnprocessedNodes:  unprocessed,
 		CycleParticipants: cycleParticipants,
 		CyclePath:         cyclePath,
+		SuggestedBreaks:   g.SuggestCycleBreaks(),
 	}
 }
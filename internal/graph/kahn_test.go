@@ -1,7 +1,9 @@
 package graph
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"sort"
 	"testing"
@@ -1436,3 +1438,247 @@ func TestCycleInfo_ProcessedPlusUnprocessedEqualsTotal(t *testing.T) {
 			info.ProcessedNodes, len(info.UnprocessedNodes), actual, info.TotalNodes)
 	}
 }
+
+// cancelAfterContext reports context.Canceled once Err has been called more
+// than n times, cancelling deterministically partway through a Kahn loop.
+type cancelAfterContext struct {
+	context.Context
+	n     int
+	calls int
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.calls++
+	if c.calls > c.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+// buildWideGraph returns a root with n direct children.
+func buildWideGraph(n int) *Graph {
+	g := NewGraph("root", "id")
+	for i := 0; i < n; i++ {
+		child := fmt.Sprintf("t%d", i)
+		g.AddNode(child, &Node{Name: child, ForeignKey: "root_id", ReferenceKey: "id", DependencyType: "1-N"})
+		g.AddEdge("root", child)
+	}
+	return g
+}
+
+func TestTopologicalSortContext_CancelledMidSort(t *testing.T) {
+	g := buildWideGraph(10 * ctxCheckInterval)
+
+	ctx := &cancelAfterContext{Context: context.Background(), n: 3}
+	order, err := g.TopologicalSortContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if order != nil {
+		t.Errorf("expected no partial order, got %d tables", len(order))
+	}
+	if ctx.calls != 4 {
+		t.Errorf("expected sort to stop at the first check after cancellation, Err called %d times", ctx.calls)
+	}
+
+	if _, err := g.DeleteOrderContext(&cancelAfterContext{Context: context.Background(), n: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("DeleteOrderContext: expected context.Canceled, got %v", err)
+	}
+	if _, err := g.DetectIncompleteProcessingContext(&cancelAfterContext{Context: context.Background(), n: 2}); !errors.Is(err, context.Canceled) {
+		t.Errorf("DetectIncompleteProcessingContext: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTopologicalSortContext_MatchesTopologicalSort(t *testing.T) {
+	g := buildWideGraph(ctxCheckInterval + 1)
+
+	want, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort: %v", err)
+	}
+	got, err := g.TopologicalSortContext(context.Background())
+	if err != nil {
+		t.Fatalf("TopologicalSortContext: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("context variant must produce the same order")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.CopyOrderContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("CopyOrderContext with cancelled ctx: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTopologicalSortWithPriority_SiblingBranches(t *testing.T) {
+	// customers -> {invoices -> invoice_lines, orders -> items}. By name,
+	// invoices comes before orders; a priority on orders puts it first, and
+	// a negative priority on items puts it after the invoices branch.
+	g := NewGraph("customers", "id")
+	for _, name := range []string{"invoices", "invoice_lines", "orders", "items"} {
+		g.AddNode(name, &Node{Name: name})
+	}
+	g.AddEdge("customers", "invoices")
+	g.AddEdge("invoices", "invoice_lines")
+	g.AddEdge("customers", "orders")
+	g.AddEdge("orders", "items")
+
+	got, err := g.TopologicalSortWithPriorityContext(context.Background())
+	if err != nil {
+		t.Fatalf("TopologicalSortWithPriorityContext: %v", err)
+	}
+	if byName := []string{"customers", "invoices", "invoice_lines", "orders", "items"}; !reflect.DeepEqual(got, byName) {
+		t.Errorf("without priorities: got %v, want ties broken by name %v", got, byName)
+	}
+
+	g.Nodes["orders"].Priority = 10
+	g.Nodes["items"].Priority = -5
+	want := []string{"customers", "orders", "invoices", "invoice_lines", "items"}
+	got, err = g.TopologicalSortWithPriorityContext(context.Background())
+	if err != nil {
+		t.Fatalf("TopologicalSortWithPriorityContext: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// CopyOrder follows the priorities once any is set; delete is the reverse.
+	copyOrder, _ := g.CopyOrder()
+	if !reflect.DeepEqual(copyOrder, want) {
+		t.Errorf("CopyOrder() = %v, want %v", copyOrder, want)
+	}
+	deleteOrder, _ := g.DeleteOrder()
+	if deleteOrder[0] != "items" || deleteOrder[len(deleteOrder)-1] != "customers" {
+		t.Errorf("DeleteOrder() = %v, want the reverse of %v", deleteOrder, want)
+	}
+}
+
+func TestTopologicalSortWithPriority_RespectsDependencies(t *testing.T) {
+	// A high-priority child never jumps ahead of its parent.
+	g := NewGraph("a", "id")
+	g.AddNode("b", &Node{Name: "b"})
+	g.AddNode("c", &Node{Name: "c", Priority: 100})
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+
+	got, err := g.TopologicalSortWithPriorityContext(context.Background())
+	if err != nil {
+		t.Fatalf("TopologicalSortWithPriorityContext: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	g.AddEdge("c", "b")
+	var cycleErr *CycleError
+	if _, err := g.TopologicalSortWithPriorityContext(context.Background()); !errors.As(err, &cycleErr) {
+		t.Errorf("expected *CycleError, got %v", err)
+	}
+}
+
+// assertTopological fails unless every edge of g runs forward in order and
+// order covers every table.
+func assertTopological(t *testing.T, g *Graph, order []string) {
+	t.Helper()
+	if len(order) != len(g.Nodes) {
+		t.Fatalf("visited %d tables %v, want %d", len(order), order, len(g.Nodes))
+	}
+	pos := make(map[string]int, len(order))
+	for i, table := range order {
+		pos[table] = i
+	}
+	for parent, children := range g.Children {
+		for _, child := range children {
+			if pos[parent] > pos[child] {
+				t.Errorf("order %v visits %s -> %s in the wrong direction", order, parent, child)
+			}
+		}
+	}
+}
+
+func TestForEachInCopyOrderContext_VisitsTopologically(t *testing.T) {
+	g := newEdgeGraph("a", [2]string{"a", "b"}, [2]string{"a", "c"}, [2]string{"b", "d"}, [2]string{"c", "d"}, [2]string{"d", "e"})
+
+	var copyOrder []string
+	if err := g.ForEachInCopyOrderContext(context.Background(), func(table string) error {
+		copyOrder = append(copyOrder, table)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachInCopyOrderContext: %v", err)
+	}
+	assertTopological(t, g, copyOrder)
+}
+
+func TestForEachInCopyOrderContext_Priorities(t *testing.T) {
+	g := NewGraph("customers", "id")
+	for _, name := range []string{"invoices", "invoice_lines", "orders", "items"} {
+		g.AddNode(name, &Node{Name: name})
+	}
+	g.AddEdge("customers", "invoices")
+	g.AddEdge("invoices", "invoice_lines")
+	g.AddEdge("customers", "orders")
+	g.AddEdge("orders", "items")
+	g.Nodes["orders"].Priority = 10
+	g.Nodes["items"].Priority = -5
+
+	var got []string
+	if err := g.ForEachInCopyOrderContext(context.Background(), func(table string) error {
+		got = append(got, table)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachInCopyOrderContext: %v", err)
+	}
+	if want, _ := g.CopyOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ForEachInCopyOrderContext visited %v, want CopyOrder's %v", got, want)
+	}
+}
+
+func TestForEachInCopyOrderContext_StopsOnCallbackError(t *testing.T) {
+	g := newEdgeGraph("a", [2]string{"a", "b"}, [2]string{"a", "c"}, [2]string{"b", "d"}, [2]string{"c", "d"}, [2]string{"d", "e"})
+	stop := errors.New("stop")
+
+	calls := 0
+	err := g.ForEachInCopyOrderContext(context.Background(), func(table string) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("got %v, want the callback's error", err)
+	}
+	if calls != 2 {
+		t.Errorf("callback ran %d times after returning an error, want 2", calls)
+	}
+}
+
+func TestForEachInCopyOrderContext_CycleAndCancellation(t *testing.T) {
+	g := NewGraph("a", "id")
+	g.AddNode("b", &Node{Name: "b"})
+	g.AddNode("c", &Node{Name: "c"})
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "b")
+
+	var visited []string
+	err := g.ForEachInCopyOrderContext(context.Background(), func(table string) error {
+		visited = append(visited, table)
+		return nil
+	})
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected *CycleError, got %v", err)
+	}
+	if !reflect.DeepEqual(visited, []string{"a"}) || len(cycleErr.Info.UnprocessedNodes) != 2 {
+		t.Errorf("visited %v, unprocessed %v; want [a] and the b/c cycle", visited, cycleErr.Info.UnprocessedNodes)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	acyclic := newEdgeGraph("a", [2]string{"a", "b"})
+	if err := acyclic.ForEachInCopyOrderContext(ctx, func(string) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled context: got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ToMermaid renders the graph as a Mermaid "graph TD" block for pasting into
+// Markdown. Each edge is labelled with the child's foreign key column and
+// dependency type, e.g. orders -->|customer_id (1-N)| order_items. The root
+// table is styled with the "root" class and tables without children with the
+// "leaf" class. Nodes are listed root first, then by name, and edges are
+// sorted by parent then child, so the output is stable across runs.
+func (g *Graph) ToMermaid() string {
+	nodes := g.AllNodes()
+	sort.Slice(nodes, func(i, j int) bool {
+		if (nodes[i] == g.Root) != (nodes[j] == g.Root) {
+			return nodes[i] == g.Root
+		}
+		return nodes[i] < nodes[j]
+	})
+	edges := g.AllEdges()
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	var leaves []string
+	for _, name := range nodes {
+		fmt.Fprintf(&sb, "    %s[\"%s\"]\n", mermaidID(name), mermaidLabel(name))
+		if name != g.Root && len(g.Children[name]) == 0 {
+			leaves = append(leaves, mermaidID(name))
+		}
+	}
+	for _, e := range edges {
+		if label := g.mermaidEdgeLabel(e.From, e.To); label != "" {
+			fmt.Fprintf(&sb, "    %s -->|%s| %s\n", mermaidID(e.From), mermaidLabel(label), mermaidID(e.To))
+		} else {
+			fmt.Fprintf(&sb, "    %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+		}
+	}
+
+	sb.WriteString("    classDef root fill:#f9d,stroke:#333,stroke-width:2px\n")
+	sb.WriteString("    classDef leaf fill:#dfd,stroke:#333\n")
+	if g.HasNode(g.Root) {
+		fmt.Fprintf(&sb, "    class %s root\n", mermaidID(g.Root))
+	}
+	if len(leaves) > 0 {
+		fmt.Fprintf(&sb, "    class %s leaf\n", strings.Join(leaves, ","))
+	}
+	return sb.String()
+}
+
+// WriteMermaid writes ToMermaid to w.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	_, err := io.WriteString(w, g.ToMermaid())
+	return err
+}
+
+// mermaidEdgeLabel is "fk (type)" from the edge metadata, falling back to the
+// child node for edges added without metadata; "" when neither is known.
+func (g *Graph) mermaidEdgeLabel(parent, child string) string {
+	var fk, depType string
+	if meta := g.GetEdgeMeta(parent, child); meta != nil {
+		fk, depType = meta.ForeignKey, meta.DependencyType
+	} else if node := g.GetNode(child); node != nil {
+		fk, depType = node.ForeignKey, node.DependencyType
+	}
+	if depType == "" {
+		return fk
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s)", fk, depType))
+}
+
+// mermaidID turns a table name into a valid Mermaid node ID by replacing
+// every character other than letters, digits and underscores.
+func mermaidID(table string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, table)
+}
+
+// mermaidLabel escapes the characters that end a quoted Mermaid label or
+// an edge label.
+func mermaidLabel(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;").Replace(s)
+}
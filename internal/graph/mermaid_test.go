@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestToMermaid(t *testing.T) {
+	g := NewGraph("customers", "id")
+	g.AddNode("orders", &Node{Name: "orders", ForeignKey: "customer_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddNode("order_items", &Node{Name: "order_items", ForeignKey: "order_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddNode("customer_profile", &Node{Name: "customer_profile"})
+	g.AddEdge("customers", "orders")
+	g.AddEdge("orders", "order_items")
+	g.AddEdgeWithMeta("customers", "customer_profile", "customer_id", "id", "1-1")
+
+	out := g.ToMermaid()
+	if !strings.HasPrefix(out, "graph TD\n") {
+		t.Fatalf("expected a graph TD block, got:\n%s", out)
+	}
+	for _, edge := range []string{
+		"customers -->|customer_id (1-1)| customer_profile",
+		"customers -->|customer_id (1-N)| orders",
+		"orders -->|order_id (1-N)| order_items",
+	} {
+		if !strings.Contains(out, "    "+edge+"\n") {
+			t.Errorf("missing edge %q in:\n%s", edge, out)
+		}
+	}
+	if !strings.Contains(out, "    class customers root\n") {
+		t.Errorf("root is not styled:\n%s", out)
+	}
+	if !strings.Contains(out, "    class customer_profile,order_items leaf\n") {
+		t.Errorf("leaves are not styled:\n%s", out)
+	}
+	if strings.Index(out, "customers[") > strings.Index(out, "customer_profile[") {
+		t.Errorf("root node should be listed first:\n%s", out)
+	}
+
+	for i := 0; i < 5; i++ {
+		if again := g.ToMermaid(); again != out {
+			t.Fatalf("output is not deterministic:\n%s\nvs\n%s", out, again)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteMermaid(&buf); err != nil || buf.String() != out {
+		t.Errorf("WriteMermaid = %q, %v", buf.String(), err)
+	}
+}
+
+func TestToMermaid_SanitizesIDs(t *testing.T) {
+	g := NewGraph("app.users", "id")
+	g.AddNode("user-logs", &Node{Name: "user-logs"})
+	g.AddEdge("app.users", "user-logs")
+
+	out := g.ToMermaid()
+	if !strings.Contains(out, `app_users["app.users"]`) || !strings.Contains(out, "    app_users --> user_logs\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,89 @@
+package graph
+
+import "sort"
+
+// LongestPath returns the longest chain of tables from the root to a leaf,
+// root first, e.g. [customers orders order_items]. This is the worst-case
+// serialization depth of discovery, copy, and delete: each table on the path
+// has to wait for the one before it. Ties are broken alphabetically so the
+// result is stable. Tables unreachable from the root are ignored. Returns nil
+// if the graph has a cycle (the longest path is then unbounded; see Validate).
+func (g *Graph) LongestPath() []string {
+	order, err := g.TopologicalSort()
+	if err != nil || !g.HasNode(g.Root) {
+		return nil
+	}
+
+	// Longest distance from the root, relaxed in topological order so every
+	// parent is final before its children are visited.
+	dist := map[string]int{g.Root: 0}
+	prev := make(map[string]string)
+	for _, table := range order {
+		d, reachable := dist[table]
+		if !reachable {
+			continue
+		}
+		for _, child := range g.Children[table] {
+			current, seen := dist[child]
+			if !seen || d+1 > current || (d+1 == current && table < prev[child]) {
+				dist[child] = d + 1
+				prev[child] = table
+			}
+		}
+	}
+
+	end := g.Root
+	for table, d := range dist {
+		if d > dist[end] || (d == dist[end] && table < end) {
+			end = table
+		}
+	}
+
+	path := make([]string, dist[end]+1)
+	for i, table := len(path)-1, end; i >= 0; i-- {
+		path[i] = table
+		table = prev[table]
+	}
+	return path
+}
+
+// Depth returns the number of edges on LongestPath: 0 for a root-only graph,
+// 2 for customers -> orders -> order_items. Returns -1 if the graph has a cycle.
+func (g *Graph) Depth() int {
+	path := g.LongestPath()
+	if path == nil {
+		return -1
+	}
+	return len(path) - 1
+}
+
+// Levels groups the tables reachable from the root by BFS depth: level 0 is
+// the root, level 1 its children, level n the tables whose shortest path
+// from the root has n edges, e.g. [[customers] [orders profiles]
+// [order_items]]. Each level is sorted by name. A table reached through
+// several paths appears once, at its shallowest level; tables unreachable
+// from the root are omitted. Returns nil if the root is not in the graph.
+func (g *Graph) Levels() [][]string {
+	if !g.HasNode(g.Root) {
+		return nil
+	}
+	seen := map[string]bool{g.Root: true}
+	levels := [][]string{{g.Root}}
+	for current := levels[0]; ; {
+		var next []string
+		for _, table := range current {
+			for _, child := range g.Children[table] {
+				if !seen[child] {
+					seen[child] = true
+					next = append(next, child)
+				}
+			}
+		}
+		if len(next) == 0 {
+			return levels
+		}
+		sort.Strings(next)
+		levels = append(levels, next)
+		current = next
+	}
+}
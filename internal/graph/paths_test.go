@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+func TestLongestPath_DeepChain(t *testing.T) {
+	// A -> B -> C -> D -> E, plus a short branch A -> X
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "D"},
+		[2]string{"D", "E"}, [2]string{"A", "X"})
+
+	want := []string{"A", "B", "C", "D", "E"}
+	if got := g.LongestPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LongestPath() = %v, want %v", got, want)
+	}
+	if got := g.Depth(); got != 4 {
+		t.Errorf("Depth() = %d, want 4", got)
+	}
+}
+
+func TestLongestPath_Diamond(t *testing.T) {
+	// A -> B -> D, A -> C -> D, D -> E: both branches have the same length,
+	// so the alphabetically first parent (B) is chosen.
+	g := newEdgeGraph("A", [2]string{"A", "C"}, [2]string{"A", "B"}, [2]string{"C", "D"},
+		[2]string{"B", "D"}, [2]string{"D", "E"})
+
+	want := []string{"A", "B", "D", "E"}
+	if got := g.LongestPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LongestPath() = %v, want %v", got, want)
+	}
+	if got := g.Depth(); got != 3 {
+		t.Errorf("Depth() = %d, want 3", got)
+	}
+}
+
+func TestLongestPath_PrefersLongerBranchIntoSharedChild(t *testing.T) {
+	// D is reachable directly from A and via A -> B -> C; its depth is the longer one.
+	g := newEdgeGraph("A", [2]string{"A", "D"}, [2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "D"})
+
+	want := []string{"A", "B", "C", "D"}
+	if got := g.LongestPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LongestPath() = %v, want %v", got, want)
+	}
+}
+
+func TestLongestPath_RootOnly(t *testing.T) {
+	g := NewGraph("users", "id")
+	if got := g.LongestPath(); !reflect.DeepEqual(got, []string{"users"}) {
+		t.Errorf("LongestPath() = %v, want [users]", got)
+	}
+	if got := g.Depth(); got != 0 {
+		t.Errorf("Depth() = %d, want 0", got)
+	}
+}
+
+func TestLongestPath_Cycle(t *testing.T) {
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "B"})
+	if got := g.LongestPath(); got != nil {
+		t.Errorf("LongestPath() = %v, want nil for cyclic graph", got)
+	}
+	if got := g.Depth(); got != -1 {
+		t.Errorf("Depth() = %d, want -1 for cyclic graph", got)
+	}
+}
+
+func TestLevels_ComplexGraph(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N",
+				Relations: []config.Relation{
+					{Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N"},
+					{Table: "shipments", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-1"},
+				}},
+			{Table: "profiles", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-1"},
+			{Table: "sessions", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N"},
+		},
+	}
+	g, err := NewBuilder(job).Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	want := [][]string{
+		{"users"},
+		{"orders", "profiles", "sessions"},
+		{"order_items", "shipments"},
+	}
+	if got := g.Levels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Levels() = %v, want %v", got, want)
+	}
+}
+
+func TestLevels_ShallowestPathAndUnreachable(t *testing.T) {
+	// A -> B -> C and A -> C: C is at level 1. X is not reachable from A.
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"A", "C"})
+	g.AddNode("X", &Node{Name: "X"})
+
+	want := [][]string{{"A"}, {"B", "C"}}
+	if got := g.Levels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Levels() = %v, want %v", got, want)
+	}
+
+	// Cycles terminate.
+	cyclic := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "A"})
+	if got := cyclic.Levels(); !reflect.DeepEqual(got, [][]string{{"A"}, {"B"}}) {
+		t.Errorf("Levels() on cycle = %v", got)
+	}
+}
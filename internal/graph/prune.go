@@ -0,0 +1,67 @@
+package graph
+
+import "fmt"
+
+// PruneTables removes each table in exclude together with all of its
+// descendants, along with their edges, edge metadata, PK columns and column
+// filters, and returns the removed tables in breadth-first order from each
+// excluded table. Tables in exclude that are not in the graph are ignored.
+//
+// Nothing is removed when the root is excluded or when a table in keep
+// would be removed; both are reported as errors.
+func (g *Graph) PruneTables(exclude, keep []string) ([]string, error) {
+	var pruned []string
+	removed := make(map[string]bool)
+	for _, table := range exclude {
+		if !g.HasNode(table) || removed[table] {
+			continue
+		}
+		if table == g.Root {
+			return nil, fmt.Errorf("cannot exclude root table %q", table)
+		}
+		for _, t := range append([]string{table}, g.DescendantsOf(table)...) {
+			if !removed[t] {
+				removed[t] = true
+				pruned = append(pruned, t)
+			}
+		}
+	}
+
+	for _, table := range keep {
+		if removed[table] {
+			return nil, fmt.Errorf("excluding tables would remove %q, which is listed in relations", table)
+		}
+	}
+
+	for _, table := range pruned {
+		for _, parent := range g.Parents[table] {
+			if !removed[parent] {
+				g.Children[parent] = without(g.Children[parent], table)
+				if len(g.Children[parent]) == 0 {
+					delete(g.Children, parent)
+				}
+			}
+			delete(g.edgeMetadata, Edge{From: parent, To: table})
+		}
+		for _, child := range g.Children[table] {
+			delete(g.edgeMetadata, Edge{From: table, To: child})
+		}
+		delete(g.Children, table)
+		delete(g.Parents, table)
+		delete(g.Nodes, table)
+		delete(g.pkColumns, table)
+		delete(g.columnFilters, table)
+	}
+	return pruned, nil
+}
+
+// without returns list minus every occurrence of s, reusing list's array.
+func without(list []string, s string) []string {
+	out := list[:0]
+	for _, v := range list {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
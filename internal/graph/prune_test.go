@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+// buildBranchTestGraph creates customers -> orders -> order_items,
+// orders -> shipments, customers -> addresses.
+func buildBranchTestGraph() *Graph {
+	g := NewGraph("customers", "id")
+	for _, name := range []string{"orders", "order_items", "shipments", "addresses"} {
+		g.AddNode(name, nil)
+	}
+	g.AddEdgeWithMeta("customers", "orders", "customer_id", "id", "1-N")
+	g.AddEdgeWithMeta("orders", "order_items", "order_id", "order_id", "1-N")
+	g.AddEdgeWithMeta("orders", "shipments", "order_id", "order_id", "1-1")
+	g.AddEdgeWithMeta("customers", "addresses", "customer_id", "id", "1-N")
+	g.SetPK("orders", "order_id")
+	g.SetPK("order_items", "item_id")
+	g.SetPK("shipments", "shipment_id")
+	g.SetPK("addresses", "address_id")
+	return g
+}
+
+func TestPruneTables_RemovesSubtree(t *testing.T) {
+	g := buildBranchTestGraph()
+	g.SetColumnFilter("shipments", &ColumnFilter{Include: []string{"shipment_id"}})
+
+	pruned, err := g.PruneTables([]string{"orders", "not_in_graph"}, []string{"addresses"})
+	if err != nil {
+		t.Fatalf("PruneTables failed: %v", err)
+	}
+	if want := []string{"orders", "order_items", "shipments"}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("pruned = %v, want %v", pruned, want)
+	}
+
+	nodes := g.AllNodes()
+	sort.Strings(nodes)
+	if want := []string{"addresses", "customers"}; !reflect.DeepEqual(nodes, want) {
+		t.Errorf("AllNodes = %v, want %v", nodes, want)
+	}
+	order, err := g.CopyOrder()
+	if err != nil {
+		t.Fatalf("CopyOrder failed: %v", err)
+	}
+	if want := []string{"customers", "addresses"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("CopyOrder = %v, want %v", order, want)
+	}
+	if g.GetEdgeMeta("customers", "orders") != nil || g.GetEdgeMeta("orders", "shipments") != nil {
+		t.Error("edge metadata of pruned tables should be removed")
+	}
+	if g.HasPK("order_items") || g.GetColumnFilter("shipments") != nil {
+		t.Error("PK and column filter of pruned tables should be removed")
+	}
+}
+
+func TestPruneTables_Refusals(t *testing.T) {
+	g := buildBranchTestGraph()
+
+	if _, err := g.PruneTables([]string{"customers"}, nil); err == nil {
+		t.Error("expected an error excluding the root")
+	}
+	_, err := g.PruneTables([]string{"orders"}, []string{"shipments"})
+	if err == nil || !strings.Contains(err.Error(), `"shipments"`) {
+		t.Errorf("expected an error naming the kept table, got %v", err)
+	}
+	if g.NodeCount() != 5 {
+		t.Errorf("a refused prune must not modify the graph, got %v", g.AllNodes())
+	}
+}
+
+func TestBuilder_BuildFromSchema_ExcludeTables(t *testing.T) {
+	// users -> audit_log -> audit_details and users -> orders, from mocked
+	// foreign key metadata.
+	build := func(t *testing.T, job *config.JobConfig) (*Graph, error) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = db.Close() }()
+		m := schemaMock{mock: mock}
+		m.pk("users", "id")
+		m.children("users",
+			[3]string{"audit_log", "user_id", "id"},
+			[3]string{"orders", "user_id", "id"},
+		)
+		m.pk("audit_log", "id")
+		m.unique("audit_log", "user_id", false)
+		m.pk("orders", "id")
+		m.unique("orders", "user_id", false)
+		m.children("audit_log", [3]string{"audit_details", "log_id", "id"})
+		m.pk("audit_details", "id")
+		m.unique("audit_details", "log_id", false)
+		m.children("orders")
+		m.children("audit_details")
+		return NewBuilder(job).BuildFromSchema(context.Background(), db, 0)
+	}
+
+	g, err := build(t, &config.JobConfig{RootTable: "users", PrimaryKey: "id", ExcludeTables: []string{"audit_log"}})
+	if err != nil {
+		t.Fatalf("BuildFromSchema failed: %v", err)
+	}
+	nodes := g.AllNodes()
+	sort.Strings(nodes)
+	if want := []string{"orders", "users"}; !reflect.DeepEqual(nodes, want) {
+		t.Errorf("AllNodes = %v, want %v", nodes, want)
+	}
+	order, err := g.CopyOrder()
+	if err != nil {
+		t.Fatalf("CopyOrder failed: %v", err)
+	}
+	if want := []string{"users", "orders"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("CopyOrder = %v, want %v", order, want)
+	}
+
+	// audit_details is listed explicitly, so its parent cannot be excluded.
+	_, err = build(t, &config.JobConfig{
+		RootTable: "users", PrimaryKey: "id", ExcludeTables: []string{"audit_log"},
+		Relations: []config.Relation{{Table: "audit_log", Relations: []config.Relation{{Table: "audit_details"}}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "exclude_tables") {
+		t.Errorf("expected an exclude_tables error, got %v", err)
+	}
+}
+
+func TestBuild_ExcludeTablesListedInRelations(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable: "users", PrimaryKey: "id", ExcludeTables: []string{"orders", "audit_log"},
+		Relations: []config.Relation{{Table: "orders", PrimaryKey: "id", ForeignKey: "user_id"}},
+	}
+	if _, err := NewBuilder(job).Build(); err == nil || !strings.Contains(err.Error(), `"orders"`) {
+		t.Errorf("expected an error excluding a configured relation, got %v", err)
+	}
+
+	// Tables absent from the graph are ignored.
+	job.ExcludeTables = []string{"audit_log"}
+	if _, err := NewBuilder(job).Build(); err != nil {
+		t.Errorf("Build failed: %v", err)
+	}
+}
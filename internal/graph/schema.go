@@ -0,0 +1,183 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DefaultSchemaMaxDepth is the relation depth BuildFromSchema walks when
+// maxDepth <= 0. It matches the deepest nesting config validation allows.
+const DefaultSchemaMaxDepth = 10
+
+// schemaChildFK is one single-column foreign key referencing a parent table.
+type schemaChildFK struct {
+	table            string
+	column           string
+	referencedColumn string
+}
+
+// BuildFromSchema builds the dependency graph of rootTable from the foreign
+// keys of the connected database (DATABASE()) instead of configured
+// relations. Starting at the root it walks, breadth first, every table whose
+// single-column foreign key references the parent's primary key, down to
+// maxDepth levels of children (DefaultSchemaMaxDepth when <= 0).
+//
+// The dependency type is "1-1" when the foreign key column alone is uniquely
+// indexed, otherwise "1-N". Each table joins the graph once, under the
+// first (shallowest) parent that reaches it, so the result is always a
+// tree; self-references, composite foreign keys and keys referencing a
+// non-PK column are skipped. Every table must have a single-column primary
+// key.
+func BuildFromSchema(ctx context.Context, db *sql.DB, rootTable string, maxDepth int) (*Graph, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is nil")
+	}
+	if rootTable == "" {
+		return nil, fmt.Errorf("root table is not specified")
+	}
+	if maxDepth <= 0 {
+		maxDepth = DefaultSchemaMaxDepth
+	}
+
+	rootPK, err := schemaPrimaryKey(ctx, db, rootTable)
+	if err != nil {
+		return nil, err
+	}
+	g := NewGraph(rootTable, rootPK)
+
+	level := []string{rootTable}
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, parent := range level {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			fks, err := schemaChildFKs(ctx, db, parent)
+			if err != nil {
+				return nil, err
+			}
+			parentPK := g.GetPK(parent)
+			for _, fk := range fks {
+				if fk.table == parent || g.HasNode(fk.table) || fk.referencedColumn != parentPK {
+					continue
+				}
+				childPK, err := schemaPrimaryKey(ctx, db, fk.table)
+				if err != nil {
+					return nil, err
+				}
+				depType := "1-N"
+				unique, err := schemaUniqueColumn(ctx, db, fk.table, fk.column)
+				if err != nil {
+					return nil, err
+				}
+				if unique {
+					depType = "1-1"
+				}
+
+				g.AddNode(fk.table, &Node{
+					ForeignKey:     fk.column,
+					ReferenceKey:   parentPK,
+					DependencyType: depType,
+				})
+				g.SetPK(fk.table, childPK)
+				if err := g.AddEdgeChecked(parent, fk.table); err != nil {
+					return nil, err
+				}
+				g.setEdgeMeta(parent, fk.table, fk.column, parentPK, depType)
+				next = append(next, fk.table)
+			}
+		}
+		level = next
+	}
+
+	if err := g.Validate(); err != nil {
+		return nil, fmt.Errorf("graph validation failed: %w", err)
+	}
+	return g, nil
+}
+
+// schemaPrimaryKey returns table's primary key column, which must be a
+// single column.
+func schemaPrimaryKey(ctx context.Context, db *sql.DB, table string) (string, error) {
+	const query = `
+		SELECT COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+		ORDER BY ORDINAL_POSITION`
+
+	rows, err := db.QueryContext(ctx, query, table)
+	if err != nil {
+		return "", fmt.Errorf("failed to read primary key of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return "", fmt.Errorf("failed to read primary key of %s: %w", table, err)
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to read primary key of %s: %w", table, err)
+	}
+	if len(columns) != 1 {
+		return "", fmt.Errorf("table %q must have a single-column primary key (found %d columns)", table, len(columns))
+	}
+	return columns[0], nil
+}
+
+// schemaChildFKs returns the single-column foreign keys referencing parent,
+// ordered by table and constraint name.
+func schemaChildFKs(ctx context.Context, db *sql.DB, parent string) ([]schemaChildFK, error) {
+	const query = `
+		SELECT TABLE_NAME, COLUMN_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE kcu
+		WHERE TABLE_SCHEMA = DATABASE()
+		AND REFERENCED_TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME = ?
+		AND (SELECT COUNT(*) FROM information_schema.KEY_COLUMN_USAGE k2
+			WHERE k2.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+			AND k2.TABLE_NAME = kcu.TABLE_NAME
+			AND k2.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME) = 1
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME`
+
+	rows, err := db.QueryContext(ctx, query, parent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys referencing %s: %w", parent, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var fks []schemaChildFK
+	for rows.Next() {
+		var fk schemaChildFK
+		if err := rows.Scan(&fk.table, &fk.column, &fk.referencedColumn); err != nil {
+			return nil, fmt.Errorf("failed to read foreign keys referencing %s: %w", parent, err)
+		}
+		fks = append(fks, fk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys referencing %s: %w", parent, err)
+	}
+	return fks, nil
+}
+
+// schemaUniqueColumn reports whether column of table is, on its own, a
+// unique index (including the primary key).
+func schemaUniqueColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	const query = `
+		SELECT COUNT(*)
+		FROM information_schema.STATISTICS s
+		WHERE s.TABLE_SCHEMA = DATABASE() AND s.TABLE_NAME = ? AND s.COLUMN_NAME = ? AND s.NON_UNIQUE = 0
+		AND (SELECT COUNT(*) FROM information_schema.STATISTICS s2
+			WHERE s2.TABLE_SCHEMA = s.TABLE_SCHEMA
+			AND s2.TABLE_NAME = s.TABLE_NAME
+			AND s2.INDEX_NAME = s.INDEX_NAME) = 1`
+
+	var count int
+	if err := db.QueryRowContext(ctx, query, table, column).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to read unique indexes of %s: %w", table, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// schemaMock answers BuildFromSchema's metadata queries in the order the
+// walk issues them.
+type schemaMock struct {
+	mock sqlmock.Sqlmock
+}
+
+func (m schemaMock) pk(table, column string) {
+	m.mock.ExpectQuery("CONSTRAINT_NAME = 'PRIMARY'").WithArgs(table).
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}).AddRow(column))
+}
+
+func (m schemaMock) children(parent string, fks ...[3]string) {
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "REFERENCED_COLUMN_NAME"})
+	for _, fk := range fks {
+		rows.AddRow(fk[0], fk[1], fk[2])
+	}
+	m.mock.ExpectQuery("REFERENCED_TABLE_NAME = \\?").WithArgs(parent).WillReturnRows(rows)
+}
+
+func (m schemaMock) unique(table, column string, unique bool) {
+	count := 0
+	if unique {
+		count = 1
+	}
+	m.mock.ExpectQuery("information_schema.STATISTICS").WithArgs(table, column).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(count))
+}
+
+func TestBuildFromSchema(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	m := schemaMock{mock: mock}
+
+	m.pk("users", "id")
+	m.children("users",
+		[3]string{"orders", "user_id", "id"},
+		[3]string{"user_profiles", "user_id", "id"},
+		[3]string{"users", "referred_by", "id"}, // self-reference: skipped
+	)
+	m.pk("orders", "order_id")
+	m.unique("orders", "user_id", false)
+	m.pk("user_profiles", "id")
+	m.unique("user_profiles", "user_id", true)
+	m.children("orders",
+		[3]string{"order_items", "order_id", "order_id"},
+		[3]string{"shipments", "order_code", "code"}, // not the parent PK: skipped
+	)
+	m.pk("order_items", "id")
+	m.unique("order_items", "order_id", false)
+	m.children("user_profiles", [3]string{"orders", "profile_id", "id"}) // already in the graph
+	m.children("order_items")
+
+	g, err := BuildFromSchema(context.Background(), db, "users", 0)
+	if err != nil {
+		t.Fatalf("BuildFromSchema() failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	if g.NodeCount() != 4 {
+		t.Fatalf("expected 4 tables, got %v", g.AllNodes())
+	}
+	tests := []struct {
+		parent, child, fk, ref, depType, pk string
+	}{
+		{"users", "orders", "user_id", "id", "1-N", "order_id"},
+		{"users", "user_profiles", "user_id", "id", "1-1", "id"},
+		{"orders", "order_items", "order_id", "order_id", "1-N", "id"},
+	}
+	for _, tt := range tests {
+		meta := g.GetEdgeMeta(tt.parent, tt.child)
+		if meta == nil {
+			t.Errorf("missing edge %s -> %s", tt.parent, tt.child)
+			continue
+		}
+		if meta.ForeignKey != tt.fk || meta.ReferenceKey != tt.ref || meta.DependencyType != tt.depType {
+			t.Errorf("%s -> %s: got %+v", tt.parent, tt.child, meta)
+		}
+		node := g.GetNode(tt.child)
+		if node.ForeignKey != tt.fk || node.ReferenceKey != tt.ref || node.DependencyType != tt.depType {
+			t.Errorf("%s node: got %+v", tt.child, node)
+		}
+		if got := g.GetPK(tt.child); got != tt.pk {
+			t.Errorf("%s PK: got %q, want %q", tt.child, got, tt.pk)
+		}
+	}
+	if parents := g.GetParents("orders"); len(parents) != 1 {
+		t.Errorf("orders should keep only its first parent, got %v", parents)
+	}
+
+	order, err := g.CopyOrder()
+	if err != nil {
+		t.Fatalf("CopyOrder() failed: %v", err)
+	}
+	if order[0] != "users" || order[len(order)-1] != "order_items" {
+		t.Errorf("unexpected copy order %v", order)
+	}
+}
+
+func TestBuildFromSchema_MaxDepth(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	m := schemaMock{mock: mock}
+
+	// Depth 1 stops after the root's children; order_items is never read.
+	m.pk("users", "id")
+	m.children("users", [3]string{"orders", "user_id", "id"})
+	m.pk("orders", "id")
+	m.unique("orders", "user_id", false)
+
+	g, err := BuildFromSchema(context.Background(), db, "users", 1)
+	if err != nil {
+		t.Fatalf("BuildFromSchema() failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if g.NodeCount() != 2 || !g.HasNode("orders") {
+		t.Errorf("expected users and orders, got %v", g.AllNodes())
+	}
+}
+
+func TestBuildFromSchema_CompositeRootPK(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectQuery("CONSTRAINT_NAME = 'PRIMARY'").WithArgs("users").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}).AddRow("tenant_id").AddRow("id"))
+
+	if _, err := BuildFromSchema(context.Background(), db, "users", 0); err == nil {
+		t.Fatal("expected an error for a composite primary key")
+	}
+}
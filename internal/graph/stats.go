@@ -0,0 +1,33 @@
+package graph
+
+// GraphStats is a one-shot structural summary of a graph for the plan
+// command.
+type GraphStats struct {
+	NodeCount int
+	EdgeCount int
+	LeafCount int  // tables without children
+	RootCount int  // tables without parents; 1 for a well-formed job graph
+	MaxDepth  int  // edges on the longest root-to-leaf path (Depth); -1 with a cycle
+	HasCycle  bool // see HasCycle
+}
+
+// Stats returns the graph's structural summary. Tables are counted as
+// leaves and roots by their own edges, whether or not they are reachable
+// from g.Root.
+func (g *Graph) Stats() GraphStats {
+	stats := GraphStats{
+		NodeCount: g.NodeCount(),
+		EdgeCount: g.EdgeCount(),
+		MaxDepth:  g.Depth(),
+		HasCycle:  g.HasCycle(),
+	}
+	for name := range g.Nodes {
+		if len(g.Children[name]) == 0 {
+			stats.LeafCount++
+		}
+		if len(g.Parents[name]) == 0 {
+			stats.RootCount++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/dbsmedya/goarchive/internal/config"
+)
+
+func TestStats_ComplexGraph(t *testing.T) {
+	job := &config.JobConfig{
+		RootTable:  "users",
+		PrimaryKey: "id",
+		Relations: []config.Relation{
+			{Table: "orders", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N",
+				Relations: []config.Relation{
+					{Table: "order_items", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-N"},
+					{Table: "shipments", PrimaryKey: "id", ForeignKey: "order_id", DependencyType: "1-1"},
+				}},
+			{Table: "profiles", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-1"},
+			{Table: "sessions", PrimaryKey: "id", ForeignKey: "user_id", DependencyType: "1-N"},
+		},
+	}
+	g, err := NewBuilder(job).Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	want := GraphStats{
+		NodeCount: g.NodeCount(),
+		EdgeCount: edgeCount(g),
+		LeafCount: len(leafNodes(g)),
+		RootCount: 1,
+		MaxDepth:  g.Depth(),
+		HasCycle:  g.HasCycle(),
+	}
+	if want != (GraphStats{NodeCount: 6, EdgeCount: 5, LeafCount: 4, RootCount: 1, MaxDepth: 2}) {
+		t.Fatalf("fixture changed: %+v", want)
+	}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStats_DeepChainAndCycle(t *testing.T) {
+	g := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "D"},
+		[2]string{"D", "E"}, [2]string{"A", "X"})
+	want := GraphStats{NodeCount: 6, EdgeCount: 5, LeafCount: 2, RootCount: 1, MaxDepth: 4}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if g.EdgeCount() != edgeCount(g) || g.Stats().MaxDepth != g.Depth() {
+		t.Error("Stats must match the individual methods")
+	}
+
+	cyclic := newEdgeGraph("A", [2]string{"A", "B"}, [2]string{"B", "C"}, [2]string{"C", "B"})
+	got := cyclic.Stats()
+	if !got.HasCycle || got.MaxDepth != -1 || got.LeafCount != 0 || got.RootCount != 1 {
+		t.Errorf("Stats() on cycle = %+v", got)
+	}
+}
@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Structural problem kinds reported by ValidateStructure.
+const (
+	StructureOrphanNode         = "ORPHAN_NODE"
+	StructureReferenceKey       = "REFERENCE_KEY_MISMATCH"
+	StructureDuplicateEdge      = "DUPLICATE_EDGE"
+	StructureDependencyConflict = "DEPENDENCY_TYPE_CONFLICT"
+)
+
+// StructureError describes one structural problem in a graph.
+type StructureError struct {
+	Kind    string // One of the Structure* constants
+	Table   string // Table the problem was found on
+	Message string
+}
+
+// Error implements the error interface.
+func (e *StructureError) Error() string {
+	return fmt.Sprintf("%s: table %q: %s", e.Kind, e.Table, e.Message)
+}
+
+// ValidateStructure runs every structural check and returns all problems
+// found, rather than stopping at the first one like Validate. It reports, in
+// order: a *CycleError if the graph has a cycle, then *StructureError values
+// for nodes unreachable from the root, relations whose reference key is not the
+// parent's primary key, duplicate parent -> child edges, and invalid or
+// contradictory dependency types (such as a 1-1 child with several parents).
+// Returns nil for a well-formed graph.
+func (g *Graph) ValidateStructure() []error {
+	var problems []error
+	if err := g.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+
+	tables := g.AllNodes()
+	sort.Strings(tables)
+
+	reachable := g.reachableFromRoot()
+	for _, table := range tables {
+		if !reachable[table] {
+			problems = append(problems, &StructureError{
+				Kind:    StructureOrphanNode,
+				Table:   table,
+				Message: fmt.Sprintf("not reachable from root table %q", g.Root),
+			})
+		}
+	}
+
+	for _, table := range tables {
+		problems = append(problems, g.edgeProblems(table)...)
+	}
+
+	for _, table := range tables {
+		problems = append(problems, g.dependencyTypeProblems(table)...)
+	}
+
+	return problems
+}
+
+// MultiPathTables maps each table with more than one distinct parent to its
+// sorted parents: the table is reachable from the root by several paths, so
+// discovery can find the same row through each of them. Returns an empty map
+// for a tree.
+func (g *Graph) MultiPathTables() map[string][]string {
+	result := make(map[string][]string)
+	for table, parents := range g.Parents {
+		distinct := make([]string, 0, len(parents))
+		seen := make(map[string]bool, len(parents))
+		for _, parent := range parents {
+			if !seen[parent] {
+				seen[parent] = true
+				distinct = append(distinct, parent)
+			}
+		}
+		if len(distinct) > 1 {
+			sort.Strings(distinct)
+			result[table] = distinct
+		}
+	}
+	return result
+}
+
+// reachableFromRoot returns the set of tables reachable from the root.
+func (g *Graph) reachableFromRoot() map[string]bool {
+	seen := map[string]bool{g.Root: true}
+	queue := []string{g.Root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range g.Children[current] {
+			if !seen[child] {
+				seen[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return seen
+}
+
+// ValidateReferenceKeys checks that every relation joins on its parent's
+// primary key (GetPK): each parent -> child edge's reference key and, for a
+// child without edge metadata, the node's own reference key. Discovery
+// matches a child's foreign key against the parent's primary key values, so
+// a relation referencing another column would silently select the wrong
+// rows. Returns one *StructureError per mismatch, by child table name, or
+// nil.
+func (g *Graph) ValidateReferenceKeys() []error {
+	tables := g.AllNodes()
+	sort.Strings(tables)
+	var problems []error
+	for _, table := range tables {
+		problems = append(problems, g.referenceKeyProblems(table)...)
+	}
+	return problems
+}
+
+// edgeProblems checks the incoming edges of table for duplicates and for
+// reference keys that do not match the parent's primary key.
+func (g *Graph) edgeProblems(table string) []error {
+	var problems []error
+	seen := make(map[string]bool)
+	for _, parent := range g.Parents[table] {
+		if seen[parent] {
+			problems = append(problems, &StructureError{
+				Kind:    StructureDuplicateEdge,
+				Table:   table,
+				Message: fmt.Sprintf("edge %s -> %s is defined more than once", parent, table),
+			})
+		}
+		seen[parent] = true
+	}
+	return append(problems, g.referenceKeyProblems(table)...)
+}
+
+// referenceKeyProblems checks that table's incoming edges reference their
+// parent's primary key.
+func (g *Graph) referenceKeyProblems(table string) []error {
+	var problems []error
+	seen := make(map[string]bool)
+	for _, parent := range g.Parents[table] {
+		if seen[parent] {
+			continue
+		}
+		seen[parent] = true
+
+		meta := g.GetEdgeMeta(parent, table)
+		if meta != nil && meta.ReferenceKey != "" && meta.ReferenceKey != g.GetPK(parent) {
+			problems = append(problems, &StructureError{
+				Kind:  StructureReferenceKey,
+				Table: table,
+				Message: fmt.Sprintf("edge %s -> %s references %s.%s, but the primary key of %s is %q",
+					parent, table, parent, meta.ReferenceKey, parent, g.GetPK(parent)),
+			})
+		}
+	}
+
+	// Without edge metadata, fall back to the node's own reference key, which
+	// must match the primary key of at least one parent.
+	node := g.Nodes[table]
+	if node == nil || node.IsRoot || node.ReferenceKey == "" || len(g.Parents[table]) == 0 {
+		return problems
+	}
+	for _, parent := range g.Parents[table] {
+		if g.GetEdgeMeta(parent, table) != nil || node.ReferenceKey == g.GetPK(parent) {
+			return problems
+		}
+	}
+	return append(problems, &StructureError{
+		Kind:    StructureReferenceKey,
+		Table:   table,
+		Message: fmt.Sprintf("reference key %q is not the primary key of any parent (%v)", node.ReferenceKey, g.Parents[table]),
+	})
+}
+
+// dependencyTypeProblems checks that table's dependency types are valid and
+// consistent with its parents.
+func (g *Graph) dependencyTypeProblems(table string) []error {
+	var problems []error
+
+	types := make(map[string]bool)
+	if node := g.Nodes[table]; node != nil && !node.IsRoot && node.DependencyType != "" {
+		types[node.DependencyType] = true
+	}
+	for _, parent := range g.Parents[table] {
+		if meta := g.GetEdgeMeta(parent, table); meta != nil && meta.DependencyType != "" {
+			types[meta.DependencyType] = true
+		}
+	}
+	names := make([]string, 0, len(types))
+	for depType := range types {
+		names = append(names, depType)
+	}
+	sort.Strings(names)
+	for _, depType := range names {
+		if depType != "1-1" && depType != "1-N" {
+			problems = append(problems, &StructureError{
+				Kind:    StructureDependencyConflict,
+				Table:   table,
+				Message: fmt.Sprintf("invalid dependency type %q (must be '1-1' or '1-N')", depType),
+			})
+		}
+	}
+
+	oneToOne := types["1-1"]
+	if oneToOne && len(g.Parents[table]) > 1 {
+		problems = append(problems, &StructureError{
+			Kind:    StructureDependencyConflict,
+			Table:   table,
+			Message: fmt.Sprintf("1-1 relation has %d parents (%v)", len(g.Parents[table]), g.Parents[table]),
+		})
+	}
+	return problems
+}
@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// structureKinds returns the Kind of every *StructureError in problems, keyed
+// by "KIND:table".
+func structureKinds(problems []error) map[string]bool {
+	kinds := make(map[string]bool)
+	for _, err := range problems {
+		var se *StructureError
+		if errors.As(err, &se) {
+			kinds[se.Kind+":"+se.Table] = true
+		}
+	}
+	return kinds
+}
+
+func newStructureTestGraph() *Graph {
+	g := NewGraph("users", "id")
+	g.AddNode("orders", &Node{ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddNode("order_items", &Node{ForeignKey: "order_id", ReferenceKey: "order_id", DependencyType: "1-N"})
+	g.SetPK("orders", "order_id")
+	g.SetPK("order_items", "id")
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+	g.AddEdgeWithMeta("orders", "order_items", "order_id", "order_id", "1-N")
+	return g
+}
+
+func TestValidateStructure_ValidGraph(t *testing.T) {
+	if problems := newStructureTestGraph().ValidateStructure(); len(problems) != 0 {
+		t.Fatalf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateStructure_OrphanNode(t *testing.T) {
+	g := newStructureTestGraph()
+	g.AddNode("audit", nil)
+
+	if kinds := structureKinds(g.ValidateStructure()); !kinds[StructureOrphanNode+":audit"] {
+		t.Errorf("expected orphan audit, got %v", kinds)
+	}
+}
+
+func TestValidateStructure_ReferenceKeyMismatch(t *testing.T) {
+	g := newStructureTestGraph()
+	// orders' PK is order_id, but the edge references orders.id.
+	g.AddNode("shipments", &Node{ForeignKey: "order_id", ReferenceKey: "id", DependencyType: "1-N"})
+	g.AddEdgeWithMeta("orders", "shipments", "order_id", "id", "1-N")
+
+	problems := g.ValidateStructure()
+	if kinds := structureKinds(problems); !kinds[StructureReferenceKey+":shipments"] || len(problems) != 1 {
+		t.Errorf("expected one reference key mismatch on shipments, got %v", problems)
+	}
+}
+
+func TestValidateStructure_ReferenceKeyWithoutEdgeMeta(t *testing.T) {
+	g := NewGraph("users", "id")
+	g.AddNode("orders", &Node{ForeignKey: "user_id", ReferenceKey: "uuid", DependencyType: "1-N"})
+	g.AddEdge("users", "orders")
+
+	if kinds := structureKinds(g.ValidateStructure()); !kinds[StructureReferenceKey+":orders"] {
+		t.Errorf("expected reference key mismatch on orders, got %v", kinds)
+	}
+}
+
+func TestValidateStructure_DuplicateEdge(t *testing.T) {
+	g := newStructureTestGraph()
+	g.AddEdgeWithMeta("users", "orders", "user_id", "id", "1-N")
+
+	if kinds := structureKinds(g.ValidateStructure()); !kinds[StructureDuplicateEdge+":orders"] {
+		t.Errorf("expected duplicate edge on orders, got %v", kinds)
+	}
+}
+
+func TestValidateStructure_DependencyTypes(t *testing.T) {
+	g := newStructureTestGraph()
+	g.AddNode("profile", &Node{ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "1-1"})
+	g.AddEdgeWithMeta("users", "profile", "user_id", "id", "1-1")
+	g.AddEdgeWithMeta("orders", "profile", "order_id", "order_id", "1-1")
+	g.AddNode("notes", &Node{ForeignKey: "user_id", ReferenceKey: "id", DependencyType: "N-N"})
+	g.AddEdgeWithMeta("users", "notes", "user_id", "id", "N-N")
+
+	problems := g.ValidateStructure()
+	kinds := structureKinds(problems)
+	if !kinds[StructureDependencyConflict+":profile"] || !kinds[StructureDependencyConflict+":notes"] {
+		t.Errorf("expected dependency type conflicts on profile and notes, got %v", problems)
+	}
+	// The invalid type is reported once even though node and edge both carry it.
+	if len(problems) != 2 {
+		t.Errorf("expected exactly 2 problems, got %d: %v", len(problems), problems)
+	}
+}
+
+func TestValidateStructure_ReportsCycleAlongsideOtherProblems(t *testing.T) {
+	g := newStructureTestGraph()
+	g.AddEdge("order_items", "orders")
+	g.AddNode("audit", nil)
+
+	problems := g.ValidateStructure()
+	var cycleErr *CycleError
+	if len(problems) == 0 || !errors.As(problems[0], &cycleErr) {
+		t.Fatalf("expected CycleError first, got %v", problems)
+	}
+	if kinds := structureKinds(problems); !kinds[StructureOrphanNode+":audit"] {
+		t.Errorf("expected orphan audit alongside cycle, got %v", kinds)
+	}
+	if !strings.Contains(problems[len(problems)-1].Error(), "audit") {
+		t.Errorf("expected error message to name the table, got %q", problems[len(problems)-1])
+	}
+}
+
+func TestMultiPathTables(t *testing.T) {
+	if multi := newStructureTestGraph().MultiPathTables(); len(multi) != 0 {
+		t.Errorf("tree should have no multi-path tables, got %v", multi)
+	}
+
+	// A duplicated edge is one parent, not two paths.
+	g := newEdgeGraph("A", [2]string{"A", "C"}, [2]string{"A", "B"}, [2]string{"C", "D"}, [2]string{"B", "D"}, [2]string{"B", "E"}, [2]string{"B", "E"})
+	multi := g.MultiPathTables()
+	if len(multi) != 1 || strings.Join(multi["D"], ",") != "B,C" {
+		t.Errorf("MultiPathTables() = %v, want D <- [B C]", multi)
+	}
+}
+
+func TestValidateReferenceKeys(t *testing.T) {
+	if problems := newStructureTestGraph().ValidateReferenceKeys(); problems != nil {
+		t.Fatalf("expected no problems, got %v", problems)
+	}
+
+	g := newStructureTestGraph()
+	// order_items joins on orders.id, but the primary key of orders is order_id.
+	g.setEdgeMeta("orders", "order_items", "order_id", "id", "1-N")
+	problems := g.ValidateReferenceKeys()
+	if len(problems) != 1 {
+		t.Fatalf("expected one problem, got %v", problems)
+	}
+	var se *StructureError
+	if !errors.As(problems[0], &se) || se.Kind != StructureReferenceKey || se.Table != "order_items" {
+		t.Fatalf("expected reference key mismatch on order_items, got %v", problems[0])
+	}
+	if !strings.Contains(se.Message, `the primary key of orders is "order_id"`) {
+		t.Errorf("unexpected message: %s", se.Message)
+	}
+}
@@ -1,13 +1,22 @@
 // Package graph provides dependency graph structures and algorithms for GoArchive.
 package graph
 
+import "fmt"
+
 // Node represents a table in the dependency graph.
 type Node struct {
-	Name           string // Table name
-	ForeignKey     string // FK column in this table pointing to parent (empty for root)
-	ReferenceKey   string // PK column in parent that FK references (empty for root)
-	DependencyType string // "1-1" or "1-N"
-	IsRoot         bool   // True if this is the root table
+	Name                  string // Table name
+	ForeignKey            string // FK column in this table pointing to parent (empty for root)
+	ReferenceKey          string // PK column in parent that FK references (empty for root)
+	DependencyType        string // "1-1" or "1-N"
+	IsRoot                bool   // True if this is the root table
+	IndexHint             string // Index forced for this table's FK lookup during discovery (empty = optimizer's choice)
+	DiscoveryQuery        string // Raw child PK lookup with a {parent_pks} placeholder, replacing the FK lookup ("" = use ForeignKey)
+	BatchSize             int    // Per-table chunk size for discovery, copy, verify and delete (0 = phase default)
+	VerificationMethod    string // Verifier method for this table: "count", "sha256" or "server_checksum" ("" = verifier default)
+	DestinationTable      string // Destination table name template with {year}/{month} ("" = same name as source)
+	DestinationDateColumn string // Column dating each row for DestinationTable ("" = job run date)
+	Priority              int    // Ordering hint among ready tables, higher first (0 = none); see CopyOrder
 }
 
 // Edge represents a dependency relationship between tables.
@@ -16,16 +25,24 @@ type Edge struct {
 	To   string // Child table name
 }
 
+// EdgeInfo is an edge together with its relationship metadata, as returned
+// by EdgesFrom and EdgesTo. Meta is nil for an edge added without metadata.
+type EdgeInfo struct {
+	Edge
+	Meta *EdgeMeta
+}
+
 // Graph represents the complete dependency structure for an archive job.
 type Graph struct {
-	Nodes        map[string]*Node    // table name -> node
-	Children     map[string][]string // table name -> child table names (outgoing edges)
-	Parents      map[string][]string // table name -> parent table names (incoming edges)
-	Root         string              // Root table name
-	RootPK       string              // Primary key column of root table
-	rootPKMeta   rootPKMeta          // Root PK data type metadata loaded by preflight/orchestrators
-	pkColumns    map[string]string   // table name -> primary key column name (for all tables)
-	edgeMetadata map[Edge]*EdgeMeta  // Edge -> metadata
+	Nodes         map[string]*Node         // table name -> node
+	Children      map[string][]string      // table name -> child table names (outgoing edges)
+	Parents       map[string][]string      // table name -> parent table names (incoming edges)
+	Root          string                   // Root table name
+	RootPK        string                   // Primary key column of root table
+	rootPKMeta    rootPKMeta               // Root PK data type metadata loaded by preflight/orchestrators
+	pkColumns     map[string]string        // table name -> primary key column name (for all tables)
+	edgeMetadata  map[Edge]*EdgeMeta       // Edge -> metadata
+	columnFilters map[string]*ColumnFilter // table name -> copied column selection (absent = all)
 }
 
 type rootPKMeta struct {
@@ -44,13 +61,14 @@ type EdgeMeta struct {
 // NewGraph creates a new empty graph with the specified root table.
 func NewGraph(root, rootPK string) *Graph {
 	g := &Graph{
-		Nodes:        make(map[string]*Node),
-		Children:     make(map[string][]string),
-		Parents:      make(map[string][]string),
-		Root:         root,
-		RootPK:       rootPK,
-		pkColumns:    make(map[string]string),
-		edgeMetadata: make(map[Edge]*EdgeMeta),
+		Nodes:         make(map[string]*Node),
+		Children:      make(map[string][]string),
+		Parents:       make(map[string][]string),
+		Root:          root,
+		RootPK:        rootPK,
+		pkColumns:     make(map[string]string),
+		edgeMetadata:  make(map[Edge]*EdgeMeta),
+		columnFilters: make(map[string]*ColumnFilter),
 	}
 
 	// Add root node
@@ -85,10 +103,28 @@ func (g *Graph) AddEdge(parent, child string) {
 	g.Parents[child] = append(g.Parents[child], parent)
 }
 
+// AddEdgeChecked is AddEdge for edges that must join registered nodes: it
+// returns an error instead of adding an edge with an unknown endpoint, which
+// would otherwise skew in-degree counts silently. AddEdge stays unchecked
+// for hand-built graphs.
+func (g *Graph) AddEdgeChecked(parent, child string) error {
+	if !g.HasNode(parent) {
+		return fmt.Errorf("edge %s -> %s: unknown parent node %q", parent, child, parent)
+	}
+	if !g.HasNode(child) {
+		return fmt.Errorf("edge %s -> %s: unknown child node %q", parent, child, child)
+	}
+	g.AddEdge(parent, child)
+	return nil
+}
+
 // AddEdgeWithMeta adds an edge with metadata about the relationship.
 func (g *Graph) AddEdgeWithMeta(parent, child, foreignKey, referenceKey, depType string) {
 	g.AddEdge(parent, child)
+	g.setEdgeMeta(parent, child, foreignKey, referenceKey, depType)
+}
 
+func (g *Graph) setEdgeMeta(parent, child, foreignKey, referenceKey, depType string) {
 	edge := Edge{From: parent, To: child}
 	g.edgeMetadata[edge] = &EdgeMeta{
 		ForeignKey:     foreignKey,
@@ -107,6 +143,65 @@ func (g *Graph) GetParents(child string) []string {
 	return g.Parents[child]
 }
 
+// EdgesFrom returns node's outgoing edges (one per child, in insertion
+// order) with their metadata attached. Returns nil for an unknown table or a
+// leaf.
+func (g *Graph) EdgesFrom(node string) []EdgeInfo {
+	var edges []EdgeInfo
+	for _, child := range g.Children[node] {
+		edges = append(edges, EdgeInfo{Edge{From: node, To: child}, g.GetEdgeMeta(node, child)})
+	}
+	return edges
+}
+
+// EdgesTo returns node's incoming edges (one per parent, in insertion order)
+// with their metadata attached. Returns nil for an unknown table or the root.
+func (g *Graph) EdgesTo(node string) []EdgeInfo {
+	var edges []EdgeInfo
+	for _, parent := range g.Parents[node] {
+		edges = append(edges, EdgeInfo{Edge{From: parent, To: node}, g.GetEdgeMeta(parent, node)})
+	}
+	return edges
+}
+
+// DescendantsOf returns every table transitively reachable from node through
+// child edges — what would be removed along with node — in breadth-first
+// order. node itself is excluded, even when a cycle leads back to it. Returns
+// nil for an unknown table or a leaf.
+func (g *Graph) DescendantsOf(node string) []string {
+	return g.reachable(node, g.Children)
+}
+
+// AncestorsOf returns every table node transitively depends on through parent
+// edges, in breadth-first order, excluding node itself. Returns nil for an
+// unknown table or the root.
+func (g *Graph) AncestorsOf(node string) []string {
+	return g.reachable(node, g.Parents)
+}
+
+// reachable walks edges breadth-first from start and returns the visited
+// tables other than start.
+func (g *Graph) reachable(start string, edges map[string][]string) []string {
+	if !g.HasNode(start) {
+		return nil
+	}
+	var result []string
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if !seen[next] {
+				seen[next] = true
+				result = append(result, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	return result
+}
+
 // GetNode returns the node for a given table name, or nil if not found.
 func (g *Graph) GetNode(name string) *Node {
 	return g.Nodes[name]
@@ -129,6 +224,15 @@ func (g *Graph) NodeCount() int {
 	return len(g.Nodes)
 }
 
+// EdgeCount returns the number of parent -> child edges in the graph.
+func (g *Graph) EdgeCount() int {
+	count := 0
+	for _, children := range g.Children {
+		count += len(children)
+	}
+	return count
+}
+
 // AllNodes returns a slice of all table names in the graph.
 func (g *Graph) AllNodes() []string {
 	nodes := make([]string, 0, len(g.Nodes))
@@ -166,6 +270,15 @@ func (g *Graph) GetPK(table string) string {
 	return "id"
 }
 
+// BatchSizeFor returns the table's batch_size override, or fallback when the
+// table has none. Each phase passes its own default as fallback.
+func (g *Graph) BatchSizeFor(table string, fallback int) int {
+	if node := g.GetNode(table); node != nil && node.BatchSize > 0 {
+		return node.BatchSize
+	}
+	return fallback
+}
+
 // HasPK returns true if a table has an explicitly configured PK column.
 func (g *Graph) HasPK(table string) bool {
 	_, exists := g.pkColumns[table]
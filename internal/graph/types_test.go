@@ -1,7 +1,9 @@
 package graph
 
 import (
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -158,6 +160,39 @@ func TestAddEdge(t *testing.T) {
 	}
 }
 
+func TestAddEdgeChecked(t *testing.T) {
+	g := NewGraph("orders", "id")
+	g.AddNode("order_items", nil)
+
+	if err := g.AddEdgeChecked("orders", "order_items"); err != nil {
+		t.Fatalf("AddEdgeChecked between known nodes: %v", err)
+	}
+	if children := g.GetChildren("orders"); len(children) != 1 || children[0] != "order_items" {
+		t.Errorf("expected orders -> order_items, got %v", children)
+	}
+
+	tests := []struct {
+		name          string
+		parent, child string
+		wantInError   string
+	}{
+		{"unknown parent", "shipments", "order_items", `unknown parent node "shipments"`},
+		{"unknown child", "orders", "payments", `unknown child node "payments"`},
+		{"both unknown", "a", "b", `unknown parent node "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := g.AddEdgeChecked(tt.parent, tt.child)
+			if err == nil || !strings.Contains(err.Error(), tt.wantInError) {
+				t.Fatalf("AddEdgeChecked(%q, %q) error = %v, want %q", tt.parent, tt.child, err, tt.wantInError)
+			}
+			if edges := g.AllEdges(); len(edges) != 1 {
+				t.Errorf("rejected edge must not be added, edges = %v", edges)
+			}
+		})
+	}
+}
+
 func TestAddEdgeWithMeta(t *testing.T) {
 	g := NewGraph("orders", "id")
 	g.AddNode("order_items", nil)
@@ -490,3 +525,79 @@ func TestGetEdgeMetaNonExistent(t *testing.T) {
 		t.Error("GetEdgeMeta on non-existent edge should return nil")
 	}
 }
+
+// buildLineageTestGraph creates:
+//
+//	customers -> orders -> order_items -> item_notes
+//	          -> addresses
+//	products  -> order_items (second parent)
+func buildLineageTestGraph() *Graph {
+	g := NewGraph("customers", "id")
+	for _, name := range []string{"orders", "order_items", "item_notes", "addresses", "products"} {
+		g.AddNode(name, nil)
+	}
+	g.AddEdge("customers", "orders")
+	g.AddEdge("customers", "addresses")
+	g.AddEdge("orders", "order_items")
+	g.AddEdge("order_items", "item_notes")
+	g.AddEdge("products", "order_items")
+	return g
+}
+
+func TestDescendantsOf(t *testing.T) {
+	g := buildLineageTestGraph()
+	tests := []struct {
+		node string
+		want []string
+	}{
+		{"customers", []string{"orders", "addresses", "order_items", "item_notes"}},
+		{"orders", []string{"order_items", "item_notes"}},
+		{"products", []string{"order_items", "item_notes"}},
+		{"item_notes", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := g.DescendantsOf(tt.node); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DescendantsOf(%q) = %v, want %v", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestAncestorsOf(t *testing.T) {
+	g := buildLineageTestGraph()
+	tests := []struct {
+		node string
+		want []string
+	}{
+		{"item_notes", []string{"order_items", "orders", "products", "customers"}},
+		{"order_items", []string{"orders", "products", "customers"}},
+		{"addresses", []string{"customers"}},
+		{"customers", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := g.AncestorsOf(tt.node); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AncestorsOf(%q) = %v, want %v", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestDescendantsAncestors_Cycle(t *testing.T) {
+	// a -> b -> c -> b: the walk must terminate and never list the start node.
+	g := NewGraph("a", "id")
+	g.AddNode("b", nil)
+	g.AddNode("c", nil)
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "b")
+
+	if got, want := g.DescendantsOf("b"), []string{"c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DescendantsOf(b) = %v, want %v", got, want)
+	}
+	if got, want := g.AncestorsOf("b"), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AncestorsOf(b) = %v, want %v", got, want)
+	}
+	if got, want := g.DescendantsOf("a"), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DescendantsOf(a) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+// Package health provides the optional HTTP liveness/readiness server for
+// long-running GoArchive commands.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// ReadyTimeout bounds the database ping behind one /readyz request, so a
+// hung connection fails the probe instead of blocking it.
+const ReadyTimeout = 3 * time.Second
+
+// Pinger reports whether the job's databases are reachable
+// (*database.Manager).
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// NewHandler returns the health endpoints:
+//
+//	/healthz  200 while the process is up (liveness)
+//	/readyz   200 when pinger.Ping succeeds, 503 with the error otherwise
+//	/status   200 with status() as JSON, the current job state
+//
+// status may be nil, in which case /status is not served.
+func NewHandler(pinger Pinger, status func() interface{}) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, http.StatusOK, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), ReadyTimeout)
+		defer cancel()
+		if err := pinger.Ping(ctx); err != nil {
+			writeText(w, http.StatusServiceUnavailable, "not ready: "+err.Error())
+			return
+		}
+		writeText(w, http.StatusOK, "ok")
+	})
+	if status != nil {
+		mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+			body, err := json.Marshal(status())
+			if err != nil {
+				writeText(w, http.StatusInternalServerError, fmt.Sprintf("failed to encode status: %v", err))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(append(body, '\n'))
+		})
+	}
+	return mux
+}
+
+func writeText(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintln(w, msg)
+}
+
+// Start listens on addr and serves handler in the background. A listen
+// error (e.g. the port is taken) is returned before anything is served. The
+// returned stop func shuts the server down, waiting up to a few seconds for
+// in-flight probes.
+func Start(addr string, handler http.Handler, log *logger.Logger) (func(), error) {
+	if log == nil {
+		log = logger.NewDefault()
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start health server on %s: %w", addr, err)
+	}
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Health server stopped: %v", err)
+		}
+	}()
+	log.Infof("Health server listening on %s (/healthz, /readyz)", ln.Addr())
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Warnf("Failed to shut down health server: %v", err)
+		}
+	}, nil
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dbsmedya/goarchive/internal/logger"
+)
+
+// fakePinger stands in for database.Manager.
+type fakePinger struct{ err error }
+
+func (p fakePinger) Ping(context.Context) error { return p.err }
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestHandler_Healthy(t *testing.T) {
+	h := NewHandler(fakePinger{}, func() interface{} {
+		return map[string]interface{}{"JobName": "archive_old_orders", "Batch": 3}
+	})
+
+	code, _ := get(t, h, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = get(t, h, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	code, body := get(t, h, "/status")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"JobName":"archive_old_orders","Batch":3}`, body)
+}
+
+func TestHandler_Unhealthy(t *testing.T) {
+	h := NewHandler(fakePinger{err: errors.New("destination ping failed: connection refused")}, nil)
+
+	// The process is alive even when the databases are not.
+	code, _ := get(t, h, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, body := get(t, h, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "connection refused")
+	code, _ = get(t, h, "/status")
+	assert.Equal(t, http.StatusNotFound, code, "/status is only served with a status func")
+}
+
+func TestStart_ServesUntilStopped(t *testing.T) {
+	stop, err := Start("127.0.0.1:0", NewHandler(fakePinger{}, nil), logger.NewDefault())
+	require.NoError(t, err)
+	stop()
+
+	_, err = Start("256.0.0.1:0", NewHandler(fakePinger{}, nil), logger.NewDefault())
+	assert.ErrorContains(t, err, "failed to start health server")
+}
@@ -42,6 +42,7 @@ const (
 // released when the connection closes or RELEASE_LOCK() is called.
 type AdvisoryLock struct {
 	db              *sql.DB
+	pinned          *sql.Conn // caller-owned connection, see NewConnAdvisoryLock
 	conn            *sql.Conn
 	lockName        string
 	connID          int64
@@ -61,6 +62,22 @@ func NewAdvisoryLock(db *sql.DB, lockName string) *AdvisoryLock {
 	}
 }
 
+// NewConnAdvisoryLock creates an advisory lock that acquires, verifies and
+// releases the lock on conn rather than on a connection taken from a pool.
+// MySQL named locks belong to the session that took them, so this lets the
+// caller run its own statements on the session that holds the lock, e.g. to
+// check IS_USED_LOCK against CONNECTION_ID() itself.
+//
+// The caller owns conn: the lock never closes it, including when ownership
+// is lost, and conn must stay open until ReleaseLock returns.
+func NewConnAdvisoryLock(conn *sql.Conn, lockName string) *AdvisoryLock {
+	return &AdvisoryLock{
+		pinned:   conn,
+		lockName: lockName,
+		held:     false,
+	}
+}
+
 // AcquireLock attempts to acquire the advisory lock with the specified timeout.
 // Returns true if the lock was acquired, false if timeout was reached.
 // Returns an error if the database query fails.
@@ -78,27 +95,30 @@ func (a *AdvisoryLock) AcquireLock(ctx context.Context, timeoutSeconds int) (boo
 	if a.held {
 		return true, nil // Already holding the lock
 	}
-	if a.db == nil {
-		return false, fmt.Errorf("database is nil")
-	}
-
-	conn, err := a.db.Conn(ctx)
-	if err != nil {
-		return false, fmt.Errorf("failed to get dedicated connection: %w", err)
+	conn := a.pinned
+	if conn == nil {
+		if a.db == nil {
+			return false, fmt.Errorf("database is nil")
+		}
+		var err error
+		conn, err = a.db.Conn(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get dedicated connection: %w", err)
+		}
 	}
 
 	query := "SELECT GET_LOCK(?, ?)"
 	var result sql.NullInt64
 
-	err = conn.QueryRowContext(ctx, query, a.lockName, timeoutSeconds).Scan(&result)
+	err := conn.QueryRowContext(ctx, query, a.lockName, timeoutSeconds).Scan(&result)
 	if err != nil {
-		_ = conn.Close()
+		_ = a.closeConn(conn)
 		return false, fmt.Errorf("failed to execute GET_LOCK: %w", err)
 	}
 
 	// Check if result is NULL (error case)
 	if !result.Valid {
-		_ = conn.Close()
+		_ = a.closeConn(conn)
 		return false, fmt.Errorf("GET_LOCK returned NULL for lock %q (possible database error)", a.lockName)
 	}
 
@@ -107,7 +127,7 @@ func (a *AdvisoryLock) AcquireLock(ctx context.Context, timeoutSeconds int) (boo
 	case 1:
 		var connID int64
 		if err := conn.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&connID); err != nil {
-			_ = conn.Close()
+			_ = a.closeConn(conn)
 			return false, fmt.Errorf("failed to read advisory lock connection id: %w", err)
 		}
 		a.conn = conn
@@ -116,10 +136,10 @@ func (a *AdvisoryLock) AcquireLock(ctx context.Context, timeoutSeconds int) (boo
 		return true, nil
 	case 0:
 		// Timeout reached - another instance is holding the lock
-		_ = conn.Close()
+		_ = a.closeConn(conn)
 		return false, nil
 	default:
-		_ = conn.Close()
+		_ = a.closeConn(conn)
 		return false, fmt.Errorf("unexpected GET_LOCK return value: %d", result.Int64)
 	}
 }
@@ -141,7 +161,7 @@ func (a *AdvisoryLock) ReleaseLock(ctx context.Context) (bool, error) {
 
 	if !a.held {
 		if a.conn != nil {
-			closeErr := a.conn.Close()
+			closeErr := a.closeConn(a.conn)
 			a.conn = nil
 			a.connID = 0
 			if closeErr != nil {
@@ -159,7 +179,7 @@ func (a *AdvisoryLock) ReleaseLock(ctx context.Context) (bool, error) {
 	var result sql.NullInt64
 
 	err := a.conn.QueryRowContext(ctx, query, a.lockName).Scan(&result)
-	closeErr := a.conn.Close()
+	closeErr := a.closeConn(a.conn)
 	a.conn = nil
 	a.connID = 0
 	a.held = false
@@ -300,12 +320,20 @@ func (a *AdvisoryLock) checkOwnership(ctx context.Context) error {
 func (a *AdvisoryLock) markLostLocked() {
 	a.held = false
 	if a.conn != nil {
-		_ = a.conn.Close()
+		_ = a.closeConn(a.conn)
 		a.conn = nil
 	}
 	a.connID = 0
 }
 
+// closeConn closes conn unless it is the caller-owned pinned connection.
+func (a *AdvisoryLock) closeConn(conn *sql.Conn) error {
+	if conn == a.pinned {
+		return nil
+	}
+	return conn.Close()
+}
+
 // TryAcquire attempts to acquire the lock immediately without waiting.
 // Returns true if acquired, false if the lock is already held by another instance.
 // Returns an error only if there is a database failure.
@@ -371,8 +399,3 @@ func GenerateRootTableLockName(rootTable string) string {
 	}, rootTable)
 	return fmt.Sprintf("goarchive:root:%s", sanitized)
 }
-
-// NewRootTableLock creates an advisory lock keyed on a root table.
-func NewRootTableLock(db *sql.DB, rootTable string) *AdvisoryLock {
-	return NewAdvisoryLock(db, GenerateRootTableLockName(rootTable))
-}
@@ -52,15 +52,6 @@ func TestGenerateRootTableLockName(t *testing.T) {
 	}
 }
 
-func TestNewRootTableLock(t *testing.T) {
-	db, _, _ := sqlmock.New()
-	defer func() { _ = db.Close() }()
-	l := NewRootTableLock(db, "users")
-	if l.lockName != "goarchive:root:users" {
-		t.Fatalf("lock name: want %q, got %q", "goarchive:root:users", l.lockName)
-	}
-}
-
 func TestAdvisoryLock_CheckOwnership(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -135,6 +126,99 @@ func TestAdvisoryLock_CheckOwnership(t *testing.T) {
 	}
 }
 
+func TestConnAdvisoryLock_HeldAcrossOperationsOnPinnedConn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT GET_LOCK\\(\\?, \\?\\)").WithArgs("test-lock", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK(?, ?)"}).AddRow(1))
+	mock.ExpectQuery("SELECT CONNECTION_ID\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"CONNECTION_ID()"}).AddRow(42))
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT IS_USED_LOCK\\(\\?\\)").WithArgs("test-lock").
+			WillReturnRows(sqlmock.NewRows([]string{"IS_USED_LOCK(?)"}).AddRow(42))
+		mock.ExpectExec("UPDATE t").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectQuery("SELECT RELEASE_LOCK\\(\\?\\)").WithArgs("test-lock").
+		WillReturnRows(sqlmock.NewRows([]string{"RELEASE_LOCK(?)"}).AddRow(1))
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	lock := NewConnAdvisoryLock(conn, "test-lock")
+	acquired, err := lock.AcquireLock(ctx, 5)
+	if err != nil || !acquired {
+		t.Fatalf("AcquireLock = %v, %v", acquired, err)
+	}
+	if lock.conn != conn {
+		t.Fatal("lock must be taken on the pinned connection")
+	}
+
+	// The caller's own statements share the session, and ownership is still
+	// verified on it between them.
+	for i := 0; i < 3; i++ {
+		if err := lock.checkOwnership(ctx); err != nil {
+			t.Fatalf("checkOwnership after %d operations: %v", i, err)
+		}
+		if _, err := conn.ExecContext(ctx, "UPDATE t SET x = 1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	released, err := lock.ReleaseLock(ctx)
+	if err != nil || !released {
+		t.Fatalf("ReleaseLock = %v, %v", released, err)
+	}
+	// The pinned connection belongs to the caller and stays open.
+	var one int
+	if err := conn.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		t.Fatalf("pinned connection closed by ReleaseLock: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnAdvisoryLock_TimeoutKeepsPinnedConnOpen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mock.ExpectQuery("SELECT GET_LOCK\\(\\?, \\?\\)").WithArgs("test-lock", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK(?, ?)"}).AddRow(0))
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	lock := NewConnAdvisoryLock(conn, "test-lock")
+	acquired, err := lock.TryAcquire(ctx)
+	if err != nil || acquired {
+		t.Fatalf("TryAcquire = %v, %v; want false, nil", acquired, err)
+	}
+	var one int
+	if err := conn.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		t.Fatalf("pinned connection closed after timeout: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // connectToTestDB establishes a connection to the test MySQL server
 func connectToTestDB(t *testing.T) *sql.DB {
 	dsn := getTestDSN()
@@ -122,34 +122,47 @@ func buildEncoder(format string, console bool) zapcore.Encoder {
 	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
-// WithJob returns a Logger with job context.
-func (l *Logger) WithJob(jobName string) *Logger {
+// Structured field keys shared by every phase, so JSON logs can be filtered
+// and aggregated on the same names (e.g. job_name=X AND phase=delete).
+const (
+	FieldJob      = "job_name"
+	FieldPhase    = "phase"
+	FieldTable    = "table"
+	FieldBatch    = "batch_index"
+	FieldRows     = "rows"
+	FieldDuration = "duration_ms"
+)
+
+// With returns a child Logger that adds the given key-value pairs to every
+// entry. The child shares the parent's output and Close.
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
 	return &Logger{
-		SugaredLogger: l.With("job", jobName),
+		SugaredLogger: l.SugaredLogger.With(keysAndValues...),
 		base:          l.base,
 		logFile:       l.logFile,
 		closeOnce:     l.closeOnce,
 	}
 }
 
+// WithJob returns a Logger with job context.
+func (l *Logger) WithJob(jobName string) *Logger {
+	return l.With(FieldJob, jobName)
+}
+
+// WithPhase returns a Logger with phase context (discovery, copy, verify,
+// delete).
+func (l *Logger) WithPhase(phase string) *Logger {
+	return l.With(FieldPhase, phase)
+}
+
 // WithBatch returns a Logger with batch context.
 func (l *Logger) WithBatch(batchNum int) *Logger {
-	return &Logger{
-		SugaredLogger: l.With("batch", batchNum),
-		base:          l.base,
-		logFile:       l.logFile,
-		closeOnce:     l.closeOnce,
-	}
+	return l.With(FieldBatch, batchNum)
 }
 
 // WithTable returns a Logger with table context.
 func (l *Logger) WithTable(tableName string) *Logger {
-	return &Logger{
-		SugaredLogger: l.With("table", tableName),
-		base:          l.base,
-		logFile:       l.logFile,
-		closeOnce:     l.closeOnce,
-	}
+	return l.With(FieldTable, tableName)
 }
 
 // WithFields returns a Logger with additional fields.
@@ -158,12 +171,7 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	return &Logger{
-		SugaredLogger: l.With(args...),
-		base:          l.base,
-		logFile:       l.logFile,
-		closeOnce:     l.closeOnce,
-	}
+	return l.With(args...)
 }
 
 // Sync flushes any buffered log entries.
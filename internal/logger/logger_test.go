@@ -142,6 +142,27 @@ func TestWithJob(t *testing.T) {
 	_ = logger.Sync()
 }
 
+func TestWithPhaseAddsStructuredFields(t *testing.T) {
+	logPath := t.TempDir() + "/phase.log"
+	logger, err := New(&config.LoggingConfig{Level: "info", Format: "json", Output: logPath, FileOnly: true})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	logger.WithJob("test-job").WithPhase("copy").With(FieldTable, "orders").Info("phase check")
+	_ = logger.Close()
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	for _, want := range []string{`"job_name":"test-job"`, `"phase":"copy"`, `"table":"orders"`} {
+		if !strings.Contains(strings.ReplaceAll(string(content), " ", ""), want) {
+			t.Errorf("expected %s in log output, got: %s", want, content)
+		}
+	}
+}
+
 func TestWithBatch(t *testing.T) {
 	cfg := &config.LoggingConfig{
 		Level:  "info",
@@ -0,0 +1,38 @@
+package sqlutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SelectList renders a SELECT column list: "*" when columns is empty,
+// otherwise the quoted names joined by ", ".
+func SelectList(columns []string) string {
+	if len(columns) == 0 {
+		return "*"
+	}
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = QuoteIdentifier(col)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// TableColumns returns the column names of table in ordinal order, using a
+// zero-row SELECT so the result matches what SELECT * would return
+// (including how the driver reports invisible columns).
+func TableColumns(ctx context.Context, db *sql.DB, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT 0", QuoteIdentifier(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	return columns, nil
+}
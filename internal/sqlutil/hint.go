@@ -0,0 +1,25 @@
+package sqlutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithMaxExecutionTime adds a MySQL MAX_EXECUTION_TIME optimizer hint to a
+// SELECT, so the server aborts it (error 3024) once it runs longer than
+// timeout: "SELECT a FROM t" -> "SELECT /*+ MAX_EXECUTION_TIME(5000) */ a FROM t".
+// The timeout is rounded up to whole milliseconds. query is returned
+// unchanged when timeout <= 0 or it does not start with SELECT; MySQL only
+// honors the hint on top-level read-only SELECTs.
+func WithMaxExecutionTime(query string, timeout time.Duration) string {
+	if timeout <= 0 {
+		return query
+	}
+	trimmed := strings.TrimLeft(query, " \t\r\n")
+	if len(trimmed) < len("SELECT") || !strings.EqualFold(trimmed[:len("SELECT")], "SELECT") {
+		return query
+	}
+	ms := (timeout + time.Millisecond - 1) / time.Millisecond
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */%s", ms, trimmed[len("SELECT"):])
+}
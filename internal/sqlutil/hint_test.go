@@ -0,0 +1,27 @@
+package sqlutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithMaxExecutionTime(t *testing.T) {
+	tests := []struct {
+		query   string
+		timeout time.Duration
+		want    string
+	}{
+		{"SELECT `id` FROM `t` WHERE `a` IN (?)", 5 * time.Second,
+			"SELECT /*+ MAX_EXECUTION_TIME(5000) */ `id` FROM `t` WHERE `a` IN (?)"},
+		{"select COUNT(*) FROM `t`", 1500 * time.Microsecond,
+			"SELECT /*+ MAX_EXECUTION_TIME(2) */ COUNT(*) FROM `t`"},
+		{"SELECT `id` FROM `t`", 0, "SELECT `id` FROM `t`"},
+		{"DELETE FROM `t` WHERE `id` IN (?)", time.Second, "DELETE FROM `t` WHERE `id` IN (?)"},
+		{"SHOW WARNINGS", time.Second, "SHOW WARNINGS"},
+	}
+	for _, tt := range tests {
+		if got := WithMaxExecutionTime(tt.query, tt.timeout); got != tt.want {
+			t.Errorf("WithMaxExecutionTime(%q, %s) = %q, want %q", tt.query, tt.timeout, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package sqlutil
+
+import "strings"
+
+// MaxPlaceholders is MySQL's hard limit on placeholders in one prepared statement.
+const MaxPlaceholders = 65535
+
+// InClauseSize returns the number of values to put in one IN (...) list:
+// batchSize capped by maxInClause (0 = no extra cap) and by MaxPlaceholders.
+func InClauseSize(batchSize, maxInClause int) int {
+	size := batchSize
+	if maxInClause > 0 && (size <= 0 || maxInClause < size) {
+		size = maxInClause
+	}
+	if size <= 0 || size > MaxPlaceholders {
+		size = MaxPlaceholders
+	}
+	return size
+}
+
+// ChunkValues splits values into consecutive sub-slices of at most size
+// elements, one per IN (...) statement. The chunks share values' backing
+// array. size <= 0 is treated as MaxPlaceholders.
+func ChunkValues(values []interface{}, size int) [][]interface{} {
+	if size <= 0 {
+		size = MaxPlaceholders
+	}
+	chunks := make([][]interface{}, 0, (len(values)+size-1)/size)
+	for start := 0; start < len(values); start += size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+		chunks = append(chunks, values[start:end])
+	}
+	return chunks
+}
+
+// Placeholders returns n comma-separated "?" placeholders joined by sep,
+// e.g. Placeholders(3, ", ") == "?, ?, ?".
+func Placeholders(n int, sep string) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?"+sep, n-1) + "?"
+}
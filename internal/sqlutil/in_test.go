@@ -0,0 +1,62 @@
+package sqlutil
+
+import "testing"
+
+func TestInClauseSize(t *testing.T) {
+	tests := []struct {
+		batch, max, want int
+	}{
+		{1000, 0, 1000},
+		{5000, 1000, 1000},
+		{500, 1000, 500},
+		{0, 1000, 1000},
+		{0, 0, MaxPlaceholders},
+		{100000, 0, MaxPlaceholders},
+	}
+	for _, tt := range tests {
+		if got := InClauseSize(tt.batch, tt.max); got != tt.want {
+			t.Errorf("InClauseSize(%d, %d) = %d, want %d", tt.batch, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestChunkValues(t *testing.T) {
+	values := make([]interface{}, 5000)
+	for i := range values {
+		values[i] = i
+	}
+
+	chunks := ChunkValues(values, 1000)
+	if len(chunks) != 5 {
+		t.Fatalf("expected 5 chunks, got %d", len(chunks))
+	}
+	total := 0
+	for i, chunk := range chunks {
+		if len(chunk) != 1000 || chunk[0] != i*1000 {
+			t.Errorf("chunk %d: len %d, first %v", i, len(chunk), chunk[0])
+		}
+		total += len(chunk)
+	}
+	if total != 5000 {
+		t.Errorf("expected 5000 values across chunks, got %d", total)
+	}
+
+	if got := ChunkValues(values[:2500], 1000); len(got) != 3 || len(got[2]) != 500 {
+		t.Errorf("expected a short final chunk, got %d chunks", len(got))
+	}
+	if got := ChunkValues(nil, 1000); len(got) != 0 {
+		t.Errorf("expected no chunks for empty input, got %d", len(got))
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	if got := Placeholders(3, ", "); got != "?, ?, ?" {
+		t.Errorf("Placeholders(3) = %q", got)
+	}
+	if got := Placeholders(1, ","); got != "?" {
+		t.Errorf("Placeholders(1) = %q", got)
+	}
+	if got := Placeholders(0, ","); got != "" {
+		t.Errorf("Placeholders(0) = %q", got)
+	}
+}
@@ -2,8 +2,10 @@
 package sqlutil
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // QuoteIdentifier quotes a MySQL identifier (table name, column name) with backticks.
@@ -25,3 +27,33 @@ var validIdentifierRegex = regexp.MustCompile("^[a-zA-Z0-9_]+$")
 func IsValidIdentifier(name string) bool {
 	return validIdentifierRegex.MatchString(name)
 }
+
+// MaxIdentifierLength is MySQL's limit, in characters, on database, table,
+// column and index names.
+const MaxIdentifierLength = 64
+
+// CheckIdentifier rejects names MySQL cannot accept even when quoted: empty
+// names, names longer than MaxIdentifierLength characters, names ending with
+// a space, and names containing NUL, invalid UTF-8 or characters above
+// U+FFFF. Any other name, including reserved words and names with spaces or
+// backticks, is safe to emit through QuoteIdentifier. Unlike
+// IsValidIdentifier it does not restrict names to config-safe characters, so
+// it also accepts names read from the live schema.
+func CheckIdentifier(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("identifier is empty")
+	case !utf8.ValidString(name):
+		return fmt.Errorf("identifier %q is not valid UTF-8", name)
+	case utf8.RuneCountInString(name) > MaxIdentifierLength:
+		return fmt.Errorf("identifier %q is longer than %d characters", name, MaxIdentifierLength)
+	case strings.HasSuffix(name, " "):
+		return fmt.Errorf("identifier %q ends with a space", name)
+	}
+	for _, r := range name {
+		if r == 0 || r > 0xFFFF {
+			return fmt.Errorf("identifier %q contains unsupported character %U", name, r)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package sqlutil
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -132,3 +133,35 @@ func TestIsValidIdentifier_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "Reserved word", input: "order"},
+		{name: "With space", input: "my table"},
+		{name: "With backtick", input: "my`table"},
+		{name: "Leading space", input: " table"},
+		{name: "64 characters", input: strings.Repeat("a", 64)},
+		{name: "64 multibyte characters", input: strings.Repeat("é", 64)},
+		{name: "Empty string", input: "", wantErr: "is empty"},
+		{name: "Too long", input: strings.Repeat("a", 65), wantErr: "longer than 64"},
+		{name: "Trailing space", input: "table ", wantErr: "ends with a space"},
+		{name: "NUL byte", input: "ta\x00ble", wantErr: "U+0000"},
+		{name: "Supplementary character", input: "table😀", wantErr: "U+1F600"},
+		{name: "Invalid UTF-8", input: "ta\xffble", wantErr: "not valid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckIdentifier(tt.input)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
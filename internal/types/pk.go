@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// binaryPK is the map key for a binary PK, kept distinct from a string PK
+// holding the same bytes.
+type binaryPK string
+
+// IsBinaryType reports whether a column's DatabaseTypeName holds raw bytes
+// (BINARY, VARBINARY and the BLOB family), e.g. a BINARY(16) UUID.
+func IsBinaryType(databaseTypeName string) bool {
+	switch strings.ToUpper(databaseTypeName) {
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizePK converts a scanned PK value to the form carried through
+// discovery, copy, verify and delete. The MySQL driver returns []byte for
+// every non-integer column: text and DECIMAL PKs become string, while binary
+// PKs stay []byte (copied, since the driver may reuse its buffer) so they
+// bind back as bytes rather than as a character string.
+func NormalizePK(v interface{}, binary bool) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+	if binary {
+		return append([]byte(nil), b...)
+	}
+	return string(b)
+}
+
+// PKKey returns a comparable map key for pk. []byte PKs are not hashable, so
+// they are keyed by content under a type distinct from string.
+func PKKey(pk interface{}) interface{} {
+	if b, ok := pk.([]byte); ok {
+		return binaryPK(b)
+	}
+	return pk
+}
+
+// EncodePK returns the canonical text form of pk, used wherever a PK is
+// stored, compared as text or reported: integers and decimals in base 10,
+// strings as-is, and binary PKs as 0x-prefixed lowercase hex (the same form
+// the SHA256 verifier hashes).
+func EncodePK(pk interface{}) (string, error) {
+	switch v := pk.(type) {
+	case nil:
+		return "", fmt.Errorf("pk is nil")
+	case string:
+		return v, nil
+	case []byte:
+		return "0x" + hex.EncodeToString(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported pk type: %T", pk)
+	}
+}
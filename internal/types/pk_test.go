@@ -0,0 +1,77 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestIsBinaryType(t *testing.T) {
+	for _, typ := range []string{"BINARY", "VARBINARY", "BLOB", "longblob"} {
+		if !IsBinaryType(typ) {
+			t.Errorf("IsBinaryType(%q) = false, want true", typ)
+		}
+	}
+	for _, typ := range []string{"CHAR", "VARCHAR", "DECIMAL", "BIGINT", "TEXT", ""} {
+		if IsBinaryType(typ) {
+			t.Errorf("IsBinaryType(%q) = true, want false", typ)
+		}
+	}
+}
+
+func TestNormalizePK(t *testing.T) {
+	raw := []byte{0xde, 0xad, 0xbe, 0xef}
+	got := NormalizePK(raw, true)
+	b, ok := got.([]byte)
+	if !ok || string(b) != string(raw) {
+		t.Fatalf("binary: want []byte %x, got %v (%T)", raw, got, got)
+	}
+	raw[0] = 0
+	if b[0] != 0xde {
+		t.Error("binary PK must be copied, not alias the driver buffer")
+	}
+
+	if got := NormalizePK([]byte("12.50"), false); got != "12.50" {
+		t.Errorf("text/decimal: want \"12.50\", got %v (%T)", got, got)
+	}
+	if got := NormalizePK(int64(7), false); got != int64(7) {
+		t.Errorf("int64 must pass through, got %v (%T)", got, got)
+	}
+}
+
+func TestPKKey(t *testing.T) {
+	seen := map[interface{}]struct{}{}
+	for _, pk := range []interface{}{[]byte{1, 2}, []byte{1, 2}, "\x01\x02", int64(1), "1"} {
+		seen[PKKey(pk)] = struct{}{}
+	}
+	if len(seen) != 4 {
+		t.Fatalf("want 4 distinct keys (equal []byte collapse, string/binary/int stay distinct), got %d", len(seen))
+	}
+}
+
+func TestEncodePK(t *testing.T) {
+	tests := []struct {
+		name    string
+		pk      interface{}
+		want    string
+		wantErr bool
+	}{
+		{"int64", int64(-42), "-42", false},
+		{"uint64", uint64(18446744073709551615), "18446744073709551615", false},
+		{"uuid string", "6f1c2b9e-3a4d-4e5f-8a7b-1c2d3e4f5a6b", "6f1c2b9e-3a4d-4e5f-8a7b-1c2d3e4f5a6b", false},
+		{"binary uuid", []byte{0x11, 0xee, 0x00, 0xff}, "0x11ee00ff", false},
+		{"decimal string", "12.50", "12.50", false},
+		{"float", 12.5, "12.5", false},
+		{"nil rejected", nil, "", true},
+		{"unsupported", struct{}{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodePK(tt.pk)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err: want %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -16,4 +16,7 @@ type DiscoveryStats struct {
 	RecordsFound  int64         // Total records discovered across all tables
 	BFSLevels     int           // Depth of BFS traversal
 	Duration      time.Duration // Time taken for discovery
+	// EstimatedRecords is the COUNT(*)-based estimate taken before discovery
+	// (see RecordDiscovery.SetEstimateCounts); 0 when no estimate was taken.
+	EstimatedRecords int64
 }
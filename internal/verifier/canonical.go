@@ -0,0 +1,190 @@
+package verifier
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// canonicalTimeLayout renders every time.Time in UTC with microsecond
+// precision, the finest MySQL stores.
+const canonicalTimeLayout = "2006-01-02 15:04:05.999999"
+
+// canonicalFloatDigits is the significant digits floats are rounded to, so
+// values that differ only in the last bits of a DOUBLE (e.g. 0.1+0.2 and
+// 0.3) serialize identically.
+const canonicalFloatDigits = 15
+
+// valueKind is how the canonical serializer interprets a column's textual
+// driver values, from the column's database type.
+type valueKind int
+
+const (
+	kindOther valueKind = iota
+	kindInteger
+	kindFloat
+	kindDecimal
+)
+
+// kindOf maps a driver DatabaseTypeName to its valueKind.
+func kindOf(typeName string) valueKind {
+	switch strings.TrimPrefix(strings.ToUpper(typeName), "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT":
+		return kindInteger
+	case "FLOAT", "DOUBLE", "REAL":
+		return kindFloat
+	case "DECIMAL", "NUMERIC":
+		return kindDecimal
+	}
+	return kindOther
+}
+
+// newCanonicalRowSerializer returns a serializer that formats values with
+// appendCanonicalValue; typeNames are the columns' DatabaseTypeName, in
+// driver order.
+func newCanonicalRowSerializer(columns, typeNames []string) *rowSerializer {
+	s := newRowSerializer(columns)
+	s.kinds = make([]valueKind, len(columns))
+	for i := range columns {
+		if i < len(typeNames) {
+			s.kinds[i] = kindOf(typeNames[i])
+		}
+	}
+	return s
+}
+
+// appendCanonicalValue appends val so that semantically equal values read
+// from either server serialize identically, whatever Go type the driver
+// chose for them:
+//
+//   - NULL is NULL; text and binary values are hex with a 0x prefix, so the
+//     string "NULL" cannot collide with it and string and []byte agree.
+//   - integers are base 10, whether scanned as a number or as text.
+//   - floats are rounded to canonicalFloatDigits significant digits (float32
+//     values first take their shortest float32 form); -0 is 0.
+//   - DECIMAL text drops leading and trailing zeros, so 12.50 and 12.5000
+//     agree across column scales.
+//   - times are UTC in canonicalTimeLayout, so the same instant read through
+//     connections in different time zones agrees.
+//   - booleans are 1 or 0, as MySQL stores them.
+func appendCanonicalValue(buf []byte, val interface{}, kind valueKind) []byte {
+	switch v := val.(type) {
+	case nil:
+		return append(buf, "NULL"...)
+	case []byte:
+		return appendCanonicalText(buf, v, kind)
+	case string:
+		return appendCanonicalText(buf, []byte(v), kind)
+	case sql.RawBytes:
+		return appendCanonicalText(buf, v, kind)
+	case int64:
+		return strconv.AppendInt(buf, v, 10)
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case uint64:
+		return strconv.AppendUint(buf, v, 10)
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case float64:
+		return appendCanonicalFloat(buf, v)
+	case float32:
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+		return appendCanonicalFloat(buf, f)
+	case bool:
+		if v {
+			return append(buf, '1')
+		}
+		return append(buf, '0')
+	case time.Time:
+		return v.UTC().AppendFormat(buf, canonicalTimeLayout)
+	default:
+		return fmt.Appendf(buf, "%v", v)
+	}
+}
+
+// appendCanonicalText appends a value the driver returned as text according
+// to its column kind, falling back to hex when it does not parse.
+func appendCanonicalText(buf, text []byte, kind valueKind) []byte {
+	switch kind {
+	case kindInteger:
+		if n, err := strconv.ParseInt(string(text), 10, 64); err == nil {
+			return strconv.AppendInt(buf, n, 10)
+		}
+		if n, err := strconv.ParseUint(string(text), 10, 64); err == nil {
+			return strconv.AppendUint(buf, n, 10)
+		}
+	case kindFloat:
+		if f, err := strconv.ParseFloat(string(text), 64); err == nil {
+			return appendCanonicalFloat(buf, f)
+		}
+	case kindDecimal:
+		if d, ok := canonicalDecimal(string(text)); ok {
+			return append(buf, d...)
+		}
+	}
+	buf = append(buf, '0', 'x')
+	return hex.AppendEncode(buf, text)
+}
+
+// appendCanonicalFloat appends f rounded to canonicalFloatDigits significant
+// digits, with -0 as 0.
+func appendCanonicalFloat(buf []byte, f float64) []byte {
+	if f == 0 {
+		return append(buf, '0')
+	}
+	return strconv.AppendFloat(buf, f, 'g', canonicalFloatDigits, 64)
+}
+
+// canonicalDecimal normalizes a plain decimal literal such as -007.1000 to
+// -7.1, and any zero to 0. ok is false for anything else (e.g. exponents).
+func canonicalDecimal(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return "", false
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return "", false
+			}
+		}
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if intPart == "" && fracPart == "" {
+		return "0", true
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	out := intPart
+	if fracPart != "" {
+		out += "." + fracPart
+	}
+	if neg {
+		out = "-" + out
+	}
+	return out, true
+}
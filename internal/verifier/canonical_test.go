@@ -0,0 +1,138 @@
+package verifier
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dbsmedya/goarchive/internal/logger"
+	"github.com/dbsmedya/goarchive/internal/types"
+)
+
+func TestAppendCanonicalValue_EqualValues(t *testing.T) {
+	istanbul := time.FixedZone("+03", 3*60*60)
+	instant := time.Date(2026, 3, 1, 9, 30, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		name string
+		kind valueKind
+		a, b interface{}
+	}{
+		{"float rounding", kindFloat, 0.1 + 0.2, 0.3},
+		{"float32 vs text", kindFloat, float32(1.1), []byte("1.1")},
+		{"negative zero", kindFloat, math.Copysign(0, -1), []byte("0")},
+		{"decimal scale", kindDecimal, []byte("12.50"), []byte("12.5000")},
+		{"decimal leading zeros", kindDecimal, "007.10", []byte("7.1")},
+		{"decimal zero", kindDecimal, []byte("-0.00"), []byte("0")},
+		{"decimal fraction", kindDecimal, []byte(".5"), []byte("0.50")},
+		{"integer as text", kindInteger, int64(42), []byte("42")},
+		{"unsigned as text", kindInteger, uint64(18446744073709551615), []byte("18446744073709551615")},
+		{"time zones", kindOther, instant, instant.In(istanbul)},
+		{"string vs bytes", kindOther, "abc", []byte("abc")},
+		{"bool", kindInteger, true, int64(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := string(appendCanonicalValue(nil, tt.a, tt.kind))
+			b := string(appendCanonicalValue(nil, tt.b, tt.kind))
+			if a != b {
+				t.Errorf("%v and %v serialize differently: %q vs %q", tt.a, tt.b, a, b)
+			}
+		})
+	}
+}
+
+func TestAppendCanonicalValue_DistinctValues(t *testing.T) {
+	tests := []struct {
+		name string
+		kind valueKind
+		a, b interface{}
+	}{
+		{"NULL vs string NULL", kindOther, nil, "NULL"},
+		{"decimal values", kindDecimal, []byte("12.5"), []byte("12.05")},
+		{"decimal sign", kindDecimal, []byte("-1.5"), []byte("1.5")},
+		{"float beyond rounding", kindFloat, 1.0, 1.0001},
+		{"microseconds", kindOther, time.Date(2026, 1, 1, 0, 0, 0, 1000, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"empty vs NULL", kindOther, []byte{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := string(appendCanonicalValue(nil, tt.a, tt.kind))
+			b := string(appendCanonicalValue(nil, tt.b, tt.kind))
+			if a == b {
+				t.Errorf("%v and %v must serialize differently, both %q", tt.a, tt.b, a)
+			}
+		})
+	}
+}
+
+func TestAppendCanonicalValue_Formats(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		kind valueKind
+		want string
+	}{
+		{nil, kindOther, "NULL"},
+		{[]byte("NULL"), kindOther, "0x4e554c4c"},
+		{[]byte("-0012.3400"), kindDecimal, "-12.34"},
+		{[]byte("1e5"), kindDecimal, "0x316535"},
+		{2.5, kindFloat, "2.5"},
+		{time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("+03", 3*60*60)), kindOther, "2026-03-01 09:00:00"},
+		{false, kindOther, "0"},
+	}
+	for _, tt := range tests {
+		if got := string(appendCanonicalValue(nil, tt.val, tt.kind)); got != tt.want {
+			t.Errorf("appendCanonicalValue(%#v) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	for name, want := range map[string]valueKind{
+		"DECIMAL": kindDecimal, "UNSIGNED BIGINT": kindInteger, "INT": kindInteger,
+		"DOUBLE": kindFloat, "FLOAT": kindFloat, "POINT": kindOther, "VARCHAR": kindOther,
+	} {
+		if got := kindOf(name); got != want {
+			t.Errorf("kindOf(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestVerify_SHA256_CanonicalValuesIgnoreDecimalScale(t *testing.T) {
+	run := func(canonical bool) error {
+		sourceDB, sourceMock, _ := sqlmock.New()
+		defer func() { _ = sourceDB.Close() }()
+		destDB, destMock, _ := sqlmock.New()
+		defer func() { _ = destDB.Close() }()
+
+		v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodSHA256, logger.NewDefault())
+		v.SetCanonicalValues(canonical)
+
+		id := sqlmock.NewColumn("id").OfType("BIGINT", int64(0))
+		amount := sqlmock.NewColumn("amount").OfType("DECIMAL", []byte(nil))
+		sourceMock.ExpectQuery("SELECT \\* FROM `users`").WithArgs(1).
+			WillReturnRows(sqlmock.NewRowsWithColumnDefinition(id, amount).AddRow(int64(1), []byte("12.50")))
+		destMock.ExpectQuery("SELECT \\* FROM `users`").WithArgs(1).
+			WillReturnRows(sqlmock.NewRowsWithColumnDefinition(id, amount).AddRow(int64(1), []byte("12.5000")))
+		// The legacy serialization mismatches and pinpoints the row.
+		sourceMock.ExpectQuery("SELECT \\* FROM `users`").WithArgs(1).
+			WillReturnRows(sqlmock.NewRowsWithColumnDefinition(id, amount).AddRow(int64(1), []byte("12.50")))
+		destMock.ExpectQuery("SELECT \\* FROM `users`").WithArgs(1).
+			WillReturnRows(sqlmock.NewRowsWithColumnDefinition(id, amount).AddRow(int64(1), []byte("12.5000")))
+
+		_, err := v.Verify(context.Background(), &types.RecordSet{
+			RootPKs: []interface{}{1},
+			Records: map[string][]interface{}{"users": {1}},
+		})
+		return err
+	}
+
+	if err := run(false); err == nil {
+		t.Error("legacy serialization should report the DECIMAL scale difference")
+	}
+	if err := run(true); err != nil {
+		t.Errorf("canonical serialization should ignore the DECIMAL scale difference: %v", err)
+	}
+}
@@ -2,14 +2,18 @@
 package verifier
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dbsmedya/goarchive/internal/graph"
 	"github.com/dbsmedya/goarchive/internal/logger"
@@ -25,6 +29,9 @@ const (
 	MethodCount VerificationMethod = "count"
 	// MethodSHA256 uses SHA256 hash of all rows (slower but more thorough)
 	MethodSHA256 VerificationMethod = "sha256"
+	// MethodServerChecksum compares a BIT_XOR(CRC32(...)) aggregate each
+	// server computes, without fetching rows (fast, content-aware)
+	MethodServerChecksum VerificationMethod = "server_checksum"
 	// MethodSkip skips verification entirely
 	MethodSkip VerificationMethod = "skip"
 )
@@ -52,20 +59,55 @@ type VerifyStats struct {
 	TablesVerified int
 	TablesPassed   int
 	TablesFailed   int
+	FailedTables   []string // tables that mismatched, in verification order
 	TotalRows      int64
 	Method         VerificationMethod
+	// MethodPerTable is the method each verified table used: Method, or the
+	// table's verification_method override.
+	MethodPerTable map[string]VerificationMethod
+	// Tables holds each verified table's outcome, in verification order.
+	Tables []TableVerifyResult
 }
 
+// TableVerifyResult is one table's outcome in VerifyStats.Tables.
+type TableVerifyResult struct {
+	Table        string
+	Method       VerificationMethod
+	SourceCount  int64
+	DestCount    int64
+	Match        bool
+	ErrorMessage string // empty when Match
+	// MismatchedPKs lists, in record-set order, the PKs whose row is missing
+	// on one side or (sha256, server_checksum) differs between source and destination. Only
+	// set for a mismatched table; nil if the lookup itself failed (logged).
+	MismatchedPKs []interface{}
+}
+
+// ErrMismatch is wrapped by the error Verify returns when every table was
+// checked and at least one mismatched (see VerifyStats.FailedTables), as
+// opposed to a query failure that stopped verification early.
+var ErrMismatch = errors.New("verification failed")
+
 // Verifier handles data integrity verification between source and destination databases.
 //
 // GA-P4-F1: Verification Implementation
 type Verifier struct {
-	source      *sql.DB
-	destination *sql.DB
-	graph       *graph.Graph
-	method      VerificationMethod
-	chunkSize   int // For chunked SHA256 (GA-P4-F1-T3)
-	logger      *logger.Logger
+	source        *sql.DB
+	destination   *sql.DB
+	graph         *graph.Graph
+	method        VerificationMethod
+	chunkSize     int // For chunked SHA256 (GA-P4-F1-T3)
+	maxIn         int // cap on values per IN (...) list; 0 => chunkSize only
+	logger        *logger.Logger
+	selectLists   map[string]string   // table -> resolved SELECT column list for filtered tables
+	checksumExprs map[string]string   // table -> per-row CRC32 expression for server_checksum
+	ignored       map[string][]string // table -> columns excluded from SHA256 comparison
+	ignoreAll     []string            // columns excluded from SHA256 comparison in every table
+	runDate       time.Time           // dates destination_table templates without a date column
+	onTable       func(string)        // called as each table starts verifying; nil => none
+	precheck      bool                // count a table's PKs before its SHA256 row fetch
+	canonical     bool                // serialize rows with appendCanonicalValue
+	stmtTimeout   time.Duration       // MAX_EXECUTION_TIME hint on each chunk SELECT; 0 => none
 }
 
 // NewVerifier creates a new verifier for data integrity checks.
@@ -82,6 +124,9 @@ func NewVerifier(source, destination *sql.DB, g *graph.Graph, method Verificatio
 	if g == nil {
 		return nil, fmt.Errorf("graph is nil")
 	}
+	if err := g.CheckIdentifiers(); err != nil {
+		return nil, fmt.Errorf("invalid identifier in graph: %w", err)
+	}
 	if log == nil {
 		log = logger.NewDefault()
 	}
@@ -91,7 +136,7 @@ func NewVerifier(source, destination *sql.DB, g *graph.Graph, method Verificatio
 		method = MethodCount
 	}
 	switch method {
-	case MethodCount, MethodSHA256, MethodSkip:
+	case MethodCount, MethodSHA256, MethodServerChecksum, MethodSkip:
 	default:
 		return nil, fmt.Errorf("unsupported verification method: %s", method)
 	}
@@ -103,6 +148,7 @@ func NewVerifier(source, destination *sql.DB, g *graph.Graph, method Verificatio
 		method:      method,
 		chunkSize:   1000, // Default chunk size for SHA256
 		logger:      log,
+		runDate:     time.Now(),
 	}, nil
 }
 
@@ -113,6 +159,26 @@ func NewVerifier(source, destination *sql.DB, g *graph.Graph, method Verificatio
 // GA-P4-F1-T6: Returns verification statistics
 // GA-P4-F1-T7: Skip verification if method = MethodSkip
 func (v *Verifier) Verify(ctx context.Context, recordSet *types.RecordSet) (*VerifyStats, error) {
+	return v.verify(ctx, func(table string) [][]interface{} {
+		return v.chunk(table, recordSet.Records[table])
+	})
+}
+
+// VerifyBatches is Verify over PKs already split into batches per table, e.g.
+// the pkBatch slices RecordDiscovery.DiscoverStream yields. Each batch is
+// queried as one IN (...) list, as it was copied, instead of the table's PKs
+// being merged and re-chunked by the chunk size; only batches larger than
+// SetMaxInClauseSize are split. The stats are the same as Verify's over the
+// same PKs.
+func (v *Verifier) VerifyBatches(ctx context.Context, batches map[string][][]interface{}) (*VerifyStats, error) {
+	return v.verify(ctx, func(table string) [][]interface{} {
+		return v.capBatches(batches[table])
+	})
+}
+
+// verify verifies each table in copy order over the PK batches batchesFor
+// returns for it; tables without batches are skipped.
+func (v *Verifier) verify(ctx context.Context, batchesFor func(table string) [][]interface{}) (*VerifyStats, error) {
 	// GA-P4-F1-T7: Skip verification if requested
 	if v.method == MethodSkip {
 		v.logger.Info("Verification SKIPPED (method=skip)")
@@ -121,12 +187,14 @@ func (v *Verifier) Verify(ctx context.Context, recordSet *types.RecordSet) (*Ver
 		}, nil
 	}
 
+	startTime := time.Now()
 	stats := &VerifyStats{
-		Method: v.method,
+		Method:         v.method,
+		MethodPerTable: make(map[string]VerificationMethod),
 	}
 
 	// Get copy order to verify tables in same order
-	copyOrder, err := v.graph.CopyOrder()
+	copyOrder, err := v.graph.CopyOrderContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get copy order: %w", err)
 	}
@@ -135,8 +203,8 @@ func (v *Verifier) Verify(ctx context.Context, recordSet *types.RecordSet) (*Ver
 
 	// Verify each table
 	for _, table := range copyOrder {
-		pks, exists := recordSet.Records[table]
-		if !exists || len(pks) == 0 {
+		batches := batchesFor(table)
+		if len(batches) == 0 {
 			// Table has no records to verify
 			v.logger.Debugf("Skipping table %q (no records)", table)
 			continue
@@ -147,15 +215,23 @@ func (v *Verifier) Verify(ctx context.Context, recordSet *types.RecordSet) (*Ver
 			return stats, fmt.Errorf("verification interrupted: %w", err)
 		}
 
+		if v.onTable != nil {
+			v.onTable(table)
+		}
+
 		// Verify table based on method
+		tableStart := time.Now()
 		var result *VerifyResult
-		switch v.method {
+		method := v.methodFor(table)
+		switch method {
 		case MethodCount:
-			result, err = v.verifyByCount(ctx, table, pks)
+			result, err = v.verifyByCount(ctx, table, batches)
 		case MethodSHA256:
-			result, err = v.verifyBySHA256(ctx, table, pks)
+			result, err = v.verifyBySHA256(ctx, table, batches)
+		case MethodServerChecksum:
+			result, err = v.verifyByServerChecksum(ctx, table, batches)
 		default:
-			return stats, fmt.Errorf("unsupported verification method: %s", v.method)
+			return stats, fmt.Errorf("unsupported verification method for table %s: %s", table, method)
 		}
 
 		if err != nil {
@@ -164,32 +240,92 @@ func (v *Verifier) Verify(ctx context.Context, recordSet *types.RecordSet) (*Ver
 
 		stats.TablesVerified++
 		stats.TotalRows += result.SourceCount
+		stats.MethodPerTable[table] = method
+		tableResult := TableVerifyResult{
+			Table:        table,
+			Method:       method,
+			SourceCount:  result.SourceCount,
+			DestCount:    result.DestCount,
+			Match:        result.Match,
+			ErrorMessage: result.ErrorMessage,
+		}
+		if !result.Match {
+			tableResult.MismatchedPKs, err = v.mismatchedPKs(ctx, table, method, batches)
+			if err != nil {
+				v.logger.Warnf("Failed to list mismatched rows of table %s: %v", table, err)
+			}
+		}
+		stats.Tables = append(stats.Tables, tableResult)
 
 		if result.Match {
 			stats.TablesPassed++
-			v.logger.Debugf("Verification PASSED for table %q (%d rows)", table, result.SourceCount)
+			v.logger.Debugw("Verification PASSED",
+				logger.FieldTable, table,
+				"method", method,
+				logger.FieldRows, result.SourceCount,
+				logger.FieldDuration, time.Since(tableStart).Milliseconds(),
+			)
 		} else {
 			// GA-P4-F1-T5: Mismatch handling
 			stats.TablesFailed++
-			v.logger.Errorf("Verification FAILED for table %q: %s", table, result.ErrorMessage)
+			stats.FailedTables = append(stats.FailedTables, table)
+			v.logger.Errorw("Verification FAILED",
+				logger.FieldTable, table,
+				"method", method,
+				logger.FieldRows, result.SourceCount,
+				logger.FieldDuration, time.Since(tableStart).Milliseconds(),
+				"error", result.ErrorMessage,
+			)
 		}
 	}
 
-	v.logger.Infof("Verification complete: %d tables verified, %d passed, %d failed, %d total rows",
-		stats.TablesVerified, stats.TablesPassed, stats.TablesFailed, stats.TotalRows)
+	v.logger.Infow("Verification complete",
+		"tables", stats.TablesVerified,
+		"passed", stats.TablesPassed,
+		"failed", stats.TablesFailed,
+		logger.FieldRows, stats.TotalRows,
+		logger.FieldDuration, time.Since(startTime).Milliseconds(),
+	)
 
 	if stats.TablesFailed > 0 {
-		return stats, fmt.Errorf("verification failed: %d tables had mismatches", stats.TablesFailed)
+		return stats, fmt.Errorf("%w: %d tables had mismatches", ErrMismatch, stats.TablesFailed)
 	}
 
 	return stats, nil
 }
 
+// methodFor returns the method used for table: the graph node's
+// verification_method override, or the verifier's method.
+func (v *Verifier) methodFor(table string) VerificationMethod {
+	if node := v.graph.GetNode(table); node != nil && node.VerificationMethod != "" {
+		return VerificationMethod(node.VerificationMethod)
+	}
+	return v.method
+}
+
+// chunk splits table's pks into the IN (...) lists every query of the table
+// uses: the table's batch size or the chunk size, capped by
+// SetMaxInClauseSize.
+func (v *Verifier) chunk(table string, pks []interface{}) [][]interface{} {
+	return sqlutil.ChunkValues(pks, sqlutil.InClauseSize(v.graph.BatchSizeFor(table, v.chunkSize), v.maxIn))
+}
+
+// capBatches drops empty batches and splits those larger than the IN (...)
+// cap (SetMaxInClauseSize, then MaxPlaceholders).
+func (v *Verifier) capBatches(batches [][]interface{}) [][]interface{} {
+	size := sqlutil.InClauseSize(0, v.maxIn)
+	capped := make([][]interface{}, 0, len(batches))
+	for _, batch := range batches {
+		capped = append(capped, sqlutil.ChunkValues(batch, size)...)
+	}
+	return capped
+}
+
 // verifyByCount compares row counts between source and destination.
 //
 // GA-P4-F1-T1: Row count verification
-func (v *Verifier) verifyByCount(ctx context.Context, table string, pks []interface{}) (*VerifyResult, error) {
-	if len(pks) == 0 {
+func (v *Verifier) verifyByCount(ctx context.Context, table string, batches [][]interface{}) (*VerifyResult, error) {
+	if len(batches) == 0 {
 		return &VerifyResult{
 			Table:       table,
 			Method:      MethodCount,
@@ -202,13 +338,22 @@ func (v *Verifier) verifyByCount(ctx context.Context, table string, pks []interf
 	// GA-P3-F3-T9: Get PK column from graph (supports configurable PKs for all tables)
 	pkColumn := v.graph.GetPK(table)
 
-	sourceCount, err := v.countByPKChunks(ctx, v.source, table, pkColumn, pks)
+	groups, err := v.destinationGroups(ctx, table, batches)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count source: %w", err)
+		return nil, err
 	}
-	destCount, err := v.countByPKChunks(ctx, v.destination, table, pkColumn, pks)
+
+	sourceCount, err := v.countByPKChunks(ctx, v.source, table, pkColumn, batches)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count destination: %w", err)
+		return nil, fmt.Errorf("failed to count source: %w", err)
+	}
+	var destCount int64
+	for _, group := range groups {
+		count, err := v.countByPKChunks(ctx, v.destination, group.dest, pkColumn, group.batches)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count destination: %w", err)
+		}
+		destCount += count
 	}
 
 	result := &VerifyResult{
@@ -227,28 +372,18 @@ func (v *Verifier) verifyByCount(ctx context.Context, table string, pks []interf
 	return result, nil
 }
 
-func (v *Verifier) countByPKChunks(ctx context.Context, db *sql.DB, table, pkColumn string, pks []interface{}) (int64, error) {
+// countByPKChunks counts the rows of a table's PK batches found in table from
+// of db: the table itself on the source, its destination table on the
+// destination. One COUNT(*) per batch.
+func (v *Verifier) countByPKChunks(ctx context.Context, db *sql.DB, from, pkColumn string, batches [][]interface{}) (int64, error) {
 	var total int64
 
-	for i := 0; i < len(pks); i += v.chunkSize {
-		end := i + v.chunkSize
-		if end > len(pks) {
-			end = len(pks)
-		}
-		chunk := pks[i:end]
-
-		placeholders := make([]string, len(chunk))
-		args := make([]interface{}, len(chunk))
-		for j, pk := range chunk {
-			placeholders[j] = "?"
-			args[j] = pk
-		}
-
+	for _, chunk := range batches {
 		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IN (%s)",
-			sqlutil.QuoteIdentifier(table), sqlutil.QuoteIdentifier(pkColumn), strings.Join(placeholders, ","))
+			sqlutil.QuoteIdentifier(from), sqlutil.QuoteIdentifier(pkColumn), sqlutil.Placeholders(len(chunk), ","))
 
 		var count int64
-		if err := db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		if err := db.QueryRowContext(ctx, sqlutil.WithMaxExecutionTime(query, v.stmtTimeout), chunk...).Scan(&count); err != nil {
 			return 0, err
 		}
 		total += count
@@ -261,8 +396,8 @@ func (v *Verifier) countByPKChunks(ctx context.Context, db *sql.DB, table, pkCol
 //
 // GA-P4-F1-T2: SHA256 hash verification
 // GA-P4-F1-T3: Chunked SHA256 for large datasets
-func (v *Verifier) verifyBySHA256(ctx context.Context, table string, pks []interface{}) (*VerifyResult, error) {
-	if len(pks) == 0 {
+func (v *Verifier) verifyBySHA256(ctx context.Context, table string, batches [][]interface{}) (*VerifyResult, error) {
+	if len(batches) == 0 {
 		return &VerifyResult{
 			Table:  table,
 			Method: MethodSHA256,
@@ -270,13 +405,34 @@ func (v *Verifier) verifyBySHA256(ctx context.Context, table string, pks []inter
 		}, nil
 	}
 
+	// Both sides hash the same PK chunks, grouped by destination table.
+	groups, err := v.destinationGroups(ctx, table, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.precheck {
+		empty, err := v.emptyOnBothSides(ctx, table, groups)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			v.logger.Debugf("Skipping SHA256 fetch of table %q (no rows on either side)", table)
+			return &VerifyResult{
+				Table:  table,
+				Method: MethodSHA256,
+				Match:  true,
+			}, nil
+		}
+	}
+
 	// GA-P4-F1-T3: Chunk PKs to avoid memory issues
-	sourceHash, sourceCount, err := v.computeTableHash(ctx, v.source, table, pks)
+	sourceHash, sourceCount, err := v.computeTableHash(ctx, v.source, table, groups, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute source hash: %w", err)
 	}
 
-	destHash, destCount, err := v.computeTableHash(ctx, v.destination, table, pks)
+	destHash, destCount, err := v.computeTableHash(ctx, v.destination, table, groups, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute destination hash: %w", err)
 	}
@@ -303,39 +459,80 @@ func (v *Verifier) verifyBySHA256(ctx context.Context, table string, pks []inter
 	return result, nil
 }
 
-// computeTableHash computes a SHA256 hash of all rows in the specified table for the given PKs.
+// emptyOnBothSides reports whether none of the PKs of groups exist on the
+// source or in their destination tables, counting each side with COUNT(*)
+// queries so an empty table costs no row fetch.
+func (v *Verifier) emptyOnBothSides(ctx context.Context, table string, groups []pkGroup) (bool, error) {
+	pkColumn := v.graph.GetPK(table)
+	for _, group := range groups {
+		count, err := v.countByPKChunks(ctx, v.source, table, pkColumn, group.batches)
+		if err != nil {
+			return false, fmt.Errorf("failed to count source: %w", err)
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	for _, group := range groups {
+		count, err := v.countByPKChunks(ctx, v.destination, group.dest, pkColumn, group.batches)
+		if err != nil {
+			return false, fmt.Errorf("failed to count destination: %w", err)
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// computeTableHash computes a SHA256 hash of all rows in the specified table
+// for the PKs of groups, read from table itself or, when onDest is set, from
+// each group's destination table.
 //
 // GA-P4-F1-T2: SHA256 hash computation
 // GA-P4-F1-T3: Chunked processing for large datasets
-func (v *Verifier) computeTableHash(ctx context.Context, db *sql.DB, table string, pks []interface{}) (string, int64, error) {
+func (v *Verifier) computeTableHash(ctx context.Context, db *sql.DB, table string, groups []pkGroup, onDest bool) (string, int64, error) {
 	// GA-P3-F3-T9: Get PK column from graph (supports configurable PKs for all tables)
 	pkColumn := v.graph.GetPK(table)
 
+	selectList, err := v.selectList(ctx, table)
+	if err != nil {
+		return "", 0, err
+	}
+
 	// GA-P4-F1-T3: Process in chunks to avoid memory issues
 	hasher := sha256.New()
 	var totalRows int64
 
-	for i := 0; i < len(pks); i += v.chunkSize {
-		end := i + v.chunkSize
-		if end > len(pks) {
-			end = len(pks)
+	for _, group := range groups {
+		from := table
+		if onDest {
+			from = group.dest
 		}
-		chunk := pks[i:end]
-
-		// Build query
-		placeholders := make([]string, len(chunk))
-		args := make([]interface{}, len(chunk))
-		for j, pk := range chunk {
-			placeholders[j] = "?"
-			args[j] = pk
+		n, err := v.hashChunks(ctx, db, hasher, from, selectList, pkColumn, group.batches)
+		if err != nil {
+			return "", 0, err
 		}
+		totalRows += n
+	}
+
+	hashBytes := hasher.Sum(nil)
+	hashStr := hex.EncodeToString(hashBytes)
+
+	return hashStr, totalRows, nil
+}
 
+// hashChunks writes the rows of a table's PK batches, read from table from of
+// db, to hasher one batch at a time and returns how many rows it hashed.
+func (v *Verifier) hashChunks(ctx context.Context, db *sql.DB, hasher hash.Hash, from, selectList, pkColumn string, batches [][]interface{}) (int64, error) {
+	var totalRows int64
+	for _, chunk := range batches {
 		// Fetch all rows ordered by PK for deterministic hashing
-		query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN (%s) ORDER BY %s",
-			sqlutil.QuoteIdentifier(table), sqlutil.QuoteIdentifier(pkColumn), strings.Join(placeholders, ","), sqlutil.QuoteIdentifier(pkColumn))
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s) ORDER BY %s",
+			selectList, sqlutil.QuoteIdentifier(from), sqlutil.QuoteIdentifier(pkColumn), sqlutil.Placeholders(len(chunk), ","), sqlutil.QuoteIdentifier(pkColumn))
 
 		if err := func() error {
-			rows, err := db.QueryContext(ctx, query, args...)
+			rows, err := db.QueryContext(ctx, sqlutil.WithMaxExecutionTime(query, v.stmtTimeout), chunk...)
 			if err != nil {
 				return fmt.Errorf("query failed: %w", err)
 			}
@@ -353,14 +550,18 @@ func (v *Verifier) computeTableHash(ctx context.Context, db *sql.DB, table strin
 
 			// Allocate scan targets and the serializer once per chunk;
 			// Scan overwrites values in place on every row.
-			serializer := newRowSerializer(columns)
+			serializer, err := v.serializerFor(rows, columns)
+			if err != nil {
+				return fmt.Errorf("failed to get column types: %w", err)
+			}
 			values := make([]interface{}, len(columns))
 			valuePtrs := make([]interface{}, len(columns))
 			for j := range values {
 				valuePtrs[j] = &values[j]
 			}
 
-			// Hash each row
+			// Serialize each row; the chunk is hashed once it is sorted
+			var chunkRows [][]byte
 			for rows.Next() {
 				// Check context cancellation
 				if err := ctx.Err(); err != nil {
@@ -371,33 +572,241 @@ func (v *Verifier) computeTableHash(ctx context.Context, db *sql.DB, table strin
 					return fmt.Errorf("failed to scan row: %w", err)
 				}
 
-				// Hash row: col1=val1\x00col2=val2...\n (sorted by column name)
-				hasher.Write(serializer.appendRow(values))
+				// Row: col1=val1\x00col2=val2...\n (sorted by column name)
+				chunkRows = append(chunkRows, append([]byte(nil), serializer.appendRow(values)...))
 				totalRows++
 			}
 
 			if err := rows.Err(); err != nil {
 				return fmt.Errorf("error iterating rows: %w", err)
 			}
+
+			// ORDER BY <pk> orders each side, but source and destination can
+			// still disagree on the order of identical rows (different PK
+			// collation, server version or storage layout). Both sides query
+			// the same chunk of PKs, so hashing the chunk's rows in byte order
+			// makes the digest independent of the order the server returned.
+			sort.Slice(chunkRows, func(a, b int) bool {
+				return bytes.Compare(chunkRows[a], chunkRows[b]) < 0
+			})
+			for _, row := range chunkRows {
+				hasher.Write(row)
+			}
 			return nil
 		}(); err != nil {
-			return "", 0, err
+			return 0, err
 		}
 	}
+	return totalRows, nil
+}
 
-	hashBytes := hasher.Sum(nil)
-	hashStr := hex.EncodeToString(hashBytes)
+// mismatchedPKs returns the PKs of table's batches whose row is on only one side or,
+// for sha256 and server_checksum, whose serialized row differs between
+// source and destination. Count verification compares PK presence only.
+func (v *Verifier) mismatchedPKs(ctx context.Context, table string, method VerificationMethod, batches [][]interface{}) ([]interface{}, error) {
+	pkColumn := v.graph.GetPK(table)
+	selectList := sqlutil.QuoteIdentifier(pkColumn)
+	if method != MethodCount {
+		var err error
+		if selectList, err = v.selectList(ctx, table); err != nil {
+			return nil, err
+		}
+	}
+	groups, err := v.destinationGroups(ctx, table, batches)
+	if err != nil {
+		return nil, err
+	}
 
-	return hashStr, totalRows, nil
+	var mismatched []interface{}
+	for _, group := range groups {
+		sourceRows, err := v.rowsByPK(ctx, v.source, table, selectList, pkColumn, group.batches)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source rows: %w", err)
+		}
+		destRows, err := v.rowsByPK(ctx, v.destination, group.dest, selectList, pkColumn, group.batches)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read destination rows: %w", err)
+		}
+		for _, batch := range group.batches {
+			for _, pk := range batch {
+				key := pkKey(pk)
+				sourceRow, inSource := sourceRows[key]
+				destRow, inDest := destRows[key]
+				if inSource != inDest || sourceRow != destRow {
+					mismatched = append(mismatched, pk)
+				}
+			}
+		}
+	}
+	return mismatched, nil
+}
+
+// rowsByPK reads the rows of a table's PK batches from table from of db and
+// returns each row serialized as the hasher sees it, keyed by pkKey of its PK.
+func (v *Verifier) rowsByPK(ctx context.Context, db *sql.DB, from, selectList, pkColumn string, batches [][]interface{}) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, chunk := range batches {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)",
+			selectList, sqlutil.QuoteIdentifier(from), sqlutil.QuoteIdentifier(pkColumn), sqlutil.Placeholders(len(chunk), ","))
+		if err := func() error {
+			rows, err := db.QueryContext(ctx, sqlutil.WithMaxExecutionTime(query, v.stmtTimeout), chunk...)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := rows.Close(); err != nil {
+					v.logger.Warnf("Failed to close rows: %v", err)
+				}
+			}()
+			columns, err := rows.Columns()
+			if err != nil {
+				return err
+			}
+			pkIndex := -1
+			for i, col := range columns {
+				if strings.EqualFold(col, pkColumn) {
+					pkIndex = i
+				}
+			}
+			if pkIndex < 0 {
+				return fmt.Errorf("primary key %s not among the selected columns", pkColumn)
+			}
+			serializer, err := v.serializerFor(rows, columns)
+			if err != nil {
+				return err
+			}
+			values := make([]interface{}, len(columns))
+			valuePtrs := make([]interface{}, len(columns))
+			for i := range values {
+				valuePtrs[i] = &values[i]
+			}
+			for rows.Next() {
+				if err := rows.Scan(valuePtrs...); err != nil {
+					return err
+				}
+				out[pkKey(values[pkIndex])] = string(serializer.appendRow(values))
+			}
+			return rows.Err()
+		}(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// pkKey normalizes a PK value so a record-set PK and the same PK scanned
+// from either database compare equal.
+func pkKey(pk interface{}) string {
+	if b, ok := pk.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(pk)
+}
+
+// pkGroup is a set of PKs of one table whose rows were copied into the same
+// destination table, in the batches they are queried in.
+type pkGroup struct {
+	dest    string
+	batches [][]interface{}
+}
+
+// destinationGroups splits the PK batches of table by destination table,
+// mirroring the copy phase: one group for table itself or its
+// destination_table template expanded for the run date, or, with a
+// destination_date_column, one group per destination in first-seen order,
+// dated from the source rows. Each batch contributes at most one batch to
+// each group.
+func (v *Verifier) destinationGroups(ctx context.Context, table string, batches [][]interface{}) ([]pkGroup, error) {
+	dateColumn := v.graph.DestinationDateColumn(table)
+	if dateColumn == "" {
+		return []pkGroup{{dest: v.graph.DestinationTable(table, v.runDate), batches: batches}}, nil
+	}
+
+	pkColumn := v.graph.GetPK(table)
+	var groups []pkGroup
+	index := make(map[string]int)
+	for _, chunk := range batches {
+		query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s IN (%s)",
+			sqlutil.QuoteIdentifier(pkColumn), sqlutil.QuoteIdentifier(dateColumn), sqlutil.QuoteIdentifier(table),
+			sqlutil.QuoteIdentifier(pkColumn), sqlutil.Placeholders(len(chunk), ","))
+		if err := func() error {
+			rows, err := v.source.QueryContext(ctx, sqlutil.WithMaxExecutionTime(query, v.stmtTimeout), chunk...)
+			if err != nil {
+				return fmt.Errorf("failed to read destination dates: %w", err)
+			}
+			defer func() {
+				if err := rows.Close(); err != nil {
+					v.logger.Warnf("Failed to close rows: %v", err)
+				}
+			}()
+			started := make(map[int]bool) // groups with a batch for this chunk
+			for rows.Next() {
+				var pk, date interface{}
+				if err := rows.Scan(&pk, &date); err != nil {
+					return fmt.Errorf("failed to scan destination date: %w", err)
+				}
+				dest, err := v.graph.DestinationTableForValue(table, date)
+				if err != nil {
+					return err
+				}
+				i, ok := index[dest]
+				if !ok {
+					i = len(groups)
+					index[dest] = i
+					groups = append(groups, pkGroup{dest: dest})
+				}
+				if !started[i] {
+					groups[i].batches = append(groups[i].batches, nil)
+					started[i] = true
+				}
+				last := len(groups[i].batches) - 1
+				groups[i].batches[last] = append(groups[i].batches[last], pk)
+			}
+			return rows.Err()
+		}(); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// selectList returns the SELECT column list used to hash table: "*" unless
+// the table has a column filter or ignored columns, in which case only the
+// copied, untransformed columns are compared. Exclusion filters are resolved
+// against the source table once and the same list is used for the
+// destination, so columns the copy phase left out are never read on either
+// side.
+func (v *Verifier) selectList(ctx context.Context, table string) (string, error) {
+	filter := v.graph.GetColumnFilter(table)
+	if ignored := v.ignoredColumns(table); len(ignored) > 0 {
+		filter = filter.Without(ignored...)
+	}
+	if filter == nil {
+		return "*", nil
+	}
+	if list, ok := v.selectLists[table]; ok {
+		return list, nil
+	}
+
+	list, err := filter.SelectList(ctx, v.source, table)
+	if err != nil {
+		return "", err
+	}
+	if v.selectLists == nil {
+		v.selectLists = make(map[string]string)
+	}
+	v.selectLists[table] = list
+	return list, nil
 }
 
 // rowSerializer serializes rows that share one column set into a reusable
 // buffer, preserving the historical byte format the hasher consumes:
 // pairs sorted by column name, "col=value" joined by \x00, one \n per row.
 type rowSerializer struct {
-	order []int    // column indices in name-sorted order, computed once
-	names []string // column names in driver order
-	buf   []byte   // reused across rows; valid until the next appendRow
+	order []int       // column indices in name-sorted order, computed once
+	names []string    // column names in driver order
+	kinds []valueKind // driver order; non-nil => appendCanonicalValue formatting
+	buf   []byte      // reused across rows; valid until the next appendRow
 }
 
 func newRowSerializer(columns []string) *rowSerializer {
@@ -422,7 +831,11 @@ func (s *rowSerializer) appendRow(values []interface{}) []byte {
 		}
 		s.buf = append(s.buf, s.names[idx]...)
 		s.buf = append(s.buf, '=')
-		s.buf = appendValue(s.buf, values[idx])
+		if s.kinds != nil {
+			s.buf = appendCanonicalValue(s.buf, values[idx], s.kinds[idx])
+		} else {
+			s.buf = appendValue(s.buf, values[idx])
+		}
 	}
 	s.buf = append(s.buf, '\n')
 	return s.buf
@@ -450,6 +863,107 @@ func appendValue(buf []byte, val interface{}) []byte {
 	}
 }
 
+// SetIgnoredColumns excludes columns of table from SHA256 comparison, for
+// columns whose archived value intentionally differs from the source (e.g.
+// masked by a copy transform). Row counts are unaffected.
+func (v *Verifier) SetIgnoredColumns(table string, columns []string) {
+	if v.ignored == nil {
+		v.ignored = make(map[string][]string)
+	}
+	v.ignored[table] = columns
+	delete(v.selectLists, table)
+}
+
+// SetGlobalIgnoredColumns excludes columns from SHA256 comparison in every
+// table that has them (verification.ignore_columns), e.g. timestamps a
+// destination trigger rewrites on insert. Unlike SetIgnoredColumns it is not
+// per table. A table's primary key is always compared. Row counts are
+// unaffected.
+func (v *Verifier) SetGlobalIgnoredColumns(columns []string) {
+	v.ignoreAll = columns
+	v.selectLists = nil
+}
+
+// ignoredColumns returns the columns of table left out of SHA256
+// comparison: its SetIgnoredColumns list plus the SetGlobalIgnoredColumns
+// ones other than its primary key.
+func (v *Verifier) ignoredColumns(table string) []string {
+	ignored := v.ignored[table]
+	if len(v.ignoreAll) == 0 {
+		return ignored
+	}
+	pk := v.graph.GetPK(table)
+	ignored = append([]string(nil), ignored...)
+	for _, col := range v.ignoreAll {
+		if !strings.EqualFold(col, pk) {
+			ignored = append(ignored, col)
+		}
+	}
+	return ignored
+}
+
+// SetMaxInClauseSize caps the number of PKs bound into one IN (...) list,
+// independently of the chunk size. Larger chunks are split into several
+// queries whose counts (or hashed rows) are combined in order. 0 (the
+// default) leaves the cap at the chunk size.
+func (v *Verifier) SetMaxInClauseSize(n int) {
+	v.maxIn = n
+}
+
+// SetStatementTimeout bounds each chunk SELECT with a MySQL
+// MAX_EXECUTION_TIME hint (processing.statement_timeout); the server aborts a
+// slower one with error 3024. 0 (the default) adds no hint.
+func (v *Verifier) SetStatementTimeout(timeout time.Duration) {
+	v.stmtTimeout = timeout
+}
+
+// SetTableObserver registers fn, called with each table's name as
+// verification starts on it (orchestrator progress snapshots). nil removes it.
+func (v *Verifier) SetTableObserver(fn func(table string)) {
+	v.onTable = fn
+}
+
+// SetCanonicalValues makes sha256 verification (and the row comparison
+// that pinpoints mismatches) serialize values with appendCanonicalValue, so
+// rows that are equal in MySQL but read back as different Go values (DECIMAL
+// scale, float rounding, time zone) hash identically. Off by default, which
+// keeps the historical serialization.
+func (v *Verifier) SetCanonicalValues(enabled bool) {
+	v.canonical = enabled
+}
+
+// serializerFor returns the row serializer for rows' columns: canonical,
+// typed from rows' column types, when SetCanonicalValues is on.
+func (v *Verifier) serializerFor(rows *sql.Rows, columns []string) (*rowSerializer, error) {
+	if !v.canonical {
+		return newRowSerializer(columns), nil
+	}
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	typeNames := make([]string, len(colTypes))
+	for i, ct := range colTypes {
+		typeNames[i] = ct.DatabaseTypeName()
+	}
+	return newCanonicalRowSerializer(columns, typeNames), nil
+}
+
+// SetCountPrecheck enables a COUNT(*) of each table's PKs on both sides
+// before its SHA256 row fetch; the fetch is skipped when neither side has
+// any of the rows. Tables that do have rows pay one extra count per side.
+func (v *Verifier) SetCountPrecheck(enabled bool) {
+	v.precheck = enabled
+}
+
+// SetRunDate sets the job run date that fills the {year} and {month}
+// placeholders of destination_table templates without a
+// destination_date_column. Use the copy phase's run date so both name the
+// same tables.
+func (v *Verifier) SetRunDate(t time.Time) {
+	v.runDate = t
+}
+
 // SetChunkSize sets the chunk size for chunked SHA256 verification.
 //
 // GA-P4-F1-T3: Chunked SHA256 configuration
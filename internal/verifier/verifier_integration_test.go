@@ -105,7 +105,7 @@ func TestVerifier_SHA256_RealMySQLTypes_Integration(t *testing.T) {
 	}
 	pks := []interface{}{uint64(1), uint64(2), uint64(18446744073709551615)}
 
-	result, err := v.verifyBySHA256(context.Background(), "ga9_verify_types", pks)
+	result, err := v.verifyBySHA256(context.Background(), "ga9_verify_types", v.chunk("ga9_verify_types", pks))
 	if err != nil {
 		t.Fatalf("verifyBySHA256: %v", err)
 	}
@@ -115,7 +115,7 @@ func TestVerifier_SHA256_RealMySQLTypes_Integration(t *testing.T) {
 
 	// A single-column mutation on one row must flip the hash.
 	mustExec(t, destDB, "UPDATE ga9_verify_types SET s = 'tampered' WHERE id = 1")
-	result, err = v.verifyBySHA256(context.Background(), "ga9_verify_types", pks)
+	result, err = v.verifyBySHA256(context.Background(), "ga9_verify_types", v.chunk("ga9_verify_types", pks))
 	if err != nil {
 		t.Fatalf("verifyBySHA256 after mutation: %v", err)
 	}
@@ -3,10 +3,12 @@ package verifier
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -216,6 +218,44 @@ func TestVerify_Count_Success(t *testing.T) {
 	}
 }
 
+func TestVerify_Count_MaxInClauseSizeSplitsQueries(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodCount, logger.NewDefault())
+	v.SetChunkSize(5000)
+	v.SetMaxInClauseSize(1000)
+
+	pks := make([]interface{}, 5000)
+	for i := range pks {
+		pks[i] = i + 1
+	}
+	recordSet := &types.RecordSet{RootPKs: pks, Records: map[string][]interface{}{"users": pks}}
+
+	for i := 0; i < 5; i++ {
+		sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1000))
+		destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1000))
+	}
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if stats.TotalRows != 5000 || stats.TablesPassed != 1 {
+		t.Errorf("expected 5000 rows in one passing table, got %+v", stats)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected five source COUNT queries: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected five destination COUNT queries: %v", err)
+	}
+}
+
 func TestVerify_Count_Mismatch(t *testing.T) {
 	sourceDB, sourceMock, _ := sqlmock.New()
 	defer func() { _ = sourceDB.Close() }()
@@ -402,6 +442,134 @@ func TestVerify_SHA256_Success(t *testing.T) {
 	}
 }
 
+func TestVerify_SHA256_ColumnFilter(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createTestGraph()
+	g.SetColumnFilter("users", &graph.ColumnFilter{Exclude: []string{"password_hash"}})
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodSHA256, logger.NewDefault())
+
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users": {1},
+		},
+	}
+
+	// The excluded column is resolved against the source once and is never
+	// selected on either side; the destination holds NULL for it.
+	sourceMock.ExpectQuery("SELECT \\* FROM `users` LIMIT 0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "password_hash"}))
+	sourceMock.ExpectQuery("SELECT `id`, `name` FROM `users` WHERE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+	destMock.ExpectQuery("SELECT `id`, `name` FROM `users` WHERE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if stats.TablesPassed != 1 {
+		t.Errorf("Expected 1 table passed, got %d", stats.TablesPassed)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}
+
+func TestVerify_SHA256_IgnoredColumns(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createTestGraph()
+	g.SetColumnFilter("users", &graph.ColumnFilter{Include: []string{"id", "name", "email"}})
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodSHA256, logger.NewDefault())
+	v.SetIgnoredColumns("users", []string{"email"})
+
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users": {1},
+		},
+	}
+
+	// email is masked in the destination, so it is not selected on either side.
+	sourceMock.ExpectQuery("SELECT `id`, `name` FROM `users` WHERE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+	destMock.ExpectQuery("SELECT `id`, `name` FROM `users` WHERE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if stats.TablesPassed != 1 {
+		t.Errorf("Expected 1 table passed, got %d", stats.TablesPassed)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}
+
+func TestVerify_SHA256_IgnoreColumnsEveryTable(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodSHA256, logger.NewDefault())
+	// "id" is the primary key and is compared regardless.
+	v.SetGlobalIgnoredColumns([]string{"updated_at", "id"})
+
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users": {1},
+		},
+	}
+
+	// The column list is read from the source once; updated_at is left out
+	// of both SELECTs, so the trigger-rewritten destination value is never
+	// compared.
+	sourceMock.ExpectQuery("SELECT \\* FROM `users` LIMIT 0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "updated_at"}))
+	sourceMock.ExpectQuery("SELECT `id`, `name` FROM `users` WHERE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+	destMock.ExpectQuery("SELECT `id`, `name` FROM `users` WHERE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if stats.TablesPassed != 1 {
+		t.Errorf("Expected 1 table passed, got %d", stats.TablesPassed)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}
+
 func TestVerify_SHA256_Mismatch(t *testing.T) {
 	sourceDB, sourceMock, _ := sqlmock.New()
 	defer func() { _ = sourceDB.Close() }()
@@ -437,13 +605,219 @@ func TestVerify_SHA256_Mismatch(t *testing.T) {
 
 	stats, err := v.Verify(ctx, recordSet)
 
-	if err == nil {
-		t.Error("Expected error for SHA256 mismatch")
+	if !errors.Is(err, ErrMismatch) {
+		t.Errorf("Expected ErrMismatch for SHA256 mismatch, got %v", err)
 	}
 
 	if stats.TablesFailed != 1 {
 		t.Errorf("Expected 1 table failed, got %d", stats.TablesFailed)
 	}
+
+	if len(stats.FailedTables) != 1 || stats.FailedTables[0] != "users" {
+		t.Errorf("Expected FailedTables [users], got %v", stats.FailedTables)
+	}
+}
+
+func TestVerify_SHA256_RowOrderIndependent(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createTestGraph()
+	log := logger.NewDefault()
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodSHA256, log)
+	v.SetChunkSize(100)
+
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1, 2, 3},
+		Records: map[string][]interface{}{
+			"users": {1, 2, 3},
+		},
+	}
+	ctx := context.Background()
+
+	// Both sides must ask for PK order explicitly
+	rows := sqlmock.NewRows([]string{"id", "name", "email"}).
+		AddRow(1, "John Doe", "john@example.com").
+		AddRow(2, "Jane Doe", "jane@example.com").
+		AddRow(3, "Jim Doe", "jim@example.com")
+	sourceMock.ExpectQuery("SELECT \\* FROM `users` WHERE `id` IN \\(\\?,\\?,\\?\\) ORDER BY `id`").
+		WithArgs(1, 2, 3).
+		WillReturnRows(rows)
+
+	// Destination returns the same rows in a different order
+	destRows := sqlmock.NewRows([]string{"id", "name", "email"}).
+		AddRow(3, "Jim Doe", "jim@example.com").
+		AddRow(1, "John Doe", "john@example.com").
+		AddRow(2, "Jane Doe", "jane@example.com")
+	destMock.ExpectQuery("SELECT \\* FROM `users` WHERE `id` IN \\(\\?,\\?,\\?\\) ORDER BY `id`").
+		WithArgs(1, 2, 3).
+		WillReturnRows(destRows)
+
+	stats, err := v.Verify(ctx, recordSet)
+
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	if stats.TablesPassed != 1 {
+		t.Errorf("Expected 1 table passed, got %d", stats.TablesPassed)
+	}
+
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}
+
+func TestVerify_PerTableMethod(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	// Count by default; orders carries verification_method: sha256.
+	g := createTestGraph()
+	g.GetNode("orders").VerificationMethod = "sha256"
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodCount, logger.NewDefault())
+
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users":       {1},
+			"orders":      {10, 11},
+			"order_items": {100},
+		},
+	}
+
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+
+	orderRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "total"}).
+			AddRow(10, 1, "9.99").
+			AddRow(11, 1, "19.99")
+	}
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?,\\?\\) ORDER BY `id`").
+		WithArgs(10, 11).
+		WillReturnRows(orderRows())
+	destMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?,\\?\\) ORDER BY `id`").
+		WithArgs(10, 11).
+		WillReturnRows(orderRows())
+
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `order_items`").
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `order_items`").
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if stats.TablesPassed != 3 {
+		t.Errorf("Expected 3 tables passed, got %d", stats.TablesPassed)
+	}
+	want := map[string]VerificationMethod{"users": MethodCount, "orders": MethodSHA256, "order_items": MethodCount}
+	if !reflect.DeepEqual(stats.MethodPerTable, want) {
+		t.Errorf("MethodPerTable = %v, want %v", stats.MethodPerTable, want)
+	}
+	if stats.Method != MethodCount {
+		t.Errorf("Method = %s, want the verifier default count", stats.Method)
+	}
+
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}
+
+func TestVerify_PerTableResults(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	// users passes by count, orders fails sha256 on one changed row and
+	// order_items fails count on one missing row.
+	g := createTestGraph()
+	g.GetNode("orders").VerificationMethod = "sha256"
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodCount, logger.NewDefault())
+
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1},
+		Records: map[string][]interface{}{
+			"users":       {1},
+			"orders":      {10, 11},
+			"order_items": {100, 101},
+		},
+	}
+	count := func(n int) *sqlmock.Rows { return sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(n) }
+	orderRows := func(total string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "total"}).
+			AddRow(10, 1, "9.99").
+			AddRow(11, 1, total)
+	}
+
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").WithArgs(1).WillReturnRows(count(1))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `users`").WithArgs(1).WillReturnRows(count(1))
+
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE .* ORDER BY `id`").WithArgs(10, 11).WillReturnRows(orderRows("19.99"))
+	destMock.ExpectQuery("SELECT \\* FROM `orders` WHERE .* ORDER BY `id`").WithArgs(10, 11).WillReturnRows(orderRows("0.00"))
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?,\\?\\)$").WithArgs(10, 11).WillReturnRows(orderRows("19.99"))
+	destMock.ExpectQuery("SELECT \\* FROM `orders` WHERE `id` IN \\(\\?,\\?\\)$").WithArgs(10, 11).WillReturnRows(orderRows("0.00"))
+
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `order_items`").WithArgs(100, 101).WillReturnRows(count(2))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `order_items`").WithArgs(100, 101).WillReturnRows(count(1))
+	sourceMock.ExpectQuery("SELECT `id` FROM `order_items`").WithArgs(100, 101).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100).AddRow(101))
+	destMock.ExpectQuery("SELECT `id` FROM `order_items`").WithArgs(100, 101).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if !errors.Is(err, ErrMismatch) {
+		t.Fatalf("expected ErrMismatch, got %v", err)
+	}
+
+	want := []TableVerifyResult{
+		{Table: "users", Method: MethodCount, SourceCount: 1, DestCount: 1, Match: true},
+		{Table: "orders", Method: MethodSHA256, SourceCount: 2, DestCount: 2, MismatchedPKs: []interface{}{11}},
+		{Table: "order_items", Method: MethodCount, SourceCount: 2, DestCount: 1,
+			ErrorMessage: "count mismatch: source=2, dest=1", MismatchedPKs: []interface{}{101}},
+	}
+	if len(stats.Tables) != len(want) {
+		t.Fatalf("got %d table results, want %d: %+v", len(stats.Tables), len(want), stats.Tables)
+	}
+	for i, got := range stats.Tables {
+		w := want[i]
+		if w.Table == "orders" {
+			// The hash digests are in the message; check its prefix only.
+			if !strings.HasPrefix(got.ErrorMessage, "hash mismatch:") {
+				t.Errorf("orders ErrorMessage = %q, want a hash mismatch", got.ErrorMessage)
+			}
+			got.ErrorMessage = ""
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("Tables[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
 }
 
 func TestVerify_SHA256_CountMismatch(t *testing.T) {
@@ -506,7 +880,7 @@ func TestVerifyByCount_EmptyPKs(t *testing.T) {
 	v, _ := NewVerifier(sourceDB, destDB, g, MethodCount, log)
 	ctx := context.Background()
 
-	result, err := v.verifyByCount(ctx, "users", []interface{}{})
+	result, err := v.verifyByCount(ctx, "users", v.chunk("users", []interface{}{}))
 
 	if err != nil {
 		t.Fatalf("verifyByCount failed: %v", err)
@@ -542,7 +916,7 @@ func TestVerifyByCount_ErrorMessage(t *testing.T) {
 		WithArgs(1, 2, 3).
 		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2))
 
-	result, err := v.verifyByCount(ctx, "users", []interface{}{1, 2, 3})
+	result, err := v.verifyByCount(ctx, "users", v.chunk("users", []interface{}{1, 2, 3}))
 
 	if err != nil {
 		t.Fatalf("verifyByCount failed: %v", err)
@@ -585,7 +959,7 @@ func TestVerifyByCount_UsesChunking(t *testing.T) {
 		WithArgs(3).
 		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
 
-	result, err := v.verifyByCount(ctx, "users", []interface{}{1, 2, 3})
+	result, err := v.verifyByCount(ctx, "users", v.chunk("users", []interface{}{1, 2, 3}))
 	if err != nil {
 		t.Fatalf("verifyByCount failed: %v", err)
 	}
@@ -594,6 +968,80 @@ func TestVerifyByCount_UsesChunking(t *testing.T) {
 	}
 }
 
+func TestVerifyByCount_DestinationTableTemplate(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createTestGraph()
+	g.GetNode("orders").DestinationTable = "orders_{year}{month}"
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodCount, logger.NewDefault())
+	v.SetRunDate(time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC))
+
+	// The source is read by its own name, the destination by the computed one.
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders` WHERE").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders_202301` WHERE").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2))
+
+	result, err := v.verifyByCount(context.Background(), "orders", v.chunk("orders", []interface{}{1, 2}))
+	if err != nil {
+		t.Fatalf("verifyByCount failed: %v", err)
+	}
+	if !result.Match {
+		t.Errorf("expected match, got %s", result.ErrorMessage)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyBySHA256_DestinationDateColumn(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createTestGraph()
+	g.GetNode("orders").DestinationTable = "orders_{year}{month}"
+	g.GetNode("orders").DestinationDateColumn = "created_at"
+	v, _ := NewVerifier(sourceDB, destDB, g, MethodSHA256, logger.NewDefault())
+
+	// Rows are grouped by the destination their source date routes them to,
+	// and both sides hash the same groups.
+	sourceMock.ExpectQuery("SELECT `id`, `created_at` FROM `orders` WHERE `id` IN").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).
+			AddRow(1, "2023-01-05 08:00:00").AddRow(2, "2023-02-01 00:00:00"))
+	row := func(id int, created string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "created_at"}).AddRow(id, created)
+	}
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE").WithArgs(int64(1)).WillReturnRows(row(1, "2023-01-05 08:00:00"))
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE").WithArgs(int64(2)).WillReturnRows(row(2, "2023-02-01 00:00:00"))
+	destMock.ExpectQuery("SELECT \\* FROM `orders_202301` WHERE").WithArgs(int64(1)).WillReturnRows(row(1, "2023-01-05 08:00:00"))
+	destMock.ExpectQuery("SELECT \\* FROM `orders_202302` WHERE").WithArgs(int64(2)).WillReturnRows(row(2, "2023-02-01 00:00:00"))
+
+	result, err := v.verifyBySHA256(context.Background(), "orders", v.chunk("orders", []interface{}{1, 2}))
+	if err != nil {
+		t.Fatalf("verifyBySHA256 failed: %v", err)
+	}
+	if !result.Match || result.DestCount != 2 {
+		t.Errorf("expected match over 2 rows, got %+v", result)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
 // ============================================================================
 // verifyBySHA256 Tests
 // ============================================================================
@@ -609,7 +1057,7 @@ func TestVerifyBySHA256_EmptyPKs(t *testing.T) {
 	v, _ := NewVerifier(sourceDB, destDB, g, MethodSHA256, log)
 	ctx := context.Background()
 
-	result, err := v.verifyBySHA256(ctx, "users", []interface{}{})
+	result, err := v.verifyBySHA256(ctx, "users", v.chunk("users", []interface{}{}))
 
 	if err != nil {
 		t.Fatalf("verifyBySHA256 failed: %v", err)
@@ -620,6 +1068,122 @@ func TestVerifyBySHA256_EmptyPKs(t *testing.T) {
 	}
 }
 
+func TestVerifyBySHA256_CountPrecheckSkipsEmptyTable(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodSHA256, logger.NewDefault())
+	v.SetCountPrecheck(true)
+
+	// Only the counts run: no SELECT * is expected on either side.
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders` WHERE `id` IN").
+		WithArgs(10, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders` WHERE `id` IN").
+		WithArgs(10, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	result, err := v.verifyBySHA256(context.Background(), "orders", v.chunk("orders", []interface{}{10, 11}))
+	if err != nil {
+		t.Fatalf("verifyBySHA256 failed: %v", err)
+	}
+	if !result.Match || result.SourceCount != 0 || result.DestCount != 0 {
+		t.Errorf("expected an empty match, got %+v", result)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyBySHA256_CountPrecheckFetchesPopulatedTable(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodSHA256, logger.NewDefault())
+	v.SetCountPrecheck(true)
+
+	// A nonzero source count ends the precheck before the destination count.
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders` WHERE `id` IN").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	sourceMock.ExpectQuery("SELECT \\* FROM `orders` WHERE").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "total"}).AddRow(10, "9.99"))
+	destMock.ExpectQuery("SELECT \\* FROM `orders` WHERE").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "total"}).AddRow(10, "9.99"))
+
+	result, err := v.verifyBySHA256(context.Background(), "orders", v.chunk("orders", []interface{}{10}))
+	if err != nil {
+		t.Fatalf("verifyBySHA256 failed: %v", err)
+	}
+	if !result.Match || result.SourceCount != 1 || result.DestCount != 1 {
+		t.Errorf("expected a hashed match over 1 row, got %+v", result)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyByCount_QuotesAwkwardIdentifiers(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = destDB.Close() }()
+
+	// A reserved word, a backtick and a space are all legal once quoted.
+	g := graph.NewGraph("order", "key")
+	g.AddNode("line`items", &graph.Node{Name: "line`items", ForeignKey: "order id", ReferenceKey: "key", DependencyType: "1-N"})
+	g.AddEdge("order", "line`items")
+	g.SetPK("line`items", "group id")
+	v, err := NewVerifier(sourceDB, destDB, g, MethodCount, logger.NewDefault())
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+
+	const query = "SELECT COUNT(*) FROM `line``items` WHERE `group id` IN (?)"
+	sourceMock.ExpectQuery(query).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	destMock.ExpectQuery(query).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	result, err := v.verifyByCount(context.Background(), "line`items", v.chunk("line`items", []interface{}{10}))
+	if err != nil {
+		t.Fatalf("verifyByCount failed: %v", err)
+	}
+	if !result.Match {
+		t.Errorf("expected match, got %+v", result)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewVerifier_InvalidIdentifier(t *testing.T) {
+	sourceDB, _, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, _, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	g := createTestGraph()
+	g.SetPK("orders", "")
+	if _, err := NewVerifier(sourceDB, destDB, g, MethodCount, logger.NewDefault()); err == nil ||
+		!strings.Contains(err.Error(), "table orders primary key: identifier is empty") {
+		t.Errorf("expected empty primary key to be rejected, got %v", err)
+	}
+}
+
 // ============================================================================
 // Context Cancellation Tests
 // ============================================================================
@@ -647,6 +1211,168 @@ func TestVerify_ContextCancellation(t *testing.T) {
 	}
 }
 
+// expectBatchedTable sets up one table's queries for batches, in the order
+// the verifier issues them. Rows in missing are absent on the destination;
+// when any is, the count mismatches and the row comparison follows.
+func expectBatchedTable(sourceMock, destMock sqlmock.Sqlmock, method VerificationMethod, table string, batches [][]interface{}, missing map[interface{}]bool) {
+	present := func(batch []interface{}, onDest bool) []interface{} {
+		var out []interface{}
+		for _, pk := range batch {
+			if !onDest || !missing[pk] {
+				out = append(out, pk)
+			}
+		}
+		return out
+	}
+	countQuery := "SELECT COUNT\\(\\*\\) FROM `" + table + "`"
+	rowsQuery := "SELECT \\* FROM `" + table + "`"
+	for _, side := range []struct {
+		mock   sqlmock.Sqlmock
+		onDest bool
+	}{{sourceMock, false}, {destMock, true}} {
+		for _, batch := range batches {
+			rows := present(batch, side.onDest)
+			if method == MethodCount {
+				side.mock.ExpectQuery(countQuery).WithArgs(toDriverArgs(batch)...).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(len(rows)))
+				continue
+			}
+			result := sqlmock.NewRows([]string{"id", "name"})
+			for _, pk := range rows {
+				result.AddRow(pk, fmt.Sprintf("row %v", pk))
+			}
+			side.mock.ExpectQuery(rowsQuery).WithArgs(toDriverArgs(batch)...).WillReturnRows(result)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	selectQuery := "SELECT `id` FROM `" + table + "`"
+	if method != MethodCount {
+		selectQuery = rowsQuery
+	}
+	for _, side := range []struct {
+		mock   sqlmock.Sqlmock
+		onDest bool
+	}{{sourceMock, false}, {destMock, true}} {
+		for _, batch := range batches {
+			result := sqlmock.NewRows([]string{"id"})
+			if method != MethodCount {
+				result = sqlmock.NewRows([]string{"id", "name"})
+			}
+			for _, pk := range present(batch, side.onDest) {
+				if method == MethodCount {
+					result.AddRow(pk)
+				} else {
+					result.AddRow(pk, fmt.Sprintf("row %v", pk))
+				}
+			}
+			side.mock.ExpectQuery(selectQuery).WithArgs(toDriverArgs(batch)...).WillReturnRows(result)
+		}
+	}
+}
+
+func toDriverArgs(values []interface{}) []driver.Value {
+	args := make([]driver.Value, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// TestVerifyBatches_MatchesVerify verifies the same PKs once as a record set
+// (re-chunked by the chunk size) and once in discovery-sized batches, and
+// expects identical stats, mismatch included.
+func TestVerifyBatches_MatchesVerify(t *testing.T) {
+	recordSet := createTestRecordSet()
+	// As DiscoverStream yields them with a discovery batch size of 4.
+	batches := map[string][][]interface{}{
+		"users":       {{1, 2, 3}},
+		"orders":      {{10, 11, 12, 13}, {14, 15}},
+		"order_items": {{100, 101, 102, 103}, {104, 105, 106, 107}, {108, 109, 110, 111}},
+	}
+	missing := map[interface{}]bool{13: true}
+
+	for _, method := range []VerificationMethod{MethodCount, MethodSHA256} {
+		t.Run(string(method), func(t *testing.T) {
+			run := func(verify func(v *Verifier) (*VerifyStats, error), tableBatches func(table string) [][]interface{}) (*VerifyStats, error) {
+				sourceDB, sourceMock, _ := sqlmock.New()
+				defer func() { _ = sourceDB.Close() }()
+				destDB, destMock, _ := sqlmock.New()
+				defer func() { _ = destDB.Close() }()
+				v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), method, logger.NewDefault())
+
+				for _, table := range []string{"users", "orders", "order_items"} {
+					tableMissing := map[interface{}]bool(nil)
+					if table == "orders" {
+						tableMissing = missing
+					}
+					expectBatchedTable(sourceMock, destMock, method, table, tableBatches(table), tableMissing)
+				}
+				stats, err := verify(v)
+				if err := sourceMock.ExpectationsWereMet(); err != nil {
+					t.Errorf("source: %v", err)
+				}
+				if err := destMock.ExpectationsWereMet(); err != nil {
+					t.Errorf("destination: %v", err)
+				}
+				return stats, err
+			}
+
+			monolithic, err := run(func(v *Verifier) (*VerifyStats, error) {
+				return v.Verify(context.Background(), recordSet)
+			}, func(table string) [][]interface{} {
+				return [][]interface{}{recordSet.Records[table]}
+			})
+			if !errors.Is(err, ErrMismatch) {
+				t.Fatalf("Verify: expected ErrMismatch, got %v", err)
+			}
+			batched, err := run(func(v *Verifier) (*VerifyStats, error) {
+				return v.VerifyBatches(context.Background(), batches)
+			}, func(table string) [][]interface{} {
+				return batches[table]
+			})
+			if !errors.Is(err, ErrMismatch) {
+				t.Fatalf("VerifyBatches: expected ErrMismatch, got %v", err)
+			}
+
+			if !reflect.DeepEqual(batched, monolithic) {
+				t.Errorf("VerifyBatches stats differ from Verify:\nbatched    %+v\nmonolithic %+v", batched, monolithic)
+			}
+			if !reflect.DeepEqual(batched.FailedTables, []string{"orders"}) ||
+				!reflect.DeepEqual(batched.Tables[1].MismatchedPKs, []interface{}{13}) {
+				t.Errorf("expected orders to fail on PK 13, got %+v", batched.Tables)
+			}
+		})
+	}
+}
+
+func TestVerifyBatches_MaxInClauseSizeSplitsBatches(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodCount, logger.NewDefault())
+	v.SetMaxInClauseSize(2)
+
+	// An empty batch is dropped; the batch of 3 becomes IN lists of 2 and 1.
+	expectBatchedTable(sourceMock, destMock, MethodCount, "users", [][]interface{}{{1, 2}, {3}}, nil)
+	stats, err := v.VerifyBatches(context.Background(), map[string][][]interface{}{"users": {{1, 2, 3}, {}}})
+	if err != nil {
+		t.Fatalf("VerifyBatches failed: %v", err)
+	}
+	if stats.TotalRows != 3 || stats.TablesPassed != 1 {
+		t.Errorf("expected 3 rows in one passing table, got %+v", stats)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination: %v", err)
+	}
+}
+
 // ============================================================================
 // Setter/Getter Tests
 // ============================================================================
@@ -853,3 +1579,88 @@ func TestNewVerifier_UnsupportedMethod(t *testing.T) {
 		t.Error("Expected error for unsupported method in constructor")
 	}
 }
+
+func TestVerify_ServerChecksum_Match(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer func() { _ = destDB.Close() }()
+
+	v, err := NewVerifier(sourceDB, destDB, createTestGraph(), MethodServerChecksum, logger.NewDefault())
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1, 2},
+		Records: map[string][]interface{}{"users": {1, 2}},
+	}
+
+	sourceMock.ExpectQuery("SELECT * FROM `users` LIMIT 0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	checksum := "SELECT COUNT(*), BIT_XOR(CRC32(CONCAT_WS('#', `id`, `name`, CONCAT(ISNULL(`id`), ISNULL(`name`))))) FROM `users` WHERE `id` IN (?,?)"
+	sourceMock.ExpectQuery(checksum).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).AddRow(2, 3735928559))
+	destMock.ExpectQuery(checksum).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).AddRow(2, 3735928559))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if stats.TablesPassed != 1 || stats.MethodPerTable["users"] != MethodServerChecksum {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}
+
+func TestVerify_ServerChecksum_MismatchFallsBackToRows(t *testing.T) {
+	sourceDB, sourceMock, _ := sqlmock.New()
+	defer func() { _ = sourceDB.Close() }()
+	destDB, destMock, _ := sqlmock.New()
+	defer func() { _ = destDB.Close() }()
+
+	v, _ := NewVerifier(sourceDB, destDB, createTestGraph(), MethodServerChecksum, logger.NewDefault())
+	recordSet := &types.RecordSet{
+		RootPKs: []interface{}{1, 2},
+		Records: map[string][]interface{}{"users": {1, 2}},
+	}
+
+	sourceMock.ExpectQuery("SELECT \\* FROM `users` LIMIT 0").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	sourceMock.ExpectQuery("SELECT COUNT\\(\\*\\), BIT_XOR\\(CRC32").WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).AddRow(2, 111))
+	destMock.ExpectQuery("SELECT COUNT\\(\\*\\), BIT_XOR\\(CRC32").WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).AddRow(2, 222))
+
+	// The row-by-row fallback pinpoints the differing row.
+	sourceMock.ExpectQuery("SELECT \\* FROM `users` WHERE `id` IN").WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Ann").AddRow(2, "Bob"))
+	destMock.ExpectQuery("SELECT \\* FROM `users` WHERE `id` IN").WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Ann").AddRow(2, "Bo"))
+
+	stats, err := v.Verify(context.Background(), recordSet)
+	if !errors.Is(err, ErrMismatch) {
+		t.Fatalf("expected ErrMismatch, got %v", err)
+	}
+	if len(stats.Tables) != 1 {
+		t.Fatalf("expected one table result, got %+v", stats.Tables)
+	}
+	result := stats.Tables[0]
+	if !strings.Contains(result.ErrorMessage, "checksum mismatch: source=0000006f, dest=000000de") {
+		t.Errorf("unexpected error message %q", result.ErrorMessage)
+	}
+	if !reflect.DeepEqual(result.MismatchedPKs, []interface{}{2}) {
+		t.Errorf("MismatchedPKs = %v, want [2]", result.MismatchedPKs)
+	}
+	if err := sourceMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("source expectations: %v", err)
+	}
+	if err := destMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("destination expectations: %v", err)
+	}
+}